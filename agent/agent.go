@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	metrics "github.com/armon/go-metrics"
@@ -13,33 +14,95 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
 	"github.com/hashicorp/nomad-autoscaler/policy"
 	filePolicy "github.com/hashicorp/nomad-autoscaler/policy/file"
+	gitPolicy "github.com/hashicorp/nomad-autoscaler/policy/git"
 	nomadPolicy "github.com/hashicorp/nomad-autoscaler/policy/nomad"
 	"github.com/hashicorp/nomad-autoscaler/policyeval"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
 	"github.com/hashicorp/nomad/api"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Agent struct {
 	logger        hclog.Logger
 	config        *config.Agent
+	configLoader  ConfigLoader
 	nomadClient   *api.Client
 	pluginManager *manager.PluginManager
 	policyManager *policy.Manager
 	inMemSink     *metrics.InmemSink
 	evalBroker    *policyeval.Broker
 
+	// wal is the write-ahead log of in-flight scaling actions. It is nil if
+	// the operator has not configured a WAL directory.
+	wal *policyeval.WAL
+
+	// pause is the switch used to pause and resume scaling actions across
+	// all workers.
+	pause *policyeval.Pause
+
 	// nomadCfg is the merged Nomad API configuration that should be used when
 	// setting up all clients. It is the result of the Nomad api.DefaultConfig
 	// merged with the user specified Nomad config.Nomad.
 	nomadCfg *api.Config
+
+	// nomadRegionCfgs are the Nomad API configurations of the additional
+	// regions, keyed by region. Each is the region config merged with
+	// nomadCfg.
+	nomadRegionCfgs map[string]*api.Config
+
+	// nomadRegionClients are the Nomad clients of the additional regions,
+	// keyed by region.
+	nomadRegionClients map[string]*api.Client
+
+	// nomadPolicySource is the Nomad policy source which is used to track
+	// whether the Nomad API is reachable.
+	nomadPolicySource *nomadPolicy.Source
+
+	// nomadRegionSources are the Nomad policy sources of the additional
+	// regions.
+	nomadRegionSources []*nomadPolicy.Source
+
+	// tracerProvider exports policy evaluation spans. It is nil if the
+	// operator has not configured an OTLP tracing endpoint.
+	tracerProvider *sdktrace.TracerProvider
+
+	// policyProcessor is shared by the policy sources and is updated when the
+	// agent configuration is reloaded.
+	policyProcessor *policy.Processor
+
+	// leaderElection tracks whether the agent is the leader of a high
+	// availability deployment. It is nil if high availability is disabled, in
+	// which case the agent always evaluates policies.
+	leaderElection *leaderElection
+
+	// reloadLock serializes reloads triggered by signals and the HTTP API.
+	reloadLock sync.Mutex
+
+	// ready is set once Run has initialised the components used by the HTTP
+	// handlers, which are served before Run is called. It is guarded by
+	// readyLock, which publishes the components to the handlers.
+	ready     bool
+	readyLock sync.RWMutex
 }
 
-func NewAgent(c *config.Agent, logger hclog.Logger) *Agent {
+// NewAgent returns a new agent using the passed config. The loader is used to
+// load the config again when the agent is reloaded; if it is nil, reloading
+// only reloads the policy sources.
+func NewAgent(c *config.Agent, loader ConfigLoader, logger hclog.Logger) *Agent {
+	nomadCfg := nomadHelper.MergeDefaultWithAgentConfig(c.Nomad)
+
+	nomadRegionCfgs := make(map[string]*api.Config, len(c.Nomad.Regions))
+	for _, region := range c.Nomad.Regions {
+		nomadRegionCfgs[region.Name] = nomadHelper.MergeRegionWithAgentConfig(region, nomadCfg)
+	}
+
 	return &Agent{
-		logger:   logger,
-		config:   c,
-		nomadCfg: nomadHelper.MergeDefaultWithAgentConfig(c.Nomad),
+		logger:          logger,
+		config:          c,
+		configLoader:    loader,
+		nomadCfg:        nomadCfg,
+		nomadRegionCfgs: nomadRegionCfgs,
 	}
 }
 
@@ -50,6 +113,16 @@ func (a *Agent) Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Setup the rate limiter shared by all Nomad API clients within the agent
+	// process. This must happen before any clients are created.
+	a.setupNomadRateLimiter()
+
+	// Load the Nomad ACL token from the token file, if configured, so the
+	// clients are created using it.
+	if err := a.loadNomadTokenFile(); err != nil {
+		return err
+	}
+
 	// Generate the Nomad client.
 	if err := a.generateNomadClient(); err != nil {
 		return err
@@ -67,6 +140,33 @@ func (a *Agent) Run() error {
 	}
 	a.inMemSink = inMem
 
+	// Setup the optional exporting of policy evaluation traces.
+	if err := a.setupTracing(a.config.Telemetry); err != nil {
+		return fmt.Errorf("failed to setup tracing: %v", err)
+	}
+
+	// Open the write-ahead log and verify any scaling actions which were
+	// in-flight when the agent last stopped.
+	if err := a.setupWAL(); err != nil {
+		return fmt.Errorf("failed to setup WAL: %v", err)
+	}
+
+	// Restore the pause state, so an agent paused before a restart does not
+	// resume scaling unexpectedly.
+	pause, err := policyeval.NewPause(a.config.PolicyEval.PauseDir)
+	if err != nil {
+		return fmt.Errorf("failed to setup pause state: %v", err)
+	}
+	a.pause = pause
+	if pause.Paused() {
+		a.logger.Warn("scaling is paused, scaling actions will not be executed",
+			"since", pause.Status().Since)
+	}
+
+	// Join the leader election before the policy manager starts, so
+	// evaluations are not enqueued by followers.
+	a.setupLeaderElection(ctx)
+
 	policyEvalCh := a.setupPolicyManager()
 	go a.policyManager.Run(ctx, policyEvalCh)
 
@@ -79,9 +179,19 @@ func (a *Agent) Run() error {
 
 	a.initEnt(ctx)
 
+	// The components used by the HTTP handlers are initialised.
+	a.readyLock.Lock()
+	a.ready = true
+	a.readyLock.Unlock()
+
 	// Launch the eval handler.
 	go a.runEvalHandler(ctx, policyEvalCh)
 
+	// Watch the Nomad ACL token file so rotated tokens are used.
+	if a.config.Nomad.TokenFile != "" {
+		go a.runNomadTokenFileWatcher(ctx)
+	}
+
 	// Wait for our exit.
 	a.handleSignals()
 	return nil
@@ -94,6 +204,13 @@ func (a *Agent) runEvalHandler(ctx context.Context, evalCh chan *sdk.ScalingEval
 			a.logger.Info("context closed, shutting down eval handler")
 			return
 		case policyEval := <-evalCh:
+			// Only the leader evaluates policies, followers discard their
+			// evaluations so targets are not scaled more than once.
+			if !a.leaderElection.isLeader() {
+				a.logger.Trace("agent is not the leader, discarding policy evaluation",
+					"policy_id", policyEval.Policy.ID)
+				continue
+			}
 			a.evalBroker.Enqueue(policyEval)
 		}
 	}
@@ -101,16 +218,22 @@ func (a *Agent) runEvalHandler(ctx context.Context, evalCh chan *sdk.ScalingEval
 
 func (a *Agent) initWorkers(ctx context.Context) {
 	policyEvalLogger := a.logger.ResetNamed("policy_eval")
+	metricWindows := policyeval.NewMetricWindows()
+	actionLimiter := policyeval.NewActionLimiter(a.config.PolicyEval.MaxConcurrentScalingActions)
 
 	for i := 0; i < a.config.PolicyEval.Workers["horizontal"]; i++ {
 		w := policyeval.NewBaseWorker(
-			policyEvalLogger, a.pluginManager, a.policyManager, a.evalBroker, "horizontal")
+			policyEvalLogger, a.pluginManager, a.policyManager, a.evalBroker, a.wal, "horizontal",
+			a.config.PolicyEval.SlowPhaseThreshold, metricWindows, a.config.PolicyEval.RequireDesiredCount,
+			a.config.PolicyEval.MaxActionCount, a.config.PolicyEval.ScaleInHealthyGuard, actionLimiter, a.pause)
 		go w.Run(ctx)
 	}
 
 	for i := 0; i < a.config.PolicyEval.Workers["cluster"]; i++ {
 		w := policyeval.NewBaseWorker(
-			policyEvalLogger, a.pluginManager, a.policyManager, a.evalBroker, "cluster")
+			policyEvalLogger, a.pluginManager, a.policyManager, a.evalBroker, a.wal, "cluster",
+			a.config.PolicyEval.SlowPhaseThreshold, metricWindows, a.config.PolicyEval.RequireDesiredCount,
+			a.config.PolicyEval.MaxActionCount, a.config.PolicyEval.ScaleInHealthyGuard, actionLimiter, a.pause)
 		go w.Run(ctx)
 	}
 }
@@ -119,26 +242,67 @@ func (a *Agent) setupPolicyManager() chan *sdk.ScalingEvaluation {
 
 	// Create our processor, a shared method for performing basic policy
 	// actions.
-	cfgDefaults := policy.ConfigDefaults{
-		DefaultEvaluationInterval: a.config.Policy.DefaultEvaluationInterval,
-		DefaultCooldown:           a.config.Policy.DefaultCooldown,
-	}
-	policyProcessor := policy.NewProcessor(&cfgDefaults, a.getNomadAPMNames())
+	a.policyProcessor = policy.NewProcessor(a.policyConfigDefaults(), a.getNomadAPMNames())
 
 	// Setup our initial default policy source which is Nomad.
+	a.nomadPolicySource = nomadPolicy.NewNomadSource(a.logger, a.nomadClient, a.policyProcessor)
+
+	// Setup a Nomad source for each additional region, so the policies of
+	// all regions are watched.
+	for region, client := range a.nomadRegionClients {
+		a.nomadRegionSources = append(a.nomadRegionSources,
+			nomadPolicy.NewNomadRegionSource(a.logger, client, a.policyProcessor, region))
+	}
+	sources := a.policySources()
+
+	precedence := make([]policy.SourceName, len(a.config.Policy.SourcePrecedence))
+	for i, name := range a.config.Policy.SourcePrecedence {
+		precedence[i] = policy.SourceName(name)
+	}
+
+	a.policyManager = policy.NewManager(a.logger, sources, a.pluginManager,
+		a.config.Telemetry.CollectionInterval, precedence)
+
+	if webhook := a.config.Policy.CooldownExpiryWebhook; webhook != "" {
+		a.policyManager.SetCooldownNotifier(policy.NewWebhookNotifier(a.logger, webhook))
+	}
+
+	return make(chan *sdk.ScalingEvaluation, a.config.PolicyEval.EvalBufferSize)
+}
+
+// policySources returns the policy sources to use based on the agent config.
+func (a *Agent) policySources() map[policy.SourceName]policy.Source {
 	sources := map[policy.SourceName]policy.Source{
-		policy.SourceNameNomad: nomadPolicy.NewNomadSource(a.logger, a.nomadClient, policyProcessor),
+		policy.SourceNameNomad: a.nomadPolicySource,
+	}
+
+	for _, s := range a.nomadRegionSources {
+		sources[s.Name()] = s
 	}
 
 	// If the operators has configured a scaling policy directory to read from
 	// then setup the file source.
 	if a.config.Policy.Dir != "" {
-		sources[policy.SourceNameFile] = filePolicy.NewFileSource(a.logger, a.config.Policy.Dir, policyProcessor)
+		sources[policy.SourceNameFile] = filePolicy.NewFileSource(a.logger, a.config.Policy.Dir, a.policyProcessor)
 	}
 
-	a.policyManager = policy.NewManager(a.logger, sources, a.pluginManager, a.config.Telemetry.CollectionInterval)
+	// If the operator has configured a Git repository to read scaling
+	// policies from then setup the Git source.
+	if g := a.config.Policy.Git; g != nil {
+		sources[policy.SourceNameGit] = gitPolicy.NewGitSource(a.logger, &gitPolicy.Config{
+			URL:        g.URL,
+			Branch:     g.Branch,
+			Path:       g.Path,
+			Interval:   g.Interval,
+			Token:      g.Token,
+			Username:   g.Username,
+			SSHKeyFile: g.SSHKeyFile,
+			Shallow:    g.Shallow,
+			CloneDir:   g.CloneDir,
+		}, a.policyProcessor)
+	}
 
-	return make(chan *sdk.ScalingEvaluation, 10)
+	return sources
 }
 
 func (a *Agent) stop() {
@@ -146,26 +310,71 @@ func (a *Agent) stop() {
 	if a.pluginManager != nil {
 		a.pluginManager.KillPlugins()
 	}
+
+	if err := a.wal.Close(); err != nil {
+		a.logger.Error("failed to close WAL", "error", err)
+	}
+
+	a.stopTracing()
 }
 
 // generateNomadClient creates a Nomad client for use within the agent.
 func (a *Agent) generateNomadClient() error {
 
 	// Generate the Nomad client.
-	client, err := api.NewClient(a.nomadCfg)
+	client, err := nomadHelper.NewClient(a.nomadCfg)
 	if err != nil {
 		return fmt.Errorf("failed to instantiate Nomad client: %v", err)
 	}
 	a.nomadClient = client
 
+	a.nomadRegionClients = make(map[string]*api.Client, len(a.nomadRegionCfgs))
+	for region, cfg := range a.nomadRegionCfgs {
+		client, err := nomadHelper.NewClient(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to instantiate Nomad client for region %q: %v", region, err)
+		}
+		a.nomadRegionClients[region] = client
+	}
+
 	return nil
 }
 
+// setupNomadRateLimiter configures the rate limiter shared by all Nomad API
+// clients created within the agent process, including those of internal
+// plugins.
+func (a *Agent) setupNomadRateLimiter() {
+	if a.config.Nomad.RateLimit <= 0 {
+		nomadHelper.SetSharedRateLimiter(nil)
+		return
+	}
+
+	a.logger.Info("enabling Nomad API rate limit",
+		"rate", a.config.Nomad.RateLimit, "burst", a.config.Nomad.RateLimitBurst)
+	nomadHelper.SetSharedRateLimiter(
+		nomadHelper.NewRateLimiter(a.config.Nomad.RateLimit, a.config.Nomad.RateLimitBurst))
+}
+
 // reload triggers the reload of sub-routines based on the operator sending a
-// SIGHUP signal to the agent.
+// SIGHUP signal to the agent. The agent configuration is reloaded first, so
+// the policy sources are reloaded using the new configuration.
 func (a *Agent) reload() {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	var updateSources bool
+	if a.configLoader != nil {
+		updateSources = a.reloadConfig()
+	}
+
 	a.logger.Debug("reloading policy sources")
 	a.policyManager.ReloadSources()
+
+	// Update the policy sources after reloading the existing sources, as a new
+	// source is not ready to be reloaded until the policy manager starts it.
+	if updateSources {
+		a.policyManager.SetSources(a.policySources())
+	}
 }
 
 // handleSignals blocks until the agent receives an exit signal.
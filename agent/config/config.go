@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -38,6 +41,23 @@ type Agent struct {
 	// PluginDir is the directory that holds the autoscaler plugin binaries.
 	PluginDir string `hcl:"plugin_dir,optional"`
 
+	// DiscoverPlugins enables registering the executable plugins found in
+	// PluginDir which are not configured using apm, strategy or target
+	// blocks. Discovered plugins use their default configuration.
+	DiscoverPlugins bool `hcl:"discover_plugins,optional"`
+
+	// PluginCallTimeout is the time after which calls to plugins which can be
+	// abandoned, such as APM queries without a check query_timeout, target
+	// status and strategy runs, are timed out. Calls are not timed out if
+	// zero.
+	PluginCallTimeout    time.Duration
+	PluginCallTimeoutHCL string `hcl:"plugin_call_timeout,optional" json:"-"`
+
+	// PluginRestartThreshold is the number of consecutive timed out calls
+	// after which a plugin is assumed to be hung and is restarted. Plugins
+	// are not restarted if zero.
+	PluginRestartThreshold int `hcl:"plugin_restart_threshold,optional"`
+
 	// HTTP is the configuration used to setup the HTTP health server.
 	HTTP *HTTP `hcl:"http,block"`
 
@@ -54,9 +74,18 @@ type Agent struct {
 	// Telemetry is the configuration used to setup metrics collection.
 	Telemetry *Telemetry `hcl:"telemetry,block"`
 
+	// HighAvailability is the configuration used to elect a leader amongst
+	// multiple agents.
+	HighAvailability *HighAvailability `hcl:"high_availability,block"`
+
 	APMs       []*Plugin `hcl:"apm,block"`
 	Targets    []*Plugin `hcl:"target,block"`
 	Strategies []*Plugin `hcl:"strategy,block"`
+
+	// StrategyAliases are the names checks can use as their strategy to run
+	// a configured strategy plugin with preset config, such as differently
+	// tuned target-value strategies.
+	StrategyAliases []*StrategyAlias `hcl:"strategy_alias,block"`
 }
 
 // HTTP contains all configuration details for the running of the agent HTTP
@@ -87,6 +116,65 @@ type Nomad struct {
 	// requests with.
 	Token string `hcl:"token,optional"`
 
+	// TokenFile is the path to a file containing the SecretID of an ACL token
+	// to use to authenticate API requests with. The file is periodically
+	// re-read, so the token can be rotated without restarting the agent.
+	TokenFile string `hcl:"token_file,optional"`
+
+	// HTTPAuth is the auth info to use for http access.
+	HTTPAuth string `hcl:"http_auth,optional"`
+
+	// CACert is the path to a PEM-encoded CA cert file to use to verify the
+	// Nomad server SSL certificate.
+	CACert string `hcl:"ca_cert,optional"`
+
+	// CAPath is the path to a directory of PEM-encoded CA cert files to verify
+	// the Nomad server SSL certificate.
+	CAPath string `hcl:"ca_path,optional"`
+
+	// ClientCert is the path to the certificate for Nomad communication.
+	ClientCert string `hcl:"client_cert,optional"`
+
+	// ClientKey is the path to the private key for Nomad communication.
+	ClientKey string `hcl:"client_key,optional"`
+
+	// TLSServerName, if set, is used to set the SNI host when connecting via
+	// TLS.
+	TLSServerName string `hcl:"tls_server_name,optional"`
+
+	// SkipVerify enables or disables SSL verification.
+	SkipVerify bool `hcl:"skip_verify,optional"`
+
+	// RateLimit is the maximum number of requests per second the agent makes
+	// to the Nomad API, shared across the policy sources and internal plugins.
+	// Requests which exceed the limit are queued. Zero disables the limit.
+	RateLimit float64 `hcl:"rate_limit,optional"`
+
+	// RateLimitBurst is the number of requests which can be made at once
+	// before RateLimit is applied. It defaults to one.
+	RateLimitBurst int `hcl:"rate_limit_burst,optional"`
+
+	// Regions are the additional Nomad regions the agent watches for scaling
+	// policies and scales the targets of.
+	Regions []*NomadRegion `hcl:"additional_region,block"`
+}
+
+// NomadRegion holds the configuration used to connect to an additional Nomad
+// region. Parameters which are not set are inherited from the Nomad block.
+type NomadRegion struct {
+
+	// Name is the name of the Nomad region.
+	Name string `hcl:"name,label"`
+
+	// Address is the address of a Nomad agent within the region. If this is
+	// not set, requests are sent to the Nomad block address and forwarded to
+	// the region by Nomad.
+	Address string `hcl:"address,optional"`
+
+	// Token is the SecretID of an ACL token to use to authenticate API
+	// requests to the region with.
+	Token string `hcl:"token,optional"`
+
 	// HTTPAuth is the auth info to use for http access.
 	HTTPAuth string `hcl:"http_auth,optional"`
 
@@ -225,6 +313,35 @@ type Telemetry struct {
 	// instance is running. (e.g. a specific geo location or datacenter, dc:sfo)
 	// Defaults to none.
 	CirconusBrokerSelectTag string `hcl:"circonus_broker_select_tag,optional"`
+
+	// TracingOTLPEndpoint is the host:port of an OTLP/HTTP collector to which
+	// policy evaluation traces are exported. Tracing is disabled when unset.
+	TracingOTLPEndpoint string `hcl:"tracing_otlp_endpoint,optional"`
+
+	// TracingOTLPInsecure disables TLS when exporting traces to the
+	// TracingOTLPEndpoint.
+	TracingOTLPInsecure bool `hcl:"tracing_otlp_insecure,optional"`
+}
+
+// HighAvailability is the configuration of leader election between multiple
+// agents running against the same Nomad cluster. When enabled, only the agent
+// holding the leadership lock evaluates policies and scales targets, while
+// the other agents keep their policies loaded ready to take over.
+type HighAvailability struct {
+
+	// Enabled enables leader election.
+	Enabled bool `hcl:"enabled,optional"`
+
+	// LockPath is the path of the Nomad Variable used as the leadership lock.
+	// All agents of the deployment must use the same path.
+	LockPath string `hcl:"lock_path,optional"`
+
+	// LockTTL is the time after which the lock is released if the leader
+	// fails to renew it. Nomad also prevents the lock from being acquired for
+	// the same period after it expires, which gives the previous leader time
+	// to stop evaluating. It must be at least 10 seconds.
+	LockTTL    time.Duration
+	LockTTLHCL string `hcl:"lock_ttl,optional" json:"-"`
 }
 
 // Plugin is an individual configured plugin and holds all the required params
@@ -236,14 +353,29 @@ type Plugin struct {
 	Config map[string]string `hcl:"config,optional"`
 }
 
+// StrategyAlias is the configuration of a name which checks can use as their
+// strategy to run the Strategy plugin with the preset Config. Config set by
+// the check takes precedence over the preset Config.
+type StrategyAlias struct {
+	Name     string            `hcl:"name,label"`
+	Strategy string            `hcl:"strategy"`
+	Config   map[string]string `hcl:"config,optional"`
+}
+
 // Policy holds the configuration information specific to the policy manager
 // and resulting policy parsing.
 type Policy struct {
 
 	// Dir is the directory which contains scaling policies to be loaded from
-	// disk. This currently only supports cluster scaling policies.
+	// disk. It can also be a single file containing one or more policies.
+	// This currently only supports cluster scaling policies.
 	Dir string `hcl:"dir,optional"`
 
+	// TemplateDir is the directory which contains the policy templates that
+	// scaling policies can reference using the template parameter. Templates
+	// use the same format as the policies loaded from Dir.
+	TemplateDir string `hcl:"template_dir,optional"`
+
 	// DefaultCooldown is the default cooldown parameter added to all policies
 	// which do not explicitly configure the parameter.
 	DefaultCooldown    time.Duration
@@ -253,6 +385,60 @@ type Policy struct {
 	// `evaluation_interval` is not defined in a policy.
 	DefaultEvaluationInterval    time.Duration
 	DefaultEvaluationIntervalHCL string `hcl:"default_evaluation_interval,optional" json:"-"`
+
+	// SourcePrecedence is the order in which policy sources are used when
+	// multiple sources provide a policy with the same ID. Sources which are
+	// not listed have a lower precedence and are ordered by name.
+	SourcePrecedence []string `hcl:"source_precedence,optional"`
+
+	// CooldownExpiryWebhook is the URL which is sent a POST request when the
+	// cooldown of a policy expires, so external systems can track when
+	// policies are able to scale again.
+	CooldownExpiryWebhook string `hcl:"cooldown_expiry_webhook,optional"`
+
+	// Git is the configuration of the Git repository which scaling policies
+	// are loaded from. Policies are not loaded from Git if it is nil.
+	Git *PolicyGit `hcl:"git,block"`
+}
+
+// PolicyGit is the configuration of the Git repository which scaling policies
+// are loaded from. The repository is polled for new commits, so policies can
+// be managed using GitOps workflows.
+type PolicyGit struct {
+
+	// URL is the URL of the repository, using either HTTPS or SSH.
+	URL string `hcl:"url,optional"`
+
+	// Branch is the branch to load policies from. The default branch of the
+	// repository is used if empty.
+	Branch string `hcl:"branch,optional"`
+
+	// Path is the directory within the repository which contains the policy
+	// files. The root of the repository is used if empty.
+	Path string `hcl:"path,optional"`
+
+	// Interval is the interval at which the repository is pulled for new
+	// commits.
+	Interval    time.Duration
+	IntervalHCL string `hcl:"interval,optional" json:"-"`
+
+	// Token is the access token used to authenticate over HTTPS. Username is
+	// the username sent along with the token, which most Git hosting services
+	// ignore.
+	Token    string `hcl:"token,optional"`
+	Username string `hcl:"username,optional"`
+
+	// SSHKeyFile is the path of the private key used to authenticate over
+	// SSH.
+	SSHKeyFile string `hcl:"ssh_key_file,optional"`
+
+	// Shallow limits the clone to the latest commit of the branch.
+	Shallow bool `hcl:"shallow,optional"`
+
+	// CloneDir is the directory the repository is cloned into. A private
+	// directory within the system temporary directory is used if empty. The
+	// directory must be owned by the user running the agent.
+	CloneDir string `hcl:"clone_dir,optional"`
 }
 
 // PolicyEval holds the configuration related to the policy evaluation process.
@@ -274,6 +460,55 @@ type PolicyEval struct {
 
 	// Workers hold the number of workers to initialize for each queue.
 	Workers map[string]int `hcl:"workers,optional"`
+
+	// SlowPhaseThreshold is the duration after which a phase of a policy
+	// evaluation, such as the APM query or target scale, is logged as slow.
+	// Logging is disabled if this is zero.
+	SlowPhaseThreshold    time.Duration
+	SlowPhaseThresholdHCL string `hcl:"slow_phase_threshold,optional" json:"-"`
+
+	// WALDir is the directory used to store the write-ahead log of in-flight
+	// scaling actions. The write-ahead log is disabled if this is empty.
+	WALDir string `hcl:"wal_dir,optional"`
+
+	// WALMaxEntries is the number of records the write-ahead log can hold
+	// before it is compacted, which bounds its size on disk.
+	WALMaxEntries int `hcl:"wal_max_entries,optional"`
+
+	// PauseDir is the directory used to persist whether scaling is paused,
+	// so the agent stays paused when restarted. The state is only kept in
+	// memory if this is empty.
+	PauseDir string `hcl:"pause_dir,optional"`
+
+	// RequireDesiredCount indicates policies should only be evaluated once
+	// the target is running its desired count, such as when all allocations
+	// of a Nomad task group are running. This avoids scaling based on a count
+	// which hides failing instances. Targets which don't report both counts
+	// are always evaluated.
+	RequireDesiredCount bool `hcl:"require_desired_count,optional"`
+
+	// MaxActionCount is the absolute ceiling for the count of a scaling
+	// action, independent of the policy max. Actions above it most likely
+	// come from a faulty strategy or metric and are skipped instead of being
+	// capped. The check is disabled if this is zero.
+	MaxActionCount int64 `hcl:"max_action_count,optional"`
+
+	// EvalBufferSize is the number of policy evaluations which can be queued
+	// for dispatch to the eval broker. Policy handlers block once the buffer
+	// is full.
+	EvalBufferSize int `hcl:"eval_buffer_size,optional"`
+
+	// ScaleInHealthyGuard indicates scale in actions are skipped while the
+	// number of healthy instances of the target is at or below the new count,
+	// to avoid worsening an ongoing incident. Targets which don't report both
+	// their desired and running counts are not guarded.
+	ScaleInHealthyGuard bool `hcl:"scale_in_healthy_guard,optional"`
+
+	// MaxConcurrentScalingActions is the number of scaling actions which can
+	// be in-flight at once across all policies, to protect downstream
+	// systems. Further actions wait for a slot, with actions of higher
+	// priority policies going first. The limit is disabled if this is zero.
+	MaxConcurrentScalingActions int `hcl:"max_concurrent_scaling_actions,optional"`
 }
 
 const (
@@ -309,6 +544,33 @@ const (
 	// defaultPolicyWorkerAckTimeout is the default time limit that a policy
 	// eval must be ACK'd.
 	defaultPolicyEvalAckTimeout = 5 * time.Minute
+
+	// defaultPolicyEvalWALMaxEntries is the default number of records the
+	// write-ahead log can hold before being compacted.
+	defaultPolicyEvalWALMaxEntries = 1000
+
+	// defaultPolicyEvalMaxActionCount is the default absolute ceiling for the
+	// count of a scaling action, which matches the bound of Nomad task group
+	// counts.
+	defaultPolicyEvalMaxActionCount = math.MaxInt32
+
+	// defaultPolicyEvalBufferSize is the default number of policy evaluations
+	// which can be queued for dispatch to the eval broker.
+	defaultPolicyEvalBufferSize = 10
+
+	// defaultHighAvailabilityLockPath is the default path of the Nomad
+	// Variable used as the leadership lock.
+	defaultHighAvailabilityLockPath = "nomad-autoscaler/lock"
+
+	// defaultHighAvailabilityLockTTL is the default TTL of the leadership
+	// lock.
+	defaultHighAvailabilityLockTTL = 15 * time.Second
+
+	// minHighAvailabilityLockTTL is the minimum TTL of the leadership lock.
+	// Nomad doesn't accept lock TTLs below it, and the lock is renewed at a
+	// third of the TTL so it must be long enough to allow for a renewal
+	// request.
+	minHighAvailabilityLockTTL = 10 * time.Second
 )
 
 var defaultPolicyEvalWorkers = map[string]int{
@@ -342,9 +604,16 @@ func Default() (*Agent, error) {
 			DefaultEvaluationInterval: defaultEvaluationInterval,
 		},
 		PolicyEval: &PolicyEval{
-			DeliveryLimit: defaultPolicyEvalDeliveryLimit,
-			AckTimeout:    defaultPolicyEvalAckTimeout,
-			Workers:       defaultPolicyEvalWorkers,
+			DeliveryLimit:  defaultPolicyEvalDeliveryLimit,
+			AckTimeout:     defaultPolicyEvalAckTimeout,
+			Workers:        defaultPolicyEvalWorkers,
+			WALMaxEntries:  defaultPolicyEvalWALMaxEntries,
+			MaxActionCount: defaultPolicyEvalMaxActionCount,
+			EvalBufferSize: defaultPolicyEvalBufferSize,
+		},
+		HighAvailability: &HighAvailability{
+			LockPath: defaultHighAvailabilityLockPath,
+			LockTTL:  defaultHighAvailabilityLockTTL,
 		},
 		APMs:       []*Plugin{{Name: plugins.InternalAPMNomad, Driver: plugins.InternalAPMNomad}},
 		Strategies: []*Plugin{{Name: plugins.InternalStrategyTargetValue, Driver: plugins.InternalStrategyTargetValue}},
@@ -368,6 +637,15 @@ func (a *Agent) Merge(b *Agent) *Agent {
 	if b.PluginDir != "" {
 		result.PluginDir = b.PluginDir
 	}
+	if b.DiscoverPlugins {
+		result.DiscoverPlugins = true
+	}
+	if b.PluginCallTimeout != 0 {
+		result.PluginCallTimeout = b.PluginCallTimeout
+	}
+	if b.PluginRestartThreshold != 0 {
+		result.PluginRestartThreshold = b.PluginRestartThreshold
+	}
 	if b.HTTP != nil {
 		result.HTTP = result.HTTP.merge(b.HTTP)
 	}
@@ -388,6 +666,10 @@ func (a *Agent) Merge(b *Agent) *Agent {
 		result.PolicyEval = result.PolicyEval.merge(b.PolicyEval)
 	}
 
+	if b.HighAvailability != nil {
+		result.HighAvailability = result.HighAvailability.merge(b.HighAvailability)
+	}
+
 	if len(result.APMs) == 0 && len(b.APMs) != 0 {
 		apmCopy := make([]*Plugin, len(b.APMs))
 		for i, v := range b.APMs {
@@ -418,19 +700,74 @@ func (a *Agent) Merge(b *Agent) *Agent {
 		result.Strategies = pluginConfigSetMerge(result.Strategies, b.Strategies)
 	}
 
+	if len(b.StrategyAliases) != 0 {
+		result.StrategyAliases = strategyAliasSetMerge(result.StrategyAliases, b.StrategyAliases)
+	}
+
 	return &result
 }
 
 func (a *Agent) Validate() error {
 	var result *multierror.Error
 
+	if a.PluginCallTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("plugin_call_timeout must be positive"))
+	}
+	if a.PluginRestartThreshold < 0 {
+		result = multierror.Append(result, fmt.Errorf("plugin_restart_threshold must be positive"))
+	}
+
+	if a.Nomad != nil {
+		result = multierror.Append(result, a.Nomad.validate())
+	}
+
+	if a.Policy != nil {
+		result = multierror.Append(result, a.Policy.validate())
+	}
+
 	if a.PolicyEval != nil {
 		result = multierror.Append(result, a.PolicyEval.validate())
 	}
 
+	if a.HighAvailability != nil {
+		result = multierror.Append(result, a.HighAvailability.validate())
+	}
+
+	result = multierror.Append(result, a.validateStrategyAliases())
+
 	return result.ErrorOrNil()
 }
 
+// validateStrategyAliases validates the strategy aliases can be told apart
+// from each other and from the strategy plugins. Whether the aliased strategy
+// plugins exist is validated when the plugins are loaded, since they can be
+// configured in other files or discovered in the plugin directory.
+func (a *Agent) validateStrategyAliases() *multierror.Error {
+	var result *multierror.Error
+
+	strategies := make(map[string]bool, len(a.Strategies))
+	for _, s := range a.Strategies {
+		strategies[s.Name] = true
+	}
+
+	seen := make(map[string]bool, len(a.StrategyAliases))
+	for _, alias := range a.StrategyAliases {
+		if seen[alias.Name] {
+			result = multierror.Append(result, fmt.Errorf("strategy_alias %q is defined more than once", alias.Name))
+		}
+		seen[alias.Name] = true
+
+		if strategies[alias.Name] {
+			result = multierror.Append(result, fmt.Errorf("strategy_alias %q has the name of a strategy plugin", alias.Name))
+		}
+		if alias.Strategy == "" {
+			result = multierror.Append(result, fmt.Errorf("strategy_alias %q strategy can't be empty", alias.Name))
+		}
+	}
+
+	return result
+}
+
 func (h *HTTP) merge(b *HTTP) *HTTP {
 	result := *h
 
@@ -459,6 +796,9 @@ func (n *Nomad) merge(b *Nomad) *Nomad {
 	if b.Token != "" {
 		result.Token = b.Token
 	}
+	if b.TokenFile != "" {
+		result.TokenFile = b.TokenFile
+	}
 	if b.HTTPAuth != "" {
 		result.HTTPAuth = b.HTTPAuth
 	}
@@ -480,10 +820,55 @@ func (n *Nomad) merge(b *Nomad) *Nomad {
 	if b.SkipVerify {
 		result.SkipVerify = b.SkipVerify
 	}
+	if b.RateLimit != 0 {
+		result.RateLimit = b.RateLimit
+	}
+	if b.RateLimitBurst != 0 {
+		result.RateLimitBurst = b.RateLimitBurst
+	}
+	if len(b.Regions) != 0 {
+		result.Regions = b.Regions
+	}
 
 	return &result
 }
 
+func (n *Nomad) validate() *multierror.Error {
+	var result *multierror.Error
+	prefix := "nomad ->"
+
+	if n.Token != "" && n.TokenFile != "" {
+		result = multierror.Append(result, fmt.Errorf("token and token_file must not both be set"))
+	}
+	if n.RateLimit < 0 {
+		result = multierror.Append(result, fmt.Errorf("rate_limit must be positive"))
+	}
+	if n.RateLimitBurst < 0 {
+		result = multierror.Append(result, fmt.Errorf("rate_limit_burst must be positive"))
+	}
+
+	seen := make(map[string]bool, len(n.Regions))
+	for _, region := range n.Regions {
+		switch {
+		case region.Name == "":
+			result = multierror.Append(result, fmt.Errorf("additional_region name must not be empty"))
+		case region.Name == n.Region:
+			result = multierror.Append(result, fmt.Errorf("additional_region %q must not be the agent region", region.Name))
+		case seen[region.Name]:
+			result = multierror.Append(result, fmt.Errorf("additional_region %q is defined more than once", region.Name))
+		}
+		seen[region.Name] = true
+	}
+
+	// Prefix all errors.
+	if result != nil {
+		for i, err := range result.Errors {
+			result.Errors[i] = multierror.Prefix(err, prefix)
+		}
+	}
+	return result
+}
+
 func (t *Telemetry) merge(b *Telemetry) *Telemetry {
 	result := *t
 
@@ -550,6 +935,12 @@ func (t *Telemetry) merge(b *Telemetry) *Telemetry {
 	if b.CirconusBrokerSelectTag != "" {
 		result.CirconusBrokerSelectTag = b.CirconusBrokerSelectTag
 	}
+	if b.TracingOTLPEndpoint != "" {
+		result.TracingOTLPEndpoint = b.TracingOTLPEndpoint
+	}
+	if b.TracingOTLPInsecure {
+		result.TracingOTLPInsecure = true
+	}
 
 	return &result
 }
@@ -586,15 +977,117 @@ func (p *Policy) merge(b *Policy) *Policy {
 	if b.Dir != "" {
 		result.Dir = b.Dir
 	}
+	if b.TemplateDir != "" {
+		result.TemplateDir = b.TemplateDir
+	}
 	if b.DefaultCooldown != 0 {
 		result.DefaultCooldown = b.DefaultCooldown
 	}
 	if b.DefaultEvaluationInterval != 0 {
 		result.DefaultEvaluationInterval = b.DefaultEvaluationInterval
 	}
+	if len(b.SourcePrecedence) != 0 {
+		result.SourcePrecedence = b.SourcePrecedence
+	}
+	if b.CooldownExpiryWebhook != "" {
+		result.CooldownExpiryWebhook = b.CooldownExpiryWebhook
+	}
+	if b.Git != nil {
+		if result.Git == nil {
+			result.Git = &PolicyGit{}
+		}
+		result.Git = result.Git.merge(b.Git)
+	}
+	return &result
+}
+
+func (g *PolicyGit) merge(b *PolicyGit) *PolicyGit {
+	result := *g
+
+	if b.URL != "" {
+		result.URL = b.URL
+	}
+	if b.Branch != "" {
+		result.Branch = b.Branch
+	}
+	if b.Path != "" {
+		result.Path = b.Path
+	}
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.Token != "" {
+		result.Token = b.Token
+	}
+	if b.Username != "" {
+		result.Username = b.Username
+	}
+	if b.SSHKeyFile != "" {
+		result.SSHKeyFile = b.SSHKeyFile
+	}
+	if b.Shallow {
+		result.Shallow = true
+	}
+	if b.CloneDir != "" {
+		result.CloneDir = b.CloneDir
+	}
 	return &result
 }
 
+func (g *PolicyGit) validate() *multierror.Error {
+	var result *multierror.Error
+
+	if g.URL == "" {
+		result = multierror.Append(result, fmt.Errorf("git -> url must be set"))
+	}
+	if g.Interval < 0 {
+		result = multierror.Append(result, fmt.Errorf("git -> interval must be positive"))
+	}
+	if g.Token != "" && g.SSHKeyFile != "" {
+		result = multierror.Append(result, fmt.Errorf("git -> only one of token and ssh_key_file can be set"))
+	}
+	if filepath.IsAbs(g.Path) || strings.HasPrefix(filepath.Clean(g.Path), "..") {
+		result = multierror.Append(result, fmt.Errorf("git -> path must be within the repository"))
+	}
+	return result
+}
+
+func (p *Policy) validate() *multierror.Error {
+	var result *multierror.Error
+	prefix := "policy ->"
+
+	seen := make(map[string]bool, len(p.SourcePrecedence))
+	for _, source := range p.SourcePrecedence {
+		if source == "" {
+			result = multierror.Append(result, fmt.Errorf("source_precedence must not contain empty values"))
+			continue
+		}
+		if seen[source] {
+			result = multierror.Append(result, fmt.Errorf("source_precedence contains duplicate source %q", source))
+		}
+		seen[source] = true
+	}
+
+	if p.CooldownExpiryWebhook != "" {
+		u, err := url.Parse(p.CooldownExpiryWebhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result = multierror.Append(result, fmt.Errorf("cooldown_expiry_webhook must be an HTTP or HTTPS URL"))
+		}
+	}
+
+	if p.Git != nil {
+		result = multierror.Append(result, p.Git.validate())
+	}
+
+	// Prefix all errors.
+	if result != nil {
+		for i, err := range result.Errors {
+			result.Errors[i] = multierror.Prefix(err, prefix)
+		}
+	}
+	return result
+}
+
 func (pw *PolicyEval) merge(in *PolicyEval) *PolicyEval {
 	result := *pw
 
@@ -615,6 +1108,42 @@ func (pw *PolicyEval) merge(in *PolicyEval) *PolicyEval {
 		result.EvaluateAfter = in.EvaluateAfter
 	}
 
+	if in.SlowPhaseThreshold != 0 {
+		result.SlowPhaseThreshold = in.SlowPhaseThreshold
+	}
+
+	if in.WALDir != "" {
+		result.WALDir = in.WALDir
+	}
+
+	if in.WALMaxEntries != 0 {
+		result.WALMaxEntries = in.WALMaxEntries
+	}
+
+	if in.PauseDir != "" {
+		result.PauseDir = in.PauseDir
+	}
+
+	if in.RequireDesiredCount {
+		result.RequireDesiredCount = true
+	}
+
+	if in.MaxActionCount != 0 {
+		result.MaxActionCount = in.MaxActionCount
+	}
+
+	if in.EvalBufferSize != 0 {
+		result.EvalBufferSize = in.EvalBufferSize
+	}
+
+	if in.ScaleInHealthyGuard {
+		result.ScaleInHealthyGuard = true
+	}
+
+	if in.MaxConcurrentScalingActions != 0 {
+		result.MaxConcurrentScalingActions = in.MaxConcurrentScalingActions
+	}
+
 	return &result
 }
 
@@ -632,6 +1161,59 @@ func (pw *PolicyEval) validate() *multierror.Error {
 		}
 	}
 
+	if pw.WALMaxEntries < 0 {
+		result = multierror.Append(result, fmt.Errorf("wal_max_entries must be positive"))
+	}
+
+	if pw.SlowPhaseThreshold < 0 {
+		result = multierror.Append(result, fmt.Errorf("slow_phase_threshold must be positive"))
+	}
+
+	if pw.MaxActionCount < 0 {
+		result = multierror.Append(result, fmt.Errorf("max_action_count must be positive"))
+	}
+
+	if pw.EvalBufferSize < 0 {
+		result = multierror.Append(result, fmt.Errorf("eval_buffer_size must be positive"))
+	}
+
+	if pw.MaxConcurrentScalingActions < 0 {
+		result = multierror.Append(result, fmt.Errorf("max_concurrent_scaling_actions must be positive"))
+	}
+
+	// Prefix all errors.
+	if result != nil {
+		for i, err := range result.Errors {
+			result.Errors[i] = multierror.Prefix(err, prefix)
+		}
+	}
+	return result
+}
+
+func (ha *HighAvailability) merge(b *HighAvailability) *HighAvailability {
+	result := *ha
+
+	if b.Enabled {
+		result.Enabled = true
+	}
+	if b.LockPath != "" {
+		result.LockPath = b.LockPath
+	}
+	if b.LockTTL != 0 {
+		result.LockTTL = b.LockTTL
+	}
+
+	return &result
+}
+
+func (ha *HighAvailability) validate() *multierror.Error {
+	var result *multierror.Error
+	prefix := "high_availability ->"
+
+	if ha.LockTTL < minHighAvailabilityLockTTL {
+		result = multierror.Append(result, fmt.Errorf("lock_ttl must be at least %s", minHighAvailabilityLockTTL))
+	}
+
 	// Prefix all errors.
 	if result != nil {
 		for i, err := range result.Errors {
@@ -680,11 +1262,51 @@ func pluginConfigSetMerge(first, second []*Plugin) []*Plugin {
 	return out
 }
 
+// strategyAliasSetMerge merges the strategy aliases, replacing the aliases of
+// the first set which are also defined in the second.
+func strategyAliasSetMerge(first, second []*StrategyAlias) []*StrategyAlias {
+	sindex := make(map[string]*StrategyAlias, len(second))
+	for _, a := range second {
+		sindex[a.Name] = a
+	}
+
+	var out []*StrategyAlias
+
+	for _, a := range first {
+		if _, ok := sindex[a.Name]; !ok {
+			out = append(out, a.copy())
+		}
+	}
+	for _, a := range second {
+		out = append(out, a.copy())
+	}
+
+	return out
+}
+
+func (s *StrategyAlias) copy() *StrategyAlias {
+	c := *s
+	if i, err := copystructure.Copy(s.Config); err != nil {
+		panic(err.Error())
+	} else {
+		c.Config = i.(map[string]string)
+	}
+	return &c
+}
+
 func parseFile(file string, cfg *Agent) error {
 	if err := hclsimple.DecodeFile(file, nil, cfg); err != nil {
 		return err
 	}
 
+	if cfg.PluginCallTimeoutHCL != "" {
+		d, err := time.ParseDuration(cfg.PluginCallTimeoutHCL)
+		if err != nil {
+			return err
+		}
+		cfg.PluginCallTimeout = d
+	}
+
 	if cfg.Policy != nil {
 		if cfg.Policy.DefaultCooldownHCL != "" {
 			d, err := time.ParseDuration(cfg.Policy.DefaultCooldownHCL)
@@ -701,6 +1323,14 @@ func parseFile(file string, cfg *Agent) error {
 			}
 			cfg.Policy.DefaultEvaluationInterval = d
 		}
+
+		if cfg.Policy.Git != nil && cfg.Policy.Git.IntervalHCL != "" {
+			d, err := time.ParseDuration(cfg.Policy.Git.IntervalHCL)
+			if err != nil {
+				return err
+			}
+			cfg.Policy.Git.Interval = d
+		}
 	}
 
 	if cfg.Telemetry != nil {
@@ -740,6 +1370,24 @@ func parseFile(file string, cfg *Agent) error {
 			}
 			cfg.PolicyEval.EvaluateAfter = t
 		}
+
+		if cfg.PolicyEval.SlowPhaseThresholdHCL != "" {
+			t, err := time.ParseDuration(cfg.PolicyEval.SlowPhaseThresholdHCL)
+			if err != nil {
+				return err
+			}
+			cfg.PolicyEval.SlowPhaseThreshold = t
+		}
+	}
+
+	if cfg.HighAvailability != nil {
+		if cfg.HighAvailability.LockTTLHCL != "" {
+			t, err := time.ParseDuration(cfg.HighAvailability.LockTTLHCL)
+			if err != nil {
+				return err
+			}
+			cfg.HighAvailability.LockTTL = t
+		}
 	}
 
 	return nil
@@ -26,6 +26,12 @@ func Test_Default(t *testing.T) {
 	assert.Equal(t, defaultPolicyEvalDeliveryLimit, def.PolicyEval.DeliveryLimit)
 	assert.Equal(t, defaultPolicyEvalAckTimeout, def.PolicyEval.AckTimeout)
 	assert.Equal(t, defaultPolicyEvalWorkers, def.PolicyEval.Workers)
+	assert.Equal(t, defaultPolicyEvalWALMaxEntries, def.PolicyEval.WALMaxEntries)
+	assert.Equal(t, int64(defaultPolicyEvalMaxActionCount), def.PolicyEval.MaxActionCount)
+	assert.Equal(t, defaultPolicyEvalBufferSize, def.PolicyEval.EvalBufferSize)
+	assert.False(t, def.HighAvailability.Enabled)
+	assert.Equal(t, defaultHighAvailabilityLockPath, def.HighAvailability.LockPath)
+	assert.Equal(t, defaultHighAvailabilityLockTTL, def.HighAvailability.LockTTL)
 	assert.Len(t, def.APMs, 1)
 	assert.Len(t, def.Targets, 1)
 	assert.Len(t, def.Strategies, 1)
@@ -58,33 +64,56 @@ func TestAgent_Merge(t *testing.T) {
 				Config: map[string]string{"address": "http://prometheus.systems:9090"},
 			},
 		},
+		StrategyAliases: []*StrategyAlias{
+			{Name: "cpu-aggressive", Strategy: "target-value", Config: map[string]string{"target": "50"}},
+			{Name: "cpu-relaxed", Strategy: "target-value", Config: map[string]string{"target": "90"}},
+		},
 	}
 
 	cfg2 := &Agent{
-		EnableDebug: true,
-		LogLevel:    "trace",
-		LogJson:     true,
-		PluginDir:   "/var/lib/nomad-autoscaler/plugins",
+		EnableDebug:     true,
+		LogLevel:        "trace",
+		LogJson:         true,
+		PluginDir:       "/var/lib/nomad-autoscaler/plugins",
+		DiscoverPlugins: true,
+
+		PluginCallTimeout:      30 * time.Second,
+		PluginRestartThreshold: 3,
+
 		HTTP: &HTTP{
 			BindPort: 4646,
 		},
 		Nomad: &Nomad{
-			Address:       "https://nomad-new.systems:4646",
-			Region:        "moon-base-1",
-			Namespace:     "fra-mauro",
-			Token:         "super-secret-tokeny-thing",
-			HTTPAuth:      "admin:admin",
-			CACert:        "/etc/nomad.d/ca.crt",
-			CAPath:        "/etc/nomad.d/ca/",
-			ClientCert:    "/etc/nomad.d/client.crt",
-			ClientKey:     "/etc/nomad.d/client-key.crt",
-			TLSServerName: "cows-or-pets",
-			SkipVerify:    true,
+			Address:        "https://nomad-new.systems:4646",
+			Region:         "moon-base-1",
+			Namespace:      "fra-mauro",
+			Token:          "super-secret-tokeny-thing",
+			HTTPAuth:       "admin:admin",
+			CACert:         "/etc/nomad.d/ca.crt",
+			CAPath:         "/etc/nomad.d/ca/",
+			ClientCert:     "/etc/nomad.d/client.crt",
+			ClientKey:      "/etc/nomad.d/client-key.crt",
+			TLSServerName:  "cows-or-pets",
+			SkipVerify:     true,
+			RateLimit:      25,
+			RateLimitBurst: 5,
+			Regions: []*NomadRegion{
+				{Name: "eu", Address: "https://nomad-eu.systems:4646", Token: "eu-token"},
+			},
 		},
 		Policy: &Policy{
 			Dir:                       "/etc/scaling/policies",
+			TemplateDir:               "/etc/scaling/templates",
 			DefaultCooldown:           20 * time.Minute,
 			DefaultEvaluationInterval: 10 * time.Second,
+			SourcePrecedence:          []string{"nomad", "file"},
+			CooldownExpiryWebhook:     "https://hooks.systems/cooldown",
+			Git: &PolicyGit{
+				URL:      "https://git.systems/scaling.git",
+				Path:     "policies",
+				Interval: 30 * time.Second,
+				Shallow:  true,
+			},
 		},
 		PolicyEval: &PolicyEval{
 			DeliveryLimitPtr: ptr.IntToPtr(10),
@@ -94,6 +123,20 @@ func TestAgent_Merge(t *testing.T) {
 				"cluster":    8,
 				"horizontal": 7,
 			},
+			WALDir:             "/var/lib/nomad-autoscaler",
+			WALMaxEntries:       500,
+			PauseDir:            "/var/lib/nomad-autoscaler",
+			SlowPhaseThreshold:  2 * time.Second,
+			RequireDesiredCount: true,
+			MaxActionCount:      1000,
+			EvalBufferSize:      50,
+			ScaleInHealthyGuard: true,
+
+			MaxConcurrentScalingActions: 4,
+		},
+		HighAvailability: &HighAvailability{
+			Enabled: true,
+			LockTTL: 30 * time.Second,
 		},
 		Telemetry: &Telemetry{
 			StatsiteAddr:                       "some-address",
@@ -117,6 +160,8 @@ func TestAgent_Merge(t *testing.T) {
 			CirconusCheckDisplayName:           "some-name",
 			CirconusBrokerID:                   "some-id",
 			CirconusBrokerSelectTag:            "some-other-tag",
+			TracingOTLPEndpoint:                "otel-collector:4318",
+			TracingOTLPInsecure:                true,
 		},
 		APMs: []*Plugin{
 			{
@@ -136,34 +181,56 @@ func TestAgent_Merge(t *testing.T) {
 				Driver: "pid",
 			},
 		},
+		StrategyAliases: []*StrategyAlias{
+			{Name: "cpu-aggressive", Strategy: "pid"},
+		},
 	}
 
 	expectedResult := &Agent{
-		EnableDebug: true,
-		LogLevel:    "trace",
-		LogJson:     true,
-		PluginDir:   "/var/lib/nomad-autoscaler/plugins",
+		EnableDebug:     true,
+		LogLevel:        "trace",
+		LogJson:         true,
+		PluginDir:       "/var/lib/nomad-autoscaler/plugins",
+		DiscoverPlugins: true,
+
+		PluginCallTimeout:      30 * time.Second,
+		PluginRestartThreshold: 3,
+
 		HTTP: &HTTP{
 			BindAddress: "scaler.nomad",
 			BindPort:    4646,
 		},
 		Nomad: &Nomad{
-			Address:       "https://nomad-new.systems:4646",
-			Region:        "moon-base-1",
-			Namespace:     "fra-mauro",
-			Token:         "super-secret-tokeny-thing",
-			HTTPAuth:      "admin:admin",
-			CACert:        "/etc/nomad.d/ca.crt",
-			CAPath:        "/etc/nomad.d/ca/",
-			ClientCert:    "/etc/nomad.d/client.crt",
-			ClientKey:     "/etc/nomad.d/client-key.crt",
-			TLSServerName: "cows-or-pets",
-			SkipVerify:    true,
+			Address:        "https://nomad-new.systems:4646",
+			Region:         "moon-base-1",
+			Namespace:      "fra-mauro",
+			Token:          "super-secret-tokeny-thing",
+			HTTPAuth:       "admin:admin",
+			CACert:         "/etc/nomad.d/ca.crt",
+			CAPath:         "/etc/nomad.d/ca/",
+			ClientCert:     "/etc/nomad.d/client.crt",
+			ClientKey:      "/etc/nomad.d/client-key.crt",
+			TLSServerName:  "cows-or-pets",
+			SkipVerify:     true,
+			RateLimit:      25,
+			RateLimitBurst: 5,
+			Regions: []*NomadRegion{
+				{Name: "eu", Address: "https://nomad-eu.systems:4646", Token: "eu-token"},
+			},
 		},
 		Policy: &Policy{
 			Dir:                       "/etc/scaling/policies",
+			TemplateDir:               "/etc/scaling/templates",
 			DefaultCooldown:           20 * time.Minute,
 			DefaultEvaluationInterval: 10 * time.Second,
+			SourcePrecedence:          []string{"nomad", "file"},
+			CooldownExpiryWebhook:     "https://hooks.systems/cooldown",
+			Git: &PolicyGit{
+				URL:      "https://git.systems/scaling.git",
+				Path:     "policies",
+				Interval: 30 * time.Second,
+				Shallow:  true,
+			},
 		},
 		PolicyEval: &PolicyEval{
 			DeliveryLimitPtr: ptr.IntToPtr(10),
@@ -174,6 +241,21 @@ func TestAgent_Merge(t *testing.T) {
 				"horizontal": 7,
 				"some-other": 3,
 			},
+			WALDir:             "/var/lib/nomad-autoscaler",
+			WALMaxEntries:       500,
+			PauseDir:            "/var/lib/nomad-autoscaler",
+			SlowPhaseThreshold:  2 * time.Second,
+			RequireDesiredCount: true,
+			MaxActionCount:      1000,
+			EvalBufferSize:      50,
+			ScaleInHealthyGuard: true,
+
+			MaxConcurrentScalingActions: 4,
+		},
+		HighAvailability: &HighAvailability{
+			Enabled:  true,
+			LockPath: "nomad-autoscaler/lock",
+			LockTTL:  30 * time.Second,
 		},
 		Telemetry: &Telemetry{
 			StatsiteAddr:                       "some-address",
@@ -198,6 +280,8 @@ func TestAgent_Merge(t *testing.T) {
 			CirconusCheckDisplayName:           "some-name",
 			CirconusBrokerID:                   "some-id",
 			CirconusBrokerSelectTag:            "some-other-tag",
+			TracingOTLPEndpoint:                "otel-collector:4318",
+			TracingOTLPInsecure:                true,
 		},
 		APMs: []*Plugin{
 			{
@@ -231,6 +315,10 @@ func TestAgent_Merge(t *testing.T) {
 				Driver: "pid",
 			},
 		},
+		StrategyAliases: []*StrategyAlias{
+			{Name: "cpu-relaxed", Strategy: "target-value", Config: map[string]string{"target": "90"}},
+			{Name: "cpu-aggressive", Strategy: "pid"},
+		},
 	}
 
 	actualResult := baseCfg.Merge(cfg1)
@@ -241,11 +329,16 @@ func TestAgent_Merge(t *testing.T) {
 	assert.Equal(t, expectedResult.LogLevel, actualResult.LogLevel)
 	assert.Equal(t, expectedResult.Nomad, actualResult.Nomad)
 	assert.Equal(t, expectedResult.PluginDir, actualResult.PluginDir)
+	assert.Equal(t, expectedResult.DiscoverPlugins, actualResult.DiscoverPlugins)
+	assert.Equal(t, expectedResult.PluginCallTimeout, actualResult.PluginCallTimeout)
+	assert.Equal(t, expectedResult.PluginRestartThreshold, actualResult.PluginRestartThreshold)
 	assert.Equal(t, expectedResult.Policy, actualResult.Policy)
 	assert.Equal(t, expectedResult.PolicyEval, actualResult.PolicyEval)
+	assert.Equal(t, expectedResult.HighAvailability, actualResult.HighAvailability)
 	assert.ElementsMatch(t, expectedResult.APMs, actualResult.APMs)
 	assert.ElementsMatch(t, expectedResult.Targets, actualResult.Targets)
 	assert.ElementsMatch(t, expectedResult.Strategies, actualResult.Strategies)
+	assert.Equal(t, expectedResult.StrategyAliases, actualResult.StrategyAliases)
 }
 
 func TestAgent_parseFile(t *testing.T) {
@@ -347,3 +440,48 @@ func TestAgent_loadDir(t *testing.T) {
 	assert.Equal(t, "trace", cfg.LogLevel)
 	assert.Equal(t, "/opt/nomad-autoscaler/plugins", cfg.PluginDir)
 }
+
+func TestAgent_validateStrategyAliases(t *testing.T) {
+	cfg := &Agent{
+		Strategies: []*Plugin{{Name: "target-value", Driver: "target-value"}},
+		StrategyAliases: []*StrategyAlias{
+			{Name: "cpu-aggressive", Strategy: "target-value"},
+			{Name: "cpu-aggressive", Strategy: "target-value"},
+			{Name: "target-value", Strategy: "step"},
+			{Name: "empty"},
+		},
+	}
+
+	err := cfg.Validate()
+	assert.EqualError(t, err, `3 errors occurred:
+	* strategy_alias "cpu-aggressive" is defined more than once
+	* strategy_alias "target-value" has the name of a strategy plugin
+	* strategy_alias "empty" strategy can't be empty
+
+`)
+
+	// Aliases of strategies configured in other files are validated when
+	// the plugins are loaded.
+	cfg.StrategyAliases = []*StrategyAlias{{Name: "cpu-aggressive", Strategy: "step"}}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestHighAvailability_validate(t *testing.T) {
+	testCases := []struct {
+		inputLockTTL  time.Duration
+		expectedError bool
+		name          string
+	}{
+		{inputLockTTL: defaultHighAvailabilityLockTTL, expectedError: false, name: "default"},
+		{inputLockTTL: minHighAvailabilityLockTTL, expectedError: false, name: "minimum"},
+		{inputLockTTL: 2 * time.Nanosecond, expectedError: true, name: "too short to renew"},
+		{inputLockTTL: -time.Second, expectedError: true, name: "negative"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ha := &HighAvailability{Enabled: true, LockTTL: tc.inputLockTTL}
+			assert.Equal(t, tc.expectedError, ha.validate() != nil, tc.name)
+		})
+	}
+}
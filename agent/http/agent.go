@@ -11,6 +11,10 @@ func (s *Server) agentSpecificRequest(w http.ResponseWriter, r *http.Request) (i
 	switch {
 	case strings.HasSuffix(path, "/reload"):
 		return s.agentReload(w, r)
+	case strings.HasSuffix(path, "/pause"):
+		return s.agentPause(w, r)
+	case strings.HasSuffix(path, "/resume"):
+		return s.agentResume(w, r)
 	default:
 		return nil, newCodedError(http.StatusNotFound, "")
 	}
@@ -23,3 +27,19 @@ func (s *Server) agentReload(w http.ResponseWriter, r *http.Request) (interface{
 
 	return s.agent.ReloadAgent(w, r)
 }
+
+func (s *Server) agentPause(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	return s.agent.PauseAgent(w, r)
+}
+
+func (s *Server) agentResume(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	return s.agent.ResumeAgent(w, r)
+}
@@ -39,3 +39,51 @@ func TestServer_agentReload(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_agentPause(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedRespBody string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/agent/pause", nil),
+			expectedRespCode: 200,
+			expectedRespBody: `{"Paused":true,"Since":"2020-11-18T11:00:00Z"}`,
+			name:             "successfully pause",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/v1/agent/resume", nil),
+			expectedRespCode: 200,
+			expectedRespBody: `{"Paused":false,"Since":"2020-11-18T11:00:00Z"}`,
+			name:             "successfully resume",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/agent/pause", nil),
+			expectedRespCode: 405,
+			name:             "incorrect pause request method",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/agent/resume", nil),
+			expectedRespCode: 405,
+			name:             "incorrect resume request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, tc.inputReq)
+			assert.Equal(tc.expectedRespCode, w.Code)
+			if tc.expectedRespBody != "" {
+				assert.JSONEq(tc.expectedRespBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad-autoscaler/policy"
+)
+
+// getPendingScaleIns is the HTTP handler used to respond when a request is
+// made to the pending scale ins debug endpoint. It returns the scale ins
+// which are waiting for the scale-in stabilization window of their policy to
+// pass, keyed by policy ID.
+func (s *Server) getPendingScaleIns(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+
+	// Only allow GET requests on this endpoint.
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	return s.agent.GetPendingScaleIns(w, r)
+}
+
+// getLastActions is the HTTP handler used to respond when a request is made to
+// the last action debug endpoint. It returns the most recent scaling action
+// executed for each policy, including the history of reasons recorded as the
+// action was modified, keyed by policy ID.
+func (s *Server) getLastActions(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+
+	// Only allow GET requests on this endpoint.
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	return s.agent.GetLastActions(w, r)
+}
+
+// getSoftMaxExceeded is the HTTP handler used to respond when a request is
+// made to the exceeded soft max debug endpoint. It returns the policies whose
+// target count is above the policy's soft max, keyed by policy ID.
+func (s *Server) getSoftMaxExceeded(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+
+	// Only allow GET requests on this endpoint.
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	return s.agent.GetSoftMaxExceeded(w, r)
+}
+
+// getStrategyConfigs is the HTTP handler used to respond when a request is
+// made to the strategy config debug endpoint. It returns the config passed to
+// the Run function of each strategy of the policy identified within the path,
+// keyed by check name, with credentials redacted. The check query parameter
+// limits the response to a single check.
+func (s *Server) getStrategyConfigs(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+
+	// Only allow GET requests on this endpoint.
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	if id := strings.TrimPrefix(r.URL.Path, debugStrategyConfigRoutePattern); id == "" {
+		return nil, newCodedError(http.StatusBadRequest, "Missing policy ID")
+	}
+
+	obj, err := s.agent.GetStrategyConfigs(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, ok := obj.(map[string][]policy.StrategyConfig)
+	if !ok || configs == nil {
+		return nil, newCodedError(http.StatusNotFound, "Policy not found")
+	}
+
+	if check := r.URL.Query().Get("check"); check != "" {
+		checkConfigs, ok := configs[check]
+		if !ok {
+			return nil, newCodedError(http.StatusNotFound, "Check not found")
+		}
+		return map[string][]policy.StrategyConfig{check: checkConfigs}, nil
+	}
+	return configs, nil
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_getPendingScaleIns(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/scale-in", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"Count":2`,
+			name:             "successfully list pending scale ins",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/debug/scale-in", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.wrap(srv.getPendingScaleIns)(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_getSoftMaxExceeded(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/soft-max", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"SoftMax":8`,
+			name:             "successfully list exceeded soft maxes",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/debug/soft-max", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.wrap(srv.getSoftMaxExceeded)(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_getLastActions(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/last-action", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"ReasonHistory":["scaling up because factor is 1.500000"]`,
+			name:             "successfully list last actions",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/debug/last-action", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.wrap(srv.getLastActions)(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_getStrategyConfigs(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/strategy-config/mock-policy", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"token":"<redacted>"`,
+			name:             "successful request",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/strategy-config/mock-policy?check=mock-check", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"mock-check":[`,
+			name:             "successful request for check",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/strategy-config/mock-policy?check=unknown", nil),
+			expectedRespCode: 404,
+			name:             "check not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/strategy-config/unknown", nil),
+			expectedRespCode: 404,
+			name:             "policy not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/debug/strategy-config/", nil),
+			expectedRespCode: 400,
+			name:             "missing policy ID",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/debug/strategy-config/mock-policy", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.wrap(srv.getStrategyConfigs)(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
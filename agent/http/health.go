@@ -7,16 +7,33 @@ import (
 
 // getHealth is the HTTP handler used to respond when a request is made to the
 // health endpoint. The response is based on the aliveness parameter within the
-// httpServer struct.
-func (s *Server) getHealth(_ http.ResponseWriter, r *http.Request) (interface{}, error) {
+// httpServer struct and the health reported by the agent. An agent which can
+// perform its work, but not reliably, responds successfully with a body
+// reporting it as degraded. Requests with the type query parameter set to
+// "ready" also fail until the agent has completed its startup, so it isn't
+// trusted before it is functional.
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request) (interface{}, error) {
 
 	// Only allow GET requests on this endpoint.
 	if r.Method != http.MethodGet {
 		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
 	}
 
-	if atomic.LoadInt32(&s.aliveness) == healthAlivenessReady {
-		return nil, nil
+	if atomic.LoadInt32(&s.aliveness) != healthAlivenessReady {
+		return nil, newCodedError(http.StatusServiceUnavailable, "Service unavailable")
 	}
-	return nil, newCodedError(http.StatusServiceUnavailable, "Service unavailable")
+
+	if r.URL.Query().Get("type") == healthTypeReady {
+		if _, err := s.agent.GetReadiness(w, r); err != nil {
+			return nil, newCodedError(http.StatusServiceUnavailable, err.Error())
+		}
+	}
+
+	// The server is serving, but the agent may not be able to perform its
+	// work. Reflect this in the response so operators can alert on it.
+	health, err := s.agent.GetHealth(w, r)
+	if err != nil {
+		return nil, newCodedError(http.StatusServiceUnavailable, err.Error())
+	}
+	return health, nil
 }
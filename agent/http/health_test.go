@@ -15,6 +15,7 @@ func TestServer_getHealth(t *testing.T) {
 		inputWriter       *httptest.ResponseRecorder
 		inputSetAliveness int32
 		expectedRespCode  int
+		expectedRespBody  string
 		name              string
 	}{
 		{
@@ -22,6 +23,7 @@ func TestServer_getHealth(t *testing.T) {
 			inputWriter:       httptest.NewRecorder(),
 			inputSetAliveness: healthAlivenessReady,
 			expectedRespCode:  200,
+			expectedRespBody:  `{"Sources":[{"LastListing":"2020-11-18T11:00:00Z","Live":false,"Name":"nomad","Window":900000000000}],"Status":"degraded"}`,
 			name:              "agent alive and ready",
 		},
 		{
@@ -31,6 +33,24 @@ func TestServer_getHealth(t *testing.T) {
 			expectedRespCode:  503,
 			name:              "agent unavailable",
 		},
+		{
+			inputReq:          httptest.NewRequest("GET", "/v1/health?type=ready", nil),
+			inputWriter:       httptest.NewRecorder(),
+			inputSetAliveness: healthAlivenessReady,
+			expectedRespCode:  200,
+			name:              "agent ready type",
+		},
+		{
+			inputReq: func() *http.Request {
+				r := httptest.NewRequest("GET", "/v1/health?type=ready", nil)
+				r.Header.Set("X-Mock-Not-Ready", "true")
+				return r
+			}(),
+			inputWriter:       httptest.NewRecorder(),
+			inputSetAliveness: healthAlivenessReady,
+			expectedRespCode:  503,
+			name:              "agent not ready type",
+		},
 		{
 			inputReq:          httptest.NewRequest("PUT", "/v1/health", nil),
 			inputWriter:       httptest.NewRecorder(),
@@ -49,6 +69,9 @@ func TestServer_getHealth(t *testing.T) {
 			atomic.StoreInt32(&srv.aliveness, tc.inputSetAliveness)
 			srv.mux.ServeHTTP(tc.inputWriter, tc.inputReq)
 			assert.Equal(t, tc.expectedRespCode, tc.inputWriter.Code, tc.name)
+			if tc.expectedRespBody != "" {
+				assert.Equal(t, tc.expectedRespBody, tc.inputWriter.Body.String(), tc.name)
+			}
 		})
 	}
 }
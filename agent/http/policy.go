@@ -0,0 +1,152 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// policyFormatHCL is the value of the format query parameter used to request
+// the policy encoded as canonical HCL rather than JSON.
+const policyFormatHCL = "hcl"
+
+// policySpecificRequest handles the requests for the `/v1/policy/` endpoint and
+// sub-paths.
+func (s *Server) policySpecificRequest(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/reload"):
+		return s.reloadPolicy(w, r)
+	case strings.HasSuffix(r.URL.Path, "/limits"):
+		return s.getPolicyLimits(w, r)
+	case strings.HasSuffix(r.URL.Path, "/evaluate"):
+		return s.evaluatePolicy(w, r)
+	default:
+		return s.getPolicy(w, r)
+	}
+}
+
+// reloadPolicy is the HTTP handler used to respond when a request is made to
+// reload a single policy. The source which owns the policy reads and parses
+// it immediately, and the new version is returned.
+func (s *Server) reloadPolicy(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, policyRoutePattern), "/reload")
+	if id == "" {
+		return nil, newCodedError(http.StatusBadRequest, "Missing policy ID")
+	}
+
+	obj, err := s.agent.ReloadPolicy(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := obj.(*sdk.ScalingPolicy)
+	if !ok || p == nil {
+		return nil, newCodedError(http.StatusNotFound, "Policy not found")
+	}
+	return p, nil
+}
+
+// evaluatePolicy is the HTTP handler used to respond when a request is made to
+// evaluate a single policy immediately. The force query parameter allows the
+// evaluation to run while the policy is in cooldown, and the triggered_by
+// parameter identifies the requester in the resulting scaling action.
+func (s *Server) evaluatePolicy(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, policyRoutePattern), "/evaluate")
+	if id == "" {
+		return nil, newCodedError(http.StatusBadRequest, "Missing policy ID")
+	}
+
+	if force := r.URL.Query().Get("force"); force != "" {
+		if _, err := strconv.ParseBool(force); err != nil {
+			return nil, newCodedError(http.StatusBadRequest, fmt.Sprintf("Invalid force value %q", force))
+		}
+	}
+
+	obj, err := s.agent.EvaluatePolicy(w, r)
+	if err != nil {
+		if errors.Is(err, policy.ErrPolicyInCooldown) || errors.Is(err, policy.ErrPolicyNotReady) {
+			return nil, newCodedError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+
+	eval, ok := obj.(*sdk.ScalingEvaluation)
+	if !ok || eval == nil {
+		return nil, newCodedError(http.StatusNotFound, "Policy not found")
+	}
+	return eval, nil
+}
+
+// getPolicyLimits is the HTTP handler used to respond when a request is made
+// for the effective count limits of a single policy, along with the source
+// each limit was resolved from.
+func (s *Server) getPolicyLimits(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, policyRoutePattern), "/limits")
+	if id == "" {
+		return nil, newCodedError(http.StatusBadRequest, "Missing policy ID")
+	}
+
+	obj, err := s.agent.GetPolicyLimits(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, ok := obj.(*policy.Limits)
+	if !ok || limits == nil {
+		return nil, newCodedError(http.StatusNotFound, "Policy not found")
+	}
+	return limits, nil
+}
+
+// getPolicy is the HTTP handler used to respond when a request is made to the
+// policy endpoint. It returns the policy identified within the path as it was
+// understood by the agent after parsing, including any defaulted values.
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+
+	// Only allow GET requests on this endpoint.
+	if r.Method != http.MethodGet {
+		return nil, newCodedError(http.StatusMethodNotAllowed, errInvalidMethod)
+	}
+
+	if strings.TrimPrefix(r.URL.Path, policyRoutePattern) == "" {
+		return nil, newCodedError(http.StatusBadRequest, "Missing policy ID")
+	}
+
+	obj, err := s.agent.GetPolicy(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := obj.(*sdk.ScalingPolicy)
+	if !ok || p == nil {
+		return nil, newCodedError(http.StatusNotFound, "Policy not found")
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return p, nil
+	case policyFormatHCL:
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(policy.EncodeHCL(p))
+		return nil, nil
+	default:
+		return nil, newCodedError(http.StatusBadRequest, fmt.Sprintf("Unsupported format %q", format))
+	}
+}
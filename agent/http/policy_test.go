@@ -0,0 +1,216 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_getPolicy(t *testing.T) {
+	testCases := []struct {
+		inputReq            *http.Request
+		expectedRespCode    int
+		expectedContentType string
+		expectedBody        string
+		name                string
+	}{
+		{
+			inputReq:            httptest.NewRequest("GET", "/v1/policy/mock-policy", nil),
+			expectedRespCode:    200,
+			expectedContentType: "application/json",
+			expectedBody:        `"ID":"mock-policy"`,
+			name:                "policy as json",
+		},
+		{
+			inputReq:            httptest.NewRequest("GET", "/v1/policy/mock-policy?format=hcl", nil),
+			expectedRespCode:    200,
+			expectedContentType: "text/plain",
+			expectedBody:        `scaling "mock-policy" {`,
+			name:                "policy as hcl",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/mock-policy?format=yaml", nil),
+			expectedRespCode: 400,
+			name:             "unsupported format",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/", nil),
+			expectedRespCode: 400,
+			name:             "missing policy ID",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/unknown", nil),
+			expectedRespCode: 404,
+			name:             "policy not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/v1/policy/mock-policy", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedContentType, w.Header().Get("Content-Type"), tc.name)
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_reloadPolicy(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/mock-policy/reload", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"ID":"mock-policy"`,
+			name:             "successful reload",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/v1/policy/mock-policy/reload", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"ID":"mock-policy"`,
+			name:             "successful reload with PUT",
+		},
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/unknown/reload", nil),
+			expectedRespCode: 404,
+			name:             "policy not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/mock-policy/reload", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_getPolicyLimits(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/mock-policy/limits", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"Max":{"Source":"static","Value":10}`,
+			name:             "successful request",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/unknown/limits", nil),
+			expectedRespCode: 404,
+			name:             "policy not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("PUT", "/v1/policy/mock-policy/limits", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
+
+func TestServer_evaluatePolicy(t *testing.T) {
+	testCases := []struct {
+		inputReq         *http.Request
+		expectedRespCode int
+		expectedBody     string
+		name             string
+	}{
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/mock-policy/evaluate?triggered_by=alice", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"Trigger":"manual","TriggeredBy":"alice"`,
+			name:             "successful request",
+		},
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/mock-cooldown-policy/evaluate", nil),
+			expectedRespCode: 409,
+			expectedBody:     "policy is in cooldown",
+			name:             "policy in cooldown",
+		},
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/mock-cooldown-policy/evaluate?force=true", nil),
+			expectedRespCode: 200,
+			expectedBody:     `"Trigger":"manual"`,
+			name:             "forced request during cooldown",
+		},
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/mock-policy/evaluate?force=maybe", nil),
+			expectedRespCode: 400,
+			name:             "invalid force value",
+		},
+		{
+			inputReq:         httptest.NewRequest("POST", "/v1/policy/unknown/evaluate", nil),
+			expectedRespCode: 404,
+			name:             "policy not found",
+		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/mock-policy/evaluate", nil),
+			expectedRespCode: 405,
+			name:             "incorrect request method",
+		},
+	}
+
+	srv, stopSrv := TestServer(t)
+	defer stopSrv()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, tc.inputReq)
+			assert.Equal(t, tc.expectedRespCode, w.Code, tc.name)
+
+			if tc.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedBody, tc.name)
+			}
+		})
+	}
+}
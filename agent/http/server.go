@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad-autoscaler/agent"
 	"github.com/hashicorp/nomad-autoscaler/agent/config"
 )
 
@@ -28,6 +30,30 @@ const (
 	// register endpoints related to the agent.
 	agentRoutePattern = "/v1/agent/"
 
+	// policyRoutePattern is the Autoscaler HTTP router pattern which is used
+	// to register the policy endpoint.
+	policyRoutePattern = "/v1/policy/"
+
+	// debugScaleInRoutePattern is the Autoscaler HTTP router pattern which is
+	// used to register the pending scale ins debug endpoint.
+	debugScaleInRoutePattern = "/debug/scale-in"
+
+	// debugSoftMaxRoutePattern is the Autoscaler HTTP router pattern which
+	// is used to register the exceeded soft max debug endpoint.
+	debugSoftMaxRoutePattern = "/debug/soft-max"
+
+	// debugLastActionRoutePattern is the Autoscaler HTTP router pattern which
+	// is used to register the last action debug endpoint.
+	debugLastActionRoutePattern = "/debug/last-action"
+
+	// debugStrategyConfigRoutePattern is the Autoscaler HTTP router pattern
+	// which is used to register the strategy config debug endpoint.
+	debugStrategyConfigRoutePattern = "/debug/strategy-config/"
+
+	// healthTypeReady is the value of the type query parameter of the health
+	// endpoint used to check the readiness of the agent.
+	healthTypeReady = "ready"
+
 	// healthAliveness is used to define the health of the Autoscaler agent. It
 	// currently can only be in two states; ready or unavailable and depends
 	// entirely on whether the server is serving or not.
@@ -43,6 +69,62 @@ type AgentHTTP interface {
 
 	// ReloadAgent triggers the agent to reload policies and configuration.
 	ReloadAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// PauseAgent pauses the execution of scaling actions by all policies, and
+	// returns the new pause status. Policies are still evaluated.
+	PauseAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// ResumeAgent resumes the execution of scaling actions, and returns the
+	// new pause status.
+	ResumeAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetHealth returns an error if the agent is running but unable to
+	// perform its work, such as when Nomad is unreachable. Otherwise it may
+	// return details of the agent's health, such as the liveness of the
+	// policy sources.
+	GetHealth(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetReadiness returns an error until the agent has completed its
+	// startup and is able to perform its work, such as before any policy
+	// evaluation has completed successfully.
+	GetReadiness(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetPolicy returns the policy identified within the request path, as
+	// understood by the agent after parsing. It returns a nil object if the
+	// policy is not found.
+	GetPolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// ReloadPolicy reads the policy identified within the request path from
+	// its source and returns the new version. It returns a nil object if the
+	// policy is not found.
+	ReloadPolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetPolicyLimits returns the effective count limits of the policy
+	// identified within the request path, including the quota cap of its
+	// target if it was capped when last scaled. It returns a nil object if
+	// the policy is not found.
+	GetPolicyLimits(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// EvaluatePolicy requests an immediate evaluation of the policy
+	// identified within the request path and returns the evaluation. It
+	// returns a nil object if the policy is not found.
+	EvaluatePolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetPendingScaleIns returns the scale ins which are waiting for the
+	// scale-in stabilization window of their policy to pass.
+	GetPendingScaleIns(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetSoftMaxExceeded returns the policies whose target count is above
+	// the policy's soft max.
+	GetSoftMaxExceeded(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetLastActions returns the most recent scaling action executed for
+	// each policy, along with its reason history, keyed by policy ID.
+	GetLastActions(resp http.ResponseWriter, req *http.Request) (interface{}, error)
+
+	// GetStrategyConfigs returns the strategy configs of each check of the
+	// policy identified within the request path, keyed by check name.
+	GetStrategyConfigs(resp http.ResponseWriter, req *http.Request) (interface{}, error)
 }
 
 type Server struct {
@@ -74,6 +156,7 @@ func NewHTTPServer(debug bool, cfg *config.HTTP, log hclog.Logger, agent AgentHT
 	srv.mux.HandleFunc(healthRoutePattern, srv.wrap(srv.getHealth))
 	srv.mux.HandleFunc(metricsRoutePattern, srv.wrap(srv.getMetrics))
 	srv.mux.HandleFunc(agentRoutePattern, srv.wrap(srv.agentSpecificRequest))
+	srv.mux.HandleFunc(policyRoutePattern, srv.wrap(srv.policySpecificRequest))
 
 	// Setup the debugging endpoints.
 	if debug {
@@ -82,6 +165,10 @@ func NewHTTPServer(debug bool, cfg *config.HTTP, log hclog.Logger, agent AgentHT
 		srv.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 		srv.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 		srv.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		srv.mux.HandleFunc(debugScaleInRoutePattern, srv.wrap(srv.getPendingScaleIns))
+		srv.mux.HandleFunc(debugSoftMaxRoutePattern, srv.wrap(srv.getSoftMaxExceeded))
+		srv.mux.HandleFunc(debugLastActionRoutePattern, srv.wrap(srv.getLastActions))
+		srv.mux.HandleFunc(debugStrategyConfigRoutePattern, srv.wrap(srv.getStrategyConfigs))
 	}
 
 	// Configure the HTTP server to the most basic level.
@@ -91,6 +178,7 @@ func NewHTTPServer(debug bool, cfg *config.HTTP, log hclog.Logger, agent AgentHT
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  15 * time.Second,
+		ErrorLog:     srv.log.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}),
 	}
 
 	// Announce on the configured network address. If there is an error in the
@@ -206,6 +294,11 @@ func (s *Server) handleHTTPError(w http.ResponseWriter, r *http.Request, err err
 		code = codedErr.Code()
 	}
 
+	// The agent is still starting, so the request can be retried.
+	if errors.Is(err, agent.ErrAgentNotReady) {
+		code = http.StatusServiceUnavailable
+	}
+
 	// Write the status code header.
 	w.WriteHeader(code)
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,6 +36,14 @@ func TestServer_handlerHTTPError(t *testing.T) {
 			expectedRespBody: "I'm a teapot",
 			name:             "custom error using codedError",
 		},
+		{
+			inputReq:         httptest.NewRequest("GET", "/v1/policy/id", nil),
+			inputWriter:      httptest.NewRecorder(),
+			inputError:       agent.ErrAgentNotReady,
+			expectedRespCode: 503,
+			expectedRespBody: "agent not ready",
+			name:             "agent not ready",
+		},
 	}
 
 	srv := &Server{log: hclog.NewNullLogger()}
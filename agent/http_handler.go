@@ -1,14 +1,241 @@
 package agent
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/policyeval"
+)
 
 // The methods in this file implement in the http.AgentHTTP interface.
 
+// ErrAgentNotReady is returned by the HTTP handlers of the agent until Run has
+// initialised the components they use.
+var ErrAgentNotReady = errors.New("agent not ready")
+
+// checkReady returns ErrAgentNotReady until Run has initialised the agent. It
+// must be called by each HTTP handler before using the agent components.
+func (a *Agent) checkReady() error {
+	a.readyLock.RLock()
+	defer a.readyLock.RUnlock()
+
+	if !a.ready {
+		return ErrAgentNotReady
+	}
+	return nil
+}
+
 func (a *Agent) DisplayMetrics(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
 	return a.inMemSink.DisplayMetrics(resp, req)
 }
 
 func (a *Agent) ReloadAgent(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
 	a.reload()
 	return nil, nil
 }
+
+func (a *Agent) PauseAgent(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	return a.setPaused(true)
+}
+
+func (a *Agent) ResumeAgent(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	return a.setPaused(false)
+}
+
+func (a *Agent) setPaused(paused bool) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+
+	status, err := a.pause.Set(paused)
+	if err != nil {
+		return nil, err
+	}
+
+	if paused {
+		a.logger.Warn("scaling paused, scaling actions will not be executed")
+	} else {
+		a.logger.Info("scaling resumed")
+	}
+	return &status, nil
+}
+
+// Health is the response of the health endpoint when the agent is able to
+// perform its work.
+type Health struct {
+
+	// Status is "degraded" if any policy source has not sent a listing of
+	// policy IDs within its expected window, and "ok" otherwise.
+	Status string
+
+	// Sources details the liveness of each running policy source.
+	Sources []policy.SourceHealth
+
+	// Leadership is the leadership status of the agent. It is only included
+	// when high availability is enabled.
+	Leadership *Leadership `json:",omitempty"`
+
+	// Pause is the pause status of scaling. It is only included while
+	// scaling is paused.
+	Pause *policyeval.PauseStatus `json:",omitempty"`
+
+	// PluginRestarts details the plugins which have been restarted because
+	// they consistently timed out.
+	PluginRestarts []manager.PluginRestarts `json:",omitempty"`
+}
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusDegraded = "degraded"
+)
+
+func (a *Agent) GetHealth(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	if a.nomadPolicySource != nil && !a.nomadPolicySource.Reachable() {
+		return nil, errors.New("unable to reach the Nomad API")
+	}
+
+	health := &Health{Status: healthStatusOK, Sources: a.policyManager.SourceHealth()}
+	if a.leaderElection != nil {
+		health.Leadership = a.leaderElection.leadership()
+	}
+	health.PluginRestarts = a.pluginManager.PluginRestarts()
+	if a.pause.Paused() {
+		status := a.pause.Status()
+		health.Pause = &status
+	}
+	for _, s := range health.Sources {
+		if !s.Live {
+			health.Status = healthStatusDegraded
+			a.logger.Warn("policy source has not sent a policy listing within the expected window",
+				"policy_source", s.Name, "last_listing", s.LastListing, "window", s.Window)
+		}
+	}
+	return health, nil
+}
+
+func (a *Agent) GetReadiness(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+
+	// Followers discard their evaluations, so they can't wait for one to
+	// complete.
+	if !a.leaderElection.isLeader() {
+		return nil, nil
+	}
+
+	if a.evalBroker.Acked() {
+		return nil, nil
+	}
+
+	// An agent without policies has nothing to evaluate, so it is ready once
+	// the policy sources have listed their policies.
+	if count, listed := a.policyManager.PolicyCount(); listed && count == 0 {
+		return nil, nil
+	}
+	return nil, errors.New("no policy evaluation has completed successfully")
+}
+
+func (a *Agent) GetPolicy(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimPrefix(req.URL.Path, "/v1/policy/")
+	if p, ok := a.policyManager.GetPolicy(id); ok {
+		return p, nil
+	}
+	return nil, nil
+}
+
+func (a *Agent) GetPolicyLimits(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v1/policy/"), "/limits")
+	if p, ok := a.policyManager.GetPolicy(id); ok {
+		limits := policy.EffectiveLimits(p)
+		if capped, ok := a.policyManager.QuotaCap(id); ok {
+			limits = limits.WithQuotaCap(capped)
+		}
+		return &limits, nil
+	}
+	return nil, nil
+}
+
+func (a *Agent) EvaluatePolicy(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v1/policy/"), "/evaluate")
+	force, _ := strconv.ParseBool(req.URL.Query().Get("force"))
+
+	if !a.leaderElection.isLeader() {
+		return nil, fmt.Errorf("agent is not the leader: %w", policy.ErrPolicyNotReady)
+	}
+
+	eval, ok, err := a.policyManager.EvaluatePolicy(id, req.URL.Query().Get("triggered_by"), force)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	a.evalBroker.Enqueue(eval)
+	return eval, nil
+}
+
+func (a *Agent) GetPendingScaleIns(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	return a.policyManager.PendingScaleIns(), nil
+}
+
+func (a *Agent) GetSoftMaxExceeded(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	return a.policyManager.SoftMaxExceeded(), nil
+}
+
+func (a *Agent) GetLastActions(_ http.ResponseWriter, _ *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	return a.policyManager.LastActions(), nil
+}
+
+func (a *Agent) GetStrategyConfigs(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimPrefix(req.URL.Path, "/debug/strategy-config/")
+	if p, ok := a.policyManager.GetPolicy(id); ok {
+		return policy.EffectiveStrategyConfigs(p), nil
+	}
+	return nil, nil
+}
+
+func (a *Agent) ReloadPolicy(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if err := a.checkReady(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v1/policy/"), "/reload")
+	p, ok, err := a.policyManager.ReloadPolicy(req.Context(), id)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgent_httpHandlers_notReady(t *testing.T) {
+	a := &Agent{}
+
+	// The HTTP server is started before the agent runs, so the handlers must
+	// not use the agent components until they are initialised.
+	handlers := map[string]func(http.ResponseWriter, *http.Request) (interface{}, error){
+		"DisplayMetrics":     a.DisplayMetrics,
+		"ReloadAgent":        a.ReloadAgent,
+		"PauseAgent":         a.PauseAgent,
+		"ResumeAgent":        a.ResumeAgent,
+		"GetHealth":          a.GetHealth,
+		"GetReadiness":       a.GetReadiness,
+		"GetPolicy":          a.GetPolicy,
+		"GetPolicyLimits":    a.GetPolicyLimits,
+		"EvaluatePolicy":     a.EvaluatePolicy,
+		"GetPendingScaleIns": a.GetPendingScaleIns,
+		"GetSoftMaxExceeded": a.GetSoftMaxExceeded,
+		"GetLastActions":     a.GetLastActions,
+		"GetStrategyConfigs": a.GetStrategyConfigs,
+		"ReloadPolicy":       a.ReloadPolicy,
+	}
+
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			obj, err := handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/policy/id", nil))
+			assert.Nil(t, obj)
+			assert.Equal(t, ErrAgentNotReady, err)
+		})
+	}
+}
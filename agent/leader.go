@@ -0,0 +1,249 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
+	"github.com/hashicorp/nomad/api"
+)
+
+// leaderLock is a lock shared by all agents of a deployment, which is used to
+// elect the leader.
+type leaderLock interface {
+
+	// acquire attempts to acquire the lock. It returns false without an error
+	// if the lock is held by another agent.
+	acquire() (bool, error)
+
+	// renew extends the TTL of the held lock. It returns an error if the lock
+	// could not be renewed, in which case it may no longer be held.
+	renew() error
+
+	// release releases the held lock, so another agent can acquire it
+	// without waiting for the TTL to expire.
+	release() error
+}
+
+// leaderElection runs the election of the leader amongst the agents of a
+// deployment, and tracks whether this agent is the leader.
+//
+// Followers keep running their policy sources and handlers, so they are able
+// to take over as soon as they are elected. The cooldown of each policy is
+// derived from the last scaling event reported by its target, so the new
+// leader honours cooldowns started by the previous leader.
+type leaderElection struct {
+	logger hclog.Logger
+	lock   leaderLock
+
+	// interval is the interval at which followers attempt to acquire the
+	// lock and the leader renews it.
+	interval time.Duration
+
+	stateLock sync.RWMutex
+	leader    bool
+	since     time.Time
+}
+
+// Leadership is the leadership status of the agent.
+type Leadership struct {
+
+	// Leader is true if the agent is the leader and evaluates policies.
+	Leader bool
+
+	// Since is the time at which the agent last became the leader or a
+	// follower.
+	Since time.Time
+}
+
+// newLeaderElection returns a new leaderElection using the passed lock. The
+// lock is renewed three times per TTL, so a single failed renewal does not
+// cause the lock to expire.
+func newLeaderElection(logger hclog.Logger, lock leaderLock, ttl time.Duration) *leaderElection {
+	return &leaderElection{
+		logger:   logger,
+		lock:     lock,
+		interval: ttl / 3,
+		since:    time.Now(),
+	}
+}
+
+// run participates in the election until the context is canceled, at which
+// point the lock is released if held.
+func (e *leaderElection) run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.elect()
+
+		select {
+		case <-ctx.Done():
+			if e.isLeader() {
+				if err := e.lock.release(); err != nil {
+					e.logger.Warn("failed to release leadership lock", "error", err)
+				}
+				e.setLeader(false)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect renews the lock if the agent is the leader, or attempts to acquire it
+// otherwise. The agent steps down as soon as a renewal fails, as it can no
+// longer be sure another agent has not acquired the lock.
+func (e *leaderElection) elect() {
+	if e.isLeader() {
+		if err := e.lock.renew(); err != nil {
+			e.logger.Error("failed to renew leadership lock, stepping down", "error", err)
+			e.setLeader(false)
+		}
+		return
+	}
+
+	acquired, err := e.lock.acquire()
+	if err != nil {
+		e.logger.Warn("failed to acquire leadership lock", "error", err)
+		return
+	}
+	if acquired {
+		e.logger.Info("acquired leadership lock, evaluating policies")
+		e.setLeader(true)
+	}
+}
+
+// isLeader returns whether the agent is the leader. A nil leaderElection,
+// used when high availability is disabled, is always the leader.
+func (e *leaderElection) isLeader() bool {
+	if e == nil {
+		return true
+	}
+
+	e.stateLock.RLock()
+	defer e.stateLock.RUnlock()
+	return e.leader
+}
+
+// leadership returns the leadership status of the agent.
+func (e *leaderElection) leadership() *Leadership {
+	e.stateLock.RLock()
+	defer e.stateLock.RUnlock()
+	return &Leadership{Leader: e.leader, Since: e.since}
+}
+
+func (e *leaderElection) setLeader(leader bool) {
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
+	if e.leader != leader {
+		e.leader = leader
+		e.since = time.Now()
+	}
+
+	var v float32
+	if leader {
+		v = 1
+	}
+	metrics.SetGauge([]string{"leader", "is_leader"}, v)
+}
+
+// setupLeaderElection starts the leader election if high availability has
+// been enabled.
+func (a *Agent) setupLeaderElection(ctx context.Context) {
+	ha := a.config.HighAvailability
+	if ha == nil || !ha.Enabled {
+		return
+	}
+
+	holder, err := os.Hostname()
+	if err != nil {
+		holder = "unknown"
+	}
+	holder = fmt.Sprintf("%s-%d", holder, os.Getpid())
+
+	lock := &nomadVariableLock{client: a.nomadClient, path: ha.LockPath, ttl: ha.LockTTL, holder: holder}
+	a.leaderElection = newLeaderElection(a.logger.Named("leader"), lock, ha.LockTTL)
+
+	a.logger.Info("high availability enabled, waiting for leadership", "lock_path", ha.LockPath)
+	go a.leaderElection.run(ctx)
+}
+
+// nomadVariableLock implements leaderLock using the lock of a Nomad Variable.
+type nomadVariableLock struct {
+	client *api.Client
+	path   string
+	ttl    time.Duration
+
+	// holder identifies the agent holding the lock, and is written to the
+	// variable so operators can find the leader.
+	holder string
+
+	// id is the ID of the lock while it is held.
+	id string
+}
+
+// variableLockRequest is the body of the Nomad Variable lock requests.
+type variableLockRequest struct {
+	Path  string
+	Items map[string]string `json:",omitempty"`
+	Lock  *variableLock
+}
+
+// variableLock is the lock of a Nomad Variable.
+type variableLock struct {
+	ID        string `json:",omitempty"`
+	TTL       string `json:",omitempty"`
+	LockDelay string `json:",omitempty"`
+}
+
+func (l *nomadVariableLock) acquire() (bool, error) {
+	req := &variableLockRequest{
+		Path:  l.path,
+		Items: map[string]string{"holder": l.holder},
+		Lock:  &variableLock{TTL: l.ttl.String(), LockDelay: l.ttl.String()},
+	}
+
+	var resp variableLockRequest
+	if err := l.write("lock-acquire", req, &resp); err != nil {
+		if nomadHelper.ResponseCode(err) == http.StatusConflict {
+			return false, nil
+		}
+		return false, err
+	}
+	if resp.Lock == nil || resp.Lock.ID == "" {
+		return false, fmt.Errorf("response does not include the lock ID")
+	}
+
+	l.id = resp.Lock.ID
+	return true, nil
+}
+
+func (l *nomadVariableLock) renew() error {
+	return l.write("lock-renew", &variableLockRequest{Path: l.path, Lock: &variableLock{ID: l.id}}, nil)
+}
+
+func (l *nomadVariableLock) release() error {
+	return l.write("lock-release", &variableLockRequest{Path: l.path, Lock: &variableLock{ID: l.id}}, nil)
+}
+
+// write performs the lock operation op against the variable.
+func (l *nomadVariableLock) write(op string, in, out interface{}) error {
+	segments := strings.Split(strings.Trim(l.path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	endpoint := fmt.Sprintf("/v1/var/%s?%s", strings.Join(segments, "/"), op)
+
+	_, err := l.client.Raw().Write(endpoint, in, out, nil)
+	return err
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLeaderLock is a leaderLock which returns scripted results.
+type testLeaderLock struct {
+	acquired   bool
+	acquireErr error
+	renewErr   error
+	released   bool
+}
+
+func (l *testLeaderLock) acquire() (bool, error) { return l.acquired, l.acquireErr }
+func (l *testLeaderLock) renew() error           { return l.renewErr }
+func (l *testLeaderLock) release() error         { l.released = true; return nil }
+
+func TestLeaderElection_elect(t *testing.T) {
+	lock := &testLeaderLock{}
+	e := newLeaderElection(hclog.NewNullLogger(), lock, 15*time.Second)
+
+	// The lock is held by another agent.
+	e.elect()
+	assert.False(t, e.isLeader())
+
+	// Errors acquiring the lock leave the agent as a follower.
+	lock.acquired, lock.acquireErr = true, errors.New("connection refused")
+	e.elect()
+	assert.False(t, e.isLeader())
+
+	lock.acquireErr = nil
+	e.elect()
+	assert.True(t, e.isLeader())
+	since := e.leadership().Since
+
+	// Renewing the lock keeps the agent the leader.
+	e.elect()
+	assert.Equal(t, &Leadership{Leader: true, Since: since}, e.leadership())
+
+	// The agent steps down as soon as a renewal fails.
+	lock.renewErr = errors.New("lock not held")
+	e.elect()
+	assert.False(t, e.leadership().Leader)
+}
+
+func TestLeaderElection_isLeader(t *testing.T) {
+	// Agents without high availability are always the leader.
+	var e *leaderElection
+	assert.True(t, e.isLeader())
+}
+
+func TestNomadVariableLock(t *testing.T) {
+	var requests []string
+	var heldBy string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/v1/var/nomad-autoscaler/lock", r.URL.Path)
+
+		var req variableLockRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, r.URL.RawQuery)
+
+		switch {
+		case hasParam(r, "lock-acquire"):
+			if heldBy != "" {
+				http.Error(w, "conflict", http.StatusConflict)
+				return
+			}
+			assert.Equal(t, "15s", req.Lock.TTL)
+			assert.Equal(t, "agent-1", req.Items["holder"])
+			heldBy = "lock-id"
+			_ = json.NewEncoder(w).Encode(&variableLockRequest{Path: req.Path, Lock: &variableLock{ID: heldBy}})
+		case hasParam(r, "lock-renew"), hasParam(r, "lock-release"):
+			if req.Lock.ID != heldBy {
+				http.Error(w, "lock not held", http.StatusConflict)
+				return
+			}
+			if hasParam(r, "lock-release") {
+				heldBy = ""
+			}
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer ts.Close()
+
+	client, err := api.NewClient(&api.Config{Address: ts.URL})
+	require.NoError(t, err)
+
+	lock := &nomadVariableLock{client: client, path: "nomad-autoscaler/lock", ttl: 15 * time.Second, holder: "agent-1"}
+
+	acquired, err := lock.acquire()
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.NoError(t, lock.renew())
+
+	// The lock is held, so another agent can't acquire it.
+	other := &nomadVariableLock{client: client, path: "nomad-autoscaler/lock", ttl: 15 * time.Second, holder: "agent-2"}
+	acquired, err = other.acquire()
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Error(t, other.renew())
+
+	assert.NoError(t, lock.release())
+	assert.Len(t, requests, 5)
+}
+
+func hasParam(r *http.Request, key string) bool {
+	_, ok := r.URL.Query()[key]
+	return ok
+}
@@ -15,11 +15,25 @@ import (
 func (a *Agent) setupPlugins() error {
 
 	a.pluginManager = manager.NewPluginManager(a.logger, a.config.PluginDir, a.setupPluginsConfig())
+	a.pluginManager.SetHangDetection(a.config.PluginCallTimeout, a.config.PluginRestartThreshold)
 
 	// Trigger the loading of the plugins which will be available to the agent.
 	// Any errors here will cause the agent to fail, but will include wrapped
 	// errors so the user can fix any problems in a single iteration.
-	return a.pluginManager.Load()
+	if err := a.pluginManager.Load(); err != nil {
+		return err
+	}
+
+	// The strategy aliases are validated against the loaded plugins, which
+	// include the plugins discovered in the plugin directory.
+	return a.pluginManager.SetStrategyAliases(a.config.StrategyAliases)
+}
+
+// PluginsConfig returns the configuration of the plugins launched by the
+// agent, including the inherited Nomad configuration. It allows commands to
+// launch the plugins without running the agent.
+func (a *Agent) PluginsConfig() map[string][]*config.Plugin {
+	return a.setupPluginsConfig()
 }
 
 // setupPluginsConfig builds a map which is used by the plugin manager to load
@@ -38,6 +52,19 @@ func (a *Agent) setupPluginsConfig() map[string][]*config.Plugin {
 		cfg[sdk.PluginTypeTarget] = a.config.Targets
 	}
 
+	// Register the plugins found in the plugin directory which have not been
+	// configured by the operator.
+	if a.config.DiscoverPlugins {
+		for pluginType, discovered := range manager.DiscoverPlugins(a.logger, a.config.PluginDir) {
+			for _, d := range discovered {
+				if !hasPluginNamed(cfg[pluginType], d.Name) {
+					a.logger.Info("registering discovered plugin", "plugin_name", d.Name, "plugin_type", pluginType)
+					cfg[pluginType] = append(cfg[pluginType], d)
+				}
+			}
+		}
+	}
+
 	// Iterate the configs and perform the config setup on each. If the
 	// operator did not specify any config, it will be nil so make sure we
 	// initialise the map.
@@ -63,6 +90,7 @@ func (a *Agent) setupPluginConfig(cfg map[string]string) {
 	val, ok := cfg[plugins.ConfigKeyNomadConfigInherit]
 	if !ok {
 		nomadHelper.MergeMapWithAgentConfig(cfg, a.nomadCfg)
+		nomadHelper.MergeMapWithRegionConfigs(cfg, a.nomadRegionCfgs)
 		return
 	}
 
@@ -77,7 +105,19 @@ func (a *Agent) setupPluginConfig(cfg map[string]string) {
 	}
 	if boolVal {
 		nomadHelper.MergeMapWithAgentConfig(cfg, a.nomadCfg)
+		nomadHelper.MergeMapWithRegionConfigs(cfg, a.nomadRegionCfgs)
+	}
+}
+
+// hasPluginNamed returns whether the list of plugin configurations contains
+// one with the passed name.
+func hasPluginNamed(cfgs []*config.Plugin, name string) bool {
+	for _, c := range cfgs {
+		if c.Name == name {
+			return true
+		}
 	}
+	return false
 }
 
 func (a *Agent) getNomadAPMNames() []string {
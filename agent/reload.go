@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	filePolicy "github.com/hashicorp/nomad-autoscaler/policy/file"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// ConfigLoader loads the agent configuration from its sources, such as config
+// files and CLI flags. It is called when the agent is asked to reload.
+type ConfigLoader func() (*config.Agent, error)
+
+// reloadConfig loads the agent configuration and applies the parameters which
+// can be changed without restarting the agent:
+//
+//   - log_level
+//   - apm, strategy and target plugin blocks
+//   - discover_plugins, which also rescans the plugin directory
+//   - plugin_call_timeout and plugin_restart_threshold
+//   - policy.dir
+//   - policy.git, which restarts the Git policy source
+//   - policy.template_dir, which also reloads the policy templates
+//   - policy.default_cooldown
+//   - policy.default_evaluation_interval
+//
+// Changes to any other parameter are logged, but only take effect once the
+// agent is restarted. Evaluations which are in-flight are not interrupted.
+//
+// The returned boolean indicates whether the policy sources need to be
+// updated.
+func (a *Agent) reloadConfig() bool {
+	a.logger.Info("reloading agent configuration")
+
+	newCfg, err := a.configLoader()
+	if err != nil {
+		a.logger.Error("failed to reload agent configuration, keeping current configuration", "error", err)
+		return false
+	}
+
+	for _, field := range restartRequiredFields(a.config, newCfg) {
+		a.logger.Warn("configuration change requires agent restart to take effect", "field", field)
+	}
+
+	if newCfg.LogLevel != a.config.LogLevel {
+		a.logger.Info("updating log level", "log_level", newCfg.LogLevel)
+		a.logger.SetLevel(hclog.LevelFromString(newCfg.LogLevel))
+		a.config.LogLevel = newCfg.LogLevel
+	}
+
+	// Reload the plugins. Plugins which fail to launch are logged, but do not
+	// prevent the rest of the configuration being applied.
+	a.config.APMs = newCfg.APMs
+	a.config.Strategies = newCfg.Strategies
+	a.config.Targets = newCfg.Targets
+	a.config.DiscoverPlugins = newCfg.DiscoverPlugins
+	if err := a.pluginManager.Reload(a.setupPluginsConfig()); err != nil {
+		a.logger.Error("failed to reload plugins", "error", err)
+	}
+	a.config.StrategyAliases = newCfg.StrategyAliases
+	if err := a.pluginManager.SetStrategyAliases(a.config.StrategyAliases); err != nil {
+		a.logger.Error("failed to reload strategy aliases, keeping current aliases", "error", err)
+	}
+
+	a.config.PluginCallTimeout = newCfg.PluginCallTimeout
+	a.config.PluginRestartThreshold = newCfg.PluginRestartThreshold
+	a.pluginManager.SetHangDetection(a.config.PluginCallTimeout, a.config.PluginRestartThreshold)
+
+	// Update the policy processor so policies are parsed using the new
+	// defaults and Nomad APM names.
+	a.config.Policy.DefaultCooldown = newCfg.Policy.DefaultCooldown
+	a.config.Policy.DefaultEvaluationInterval = newCfg.Policy.DefaultEvaluationInterval
+	a.config.Policy.TemplateDir = newCfg.Policy.TemplateDir
+	a.policyProcessor.Update(a.policyConfigDefaults(), a.getNomadAPMNames())
+
+	var updateSources bool
+	if newCfg.Policy.Dir != a.config.Policy.Dir {
+		a.logger.Info("updating policy directory", "dir", newCfg.Policy.Dir)
+		a.config.Policy.Dir = newCfg.Policy.Dir
+		updateSources = true
+	}
+	if !reflect.DeepEqual(newCfg.Policy.Git, a.config.Policy.Git) {
+		a.logger.Info("updating git policy source")
+		a.config.Policy.Git = newCfg.Policy.Git
+		updateSources = true
+	}
+	return updateSources
+}
+
+// restartRequiredFields returns the names of the configuration parameters
+// which differ between the configs but cannot be reloaded.
+func restartRequiredFields(old, new *config.Agent) []string {
+	fields := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"log_json", old.LogJson, new.LogJson},
+		{"enable_debug", old.EnableDebug, new.EnableDebug},
+		{"plugin_dir", old.PluginDir, new.PluginDir},
+		{"http", old.HTTP, new.HTTP},
+		{"nomad", old.Nomad, new.Nomad},
+		{"high_availability", old.HighAvailability, new.HighAvailability},
+		{"policy.source_precedence", old.Policy.SourcePrecedence, new.Policy.SourcePrecedence},
+		{"policy.cooldown_expiry_webhook", old.Policy.CooldownExpiryWebhook, new.Policy.CooldownExpiryWebhook},
+		{"policy_eval", old.PolicyEval, new.PolicyEval},
+		{"telemetry", old.Telemetry, new.Telemetry},
+	}
+
+	var changed []string
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.old, f.new) {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+// policyConfigDefaults returns the policy defaults from the agent config.
+func (a *Agent) policyConfigDefaults() *policy.ConfigDefaults {
+	return &policy.ConfigDefaults{
+		DefaultEvaluationInterval: a.config.Policy.DefaultEvaluationInterval,
+		DefaultCooldown:           a.config.Policy.DefaultCooldown,
+		Templates:                 a.policyTemplates(),
+		TargetSchemas:             a.pluginManager.TargetConfigSchemas(),
+		TargetValidators:          a.pluginManager.TargetConfigValidators(),
+		QueryValidators:           a.pluginManager.APMQueryValidators(),
+		ConfigOverriders:          a.pluginManager.APMConfigOverriders(),
+		StrategyValidators:        a.pluginManager.StrategyConfigValidators(),
+	}
+}
+
+// policyTemplates loads the policy templates from the configured template
+// directory. Templates which fail to load are logged rather than stopping the
+// agent, policies which reference them fail validation instead.
+func (a *Agent) policyTemplates() map[string]*sdk.ScalingPolicy {
+	if a.config.Policy.TemplateDir == "" {
+		return nil
+	}
+
+	templates, err := filePolicy.LoadTemplates(a.config.Policy.TemplateDir)
+	if err != nil {
+		a.logger.Error("failed to load policy templates", "dir", a.config.Policy.TemplateDir, "error", err)
+	}
+	return templates
+}
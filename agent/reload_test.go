@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_restartRequiredFields(t *testing.T) {
+	testCases := []struct {
+		inputModifier  func(*config.Agent)
+		expectedOutput []string
+		name           string
+	}{
+		{
+			inputModifier:  func(c *config.Agent) {},
+			expectedOutput: nil,
+			name:           "no changes",
+		},
+		{
+			inputModifier: func(c *config.Agent) {
+				c.LogLevel = "trace"
+				c.Policy.Dir = "/policies"
+				c.Policy.Git = &config.PolicyGit{URL: "https://git.example.com/policies.git"}
+				c.Policy.DefaultCooldown = time.Hour
+				c.APMs = append(c.APMs, &config.Plugin{Name: "mock", Driver: "mock-apm"})
+			},
+			expectedOutput: nil,
+			name:           "only reloadable changes",
+		},
+		{
+			inputModifier: func(c *config.Agent) {
+				c.HTTP.BindPort = 9999
+				c.Nomad.Address = "http://nomad.example.com:4646"
+				c.HighAvailability.Enabled = true
+				c.Policy.SourcePrecedence = []string{"file"}
+				c.Telemetry.PrometheusMetrics = true
+			},
+			expectedOutput: []string{"http", "nomad", "high_availability", "policy.source_precedence", "telemetry"},
+			name:           "restart required changes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldCfg, err := config.Default()
+			assert.NoError(t, err)
+			newCfg, err := config.Default()
+			assert.NoError(t, err)
+
+			tc.inputModifier(newCfg)
+			assert.Equal(t, tc.expectedOutput, restartRequiredFields(oldCfg, newCfg), tc.name)
+		})
+	}
+}
+
+func TestAgent_reloadConfig(t *testing.T) {
+	logger := hclog.NewNullLogger()
+
+	cfg, err := config.Default()
+	assert.NoError(t, err)
+
+	a := NewAgent(cfg, nil, logger)
+	a.pluginManager = manager.NewPluginManager(logger, "", a.setupPluginsConfig())
+	assert.NoError(t, a.pluginManager.Load())
+	defer a.pluginManager.KillPlugins()
+	a.policyProcessor = policy.NewProcessor(a.policyConfigDefaults(), a.getNomadAPMNames())
+
+	// A failure to load the config keeps the current config.
+	a.configLoader = func() (*config.Agent, error) { return nil, errors.New("bad config") }
+	assert.False(t, a.reloadConfig())
+	assert.Equal(t, cfg, a.config)
+
+	newCfg, err := config.Default()
+	assert.NoError(t, err)
+	newCfg.Policy.Dir = "/policies"
+	newCfg.Policy.DefaultCooldown = time.Hour
+	newCfg.APMs = append(newCfg.APMs, &config.Plugin{Name: "mock", Driver: "mock-apm"})
+
+	a.configLoader = func() (*config.Agent, error) { return newCfg, nil }
+	assert.True(t, a.reloadConfig())
+	assert.Equal(t, "/policies", a.config.Policy.Dir)
+	assert.True(t, a.pluginManager.HasPlugin("mock", sdk.PluginTypeAPM))
+
+	// The processor applies the new defaults to policies.
+	p := &sdk.ScalingPolicy{}
+	a.policyProcessor.ApplyPolicyDefaults(p)
+	assert.Equal(t, time.Hour, p.Cooldown)
+
+	// Reloading the same config does not require the sources to be updated.
+	assert.False(t, a.reloadConfig())
+}
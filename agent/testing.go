@@ -1,9 +1,15 @@
 package agent
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/policyeval"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
 
 type MockAgentHTTP struct{}
@@ -20,3 +26,146 @@ func (m *MockAgentHTTP) DisplayMetrics(resp http.ResponseWriter, req *http.Reque
 func (m *MockAgentHTTP) ReloadAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	return nil, nil
 }
+
+func (m *MockAgentHTTP) PauseAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return &policyeval.PauseStatus{
+		Paused: true,
+		Since:  time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *MockAgentHTTP) ResumeAgent(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return &policyeval.PauseStatus{
+		Paused: false,
+		Since:  time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *MockAgentHTTP) GetHealth(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return &Health{
+		Status: healthStatusDegraded,
+		Sources: []policy.SourceHealth{
+			{
+				Name:        policy.SourceNameNomad,
+				LastListing: time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC),
+				Window:      15 * time.Minute,
+				Live:        false,
+			},
+		},
+	}, nil
+}
+
+func (m *MockAgentHTTP) GetReadiness(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Header.Get("X-Mock-Not-Ready") != "" {
+		return nil, errors.New("no policy evaluation has completed successfully")
+	}
+	return nil, nil
+}
+
+func (m *MockAgentHTTP) GetPolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if strings.TrimPrefix(req.URL.Path, "/v1/policy/") != "mock-policy" {
+		return nil, nil
+	}
+	return mockPolicy(), nil
+}
+
+func (m *MockAgentHTTP) ReloadPolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.URL.Path != "/v1/policy/mock-policy/reload" {
+		return nil, nil
+	}
+	return mockPolicy(), nil
+}
+
+func (m *MockAgentHTTP) GetPolicyLimits(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.URL.Path != "/v1/policy/mock-policy/limits" {
+		return nil, nil
+	}
+	limits := policy.EffectiveLimits(mockPolicy())
+	return &limits, nil
+}
+
+func (m *MockAgentHTTP) EvaluatePolicy(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.URL.Path {
+	case "/v1/policy/mock-policy/evaluate":
+	case "/v1/policy/mock-cooldown-policy/evaluate":
+		if req.URL.Query().Get("force") != "true" {
+			return nil, policy.ErrPolicyInCooldown
+		}
+	default:
+		return nil, nil
+	}
+
+	eval := sdk.NewScalingEvaluation(mockPolicy(), &sdk.TargetStatus{Ready: true, Count: 1})
+	eval.ID = "mock-eval"
+	eval.CreateTime = time.Date(2020, time.November, 17, 0, 17, 50, 0, time.UTC)
+	eval.Trigger = sdk.EvaluationTriggerManual
+	eval.TriggeredBy = req.URL.Query().Get("triggered_by")
+	return eval, nil
+}
+
+func (m *MockAgentHTTP) GetPendingScaleIns(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return map[string]policy.PendingScaleIn{
+		"mock-policy": {
+			Since:     time.Date(2020, time.November, 17, 0, 17, 50, 0, time.UTC),
+			Window:    5 * time.Minute,
+			Remaining: time.Minute,
+			Count:     2,
+		},
+	}, nil
+}
+
+func (m *MockAgentHTTP) GetSoftMaxExceeded(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return map[string]policy.SoftMaxExceeded{
+		"mock-policy": {
+			Since:   time.Date(2020, time.November, 17, 0, 17, 50, 0, time.UTC),
+			SoftMax: 8,
+			Count:   9,
+		},
+	}, nil
+}
+
+func (m *MockAgentHTTP) GetLastActions(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return map[string]policy.LastAction{
+		"mock-policy": {
+			Time:          time.Date(2020, time.November, 17, 0, 17, 50, 0, time.UTC),
+			Count:         10,
+			Direction:     "up",
+			Reason:        "capped count from 12 to 10 to stay within limits",
+			ReasonHistory: []string{"scaling up because factor is 1.500000"},
+		},
+	}, nil
+}
+
+func (m *MockAgentHTTP) GetStrategyConfigs(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.URL.Path != "/debug/strategy-config/mock-policy" {
+		return nil, nil
+	}
+
+	p := mockPolicy()
+	p.Checks = []*sdk.ScalingPolicyCheck{
+		{
+			Name: "mock-check",
+			Strategy: &sdk.ScalingPolicyStrategy{
+				Name:   "target-value",
+				Config: map[string]string{"target": "70", "token": "mock-token"},
+			},
+		},
+	}
+	return policy.EffectiveStrategyConfigs(p), nil
+}
+
+func mockPolicy() *sdk.ScalingPolicy {
+	return &sdk.ScalingPolicy{
+		ID:                 "mock-policy",
+		Type:               sdk.ScalingPolicyTypeHorizontal,
+		Min:                1,
+		Max:                10,
+		Enabled:            true,
+		Cooldown:           5 * time.Minute,
+		EvaluationInterval: 10 * time.Second,
+		Target: &sdk.ScalingPolicyTarget{
+			Name:   "nomad",
+			Config: map[string]string{"Job": "example", "Group": "cache"},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
+)
+
+// nomadTokenFileCheckInterval is the interval at which the Nomad ACL token
+// file is re-read to detect a rotated token.
+const nomadTokenFileCheckInterval = 10 * time.Second
+
+// readNomadTokenFile reads the Nomad ACL token from the file at the passed
+// path. Surrounding whitespace, such as a trailing newline, is removed.
+func readNomadTokenFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return "", fmt.Errorf("Nomad token file %q does not exist", path)
+	case os.IsPermission(err):
+		return "", fmt.Errorf("permission denied reading Nomad token file %q", path)
+	case err != nil:
+		return "", fmt.Errorf("failed to read Nomad token file %q: %v", path, err)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("Nomad token file %q is empty", path)
+	}
+	return token, nil
+}
+
+// loadNomadTokenFile sets the Nomad API configs to use the token read from
+// the configured token file. It must be called before the Nomad clients are
+// created.
+func (a *Agent) loadNomadTokenFile() error {
+	if a.config.Nomad.TokenFile == "" {
+		return nil
+	}
+
+	token, err := readNomadTokenFile(a.config.Nomad.TokenFile)
+	if err != nil {
+		return err
+	}
+	a.setNomadToken(token)
+	return nil
+}
+
+// setNomadToken sets the token of the Nomad API configs, and of the clients
+// if they have been created. Additional regions which configure their own
+// token are not modified.
+func (a *Agent) setNomadToken(token string) {
+	a.nomadCfg.SecretID = token
+	if a.nomadClient != nil {
+		a.nomadClient.SetSecretID(token)
+	}
+
+	for _, region := range a.config.Nomad.Regions {
+		if region.Token != "" {
+			continue
+		}
+		if cfg, ok := a.nomadRegionCfgs[region.Name]; ok {
+			cfg.SecretID = token
+		}
+		if client, ok := a.nomadRegionClients[region.Name]; ok {
+			client.SetSecretID(token)
+		}
+	}
+}
+
+// runNomadTokenFileWatcher periodically re-reads the Nomad ACL token file
+// and updates the token used by the agent when it changes.
+func (a *Agent) runNomadTokenFileWatcher(ctx context.Context) {
+	ticker := time.NewTicker(nomadTokenFileCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.reloadNomadTokenFile()
+		}
+	}
+}
+
+// reloadNomadTokenFile re-reads the Nomad ACL token file and, if the token
+// has changed, updates the Nomad clients of the agent. Plugins which
+// inherited the previous token from the agent are relaunched using the new
+// token. Errors reading the file are logged and the current token is kept.
+func (a *Agent) reloadNomadTokenFile() {
+	token, err := readNomadTokenFile(a.config.Nomad.TokenFile)
+	if err != nil {
+		a.logger.Error("failed to reload Nomad ACL token, continuing to use the current token", "error", err)
+		return
+	}
+
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	oldToken := a.nomadCfg.SecretID
+	if token == oldToken {
+		return
+	}
+
+	a.logger.Info("Nomad ACL token file changed, updating token", "path", a.config.Nomad.TokenFile)
+	a.setNomadToken(token)
+
+	for _, cfgs := range [][]*config.Plugin{a.config.APMs, a.config.Strategies, a.config.Targets} {
+		for _, c := range cfgs {
+			nomadHelper.ReplaceMapToken(c.Config, oldToken, token)
+		}
+	}
+	if err := a.pluginManager.Reload(a.setupPluginsConfig()); err != nil {
+		a.logger.Error("failed to reload plugins using the new Nomad ACL token", "error", err)
+	}
+}
@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgent_readNomadTokenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nomad-autoscaler-token")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid")
+	assert.NoError(t, ioutil.WriteFile(valid, []byte("  secret-id\n"), 0600))
+	empty := filepath.Join(dir, "empty")
+	assert.NoError(t, ioutil.WriteFile(empty, []byte("\n"), 0600))
+	missing := filepath.Join(dir, "missing")
+
+	testCases := []struct {
+		inputPath      string
+		expectedOutput string
+		expectedError  string
+		name           string
+	}{
+		{
+			inputPath:      valid,
+			expectedOutput: "secret-id",
+			name:           "token with surrounding whitespace",
+		},
+		{
+			inputPath:     empty,
+			expectedError: `Nomad token file "` + empty + `" is empty`,
+			name:          "empty file",
+		},
+		{
+			inputPath:     missing,
+			expectedError: `Nomad token file "` + missing + `" does not exist`,
+			name:          "missing file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := readNomadTokenFile(tc.inputPath)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
+
+func TestAgent_reloadNomadTokenFile(t *testing.T) {
+	var lastToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastToken = r.Header.Get("X-Nomad-Token")
+		_, _ = w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "nomad-autoscaler-token")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("old-token\n"), 0600))
+
+	cfg, err := config.Default()
+	assert.NoError(t, err)
+	cfg.Nomad.Address = srv.URL
+	cfg.Nomad.TokenFile = tokenFile
+
+	logger := hclog.NewNullLogger()
+	a := NewAgent(cfg, nil, logger)
+	assert.NoError(t, a.loadNomadTokenFile())
+	assert.NoError(t, a.generateNomadClient())
+
+	a.pluginManager = manager.NewPluginManager(logger, "", a.setupPluginsConfig())
+	assert.NoError(t, a.pluginManager.Load())
+	defer a.pluginManager.KillPlugins()
+	assert.Equal(t, "old-token", cfg.Targets[0].Config["nomad_token"])
+
+	_, err = a.nomadClient.Status().Leader()
+	assert.NoError(t, err)
+	assert.Equal(t, "old-token", lastToken)
+
+	// A missing file keeps the current token.
+	assert.NoError(t, os.Remove(tokenFile))
+	a.reloadNomadTokenFile()
+	_, err = a.nomadClient.Status().Leader()
+	assert.NoError(t, err)
+	assert.Equal(t, "old-token", lastToken)
+
+	// A rotated token is used by the agent client and passed to plugins.
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("new-token\n"), 0600))
+	a.reloadNomadTokenFile()
+	_, err = a.nomadClient.Status().Leader()
+	assert.NoError(t, err)
+	assert.Equal(t, "new-token", lastToken)
+	assert.Equal(t, "new-token", cfg.Targets[0].Config["nomad_token"])
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// setupTracing configures the global OpenTelemetry tracer provider to export
+// policy evaluation spans to the configured OTLP/HTTP endpoint. When no
+// endpoint is configured the global no-op provider is left in place so that
+// spans created by the policy evaluation workers are discarded.
+func (a *Agent) setupTracing(cfg *config.Telemetry) error {
+	if cfg == nil || cfg.TracingOTLPEndpoint == "" {
+		return nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.TracingOTLPEndpoint)}
+	if cfg.TracingOTLPInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to setup OTLP trace exporter: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "nomad-autoscaler"),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	a.tracerProvider = provider
+
+	a.logger.Info("exporting policy evaluation traces", "endpoint", cfg.TracingOTLPEndpoint)
+	return nil
+}
+
+// stopTracing flushes any buffered spans and shuts down the tracer provider
+// if tracing is enabled.
+func (a *Agent) stopTracing() {
+	if a.tracerProvider == nil {
+		return
+	}
+	if err := a.tracerProvider.Shutdown(context.Background()); err != nil {
+		a.logger.Error("failed to shutdown tracer provider", "error", err)
+	}
+}
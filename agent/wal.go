@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad-autoscaler/plugins/target"
+	"github.com/hashicorp/nomad-autoscaler/policyeval"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// setupWAL opens the write-ahead log of in-flight scaling actions if it has
+// been configured, and verifies any actions which were pending when the agent
+// last stopped.
+func (a *Agent) setupWAL() error {
+	if a.config.PolicyEval.WALDir == "" {
+		return nil
+	}
+
+	wal, err := policyeval.OpenWAL(a.config.PolicyEval.WALDir, a.config.PolicyEval.WALMaxEntries)
+	if err != nil {
+		return err
+	}
+	a.wal = wal
+
+	for _, entry := range wal.Pending() {
+		a.verifyWALEntry(entry)
+
+		if err := wal.Complete(entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyWALEntry compares a scaling action which was in-flight when the agent
+// stopped against the current status of its target, and logs the outcome so
+// operators are aware of any ambiguous state.
+//
+// The action is not replayed, as the policy will be evaluated again using up
+// to date information once the agent is running.
+func (a *Agent) verifyWALEntry(entry *policyeval.WALEntry) {
+	logger := a.logger.With("policy_id", entry.PolicyID, "desired_count", entry.Count,
+		"direction", entry.Direction.String(), "created", entry.CreateTime)
+
+	status, err := a.walEntryTargetStatus(entry)
+	if err != nil {
+		logger.Warn("unable to verify scaling action which was in-flight when the agent stopped", "error", err)
+		return
+	}
+
+	if status == nil {
+		logger.Warn("target of scaling action which was in-flight when the agent stopped no longer exists")
+		return
+	}
+
+	if status.Count == entry.Count {
+		logger.Info("verified scaling action which was in-flight when the agent stopped was applied")
+	} else {
+		logger.Warn("scaling action which was in-flight when the agent stopped may not have been applied",
+			"current_count", status.Count)
+	}
+}
+
+// walEntryTargetStatus fetches the current status of the target referenced by
+// the WAL entry.
+func (a *Agent) walEntryTargetStatus(entry *policyeval.WALEntry) (*sdk.TargetStatus, error) {
+	if entry.Target == nil {
+		return nil, fmt.Errorf("entry has no target")
+	}
+
+	targetPlugin, err := a.pluginManager.Dispense(entry.Target.Name, sdk.PluginTypeTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	targetInst, ok := targetPlugin.Plugin().(target.Target)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a target plugin", entry.Target.Name)
+	}
+
+	return targetInst.Status(entry.Target.Config)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"flag"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -19,6 +20,12 @@ import (
 type AgentCommand struct {
 	args []string
 
+	// configPath and cmdConfig are the config file paths and CLI flag config
+	// parsed from args. They are used to load the agent config on startup and
+	// each time the agent is reloaded.
+	configPath []string
+	cmdConfig  *config.Agent
+
 	agent      *agent.Agent
 	httpServer *agentHTTP.Server
 }
@@ -36,6 +43,15 @@ Usage: nomad-autoscaler agent [options] [args]
   files used, but a subset of the options may also be passed directly as CLI
   arguments or environment variables, listed below.
 
+  Sending the agent a SIGHUP signal, or calling the /v1/agent/reload endpoint,
+  reloads the configuration files and CLI arguments. The following parameters
+  are applied without restarting the agent: log_level, the apm, strategy and
+  target plugin blocks, discover_plugins, plugin_call_timeout,
+  plugin_restart_threshold, and the policy dir, git, template_dir,
+  default_cooldown and default_evaluation_interval parameters. Plugins are
+  only restarted if their configuration changed. All other parameters require
+  the agent to be restarted before changes take effect.
+
 Options:
 
   -config=<path>
@@ -58,6 +74,19 @@ Options:
     specified, the plugin directory defaults to be that of
     <current-dir>/plugins/.
 
+  -discover-plugins
+    Register the executable plugins found in the plugin directory which are
+    not configured using apm, strategy or target blocks. The default is false.
+
+  -plugin-call-timeout=<dur>
+    The time after which calls to plugins which can be abandoned, such as APM
+    queries, target status and strategy runs, are timed out. Calls are not
+    timed out by default.
+
+  -plugin-restart-threshold=<num>
+    The number of consecutive timed out calls after which a plugin is assumed
+    to be hung and is restarted. Plugins are not restarted by default.
+
 HTTP Options:
 
   -http-bind-address=<addr>
@@ -82,6 +111,11 @@ Nomad Options:
   -nomad-token=<token>
     The SecretID of an ACL token to use to authenticate API requests with.
 
+  -nomad-token-file=<path>
+    The path to a file containing the SecretID of an ACL token to use to
+    authenticate API requests with. The file is periodically re-read so the
+    token can be rotated without restarting the agent.
+
   -nomad-http-auth=<username:password>
     The authentication information to use when connecting to a Nomad API which
     is using HTTP authentication.
@@ -109,10 +143,24 @@ Nomad Options:
   -nomad-skip-verify
     Do not verify TLS certificates. This is strongly discouraged.
 
+  -nomad-rate-limit=<num>
+    The maximum number of requests per second the agent makes to the Nomad
+    API. Requests which exceed the limit are queued. The default is 0, which
+    disables the limit.
+
+  -nomad-rate-limit-burst=<num>
+    The number of requests which can be made to the Nomad API at once before
+    the rate limit is applied. The default is 1.
+
 Policy Options:
 
   -policy-dir=<path>
-    The path to a directory used to load scaling policies.
+    The path to a directory or file used to load scaling policies. A single
+    file can contain multiple scaling policies.
+
+  -policy-template-dir=<path>
+    The path to a directory used to load policy templates, which scaling
+    policies can reference using the template parameter.
 
   -policy-default-cooldown=<dur>
     The default cooldown that will be applied to all scaling policies which do
@@ -122,6 +170,12 @@ Policy Options:
     The default evaluation interval that will be applied to all scaling policies
     which do not specify an evaluation interval.
 
+  -policy-source-precedence=<name>
+    The name of a policy source, such as "file" or "nomad", used to resolve
+    policies with the same ID provided by multiple sources. Can be specified
+    multiple times; earlier sources have higher precedence. Sources which are
+    not listed have a lower precedence and are ordered by name.
+
 Telemetry Options:
 
   -telemetry-disable-hostname
@@ -235,6 +289,11 @@ func (c *AgentCommand) Run(args []string) int {
 		JSONFormat: parsedConfig.LogJson,
 	})
 
+	// Redirect the standard library logger, which is used by some of our
+	// dependencies, so all output honors the configured log format.
+	log.SetOutput(logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}))
+	log.SetFlags(0)
+
 	logger.Info("Starting Nomad Autoscaler agent")
 	// Compile agent information for output later
 	info := make(map[string]string)
@@ -267,7 +326,7 @@ func (c *AgentCommand) Run(args []string) int {
 	logger.Info("Nomad Autoscaler agent started! Log data will stream in below:")
 
 	// create and run agent and HTTP server
-	c.agent = agent.NewAgent(parsedConfig, logger)
+	c.agent = agent.NewAgent(parsedConfig, c.loadConfig, logger)
 	httpServer, err := agentHTTP.NewHTTPServer(parsedConfig.EnableDebug, parsedConfig.HTTP, logger, c.agent)
 	if err != nil {
 		logger.Error("failed to setup HTTP getHealth server", "error", err)
@@ -305,6 +364,12 @@ func (c *AgentCommand) readConfig() *config.Agent {
 	flags.BoolVar(&cmdConfig.LogJson, "log-json", false, "")
 	flags.BoolVar(&cmdConfig.EnableDebug, "enable-debug", false, "")
 	flags.StringVar(&cmdConfig.PluginDir, "plugin-dir", "", "")
+	flags.BoolVar(&cmdConfig.DiscoverPlugins, "discover-plugins", false, "")
+	flags.Var((flaghelper.FuncDurationVar)(func(d time.Duration) error {
+		cmdConfig.PluginCallTimeout = d
+		return nil
+	}), "plugin-call-timeout", "")
+	flags.IntVar(&cmdConfig.PluginRestartThreshold, "plugin-restart-threshold", 0, "")
 
 	// Specify our HTTP bind flags.
 	flags.StringVar(&cmdConfig.HTTP.BindAddress, "http-bind-address", "", "")
@@ -315,6 +380,7 @@ func (c *AgentCommand) readConfig() *config.Agent {
 	flags.StringVar(&cmdConfig.Nomad.Region, "nomad-region", "", "")
 	flags.StringVar(&cmdConfig.Nomad.Namespace, "nomad-namespace", "", "")
 	flags.StringVar(&cmdConfig.Nomad.Token, "nomad-token", "", "")
+	flags.StringVar(&cmdConfig.Nomad.TokenFile, "nomad-token-file", "", "")
 	flags.StringVar(&cmdConfig.Nomad.HTTPAuth, "nomad-http-auth", "", "")
 	flags.StringVar(&cmdConfig.Nomad.CACert, "nomad-ca-cert", "", "")
 	flags.StringVar(&cmdConfig.Nomad.CAPath, "nomad-ca-path", "", "")
@@ -322,9 +388,12 @@ func (c *AgentCommand) readConfig() *config.Agent {
 	flags.StringVar(&cmdConfig.Nomad.ClientKey, "nomad-client-key", "", "")
 	flags.StringVar(&cmdConfig.Nomad.TLSServerName, "nomad-tls-server-name", "", "")
 	flags.BoolVar(&cmdConfig.Nomad.SkipVerify, "nomad-skip-verify", false, "")
+	flags.Float64Var(&cmdConfig.Nomad.RateLimit, "nomad-rate-limit", 0, "")
+	flags.IntVar(&cmdConfig.Nomad.RateLimitBurst, "nomad-rate-limit-burst", 0, "")
 
 	// Specify our Policy CLI flags.
 	flags.StringVar(&cmdConfig.Policy.Dir, "policy-dir", "", "")
+	flags.StringVar(&cmdConfig.Policy.TemplateDir, "policy-template-dir", "", "")
 	flags.Var((flaghelper.FuncDurationVar)(func(d time.Duration) error {
 		cmdConfig.Policy.DefaultCooldown = d
 		return nil
@@ -333,6 +402,7 @@ func (c *AgentCommand) readConfig() *config.Agent {
 		cmdConfig.Policy.DefaultEvaluationInterval = d
 		return nil
 	}), "policy-default-evaluation-interval", "")
+	flags.Var((*flaghelper.StringFlag)(&cmdConfig.Policy.SourcePrecedence), "policy-source-precedence", "")
 
 	// Specify our Telemetry CLI flags.
 	flags.BoolVar(&cmdConfig.Telemetry.DisableHostname, "telemetry-disable-hostname", false, "")
@@ -368,11 +438,25 @@ func (c *AgentCommand) readConfig() *config.Agent {
 		return nil
 	}
 
+	c.configPath = configPath
+	c.cmdConfig = cmdConfig
+
+	cfg, err := c.loadConfig()
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+	return cfg
+}
+
+// loadConfig loads the agent config by merging the default config, the config
+// files and the CLI flags.
+func (c *AgentCommand) loadConfig() (*config.Agent, error) {
+
 	// Grab a default config as the base.
 	cfg, err := config.Default()
 	if err != nil {
-		fmt.Printf("Error generating default agent config: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("Error generating default agent config: %v", err)
 	}
 
 	var validationErr *multierror.Error
@@ -380,11 +464,10 @@ func (c *AgentCommand) readConfig() *config.Agent {
 	// Merge in the enterprise overlay.
 	cfg = cfg.Merge(config.DefaultEntConfig())
 
-	for _, path := range configPath {
+	for _, path := range c.configPath {
 		current, err := config.Load(path)
 		if err != nil {
-			fmt.Printf("Error loading configuration from %s: %s\n", path, err)
-			return nil
+			return nil, fmt.Errorf("Error loading configuration from %s: %s", path, err)
 		}
 
 		if err := current.Validate(); err != nil {
@@ -403,12 +486,9 @@ func (c *AgentCommand) readConfig() *config.Agent {
 	}
 
 	if validationErr != nil {
-		fmt.Printf("Invalid configuration. %v", validationErr)
-		return nil
+		return nil, fmt.Errorf("Invalid configuration. %v", validationErr)
 	}
 
 	// Merge the read file based configuration with the passed CLI args.
-	cfg = cfg.Merge(cmdConfig)
-
-	return cfg
+	return cfg.Merge(c.cmdConfig), nil
 }
@@ -0,0 +1,195 @@
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	filePolicy "github.com/hashicorp/nomad-autoscaler/policy/file"
+	nomadPolicy "github.com/hashicorp/nomad-autoscaler/policy/nomad"
+	fileHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/file"
+	"github.com/hashicorp/nomad/api"
+)
+
+type PolicyLintCommand struct{}
+
+// Help should return long-form help text that includes the command-line
+// usage, a brief few sentences explaining the function of the command,
+// and the complete list of flags the command accepts.
+func (c *PolicyLintCommand) Help() string {
+	helpText := `
+Usage: nomad-autoscaler policy lint [options] <path> [<path>...]
+
+  Validates scaling policies without connecting to Nomad or loading plugins,
+  so policy mistakes can be found before they are deployed. Each path can be
+  a file or a directory, in which case all the ".hcl" and ".json" files within
+  it are validated.
+
+  Files are validated as file source scaling policies, unless they are JSON
+  Nomad jobs, such as the output of "nomad job run -output", in which case the
+  scaling policies of the job are validated as they would be by the Nomad
+  policy source.
+
+  The exit code is 0 if all policies are valid, and 1 otherwise.
+
+Options:
+
+  -template-dir=<path>
+    The path to a directory used to load the policy templates referenced by
+    the file source scaling policies.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis should return a one-line, short synopsis of the command.
+// This should be less than 50 characters ideally.
+func (c *PolicyLintCommand) Synopsis() string {
+	return "Validates scaling policy files"
+}
+
+// Run should run the actual command with the given CLI instance and
+// command-line arguments. It should return the exit status when it is
+// finished.
+func (c *PolicyLintCommand) Run(args []string) int {
+	flags := flag.NewFlagSet("policy lint", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Println(c.Help()) }
+
+	var templateDir string
+	flags.StringVar(&templateDir, "template-dir", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "At least one path to lint is required")
+		return 1
+	}
+
+	defaults := &policy.ConfigDefaults{}
+	if templateDir != "" {
+		templates, err := filePolicy.LoadTemplates(templateDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load policy templates: %v\n", err)
+			return 1
+		}
+		defaults.Templates = templates
+	}
+
+	results, err := lintPaths(paths, policy.NewProcessor(defaults, nil))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to lint policies: %v\n", err)
+		return 1
+	}
+
+	files := make([]string, 0, len(results))
+	for file := range results {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var invalid int
+	for _, file := range files {
+		if results[file] == nil {
+			fmt.Printf("%s: valid\n", file)
+			continue
+		}
+
+		invalid++
+		fmt.Printf("%s: invalid\n", file)
+		errs := []error{results[file]}
+		if mErr, ok := results[file].(*multierror.Error); ok {
+			errs = mErr.Errors
+		}
+		for _, err := range errs {
+			fmt.Printf("    * %v\n", err)
+		}
+	}
+
+	if invalid > 0 {
+		fmt.Printf("\n%d of %d files contain invalid policies\n", invalid, len(files))
+		return 1
+	}
+	return 0
+}
+
+// lintPaths validates the policies within all the files identified by the
+// paths, returning the result for each file.
+func lintPaths(paths []string, processor *policy.Processor) (map[string]error, error) {
+	results := make(map[string]error)
+
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fi.IsDir() {
+			results[path] = lintFile(path, processor)
+			continue
+		}
+
+		// Use the same discovery as the file source so exactly the files the
+		// agent would load are validated.
+		files, err := fileHelper.GetFileListFromDir(path, ".hcl", ".json")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			results[file] = lintFile(file, processor)
+		}
+	}
+
+	return results, nil
+}
+
+// lintFile validates the policies within a single file.
+func lintFile(file string, processor *policy.Processor) error {
+	if isNomadJobFile(file) {
+		return lintNomadJobFile(file)
+	}
+	return filePolicy.LintFile(file, processor)
+}
+
+// nomadJobFile is the structure of a JSON Nomad job file.
+type nomadJobFile struct {
+	Job *api.Job
+}
+
+// isNomadJobFile identifies whether the file is a JSON Nomad job rather than
+// a file source scaling policy.
+func isNomadJobFile(file string) bool {
+	if filepath.Ext(file) != ".json" {
+		return false
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	var jobFile nomadJobFile
+	return json.Unmarshal(content, &jobFile) == nil && jobFile.Job != nil
+}
+
+// lintNomadJobFile validates the scaling policies within a JSON Nomad job.
+func lintNomadJobFile(file string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
+
+	var jobFile nomadJobFile
+	if err := json.Unmarshal(content, &jobFile); err != nil {
+		return fmt.Errorf("failed to decode file: %v", err)
+	}
+	return nomadPolicy.ValidateJob(jobFile.Job)
+}
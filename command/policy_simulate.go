@@ -0,0 +1,237 @@
+package command
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	filePolicy "github.com/hashicorp/nomad-autoscaler/policy/file"
+	"github.com/hashicorp/nomad-autoscaler/policyeval"
+	flaghelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/flag"
+)
+
+const (
+	simulateFormatCSV  = "csv"
+	simulateFormatJSON = "json"
+)
+
+type PolicySimulateCommand struct{}
+
+// Help should return long-form help text that includes the command-line
+// usage, a brief few sentences explaining the function of the command,
+// and the complete list of flags the command accepts.
+func (c *PolicySimulateCommand) Help() string {
+	helpText := `
+Usage: nomad-autoscaler policy simulate [options] <path>
+
+  Replays the evaluations of a file source scaling policy over a historical
+  time range and outputs the count timeline that would have resulted. This
+  allows strategy parameters to be tuned against past metrics.
+
+  The policy checks are run using the APM and strategy plugins of the agent
+  configuration, with each query ending at the simulated evaluation time, so
+  the APMs must be able to return historical metrics. The target is never
+  scaled; the simulated count starts at the policy min unless -count is set.
+  The policy cooldown is applied after each simulated scaling action.
+
+Options:
+
+  -config=<path>
+    The path to either a single config file or a directory of config files
+    used to configure the plugins. Can be specified multiple times.
+
+  -policy=<name>
+    The name of the policy to simulate. Required if the file contains more
+    than one policy.
+
+  -template-dir=<path>
+    The path to a directory used to load the policy templates referenced by
+    the policy.
+
+  -from=<time>
+    The RFC3339 time of the first simulated evaluation. Required.
+
+  -to=<time>
+    The RFC3339 time after which no evaluations are simulated. Defaults to
+    the current time.
+
+  -count=<count>
+    The count of the target at the start of the simulation. Defaults to the
+    policy min.
+
+  -format=<format>
+    The output format, either "csv" or "json". Defaults to "csv".
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis should return a one-line, short synopsis of the command.
+// This should be less than 50 characters ideally.
+func (c *PolicySimulateCommand) Synopsis() string {
+	return "Simulates a scaling policy over past metrics"
+}
+
+// Run should run the actual command with the given CLI instance and
+// command-line arguments. It should return the exit status when it is
+// finished.
+func (c *PolicySimulateCommand) Run(args []string) int {
+	flags := flag.NewFlagSet("policy simulate", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Println(c.Help()) }
+
+	var (
+		configPath                  []string
+		name, templateDir, from, to string
+		format                      string
+		count                       int64
+	)
+	flags.Var((*flaghelper.StringFlag)(&configPath), "config", "")
+	flags.StringVar(&name, "policy", "", "")
+	flags.StringVar(&templateDir, "template-dir", "", "")
+	flags.StringVar(&from, "from", "", "")
+	flags.StringVar(&to, "to", "", "")
+	flags.Int64Var(&count, "count", -1, "")
+	flags.StringVar(&format, "format", simulateFormatCSV, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 1 {
+		fmt.Fprintln(os.Stderr, "This command takes one argument: <path>")
+		return 1
+	}
+	if format != simulateFormatCSV && format != simulateFormatJSON {
+		fmt.Fprintf(os.Stderr, "Unsupported format %q, must be %q or %q\n", format, simulateFormatCSV, simulateFormatJSON)
+		return 1
+	}
+
+	fromTime, toTime, err := parseSimulationRange(from, to, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg, err := (&AgentCommand{configPath: configPath, cmdConfig: &config.Agent{}}).loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	defaults := &policy.ConfigDefaults{
+		DefaultCooldown:           cfg.Policy.DefaultCooldown,
+		DefaultEvaluationInterval: cfg.Policy.DefaultEvaluationInterval,
+	}
+	if templateDir != "" {
+		templates, err := filePolicy.LoadTemplates(templateDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load policy templates: %v\n", err)
+			return 1
+		}
+		defaults.Templates = templates
+	}
+
+	p, err := filePolicy.ReadFilePolicy(flags.Arg(0), name, policy.NewProcessor(defaults, nil))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read policy: %v\n", err)
+		return 1
+	}
+	if count < 0 {
+		count = p.Min
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "policy-simulate",
+		Level:  hclog.LevelFromString(cfg.LogLevel),
+		Output: os.Stderr,
+	})
+
+	pm := manager.NewPluginManager(logger, cfg.PluginDir, agent.NewAgent(cfg, nil, logger).PluginsConfig())
+	if err := pm.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load plugins: %v\n", err)
+		return 1
+	}
+	defer pm.KillPlugins()
+
+	if err := pm.SetStrategyAliases(cfg.StrategyAliases); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set strategy aliases: %v\n", err)
+		return 1
+	}
+
+	steps, err := policyeval.NewSimulator(logger, pm).Run(context.Background(), p, fromTime, toTime, count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to simulate policy: %v\n", err)
+		return 1
+	}
+
+	if err := writeSimulationSteps(os.Stdout, format, steps); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write simulation output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseSimulationRange parses the RFC3339 start and end times of the
+// simulation. The end defaults to now if it is empty.
+func parseSimulationRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	if from == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("The -from flag is required")
+	}
+
+	fromTime, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid -from time: %v", err)
+	}
+
+	toTime := now
+	if to != "" {
+		if toTime, err = time.Parse(time.RFC3339, to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid -to time: %v", err)
+		}
+	}
+
+	if !fromTime.Before(toTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("The -from time must be before the -to time")
+	}
+	return fromTime, toTime, nil
+}
+
+// writeSimulationSteps writes the simulation steps to w in the passed format.
+func writeSimulationSteps(w io.Writer, format string, steps []*policyeval.SimulationStep) error {
+	if format == simulateFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(steps)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "count", "direction", "check", "reason", "in_cooldown"}); err != nil {
+		return err
+	}
+	for _, s := range steps {
+		record := []string{
+			s.Time.Format(time.RFC3339),
+			strconv.FormatInt(s.Count, 10),
+			s.Direction,
+			s.Check,
+			s.Reason,
+			strconv.FormatBool(s.InCooldown),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
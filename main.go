@@ -18,6 +18,12 @@ func main() {
 		"agent": func() (cli.Command, error) {
 			return &command.AgentCommand{}, nil
 		},
+		"policy lint": func() (cli.Command, error) {
+			return &command.PolicyLintCommand{}, nil
+		},
+		"policy simulate": func() (cli.Command, error) {
+			return &command.PolicySimulateCommand{}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &command.VersionCommand{Version: versionString}, nil
 		},
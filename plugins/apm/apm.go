@@ -22,3 +22,56 @@ type APM interface {
 	// to gather the metrics desired by the feature.
 	QueryMultiple(query string, timeRange sdk.TimeRange) ([]sdk.TimestampedMetrics, error)
 }
+
+// ParamsQuerier is an optional interface which APM plugins can implement to
+// receive the structured query parameters of a check. Plugins which do not
+// implement it are queried using APM.Query and the parameters are ignored.
+type ParamsQuerier interface {
+
+	// QueryWithParams is used to ask the remote APM for timestamped metrics
+	// based on the passed request, applying the request parameters to the
+	// query.
+	QueryWithParams(req sdk.QueryRequest) (sdk.TimestampedMetrics, error)
+}
+
+// ConfigOverrider is an optional interface which APM plugins can implement to
+// allow checks to override the plugin config for their queries, such as the
+// address of the APM or the credentials used to query it. The overrides are
+// passed within the Config of the sdk.QueryRequest, so plugins implementing
+// it must also implement ParamsQuerier. Checks which override the config of
+// plugins which do not implement it are rejected when policies are loaded.
+//
+// Query requests are not extended over the external plugin gRPC interface,
+// so only internal APM plugins can implement ConfigOverrider.
+type ConfigOverrider interface {
+
+	// ValidateConfigOverride returns an error describing why the passed
+	// overrides are not allowed, such as keys which can't be overridden.
+	ValidateConfigOverride(config map[string]string) error
+}
+
+// LabeledQuerier is an optional interface which APM plugins can implement to
+// support checks with PerInstance set. Rather than a single series, the query
+// result is a series per instance keyed by the label identifying the
+// instance, which allows strategies to scale based on individual instances.
+// Plugins which do not implement it can't be used by per-instance checks.
+//
+// Labeled results are not supported over the external plugin gRPC interface,
+// so only internal APM plugins can implement LabeledQuerier.
+type LabeledQuerier interface {
+
+	// QueryLabeled is used to ask the remote APM for timestamped metrics
+	// based on the passed query and time range, keyed by instance label.
+	QueryLabeled(query string, timeRange sdk.TimeRange) (sdk.LabeledMetrics, error)
+}
+
+// QueryValidator is an optional interface which APM plugins can implement to
+// validate the syntax of check queries when policies are loaded, rather than
+// failing once the policy is evaluated. The queries are validated after the
+// policy is canonicalized.
+type QueryValidator interface {
+
+	// ValidateQuery returns an error describing why the passed query is not
+	// valid for the APM.
+	ValidateQuery(query string) error
+}
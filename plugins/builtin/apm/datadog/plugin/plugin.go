@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -30,6 +31,10 @@ const (
 	datadogAuthAPPKey = "appKeyAuth"
 
 	ratelimitResetHdr = "X-Ratelimit-Reset"
+
+	// defaultRollup is the rollup method used when a query only sets the
+	// window parameter, matching the Datadog default.
+	defaultRollup = "avg"
 )
 
 var (
@@ -48,6 +53,9 @@ var (
 	}
 )
 
+// Ensure APMPlugin supports structured query parameters.
+var _ apm.ParamsQuerier = (*APMPlugin)(nil)
+
 type APMPlugin struct {
 	client    *datadog.APIClient
 	clientCtx context.Context
@@ -107,7 +115,76 @@ func (a *APMPlugin) Query(q string, r sdk.TimeRange) (sdk.TimestampedMetrics, er
 	if err != nil {
 		return nil, err
 	}
+	return singleMetricStream(m)
+}
+
+// QueryWithParams applies the aggregator, rollup and window parameters of the
+// request to the query using the Datadog query syntax before querying it.
+func (a *APMPlugin) QueryWithParams(req sdk.QueryRequest) (sdk.TimestampedMetrics, error) {
+	q, err := applyQueryParams(req.Query, req.Params)
+	if err != nil {
+		return nil, err
+	}
+	return a.Query(q, req.TimeRange)
+}
+
+// applyQueryParams builds the query which applies the passed parameters to
+// the base query, for example "sum:system.cpu.user{*}.rollup(avg, 60)". The
+// base query must not already define the parameters it is given.
+func applyQueryParams(q string, params *sdk.QueryParams) (string, error) {
+	if params == nil {
+		return q, nil
+	}
+
+	if params.Aggregator != "" {
+		if !isDatadogAggregator(params.Aggregator) {
+			return "", fmt.Errorf("unsupported aggregator %q", params.Aggregator)
+		}
+		if prefix := strings.SplitN(q, ":", 2)[0]; isDatadogAggregator(prefix) {
+			return "", fmt.Errorf("query already defines the %q aggregator", prefix)
+		}
+		q = params.Aggregator + ":" + q
+	}
+
+	if params.Rollup != "" || params.Window > 0 {
+		if strings.Contains(q, ".rollup(") {
+			return "", fmt.Errorf("query already defines a rollup")
+		}
+
+		rollup := params.Rollup
+		switch rollup {
+		case "":
+			rollup = defaultRollup
+		case "avg", "max", "min", "sum", "count":
+		default:
+			return "", fmt.Errorf("unsupported rollup %q", params.Rollup)
+		}
+
+		if params.Window > 0 {
+			if params.Window < time.Second {
+				return "", fmt.Errorf("window must be at least 1s, found %v", params.Window)
+			}
+			q = fmt.Sprintf("%s.rollup(%s, %d)", q, rollup, int64(params.Window.Seconds()))
+		} else {
+			q = fmt.Sprintf("%s.rollup(%s)", q, rollup)
+		}
+	}
+
+	return q, nil
+}
+
+// isDatadogAggregator returns whether s is a Datadog space aggregator.
+func isDatadogAggregator(s string) bool {
+	switch s {
+	case "avg", "max", "min", "sum":
+		return true
+	}
+	return false
+}
 
+// singleMetricStream returns the only metric stream in m. It errors if the
+// query returned more than one stream.
+func singleMetricStream(m []sdk.TimestampedMetrics) (sdk.TimestampedMetrics, error) {
 	switch len(m) {
 	case 0:
 		return sdk.TimestampedMetrics{}, nil
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -130,3 +132,89 @@ func TestAPMPlugin_SetConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyQueryParams(t *testing.T) {
+	testCases := []struct {
+		inputQuery    string
+		inputParams   *sdk.QueryParams
+		expectedQuery string
+		expectedError error
+		name          string
+	}{
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   nil,
+			expectedQuery: "system.cpu.user{*}",
+			expectedError: nil,
+			name:          "no params",
+		},
+		{
+			inputQuery: "system.cpu.user{host:a}",
+			inputParams: &sdk.QueryParams{
+				Window:     time.Minute,
+				Rollup:     "max",
+				Aggregator: "sum",
+			},
+			expectedQuery: "sum:system.cpu.user{host:a}.rollup(max, 60)",
+			expectedError: nil,
+			name:          "all params",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Window: 5 * time.Minute},
+			expectedQuery: "system.cpu.user{*}.rollup(avg, 300)",
+			expectedError: nil,
+			name:          "window uses default rollup",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Rollup: "count"},
+			expectedQuery: "system.cpu.user{*}.rollup(count)",
+			expectedError: nil,
+			name:          "rollup without window",
+		},
+		{
+			inputQuery:    "avg:system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Aggregator: "sum"},
+			expectedQuery: "",
+			expectedError: errors.New(`query already defines the "avg" aggregator`),
+			name:          "aggregator already in query",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}.rollup(max)",
+			inputParams:   &sdk.QueryParams{Rollup: "avg"},
+			expectedQuery: "",
+			expectedError: errors.New("query already defines a rollup"),
+			name:          "rollup already in query",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Aggregator: "p95"},
+			expectedQuery: "",
+			expectedError: errors.New(`unsupported aggregator "p95"`),
+			name:          "unsupported aggregator",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Rollup: "median"},
+			expectedQuery: "",
+			expectedError: errors.New(`unsupported rollup "median"`),
+			name:          "unsupported rollup",
+		},
+		{
+			inputQuery:    "system.cpu.user{*}",
+			inputParams:   &sdk.QueryParams{Window: 500 * time.Millisecond},
+			expectedQuery: "",
+			expectedError: errors.New("window must be at least 1s, found 500ms"),
+			name:          "window too small",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualQuery, actualError := applyQueryParams(tc.inputQuery, tc.inputParams)
+			assert.Equal(t, tc.expectedQuery, actualQuery, tc.name)
+			assert.Equal(t, tc.expectedError, actualError, tc.name)
+		})
+	}
+}
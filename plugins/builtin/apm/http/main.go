@@ -0,0 +1,16 @@
+package main
+
+import (
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	httpAPM "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/http/plugin"
+)
+
+func main() {
+	plugins.Serve(factory)
+}
+
+// factory returns a new instance of the HTTP APM plugin.
+func factory(log hclog.Logger) interface{} {
+	return httpAPM.NewHTTPPlugin(log)
+}
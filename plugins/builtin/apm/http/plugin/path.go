@@ -0,0 +1,111 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathArrayLength is the path component which returns the length of an array
+// rather than one of its elements.
+const pathArrayLength = "#"
+
+// parsePath splits the path of a metric into its components. Components are
+// separated by dots, and a dot within a key can be escaped using a backslash.
+func parsePath(path string) ([]string, error) {
+	var (
+		components []string
+		current    strings.Builder
+		escaped    bool
+	)
+
+	for _, r := range path {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			components = append(components, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("path %q ends with an escape character", path)
+	}
+	components = append(components, current.String())
+
+	for _, c := range components {
+		if c == "" {
+			return nil, fmt.Errorf("path %q contains an empty component", path)
+		}
+	}
+	return components, nil
+}
+
+// extractValue returns the number found at the path within the JSON body. The
+// path uses a subset of the gjson syntax:
+//
+//	queue.depth   the value of key depth within the object queue.
+//	workers.0.cpu the value of key cpu within the first element of the array
+//	              workers.
+//	workers.#     the number of elements within the array workers.
+//
+// The value must be a number, or a string containing a number.
+func extractValue(body []byte, path string) (float64, error) {
+	components, err := parsePath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return 0, fmt.Errorf("failed to decode JSON: %v", err)
+	}
+
+	for i, c := range components {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[c]
+			if !ok {
+				return 0, fmt.Errorf("key %q not found", strings.Join(components[:i+1], "."))
+			}
+			value = child
+
+		case []interface{}:
+			if c == pathArrayLength {
+				value = json.Number(strconv.Itoa(len(v)))
+				continue
+			}
+			idx, err := strconv.Atoi(c)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return 0, fmt.Errorf("index %q out of range for array of length %d at %q",
+					c, len(v), strings.Join(components[:i], "."))
+			}
+			value = v[idx]
+
+		default:
+			return 0, fmt.Errorf("can't read %q from value of type %T", c, value)
+		}
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not a number", v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("value of type %T is not a number", value)
+	}
+}
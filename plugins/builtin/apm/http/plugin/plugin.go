@@ -0,0 +1,333 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/apm"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "http-apm"
+
+	// configKeyAddress is the accepted configuration key which holds the base
+	// URL used to resolve queries with a relative URL.
+	configKeyAddress = "address"
+
+	// configKeyTimeout is the accepted configuration key which holds the
+	// timeout of each request, such as "5s".
+	configKeyTimeout = "timeout"
+
+	// configKeyHeaderPrefix is the prefix of the accepted configuration keys
+	// which hold the headers sent with requests, such as
+	// "header.Authorization". This allows requests to be authenticated. The
+	// headers are only sent to the scheme and host of the configured address,
+	// so they can't be sent elsewhere by queries using an absolute URL.
+	configKeyHeaderPrefix = "header."
+
+	// defaultTimeout is the timeout of each request if the operator has not
+	// configured one.
+	defaultTimeout = 10 * time.Second
+
+	// maxErrorBodySize is the maximum number of bytes of the body of an
+	// unsuccessful response included within the returned error.
+	maxErrorBodySize = 512
+
+	// maxBodySize is the maximum number of bytes of the body of a successful
+	// response which are read.
+	maxBodySize = 10 * 1024 * 1024
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeAPM,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewHTTPPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeAPM,
+	}
+)
+
+// Ensure APMPlugin validates queries when policies are loaded, and allows
+// checks to override its config.
+var (
+	_ apm.QueryValidator  = (*APMPlugin)(nil)
+	_ apm.ParamsQuerier   = (*APMPlugin)(nil)
+	_ apm.ConfigOverrider = (*APMPlugin)(nil)
+)
+
+// APMPlugin is an APM which reads a metric from an arbitrary HTTP endpoint
+// returning JSON. It allows scaling on metrics which are not stored within a
+// supported metrics backend, without writing a custom plugin.
+//
+// The query of a check is the URL to request, with the path of the metric
+// within the response as the URL fragment:
+//
+//	https://queue.example.com/stats#queues.jobs.depth
+//
+// The fragment is never sent to the server. See extractValue for the syntax
+// of the path. Each query returns a single value, timestamped with the time
+// the response was received.
+//
+// Checks can override any of the plugin config using their source config, so
+// one plugin can query endpoints requiring different credentials.
+type APMPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+
+	// requests is the parsed plugin config used to send requests.
+	requests *requestConfig
+}
+
+// requestConfig is the parsed config used to send requests.
+type requestConfig struct {
+	client  *http.Client
+	address *url.URL
+	headers http.Header
+}
+
+func NewHTTPPlugin(log hclog.Logger) apm.APM {
+	return &APMPlugin{
+		logger:   log,
+		requests: &requestConfig{client: &http.Client{Timeout: defaultTimeout}},
+	}
+}
+
+func (a *APMPlugin) SetConfig(config map[string]string) error {
+	requests, err := parseRequestConfig(config)
+	if err != nil {
+		return err
+	}
+
+	a.config = config
+	a.requests = requests
+	return nil
+}
+
+// parseRequestConfig parses the plugin config used to send requests.
+func parseRequestConfig(config map[string]string) (*requestConfig, error) {
+	rc := &requestConfig{headers: make(http.Header)}
+
+	if addr := config[configKeyAddress]; addr != "" {
+		u, err := url.Parse(addr)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("%q config value must be an absolute URL, found %q", configKeyAddress, addr)
+		}
+		rc.address = u
+	}
+
+	timeout := defaultTimeout
+	if t := config[configKeyTimeout]; t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%q config value must be a positive duration, found %q", configKeyTimeout, t)
+		}
+		timeout = d
+	}
+	rc.client = &http.Client{Timeout: timeout}
+
+	for k, v := range config {
+		if name := strings.TrimPrefix(k, configKeyHeaderPrefix); name != k && name != "" {
+			rc.headers.Set(name, v)
+		}
+	}
+	return rc, nil
+}
+
+// ValidateConfigOverride satisfies the ValidateConfigOverride function on the
+// apm.ConfigOverrider interface. Checks can override the address, timeout
+// and headers of the plugin config.
+func (a *APMPlugin) ValidateConfigOverride(config map[string]string) error {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k != configKeyAddress && k != configKeyTimeout && !strings.HasPrefix(k, configKeyHeaderPrefix) {
+			return fmt.Errorf("%q config value can't be overridden", k)
+		}
+	}
+
+	_, err := a.overrideRequestConfig(config)
+	return err
+}
+
+// overrideRequestConfig returns the config used to send requests with the
+// passed values overriding the plugin config. The configured headers hold the
+// credentials of the configured address, so they are not inherited when the
+// address is overridden.
+func (a *APMPlugin) overrideRequestConfig(override map[string]string) (*requestConfig, error) {
+	if len(override) == 0 {
+		return a.requests, nil
+	}
+	_, addressOverridden := override[configKeyAddress]
+
+	config := make(map[string]string, len(a.config)+len(override))
+	for k, v := range a.config {
+		if addressOverridden && strings.HasPrefix(k, configKeyHeaderPrefix) {
+			continue
+		}
+		config[k] = v
+	}
+	for k, v := range override {
+		config[k] = v
+	}
+	return parseRequestConfig(config)
+}
+
+func (a *APMPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+func (a *APMPlugin) Query(q string, r sdk.TimeRange) (sdk.TimestampedMetrics, error) {
+	return a.QueryWithParams(sdk.QueryRequest{Query: q, TimeRange: r})
+}
+
+// QueryWithParams satisfies the QueryWithParams function on the
+// apm.ParamsQuerier interface. The endpoint returns a single value, so only
+// the config overrides of the request are used.
+func (a *APMPlugin) QueryWithParams(req sdk.QueryRequest) (sdk.TimestampedMetrics, error) {
+	rc, err := a.overrideRequestConfig(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	u, path, err := rc.parseQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("querying HTTP endpoint", "url", u, "path", path)
+
+	if !rc.sendsHeaders(u) && len(rc.headers) > 0 {
+		a.logger.Debug("URL does not match the configured address, not sending headers", "url", u)
+	}
+
+	body, err := rc.get(u)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := extractValue(body, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %q from response of %s: %v", path, u, err)
+	}
+	return sdk.TimestampedMetrics{{Timestamp: time.Now(), Value: value}}, nil
+}
+
+func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
+	m, err := a.Query(q, r)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.TimestampedMetrics{m}, nil
+}
+
+// ValidateQuery satisfies the ValidateQuery function on the
+// apm.QueryValidator interface. It parses the query in the same manner as
+// Query, without sending a request.
+func (a *APMPlugin) ValidateQuery(q string) error {
+	_, path, err := a.requests.parseQuery(q)
+	if err != nil {
+		return err
+	}
+	_, err = parsePath(path)
+	return err
+}
+
+// parseQuery splits the query into the URL to request and the path of the
+// metric within the response. Relative URLs are resolved against the
+// configured address.
+func (rc *requestConfig) parseQuery(q string) (string, string, error) {
+	i := strings.Index(q, "#")
+	if i < 0 || i == len(q)-1 {
+		return "", "", fmt.Errorf("query must be a URL with the path of the metric as its fragment, such as https://example.com/stats#queue.depth")
+	}
+	rawURL, path := q[:i], q[i+1:]
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL %q: %v", rawURL, err)
+	}
+	if !u.IsAbs() {
+		if rc.address == nil {
+			return "", "", fmt.Errorf("URL %q is relative but the %q config value is not set", rawURL, configKeyAddress)
+		}
+		u = rc.address.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("URL %q must use http or https", rawURL)
+	}
+	return u.String(), path, nil
+}
+
+// sendsHeaders returns whether the configured headers are sent with requests
+// to the URL. They are only sent when the URL has the same scheme and host as
+// the configured address, as the query of a check may be any URL.
+func (rc *requestConfig) sendsHeaders(u string) bool {
+	if rc.address == nil {
+		return false
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Scheme, rc.address.Scheme) && strings.EqualFold(parsed.Host, rc.address.Host)
+}
+
+// get requests the URL and returns the body of the response. The request is
+// abandoned once the configured timeout is reached, and responses with a
+// status code other than 200 are returned as errors.
+func (rc *requestConfig) get(u string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if rc.sendsHeaders(u) {
+		for k, v := range rc.headers {
+			req.Header[k] = v
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, fmt.Errorf("failed to query %s: unexpected status code %d: %s",
+			u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// Read one byte past the limit, so larger responses can be detected.
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %v", u, err)
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("response of %s is larger than %d bytes", u, maxBodySize)
+	}
+	return body, nil
+}
@@ -0,0 +1,388 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_extractValue(t *testing.T) {
+	body := []byte(`{
+  "queue": {"depth": 42, "lag": "1.5"},
+  "workers": [{"cpu": 10}, {"cpu": 20.5}],
+  "name": "jobs",
+  "key.with.dots": 7
+}`)
+
+	testCases := []struct {
+		inputPath      string
+		expectedOutput float64
+		expectedError  string
+		name           string
+	}{
+		{
+			inputPath:      "queue.depth",
+			expectedOutput: 42,
+			name:           "nested number",
+		},
+		{
+			inputPath:      "queue.lag",
+			expectedOutput: 1.5,
+			name:           "numeric string",
+		},
+		{
+			inputPath:      "workers.1.cpu",
+			expectedOutput: 20.5,
+			name:           "array index",
+		},
+		{
+			inputPath:      "workers.#",
+			expectedOutput: 2,
+			name:           "array length",
+		},
+		{
+			inputPath:      `key\.with\.dots`,
+			expectedOutput: 7,
+			name:           "escaped dots",
+		},
+		{
+			inputPath:     "queue.size",
+			expectedError: `key "queue.size" not found`,
+			name:          "missing key",
+		},
+		{
+			inputPath:     "workers.2.cpu",
+			expectedError: `index "2" out of range for array of length 2 at "workers"`,
+			name:          "index out of range",
+		},
+		{
+			inputPath:     "name",
+			expectedError: `value "jobs" is not a number`,
+			name:          "non-numeric string",
+		},
+		{
+			inputPath:     "queue",
+			expectedError: "value of type map[string]interface {} is not a number",
+			name:          "object value",
+		},
+		{
+			inputPath:     "queue.depth.value",
+			expectedError: `can't read "value" from value of type json.Number`,
+			name:          "path through number",
+		},
+		{
+			inputPath:     "queue..depth",
+			expectedError: `path "queue..depth" contains an empty component`,
+			name:          "empty component",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := extractValue(body, tc.inputPath)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_Query(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stats":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = fmt.Fprint(w, "missing token")
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"queue": {"depth": 42}}`)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = fmt.Fprint(w, `{"queue": {"depth": 42}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputQuery     string
+		expectedOutput float64
+		expectedError  string
+		name           string
+	}{
+		{
+			inputConfig:    map[string]string{"address": ts.URL, "header.Authorization": "Bearer secret"},
+			inputQuery:     ts.URL + "/stats#queue.depth",
+			expectedOutput: 42,
+			name:           "absolute URL",
+		},
+		{
+			inputConfig:    map[string]string{"address": ts.URL, "header.Authorization": "Bearer secret"},
+			inputQuery:     "/stats#queue.depth",
+			expectedOutput: 42,
+			name:           "relative URL",
+		},
+		{
+			inputConfig:   map[string]string{},
+			inputQuery:    ts.URL + "/stats#queue.depth",
+			expectedError: fmt.Sprintf("failed to query %s/stats: unexpected status code 401: missing token", ts.URL),
+			name:          "unsuccessful response",
+		},
+		{
+			inputConfig:   map[string]string{"timeout": "50ms"},
+			inputQuery:    ts.URL + "/slow#queue.depth",
+			expectedError: "Client.Timeout exceeded",
+			name:          "timeout",
+		},
+		{
+			inputConfig:   map[string]string{"header.Authorization": "Bearer secret"},
+			inputQuery:    ts.URL + "/stats#queue.depth",
+			expectedError: fmt.Sprintf("failed to query %s/stats: unexpected status code 401: missing token", ts.URL),
+			name:          "headers not sent without address",
+		},
+		{
+			inputConfig:   map[string]string{"address": ts.URL, "header.Authorization": "Bearer secret"},
+			inputQuery:    ts.URL + "/stats#queue.size",
+			expectedError: `key "queue.size" not found`,
+			name:          "missing key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewHTTPPlugin(hclog.NewNullLogger())
+			require.NoError(t, p.SetConfig(tc.inputConfig))
+
+			actualOutput, err := p.Query(tc.inputQuery, sdk.TimeRange{})
+			if tc.expectedError != "" {
+				require.Error(t, err, tc.name)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.name)
+				return
+			}
+			require.NoError(t, err, tc.name)
+			require.Len(t, actualOutput, 1, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput[0].Value, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_Query_foreignHost(t *testing.T) {
+	var received http.Header
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = fmt.Fprint(w, `{"queue": {"depth": 42}}`)
+	}))
+	defer foreign.Close()
+
+	p := NewHTTPPlugin(hclog.NewNullLogger())
+	require.NoError(t, p.SetConfig(map[string]string{
+		"address":              "https://apm.example.com",
+		"header.Authorization": "Bearer secret",
+	}))
+
+	// The query of a check may be any URL, so the configured credentials are
+	// not sent to hosts other than the configured address.
+	m, err := p.Query(foreign.URL+"/stats#queue.depth", sdk.TimeRange{})
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	assert.Empty(t, received.Get("Authorization"))
+}
+
+func TestAPMPlugin_Query_largeResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"queue": {"depth": 42}, "padding": "%s"}`, strings.Repeat("a", maxBodySize))
+	}))
+	defer ts.Close()
+
+	p := NewHTTPPlugin(hclog.NewNullLogger())
+	require.NoError(t, p.SetConfig(map[string]string{}))
+
+	_, err := p.Query(ts.URL+"/stats#queue.depth", sdk.TimeRange{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is larger than")
+}
+
+func TestAPMPlugin_SetConfig(t *testing.T) {
+	testCases := []struct {
+		inputConfig   map[string]string
+		expectedError string
+		name          string
+	}{
+		{
+			inputConfig: map[string]string{"address": "https://example.com", "timeout": "5s"},
+			name:        "valid config",
+		},
+		{
+			inputConfig:   map[string]string{"address": "example.com"},
+			expectedError: `"address" config value must be an absolute URL, found "example.com"`,
+			name:          "relative address",
+		},
+		{
+			inputConfig:   map[string]string{"timeout": "-1s"},
+			expectedError: `"timeout" config value must be a positive duration, found "-1s"`,
+			name:          "negative timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewHTTPPlugin(hclog.NewNullLogger()).SetConfig(tc.inputConfig)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_ValidateQuery(t *testing.T) {
+	testCases := []struct {
+		inputConfig map[string]string
+		inputQuery  string
+		expectError bool
+		name        string
+	}{
+		{
+			inputQuery:  "https://example.com/stats#queue.depth",
+			expectError: false,
+			name:        "valid query",
+		},
+		{
+			inputConfig: map[string]string{"address": "https://example.com"},
+			inputQuery:  "/stats#queue.depth",
+			expectError: false,
+			name:        "valid relative query",
+		},
+		{
+			inputQuery:  "https://example.com/stats",
+			expectError: true,
+			name:        "missing path",
+		},
+		{
+			inputQuery:  "/stats#queue.depth",
+			expectError: true,
+			name:        "relative query without address",
+		},
+		{
+			inputQuery:  "ftp://example.com/stats#queue.depth",
+			expectError: true,
+			name:        "unsupported scheme",
+		},
+		{
+			inputQuery:  `https://example.com/stats#queue.depth\`,
+			expectError: true,
+			name:        "invalid path",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewHTTPPlugin(hclog.NewNullLogger()).(*APMPlugin)
+			require.NoError(t, p.SetConfig(tc.inputConfig))
+			assert.Equal(t, tc.expectError, p.ValidateQuery(tc.inputQuery) != nil, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_QueryWithParams_config(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"queue": {"depth": 42}}`)
+	}))
+	defer ts.Close()
+
+	p := NewHTTPPlugin(hclog.NewNullLogger()).(*APMPlugin)
+	require.NoError(t, p.SetConfig(map[string]string{"address": "https://example.com"}))
+
+	req := sdk.QueryRequest{
+		Query:  "/stats#queue.depth",
+		Config: map[string]string{"address": ts.URL, "header.Authorization": "Bearer secret"},
+	}
+
+	m, err := p.QueryWithParams(req)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	assert.Equal(t, float64(42), m[0].Value)
+}
+
+func TestAPMPlugin_QueryWithParams_inheritedHeaders(t *testing.T) {
+	var received http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = fmt.Fprint(w, `{"queue": {"depth": 42}}`)
+	}))
+	defer ts.Close()
+
+	p := NewHTTPPlugin(hclog.NewNullLogger()).(*APMPlugin)
+	require.NoError(t, p.SetConfig(map[string]string{
+		"address":              "https://apm.example.com",
+		"header.Authorization": "Bearer secret",
+	}))
+
+	// The configured credentials are not sent to an address set by a check.
+	req := sdk.QueryRequest{Query: "/stats#queue.depth", Config: map[string]string{"address": ts.URL}}
+	_, err := p.QueryWithParams(req)
+	require.NoError(t, err)
+	assert.Empty(t, received.Get("Authorization"))
+
+	// Checks can still set their own headers along with the address.
+	req.Config["header.Authorization"] = "Bearer check"
+	_, err = p.QueryWithParams(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer check", received.Get("Authorization"))
+}
+
+func TestAPMPlugin_ValidateConfigOverride(t *testing.T) {
+	testCases := []struct {
+		inputConfig   map[string]string
+		expectedError string
+		name          string
+	}{
+		{
+			inputConfig: map[string]string{"address": "https://example.com", "timeout": "5s", "header.Authorization": "Bearer secret"},
+			name:        "valid override",
+		},
+		{
+			inputConfig:   map[string]string{"timeout": "-1s"},
+			expectedError: `"timeout" config value must be a positive duration, found "-1s"`,
+			name:          "invalid value",
+		},
+		{
+			inputConfig:   map[string]string{"insecure": "true"},
+			expectedError: `"insecure" config value can't be overridden`,
+			name:          "unsupported key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewHTTPPlugin(hclog.NewNullLogger()).(*APMPlugin)
+			require.NoError(t, p.SetConfig(map[string]string{}))
+
+			err := p.ValidateConfigOverride(tc.inputConfig)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	mock "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/mock/plugin"
+)
+
+func main() {
+	plugins.Serve(factory)
+}
+
+// factory returns a new instance of the mock APM plugin.
+func factory(log hclog.Logger) interface{} {
+	return mock.NewMockPlugin(log)
+}
@@ -0,0 +1,200 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/apm"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "mock-apm"
+
+	// The kinds of script supported within a query. A query takes the form
+	// of <kind>:<args>, for example "sequence:10,20,5".
+	scriptKindConstant = "constant"
+	scriptKindSequence = "sequence"
+	scriptKindRamp     = "ramp"
+	scriptKindSine     = "sine"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeAPM,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewMockPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeAPM,
+	}
+)
+
+// APMPlugin is an APM which returns scripted values rather than querying a
+// metrics backend. It allows the full autoscaling loop to be exercised
+// deterministically, for example in tests and demos.
+//
+// The query of a check describes the script used to generate values:
+//
+//	constant:<value>                   always returns value.
+//	sequence:<v1>,<v2>,...             returns the next value on each query,
+//	                                   holding the last value once exhausted.
+//	ramp:<start>,<step>,<end>          starts at start and moves by step on
+//	                                   each query, holding at end.
+//	sine:<offset>,<amplitude>,<period> returns a sine wave over time, where
+//	                                   period is a duration such as "10m".
+//
+// The query can also be the name of a key within the plugin config, in which
+// case the value of the key is used as the script. This allows scripts to be
+// shared across policies.
+type APMPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+
+	// startTime is used as the origin of time based scripts.
+	startTime time.Time
+
+	// lock is used to synchronize access to the fields below.
+	lock sync.Mutex
+
+	// queryCounts tracks the number of times each script has been queried so
+	// sequences and ramps can progress.
+	queryCounts map[string]int
+}
+
+func NewMockPlugin(log hclog.Logger) apm.APM {
+	return &APMPlugin{
+		logger:      log,
+		startTime:   time.Now(),
+		queryCounts: make(map[string]int),
+	}
+}
+
+func (a *APMPlugin) SetConfig(config map[string]string) error {
+	a.config = config
+	return nil
+}
+
+func (a *APMPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+func (a *APMPlugin) Query(q string, r sdk.TimeRange) (sdk.TimestampedMetrics, error) {
+	script := q
+	if s, ok := a.config[q]; ok {
+		script = s
+	}
+
+	a.lock.Lock()
+	n := a.queryCounts[script]
+	a.queryCounts[script]++
+	a.lock.Unlock()
+
+	value, err := evaluateScript(script, n, r.To.Sub(a.startTime))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate mock query %q: %v", q, err)
+	}
+
+	a.logger.Debug("returning mock metric", "query", q, "value", value)
+	return sdk.TimestampedMetrics{{Timestamp: r.To, Value: value}}, nil
+}
+
+func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
+	m, err := a.Query(q, r)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.TimestampedMetrics{m}, nil
+}
+
+// evaluateScript returns the value of the script for the nth query, which was
+// performed elapsed time after the plugin started.
+func evaluateScript(script string, n int, elapsed time.Duration) (float64, error) {
+	parts := strings.SplitN(script, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("expected format <kind>:<args>")
+	}
+	kind, args := parts[0], strings.Split(parts[1], ",")
+
+	switch kind {
+	case scriptKindConstant:
+		values, err := parseFloats(args, 1)
+		if err != nil {
+			return 0, err
+		}
+		return values[0], nil
+
+	case scriptKindSequence:
+		values, err := parseFloats(args, -1)
+		if err != nil {
+			return 0, err
+		}
+		if n >= len(values) {
+			n = len(values) - 1
+		}
+		return values[n], nil
+
+	case scriptKindRamp:
+		values, err := parseFloats(args, 3)
+		if err != nil {
+			return 0, err
+		}
+		start, step, end := values[0], values[1], values[2]
+
+		value := start + step*float64(n)
+		if (step > 0 && value > end) || (step < 0 && value < end) {
+			value = end
+		}
+		return value, nil
+
+	case scriptKindSine:
+		if len(args) != 3 {
+			return 0, fmt.Errorf("%s requires 3 arguments, found %d", kind, len(args))
+		}
+		values, err := parseFloats(args[:2], 2)
+		if err != nil {
+			return 0, err
+		}
+		period, err := time.ParseDuration(strings.TrimSpace(args[2]))
+		if err != nil || period <= 0 {
+			return 0, fmt.Errorf("invalid period %q", args[2])
+		}
+
+		offset, amplitude := values[0], values[1]
+		return offset + amplitude*math.Sin(2*math.Pi*elapsed.Seconds()/period.Seconds()), nil
+
+	default:
+		return 0, fmt.Errorf("unsupported kind %q", kind)
+	}
+}
+
+// parseFloats parses each argument as a float. If expected is not negative,
+// the number of arguments must match it.
+func parseFloats(args []string, expected int) ([]float64, error) {
+	if expected >= 0 && len(args) != expected {
+		return nil, fmt.Errorf("expected %d arguments, found %d", expected, len(args))
+	}
+
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", arg)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
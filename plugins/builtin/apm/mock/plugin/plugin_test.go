@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	targetValue "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/target-value/plugin"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_evaluateScript(t *testing.T) {
+	testCases := []struct {
+		inputScript    string
+		inputN         int
+		inputElapsed   time.Duration
+		expectedOutput float64
+		expectedError  error
+		name           string
+	}{
+		{
+			inputScript:    "constant:42",
+			expectedOutput: 42,
+			name:           "constant",
+		},
+		{
+			inputScript:    "sequence:10, 20, 5",
+			inputN:         1,
+			expectedOutput: 20,
+			name:           "sequence",
+		},
+		{
+			inputScript:    "sequence:10,20,5",
+			inputN:         7,
+			expectedOutput: 5,
+			name:           "sequence holds last value",
+		},
+		{
+			inputScript:    "ramp:10,5,30",
+			inputN:         2,
+			expectedOutput: 20,
+			name:           "ramp up",
+		},
+		{
+			inputScript:    "ramp:10,5,30",
+			inputN:         10,
+			expectedOutput: 30,
+			name:           "ramp holds at end",
+		},
+		{
+			inputScript:    "ramp:30,-10,0",
+			inputN:         5,
+			expectedOutput: 0,
+			name:           "ramp down holds at end",
+		},
+		{
+			inputScript:    "sine:50,10,4m",
+			inputElapsed:   time.Minute,
+			expectedOutput: 60,
+			name:           "sine peak",
+		},
+		{
+			inputScript:   "constant",
+			expectedError: errors.New("expected format <kind>:<args>"),
+			name:          "missing args",
+		},
+		{
+			inputScript:   "ramp:1,2",
+			expectedError: errors.New("expected 3 arguments, found 2"),
+			name:          "wrong number of args",
+		},
+		{
+			inputScript:   "sequence:1,two",
+			expectedError: errors.New(`invalid value "two"`),
+			name:          "invalid value",
+		},
+		{
+			inputScript:   "sine:50,10,never",
+			expectedError: errors.New(`invalid period "never"`),
+			name:          "invalid period",
+		},
+		{
+			inputScript:   "random:1",
+			expectedError: errors.New(`unsupported kind "random"`),
+			name:          "unsupported kind",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, actualError := evaluateScript(tc.inputScript, tc.inputN, tc.inputElapsed)
+			assert.InDelta(t, tc.expectedOutput, actualOutput, 0.000001, tc.name)
+			assert.Equal(t, tc.expectedError, actualError, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_Query_configScript(t *testing.T) {
+	a := NewMockPlugin(hclog.NewNullLogger())
+	assert.NoError(t, a.SetConfig(map[string]string{"spike": "sequence:1,100"}))
+
+	to := time.Now()
+	r := sdk.TimeRange{From: to.Add(-time.Minute), To: to}
+
+	m, err := a.Query("spike", r)
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.TimestampedMetrics{{Timestamp: to, Value: 1}}, m)
+
+	m, err = a.Query("spike", r)
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.TimestampedMetrics{{Timestamp: to, Value: 100}}, m)
+}
+
+// TestAPMPlugin_scalingLoop runs the mock APM with the target-value strategy
+// to show a policy scaling up and then back down as the mock value changes.
+func TestAPMPlugin_scalingLoop(t *testing.T) {
+	a := NewMockPlugin(hclog.NewNullLogger())
+	assert.NoError(t, a.SetConfig(map[string]string{}))
+
+	s := targetValue.NewTargetValuePlugin(hclog.NewNullLogger())
+	assert.NoError(t, s.SetConfig(map[string]string{}))
+
+	check := &sdk.ScalingPolicyCheck{
+		Query: "sequence:10,20,10,5",
+		Strategy: &sdk.ScalingPolicyStrategy{
+			Config: map[string]string{"target": "10"},
+		},
+	}
+
+	expected := []struct {
+		direction sdk.ScaleDirection
+		count     int64
+	}{
+		{direction: sdk.ScaleDirectionNone, count: 2},
+		{direction: sdk.ScaleDirectionUp, count: 4},
+		{direction: sdk.ScaleDirectionNone, count: 4},
+		{direction: sdk.ScaleDirectionDown, count: 2},
+	}
+
+	count := int64(2)
+	for i, e := range expected {
+		to := time.Now()
+		metrics, err := a.Query(check.Query, sdk.TimeRange{From: to.Add(-time.Minute), To: to})
+		assert.NoError(t, err)
+
+		eval := &sdk.ScalingCheckEvaluation{Check: check, Metrics: metrics, Action: &sdk.ScalingAction{}}
+		eval, err = s.Run(eval, count)
+		assert.NoError(t, err)
+
+		assert.Equal(t, e.direction, eval.Action.Direction, "step %d", i)
+		if eval.Action.Direction != sdk.ScaleDirectionNone {
+			count = eval.Action.Count
+		}
+		assert.Equal(t, e.count, count, "step %d", i)
+	}
+}
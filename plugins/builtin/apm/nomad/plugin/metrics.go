@@ -48,6 +48,24 @@ func (a *APMPlugin) Query(q string, _ sdk.TimeRange) (sdk.TimestampedMetrics, er
 	}
 }
 
+// ValidateQuery satisfies the ValidateQuery function on the
+// apm.QueryValidator interface. It parses the query in the same manner as
+// Query, without fetching any data.
+func (a *APMPlugin) ValidateQuery(q string) error {
+	querySplit := strings.Split(q, "_")
+
+	var err error
+	switch querySplit[0] {
+	case QueryTypeTaskGroup:
+		_, err = parseTaskGroupQuery(q)
+	case QueryTypeNode:
+		_, err = parseNodePoolQuery(q)
+	default:
+		err = fmt.Errorf("unsupported query type %q", querySplit[0])
+	}
+	return err
+}
+
 func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
 	d, err := a.Query(q, r)
 	if err != nil {
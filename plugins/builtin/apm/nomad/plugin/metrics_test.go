@@ -53,3 +53,46 @@ func Test_validateMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestAPMPlugin_ValidateQuery(t *testing.T) {
+	testCases := []struct {
+		inputQuery  string
+		expectError bool
+		name        string
+	}{
+		{
+			inputQuery:  "taskgroup_avg_cpu/cache/example",
+			expectError: false,
+			name:        "valid task group query",
+		},
+		{
+			inputQuery:  "node_percentage-allocated_memory/high-memory/class",
+			expectError: false,
+			name:        "valid node query",
+		},
+		{
+			inputQuery:  "taskgroup_avg_disk/cache/example",
+			expectError: true,
+			name:        "invalid task group metric",
+		},
+		{
+			inputQuery:  "node_percentage-allocated_memory",
+			expectError: true,
+			name:        "node query without pool identifier",
+		},
+		{
+			inputQuery:  "job_avg_cpu/cache/example",
+			expectError: true,
+			name:        "unsupported query type",
+		},
+	}
+
+	a := &APMPlugin{}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := a.ValidateQuery(tc.inputQuery)
+			assert.Equal(t, tc.expectError, err != nil, tc.name)
+		})
+	}
+}
@@ -48,7 +48,7 @@ func (a *APMPlugin) SetConfig(config map[string]string) error {
 
 	cfg := nomadHelper.ConfigFromNamespacedMap(config)
 
-	client, err := api.NewClient(cfg)
+	client, err := nomadHelper.NewClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to instantiate Nomad client: %v", err)
 	}
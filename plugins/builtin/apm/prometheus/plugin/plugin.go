@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -23,6 +26,11 @@ const (
 	// configKeyAddress is the accepted configuration key which holds the
 	// address param.
 	configKeyAddress = "address"
+
+	// configKeyHeaderPrefix is the prefix of the accepted configuration keys
+	// which hold the headers sent with each request, such as
+	// "header.Authorization". This allows requests to be authenticated.
+	configKeyHeaderPrefix = "header."
 )
 
 var (
@@ -41,6 +49,14 @@ var (
 	}
 )
 
+// Ensure APMPlugin supports per-instance queries, and allows checks to
+// override the address and headers used to query Prometheus.
+var (
+	_ apm.LabeledQuerier  = (*APMPlugin)(nil)
+	_ apm.ParamsQuerier   = (*APMPlugin)(nil)
+	_ apm.ConfigOverrider = (*APMPlugin)(nil)
+)
+
 type APMPlugin struct {
 	client api.Client
 	config map[string]string
@@ -57,28 +73,101 @@ func (a *APMPlugin) SetConfig(config map[string]string) error {
 
 	a.config = config
 
+	client, err := newClient(config)
+	if err != nil {
+		return err
+	}
+
+	// store config and client in plugin instance
+	a.client = client
+
+	return nil
+}
+
+// newClient creates a Prometheus client using the passed config.
+func newClient(config map[string]string) (api.Client, error) {
+
 	// If the address is not set, or is empty within the config, any client
 	// calls will fail. It seems logical to catch this here rather than just
 	// let queries fail.
-	addr, ok := a.config[configKeyAddress]
+	addr, ok := config[configKeyAddress]
 	if !ok || addr == "" {
-		return fmt.Errorf("%q config value cannot be empty", configKeyAddress)
+		return nil, fmt.Errorf("%q config value cannot be empty", configKeyAddress)
 	}
 
 	promCfg := api.Config{
 		Address: addr,
 	}
 
+	headers := make(http.Header)
+	for k, v := range config {
+		if name := strings.TrimPrefix(k, configKeyHeaderPrefix); name != k && name != "" {
+			headers.Set(name, v)
+		}
+	}
+	if len(headers) > 0 {
+		promCfg.RoundTripper = &headerRoundTripper{headers: headers, next: api.DefaultRoundTripper}
+	}
+
 	// create Prometheus client
 	client, err := api.NewClient(promCfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize Prometheus client: %v", err)
+		return nil, fmt.Errorf("failed to initialize Prometheus client: %v", err)
 	}
+	return client, nil
+}
 
-	// store config and client in plugin instance
-	a.client = client
+// headerRoundTripper adds the configured headers to each request.
+type headerRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
 
-	return nil
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range h.headers {
+		req.Header[k] = v
+	}
+	return h.next.RoundTrip(req)
+}
+
+// ValidateConfigOverride satisfies the ValidateConfigOverride function on the
+// apm.ConfigOverrider interface. Checks can override the address and the
+// headers of the plugin config.
+func (a *APMPlugin) ValidateConfigOverride(config map[string]string) error {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k != configKeyAddress && !strings.HasPrefix(k, configKeyHeaderPrefix) {
+			return fmt.Errorf("%q config value can't be overridden", k)
+		}
+	}
+
+	_, err := newClient(a.overrideConfig(config))
+	return err
+}
+
+// overrideConfig returns a copy of the plugin config with the passed values
+// overridden. The configured headers hold the credentials of the configured
+// address, so they are not inherited when the address is overridden.
+func (a *APMPlugin) overrideConfig(override map[string]string) map[string]string {
+	_, addressOverridden := override[configKeyAddress]
+
+	config := make(map[string]string, len(a.config)+len(override))
+	for k, v := range a.config {
+		if addressOverridden && strings.HasPrefix(k, configKeyHeaderPrefix) {
+			continue
+		}
+		config[k] = v
+	}
+	for k, v := range override {
+		config[k] = v
+	}
+	return config
 }
 
 func (a *APMPlugin) PluginInfo() (*base.PluginInfo, error) {
@@ -86,7 +175,26 @@ func (a *APMPlugin) PluginInfo() (*base.PluginInfo, error) {
 }
 
 func (a *APMPlugin) Query(q string, r sdk.TimeRange) (sdk.TimestampedMetrics, error) {
-	m, err := a.QueryMultiple(q, r)
+	return a.QueryWithParams(sdk.QueryRequest{Query: q, TimeRange: r})
+}
+
+func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
+	return a.queryMultiple(a.client, q, r)
+}
+
+// QueryWithParams satisfies the QueryWithParams function on the
+// apm.ParamsQuerier interface. Prometheus queries express their parameters
+// within the query, so only the config overrides of the request are used.
+func (a *APMPlugin) QueryWithParams(req sdk.QueryRequest) (sdk.TimestampedMetrics, error) {
+	client := a.client
+	if len(req.Config) > 0 {
+		var err error
+		if client, err = newClient(a.overrideConfig(req.Config)); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := a.queryMultiple(client, req.Query, req.TimeRange)
 	if err != nil {
 		return nil, err
 	}
@@ -101,22 +209,10 @@ func (a *APMPlugin) Query(q string, r sdk.TimeRange) (sdk.TimestampedMetrics, er
 	}
 }
 
-func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
-	a.logger.Debug("querying Prometheus", "query", q, "range", r)
-
-	v1api := v1.NewAPI(a.client)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	promRange := v1.Range{Start: r.From, End: r.To, Step: time.Second}
-	result, warnings, err := v1api.QueryRange(ctx, q, promRange)
+func (a *APMPlugin) queryMultiple(client api.Client, q string, r sdk.TimeRange) ([]sdk.TimestampedMetrics, error) {
+	result, err := a.queryRange(client, q, r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query: %v", err)
-	}
-
-	// If Prometheus returned warnings, report these to the user.
-	for _, w := range warnings {
-		a.logger.Warn("prometheus query returned warning", "warning", w)
+		return nil, err
 	}
 
 	switch t := result.Type(); t {
@@ -134,6 +230,43 @@ func (a *APMPlugin) QueryMultiple(q string, r sdk.TimeRange) ([]sdk.TimestampedM
 	}
 }
 
+// QueryLabeled satisfies the QueryLabeled function on the apm.LabeledQuerier
+// interface. Each series returned by the query is keyed by its label set,
+// such as {instance="10.0.0.1:9100"}.
+func (a *APMPlugin) QueryLabeled(q string, r sdk.TimeRange) (sdk.LabeledMetrics, error) {
+	result, err := a.queryRange(a.client, q, r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := result.Type(); t {
+	case model.ValMatrix:
+		return parseLabeledMatrix(result.(model.Matrix))
+	default:
+		return nil, fmt.Errorf("result type (`%v`) is not supported for per-instance queries", t)
+	}
+}
+
+func (a *APMPlugin) queryRange(client api.Client, q string, r sdk.TimeRange) (model.Value, error) {
+	a.logger.Debug("querying Prometheus", "query", q, "range", r)
+
+	v1api := v1.NewAPI(client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	promRange := v1.Range{Start: r.From, End: r.To, Step: time.Second}
+	result, warnings, err := v1api.QueryRange(ctx, q, promRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %v", err)
+	}
+
+	// If Prometheus returned warnings, report these to the user.
+	for _, w := range warnings {
+		a.logger.Warn("prometheus query returned warning", "warning", w)
+	}
+	return result, nil
+}
+
 func parseScalar(s *model.Scalar) ([]sdk.TimestampedMetrics, error) {
 	if s == nil {
 		return nil, nil
@@ -182,6 +315,25 @@ func parseMatrix(m model.Matrix) ([]sdk.TimestampedMetrics, error) {
 	return result, nil
 }
 
+func parseLabeledMatrix(m model.Matrix) (sdk.LabeledMetrics, error) {
+	result := make(sdk.LabeledMetrics, len(m))
+	for _, ss := range m {
+		var metrics sdk.TimestampedMetrics
+		for _, sp := range ss.Values {
+			tm, err := parseSample(sp)
+			if err != nil {
+				return nil, err
+			}
+
+			metrics = append(metrics, tm)
+		}
+
+		result[ss.Metric.String()] = metrics
+	}
+
+	return result, nil
+}
+
 func parseSample(s interface{}) (sdk.TimestampedMetric, error) {
 	var ts model.Time
 	var val model.SampleValue
@@ -2,10 +2,17 @@ package plugin
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAPMPlugin_SetConfig(t *testing.T) {
@@ -46,3 +53,144 @@ func TestAPMPlugin_SetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestAPMPlugin_ValidateConfigOverride(t *testing.T) {
+	testCases := []struct {
+		inputConfig   map[string]string
+		expectedError error
+		name          string
+	}{
+		{
+			inputConfig:   map[string]string{"address": "http://127.0.0.1:9091", "header.Authorization": "Bearer secret"},
+			expectedError: nil,
+			name:          "address and header",
+		},
+		{
+			inputConfig:   map[string]string{"address": "\n\n"},
+			expectedError: errors.New(`failed to initialize Prometheus client: parse "\n\n": net/url: invalid control character in URL`),
+			name:          "malformed address",
+		},
+		{
+			inputConfig:   map[string]string{"timeout": "1m"},
+			expectedError: errors.New(`"timeout" config value can't be overridden`),
+			name:          "unsupported key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apmPlugin := APMPlugin{logger: hclog.NewNullLogger()}
+			require.NoError(t, apmPlugin.SetConfig(map[string]string{"address": "http://127.0.0.1:9090"}))
+
+			err := apmPlugin.ValidateConfigOverride(tc.inputConfig)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+		})
+	}
+}
+
+func TestAPMPlugin_QueryWithParams_config(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1600000000,"42"]]}]}}`)
+	}))
+	defer ts.Close()
+
+	apmPlugin := APMPlugin{logger: hclog.NewNullLogger()}
+	require.NoError(t, apmPlugin.SetConfig(map[string]string{"address": "http://127.0.0.1:1"}))
+
+	req := sdk.QueryRequest{
+		Query:     "up",
+		TimeRange: sdk.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000060, 0)},
+		Config:    map[string]string{"address": ts.URL, "header.Authorization": "Bearer secret"},
+	}
+
+	m, err := apmPlugin.QueryWithParams(req)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	assert.Equal(t, float64(42), m[0].Value)
+
+	// The override is only used for the request which sets it.
+	req.Config = nil
+	_, err = apmPlugin.QueryWithParams(req)
+	assert.Error(t, err)
+}
+
+func TestAPMPlugin_QueryWithParams_inheritedHeaders(t *testing.T) {
+	var received http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+	}))
+	defer ts.Close()
+
+	apmPlugin := APMPlugin{logger: hclog.NewNullLogger()}
+	require.NoError(t, apmPlugin.SetConfig(map[string]string{
+		"address":              "http://127.0.0.1:1",
+		"header.Authorization": "Bearer secret",
+	}))
+
+	// The configured credentials are not sent to an address set by a check.
+	req := sdk.QueryRequest{
+		Query:     "up",
+		TimeRange: sdk.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000060, 0)},
+		Config:    map[string]string{"address": ts.URL},
+	}
+	_, err := apmPlugin.QueryWithParams(req)
+	require.NoError(t, err)
+	assert.Empty(t, received.Get("Authorization"))
+
+	// Checks can still set their own headers along with the address.
+	req.Config["header.Authorization"] = "Bearer check"
+	_, err = apmPlugin.QueryWithParams(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer check", received.Get("Authorization"))
+}
+
+func Test_parseLabeledMatrix(t *testing.T) {
+	testCases := []struct {
+		inputMatrix    model.Matrix
+		expectedOutput sdk.LabeledMetrics
+		name           string
+	}{
+		{
+			inputMatrix:    model.Matrix{},
+			expectedOutput: sdk.LabeledMetrics{},
+			name:           "empty matrix",
+		},
+		{
+			inputMatrix: model.Matrix{
+				{
+					Metric: model.Metric{"instance": "a"},
+					Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+				},
+				{
+					Metric: model.Metric{"instance": "b"},
+					Values: []model.SamplePair{{Timestamp: 1000, Value: 3}},
+				},
+			},
+			expectedOutput: sdk.LabeledMetrics{
+				`{instance="a"}`: {
+					{Timestamp: time.Unix(1, 0), Value: 1},
+					{Timestamp: time.Unix(2, 0), Value: 2},
+				},
+				`{instance="b"}`: {
+					{Timestamp: time.Unix(1, 0), Value: 3},
+				},
+			},
+			name: "series keyed by labels",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := parseLabeledMatrix(tc.inputMatrix)
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
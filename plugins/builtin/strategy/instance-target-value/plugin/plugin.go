@@ -0,0 +1,163 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	targetvalue "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/target-value/plugin"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "instance-target-value"
+
+	// runConfigKeySelect is the key read from the RunRequest.Config map which
+	// controls which instance is used to calculate the new count. All other
+	// keys are those supported by the target-value strategy.
+	runConfigKeySelect = "select"
+
+	// These are the supported values of the select config key. The max value
+	// scales on the instance with the highest metric value, and min on the
+	// instance with the lowest.
+	selectMax = "max"
+	selectMin = "min"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewInstanceTargetValuePlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy interface.
+var _ strategy.Strategy = (*StrategyPlugin)(nil)
+
+// StrategyPlugin is the InstanceTargetValue implementation of the
+// strategy.Strategy interface. It consumes the per-instance metrics of checks
+// with per_instance set, and applies the target-value calculation to the
+// latest metric of a single instance selected by the select config key.
+//
+// Per-instance metrics are only available to internal plugins, so this
+// plugin is not built as an external plugin binary.
+type StrategyPlugin struct {
+	config      map[string]string
+	logger      hclog.Logger
+	targetValue strategy.Strategy
+}
+
+// NewInstanceTargetValuePlugin returns the InstanceTargetValue implementation
+// of the strategy.Strategy interface.
+func NewInstanceTargetValuePlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger:      log,
+		targetValue: targetvalue.NewTargetValuePlugin(log),
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+
+	// Read and validate the select value from req.Config.
+	sel := eval.Check.Strategy.Config[runConfigKeySelect]
+	switch sel {
+	case "":
+		sel = selectMax
+	case selectMax, selectMin:
+	default:
+		return nil, fmt.Errorf("invalid value for `select`: %v (%T)", sel, sel)
+	}
+
+	if eval.LabeledMetrics == nil {
+		return nil, fmt.Errorf("no per-instance metrics found, per_instance must be set on the check")
+	}
+
+	label, metrics := selectInstance(eval.LabeledMetrics, sel)
+	if label == "" {
+		s.logger.Trace("no instance metrics available", "check_name", eval.Check.Name)
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	s.logger.Trace("selected instance metric",
+		"check_name", eval.Check.Name, "select", sel, "instance", label,
+		"metric_value", metrics[len(metrics)-1].Value)
+
+	// Calculate the new count using the selected instance's metrics.
+	eval.Metrics = metrics
+
+	eval, err := s.targetValue.Run(eval, count)
+	if err != nil || eval == nil {
+		return eval, err
+	}
+
+	if eval.Action.Reason != "" {
+		eval.Action.Reason = fmt.Sprintf("%s on instance %s", eval.Action.Reason, label)
+	}
+	return eval, nil
+}
+
+// selectInstance returns the label and metrics of the instance whose latest
+// metric value is the highest, when sel is selectMax, or the lowest, when sel
+// is selectMin. Instances without metrics are ignored. If no instance has
+// metrics, the returned label is empty.
+//
+// Instances are compared in label order, so ties are consistently resolved in
+// favour of the first label.
+func selectInstance(lm sdk.LabeledMetrics, sel string) (string, sdk.TimestampedMetrics) {
+	labels := make([]string, 0, len(lm))
+	for label := range lm {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var (
+		selected string
+		value    float64
+	)
+
+	for _, label := range labels {
+		m := lm[label]
+		if len(m) == 0 {
+			continue
+		}
+
+		v := m[len(m)-1].Value
+		if selected == "" ||
+			sel == selectMax && v > value ||
+			sel == selectMin && v < value {
+			selected, value = label, v
+		}
+	}
+
+	if selected == "" {
+		return "", nil
+	}
+	return selected, lm[selected]
+}
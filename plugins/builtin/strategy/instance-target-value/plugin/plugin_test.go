@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "instance-target-value", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	labeledMetrics := sdk.LabeledMetrics{
+		`{instance="a"}`: {{Value: 30}, {Value: 5}},
+		`{instance="b"}`: {{Value: 20}},
+		`{instance="c"}`: {{Value: 12}},
+		`{instance="d"}`: {},
+	}
+
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputMetrics   sdk.LabeledMetrics
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig:    map[string]string{"target": "10"},
+			inputMetrics:   labeledMetrics,
+			expectedCount:  4,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: `scaling up because factor is 2.000000 on instance {instance="b"}`,
+			name:           "scale on max instance by default",
+		},
+		{
+			inputConfig:    map[string]string{"target": "10", "select": "min"},
+			inputMetrics:   labeledMetrics,
+			expectedCount:  1,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: `scaling down because factor is 0.500000 on instance {instance="a"}`,
+			name:           "scale on min instance",
+		},
+		{
+			inputConfig:  map[string]string{"target": "10"},
+			inputMetrics: sdk.LabeledMetrics{`{instance="a"}`: {}},
+			expectedDir:  sdk.ScaleDirectionNone,
+			name:         "no instance metrics",
+		},
+		{
+			inputConfig:   map[string]string{"target": "10", "select": "avg"},
+			inputMetrics:  labeledMetrics,
+			expectedError: errors.New("invalid value for `select`: avg (string)"),
+			name:          "invalid select",
+		},
+		{
+			inputConfig:   map[string]string{"target": "10"},
+			inputMetrics:  nil,
+			expectedError: errors.New("no per-instance metrics found, per_instance must be set on the check"),
+			name:          "check not per-instance",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewInstanceTargetValuePlugin(hclog.NewNullLogger())
+			eval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Name:     "check",
+					Strategy: &sdk.ScalingPolicyStrategy{Name: "instance-target-value", Config: tc.inputConfig},
+				},
+				LabeledMetrics: tc.inputMetrics,
+				Action:         &sdk.ScalingAction{},
+			}
+
+			actualEval, err := s.Run(eval, 2)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.expectedCount, actualEval.Action.Count, tc.name)
+			assert.Equal(t, tc.expectedDir, actualEval.Action.Direction, tc.name)
+			assert.Equal(t, tc.expectedReason, actualEval.Action.Reason, tc.name)
+		})
+	}
+}
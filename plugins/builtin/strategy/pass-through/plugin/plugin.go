@@ -0,0 +1,111 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "pass-through"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewPassThroughPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy interface.
+var _ strategy.Strategy = (*StrategyPlugin)(nil)
+
+// StrategyPlugin is the PassThrough implementation of the strategy.Strategy
+// interface. It uses the metric as the desired count of the target, rounded
+// up, which allows the count to be calculated by the query or a check
+// formula.
+type StrategyPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+}
+
+// NewPassThroughPlugin returns the PassThrough implementation of the
+// strategy.Strategy interface.
+func NewPassThroughPlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger: log,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+
+	// This shouldn't happen, but check it just in case.
+	if len(eval.Metrics) == 0 {
+		return nil, nil
+	}
+
+	// Use only the latest value, which is the desired count.
+	metric := eval.Metrics[len(eval.Metrics)-1]
+
+	// A NaN, infinite or negative metric would result in a nonsensical count,
+	// so do not attempt to use it.
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) || metric.Value < 0 {
+		return nil, fmt.Errorf("invalid metric value: %v", metric.Value)
+	}
+
+	desiredCount := math.Ceil(metric.Value)
+
+	// Converting a count which does not fit in an int64 is undefined, and
+	// would most likely result in a negative count.
+	if desiredCount >= math.MaxInt64 {
+		return nil, fmt.Errorf("metric value %g is too large to be used as count", metric.Value)
+	}
+	newCount := int64(desiredCount)
+
+	s.logger.Trace("calculated scaling strategy results",
+		"check_name", eval.Check.Name, "current_count", count, "new_count", newCount,
+		"metric_value", metric.Value, "metric_time", metric.Timestamp)
+
+	switch {
+	case newCount > count:
+		eval.Action.Direction = sdk.ScaleDirectionUp
+	case newCount < count:
+		eval.Action.Direction = sdk.ScaleDirectionDown
+	default:
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	eval.Action.Count = newCount
+	eval.Action.Reason = fmt.Sprintf("scaling %s because metric is %g", eval.Action.Direction, metric.Value)
+
+	return eval, nil
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "pass-through", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	testCases := []struct {
+		inputMetric    float64
+		inputCount     int64
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputMetric:   -1,
+			inputCount:    2,
+			expectedError: errors.New("invalid metric value: -1"),
+			name:          "negative metric",
+		},
+		{
+			inputMetric:   math.NaN(),
+			inputCount:    2,
+			expectedError: errors.New("invalid metric value: NaN"),
+			name:          "NaN metric",
+		},
+		{
+			inputMetric:   math.Inf(1),
+			inputCount:    2,
+			expectedError: errors.New("invalid metric value: +Inf"),
+			name:          "infinite metric",
+		},
+		{
+			inputMetric:   1e20,
+			inputCount:    2,
+			expectedError: errors.New("metric value 1e+20 is too large to be used as count"),
+			name:          "metric too large",
+		},
+		{
+			inputMetric:    4.2,
+			inputCount:     2,
+			expectedCount:  5,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because metric is 4.2",
+			name:           "scale up",
+		},
+		{
+			inputMetric:    3,
+			inputCount:     8,
+			expectedCount:  3,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because metric is 3",
+			name:           "scale down",
+		},
+		{
+			inputMetric:    0,
+			inputCount:     3,
+			expectedCount:  0,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because metric is 0",
+			name:           "scale down to zero",
+		},
+		{
+			inputMetric: 2.1,
+			inputCount:  3,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "metric matches count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewPassThroughPlugin(hclog.NewNullLogger())
+
+			eval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Name:     "check",
+					Strategy: &sdk.ScalingPolicyStrategy{Name: "pass-through"},
+				},
+				Metrics: sdk.TimestampedMetrics{{Value: tc.inputMetric}},
+				Action:  &sdk.ScalingAction{},
+			}
+
+			eval, err := s.Run(eval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if tc.expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expectedDir, eval.Action.Direction, tc.name)
+			assert.Equal(t, tc.expectedCount, eval.Action.Count, tc.name)
+			assert.Equal(t, tc.expectedReason, eval.Action.Reason, tc.name)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "queue-ratio"
+
+	// These are the keys read from the RunRequest.Config map.
+	runConfigKeyTargetRatio = "target_ratio"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewQueueRatioPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy interface.
+var _ strategy.Strategy = (*StrategyPlugin)(nil)
+
+// StrategyPlugin is the QueueRatio implementation of the strategy.Strategy
+// interface. It keeps the number of queued items per instance of the target
+// around the target ratio, so the metric is the total depth of the queue
+// rather than a value per instance.
+//
+// The count is calculated from the queue depth alone, so it does not depend
+// on the current count and scaling from 0 needs no special handling. An
+// empty queue results in a count of 0, which the policy min is then applied
+// to.
+type StrategyPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+}
+
+// NewQueueRatioPlugin returns the QueueRatio implementation of the
+// strategy.Strategy interface.
+func NewQueueRatioPlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger: log,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+
+	// Read and parse the target ratio from req.Config. This is the number of
+	// queued items each instance of the target should handle.
+	tr := eval.Check.Strategy.Config[runConfigKeyTargetRatio]
+	if tr == "" {
+		return nil, fmt.Errorf("missing required field `target_ratio`")
+	}
+
+	targetRatio, err := strconv.ParseFloat(tr, 64)
+	if err != nil || targetRatio <= 0 || math.IsInf(targetRatio, 0) {
+		return nil, fmt.Errorf("invalid value for `target_ratio`: %v (%T)", tr, tr)
+	}
+
+	// This shouldn't happen, but check it just in case.
+	if len(eval.Metrics) == 0 {
+		return nil, nil
+	}
+
+	// Use only the latest value, which is the current depth of the queue.
+	metric := eval.Metrics[len(eval.Metrics)-1]
+
+	// A NaN, infinite or negative queue depth would result in a nonsensical
+	// count, so do not attempt to calculate one.
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) || metric.Value < 0 {
+		return nil, fmt.Errorf("invalid metric value: %v", metric.Value)
+	}
+
+	desiredCount := math.Ceil(metric.Value / targetRatio)
+
+	// Converting a count which does not fit in an int64 is undefined, and
+	// would most likely result in a negative count.
+	if desiredCount >= math.MaxInt64 {
+		return nil, fmt.Errorf("calculated count %g is too large, queue depth is %g", desiredCount, metric.Value)
+	}
+	newCount := int64(desiredCount)
+
+	s.logger.Trace("calculated scaling strategy results",
+		"check_name", eval.Check.Name, "current_count", count, "new_count", newCount,
+		"metric_value", metric.Value, "metric_time", metric.Timestamp, "target_ratio", targetRatio)
+
+	switch {
+	case newCount > count:
+		eval.Action.Direction = sdk.ScaleDirectionUp
+	case newCount < count:
+		eval.Action.Direction = sdk.ScaleDirectionDown
+	default:
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	eval.Action.Count = newCount
+	eval.Action.Reason = fmt.Sprintf("scaling %s because queue depth is %g and target ratio is %g per instance",
+		eval.Action.Direction, metric.Value, targetRatio)
+
+	// The deviation from the target ratio can only be calculated while the
+	// target has instances to share the queue.
+	if count > 0 {
+		eval.Action.SetDeviation(metric.Value / float64(count) / targetRatio)
+	}
+
+	return eval, nil
+}
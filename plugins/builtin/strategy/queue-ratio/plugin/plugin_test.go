@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "queue-ratio", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputMetric    float64
+		inputCount     int64
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig:   map[string]string{},
+			inputMetric:   10,
+			inputCount:    2,
+			expectedError: errors.New("missing required field `target_ratio`"),
+			name:          "missing target ratio",
+		},
+		{
+			inputConfig:   map[string]string{"target_ratio": "0"},
+			inputMetric:   10,
+			inputCount:    2,
+			expectedError: errors.New("invalid value for `target_ratio`: 0 (string)"),
+			name:          "zero target ratio",
+		},
+		{
+			inputConfig:   map[string]string{"target_ratio": "10"},
+			inputMetric:   -1,
+			inputCount:    2,
+			expectedError: errors.New("invalid metric value: -1"),
+			name:          "negative queue depth",
+		},
+		{
+			inputConfig:   map[string]string{"target_ratio": "10"},
+			inputMetric:   math.NaN(),
+			inputCount:    2,
+			expectedError: errors.New("invalid metric value: NaN"),
+			name:          "NaN queue depth",
+		},
+		{
+			inputConfig:    map[string]string{"target_ratio": "10"},
+			inputMetric:    45,
+			inputCount:     2,
+			expectedCount:  5,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because queue depth is 45 and target ratio is 10 per instance",
+			name:           "scale up",
+		},
+		{
+			inputConfig:    map[string]string{"target_ratio": "10"},
+			inputMetric:    25,
+			inputCount:     0,
+			expectedCount:  3,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because queue depth is 25 and target ratio is 10 per instance",
+			name:           "scale up from zero",
+		},
+		{
+			inputConfig:    map[string]string{"target_ratio": "2.5"},
+			inputMetric:    10,
+			inputCount:     8,
+			expectedCount:  4,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because queue depth is 10 and target ratio is 2.5 per instance",
+			name:           "scale down",
+		},
+		{
+			inputConfig:    map[string]string{"target_ratio": "10"},
+			inputMetric:    0,
+			inputCount:     3,
+			expectedCount:  0,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because queue depth is 0 and target ratio is 10 per instance",
+			name:           "empty queue",
+		},
+		{
+			inputConfig: map[string]string{"target_ratio": "10"},
+			inputMetric: 0,
+			inputCount:  0,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "empty queue without instances",
+		},
+		{
+			inputConfig: map[string]string{"target_ratio": "10"},
+			inputMetric: 21,
+			inputCount:  3,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "ratio within target",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewQueueRatioPlugin(hclog.NewNullLogger())
+
+			eval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Name:     "check",
+					Strategy: &sdk.ScalingPolicyStrategy{Name: "queue-ratio", Config: tc.inputConfig},
+				},
+				Metrics: sdk.TimestampedMetrics{{Value: tc.inputMetric}},
+				Action:  &sdk.ScalingAction{},
+			}
+
+			eval, err := s.Run(eval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if tc.expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expectedDir, eval.Action.Direction, tc.name)
+			assert.Equal(t, tc.expectedCount, eval.Action.Count, tc.name)
+			assert.Equal(t, tc.expectedReason, eval.Action.Reason, tc.name)
+		})
+	}
+}
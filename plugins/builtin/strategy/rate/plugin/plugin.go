@@ -0,0 +1,219 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "rate"
+
+	// These are the keys read from the RunRequest.Config map.
+	runConfigKeyThreshold = "threshold"
+	runConfigKeyAmount    = "amount"
+	runConfigKeyScaleIn   = "scale_in"
+
+	// defaultAmount is the number of instances added or removed when the
+	// rate of change crosses the threshold.
+	defaultAmount = 1
+
+	// sampleTTL is the time after which the previous metric value of a check
+	// is discarded if the check has not been evaluated, such as when its
+	// policy has been removed.
+	sampleTTL = time.Hour
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewRatePlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy interface.
+var _ strategy.Strategy = (*StrategyPlugin)(nil)
+
+// StrategyPlugin is the Rate implementation of the strategy.Strategy
+// interface. It scales based on the rate of change of the metric rather than
+// its value, so rapidly increasing load triggers a scale out before the
+// thresholds of other checks are reached.
+//
+// The rate is calculated using the metric value of the previous evaluation of
+// the check, so the first evaluation of a check never results in an action.
+type StrategyPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+
+	// samples are the latest metric values of each check, keyed by the
+	// policy ID and check name.
+	samples     map[string]*sample
+	samplesLock sync.Mutex
+
+	// now returns the current time and can be replaced within tests.
+	now func() time.Time
+}
+
+// sample is the metric value of a check evaluation.
+type sample struct {
+	metric sdk.TimestampedMetric
+
+	// seen is when the sample was recorded, and is used to discard samples
+	// of checks which are not evaluated anymore.
+	seen time.Time
+}
+
+// NewRatePlugin returns the Rate implementation of the strategy.Strategy
+// interface.
+func NewRatePlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger:  log,
+		samples: make(map[string]*sample),
+		now:     time.Now,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+
+	// Read and parse threshold value from req.Config. The threshold is the
+	// rate of change of the metric per minute.
+	th := eval.Check.Strategy.Config[runConfigKeyThreshold]
+	if th == "" {
+		return nil, fmt.Errorf("missing required field `threshold`")
+	}
+
+	threshold, err := strconv.ParseFloat(th, 64)
+	if err != nil || threshold <= 0 {
+		return nil, fmt.Errorf("invalid value for `threshold`: %v (%T)", th, th)
+	}
+
+	// Read and parse the optional amount from req.Config.
+	amount := int64(defaultAmount)
+
+	if a := eval.Check.Strategy.Config[runConfigKeyAmount]; a != "" {
+		amount, _, err = eval.Check.Strategy.ConfigInt(runConfigKeyAmount)
+		if err != nil || amount < 1 {
+			return nil, fmt.Errorf("invalid value for `amount`: %v (%T)", a, a)
+		}
+	}
+
+	// Read and parse the optional scale_in flag from req.Config. A falling
+	// metric does not necessarily mean the target is over-provisioned, so
+	// scaling in is opt-in.
+	var scaleIn bool
+
+	if si := eval.Check.Strategy.Config[runConfigKeyScaleIn]; si != "" {
+		scaleIn, err = strconv.ParseBool(si)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for `scale_in`: %v (%T)", si, si)
+		}
+	}
+
+	// This shouldn't happen, but check it just in case.
+	if len(eval.Metrics) == 0 {
+		return nil, nil
+	}
+
+	metric := eval.Metrics[len(eval.Metrics)-1]
+
+	// A NaN or infinite metric would result in a nonsensical rate, so do not
+	// attempt to calculate one.
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+		return nil, fmt.Errorf("invalid metric value: %v", metric.Value)
+	}
+
+	prev, ok := s.swapSample(eval.PolicyID+"/"+eval.Check.Name, metric)
+	if !ok {
+		s.logger.Trace("no previous metric value to calculate rate of change",
+			"check_name", eval.Check.Name, "metric_value", metric.Value)
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	// The APM may return the same data point as the previous evaluation, in
+	// which case there is no new data to calculate the rate from.
+	elapsed := metric.Timestamp.Sub(prev.Timestamp)
+	if elapsed <= 0 {
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	rate := (metric.Value - prev.Value) / elapsed.Minutes()
+	s.logger.Trace("calculated metric rate of change", "check_name", eval.Check.Name,
+		"metric_value", metric.Value, "previous_value", prev.Value, "rate", rate, "threshold", threshold)
+
+	var newCount int64
+
+	switch {
+	case rate >= threshold:
+		eval.Action.Direction = sdk.ScaleDirectionUp
+		newCount = count + amount
+	case rate <= -threshold && scaleIn && count > 0:
+		eval.Action.Direction = sdk.ScaleDirectionDown
+		if newCount = count - amount; newCount < 0 {
+			newCount = 0
+		}
+	default:
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	eval.Action.Count = newCount
+	eval.Action.Reason = fmt.Sprintf("scaling %s because metric rate of change is %.2f per minute",
+		eval.Action.Direction, rate)
+	return eval, nil
+}
+
+// swapSample stores the metric as the latest sample of the check identified
+// by key and returns the previous one, if any. Samples of checks which have
+// not been evaluated within sampleTTL are discarded.
+func (s *StrategyPlugin) swapSample(key string, metric sdk.TimestampedMetric) (sdk.TimestampedMetric, bool) {
+	s.samplesLock.Lock()
+	defer s.samplesLock.Unlock()
+
+	now := s.now()
+	for k, v := range s.samples {
+		if now.Sub(v.seen) > sampleTTL {
+			delete(s.samples, k)
+		}
+	}
+
+	prev, ok := s.samples[key]
+	s.samples[key] = &sample{metric: metric, seen: now}
+
+	if !ok {
+		return sdk.TimestampedMetric{}, false
+	}
+	return prev.metric, true
+}
@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "rate", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	start := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputPrevious  *sdk.TimestampedMetric
+		inputMetric    sdk.TimestampedMetric
+		inputCount     int64
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig:   map[string]string{},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputCount:    2,
+			expectedError: errors.New("missing required field `threshold`"),
+			name:          "missing threshold",
+		},
+		{
+			inputConfig:   map[string]string{"threshold": "-5"},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputCount:    2,
+			expectedError: errors.New("invalid value for `threshold`: -5 (string)"),
+			name:          "negative threshold",
+		},
+		{
+			inputConfig:   map[string]string{"threshold": "5", "amount": "0"},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputCount:    2,
+			expectedError: errors.New("invalid value for `amount`: 0 (string)"),
+			name:          "invalid amount",
+		},
+		{
+			inputConfig: map[string]string{"threshold": "5"},
+			inputMetric: sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputCount:  2,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "first evaluation",
+		},
+		{
+			inputConfig:    map[string]string{"threshold": "5", "amount": "2"},
+			inputPrevious:  &sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputMetric:    sdk.TimestampedMetric{Timestamp: start.Add(30 * time.Second), Value: 15},
+			inputCount:     2,
+			expectedCount:  4,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because metric rate of change is 10.00 per minute",
+			name:           "rate above threshold",
+		},
+		{
+			inputConfig:    map[string]string{"threshold": "5", "amount": "2e+00"},
+			inputPrevious:  &sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputMetric:    sdk.TimestampedMetric{Timestamp: start.Add(30 * time.Second), Value: 15},
+			inputCount:     2,
+			expectedCount:  4,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because metric rate of change is 10.00 per minute",
+			name:           "amount in scientific notation",
+		},
+		{
+			inputConfig:   map[string]string{"threshold": "5"},
+			inputPrevious: &sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start.Add(time.Minute), Value: 14},
+			inputCount:    2,
+			expectedDir:   sdk.ScaleDirectionNone,
+			name:          "rate below threshold",
+		},
+		{
+			inputConfig:   map[string]string{"threshold": "5"},
+			inputPrevious: &sdk.TimestampedMetric{Timestamp: start, Value: 20},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start.Add(time.Minute), Value: 10},
+			inputCount:    2,
+			expectedDir:   sdk.ScaleDirectionNone,
+			name:          "falling rate without scale in",
+		},
+		{
+			inputConfig:    map[string]string{"threshold": "5", "amount": "3", "scale_in": "true"},
+			inputPrevious:  &sdk.TimestampedMetric{Timestamp: start, Value: 20},
+			inputMetric:    sdk.TimestampedMetric{Timestamp: start.Add(time.Minute), Value: 10},
+			inputCount:     2,
+			expectedCount:  0,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because metric rate of change is -10.00 per minute",
+			name:           "falling rate with scale in",
+		},
+		{
+			inputConfig:   map[string]string{"threshold": "5"},
+			inputPrevious: &sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputMetric:   sdk.TimestampedMetric{Timestamp: start, Value: 10},
+			inputCount:    2,
+			expectedDir:   sdk.ScaleDirectionNone,
+			name:          "same data point",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewRatePlugin(hclog.NewNullLogger()).(*StrategyPlugin)
+
+			newEval := func(m sdk.TimestampedMetric) *sdk.ScalingCheckEvaluation {
+				return &sdk.ScalingCheckEvaluation{
+					PolicyID: "policy",
+					Check: &sdk.ScalingPolicyCheck{
+						Name:     "check",
+						Strategy: &sdk.ScalingPolicyStrategy{Name: "rate", Config: tc.inputConfig},
+					},
+					Metrics: sdk.TimestampedMetrics{m},
+					Action:  &sdk.ScalingAction{},
+				}
+			}
+
+			if tc.inputPrevious != nil {
+				_, err := s.Run(newEval(*tc.inputPrevious), tc.inputCount)
+				assert.NoError(t, err)
+			}
+
+			eval, err := s.Run(newEval(tc.inputMetric), tc.inputCount)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if tc.expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expectedDir, eval.Action.Direction, tc.name)
+			assert.Equal(t, tc.expectedCount, eval.Action.Count, tc.name)
+			assert.Equal(t, tc.expectedReason, eval.Action.Reason, tc.name)
+		})
+	}
+}
+
+func TestStrategyPlugin_swapSample(t *testing.T) {
+	now := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+	s := NewRatePlugin(hclog.NewNullLogger()).(*StrategyPlugin)
+	s.now = func() time.Time { return now }
+
+	m := sdk.TimestampedMetric{Timestamp: now, Value: 1}
+
+	// Checks are tracked independently.
+	_, ok := s.swapSample("policy-1/check", m)
+	assert.False(t, ok)
+	_, ok = s.swapSample("policy-2/check", m)
+	assert.False(t, ok)
+
+	prev, ok := s.swapSample("policy-1/check", sdk.TimestampedMetric{Timestamp: now, Value: 2})
+	assert.True(t, ok)
+	assert.Equal(t, m, prev)
+
+	// Samples of checks which are not evaluated anymore are discarded.
+	now = now.Add(sampleTTL + time.Minute)
+	_, ok = s.swapSample("policy-1/check", m)
+	assert.False(t, ok)
+	assert.Len(t, s.samples, 1)
+}
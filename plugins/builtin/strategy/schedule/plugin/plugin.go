@@ -0,0 +1,152 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "schedule"
+
+	// runConfigKeyTimezone is the key read from the RunRequest.Config map
+	// which holds the IANA name of the timezone the windows are defined in.
+	// All other keys define a schedule window, named by the key.
+	runConfigKeyTimezone = "timezone"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewSchedulePlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy interface.
+var _ strategy.Strategy = (*StrategyPlugin)(nil)
+
+// StrategyPlugin is the Schedule implementation of the strategy.Strategy
+// interface. It overrides the count, or the limits of the count, during the
+// schedule windows defined in its config, such as outside of business hours.
+// Outside of all windows it does not propose a change, so when it is chained
+// after another strategy the count proposed by that strategy is kept.
+type StrategyPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+
+	// now returns the current time and can be replaced within tests.
+	now func() time.Time
+}
+
+// NewSchedulePlugin returns the Schedule implementation of the
+// strategy.Strategy interface.
+func NewSchedulePlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger: log,
+		now:    time.Now,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+
+	loc := time.UTC
+	if tz := eval.Check.Strategy.Config[runConfigKeyTimezone]; tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid value for `%s`: %v", runConfigKeyTimezone, err)
+		}
+	}
+
+	windows, err := parseWindows(eval.Check.Strategy.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Windows are matched against the wall clock time of the timezone, so
+	// they follow DST changes.
+	now := s.now().In(loc)
+
+	var active *window
+	for _, w := range windows {
+		if w.Matches(now) {
+			active = w
+			break
+		}
+	}
+
+	if active == nil {
+		s.logger.Trace("no schedule window is active", "check_name", eval.Check.Name, "time", now)
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	newCount := active.apply(count)
+	s.logger.Trace("schedule window is active", "check_name", eval.Check.Name,
+		"window", active.name, "time", now, "count", count, "new_count", newCount)
+
+	switch {
+	case newCount > count:
+		eval.Action.Direction = sdk.ScaleDirectionUp
+	case newCount < count:
+		eval.Action.Direction = sdk.ScaleDirectionDown
+	default:
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	eval.Action.Count = newCount
+	eval.Action.Reason = fmt.Sprintf("scaling %s because schedule window %s is active",
+		eval.Action.Direction, active.name)
+	return eval, nil
+}
+
+// parseWindows parses all the schedule windows within the config. Windows are
+// returned ordered by name, which is the order they are matched in.
+func parseWindows(config map[string]string) ([]*window, error) {
+	names := make([]string, 0, len(config))
+	for k := range config {
+		if k != runConfigKeyTimezone {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	windows := make([]*window, 0, len(names))
+	for _, name := range names {
+		w, err := parseWindow(name, config[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule window `%s`: %v", name, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
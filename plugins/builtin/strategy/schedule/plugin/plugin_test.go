@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "schedule", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	config := map[string]string{
+		"timezone": "Europe/Berlin",
+		"night":    "mon-fri 20:00-08:00 count=2",
+		"weekend":  "sat,sun 00:00-24:00 min=1 max=3",
+	}
+
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputTime      time.Time
+		inputCount     int64
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig: config,
+			// Wednesday 12:00 in Berlin.
+			inputTime:   time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC),
+			inputCount:  5,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "no active window",
+		},
+		{
+			inputConfig: config,
+			// Wednesday 21:00 in Berlin.
+			inputTime:      time.Date(2020, time.November, 18, 20, 0, 0, 0, time.UTC),
+			inputCount:     5,
+			expectedCount:  2,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because schedule window night is active",
+			name:           "fixed count window",
+		},
+		{
+			inputConfig: config,
+			// Saturday 07:00 in Berlin belongs to the window which started on
+			// Friday evening.
+			inputTime:      time.Date(2020, time.November, 21, 6, 0, 0, 0, time.UTC),
+			inputCount:     1,
+			expectedCount:  2,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because schedule window night is active",
+			name:           "window crossing midnight",
+		},
+		{
+			inputConfig: config,
+			// Sunday 07:00 in Berlin.
+			inputTime:      time.Date(2020, time.November, 22, 6, 0, 0, 0, time.UTC),
+			inputCount:     5,
+			expectedCount:  3,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because schedule window weekend is active",
+			name:           "limit window above max",
+		},
+		{
+			inputConfig: config,
+			// Sunday 12:00 in Berlin.
+			inputTime:   time.Date(2020, time.November, 22, 11, 0, 0, 0, time.UTC),
+			inputCount:  2,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "limit window within limits",
+		},
+		{
+			inputConfig: config,
+			// Tuesday 07:30 in Berlin during summer time (UTC+2). The same UTC
+			// time is 06:30 in winter, so the window must follow DST.
+			inputTime:      time.Date(2020, time.June, 16, 5, 30, 0, 0, time.UTC),
+			inputCount:     5,
+			expectedCount:  2,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because schedule window night is active",
+			name:           "window during summer time",
+		},
+		{
+			inputConfig: config,
+			// Tuesday 08:30 in Berlin during summer time.
+			inputTime:   time.Date(2020, time.June, 16, 6, 30, 0, 0, time.UTC),
+			inputCount:  5,
+			expectedDir: sdk.ScaleDirectionNone,
+			name:        "window ended during summer time",
+		},
+		{
+			inputConfig:   map[string]string{"timezone": "Mars/Olympus"},
+			inputTime:     time.Now(),
+			expectedError: errors.New("invalid value for `timezone`: unknown time zone Mars/Olympus"),
+			name:          "invalid timezone",
+		},
+		{
+			inputConfig:   map[string]string{"night": "mon-fri 20:00-20:00 count=2"},
+			inputTime:     time.Now(),
+			expectedError: errors.New("invalid schedule window `night`: invalid time range \"20:00-20:00\""),
+			name:          "invalid window",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSchedulePlugin(hclog.NewNullLogger()).(*StrategyPlugin)
+			s.now = func() time.Time { return tc.inputTime }
+
+			eval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{Config: tc.inputConfig},
+				},
+				Action: &sdk.ScalingAction{},
+			}
+
+			actual, err := s.Run(eval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expectedDir, actual.Action.Direction, tc.name)
+			if tc.expectedDir != sdk.ScaleDirectionNone {
+				assert.Equal(t, tc.expectedCount, actual.Action.Count, tc.name)
+				assert.Equal(t, tc.expectedReason, actual.Action.Reason, tc.name)
+			}
+		})
+	}
+}
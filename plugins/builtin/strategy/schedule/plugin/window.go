@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/schedule"
+)
+
+// window is a recurring period of time during which the count, or its limits,
+// are overridden. It is defined using a cron-like format:
+//
+//	<days> <start>-<end> <override> [<override>]
+//
+// Days is "*" or a comma separated list of day names and ranges, such as
+// "mon-fri" or "sat,sun". Start and end are wall clock times in the HH:MM
+// format, with the end being exclusive and 24:00 allowed. A window which ends
+// before it starts crosses midnight and belongs to the day it starts on.
+// Overrides are either count=<n>, or min=<n> and/or max=<n>. For example:
+//
+//	mon-fri 20:00-08:00 count=2
+//	sat,sun 00:00-24:00 min=1 max=3
+//
+// Wall clock times which are skipped when DST starts never match, and times
+// which are repeated when DST ends match both times.
+type window struct {
+	*schedule.Window
+	name string
+
+	count    *int64
+	min, max *int64
+}
+
+// parseWindow parses the window definition.
+func parseWindow(name, def string) (*window, error) {
+	fields := strings.Fields(def)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("expected \"<days> <start>-<end> <override>\", found %q", def)
+	}
+
+	times := strings.SplitN(fields[1], "-", 2)
+	if len(times) != 2 {
+		return nil, fmt.Errorf("invalid time range %q", fields[1])
+	}
+
+	sw, err := schedule.ParseWindow(fields[0], times[0], times[1])
+	if err != nil {
+		return nil, err
+	}
+	w := &window{Window: sw, name: name}
+
+	for _, o := range fields[2:] {
+		if err := w.parseOverride(o); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case w.count != nil && (w.min != nil || w.max != nil):
+		return nil, fmt.Errorf("count can't be combined with min or max")
+	case w.min != nil && w.max != nil && *w.min > *w.max:
+		return nil, fmt.Errorf("min must not be greater than max")
+	}
+
+	return w, nil
+}
+
+// parseOverride parses a single override field of a window.
+func (w *window) parseOverride(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid override %q", s)
+	}
+
+	v, err := strconv.ParseInt(kv[1], 10, 64)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid value for %s: %q", kv[0], kv[1])
+	}
+
+	switch kv[0] {
+	case "count":
+		w.count = &v
+	case "min":
+		w.min = &v
+	case "max":
+		w.max = &v
+	default:
+		return fmt.Errorf("invalid override %q", s)
+	}
+	return nil
+}
+
+// apply returns the count to use while the window is active.
+func (w *window) apply(count int64) int64 {
+	if w.count != nil {
+		return *w.count
+	}
+	if w.min != nil && count < *w.min {
+		return *w.min
+	}
+	if w.max != nil && count > *w.max {
+		return *w.max
+	}
+	return count
+}
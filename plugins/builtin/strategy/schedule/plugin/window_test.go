@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/schedule"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseWindow(t *testing.T) {
+	testCases := []struct {
+		inputDef      string
+		expectedDays  [7]bool
+		expectedStart int
+		expectedEnd   int
+		expectError   bool
+		name          string
+	}{
+		{
+			inputDef:      "* 00:00-24:00 count=1",
+			expectedDays:  [7]bool{true, true, true, true, true, true, true},
+			expectedStart: 0,
+			expectedEnd:   schedule.MinutesPerDay,
+			name:          "every day",
+		},
+		{
+			inputDef:      "fri-mon 18:30-06:15 min=1 max=2",
+			expectedDays:  [7]bool{true, true, false, false, false, true, true},
+			expectedStart: 18*60 + 30,
+			expectedEnd:   6*60 + 15,
+			name:          "range wrapping around the week",
+		},
+		{
+			inputDef:      "Tue,thu 09:00-17:00 max=4",
+			expectedDays:  [7]bool{false, false, true, false, true, false, false},
+			expectedStart: 9 * 60,
+			expectedEnd:   17 * 60,
+			name:          "list of days",
+		},
+		{
+			inputDef:    "mon 09:00-17:00",
+			expectError: true,
+			name:        "missing override",
+		},
+		{
+			inputDef:    "someday 09:00-17:00 count=1",
+			expectError: true,
+			name:        "invalid day",
+		},
+		{
+			inputDef:    "mon 25:00-17:00 count=1",
+			expectError: true,
+			name:        "invalid time",
+		},
+		{
+			inputDef:    "mon 09:00-17:00 count=1 max=2",
+			expectError: true,
+			name:        "count combined with max",
+		},
+		{
+			inputDef:    "mon 09:00-17:00 min=3 max=2",
+			expectError: true,
+			name:        "min greater than max",
+		},
+		{
+			inputDef:    "mon 09:00-17:00 count=-1",
+			expectError: true,
+			name:        "negative count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := parseWindow("test", tc.inputDef)
+			if tc.expectError {
+				assert.Error(t, err, tc.name)
+				return
+			}
+
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedDays, w.Days, tc.name)
+			assert.Equal(t, tc.expectedStart, w.Start, tc.name)
+			assert.Equal(t, tc.expectedEnd, w.End, tc.name)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst strategy
+	// plugins.
+	pluginName = "step"
+
+	// These are the keys read from the RunRequest.Config map.
+	runConfigKeySteps = "steps"
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewStepPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeStrategy,
+	}
+)
+
+// Assert that StrategyPlugin meets the strategy.Strategy and
+// strategy.ConfigValidator interfaces.
+var (
+	_ strategy.Strategy        = (*StrategyPlugin)(nil)
+	_ strategy.ConfigValidator = (*StrategyPlugin)(nil)
+)
+
+// StrategyPlugin is the Step implementation of the strategy.Strategy
+// interface. The config defines contiguous bands of the metric value, each
+// with an adjustment which is applied to the current count when the metric
+// is within the band, similar to the step scaling policies of AWS.
+type StrategyPlugin struct {
+	config map[string]string
+	logger hclog.Logger
+}
+
+// NewStepPlugin returns the Step implementation of the strategy.Strategy
+// interface.
+func NewStepPlugin(log hclog.Logger) strategy.Strategy {
+	return &StrategyPlugin{
+		logger: log,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (s *StrategyPlugin) SetConfig(config map[string]string) error {
+	s.config = config
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (s *StrategyPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// ValidateStrategyConfig satisfies the ValidateStrategyConfig function on the
+// strategy.ConfigValidator interface, so invalid steps are reported when the
+// policy is loaded.
+func (s *StrategyPlugin) ValidateStrategyConfig(config map[string]string) error {
+	_, err := parseConfigSteps(config)
+	return err
+}
+
+// Run satisfies the Run function on the strategy.Strategy interface.
+func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+	steps, err := parseConfigSteps(eval.Check.Strategy.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// This shouldn't happen, but check it just in case.
+	if len(eval.Metrics) == 0 {
+		return nil, nil
+	}
+
+	metric := eval.Metrics[len(eval.Metrics)-1].Value
+	if math.IsNaN(metric) {
+		return nil, fmt.Errorf("invalid metric value: %v", metric)
+	}
+
+	st := steps.find(metric)
+	if st == nil {
+		s.logger.Trace("metric value is outside of all steps",
+			"check_name", eval.Check.Name, "metric_value", metric)
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	newCount := count + st.adjustment
+	if newCount < 0 {
+		newCount = 0
+	}
+
+	switch {
+	case newCount > count:
+		eval.Action.Direction = sdk.ScaleDirectionUp
+	case newCount < count:
+		eval.Action.Direction = sdk.ScaleDirectionDown
+	default:
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		return eval, nil
+	}
+
+	eval.Action.Count = newCount
+	eval.Action.Reason = fmt.Sprintf("scaling %s because metric %.2f is within step %s",
+		eval.Action.Direction, metric, st)
+	return eval, nil
+}
+
+// parseConfigSteps reads and parses the required steps from the strategy
+// config.
+func parseConfigSteps(config map[string]string) (steps, error) {
+	s := config[runConfigKeySteps]
+	if s == "" {
+		return nil, fmt.Errorf("missing required field `steps`")
+	}
+
+	parsed, err := parseSteps(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for `steps`: %v", err)
+	}
+	return parsed, nil
+}
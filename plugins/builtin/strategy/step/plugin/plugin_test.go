@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyPlugin_PluginInfo(t *testing.T) {
+	s := &StrategyPlugin{}
+	expectedOutput := &base.PluginInfo{Name: "step", PluginType: "strategy"}
+	actualOutput, err := s.PluginInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, actualOutput)
+}
+
+func TestStrategyPlugin_Run(t *testing.T) {
+	steps := "..50:-2, 50..80:0, 80..100:+3, 100..:+10"
+
+	testCases := []struct {
+		inputConfig    map[string]string
+		inputMetric    float64
+		inputCount     int64
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig:   map[string]string{},
+			inputMetric:   10,
+			inputCount:    2,
+			expectedError: errors.New("missing required field `steps`"),
+			name:          "missing steps",
+		},
+		{
+			inputConfig:   map[string]string{"steps": "0..50:-2, 60..:+1"},
+			inputMetric:   10,
+			inputCount:    2,
+			expectedError: errors.New("invalid value for `steps`: steps 0..50:-2 and 60..:+1 are not contiguous"),
+			name:          "invalid steps",
+		},
+		{
+			inputConfig:    map[string]string{"steps": steps},
+			inputMetric:    20,
+			inputCount:     5,
+			expectedCount:  3,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because metric 20.00 is within step ..50:-2",
+			name:           "scale in",
+		},
+		{
+			inputConfig:   map[string]string{"steps": steps},
+			inputMetric:   50,
+			inputCount:    5,
+			expectedCount: 0,
+			expectedDir:   sdk.ScaleDirectionNone,
+			name:          "no adjustment at lower bound",
+		},
+		{
+			inputConfig:    map[string]string{"steps": steps},
+			inputMetric:    85,
+			inputCount:     5,
+			expectedCount:  8,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because metric 85.00 is within step 80..100:+3",
+			name:           "scale out",
+		},
+		{
+			inputConfig:    map[string]string{"steps": steps},
+			inputMetric:    250,
+			inputCount:     5,
+			expectedCount:  15,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because metric 250.00 is within step 100..:+10",
+			name:           "scale out in unbounded step",
+		},
+		{
+			inputConfig:    map[string]string{"steps": steps},
+			inputMetric:    20,
+			inputCount:     1,
+			expectedCount:  0,
+			expectedDir:    sdk.ScaleDirectionDown,
+			expectedReason: "scaling down because metric 20.00 is within step ..50:-2",
+			name:           "scale in does not go below zero",
+		},
+		{
+			inputConfig:   map[string]string{"steps": "0..50:-1, 50..100:+1"},
+			inputMetric:   150,
+			inputCount:    5,
+			expectedCount: 0,
+			expectedDir:   sdk.ScaleDirectionNone,
+			name:          "metric outside of steps",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewStepPlugin(hclog.NewNullLogger())
+
+			eval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Name:     "check",
+					Strategy: &sdk.ScalingPolicyStrategy{Name: "step", Config: tc.inputConfig},
+				},
+				Metrics: sdk.TimestampedMetrics{{Value: tc.inputMetric}},
+				Action:  &sdk.ScalingAction{},
+			}
+
+			eval, err := s.Run(eval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if tc.expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expectedDir, eval.Action.Direction, tc.name)
+			assert.Equal(t, tc.expectedCount, eval.Action.Count, tc.name)
+			assert.Equal(t, tc.expectedReason, eval.Action.Reason, tc.name)
+		})
+	}
+}
+
+func TestStrategyPlugin_ValidateStrategyConfig(t *testing.T) {
+	s := NewStepPlugin(hclog.NewNullLogger()).(*StrategyPlugin)
+
+	assert.NoError(t, s.ValidateStrategyConfig(map[string]string{"steps": "..0:-1, 0..:+1"}))
+	assert.EqualError(t, s.ValidateStrategyConfig(map[string]string{}), "missing required field `steps`")
+}
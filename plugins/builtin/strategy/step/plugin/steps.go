@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// step is a band of the metric value, from lower inclusive to upper
+// exclusive, along with the adjustment applied to the count when the metric
+// is within it. Unbounded ends are represented by infinite bounds.
+type step struct {
+	lower      float64
+	upper      float64
+	adjustment int64
+}
+
+// String returns the step in the format it is configured in.
+func (s *step) String() string {
+	var lower, upper string
+	if !math.IsInf(s.lower, -1) {
+		lower = strconv.FormatFloat(s.lower, 'f', -1, 64)
+	}
+	if !math.IsInf(s.upper, 1) {
+		upper = strconv.FormatFloat(s.upper, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s..%s:%+d", lower, upper, s.adjustment)
+}
+
+// steps are contiguous bands sorted by their lower bound.
+type steps []*step
+
+// find returns the step containing the value, or nil if the value is outside
+// of all steps.
+func (s steps) find(v float64) *step {
+	i := sort.Search(len(s), func(i int) bool { return s[i].upper > v })
+	if i == len(s) || v < s[i].lower {
+		return nil
+	}
+	return s[i]
+}
+
+// parseSteps parses a comma separated list of steps in the format
+// lower..upper:adjustment, such as "..50:-2, 50..80:0, 80..100:+3, 100..:+10".
+// Either bound can be omitted to leave that end of the step unbounded. The
+// steps can be listed in any order, but must be contiguous and must not
+// overlap.
+func parseSteps(s string) (steps, error) {
+	var parsed steps
+
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		st, err := parseStep(raw)
+		if err != nil {
+			return nil, fmt.Errorf("step %q is invalid: %v", raw, err)
+		}
+		parsed = append(parsed, st)
+	}
+
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].lower < parsed[j].lower })
+
+	for i := 1; i < len(parsed); i++ {
+		prev, cur := parsed[i-1], parsed[i]
+		switch {
+		case prev.upper > cur.lower:
+			return nil, fmt.Errorf("steps %s and %s overlap", prev, cur)
+		case prev.upper < cur.lower:
+			return nil, fmt.Errorf("steps %s and %s are not contiguous", prev, cur)
+		}
+	}
+	return parsed, nil
+}
+
+// parseStep parses a single step in the format lower..upper:adjustment.
+func parseStep(s string) (*step, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("must be in the format lower..upper:adjustment")
+	}
+	bounds, adj := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+
+	parts := strings.Split(bounds, "..")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("must be in the format lower..upper:adjustment")
+	}
+
+	st := &step{lower: math.Inf(-1), upper: math.Inf(1)}
+
+	var err error
+	if l := strings.TrimSpace(parts[0]); l != "" {
+		if st.lower, err = parseBound(l); err != nil {
+			return nil, fmt.Errorf("lower bound %q is not a number", l)
+		}
+	}
+	if u := strings.TrimSpace(parts[1]); u != "" {
+		if st.upper, err = parseBound(u); err != nil {
+			return nil, fmt.Errorf("upper bound %q is not a number", u)
+		}
+	}
+	if st.lower >= st.upper {
+		return nil, fmt.Errorf("lower bound must be less than upper bound")
+	}
+
+	if st.adjustment, err = strconv.ParseInt(adj, 10, 64); err != nil {
+		return nil, fmt.Errorf("adjustment %q is not an integer", adj)
+	}
+	return st, nil
+}
+
+// parseBound parses a finite step bound.
+func parseBound(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid bound %q", s)
+	}
+	return f, nil
+}
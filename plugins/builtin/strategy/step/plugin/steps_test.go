@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseSteps(t *testing.T) {
+	testCases := []struct {
+		inputSteps     string
+		expectedOutput steps
+		expectedError  error
+		name           string
+	}{
+		{
+			inputSteps: "80..100:+3, ..50:-2, 100..:10, 50..80:0",
+			expectedOutput: steps{
+				{lower: math.Inf(-1), upper: 50, adjustment: -2},
+				{lower: 50, upper: 80, adjustment: 0},
+				{lower: 80, upper: 100, adjustment: 3},
+				{lower: 100, upper: math.Inf(1), adjustment: 10},
+			},
+			name: "unordered steps",
+		},
+		{
+			inputSteps: "-10.5..0.5:-1, 0.5..:1",
+			expectedOutput: steps{
+				{lower: -10.5, upper: 0.5, adjustment: -1},
+				{lower: 0.5, upper: math.Inf(1), adjustment: 1},
+			},
+			name: "negative and decimal bounds",
+		},
+		{
+			inputSteps:    "0..50:-1, 40..100:+1",
+			expectedError: errors.New("steps 0..50:-1 and 40..100:+1 overlap"),
+			name:          "overlapping steps",
+		},
+		{
+			inputSteps:    "..50:-1, ..100:+1",
+			expectedError: errors.New("steps ..50:-1 and ..100:+1 overlap"),
+			name:          "multiple unbounded lower steps",
+		},
+		{
+			inputSteps:    "0..50:-1, 60..100:+1",
+			expectedError: errors.New("steps 0..50:-1 and 60..100:+1 are not contiguous"),
+			name:          "gap between steps",
+		},
+		{
+			inputSteps:    "50..0:1",
+			expectedError: errors.New(`step "50..0:1" is invalid: lower bound must be less than upper bound`),
+			name:          "inverted bounds",
+		},
+		{
+			inputSteps:    "0..50",
+			expectedError: errors.New(`step "0..50" is invalid: must be in the format lower..upper:adjustment`),
+			name:          "missing adjustment",
+		},
+		{
+			inputSteps:    "0..fifty:1",
+			expectedError: errors.New(`step "0..fifty:1" is invalid: upper bound "fifty" is not a number`),
+			name:          "invalid bound",
+		},
+		{
+			inputSteps:    "0..50:1.5",
+			expectedError: errors.New(`step "0..50:1.5" is invalid: adjustment "1.5" is not an integer`),
+			name:          "invalid adjustment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := parseSteps(tc.inputSteps)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
+
+func Test_steps_find(t *testing.T) {
+	s, err := parseSteps("0..50:-1, 50..100:+1")
+	assert.NoError(t, err)
+
+	assert.Nil(t, s.find(-1))
+	assert.Equal(t, s[0], s.find(0))
+	assert.Equal(t, s[1], s.find(50))
+	assert.Equal(t, s[1], s.find(99.9))
+	assert.Nil(t, s.find(100))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad-autoscaler/plugins"
@@ -18,12 +19,21 @@ const (
 	pluginName = "target-value"
 
 	// These are the keys read from the RunRequest.Config map.
-	runConfigKeyTarget    = "target"
-	runConfigKeyThreshold = "threshold"
+	runConfigKeyTarget         = "target"
+	runConfigKeyThreshold      = "threshold"
+	runConfigKeyBootstrapCount = "bootstrap_count"
+	runConfigKeyDeadzone       = "deadzone"
+	runConfigKeyInvert         = "invert"
 
 	// defaultThreshold controls how significant is a change in the input
 	// metric value.
 	defaultThreshold = "0.01"
+
+	// maxInvertedFactor is the largest factor calculated for inverted
+	// metrics. The factor grows without bound as the metric approaches zero,
+	// and a metric at zero, such as no free memory, is the maximum pressure
+	// rather than an invalid value.
+	maxInvertedFactor = 10
 )
 
 var (
@@ -96,6 +106,44 @@ func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sd
 		return nil, fmt.Errorf("invalid value for `threshold`: %v (%T)", th, th)
 	}
 
+	// Read and parse the optional bootstrap count from req.Config. This is
+	// the count used when scaling from 0, where the current count can't be
+	// used to proportionally calculate the new count.
+	var bootstrapCount int64
+
+	if bc := eval.Check.Strategy.Config[runConfigKeyBootstrapCount]; bc != "" {
+		bootstrapCount, _, err = eval.Check.Strategy.ConfigInt(runConfigKeyBootstrapCount)
+		if err != nil || bootstrapCount < 1 {
+			return nil, fmt.Errorf("invalid value for `bootstrap_count`: %v (%T)", bc, bc)
+		}
+	}
+
+	// Read and parse the optional deadzone from req.Config. No action is
+	// taken while the metric is within the deadzone around the target.
+	var deadzone float64
+
+	if dz := eval.Check.Strategy.Config[runConfigKeyDeadzone]; dz != "" {
+		deadzone, err = parseDeadzone(dz, target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for `deadzone`: %v (%T)", dz, dz)
+		}
+	}
+
+	// Read and parse the optional invert flag from req.Config. Inverted
+	// metrics, such as free memory, fall as load increases so the target
+	// scales up when the metric is below the target.
+	var invert bool
+
+	if inv := eval.Check.Strategy.Config[runConfigKeyInvert]; inv != "" {
+		invert, err = strconv.ParseBool(inv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for `invert`: %v (%T)", inv, inv)
+		}
+		if invert && target == 0 {
+			return nil, fmt.Errorf("`target` can't be 0 when `invert` is set")
+		}
+	}
+
 	var factor float64
 
 	// This shouldn't happen, but check it just in case.
@@ -106,34 +154,75 @@ func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sd
 	// Use only the latest value for now.
 	metric := eval.Metrics[len(eval.Metrics)-1]
 
+	// A NaN or infinite metric would result in a nonsensical count, so do not
+	// attempt to calculate one.
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+		return nil, fmt.Errorf("invalid metric value: %v", metric.Value)
+	}
+
+	// Take no action while the metric is within the deadzone, which avoids
+	// flapping around the target for noisy metrics.
+	if deadzone > 0 && math.Abs(metric.Value-target) <= deadzone {
+		s.logger.Trace("metric value within deadzone",
+			"check_name", eval.Check.Name, "metric_value", metric.Value, "target", target, "deadzone", deadzone)
+		eval.Action.Direction = sdk.ScaleDirectionNone
+		eval.Action.SetNoActionReason(sdk.NoActionReasonDeadzone)
+		return eval, nil
+	}
+
 	// Handle cases where the specified target is 0. A potential use case here
 	// is targeting a CI build queue to be 0. Adding in build agents when the
 	// queue has greater than 0 items in it.
-	switch target {
-	case 0:
+	//
+	// Inverted metrics use the reciprocal factor, so a metric below the
+	// target results in a factor above 1. The factor is capped, so the target
+	// scales up by at most maxInvertedFactor at a time while the metric is at
+	// or near zero.
+	switch {
+	case invert && (metric.Value <= 0 || target/metric.Value > maxInvertedFactor):
+		factor = maxInvertedFactor
+	case invert:
+		factor = target / metric.Value
+	case target == 0:
 		factor = metric.Value
 	default:
 		factor = metric.Value / target
 	}
 
+	// Report the deviation whether or not the target is scaled, so results
+	// which keep the current count can be told apart by how close the metric
+	// is to the target, such as by the policy scale in bias.
+	eval.Action.SetDeviation(factor)
+
 	// Identify the direction of scaling, if any.
 	eval.Action.Direction = s.calculateDirection(count, factor, threshold)
 	if eval.Action.Direction == sdk.ScaleDirectionNone {
 		return eval, nil
 	}
 
-	var newCount int64
+	var desiredCount float64
 
 	// Handle cases were users wish to scale from 0. If the current count is 0,
-	// then just use the factor as the new count to target. Otherwise use our
-	// standard calculation.
-	switch count {
-	case 0:
-		newCount = int64(math.Ceil(factor))
+	// then use the bootstrap count if configured, otherwise use the factor as
+	// the new count to target. Otherwise use our standard calculation. The
+	// count is checked as well, for callers which do not set ScaleFromZero.
+	scaleFromZero := count == 0 || eval.ScaleFromZero
+	switch {
+	case scaleFromZero && bootstrapCount > 0:
+		desiredCount = float64(bootstrapCount)
+	case scaleFromZero:
+		desiredCount = math.Ceil(factor)
 	default:
-		newCount = int64(math.Ceil(float64(count) * factor))
+		desiredCount = math.Ceil(float64(count) * factor)
 	}
 
+	// Converting a count which does not fit in an int64 is undefined, and
+	// would most likely result in a negative count.
+	if desiredCount >= math.MaxInt64 {
+		return nil, fmt.Errorf("calculated count %g is too large, factor is %f", desiredCount, factor)
+	}
+	newCount := int64(desiredCount)
+
 	// Log at trace level the details of the strategy calculation. This is
 	// helpful in ultra-debugging situations when there is a need to understand
 	// all the calculations made.
@@ -155,6 +244,25 @@ func (s *StrategyPlugin) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sd
 	return eval, nil
 }
 
+// parseDeadzone parses the deadzone config value, returning the absolute
+// tolerance around the target. The value is either an absolute number, such
+// as "5", or a percentage of the target, such as "10%".
+func parseDeadzone(dz string, target float64) (float64, error) {
+	if p := strings.TrimSuffix(dz, "%"); p != dz {
+		percent, err := strconv.ParseFloat(p, 64)
+		if err != nil || percent < 0 {
+			return 0, fmt.Errorf("invalid percentage")
+		}
+		return math.Abs(target) * percent / 100, nil
+	}
+
+	deadzone, err := strconv.ParseFloat(dz, 64)
+	if err != nil || deadzone < 0 {
+		return 0, fmt.Errorf("invalid value")
+	}
+	return deadzone, nil
+}
+
 // calculateDirection is used to calculate the direction of scaling that should
 // occur, if any at all. It takes into account the current task group count in
 // order to correctly account for 0 counts.
@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -69,6 +71,116 @@ func TestStrategyPlugin_Run(t *testing.T) {
 			expectedError: fmt.Errorf("invalid value for `threshold`: not-the-float-you're-looking-for (string)"),
 			name:          "incorrect input strategy config threshold value",
 		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "0", "bootstrap_count": "0"},
+					},
+				},
+			},
+			expectedResp:  nil,
+			expectedError: fmt.Errorf("invalid value for `bootstrap_count`: 0 (string)"),
+			name:          "incorrect input strategy config bootstrap count value",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: math.Inf(1)}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			expectedResp:  nil,
+			expectedError: fmt.Errorf("invalid metric value: +Inf"),
+			name:          "infinite metric value",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 35}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70", "bootstrap_count": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 0,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 35}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70", "bootstrap_count": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     3,
+					Reason:    "scaling up because factor is 0.500000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.5},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "scale from 0 using bootstrap count",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 35}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70", "bootstrap_count": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 2,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 35}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70", "bootstrap_count": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     1,
+					Reason:    "scaling down because factor is 0.500000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.5},
+					Direction: sdk.ScaleDirectionDown,
+				},
+			},
+			expectedError: nil,
+			name:          "bootstrap count ignored with non-zero count",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0.5}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 0,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0.5}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "70"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     1,
+					Reason:    "scaling up because factor is 0.007143",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.007142857142857143},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "scale from 0 with small metric value",
+		},
 		{
 			inputEval: &sdk.ScalingCheckEvaluation{
 				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 13}},
@@ -89,6 +201,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				},
 				Action: &sdk.ScalingAction{
 					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0},
 				},
 			},
 			expectedError: nil,
@@ -115,6 +228,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				Action: &sdk.ScalingAction{
 					Count:     4,
 					Reason:    "scaling up because factor is 2.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 2.0},
 					Direction: sdk.ScaleDirectionUp,
 				},
 			},
@@ -142,6 +256,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				Action: &sdk.ScalingAction{
 					Count:     2,
 					Reason:    "scaling up because factor is 2.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 2.0},
 					Direction: sdk.ScaleDirectionUp,
 				},
 			},
@@ -168,6 +283,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				},
 				Action: &sdk.ScalingAction{
 					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.9},
 				},
 			},
 			expectedError: nil,
@@ -194,6 +310,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				Action: &sdk.ScalingAction{
 					Count:     1,
 					Reason:    "scaling up because factor is 0.100000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.1},
 					Direction: sdk.ScaleDirectionUp,
 				},
 			},
@@ -222,6 +339,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 					Count:     0,
 					Direction: sdk.ScaleDirectionDown,
 					Reason:    "scaling down because factor is 0.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.0},
 				},
 			},
 			expectedError: nil,
@@ -247,6 +365,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				},
 				Action: &sdk.ScalingAction{
 					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0000019999999998},
 				},
 			},
 			expectedError: nil,
@@ -273,6 +392,7 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				Action: &sdk.ScalingAction{
 					Count:     9,
 					Reason:    "scaling up because factor is 1.000002",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0000019999999998},
 					Direction: sdk.ScaleDirectionUp,
 				},
 			},
@@ -309,11 +429,240 @@ func TestStrategyPlugin_Run(t *testing.T) {
 				},
 				Action: &sdk.ScalingAction{
 					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0},
 				},
 			},
 			expectedError: nil,
 			name:          "properly handle multiple input",
 		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "deadzone": "-5%"},
+					},
+				},
+			},
+			expectedResp:  nil,
+			expectedError: errors.New("invalid value for `deadzone`: -5% (string)"),
+			name:          "incorrect input strategy config deadzone value",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 13}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "deadzone": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 4,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 13}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "deadzone": "3"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.no_action_reason": "deadzone"},
+				},
+			},
+			expectedError: nil,
+			name:          "no action within absolute deadzone",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 72}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "80", "deadzone": "10%"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 10,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 72}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "80", "deadzone": "10%"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Direction: sdk.ScaleDirectionNone,
+					Meta:      map[string]interface{}{"nomad_autoscaler.no_action_reason": "deadzone"},
+				},
+			},
+			expectedError: nil,
+			name:          "no action within percentage deadzone",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 20}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "deadzone": "5"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 2,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 20}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "deadzone": "5"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     4,
+					Reason:    "scaling up because factor is 2.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 2.0},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "scale up outside deadzone",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 10}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 3,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 10}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     6,
+					Reason:    "scaling up because factor is 2.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 2.0},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "scale up when inverted metric falls below target",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 40}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 4,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 40}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     2,
+					Reason:    "scaling down because factor is 0.500000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.5},
+					Direction: sdk.ScaleDirectionDown,
+				},
+			},
+			expectedError: nil,
+			name:          "scale down when inverted metric rises above target",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 4,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     40,
+					Reason:    "scaling up because factor is 10.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 10.0},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "zero inverted metric value scales up by the maximum factor",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0.5}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{},
+			},
+			inputCount: 4,
+			expectedResp: &sdk.ScalingCheckEvaluation{
+				Metrics: sdk.TimestampedMetrics{sdk.TimestampedMetric{Value: 0.5}},
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "20", "invert": "true"},
+					},
+				},
+				Action: &sdk.ScalingAction{
+					Count:     40,
+					Reason:    "scaling up because factor is 10.000000",
+					Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 10.0},
+					Direction: sdk.ScaleDirectionUp,
+				},
+			},
+			expectedError: nil,
+			name:          "inverted metric value near zero is capped by the maximum factor",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "0", "invert": "true"},
+					},
+				},
+			},
+			expectedResp:  nil,
+			expectedError: errors.New("`target` can't be 0 when `invert` is set"),
+			name:          "inverted zero target",
+		},
+		{
+			inputEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Config: map[string]string{"target": "10", "invert": "sometimes"},
+					},
+				},
+			},
+			expectedResp:  nil,
+			expectedError: errors.New("invalid value for `invert`: sometimes (string)"),
+			name:          "incorrect input strategy config invert value",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -326,6 +675,62 @@ func TestStrategyPlugin_Run(t *testing.T) {
 	}
 }
 
+func TestStrategyPlugin_Run_countMatrix(t *testing.T) {
+	s := &StrategyPlugin{logger: hclog.NewNullLogger()}
+
+	testCases := []struct {
+		inputCount        int64
+		inputMetric       float64
+		inputBootstrap    string
+		expectedDirection sdk.ScaleDirection
+		expectedCount     int64
+		expectedError     bool
+		name              string
+	}{
+		{inputCount: 0, inputMetric: 0, expectedDirection: sdk.ScaleDirectionNone, name: "zero count and zero metric"},
+		{inputCount: 0, inputMetric: 50, expectedDirection: sdk.ScaleDirectionUp, expectedCount: 1, name: "zero count and metric below target"},
+		{inputCount: 0, inputMetric: 100, expectedDirection: sdk.ScaleDirectionUp, expectedCount: 1, name: "zero count and metric at target"},
+		{inputCount: 0, inputMetric: 250, expectedDirection: sdk.ScaleDirectionUp, expectedCount: 3, name: "zero count and metric above target"},
+		{inputCount: 0, inputMetric: 50, inputBootstrap: "4", expectedDirection: sdk.ScaleDirectionUp, expectedCount: 4, name: "zero count with bootstrap count"},
+		{inputCount: 0, inputMetric: 1e300, expectedError: true, name: "zero count and huge metric"},
+		{inputCount: 1, inputMetric: 0, expectedDirection: sdk.ScaleDirectionDown, expectedCount: 0, name: "one count and zero metric"},
+		{inputCount: 1, inputMetric: 50, expectedDirection: sdk.ScaleDirectionNone, name: "one count and metric below target"},
+		{inputCount: 1, inputMetric: 100, expectedDirection: sdk.ScaleDirectionNone, name: "one count and metric at target"},
+		{inputCount: 1, inputMetric: 250, expectedDirection: sdk.ScaleDirectionUp, expectedCount: 3, name: "one count and metric above target"},
+		{inputCount: 1, inputMetric: 1e300, expectedError: true, name: "one count and huge metric"},
+		{inputCount: 1000000, inputMetric: 0, expectedDirection: sdk.ScaleDirectionDown, expectedCount: 0, name: "large count and zero metric"},
+		{inputCount: 1000000, inputMetric: 50, expectedDirection: sdk.ScaleDirectionDown, expectedCount: 500000, name: "large count and metric below target"},
+		{inputCount: 1000000, inputMetric: 100, expectedDirection: sdk.ScaleDirectionNone, name: "large count and metric at target"},
+		{inputCount: 1000000, inputMetric: 250, expectedDirection: sdk.ScaleDirectionUp, expectedCount: 2500000, name: "large count and metric above target"},
+		{inputCount: 1000000, inputMetric: 1e300, expectedError: true, name: "large count and huge metric"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := map[string]string{"target": "100"}
+			if tc.inputBootstrap != "" {
+				config["bootstrap_count"] = tc.inputBootstrap
+			}
+			eval := &sdk.ScalingCheckEvaluation{
+				Check:         &sdk.ScalingPolicyCheck{Strategy: &sdk.ScalingPolicyStrategy{Config: config}},
+				Metrics:       sdk.TimestampedMetrics{{Value: tc.inputMetric}},
+				ScaleFromZero: tc.inputCount == 0,
+				Action:        &sdk.ScalingAction{},
+			}
+
+			actualResp, err := s.Run(eval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+			if tc.expectedError {
+				return
+			}
+			assert.Equal(t, tc.expectedDirection, actualResp.Action.Direction, tc.name)
+			if tc.expectedDirection != sdk.ScaleDirectionNone {
+				assert.Equal(t, tc.expectedCount, actualResp.Action.Count, tc.name)
+			}
+		})
+	}
+}
+
 func TestStrategyPlugin_calculateDirection(t *testing.T) {
 	testCases := []struct {
 		inputCount     int64
@@ -349,3 +754,33 @@ func TestStrategyPlugin_calculateDirection(t *testing.T) {
 		assert.Equal(t, tc.expectedOutput, s.calculateDirection(tc.inputCount, tc.inputFactor, tc.threshold))
 	}
 }
+
+func Test_parseDeadzone(t *testing.T) {
+	testCases := []struct {
+		inputDeadzone  string
+		inputTarget    float64
+		expectedOutput float64
+		expectError    bool
+		name           string
+	}{
+		{inputDeadzone: "5", inputTarget: 80, expectedOutput: 5, name: "absolute"},
+		{inputDeadzone: "10%", inputTarget: 80, expectedOutput: 8, name: "percentage"},
+		{inputDeadzone: "10%", inputTarget: -80, expectedOutput: 8, name: "percentage of negative target"},
+		{inputDeadzone: "0", inputTarget: 80, expectedOutput: 0, name: "zero"},
+		{inputDeadzone: "-1", inputTarget: 80, expectError: true, name: "negative absolute"},
+		{inputDeadzone: "abc%", inputTarget: 80, expectError: true, name: "invalid percentage"},
+		{inputDeadzone: "abc", inputTarget: 80, expectError: true, name: "invalid absolute"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := parseDeadzone(tc.inputDeadzone, tc.inputTarget)
+			if tc.expectError {
+				assert.Error(t, err, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
@@ -17,6 +17,11 @@ import (
 const (
 	defaultRetryInterval = 10 * time.Second
 	defaultRetryLimit    = 15
+
+	// nodeRegisterRetryLimit is the retry limit used when waiting for new
+	// instances to register with Nomad. This is higher than the default as
+	// instances need to boot and start the Nomad client after launching.
+	nodeRegisterRetryLimit = 30
 )
 
 // setupAWSClients takes the passed config mapping and instantiates the
@@ -65,7 +70,7 @@ func (t *TargetPlugin) setupAWSClients(config map[string]string) error {
 
 // scaleOut updates the Auto Scaling Group desired count to match what the
 // Autoscaler has deemed required.
-func (t *TargetPlugin) scaleOut(ctx context.Context, asg *autoscaling.AutoScalingGroup, count int64) error {
+func (t *TargetPlugin) scaleOut(ctx context.Context, asg *autoscaling.AutoScalingGroup, count int64, config map[string]string) error {
 
 	// Create a logger for this action to pre-populate useful information we
 	// would like on all log lines.
@@ -87,6 +92,11 @@ func (t *TargetPlugin) scaleOut(ctx context.Context, asg *autoscaling.AutoScalin
 	if err := t.ensureASGInstancesCount(ctx, count, *asg.AutoScalingGroupName); err != nil {
 		return fmt.Errorf("failed to confirm scale out AWS AutoScaling Group: %v", err)
 	}
+	log.Debug("AutoScaling Group instances launched, waiting for Nomad nodes to register")
+
+	if err := t.ensureNomadNodesReady(ctx, count, config); err != nil {
+		return fmt.Errorf("failed to confirm Nomad nodes registered: %v", err)
+	}
 
 	log.Info("successfully performed and verified scaling out")
 	return nil
@@ -360,3 +370,31 @@ func (t *TargetPlugin) ensureASGInstancesCount(ctx context.Context, desired int6
 
 	return retry(ctx, defaultRetryInterval, defaultRetryLimit, f)
 }
+
+// ensureNomadNodesReady waits until the Nomad node pool identified by the
+// config contains at least the desired number of ready nodes.
+func (t *TargetPlugin) ensureNomadNodesReady(ctx context.Context, desired int64, config map[string]string) error {
+
+	class, ok := config[sdk.TargetConfigKeyClass]
+	if !ok {
+		return fmt.Errorf("required config param %q not found", sdk.TargetConfigKeyClass)
+	}
+	id := scaleutils.PoolIdentifier{IdentifierKey: scaleutils.IdentifierKeyClass, Value: class}
+
+	f := func(ctx context.Context) (bool, error) {
+
+		// An error indicates the pool is unstable, which is expected while
+		// new nodes are initializing, so retry rather than fail.
+		ready, err := t.scaleInUtils.ReadyNodeCount(id)
+		if err != nil {
+			return false, err
+		}
+
+		if int64(ready) >= desired {
+			return true, nil
+		}
+		return false, fmt.Errorf("Nomad node pool at %v ready nodes of desired %v", ready, desired)
+	}
+
+	return retry(ctx, defaultRetryInterval, nodeRegisterRetryLimit, f)
+}
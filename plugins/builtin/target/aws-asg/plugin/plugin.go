@@ -120,7 +120,7 @@ func (t *TargetPlugin) Scale(action sdk.ScalingAction, config map[string]string)
 	case "in":
 		err = t.scaleIn(ctx, curASG, num, config)
 	case "out":
-		err = t.scaleOut(ctx, curASG, num)
+		err = t.scaleOut(ctx, curASG, num, config)
 	default:
 		t.logger.Info("scaling not required", "asg_name", asgName,
 			"current_count", *curASG.DesiredCapacity, "strategy_count", action.Count)
@@ -182,6 +182,10 @@ func (t *TargetPlugin) Status(config map[string]string) (*sdk.TargetStatus, erro
 		Meta:  make(map[string]string),
 	}
 
+	// Report the desired and running capacity of the ASG, so the autoscaler
+	// can identify when instances are failing to launch.
+	processCapacity(asg, &resp)
+
 	// If we have previous activities then process the last.
 	if len(events) > 0 {
 		processLastActivity(events[0], &resp)
@@ -201,6 +205,22 @@ func (t *TargetPlugin) calculateDirection(asgDesired, strategyDesired int64) (in
 	return 0, ""
 }
 
+// processCapacity updates the status object with the desired and running
+// capacity of the ASG. Only instances which are in service are considered to
+// be running.
+func processCapacity(asg *autoscaling.AutoScalingGroup, status *sdk.TargetStatus) {
+
+	var running int64
+	for _, instance := range asg.Instances {
+		if instance.LifecycleState == autoscaling.LifecycleStateInService {
+			running++
+		}
+	}
+
+	status.Meta[sdk.TargetStatusMetaKeyDesiredCount] = strconv.FormatInt(*asg.DesiredCapacity, 10)
+	status.Meta[sdk.TargetStatusMetaKeyRunningCount] = strconv.FormatInt(running, 10)
+}
+
 // processLastActivity updates the status object based on the details within
 // the last scaling activity.
 func processLastActivity(activity autoscaling.Activity, status *sdk.TargetStatus) {
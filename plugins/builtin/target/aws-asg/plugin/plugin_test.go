@@ -120,6 +120,48 @@ func Test_processLastActivity(t *testing.T) {
 	}
 }
 
+func Test_processCapacity(t *testing.T) {
+	testCases := []struct {
+		inputASG     *autoscaling.AutoScalingGroup
+		expectedMeta map[string]string
+		name         string
+	}{
+		{
+			inputASG: &autoscaling.AutoScalingGroup{
+				DesiredCapacity: int64ToPtr(3),
+				Instances: []autoscaling.Instance{
+					{LifecycleState: autoscaling.LifecycleStateInService},
+					{LifecycleState: autoscaling.LifecycleStatePending},
+					{LifecycleState: autoscaling.LifecycleStateInService},
+				},
+			},
+			expectedMeta: map[string]string{
+				"nomad_autoscaler.count.desired": "3",
+				"nomad_autoscaler.count.running": "2",
+			},
+			name: "instances pending",
+		},
+		{
+			inputASG: &autoscaling.AutoScalingGroup{
+				DesiredCapacity: int64ToPtr(0),
+			},
+			expectedMeta: map[string]string{
+				"nomad_autoscaler.count.desired": "0",
+				"nomad_autoscaler.count.running": "0",
+			},
+			name: "no instances",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &sdk.TargetStatus{Meta: map[string]string{}}
+			processCapacity(tc.inputASG, status)
+			assert.Equal(t, tc.expectedMeta, status.Meta, tc.name)
+		})
+	}
+}
+
 func int64ToPtr(v int64) *int64 {
 	return &v
 }
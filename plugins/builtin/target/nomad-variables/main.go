@@ -0,0 +1,16 @@
+package main
+
+import (
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	nomadVariables "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/nomad-variables/plugin"
+)
+
+func main() {
+	plugins.Serve(factory)
+}
+
+// factory returns a new instance of the Nomad Variables Target plugin.
+func factory(log hclog.Logger) interface{} {
+	return nomadVariables.NewNomadVariablesPlugin(log)
+}
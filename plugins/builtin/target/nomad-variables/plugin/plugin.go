@@ -0,0 +1,308 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/target"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// pluginName is the unique name of the this plugin amongst target
+	// plugins.
+	pluginName = "nomad-variables"
+
+	// configKeys are the accepted configuration map keys which can be
+	// processed when performing SetConfig().
+	configKeyPath       = "path"
+	configKeyNamespace  = "namespace"
+	configKeyRegion     = "region"
+	configKeyDesiredKey = "desired_key"
+	configKeyActualKey  = "actual_key"
+
+	// defaultDesiredKey and defaultActualKey are the variable items used to
+	// publish the desired count and read the actual count when the target
+	// does not configure them.
+	defaultDesiredKey = "desired_count"
+	defaultActualKey  = "count"
+
+	// itemKeyReason and itemKeyScaledAt are the variable items written
+	// alongside the desired count, so external actuators can surface why and
+	// when the count was changed.
+	itemKeyReason   = "reason"
+	itemKeyScaledAt = "scaled_at"
+
+	// scaleRetryAttempts is the number of times a write will be attempted
+	// when the variable was modified concurrently or the Nomad API returns a
+	// transient error.
+	scaleRetryAttempts = 3
+
+	// Backoff values used when retrying failed writes to the Nomad API.
+	scaleBackoffBase  = 1 * time.Second
+	scaleBackoffLimit = 5 * time.Second
+)
+
+var (
+	PluginID = plugins.PluginID{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeTarget,
+	}
+
+	PluginConfig = &plugins.InternalPluginConfig{
+		Factory: func(l hclog.Logger) interface{} { return NewNomadVariablesPlugin(l) },
+	}
+
+	pluginInfo = &base.PluginInfo{
+		Name:       pluginName,
+		PluginType: sdk.PluginTypeTarget,
+	}
+)
+
+// Assert that TargetPlugin meets the target.Target and
+// target.ConfigSchemaProvider interfaces.
+var (
+	_ target.Target               = (*TargetPlugin)(nil)
+	_ target.ConfigSchemaProvider = (*TargetPlugin)(nil)
+)
+
+// TargetPlugin is the Nomad Variables implementation of the target.Target
+// interface. Instead of scaling a resource, it publishes the desired count
+// to a Nomad Variable so an external system can actuate it, and reads the
+// actual count back from the same variable.
+type TargetPlugin struct {
+	clients *nomadHelper.ClientPool
+	logger  hclog.Logger
+}
+
+// variable is the subset of a Nomad Variable used by the plugin.
+type variable struct {
+	Namespace   string
+	Path        string
+	Items       map[string]string
+	ModifyIndex uint64
+}
+
+// NewNomadVariablesPlugin returns the Nomad Variables implementation of the
+// target.Target interface.
+func NewNomadVariablesPlugin(log hclog.Logger) *TargetPlugin {
+	return &TargetPlugin{
+		logger: log,
+	}
+}
+
+// SetConfig satisfies the SetConfig function on the base.Base interface.
+func (t *TargetPlugin) SetConfig(config map[string]string) error {
+
+	cfg := nomadHelper.ConfigFromNamespacedMap(config)
+	clients := nomadHelper.NewClientPool(cfg, nomadHelper.RegionConfigsFromNamespacedMap(config))
+
+	// Create the client for the default region up front, so invalid config
+	// is reported when the plugin is configured.
+	if _, err := clients.Client(""); err != nil {
+		return err
+	}
+	t.clients = clients
+
+	return nil
+}
+
+// PluginInfo satisfies the PluginInfo function on the base.Base interface.
+func (t *TargetPlugin) PluginInfo() (*base.PluginInfo, error) {
+	return pluginInfo, nil
+}
+
+// ConfigSchema satisfies the ConfigSchema function on the
+// target.ConfigSchemaProvider interface.
+func (t *TargetPlugin) ConfigSchema() *sdk.TargetConfigSchema {
+	return &sdk.TargetConfigSchema{
+		Keys: map[string]*sdk.TargetConfigKey{
+			configKeyPath:       {Type: sdk.TargetConfigTypeString, Required: true},
+			configKeyNamespace:  {Type: sdk.TargetConfigTypeString},
+			configKeyRegion:     {Type: sdk.TargetConfigTypeString},
+			configKeyDesiredKey: {Type: sdk.TargetConfigTypeString},
+			configKeyActualKey:  {Type: sdk.TargetConfigTypeString},
+		},
+	}
+}
+
+// Scale satisfies the Scale function on the target.Target interface.
+func (t *TargetPlugin) Scale(action sdk.ScalingAction, config map[string]string) error {
+
+	// There is nothing to publish for dry-run actions.
+	if action.Count == sdk.StrategyActionMetaValueDryRunCount {
+		return nil
+	}
+
+	path, err := variablePath(config)
+	if err != nil {
+		return err
+	}
+
+	client, err := t.clients.Client(config[configKeyRegion])
+	if err != nil {
+		return err
+	}
+	namespace := variableNamespace(config)
+	desiredKey := configValueOrDefault(config, configKeyDesiredKey, defaultDesiredKey)
+
+	// The variable is written using check-and-set, so items written by the
+	// external actuator are never lost. Conflicting writes are retried with
+	// the latest version of the variable.
+	backoff := nomadHelper.NewBackoff(scaleBackoffBase, scaleBackoffLimit)
+
+	err = nomadHelper.Retry(context.Background(), scaleRetryAttempts, backoff, func() error {
+		v, err := readVariable(client, namespace, path)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			v = &variable{Namespace: namespace, Path: path}
+		}
+		if v.Items == nil {
+			v.Items = make(map[string]string)
+		}
+
+		v.Items[desiredKey] = strconv.FormatInt(action.Count, 10)
+		v.Items[itemKeyReason] = action.Reason
+		v.Items[itemKeyScaledAt] = strconv.FormatInt(time.Now().UTC().UnixNano(), 10)
+
+		endpoint := fmt.Sprintf("%s?cas=%d", variableEndpoint(path), v.ModifyIndex)
+		_, err = client.Raw().Write(endpoint, v, nil, &api.WriteOptions{Namespace: namespace})
+		// Strip the response code from conflicts, so they are retried.
+		if nomadHelper.ResponseCode(err) == http.StatusConflict {
+			return errors.New("variable was modified concurrently")
+		}
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to write variable %s: %v", path, err)
+	}
+
+	t.logger.Debug("published desired count to Nomad variable", "path", path,
+		"namespace", namespace, "count", action.Count, "correlation_id", action.CorrelationID())
+	return nil
+}
+
+// Status satisfies the Status function on the target.Target interface.
+func (t *TargetPlugin) Status(config map[string]string) (*sdk.TargetStatus, error) {
+
+	path, err := variablePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := t.clients.Client(config[configKeyRegion])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := readVariable(client, variableNamespace(config), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variable %s: %v", path, err)
+	}
+
+	// A missing variable has not been published yet, so report a count of
+	// zero and allow the first desired count to be written.
+	if v == nil {
+		return &sdk.TargetStatus{Ready: true, Count: 0, Meta: make(map[string]string)}, nil
+	}
+
+	return variableStatus(v, config)
+}
+
+// variableStatus builds the target status from the items of the variable.
+// The target is only ready once the external actuator has reported an
+// actual count matching the last desired count.
+func variableStatus(v *variable, config map[string]string) (*sdk.TargetStatus, error) {
+	desiredKey := configValueOrDefault(config, configKeyDesiredKey, defaultDesiredKey)
+	actualKey := configValueOrDefault(config, configKeyActualKey, defaultActualKey)
+
+	resp := &sdk.TargetStatus{Meta: make(map[string]string)}
+
+	actualStr, actualOK := v.Items[actualKey]
+	if actualOK {
+		actual, err := strconv.ParseInt(actualStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse item %q of variable %s: %v", actualKey, v.Path, err)
+		}
+		resp.Count = actual
+		resp.Meta[sdk.TargetStatusMetaKeyRunningCount] = actualStr
+	}
+
+	desiredStr, desiredOK := v.Items[desiredKey]
+	if desiredOK {
+		if _, err := strconv.ParseInt(desiredStr, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse item %q of variable %s: %v", desiredKey, v.Path, err)
+		}
+		resp.Meta[sdk.TargetStatusMetaKeyDesiredCount] = desiredStr
+	}
+
+	if scaledAt, ok := v.Items[itemKeyScaledAt]; ok {
+		resp.Meta[sdk.TargetStatusMetaKeyLastEvent] = scaledAt
+	}
+
+	resp.Ready = actualOK && (!desiredOK || actualStr == desiredStr)
+	return resp, nil
+}
+
+// readVariable reads the variable at path. It returns nil if the variable
+// does not exist.
+func readVariable(client *api.Client, namespace, path string) (*variable, error) {
+	var v variable
+
+	_, err := client.Raw().Query(variableEndpoint(path), &v, &api.QueryOptions{Namespace: namespace})
+	if err != nil {
+		if nomadHelper.ResponseCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// variableEndpoint returns the Nomad API endpoint of the variable at path.
+func variableEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return "/v1/var/" + strings.Join(segments, "/")
+}
+
+// variablePath returns the variable path from the target config. This is a
+// required param and results in an error if not found or is an empty string.
+func variablePath(config map[string]string) (string, error) {
+	path := strings.Trim(config[configKeyPath], "/")
+	if path == "" {
+		return "", fmt.Errorf("required config key %q not found", configKeyPath)
+	}
+	return path, nil
+}
+
+// variableNamespace returns the namespace of the variable, falling back to
+// the Nomad default namespace "default".
+func variableNamespace(config map[string]string) string {
+	return configValueOrDefault(config, configKeyNamespace, "default")
+}
+
+// configValueOrDefault returns the value of key in the config, or def if it
+// is not set.
+func configValueOrDefault(config map[string]string, key, def string) string {
+	if v := config[key]; v != "" {
+		return v
+	}
+	return def
+}
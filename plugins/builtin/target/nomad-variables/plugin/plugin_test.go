@@ -0,0 +1,262 @@
+package plugin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVariableServer emulates the Nomad Variables API, storing variables in
+// memory and enforcing check-and-set writes.
+type testVariableServer struct {
+	lock      sync.Mutex
+	variables map[string]*variable
+	index     uint64
+
+	// conflicts is the number of writes which should fail with a conflict
+	// before writes succeed.
+	conflicts int
+}
+
+func (s *testVariableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := r.URL.Query().Get("namespace") + "/" + r.URL.Path
+
+	switch r.Method {
+	case http.MethodGet:
+		v, ok := s.variables[key]
+		if !ok {
+			http.Error(w, "variable not found", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+
+	case http.MethodPut:
+		cas, _ := strconv.ParseUint(r.URL.Query().Get("cas"), 10, 64)
+
+		var current uint64
+		if v, ok := s.variables[key]; ok {
+			current = v.ModifyIndex
+		}
+		if s.conflicts > 0 || cas != current {
+			s.conflicts--
+			http.Error(w, "cas conflict", http.StatusConflict)
+			return
+		}
+
+		var v variable
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.index++
+		v.ModifyIndex = s.index
+		s.variables[key] = &v
+		_ = json.NewEncoder(w).Encode(v)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestPlugin(t *testing.T, srv *testVariableServer) (*TargetPlugin, *httptest.Server) {
+	ts := httptest.NewServer(srv)
+
+	p := NewNomadVariablesPlugin(hclog.NewNullLogger())
+	require.NoError(t, p.SetConfig(map[string]string{"nomad_address": ts.URL}))
+	return p, ts
+}
+
+func TestTargetPlugin_Scale(t *testing.T) {
+	srv := &testVariableServer{
+		variables: map[string]*variable{
+			"default//v1/var/scaling/existing": {
+				Path:        "scaling/existing",
+				Items:       map[string]string{"count": "2", "owner": "actuator"},
+				ModifyIndex: 10,
+			},
+		},
+		index: 10,
+	}
+	p, ts := newTestPlugin(t, srv)
+	defer ts.Close()
+
+	testCases := []struct {
+		inputAction   sdk.ScalingAction
+		inputConfig   map[string]string
+		expectedKey   string
+		expectedItems map[string]string
+		name          string
+	}{
+		{
+			inputAction:   sdk.ScalingAction{Count: 3, Reason: "scale out"},
+			inputConfig:   map[string]string{"path": "scaling/new"},
+			expectedKey:   "default//v1/var/scaling/new",
+			expectedItems: map[string]string{"desired_count": "3", "reason": "scale out"},
+			name:          "new variable",
+		},
+		{
+			inputAction:   sdk.ScalingAction{Count: 5, Reason: "scale out"},
+			inputConfig:   map[string]string{"path": "/scaling/existing"},
+			expectedKey:   "default//v1/var/scaling/existing",
+			expectedItems: map[string]string{"desired_count": "5", "reason": "scale out", "count": "2", "owner": "actuator"},
+			name:          "existing variable keeps items",
+		},
+		{
+			inputAction:   sdk.ScalingAction{Count: 1, Reason: "scale in"},
+			inputConfig:   map[string]string{"path": "scaling/new", "namespace": "dev", "desired_key": "want"},
+			expectedKey:   "dev//v1/var/scaling/new",
+			expectedItems: map[string]string{"want": "1", "reason": "scale in"},
+			name:          "custom namespace and key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, p.Scale(tc.inputAction, tc.inputConfig))
+
+			srv.lock.Lock()
+			defer srv.lock.Unlock()
+
+			v, ok := srv.variables[tc.expectedKey]
+			require.True(t, ok)
+			assert.NotEmpty(t, v.Items["scaled_at"])
+			delete(v.Items, "scaled_at")
+			assert.Equal(t, tc.expectedItems, v.Items)
+		})
+	}
+}
+
+func TestTargetPlugin_Scale_dryRun(t *testing.T) {
+	srv := &testVariableServer{variables: map[string]*variable{}}
+	p, ts := newTestPlugin(t, srv)
+	defer ts.Close()
+
+	action := sdk.ScalingAction{Count: 3}
+	action.Canonicalize()
+	action.SetDryRun()
+
+	require.NoError(t, p.Scale(action, map[string]string{"path": "scaling/dry-run"}))
+	assert.Empty(t, srv.variables)
+}
+
+func TestTargetPlugin_Scale_conflict(t *testing.T) {
+	srv := &testVariableServer{variables: map[string]*variable{}, conflicts: 1}
+	p, ts := newTestPlugin(t, srv)
+	defer ts.Close()
+
+	// The first write conflicts, and the retry succeeds.
+	require.NoError(t, p.Scale(sdk.ScalingAction{Count: 2}, map[string]string{"path": "scaling/conflict"}))
+	assert.Equal(t, "2", srv.variables["default//v1/var/scaling/conflict"].Items["desired_count"])
+}
+
+func TestTargetPlugin_Status(t *testing.T) {
+	srv := &testVariableServer{
+		variables: map[string]*variable{
+			"default//v1/var/scaling/existing": {
+				Path:        "scaling/existing",
+				Items:       map[string]string{"count": "2", "desired_count": "2"},
+				ModifyIndex: 1,
+			},
+		},
+	}
+	p, ts := newTestPlugin(t, srv)
+	defer ts.Close()
+
+	status, err := p.Status(map[string]string{"path": "scaling/existing"})
+	require.NoError(t, err)
+	assert.True(t, status.Ready)
+	assert.Equal(t, int64(2), status.Count)
+
+	// Variables which have not been published are ready with no count.
+	status, err = p.Status(map[string]string{"path": "scaling/missing"})
+	require.NoError(t, err)
+	assert.True(t, status.Ready)
+	assert.Equal(t, int64(0), status.Count)
+
+	_, err = p.Status(map[string]string{})
+	assert.EqualError(t, err, `required config key "path" not found`)
+}
+
+func TestVariableStatus(t *testing.T) {
+	testCases := []struct {
+		inputItems     map[string]string
+		inputConfig    map[string]string
+		expectedOutput *sdk.TargetStatus
+		expectedError  bool
+		name           string
+	}{
+		{
+			inputItems:  map[string]string{"count": "3", "desired_count": "3", "scaled_at": "1600000000000000000"},
+			inputConfig: map[string]string{},
+			expectedOutput: &sdk.TargetStatus{
+				Ready: true,
+				Count: 3,
+				Meta: map[string]string{
+					sdk.TargetStatusMetaKeyRunningCount: "3",
+					sdk.TargetStatusMetaKeyDesiredCount: "3",
+					sdk.TargetStatusMetaKeyLastEvent:    "1600000000000000000",
+				},
+			},
+			name: "actual matches desired",
+		},
+		{
+			inputItems:  map[string]string{"count": "2", "desired_count": "4"},
+			inputConfig: map[string]string{},
+			expectedOutput: &sdk.TargetStatus{
+				Ready: false,
+				Count: 2,
+				Meta: map[string]string{
+					sdk.TargetStatusMetaKeyRunningCount: "2",
+					sdk.TargetStatusMetaKeyDesiredCount: "4",
+				},
+			},
+			name: "actuator in progress",
+		},
+		{
+			inputItems:  map[string]string{"desired_count": "4"},
+			inputConfig: map[string]string{},
+			expectedOutput: &sdk.TargetStatus{
+				Ready: false,
+				Count: 0,
+				Meta:  map[string]string{sdk.TargetStatusMetaKeyDesiredCount: "4"},
+			},
+			name: "actual count not reported",
+		},
+		{
+			inputItems:  map[string]string{"running": "7"},
+			inputConfig: map[string]string{"actual_key": "running"},
+			expectedOutput: &sdk.TargetStatus{
+				Ready: true,
+				Count: 7,
+				Meta:  map[string]string{sdk.TargetStatusMetaKeyRunningCount: "7"},
+			},
+			name: "custom actual key",
+		},
+		{
+			inputItems:     map[string]string{"count": "seven"},
+			inputConfig:    map[string]string{},
+			expectedOutput: nil,
+			expectedError:  true,
+			name:           "invalid actual count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := variableStatus(&variable{Path: "test", Items: tc.inputItems}, tc.inputConfig)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package nomad
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// configKeyAnnotateDeployment is the plugin config key which enables
+	// annotating the deployments created by scaling actions with the context
+	// of the scaling decision.
+	configKeyAnnotateDeployment = "annotate_deployment"
+
+	// The scaling event meta keys the context of the scaling decision is
+	// written to.
+	deploymentMetaKeyPolicyID    = "autoscaler.policy_id"
+	deploymentMetaKeyReason      = "autoscaler.reason"
+	deploymentMetaKeyMetricValue = "autoscaler.metric_value"
+)
+
+// annotateDeploymentEnabled returns whether the plugin config has enabled
+// annotating deployments with the context of the scaling decision.
+func annotateDeploymentEnabled(config map[string]string) (bool, error) {
+	val, ok := config[configKeyAnnotateDeployment]
+	if !ok || val == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %q as boolean: %v", configKeyAnnotateDeployment, err)
+	}
+	return enabled, nil
+}
+
+// deploymentAnnotation returns the meta of the scaling request for the
+// action, annotated with the policy ID, reason and metric value of the
+// scaling decision. The decision context is only sent under the annotation
+// keys, rather than also under the internal keys of the action meta.
+//
+// Nomad deployments can't hold metadata, so the annotation is written to the
+// scaling event instead. The event records the ID of the evaluation which
+// creates the deployment, so the deployment history of a job can be related
+// to the decisions of the autoscaler.
+func deploymentAnnotation(action sdk.ScalingAction) map[string]interface{} {
+	meta := action.MetaWithoutDecisionContext()
+
+	if id := action.PolicyID(); id != "" {
+		meta[deploymentMetaKeyPolicyID] = id
+	}
+	meta[deploymentMetaKeyReason] = action.Reason
+	if v, ok := action.MetricValue(); ok {
+		meta[deploymentMetaKeyMetricValue] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	return meta
+}
@@ -0,0 +1,112 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_annotateDeploymentEnabled(t *testing.T) {
+	testCases := []struct {
+		inputConfig    map[string]string
+		expectedOutput bool
+		expectedError  bool
+		name           string
+	}{
+		{inputConfig: map[string]string{}, expectedOutput: false, name: "not set"},
+		{inputConfig: map[string]string{configKeyAnnotateDeployment: "true"}, expectedOutput: true, name: "enabled"},
+		{inputConfig: map[string]string{configKeyAnnotateDeployment: "sometimes"}, expectedError: true, name: "invalid"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := annotateDeploymentEnabled(tc.inputConfig)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+		})
+	}
+}
+
+func Test_deploymentAnnotation(t *testing.T) {
+	action := sdk.ScalingAction{Count: 3, Reason: "scaling up because factor is 1.500000", Meta: map[string]interface{}{}}
+	action.SetCorrelationID("9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64")
+	action.SetPolicyID("4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39")
+	action.SetMetricValue(75.5)
+
+	assert.Equal(t, map[string]interface{}{
+		"nomad_autoscaler.correlation_id": "9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64",
+		"autoscaler.policy_id":            "4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39",
+		"autoscaler.reason":               "scaling up because factor is 1.500000",
+		"autoscaler.metric_value":         "75.5",
+	}, deploymentAnnotation(action))
+
+	// The meta of the action is not modified.
+	assert.Len(t, action.Meta, 3)
+
+	// Actions which were not generated by a check have no metric value.
+	assert.Equal(t, map[string]interface{}{
+		"autoscaler.reason": "current count (1) below limit (2)",
+	}, deploymentAnnotation(sdk.ScalingAction{Count: 2, Reason: "current count (1) below limit (2)"}))
+}
+
+func TestTargetPlugin_Scale_annotateDeployment(t *testing.T) {
+	var scaled api.ScalingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&scaled)
+		_ = json.NewEncoder(w).Encode(&api.JobRegisterResponse{EvalID: "eval"})
+	}))
+	defer srv.Close()
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+	require.NoError(t, targetPlugin.SetConfig(map[string]string{
+		"nomad_address":             srv.URL,
+		configKeyAnnotateDeployment: "true",
+	}))
+
+	action := sdk.ScalingAction{Count: 3, Reason: "scaling up because factor is 1.500000"}
+	action.SetPolicyID("4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39")
+	config := map[string]string{configKeyJobID: "example", configKeyGroup: "cache"}
+
+	require.NoError(t, targetPlugin.Scale(action, config))
+	assert.Equal(t, "4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39", scaled.Meta[deploymentMetaKeyPolicyID])
+	assert.Equal(t, action.Reason, scaled.Meta[deploymentMetaKeyReason])
+
+	// Dry-run actions don't create a deployment, so are not annotated.
+	scaled = api.ScalingRequest{}
+	action.SetDryRun()
+	require.NoError(t, targetPlugin.Scale(action, config))
+	assert.NotContains(t, scaled.Meta, deploymentMetaKeyReason)
+}
+
+func TestTargetPlugin_Scale_annotateDeploymentDisabled(t *testing.T) {
+	var scaled api.ScalingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&scaled)
+		_ = json.NewEncoder(w).Encode(&api.JobRegisterResponse{EvalID: "eval"})
+	}))
+	defer srv.Close()
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+	require.NoError(t, targetPlugin.SetConfig(map[string]string{"nomad_address": srv.URL}))
+
+	action := sdk.ScalingAction{Count: 3, Reason: "scaling up because factor is 1.500000", Meta: map[string]interface{}{}}
+	action.SetCorrelationID("9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64")
+	action.SetPolicyID("4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39")
+	action.SetMetricValue(75.5)
+
+	// The decision context is not sent to Nomad unless annotating
+	// deployments is enabled.
+	require.NoError(t, targetPlugin.Scale(action, map[string]string{configKeyJobID: "example", configKeyGroup: "cache"}))
+	assert.Equal(t, map[string]interface{}{
+		"nomad_autoscaler.correlation_id": "9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64",
+	}, scaled.Meta)
+}
@@ -0,0 +1,66 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// metaKeyJobTypeSuffix is the key suffix used when adding a meta item to
+	// the status response detailing the jobs type.
+	metaKeyJobTypeSuffix = ".type"
+
+	// Nomad job types which run one allocation per eligible node, rather than
+	// a number of allocations set by the task group count.
+	jobTypeSystem   = "system"
+	jobTypeSysBatch = "sysbatch"
+)
+
+// ValidateTargetConfig satisfies the ValidateTargetConfig function on the
+// target.ConfigValidator interface.
+//
+// System and sysbatch jobs place one allocation on each eligible node, so
+// their count can't be horizontally scaled. Policies targeting them are
+// rejected when loaded; the nodes such jobs run on should be scaled instead,
+// using a cluster scaling target such as aws-asg. Jobs which can't be read,
+// such as jobs which are not yet registered, are not rejected.
+func (t *TargetPlugin) ValidateTargetConfig(config map[string]string) error {
+	jobID := config[configKeyJobID]
+	if jobID == "" || t.clients == nil {
+		return nil
+	}
+
+	client, err := t.clients.Client(config[configKeyRegion])
+	if err != nil {
+		return nil
+	}
+
+	namespace := config[configKeyNamespace]
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: namespace})
+	if err != nil {
+		if nomadHelper.ResponseCode(err) != http.StatusNotFound {
+			t.logger.Debug("failed to read job to validate target config",
+				"namespace", namespace, "job_id", jobID, "error", err)
+		}
+		return nil
+	}
+
+	if job.Type != nil && !scalableJobType(*job.Type) {
+		return fmt.Errorf("job %q is a %s job, which can't be horizontally scaled; scale the nodes it runs on using a cluster scaling target instead",
+			jobID, *job.Type)
+	}
+	return nil
+}
+
+// scalableJobType returns whether jobs of the passed type can be horizontally
+// scaled by modifying their task group count.
+func scalableJobType(jobType string) bool {
+	return jobType != jobTypeSystem && jobType != jobTypeSysBatch
+}
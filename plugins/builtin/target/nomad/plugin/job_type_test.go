@@ -0,0 +1,49 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetPlugin_ValidateTargetConfig(t *testing.T) {
+
+	// Serve a job of each type, named after the type.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobType := r.URL.Path[len("/v1/job/"):]
+		if jobType == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&api.Job{ID: &jobType, Type: &jobType})
+	}))
+	defer srv.Close()
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+	assert.NoError(t, targetPlugin.SetConfig(map[string]string{"nomad_address": srv.URL}))
+
+	testCases := []struct {
+		inputJob      string
+		expectedError bool
+		name          string
+	}{
+		{inputJob: "service", expectedError: false, name: "service job"},
+		{inputJob: "batch", expectedError: false, name: "batch job"},
+		{inputJob: "system", expectedError: true, name: "system job"},
+		{inputJob: "sysbatch", expectedError: true, name: "sysbatch job"},
+		{inputJob: "missing", expectedError: false, name: "job not registered"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := targetPlugin.ValidateTargetConfig(map[string]string{configKeyJobID: tc.inputJob, configKeyGroup: "cache"})
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package nomad
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// configKeyExportMeta is the plugin config key which enables writing the
+	// last scaling action into the meta of the scaled task group, so
+	// operators can see why a group is at its current count in Nomad.
+	configKeyExportMeta = "export_meta"
+
+	// The task group meta keys the last scaling action is written to.
+	groupMetaKeyLastCount    = "autoscaler.last_count"
+	groupMetaKeyLastReason   = "autoscaler.last_reason"
+	groupMetaKeyLastScaledAt = "autoscaler.last_scaled_at"
+)
+
+// exportMetaEnabled returns whether the plugin config has enabled exporting
+// the last scaling action as task group meta.
+func exportMetaEnabled(config map[string]string) (bool, error) {
+	val, ok := config[configKeyExportMeta]
+	if !ok || val == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %q as boolean: %v", configKeyExportMeta, err)
+	}
+	return enabled, nil
+}
+
+// writeGroupMeta writes the count and reason of the scaling action into the meta
+// of the task group. Task group, rather than job, meta is used because each
+// group of a job can be scaled by a different policy.
+//
+// The job is registered using its modify index, so changes made since it was
+// read are not overwritten. Registering the job creates a new job version.
+func (t *TargetPlugin) writeGroupMeta(client *api.Client, action sdk.ScalingAction, namespace, jobID, group string) error {
+	q := &api.QueryOptions{Namespace: namespace}
+
+	job, _, err := client.Jobs().Info(jobID, q)
+	if err != nil {
+		return fmt.Errorf("failed to read job: %v", err)
+	}
+
+	var tg *api.TaskGroup
+	for _, g := range job.TaskGroups {
+		if g.Name != nil && *g.Name == group {
+			tg = g
+			break
+		}
+	}
+	if tg == nil {
+		return fmt.Errorf("task group %q not found", group)
+	}
+
+	if tg.Meta == nil {
+		tg.Meta = make(map[string]string)
+	}
+	tg.Meta[groupMetaKeyLastCount] = strconv.FormatInt(action.Count, 10)
+	tg.Meta[groupMetaKeyLastReason] = action.Reason
+	tg.Meta[groupMetaKeyLastScaledAt] = time.Now().UTC().Format(time.RFC3339)
+
+	if job.JobModifyIndex == nil {
+		return fmt.Errorf("job modify index not found")
+	}
+	_, _, err = client.Jobs().EnforceRegister(job, *job.JobModifyIndex, &api.WriteOptions{Namespace: namespace})
+	if err != nil {
+		return fmt.Errorf("failed to register job: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_exportMetaEnabled(t *testing.T) {
+	testCases := []struct {
+		inputConfig    map[string]string
+		expectedOutput bool
+		expectedError  bool
+		name           string
+	}{
+		{inputConfig: map[string]string{}, expectedOutput: false, name: "not set"},
+		{inputConfig: map[string]string{configKeyExportMeta: "true"}, expectedOutput: true, name: "enabled"},
+		{inputConfig: map[string]string{configKeyExportMeta: "sometimes"}, expectedError: true, name: "invalid"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := exportMetaEnabled(tc.inputConfig)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+		})
+	}
+}
+
+func TestTargetPlugin_writeGroupMeta(t *testing.T) {
+	jobID, cache, web := "example", "cache", "web"
+	var modifyIndex uint64 = 42
+
+	var registered api.JobRegisterRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(&api.Job{
+				ID:             &jobID,
+				JobModifyIndex: &modifyIndex,
+				TaskGroups: []*api.TaskGroup{
+					{Name: &web},
+					{Name: &cache, Meta: map[string]string{"owner": "infra"}},
+				},
+			})
+		default:
+			_ = json.NewDecoder(r.Body).Decode(&registered)
+			_ = json.NewEncoder(w).Encode(&api.JobRegisterResponse{})
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.NewClient(&api.Config{Address: srv.URL})
+	require.NoError(t, err)
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	action := sdk.ScalingAction{Count: 3, Reason: "scaling up because factor is 1.500000"}
+
+	require.NoError(t, targetPlugin.writeGroupMeta(client, action, "default", jobID, cache))
+	assert.True(t, registered.EnforceIndex)
+	assert.Equal(t, modifyIndex, registered.JobModifyIndex)
+	assert.Nil(t, registered.Job.TaskGroups[0].Meta)
+
+	meta := registered.Job.TaskGroups[1].Meta
+	assert.Equal(t, "infra", meta["owner"])
+	assert.Equal(t, "3", meta[groupMetaKeyLastCount])
+	assert.Equal(t, action.Reason, meta[groupMetaKeyLastReason])
+	assert.NotEmpty(t, meta[groupMetaKeyLastScaledAt])
+
+	assert.Error(t, targetPlugin.writeGroupMeta(client, action, "default", jobID, "missing"))
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -24,6 +25,7 @@ const (
 	configKeyJobID     = "Job"
 	configKeyGroup     = "Group"
 	configKeyNamespace = "Namespace"
+	configKeyRegion    = "Region"
 
 	// garbageCollectionNanoSecondThreshold is the nanosecond threshold used
 	// when performing garbage collection of job status handlers.
@@ -32,6 +34,16 @@ const (
 	// garbageCollectionSecondInterval is the interval in seconds at which the
 	// garbage collector will run.
 	garbageCollectionSecondInterval = 60
+
+	// scaleRetryAttempts is the number of times a scale request will be
+	// attempted when the Nomad API returns a transient error.
+	scaleRetryAttempts = 3
+
+	// Backoff values used when retrying failed calls to the Nomad API.
+	scaleBackoffBase   = 1 * time.Second
+	scaleBackoffLimit  = 5 * time.Second
+	statusBackoffBase  = 1 * time.Second
+	statusBackoffLimit = 30 * time.Second
 )
 
 var (
@@ -50,13 +62,19 @@ var (
 	}
 )
 
-// Assert that TargetPlugin meets the target.Target interface.
-var _ target.Target = (*TargetPlugin)(nil)
+// Assert that TargetPlugin meets the target.Target, target.ConfigSchemaProvider
+// and target.ConfigValidator interfaces.
+var (
+	_ target.Target               = (*TargetPlugin)(nil)
+	_ target.ContextScaler        = (*TargetPlugin)(nil)
+	_ target.ConfigSchemaProvider = (*TargetPlugin)(nil)
+	_ target.ConfigValidator      = (*TargetPlugin)(nil)
+)
 
 // TargetPlugin is the Nomad implementation of the target.Target interface.
 type TargetPlugin struct {
-	client *api.Client
-	logger hclog.Logger
+	clients *nomadHelper.ClientPool
+	logger  hclog.Logger
 
 	// statusHandlers is a mapping of jobScaleStatusHandlers keyed by the
 	// namespacedJobID that the handler represents. The lock should be used
@@ -66,12 +84,21 @@ type TargetPlugin struct {
 
 	// gcRunning indicates whether the GC loop is running or not.
 	gcRunning bool
+
+	// exportMeta indicates whether the last scaling action is written into
+	// the meta of the scaled task group.
+	exportMeta bool
+
+	// annotateDeployment indicates whether the context of the scaling
+	// decision is written into the meta of the scaling request, which
+	// relates it to the deployment the request creates.
+	annotateDeployment bool
 }
 
 // namespacedJobID encapsulates the namespace and jobID, which together make a
-// unique job reference within a Nomad region.
+// unique job reference within a Nomad region, along with the region.
 type namespacedJobID struct {
-	namespace, job string
+	namespace, job, region string
 }
 
 // NewNomadPlugin returns the Nomad implementation of the target.Target
@@ -91,12 +118,27 @@ func (t *TargetPlugin) SetConfig(config map[string]string) error {
 	}
 
 	cfg := nomadHelper.ConfigFromNamespacedMap(config)
+	clients := nomadHelper.NewClientPool(cfg, nomadHelper.RegionConfigsFromNamespacedMap(config))
+
+	// Create the client for the default region up front, so invalid config
+	// is reported when the plugin is configured.
+	if _, err := clients.Client(""); err != nil {
+		return err
+	}
 
-	client, err := api.NewClient(cfg)
+	exportMeta, err := exportMetaEnabled(config)
 	if err != nil {
-		return fmt.Errorf("failed to instantiate Nomad client: %v", err)
+		return err
 	}
-	t.client = client
+
+	annotateDeployment, err := annotateDeploymentEnabled(config)
+	if err != nil {
+		return err
+	}
+
+	t.clients = clients
+	t.exportMeta = exportMeta
+	t.annotateDeployment = annotateDeployment
 
 	return nil
 }
@@ -106,8 +148,58 @@ func (t *TargetPlugin) PluginInfo() (*base.PluginInfo, error) {
 	return pluginInfo, nil
 }
 
+// ConfigSchema satisfies the ConfigSchema function on the
+// target.ConfigSchemaProvider interface.
+func (t *TargetPlugin) ConfigSchema() *sdk.TargetConfigSchema {
+	return &sdk.TargetConfigSchema{
+		Keys: map[string]*sdk.TargetConfigKey{
+			configKeyJobID:        {Type: sdk.TargetConfigTypeString, Required: true},
+			configKeyGroup:        {Type: sdk.TargetConfigTypeString, Required: true},
+			configKeyNamespace:    {Type: sdk.TargetConfigTypeString},
+			configKeyRegion:       {Type: sdk.TargetConfigTypeString},
+			configKeyEnforceQuota: {Type: sdk.TargetConfigTypeBool},
+		},
+	}
+}
+
 // Scale satisfies the Scale function on the target.Target interface.
 func (t *TargetPlugin) Scale(action sdk.ScalingAction, config map[string]string) error {
+	return t.ScaleWithContext(context.Background(), action, config)
+}
+
+// ScaleWithContext satisfies the ScaleWithContext function on the
+// target.ContextScaler interface.
+func (t *TargetPlugin) ScaleWithContext(ctx context.Context, action sdk.ScalingAction, config map[string]string) error {
+
+	// Use the client of the region the target is in. Targets without a
+	// region use the region of the plugin config.
+	client, err := t.clients.Client(config[configKeyRegion])
+	if err != nil {
+		return err
+	}
+
+	// If enabled, cap the count based on the namespace quota. Scaling past
+	// the quota will fail, so there is no point in attempting it.
+	enforceQuota, err := quotaEnforced(config)
+	if err != nil {
+		return err
+	}
+	if enforceQuota {
+		namespace := config[configKeyNamespace]
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		ok, err := t.capActionToQuota(client, &action, namespace, config[configKeyJobID], config[configKeyGroup])
+		if err != nil {
+			return fmt.Errorf("failed to apply namespace quota: %v", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: namespace %q does not allow scaling out group %s/%s",
+				sdk.ErrQuotaExhausted, namespace, config[configKeyJobID], config[configKeyGroup])
+		}
+	}
+
 	var countIntPtr *int
 	if action.Count != sdk.StrategyActionMetaValueDryRunCount {
 		countInt := int(action.Count)
@@ -123,17 +215,48 @@ func (t *TargetPlugin) Scale(action sdk.ScalingAction, config map[string]string)
 		q.Namespace = namespace
 	}
 
-	_, _, err := t.client.Jobs().Scale(config[configKeyJobID],
-		config[configKeyGroup],
-		countIntPtr,
-		action.Reason,
-		action.Error,
-		action.Meta,
-		&q)
+	// If enabled, annotate the scaling request with the context of the
+	// scaling decision. Otherwise the context is not sent to Nomad at all.
+	// Dry-run requests don't create a deployment, so are not annotated.
+	meta := action.MetaWithoutDecisionContext()
+	if t.annotateDeployment && countIntPtr != nil {
+		meta = deploymentAnnotation(action)
+	}
+
+	// Scaling to an absolute count is idempotent, so it is safe to retry the
+	// request when Nomad is temporarily unavailable.
+	backoff := nomadHelper.NewBackoff(scaleBackoffBase, scaleBackoffLimit)
+
+	var evalID string
+	err = nomadHelper.Retry(ctx, scaleRetryAttempts, backoff, func() error {
+		resp, _, err := client.Jobs().Scale(config[configKeyJobID],
+			config[configKeyGroup],
+			countIntPtr,
+			action.Reason,
+			action.Error,
+			meta,
+			q.WithContext(ctx))
+		if err == nil && resp != nil {
+			evalID = resp.EvalID
+		}
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to scale group %s/%s: %v", config["job_id"], config["group"], err)
 	}
+
+	t.logger.Debug("submitted scaling request to Nomad", "job_id", config[configKeyJobID],
+		"group", config[configKeyGroup], "correlation_id", action.CorrelationID(), "eval_id", evalID)
+
+	// Failing to export the meta does not fail the scaling action, as the
+	// group has already been scaled.
+	if t.exportMeta && countIntPtr != nil {
+		if err := t.writeGroupMeta(client, action, q.Namespace, config[configKeyJobID], config[configKeyGroup]); err != nil {
+			t.logger.Warn("failed to export scaling action as task group meta", "job_id", config[configKeyJobID],
+				"group", config[configKeyGroup], "error", err)
+		}
+	}
 	return nil
 }
 
@@ -161,7 +284,8 @@ func (t *TargetPlugin) Status(config map[string]string) (*sdk.TargetStatus, erro
 		namespace = "default"
 	}
 
-	nsID := namespacedJobID{namespace: namespace, job: jobID}
+	region := config[configKeyRegion]
+	nsID := namespacedJobID{namespace: namespace, job: jobID, region: region}
 
 	// Create a read/write lock on the handlers so we can safely interact.
 	t.statusHandlersLock.Lock()
@@ -169,7 +293,11 @@ func (t *TargetPlugin) Status(config map[string]string) (*sdk.TargetStatus, erro
 
 	// Create a handler for the job if one does not currently exist.
 	if _, ok := t.statusHandlers[nsID]; !ok {
-		t.statusHandlers[nsID] = newJobScaleStatusHandler(t.client, namespace, jobID, t.logger)
+		client, err := t.clients.Client(region)
+		if err != nil {
+			return nil, err
+		}
+		t.statusHandlers[nsID] = newJobScaleStatusHandler(client, namespace, jobID, t.logger)
 	}
 
 	// If the handler is not in a running state, start it and wait for the
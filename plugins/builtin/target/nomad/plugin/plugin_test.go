@@ -1,11 +1,17 @@
 package nomad
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTargetPlugin_garbageCollect(t *testing.T) {
@@ -17,12 +23,12 @@ func TestTargetPlugin_garbageCollect(t *testing.T) {
 	targetPlugin := TargetPlugin{
 		logger: hclog.NewNullLogger(),
 		statusHandlers: map[namespacedJobID]*jobScaleStatusHandler{
-			namespacedJobID{"default", "running"}:               {isRunning: true, lastUpdated: curTime},
-			namespacedJobID{"default", "recently-stopped"}:      {isRunning: false, lastUpdated: curTime - 1800000000000},
-			namespacedJobID{"default", "stopped-long-time-ago"}: {isRunning: false, lastUpdated: curTime - 18000000000000},
-			namespacedJobID{"special", "running"}:               {isRunning: true, lastUpdated: curTime},
-			namespacedJobID{"special", "recently-stopped"}:      {isRunning: false, lastUpdated: curTime - 1800000000000},
-			namespacedJobID{"special", "stopped-long-time-ago"}: {isRunning: false, lastUpdated: curTime - 18000000000000},
+			namespacedJobID{"default", "running", ""}:               {isRunning: true, lastUpdated: curTime},
+			namespacedJobID{"default", "recently-stopped", ""}:      {isRunning: false, lastUpdated: curTime - 1800000000000},
+			namespacedJobID{"default", "stopped-long-time-ago", ""}: {isRunning: false, lastUpdated: curTime - 18000000000000},
+			namespacedJobID{"special", "running", ""}:               {isRunning: true, lastUpdated: curTime},
+			namespacedJobID{"special", "recently-stopped", ""}:      {isRunning: false, lastUpdated: curTime - 1800000000000},
+			namespacedJobID{"special", "stopped-long-time-ago", ""}: {isRunning: false, lastUpdated: curTime - 18000000000000},
 		},
 	}
 
@@ -30,12 +36,74 @@ func TestTargetPlugin_garbageCollect(t *testing.T) {
 	targetPlugin.garbageCollect()
 
 	t.Run(testName, func(t *testing.T) {
-		assert.Nil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "stopped-long-time-ago"}], testName)
-		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "running"}], testName)
-		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "recently-stopped"}], testName)
-		assert.Nil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "stopped-long-time-ago"}], testName)
-		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "running"}], testName)
-		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "recently-stopped"}], testName)
+		assert.Nil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "stopped-long-time-ago", ""}], testName)
+		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "running", ""}], testName)
+		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"default", "recently-stopped", ""}], testName)
+		assert.Nil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "stopped-long-time-ago", ""}], testName)
+		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "running", ""}], testName)
+		assert.NotNil(t, targetPlugin.statusHandlers[namespacedJobID{"special", "recently-stopped", ""}], testName)
 		assert.Len(t, targetPlugin.statusHandlers, 4, testName)
 	})
 }
+
+func TestTargetPlugin_SetConfig_regions(t *testing.T) {
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+
+	err := targetPlugin.SetConfig(map[string]string{
+		"nomad_address":            "http://nomad.systems:4646",
+		"nomad_regions.eu.address": "http://nomad-eu.systems:4646",
+	})
+	assert.NoError(t, err)
+
+	client, err := targetPlugin.clients.Client("")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nomad.systems:4646", client.Address())
+
+	client, err = targetPlugin.clients.Client("eu")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nomad-eu.systems:4646", client.Address())
+}
+
+func TestTargetPlugin_ConfigSchema(t *testing.T) {
+	schema := (&TargetPlugin{}).ConfigSchema()
+
+	assert.NoError(t, schema.Validate(map[string]string{
+		configKeyJobID:            "example",
+		configKeyGroup:            "cache",
+		configKeyNamespace:        "default",
+		configKeyEnforceQuota:     "true",
+		sdk.TargetConfigKeyDryRun: "true",
+	}))
+	assert.Error(t, schema.Validate(map[string]string{configKeyJobID: "example"}))
+	assert.Error(t, schema.Validate(map[string]string{
+		configKeyJobID:        "example",
+		configKeyGroup:        "cache",
+		configKeyEnforceQuota: "sometimes",
+	}))
+}
+
+func TestTargetPlugin_ScaleWithContext_cancelled(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+	require.NoError(t, targetPlugin.SetConfig(map[string]string{"nomad_address": srv.URL}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// The transient error is retried after the backoff, unless the context
+	// is cancelled while waiting.
+	start := time.Now()
+	err := targetPlugin.ScaleWithContext(ctx, sdk.ScalingAction{Count: 3},
+		map[string]string{configKeyJobID: "example", configKeyGroup: "cache"})
+	assert.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(scaleBackoffBase))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,217 @@
+package nomad
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// configKeyEnforceQuota is the target config key which enables capping
+	// the desired count based on the namespace quota. Quotas are a Nomad
+	// Enterprise feature, so this is disabled by default.
+	configKeyEnforceQuota = "enforce_quota"
+
+	// metaKeyQuotaCapReason is the meta key used to detail why the desired
+	// count was capped due to the namespace quota.
+	metaKeyQuotaCapReason = metaKeyPrefix + "quota_cap_reason"
+
+	// metaKeyQuotaCappedCount is the meta key used to record the count the
+	// scaling action was capped to due to the namespace quota. It is sent
+	// with the scaling request, and reported within the target status as
+	// sdk.TargetStatusMetaKeyQuotaCappedCount while it is part of the most
+	// recent scaling event.
+	metaKeyQuotaCappedCount = metaKeyPrefix + "quota_capped_count"
+)
+
+// quotaEnforced returns whether the target config has enabled quota
+// enforcement.
+func quotaEnforced(config map[string]string) (bool, error) {
+	val, ok := config[configKeyEnforceQuota]
+	if !ok || val == "" {
+		return false, nil
+	}
+
+	enforce, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %q as boolean: %v", configKeyEnforceQuota, err)
+	}
+	return enforce, nil
+}
+
+// capActionToQuota limits the count of the scaling action so that it does not
+// exceed the namespace quota of the target job. When the count is capped, the
+// reason and capped count are recorded in the action Meta. A false return
+// indicates the quota does not allow the job group to scale out at all.
+func (t *TargetPlugin) capActionToQuota(client *api.Client, action *sdk.ScalingAction, namespace, jobID, group string) (bool, error) {
+
+	// Quotas only restrict scaling out, and dry-run actions do not modify the
+	// count, so exit early.
+	if action.Direction != sdk.ScaleDirectionUp || action.Count == sdk.StrategyActionMetaValueDryRunCount {
+		return true, nil
+	}
+
+	q := &api.QueryOptions{Namespace: namespace}
+
+	ns, _, err := client.Namespaces().Info(namespace, q)
+	if err != nil {
+		return false, fmt.Errorf("failed to read namespace %q: %v", namespace, err)
+	}
+
+	// Namespaces without a quota do not restrict the count.
+	if ns.Quota == "" {
+		return true, nil
+	}
+
+	job, _, err := client.Jobs().Info(jobID, q)
+	if err != nil {
+		return false, fmt.Errorf("failed to read job %q: %v", jobID, err)
+	}
+
+	var tg *api.TaskGroup
+	for _, jobGroup := range job.TaskGroups {
+		if jobGroup.Name != nil && *jobGroup.Name == group {
+			tg = jobGroup
+			break
+		}
+	}
+	if tg == nil {
+		return false, fmt.Errorf("task group %q not found", group)
+	}
+
+	spec, _, err := client.Quotas().Info(ns.Quota, q)
+	if err != nil {
+		return false, fmt.Errorf("failed to read quota %q: %v", ns.Quota, err)
+	}
+
+	usage, _, err := client.Quotas().Usage(ns.Quota, q)
+	if err != nil {
+		return false, fmt.Errorf("failed to read quota %q usage: %v", ns.Quota, err)
+	}
+
+	var region string
+	if job.Region != nil {
+		region = *job.Region
+	}
+
+	limit := findQuotaRegionLimit(spec.Limits, region)
+	if limit == nil {
+		return true, nil
+	}
+
+	var used *api.Resources
+	for _, u := range usage.Used {
+		if u.Region == region {
+			used = u.RegionLimit
+			break
+		}
+	}
+
+	var current int64
+	if tg.Count != nil {
+		current = int64(*tg.Count)
+	}
+
+	maxCount, capped := calculateQuotaMaxCount(limit, used, taskGroupResources(tg), current)
+	if !capped || action.Count <= maxCount {
+		return true, nil
+	}
+
+	if maxCount <= current {
+		return false, nil
+	}
+
+	if action.Meta == nil {
+		action.Meta = make(map[string]interface{})
+	}
+	action.Meta[metaKeyQuotaCapReason] = fmt.Sprintf(
+		"capped count from %d to %d due to namespace quota %q", action.Count, maxCount, ns.Quota)
+	action.Meta[metaKeyQuotaCappedCount] = strconv.FormatInt(maxCount, 10)
+
+	t.logger.Info("capped scaling action count due to namespace quota", "namespace", namespace,
+		"job_id", jobID, "group", group, "requested_count", action.Count, "capped_count", maxCount)
+	action.Count = maxCount
+
+	return true, nil
+}
+
+// findQuotaRegionLimit returns the resource limit of the quota which applies
+// to the passed region.
+func findQuotaRegionLimit(limits []*api.QuotaLimit, region string) *api.Resources {
+	for _, l := range limits {
+		if l != nil && l.Region == region {
+			return l.RegionLimit
+		}
+	}
+	return nil
+}
+
+// taskGroupResources returns the total resources required by a single
+// allocation of the task group.
+func taskGroupResources(tg *api.TaskGroup) *api.Resources {
+	var cpu, mem int
+
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		if task.Resources.CPU != nil {
+			cpu += *task.Resources.CPU
+		}
+		if task.Resources.MemoryMB != nil {
+			mem += *task.Resources.MemoryMB
+		}
+	}
+
+	return &api.Resources{CPU: &cpu, MemoryMB: &mem}
+}
+
+// calculateQuotaMaxCount returns the maximum count the task group can reach
+// without exceeding the quota limit. The boolean return indicates whether the
+// quota restricts the count at all.
+//
+// A limit value of zero is treated as unlimited, and a negative value as fully
+// disallowed, matching the behaviour of Nomad.
+func calculateQuotaMaxCount(limit, used, perAlloc *api.Resources, current int64) (int64, bool) {
+	if limit == nil || perAlloc == nil {
+		return 0, false
+	}
+
+	var (
+		maxCount int64
+		capped   bool
+	)
+
+	check := func(limit, used, perAlloc *int) {
+		if limit == nil || *limit == 0 || perAlloc == nil || *perAlloc <= 0 {
+			return
+		}
+
+		var allowed int64
+		if *limit > 0 {
+			var u int
+			if used != nil {
+				u = *used
+			}
+			if available := *limit - u; available > 0 {
+				allowed = int64(available / *perAlloc)
+			}
+		}
+
+		if count := current + allowed; !capped || count < maxCount {
+			maxCount, capped = count, true
+		}
+	}
+
+	var usedCPU, usedMem *int
+	if used != nil {
+		usedCPU, usedMem = used.CPU, used.MemoryMB
+	}
+
+	check(limit.CPU, usedCPU, perAlloc.CPU)
+	check(limit.MemoryMB, usedMem, perAlloc.MemoryMB)
+
+	return maxCount, capped
+}
@@ -0,0 +1,199 @@
+package nomad
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_quotaEnforced(t *testing.T) {
+	testCases := []struct {
+		inputConfig    map[string]string
+		expectedOutput bool
+		expectedError  error
+		name           string
+	}{
+		{
+			inputConfig:    map[string]string{},
+			expectedOutput: false,
+			expectedError:  nil,
+			name:           "not set",
+		},
+		{
+			inputConfig:    map[string]string{configKeyEnforceQuota: "true"},
+			expectedOutput: true,
+			expectedError:  nil,
+			name:           "enabled",
+		},
+		{
+			inputConfig:    map[string]string{configKeyEnforceQuota: "false"},
+			expectedOutput: false,
+			expectedError:  nil,
+			name:           "disabled",
+		},
+		{
+			inputConfig:    map[string]string{configKeyEnforceQuota: "maybe"},
+			expectedOutput: false,
+			expectedError:  errors.New(`failed to parse "enforce_quota" as boolean: strconv.ParseBool: parsing "maybe": invalid syntax`),
+			name:           "invalid value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, actualError := quotaEnforced(tc.inputConfig)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+			assert.Equal(t, tc.expectedError, actualError, tc.name)
+		})
+	}
+}
+
+func Test_calculateQuotaMaxCount(t *testing.T) {
+	testCases := []struct {
+		inputLimit     *api.Resources
+		inputUsed      *api.Resources
+		inputPerAlloc  *api.Resources
+		inputCurrent   int64
+		expectedCount  int64
+		expectedCapped bool
+		name           string
+	}{
+		{
+			inputLimit:     nil,
+			inputUsed:      nil,
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   2,
+			expectedCount:  0,
+			expectedCapped: false,
+			name:           "no region limit",
+		},
+		{
+			inputLimit:     &api.Resources{CPU: ptr.IntToPtr(0), MemoryMB: ptr.IntToPtr(0)},
+			inputUsed:      &api.Resources{CPU: ptr.IntToPtr(200), MemoryMB: ptr.IntToPtr(512)},
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   2,
+			expectedCount:  0,
+			expectedCapped: false,
+			name:           "unlimited",
+		},
+		{
+			inputLimit:     &api.Resources{CPU: ptr.IntToPtr(1000), MemoryMB: ptr.IntToPtr(1024)},
+			inputUsed:      &api.Resources{CPU: ptr.IntToPtr(200), MemoryMB: ptr.IntToPtr(512)},
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   2,
+			expectedCount:  4,
+			expectedCapped: true,
+			name:           "memory is the limiting resource",
+		},
+		{
+			inputLimit:     &api.Resources{CPU: ptr.IntToPtr(350), MemoryMB: ptr.IntToPtr(0)},
+			inputUsed:      &api.Resources{CPU: ptr.IntToPtr(200), MemoryMB: ptr.IntToPtr(512)},
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   2,
+			expectedCount:  3,
+			expectedCapped: true,
+			name:           "cpu is the limiting resource",
+		},
+		{
+			inputLimit:     &api.Resources{CPU: ptr.IntToPtr(200), MemoryMB: ptr.IntToPtr(0)},
+			inputUsed:      &api.Resources{CPU: ptr.IntToPtr(300), MemoryMB: ptr.IntToPtr(512)},
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   3,
+			expectedCount:  3,
+			expectedCapped: true,
+			name:           "quota already exceeded",
+		},
+		{
+			inputLimit:     &api.Resources{CPU: ptr.IntToPtr(-1), MemoryMB: ptr.IntToPtr(0)},
+			inputUsed:      nil,
+			inputPerAlloc:  &api.Resources{CPU: ptr.IntToPtr(100), MemoryMB: ptr.IntToPtr(256)},
+			inputCurrent:   0,
+			expectedCount:  0,
+			expectedCapped: true,
+			name:           "resource disallowed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualCount, actualCapped := calculateQuotaMaxCount(tc.inputLimit, tc.inputUsed, tc.inputPerAlloc, tc.inputCurrent)
+			assert.Equal(t, tc.expectedCount, actualCount, tc.name)
+			assert.Equal(t, tc.expectedCapped, actualCapped, tc.name)
+		})
+	}
+}
+
+func TestTargetPlugin_Scale_enforceQuota(t *testing.T) {
+	usedCPU := 300
+
+	var scaled *api.ScalingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp interface{}
+
+		switch r.URL.Path {
+		case "/v1/namespace/prod":
+			resp = &api.Namespace{Name: "prod", Quota: "prod-quota"}
+		case "/v1/job/example":
+			resp = &api.Job{
+				Region: ptr.StringToPtr("global"),
+				TaskGroups: []*api.TaskGroup{{
+					Name:  ptr.StringToPtr("cache"),
+					Count: ptr.IntToPtr(3),
+					Tasks: []*api.Task{{Resources: &api.Resources{CPU: ptr.IntToPtr(100)}}},
+				}},
+			}
+		case "/v1/quota/prod-quota":
+			resp = &api.QuotaSpec{Limits: []*api.QuotaLimit{
+				{Region: "global", RegionLimit: &api.Resources{CPU: ptr.IntToPtr(500)}},
+			}}
+		case "/v1/quota/usage/prod-quota":
+			resp = &api.QuotaUsage{Used: map[string]*api.QuotaLimit{
+				"global": {Region: "global", RegionLimit: &api.Resources{CPU: ptr.IntToPtr(usedCPU)}},
+			}}
+		case "/v1/job/example/scale":
+			scaled = &api.ScalingRequest{}
+			_ = json.NewDecoder(r.Body).Decode(scaled)
+			resp = &api.JobRegisterResponse{EvalID: "eval"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	targetPlugin := NewNomadPlugin(hclog.NewNullLogger())
+	targetPlugin.gcRunning = true
+	require.NoError(t, targetPlugin.SetConfig(map[string]string{"nomad_address": srv.URL}))
+
+	config := map[string]string{
+		configKeyJobID:        "example",
+		configKeyGroup:        "cache",
+		configKeyNamespace:    "prod",
+		configKeyEnforceQuota: "true",
+	}
+	action := sdk.ScalingAction{Count: 8, Direction: sdk.ScaleDirectionUp}
+
+	// The quota allows two more allocations, so the count is capped and the
+	// capped count is sent with the scaling request.
+	require.NoError(t, targetPlugin.Scale(action, config))
+	require.NotNil(t, scaled)
+	assert.Equal(t, int64(5), *scaled.Count)
+	assert.Equal(t, "5", scaled.Meta[metaKeyQuotaCappedCount])
+
+	// Once the quota is used up, the target is not scaled and the caller is
+	// told why.
+	usedCPU, scaled = 500, nil
+	err := targetPlugin.Scale(action, config)
+	assert.True(t, errors.Is(err, sdk.ErrQuotaExhausted))
+	assert.Nil(t, scaled)
+}
@@ -9,6 +9,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/blocking"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
 	"github.com/hashicorp/nomad/api"
 )
 
@@ -37,6 +38,10 @@ type jobScaleStatusHandler struct {
 	scaleStatus      *api.JobScaleStatusResponse
 	scaleStatusError error
 
+	// jobType is the type of the job, which is not included in the scale
+	// status response. It is empty until the job has been read.
+	jobType string
+
 	// initialDone helps synchronise the caller waiting for the state to be
 	// populated after starting the API query loop.
 	initialDone chan bool
@@ -102,6 +107,8 @@ func (jsh *jobScaleStatusHandler) status(group string) (*sdk.TargetStatus, error
 		Count: int64(status.Running),
 		Meta: map[string]string{
 			metaKeyPrefix + jsh.jobID + metaKeyJobStoppedSuffix: strconv.FormatBool(jsh.scaleStatus.JobStopped),
+			sdk.TargetStatusMetaKeyDesiredCount:                 strconv.Itoa(status.Desired),
+			sdk.TargetStatusMetaKeyRunningCount:                 strconv.Itoa(status.Running),
 		},
 	}
 
@@ -111,8 +118,19 @@ func (jsh *jobScaleStatusHandler) status(group string) (*sdk.TargetStatus, error
 	// Currently any event registered will cause the cooldown period to take
 	// effect. If we use the scale endpoint in the future to register events
 	// such as policy parsing errors, we should filter those out.
+	//
+	// If the most recent event was capped due to the namespace quota, report
+	// the capped count so the agent knows the count it requested was not
+	// applied.
 	if len(status.Events) > 0 {
 		resp.Meta[sdk.TargetStatusMetaKeyLastEvent] = strconv.FormatUint(status.Events[0].Time, 10)
+		if capped, ok := status.Events[0].Meta[metaKeyQuotaCappedCount].(string); ok {
+			resp.Meta[sdk.TargetStatusMetaKeyQuotaCappedCount] = capped
+		}
+	}
+
+	if jsh.jobType != "" {
+		resp.Meta[metaKeyPrefix+jsh.jobID+metaKeyJobTypeSuffix] = jsh.jobType
 	}
 
 	return &resp, nil
@@ -131,6 +149,7 @@ func (jsh *jobScaleStatusHandler) start() {
 		WaitTime:  5 * time.Minute,
 		WaitIndex: 1,
 	}
+	backoff := nomadHelper.NewBackoff(statusBackoffBase, statusBackoffLimit)
 
 	for {
 		status, meta, err := jsh.client.Jobs().ScaleStatus(jsh.jobID, q)
@@ -151,10 +170,16 @@ func (jsh *jobScaleStatusHandler) start() {
 			q.WaitIndex = 0
 
 			// If the error was anything other than the job not being found,
-			// try again.
-			time.Sleep(10 * time.Second)
+			// try again. Permanent errors will not resolve quickly so wait
+			// for the maximum backoff period.
+			if nomadHelper.IsTransientError(err) {
+				time.Sleep(backoff.Next())
+			} else {
+				time.Sleep(backoff.Limit())
+			}
 			continue
 		}
+		backoff.Reset()
 
 		// If the index has not changed, the query returned because the timeout
 		// was reached, therefore start the next query loop.
@@ -167,6 +192,12 @@ func (jsh *jobScaleStatusHandler) start() {
 		// Update the handlers state.
 		jsh.updateStatusState(status, nil)
 
+		// The job type can't change once the job is registered, so it only
+		// needs to be read once.
+		if jsh.jobType == "" {
+			jsh.readJobType()
+		}
+
 		// Mark the handler as initialized and notify initialDone channel.
 		if !jsh.initialized {
 			jsh.handleFirstRun()
@@ -179,6 +210,19 @@ func (jsh *jobScaleStatusHandler) start() {
 	}
 }
 
+// readJobType reads the job to find its type. Failures are only logged, as
+// the type is informational and the job is read again on the next update.
+func (jsh *jobScaleStatusHandler) readJobType() {
+	job, _, err := jsh.client.Jobs().Info(jsh.jobID, &api.QueryOptions{Namespace: jsh.namespace})
+	if err != nil {
+		jsh.logger.Debug("failed to read job type", "error", err)
+		return
+	}
+	if job.Type != nil {
+		jsh.jobType = *job.Type
+	}
+}
+
 // handleFirstRun is a helper function which responds to channel listeners that
 // the first run of the blocking query has completed and therefore data is
 // available for querying.
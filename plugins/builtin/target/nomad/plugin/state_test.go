@@ -63,7 +63,7 @@ func Test_jobStateHandler_status(t *testing.T) {
 				scaleStatus: &api.JobScaleStatusResponse{
 					JobStopped: false,
 					TaskGroups: map[string]api.TaskGroupScaleStatus{
-						"this-does-exist": {Running: 7},
+						"this-does-exist": {Desired: 9, Running: 7},
 					},
 				},
 			},
@@ -73,6 +73,8 @@ func Test_jobStateHandler_status(t *testing.T) {
 				Count: 7,
 				Meta: map[string]string{
 					"nomad_autoscaler.target.nomad.cant-think-of-a-funny-name.stopped": "false",
+					"nomad_autoscaler.count.desired":                                   "9",
+					"nomad_autoscaler.count.running":                                   "7",
 				},
 			},
 			expectedError: nil,
@@ -84,7 +86,7 @@ func Test_jobStateHandler_status(t *testing.T) {
 				scaleStatus: &api.JobScaleStatusResponse{
 					JobStopped: true,
 					TaskGroups: map[string]api.TaskGroupScaleStatus{
-						"this-does-exist": {Running: 7},
+						"this-does-exist": {Desired: 9, Running: 7},
 					},
 				},
 			},
@@ -94,11 +96,67 @@ func Test_jobStateHandler_status(t *testing.T) {
 				Count: 7,
 				Meta: map[string]string{
 					"nomad_autoscaler.target.nomad.cant-think-of-a-funny-name.stopped": "true",
+					"nomad_autoscaler.count.desired":                                   "9",
+					"nomad_autoscaler.count.running":                                   "7",
 				},
 			},
 			expectedError: nil,
 			name:          "job group found within scale status task groups and job is not running",
 		},
+		{
+			inputJSH: &jobScaleStatusHandler{
+				jobID:   "cant-think-of-a-funny-name",
+				jobType: "service",
+				scaleStatus: &api.JobScaleStatusResponse{
+					TaskGroups: map[string]api.TaskGroupScaleStatus{
+						"this-does-exist": {Desired: 9, Running: 7},
+					},
+				},
+			},
+			inputGroup: "this-does-exist",
+			expectedReturn: &sdk.TargetStatus{
+				Ready: true,
+				Count: 7,
+				Meta: map[string]string{
+					"nomad_autoscaler.target.nomad.cant-think-of-a-funny-name.stopped": "false",
+					"nomad_autoscaler.target.nomad.cant-think-of-a-funny-name.type":    "service",
+					"nomad_autoscaler.count.desired":                                   "9",
+					"nomad_autoscaler.count.running":                                   "7",
+				},
+			},
+			expectedError: nil,
+			name:          "job type included once read",
+		},
+		{
+			inputJSH: &jobScaleStatusHandler{
+				jobID: "cant-think-of-a-funny-name",
+				scaleStatus: &api.JobScaleStatusResponse{
+					TaskGroups: map[string]api.TaskGroupScaleStatus{
+						"this-does-exist": {
+							Desired: 7,
+							Running: 7,
+							Events: []api.ScalingEvent{
+								{Time: 1600000000, Meta: map[string]interface{}{metaKeyQuotaCappedCount: "7"}},
+							},
+						},
+					},
+				},
+			},
+			inputGroup: "this-does-exist",
+			expectedReturn: &sdk.TargetStatus{
+				Ready: true,
+				Count: 7,
+				Meta: map[string]string{
+					"nomad_autoscaler.target.nomad.cant-think-of-a-funny-name.stopped": "false",
+					"nomad_autoscaler.count.desired":                                   "7",
+					"nomad_autoscaler.count.running":                                   "7",
+					"nomad_autoscaler.last_event":                                      "1600000000",
+					"nomad_autoscaler.quota.capped_count":                              "7",
+				},
+			},
+			expectedError: nil,
+			name:          "quota capped count of last event reported",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// SetStrategyAliases sets the names which checks can use as their strategy
+// to run a strategy plugin with preset config. The aliases are only set if
+// they all refer to a loaded strategy plugin and none of them has the name of
+// one, otherwise an error is returned and the current aliases are kept.
+func (pm *PluginManager) SetStrategyAliases(aliases []*config.StrategyAlias) error {
+	var mErr multierror.Error
+
+	pm.pluginsLock.RLock()
+	for _, alias := range aliases {
+		if _, ok := pm.plugins[plugins.PluginID{Name: alias.Name, PluginType: sdk.PluginTypeStrategy}]; ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("strategy alias %q has the name of a strategy plugin", alias.Name))
+		}
+		if _, ok := pm.plugins[plugins.PluginID{Name: alias.Strategy, PluginType: sdk.PluginTypeStrategy}]; !ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("strategy alias %q refers to strategy %q which is not configured",
+				alias.Name, alias.Strategy))
+		}
+	}
+	pm.pluginsLock.RUnlock()
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	index := make(map[string]*config.StrategyAlias, len(aliases))
+	for _, alias := range aliases {
+		index[alias.Name] = alias
+	}
+
+	pm.strategyAliasesLock.Lock()
+	pm.strategyAliases = index
+	pm.strategyAliasesLock.Unlock()
+
+	return nil
+}
+
+// ResolveStrategy returns the strategy to run for the passed check strategy.
+// If the strategy name is an alias, the returned strategy is the aliased
+// strategy plugin with the alias config, overridden by the config of the
+// check strategy. Otherwise the check strategy is returned unchanged.
+func (pm *PluginManager) ResolveStrategy(s *sdk.ScalingPolicyStrategy) *sdk.ScalingPolicyStrategy {
+	if pm == nil || s == nil {
+		return s
+	}
+
+	alias, ok := pm.strategyAlias(s.Name)
+	if !ok {
+		return s
+	}
+
+	return &sdk.ScalingPolicyStrategy{
+		Name:   alias.Strategy,
+		Config: mergeStrategyConfig(alias.Config, s.Config),
+	}
+}
+
+// strategyAlias returns the strategy alias with the passed name, if any.
+func (pm *PluginManager) strategyAlias(name string) (*config.StrategyAlias, bool) {
+	pm.strategyAliasesLock.RLock()
+	defer pm.strategyAliasesLock.RUnlock()
+
+	alias, ok := pm.strategyAliases[name]
+	return alias, ok
+}
+
+// strategyAliasValidators returns config validators for the strategy aliases
+// of the passed strategy plugin validators, keyed by the alias name. They
+// validate the check config merged with the alias config.
+func (pm *PluginManager) strategyAliasValidators(validators map[string]strategy.ConfigValidator) map[string]strategy.ConfigValidator {
+	pm.strategyAliasesLock.RLock()
+	defer pm.strategyAliasesLock.RUnlock()
+
+	aliasValidators := make(map[string]strategy.ConfigValidator)
+
+	for name, alias := range pm.strategyAliases {
+		if v, ok := validators[alias.Strategy]; ok {
+			aliasValidators[name] = &aliasConfigValidator{validator: v, config: alias.Config}
+		}
+	}
+	return aliasValidators
+}
+
+// aliasConfigValidator validates check strategy configs which are merged
+// with the config of a strategy alias before they are used.
+type aliasConfigValidator struct {
+	validator strategy.ConfigValidator
+	config    map[string]string
+}
+
+// ValidateStrategyConfig satisfies the ValidateStrategyConfig function on the
+// strategy.ConfigValidator interface.
+func (a *aliasConfigValidator) ValidateStrategyConfig(config map[string]string) error {
+	return a.validator.ValidateStrategyConfig(mergeStrategyConfig(a.config, config))
+}
+
+// mergeStrategyConfig returns a copy of the preset config with the values of
+// config set on it.
+func mergeStrategyConfig(preset, config map[string]string) map[string]string {
+	merged := make(map[string]string, len(preset)+len(config))
+	for k, v := range preset {
+		merged[k] = v
+	}
+	for k, v := range config {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginManager_SetStrategyAliases(t *testing.T) {
+	pm := NewPluginManager(hclog.NewNullLogger(), "../test/bin", map[string][]*config.Plugin{
+		"strategy": {
+			{Name: "target-value", Driver: "target-value"},
+			{Name: "step", Driver: "step"},
+		},
+	})
+	defer pm.KillPlugins()
+	assert.NoError(t, pm.Load())
+
+	aggressive := &config.StrategyAlias{
+		Name:     "cpu-aggressive",
+		Strategy: "target-value",
+		Config:   map[string]string{"target": "50", "threshold": "0.05"},
+	}
+	assert.NoError(t, pm.SetStrategyAliases([]*config.StrategyAlias{
+		aggressive,
+		{Name: "steps", Strategy: "step", Config: map[string]string{"steps": "0..50:-1, 50..:+2"}},
+	}))
+
+	// Aliases resolve to their strategy plugin, with the check config taking
+	// precedence over the alias config.
+	check := &sdk.ScalingPolicyStrategy{Name: "cpu-aggressive", Config: map[string]string{"target": "60"}}
+	assert.Equal(t, &sdk.ScalingPolicyStrategy{
+		Name:   "target-value",
+		Config: map[string]string{"target": "60", "threshold": "0.05"},
+	}, pm.ResolveStrategy(check))
+	assert.Equal(t, map[string]string{"target": "60"}, check.Config)
+	assert.Equal(t, "50", aggressive.Config["target"])
+
+	// Strategies which are not aliases are returned unchanged.
+	plain := &sdk.ScalingPolicyStrategy{Name: "target-value", Config: map[string]string{"target": "80"}}
+	assert.Same(t, plain, pm.ResolveStrategy(plain))
+
+	assert.True(t, pm.HasPlugin("cpu-aggressive", "strategy"))
+	assert.False(t, pm.HasPlugin("cpu-aggressive", "apm"))
+
+	// Alias validators validate the check config merged with the alias config.
+	validators := pm.StrategyConfigValidators()
+	assert.Contains(t, validators, "step")
+	assert.NoError(t, validators["steps"].ValidateStrategyConfig(nil))
+	assert.Error(t, validators["step"].ValidateStrategyConfig(nil))
+	assert.Error(t, validators["steps"].ValidateStrategyConfig(map[string]string{"steps": "invalid"}))
+
+	// Invalid aliases are rejected and the current aliases kept.
+	err := pm.SetStrategyAliases([]*config.StrategyAlias{
+		{Name: "step", Strategy: "target-value"},
+		{Name: "missing", Strategy: "not-configured"},
+	})
+	assert.EqualError(t, err, `2 errors occurred:
+	* strategy alias "step" has the name of a strategy plugin
+	* strategy alias "missing" refers to strategy "not-configured" which is not configured
+
+`)
+	assert.True(t, pm.HasPlugin("cpu-aggressive", "strategy"))
+	assert.False(t, pm.HasPlugin("missing", "strategy"))
+
+	// Aliases can be removed.
+	assert.NoError(t, pm.SetStrategyAliases(nil))
+	assert.False(t, pm.HasPlugin("cpu-aggressive", "strategy"))
+	assert.Same(t, check, pm.ResolveStrategy(check))
+}
@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// discoveryStartTimeout is the time an executable found in the plugin
+// directory has to complete the plugin handshake before it is skipped.
+const discoveryStartTimeout = 10 * time.Second
+
+// DiscoverPlugins scans the plugin directory for executable plugins and
+// returns a plugin configuration for each, keyed by plugin type, so they can
+// be registered without being configured by the operator. Each executable is
+// launched to read its self-reported name and type; executables which do not
+// complete the plugin handshake, or report a name which does not match the
+// executable, are skipped with a warning.
+func DiscoverPlugins(log hclog.Logger, dir string) map[string][]*config.Plugin {
+	log = log.Named("plugin_discovery")
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		log.Warn("failed to read plugin directory", "dir", dir, "error", err)
+		return nil
+	}
+
+	discovered := map[string][]*config.Plugin{}
+
+	for _, f := range files {
+		exePath := filepath.Join(dir, f.Name())
+		if f.IsDir() || !executable(exePath, f) {
+			continue
+		}
+
+		info, err := probePlugin(log, exePath)
+		if err != nil {
+			log.Warn("skipping executable which is not a valid plugin", "path", exePath, "error", err)
+			continue
+		}
+
+		// The manager launches external plugins using the driver as the
+		// executable name, and checks the plugin reports the same name.
+		if driver := cleanPluginExecutable(f.Name()); info.Name != driver {
+			log.Warn("skipping plugin whose name doesn't match its executable",
+				"path", exePath, "plugin_name", info.Name)
+			continue
+		}
+
+		log.Debug("discovered plugin", "plugin_name", info.Name, "plugin_type", info.PluginType)
+		discovered[info.PluginType] = append(discovered[info.PluginType],
+			&config.Plugin{Name: info.Name, Driver: info.Name, Config: map[string]string{}})
+	}
+
+	return discovered
+}
+
+// probePlugin launches the plugin executable and returns the information it
+// reports about itself. The plugin is stopped before returning.
+func probePlugin(log hclog.Logger, exePath string) (*base.PluginInfo, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  plugins.Handshake,
+		Plugins:          map[string]plugin.Plugin{sdk.PluginTypeBase: &base.PluginBase{}},
+		Cmd:              exec.Command(exePath),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		StartTimeout:     discoveryStartTimeout,
+		Logger:           log.ResetNamed("external_plugin"),
+	})
+	defer client.Kill()
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate client: %v", err)
+	}
+
+	raw, err := rpcClient.Dispense(sdk.PluginTypeBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense base plugin: %v", err)
+	}
+
+	b, ok := raw.(base.Base)
+	if !ok {
+		return nil, fmt.Errorf("plugin does not implement base plugin")
+	}
+
+	info, err := b.PluginInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to call PluginInfo: %v", err)
+	}
+
+	switch info.PluginType {
+	case sdk.PluginTypeAPM, sdk.PluginTypeStrategy, sdk.PluginTypeTarget:
+	default:
+		return nil, fmt.Errorf("unsupported plugin type %q", info.PluginType)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,31 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverPlugins(t *testing.T) {
+	l := hclog.NewNullLogger()
+
+	// A directory which can't be read results in no plugins.
+	assert.Empty(t, DiscoverPlugins(l, "this/doesnt/exist"))
+
+	dir, err := ioutil.TempDir("", "nomad-autoscaler-plugins")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Create files which must be skipped: a sub-directory, a file which is
+	// not executable, and an executable which doesn't perform the plugin
+	// handshake.
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub-dir"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "not-executable"), []byte("data"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "not-a-plugin"), []byte("#!/bin/sh\necho hello\n"), 0755))
+
+	assert.Empty(t, DiscoverPlugins(l, dir))
+}
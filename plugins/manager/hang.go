@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad-autoscaler/plugins"
+)
+
+// PluginRestarts details the restarts of a plugin which was restarted by the
+// plugin manager because it consistently timed out.
+type PluginRestarts struct {
+	Name string
+	Type string
+
+	// Count is the number of times the plugin has been restarted.
+	Count int
+
+	// LastRestart is the time at which the plugin was last restarted.
+	LastRestart time.Time
+}
+
+// SetHangDetection configures the detection of hung plugins. Plugin calls
+// which can be abandoned are timed out after callTimeout, and a plugin is
+// restarted after restartThreshold consecutive calls time out. A zero value
+// disables the timeout or the restarts respectively.
+func (pm *PluginManager) SetHangDetection(callTimeout time.Duration, restartThreshold int) {
+	pm.hangLock.Lock()
+	defer pm.hangLock.Unlock()
+
+	pm.callTimeout = callTimeout
+	pm.restartThreshold = restartThreshold
+}
+
+// CallTimeout returns the time after which plugin calls which can be
+// abandoned are timed out. It is zero if calls are not timed out.
+func (pm *PluginManager) CallTimeout() time.Duration {
+	if pm == nil {
+		return 0
+	}
+
+	pm.hangLock.Lock()
+	defer pm.hangLock.Unlock()
+	return pm.callTimeout
+}
+
+// ReportSuccess records that a call to the plugin returned in time, which
+// resets its count of consecutive timeouts.
+func (pm *PluginManager) ReportSuccess(name, pluginType string) {
+	if pm == nil {
+		return
+	}
+
+	pm.hangLock.Lock()
+	defer pm.hangLock.Unlock()
+	delete(pm.timeouts, plugins.PluginID{Name: name, PluginType: pluginType})
+}
+
+// ReportTimeout records that a call to the plugin timed out. Once the plugin
+// reaches the restart threshold of consecutive timeouts it is assumed to be
+// hung, and is restarted. Calls in progress on the previous instance of an
+// external plugin fail once it is killed.
+func (pm *PluginManager) ReportTimeout(name, pluginType string) {
+	if pm == nil {
+		return
+	}
+	pID := plugins.PluginID{Name: name, PluginType: pluginType}
+
+	pm.hangLock.Lock()
+	pm.timeouts[pID]++
+	timeouts, threshold := pm.timeouts[pID], pm.restartThreshold
+	if threshold <= 0 || timeouts < threshold {
+		pm.hangLock.Unlock()
+		return
+	}
+	delete(pm.timeouts, pID)
+	pm.hangLock.Unlock()
+
+	pm.logger.Warn("plugin timed out consecutively, restarting plugin",
+		"plugin_name", name, "plugin_type", pluginType, "timeouts", timeouts)
+
+	if err := pm.restartPlugin(pID); err != nil {
+		pm.logger.Error("failed to restart plugin", "plugin_name", name, "plugin_type", pluginType, "error", err)
+	}
+}
+
+// PluginRestarts returns the plugins which have been restarted because they
+// consistently timed out, sorted by type and name.
+func (pm *PluginManager) PluginRestarts() []PluginRestarts {
+	pm.hangLock.Lock()
+	defer pm.hangLock.Unlock()
+
+	restarts := make([]PluginRestarts, 0, len(pm.restarts))
+	for _, r := range pm.restarts {
+		restarts = append(restarts, *r)
+	}
+
+	sort.Slice(restarts, func(i, j int) bool {
+		if restarts[i].Type != restarts[j].Type {
+			return restarts[i].Type < restarts[j].Type
+		}
+		return restarts[i].Name < restarts[j].Name
+	})
+	return restarts
+}
+
+// restartPlugin launches a new instance of the plugin to replace the current
+// one, which is then killed. The current instance is kept if the new instance
+// fails to launch.
+func (pm *PluginManager) restartPlugin(pID plugins.PluginID) error {
+	pm.pluginsLock.Lock()
+	defer pm.pluginsLock.Unlock()
+
+	pInfo, ok := pm.plugins[pID]
+	if !ok {
+		return fmt.Errorf("plugin %s is not configured", pID.Name)
+	}
+
+	inst, err := pm.dispensePlugin(pID, pInfo)
+	if err != nil {
+		return err
+	}
+
+	pm.pluginInstancesLock.Lock()
+	oldInst, ok := pm.pluginInstances[pID]
+	pm.pluginInstances[pID] = inst
+	pm.pluginInstancesLock.Unlock()
+
+	if ok {
+		oldInst.Kill()
+	}
+
+	pm.hangLock.Lock()
+	r, ok := pm.restarts[pID]
+	if !ok {
+		r = &PluginRestarts{Name: pID.Name, Type: pID.PluginType}
+		pm.restarts[pID] = r
+	}
+	r.Count++
+	r.LastRestart = time.Now()
+	pm.hangLock.Unlock()
+
+	metrics.IncrCounterWithLabels([]string{"plugin", "manager", "restart_count"}, 1,
+		[]metrics.Label{{Name: "plugin_name", Value: pID.Name}, {Name: "plugin_type", Value: pID.PluginType}})
+	return nil
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginManager_ReportTimeout(t *testing.T) {
+	pm := NewPluginManager(hclog.NewNullLogger(), "../test/bin", map[string][]*config.Plugin{
+		"strategy": {
+			{Name: "target-value", Driver: "target-value"},
+		},
+	})
+	defer pm.KillPlugins()
+	assert.NoError(t, pm.Load())
+
+	pm.SetHangDetection(time.Second, 2)
+	assert.Equal(t, time.Second, pm.CallTimeout())
+
+	dispense := func() interface{} {
+		p, err := pm.Dispense("target-value", "strategy")
+		assert.NoError(t, err)
+		return p.Plugin()
+	}
+	original := dispense()
+
+	// A successful call resets the consecutive timeouts.
+	pm.ReportTimeout("target-value", "strategy")
+	pm.ReportSuccess("target-value", "strategy")
+	pm.ReportTimeout("target-value", "strategy")
+	assert.Same(t, original, dispense())
+	assert.Empty(t, pm.PluginRestarts())
+
+	// Reaching the threshold restarts the plugin.
+	pm.ReportTimeout("target-value", "strategy")
+	assert.NotSame(t, original, dispense())
+
+	restarts := pm.PluginRestarts()
+	assert.Len(t, restarts, 1)
+	assert.Equal(t, "target-value", restarts[0].Name)
+	assert.Equal(t, "strategy", restarts[0].Type)
+	assert.Equal(t, 1, restarts[0].Count)
+	assert.False(t, restarts[0].LastRestart.IsZero())
+
+	// Timeouts of plugins which are not configured are ignored.
+	pm.ReportTimeout("not-configured", "apm")
+	pm.ReportTimeout("not-configured", "apm")
+	assert.Len(t, pm.PluginRestarts(), 1)
+}
+
+func TestPluginManager_ReportTimeout_disabled(t *testing.T) {
+	pm := NewPluginManager(hclog.NewNullLogger(), "../test/bin", map[string][]*config.Plugin{
+		"strategy": {
+			{Name: "target-value", Driver: "target-value"},
+		},
+	})
+	defer pm.KillPlugins()
+	assert.NoError(t, pm.Load())
+
+	for i := 0; i < 10; i++ {
+		pm.ReportTimeout("target-value", "strategy")
+	}
+	assert.Empty(t, pm.PluginRestarts())
+
+	// A nil plugin manager never times out calls.
+	var nilPM *PluginManager
+	assert.Equal(t, time.Duration(0), nilPM.CallTimeout())
+	nilPM.ReportTimeout("target-value", "strategy")
+	nilPM.ReportSuccess("target-value", "strategy")
+}
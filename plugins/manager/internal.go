@@ -7,12 +7,21 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/agent/config"
 	"github.com/hashicorp/nomad-autoscaler/plugins"
 	datadog "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/datadog/plugin"
+	httpAPM "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/http/plugin"
+	mockAPM "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/mock/plugin"
 	nomadAPM "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/nomad/plugin"
 	prometheus "github.com/hashicorp/nomad-autoscaler/plugins/builtin/apm/prometheus/plugin"
+	instanceTargetValue "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/instance-target-value/plugin"
+	passThrough "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/pass-through/plugin"
+	queueRatio "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/queue-ratio/plugin"
+	rate "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/rate/plugin"
+	schedule "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/schedule/plugin"
+	step "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/step/plugin"
 	targetValue "github.com/hashicorp/nomad-autoscaler/plugins/builtin/strategy/target-value/plugin"
 	awsASG "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/aws-asg/plugin"
 	azureVMSS "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/azure-vmss/plugin"
 	gceMIG "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/gce-mig/plugin"
+	nomadVariables "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/nomad-variables/plugin"
 	nomadTarget "github.com/hashicorp/nomad-autoscaler/plugins/builtin/target/nomad/plugin"
 )
 
@@ -29,9 +38,30 @@ func (pm *PluginManager) loadInternalPlugin(cfg *config.Plugin, pluginType strin
 	case plugins.InternalTargetNomad:
 		info.factory = nomadTarget.PluginConfig.Factory
 		info.driver = "nomad-target"
+	case plugins.InternalTargetNomadVariables:
+		info.factory = nomadVariables.PluginConfig.Factory
+		info.driver = "nomad-variables"
 	case plugins.InternalStrategyTargetValue:
 		info.factory = targetValue.PluginConfig.Factory
 		info.driver = "target-value"
+	case plugins.InternalStrategyInstanceTargetValue:
+		info.factory = instanceTargetValue.PluginConfig.Factory
+		info.driver = "instance-target-value"
+	case plugins.InternalStrategySchedule:
+		info.factory = schedule.PluginConfig.Factory
+		info.driver = "schedule"
+	case plugins.InternalStrategyRate:
+		info.factory = rate.PluginConfig.Factory
+		info.driver = "rate"
+	case plugins.InternalStrategyStep:
+		info.factory = step.PluginConfig.Factory
+		info.driver = "step"
+	case plugins.InternalStrategyQueueRatio:
+		info.factory = queueRatio.PluginConfig.Factory
+		info.driver = "queue-ratio"
+	case plugins.InternalStrategyPassThrough:
+		info.factory = passThrough.PluginConfig.Factory
+		info.driver = "pass-through"
 	case plugins.InternalAPMPrometheus:
 		info.factory = prometheus.PluginConfig.Factory
 		info.driver = "prometheus"
@@ -47,6 +77,12 @@ func (pm *PluginManager) loadInternalPlugin(cfg *config.Plugin, pluginType strin
 	case plugins.InternalAPMDatadog:
 		info.factory = datadog.PluginConfig.Factory
 		info.driver = "datadog"
+	case plugins.InternalAPMMock:
+		info.factory = mockAPM.PluginConfig.Factory
+		info.driver = "mock-apm"
+	case plugins.InternalAPMHTTP:
+		info.factory = httpAPM.PluginConfig.Factory
+		info.driver = "http-apm"
 	default:
 		pm.logger.Error("unsupported internal plugin", "plugin", cfg.Driver)
 		return
@@ -85,12 +121,21 @@ func (pm *PluginManager) useInternal(plugin string) bool {
 	switch plugin {
 	case plugins.InternalAPMNomad,
 		plugins.InternalTargetNomad,
+		plugins.InternalTargetNomadVariables,
 		plugins.InternalAPMPrometheus,
 		plugins.InternalStrategyTargetValue,
+		plugins.InternalStrategyInstanceTargetValue,
+		plugins.InternalStrategySchedule,
+		plugins.InternalStrategyRate,
+		plugins.InternalStrategyStep,
+		plugins.InternalStrategyQueueRatio,
+		plugins.InternalStrategyPassThrough,
 		plugins.InternalTargetAWSASG,
 		plugins.InternalTargetAzureVMSS,
 		plugins.InternalTargetGCEMIG,
-		plugins.InternalAPMDatadog:
+		plugins.InternalAPMDatadog,
+		plugins.InternalAPMMock,
+		plugins.InternalAPMHTTP:
 		return true
 	default:
 		return false
@@ -26,6 +26,11 @@ func TestPluginManager_useInternal(t *testing.T) {
 			inputPlugin:    plugins.InternalTargetNomad,
 			expectedOutput: true,
 		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalTargetNomadVariables,
+			expectedOutput: true,
+		},
 		{
 			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
 			inputPlugin:    plugins.InternalAPMPrometheus,
@@ -36,6 +41,31 @@ func TestPluginManager_useInternal(t *testing.T) {
 			inputPlugin:    plugins.InternalStrategyTargetValue,
 			expectedOutput: true,
 		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalAPMMock,
+			expectedOutput: true,
+		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalStrategyStep,
+			expectedOutput: true,
+		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalStrategyQueueRatio,
+			expectedOutput: true,
+		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalStrategyPassThrough,
+			expectedOutput: true,
+		},
+		{
+			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
+			inputPlugin:    plugins.InternalAPMHTTP,
+			expectedOutput: true,
+		},
 		{
 			inputPM:        NewPluginManager(l, "this/doesnt/exist", nil),
 			inputPlugin:    "this-plugin-doesnt-exist-either",
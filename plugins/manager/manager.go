@@ -3,6 +3,7 @@ package manager
 import (
 	"fmt"
 	"os/exec"
+	"reflect"
 	"sync"
 	"time"
 
@@ -12,7 +13,11 @@ import (
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad-autoscaler/agent/config"
 	"github.com/hashicorp/nomad-autoscaler/plugins"
+	"github.com/hashicorp/nomad-autoscaler/plugins/apm"
 	"github.com/hashicorp/nomad-autoscaler/plugins/base"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/plugins/target"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
 
 // PluginManager is the brains of the plugin operation and should be used to
@@ -35,6 +40,20 @@ type PluginManager struct {
 	// Nomad Autoscaler plugins.
 	pluginsLock sync.RWMutex
 	plugins     map[plugins.PluginID]*pluginInfo
+
+	// hangLock protects the state of the hung plugin detection. timeouts
+	// tracks the consecutive timeouts of each plugin, and restarts the
+	// plugins restarted after reaching the restartThreshold.
+	hangLock         sync.Mutex
+	callTimeout      time.Duration
+	restartThreshold int
+	timeouts         map[plugins.PluginID]int
+	restarts         map[plugins.PluginID]*PluginRestarts
+
+	// strategyAliases are the names checks can use as their strategy to run
+	// a strategy plugin with preset config, keyed by the alias name.
+	strategyAliasesLock sync.RWMutex
+	strategyAliases     map[string]*config.StrategyAlias
 }
 
 // pluginInfo contains all the required information to launch an Autoscaler
@@ -53,6 +72,15 @@ type pluginInfo struct {
 	factory plugins.PluginFactory
 }
 
+// equal returns whether both infos would launch an identical plugin.
+func (p *pluginInfo) equal(o *pluginInfo) bool {
+	return p.driver == o.driver &&
+		p.exePath == o.exePath &&
+		(p.factory == nil) == (o.factory == nil) &&
+		reflect.DeepEqual(p.args, o.args) &&
+		reflect.DeepEqual(p.config, o.config)
+}
+
 // NewPluginManager sets up a new PluginManager for use.
 func NewPluginManager(log hclog.Logger, dir string, cfg map[string][]*config.Plugin) *PluginManager {
 	return &PluginManager{
@@ -61,13 +89,88 @@ func NewPluginManager(log hclog.Logger, dir string, cfg map[string][]*config.Plu
 		pluginDir:       dir,
 		pluginInstances: make(map[plugins.PluginID]PluginInstance),
 		plugins:         make(map[plugins.PluginID]*pluginInfo),
+		timeouts:        make(map[plugins.PluginID]int),
+		restarts:        make(map[plugins.PluginID]*PluginRestarts),
 	}
 }
 
 // Load is responsible for registering and executing the plugins configured for
 // use by the Autoscaler agent.
 func (pm *PluginManager) Load() error {
+	pm.loadPlugins()
+	return pm.dispensePlugins()
+}
+
+// Reload updates the plugins managed by the plugin manager to match the
+// passed configuration. Plugins whose configuration has not changed are left
+// running so calls in progress are not interrupted. Changed plugins are
+// relaunched, new plugins are launched and removed plugins are killed. If a
+// changed plugin fails to launch, the previous instance is kept.
+func (pm *PluginManager) Reload(cfg map[string][]*config.Plugin) error {
+
+	pm.pluginsLock.Lock()
+	oldPlugins := pm.plugins
+	pm.plugins = make(map[plugins.PluginID]*pluginInfo)
+	pm.pluginsLock.Unlock()
+
+	pm.cfg = cfg
+	pm.loadPlugins()
+
+	var mErr multierror.Error
+
+	pm.pluginsLock.Lock()
+	defer pm.pluginsLock.Unlock()
+
+	for pID, pInfo := range pm.plugins {
+		oldInfo, exists := oldPlugins[pID]
+		if exists && oldInfo.equal(pInfo) && pm.HasPlugin(pID.Name, pID.PluginType) {
+			pInfo.baseInfo = oldInfo.baseInfo
+			continue
+		}
+
+		inst, err := pm.dispensePlugin(pID, pInfo)
+		if err != nil {
+			_ = multierror.Append(&mErr, err)
+			if exists {
+				pm.plugins[pID] = oldInfo
+			}
+			continue
+		}
+
+		pm.pluginInstancesLock.Lock()
+		oldInst, ok := pm.pluginInstances[pID]
+		pm.pluginInstances[pID] = inst
+		pm.pluginInstancesLock.Unlock()
+
+		if ok {
+			pm.logger.Info("shutting down plugin replaced by reload", "plugin_name", pID.Name)
+			oldInst.Kill()
+		}
+	}
+
+	// Kill the plugins which are no longer configured.
+	for pID := range oldPlugins {
+		if _, ok := pm.plugins[pID]; ok {
+			continue
+		}
+
+		pm.pluginInstancesLock.Lock()
+		inst, ok := pm.pluginInstances[pID]
+		delete(pm.pluginInstances, pID)
+		pm.pluginInstancesLock.Unlock()
+
+		if ok {
+			pm.logger.Info("shutting down plugin removed by reload", "plugin_name", pID.Name)
+			inst.Kill()
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
 
+// loadPlugins registers the configured plugins within the plugin store so
+// they can be launched.
+func (pm *PluginManager) loadPlugins() {
 	for t, cfgs := range pm.cfg {
 		for _, cfg := range cfgs {
 
@@ -82,8 +185,6 @@ func (pm *PluginManager) Load() error {
 			}
 		}
 	}
-
-	return pm.dispensePlugins()
 }
 
 // KillPlugins calls Kill on all plugins currently dispensed.
@@ -119,6 +220,123 @@ func (pm *PluginManager) Dispense(name, pluginType string) (PluginInstance, erro
 	return inst, nil
 }
 
+// HasPlugin returns whether a plugin with the passed name and type has been
+// dispensed and is available for use. Strategy aliases are available if the
+// strategy plugin they refer to is.
+func (pm *PluginManager) HasPlugin(name, pluginType string) bool {
+	if pluginType == sdk.PluginTypeStrategy {
+		if alias, ok := pm.strategyAlias(name); ok {
+			name = alias.Strategy
+		}
+	}
+
+	pm.pluginInstancesLock.RLock()
+	defer pm.pluginInstancesLock.RUnlock()
+
+	_, ok := pm.pluginInstances[plugins.PluginID{Name: name, PluginType: pluginType}]
+	return ok
+}
+
+// TargetConfigSchemas returns the config schemas of the dispensed target
+// plugins which provide one, keyed by the plugin name.
+func (pm *PluginManager) TargetConfigSchemas() map[string]*sdk.TargetConfigSchema {
+	pm.pluginInstancesLock.RLock()
+	defer pm.pluginInstancesLock.RUnlock()
+
+	schemas := make(map[string]*sdk.TargetConfigSchema)
+
+	for pID, inst := range pm.pluginInstances {
+		if pID.PluginType != sdk.PluginTypeTarget {
+			continue
+		}
+		if p, ok := inst.Plugin().(target.ConfigSchemaProvider); ok {
+			schemas[pID.Name] = p.ConfigSchema()
+		}
+	}
+	return schemas
+}
+
+// TargetConfigValidators returns the dispensed target plugins which validate
+// policy target configs, keyed by the plugin name.
+func (pm *PluginManager) TargetConfigValidators() map[string]target.ConfigValidator {
+	pm.pluginInstancesLock.RLock()
+	defer pm.pluginInstancesLock.RUnlock()
+
+	validators := make(map[string]target.ConfigValidator)
+
+	for pID, inst := range pm.pluginInstances {
+		if pID.PluginType != sdk.PluginTypeTarget {
+			continue
+		}
+		if v, ok := inst.Plugin().(target.ConfigValidator); ok {
+			validators[pID.Name] = v
+		}
+	}
+	return validators
+}
+
+// StrategyConfigValidators returns the dispensed strategy plugins which
+// validate check strategy configs, keyed by the plugin name, along with the
+// validators of the strategy aliases which refer to them.
+func (pm *PluginManager) StrategyConfigValidators() map[string]strategy.ConfigValidator {
+	pm.pluginInstancesLock.RLock()
+
+	validators := make(map[string]strategy.ConfigValidator)
+
+	for pID, inst := range pm.pluginInstances {
+		if pID.PluginType != sdk.PluginTypeStrategy {
+			continue
+		}
+		if v, ok := inst.Plugin().(strategy.ConfigValidator); ok {
+			validators[pID.Name] = v
+		}
+	}
+	pm.pluginInstancesLock.RUnlock()
+
+	for name, v := range pm.strategyAliasValidators(validators) {
+		validators[name] = v
+	}
+	return validators
+}
+
+// APMQueryValidators returns the dispensed APM plugins which validate check
+// queries, keyed by the plugin name.
+func (pm *PluginManager) APMQueryValidators() map[string]apm.QueryValidator {
+	pm.pluginInstancesLock.RLock()
+	defer pm.pluginInstancesLock.RUnlock()
+
+	validators := make(map[string]apm.QueryValidator)
+
+	for pID, inst := range pm.pluginInstances {
+		if pID.PluginType != sdk.PluginTypeAPM {
+			continue
+		}
+		if v, ok := inst.Plugin().(apm.QueryValidator); ok {
+			validators[pID.Name] = v
+		}
+	}
+	return validators
+}
+
+// APMConfigOverriders returns the dispensed APM plugins which allow checks to
+// override their config, keyed by the plugin name.
+func (pm *PluginManager) APMConfigOverriders() map[string]apm.ConfigOverrider {
+	pm.pluginInstancesLock.RLock()
+	defer pm.pluginInstancesLock.RUnlock()
+
+	overriders := make(map[string]apm.ConfigOverrider)
+
+	for pID, inst := range pm.pluginInstances {
+		if pID.PluginType != sdk.PluginTypeAPM {
+			continue
+		}
+		if o, ok := inst.Plugin().(apm.ConfigOverrider); ok {
+			overriders[pID.Name] = o
+		}
+	}
+	return overriders
+}
+
 // dispensePlugins launches all configured plugins. It is responsible for
 // executing external binaries as well as setting the config on all plugins so
 // they are in a ready state. Any errors from this process will result in the
@@ -138,34 +356,9 @@ func (pm *PluginManager) dispensePlugins() error {
 	defer pm.pluginsLock.Unlock()
 
 	for pID, pInfo := range pm.plugins {
-
-		var (
-			inst PluginInstance
-			info *base.PluginInfo
-			err  error
-		)
-		if pInfo.factory != nil {
-			inst, info, err = pm.launchInternalPlugin(pID, pInfo)
-		} else {
-			inst, info, err = pm.launchExternalPlugin(pID, pInfo)
-		}
-
-		// If we got an error dispensing the plugin, add this to the muilterror
-		// and continue the loop.
+		inst, err := pm.dispensePlugin(pID, pInfo)
 		if err != nil {
-			_ = multierror.Append(&mErr, fmt.Errorf("failed to dispense plugin %s: %v", pID.Name, err))
-			continue
-		}
-
-		// Update our tracking to detail the plugin base information returned
-		// from the plugin itself.
-		pm.plugins[pID].baseInfo = info
-
-		// Perform the SetConfig on the plugin to ensure its state is as the
-		// operator desires.
-		if err := inst.Plugin().(base.Base).SetConfig(pInfo.config); err != nil {
-			inst.Kill()
-			_ = multierror.Append(&mErr, fmt.Errorf("failed to set config on plugin %s: %v", pID.Name, err))
+			_ = multierror.Append(&mErr, err)
 			continue
 		}
 
@@ -173,15 +366,48 @@ func (pm *PluginManager) dispensePlugins() error {
 		pm.pluginInstancesLock.Lock()
 		pm.pluginInstances[pID] = inst
 		pm.pluginInstancesLock.Unlock()
-
-		// When logging to INFO, the plugins do not log anything during startup
-		// therefore log something useful to show the plugin is ready.
-		pm.logger.Info("successfully launched and dispensed plugin", "plugin_name", pID.Name)
 	}
 
 	return mErr.ErrorOrNil()
 }
 
+// dispensePlugin launches a single plugin and sets its config so it is in a
+// ready state. The caller is responsible for storing the returned instance
+// and must hold the pluginsLock.
+func (pm *PluginManager) dispensePlugin(pID plugins.PluginID, pInfo *pluginInfo) (PluginInstance, error) {
+
+	var (
+		inst PluginInstance
+		info *base.PluginInfo
+		err  error
+	)
+	if pInfo.factory != nil {
+		inst, info, err = pm.launchInternalPlugin(pID, pInfo)
+	} else {
+		inst, info, err = pm.launchExternalPlugin(pID, pInfo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense plugin %s: %v", pID.Name, err)
+	}
+
+	// Update our tracking to detail the plugin base information returned
+	// from the plugin itself.
+	pInfo.baseInfo = info
+
+	// Perform the SetConfig on the plugin to ensure its state is as the
+	// operator desires.
+	if err := inst.Plugin().(base.Base).SetConfig(pInfo.config); err != nil {
+		inst.Kill()
+		return nil, fmt.Errorf("failed to set config on plugin %s: %v", pID.Name, err)
+	}
+
+	// When logging to INFO, the plugins do not log anything during startup
+	// therefore log something useful to show the plugin is ready.
+	pm.logger.Info("successfully launched and dispensed plugin", "plugin_name", pID.Name)
+
+	return inst, nil
+}
+
 // launchInternalPlugin is used to dispense internal plugins.
 func (pm *PluginManager) launchInternalPlugin(id plugins.PluginID, info *pluginInfo) (PluginInstance, *base.PluginInfo, error) {
 
@@ -197,8 +197,97 @@ func TestDispense(t *testing.T) {
 						inst = p.Plugin().(target.Target)
 					}
 					assert.NotNil(t, inst)
+					assert.True(t, pm.HasPlugin(pluginConfig.Name, pluginType))
 				}
 			}
+
+			assert.False(t, pm.HasPlugin("not-configured", "target"))
 		})
 	}
 }
+
+func TestReload(t *testing.T) {
+	logger := hclog.NewNullLogger()
+
+	pm := NewPluginManager(logger, "../test/bin", map[string][]*config.Plugin{
+		"apm": []*config.Plugin{
+			&config.Plugin{
+				Name:   "prometheus",
+				Driver: "prometheus",
+				Config: map[string]string{"address": "http://example.com"},
+			},
+			&config.Plugin{
+				Name:   "mock",
+				Driver: "mock-apm",
+				Config: map[string]string{"a": "constant:1"},
+			},
+		},
+		"strategy": []*config.Plugin{
+			&config.Plugin{
+				Name:   "target-value",
+				Driver: "target-value",
+			},
+		},
+	})
+	defer pm.KillPlugins()
+	assert.NoError(t, pm.Load())
+
+	dispense := func(name, pluginType string) interface{} {
+		p, err := pm.Dispense(name, pluginType)
+		assert.NoError(t, err)
+		return p.Plugin()
+	}
+	prometheus := dispense("prometheus", "apm")
+	mock := dispense("mock", "apm")
+	targetValue := dispense("target-value", "strategy")
+
+	// Change the mock config, add a new APM and make the Prometheus config
+	// invalid.
+	err := pm.Reload(map[string][]*config.Plugin{
+		"apm": []*config.Plugin{
+			&config.Plugin{
+				Name:   "prometheus",
+				Driver: "prometheus",
+				Config: map[string]string{},
+			},
+			&config.Plugin{
+				Name:   "mock",
+				Driver: "mock-apm",
+				Config: map[string]string{"a": "constant:2"},
+			},
+			&config.Plugin{
+				Name:   "nomad",
+				Driver: "nomad-apm",
+			},
+		},
+		"strategy": []*config.Plugin{
+			&config.Plugin{
+				Name:   "target-value",
+				Driver: "target-value",
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	// The invalid plugin keeps its previous instance, the unchanged plugin
+	// keeps running and the changed plugin is replaced.
+	assert.Same(t, prometheus, dispense("prometheus", "apm"))
+	assert.Same(t, targetValue, dispense("target-value", "strategy"))
+	assert.NotSame(t, mock, dispense("mock", "apm"))
+	assert.True(t, pm.HasPlugin("nomad", "apm"))
+
+	// Remove plugins from the config.
+	err = pm.Reload(map[string][]*config.Plugin{
+		"strategy": []*config.Plugin{
+			&config.Plugin{
+				Name:   "target-value",
+				Driver: "target-value",
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, pm.HasPlugin("prometheus", "apm"))
+	assert.False(t, pm.HasPlugin("mock", "apm"))
+	assert.False(t, pm.HasPlugin("nomad", "apm"))
+	assert.Same(t, targetValue, dispense("target-value", "strategy"))
+}
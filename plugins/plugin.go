@@ -20,6 +20,10 @@ const (
 	// InternalTargetNomad is the Nomad Target internal plugin name.
 	InternalTargetNomad = "nomad-target"
 
+	// InternalTargetNomadVariables is the Nomad Variables Target internal
+	// plugin name.
+	InternalTargetNomadVariables = "nomad-variables"
+
 	// InternalAPMPrometheus is the Prometheus APM internal plugin name.
 	InternalAPMPrometheus = "prometheus"
 
@@ -27,6 +31,27 @@ const (
 	// name.
 	InternalStrategyTargetValue = "target-value"
 
+	// InternalStrategyInstanceTargetValue is the Instance Target Value
+	// Strategy internal plugin name.
+	InternalStrategyInstanceTargetValue = "instance-target-value"
+
+	// InternalStrategySchedule is the Schedule Strategy internal plugin name.
+	InternalStrategySchedule = "schedule"
+
+	// InternalStrategyRate is the Rate Strategy internal plugin name.
+	InternalStrategyRate = "rate"
+
+	// InternalStrategyStep is the Step Strategy internal plugin name.
+	InternalStrategyStep = "step"
+
+	// InternalStrategyQueueRatio is the Queue Ratio Strategy internal plugin
+	// name.
+	InternalStrategyQueueRatio = "queue-ratio"
+
+	// InternalStrategyPassThrough is the Pass Through Strategy internal plugin
+	// name.
+	InternalStrategyPassThrough = "pass-through"
+
 	// InternalTargetAWSASG is the Amazon Web Services AutoScaling Group target
 	// plugin.
 	InternalTargetAWSASG = "aws-asg"
@@ -41,6 +66,14 @@ const (
 
 	// InternalAPMDatadog is the Datadog APM plugin name.
 	InternalAPMDatadog = "datadog"
+
+	// InternalAPMMock is the mock APM plugin name, which returns scripted
+	// values for testing and demos.
+	InternalAPMMock = "mock-apm"
+
+	// InternalAPMHTTP is the HTTP APM plugin name, which reads metrics from
+	// arbitrary HTTP endpoints returning JSON.
+	InternalAPMHTTP = "http-apm"
 )
 
 // ConfigKeyNomadConfigInherit is a generic plugin config map key that supports
@@ -26,9 +26,10 @@ func (p *pluginServer) Run(_ context.Context, req *proto.RunRequest) (*proto.Run
 	// Populate the eval. At this point of the evaluation flow we will only
 	// have Check and Metrics sections populated, so only translate this.
 	eval := sdk.ScalingCheckEvaluation{
-		Action:  &sdk.ScalingAction{},
-		Check:   check,
-		Metrics: shared.ProtoToTimestampedMetrics(req.TimestampedMetric),
+		Action:        &sdk.ScalingAction{},
+		Check:         check,
+		Metrics:       shared.ProtoToTimestampedMetrics(req.TimestampedMetric),
+		ScaleFromZero: req.GetCount() == 0,
 	}
 
 	resp, err := p.impl.Run(&eval, req.GetCount())
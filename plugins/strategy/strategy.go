@@ -19,3 +19,16 @@ type Strategy interface {
 	// the current state of the scaling target.
 	Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error)
 }
+
+// ConfigValidator is an optional interface strategy plugins can implement to
+// validate the strategy config of checks when policies are loaded, rather
+// than failing each time the check is evaluated.
+//
+// The interface is not supported over the external plugin gRPC interface,
+// so only internal strategy plugins can implement it.
+type ConfigValidator interface {
+
+	// ValidateStrategyConfig returns an error describing why the passed
+	// strategy config is invalid.
+	ValidateStrategyConfig(config map[string]string) error
+}
@@ -2,11 +2,15 @@ package target
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/nomad-autoscaler/plugins/base"
 	"github.com/hashicorp/nomad-autoscaler/plugins/shared"
 	"github.com/hashicorp/nomad-autoscaler/plugins/target/proto/v1"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // pluginClient is the gRPC client implementation of the Target interface.
@@ -28,6 +32,10 @@ func (p *pluginClient) Scale(action sdk.ScalingAction, config map[string]string)
 		return err
 	}
 	_, err = p.client.Scale(p.doneCTX, &proto.ScaleRequest{Action: req, Config: config})
+	if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+		msg := strings.TrimPrefix(s.Message(), sdk.ErrQuotaExhausted.Error()+": ")
+		return fmt.Errorf("%w: %s", sdk.ErrQuotaExhausted, msg)
+	}
 	return err
 }
 
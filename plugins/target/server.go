@@ -2,10 +2,14 @@ package target
 
 import (
 	"context"
+	"errors"
 
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad-autoscaler/plugins/shared"
 	"github.com/hashicorp/nomad-autoscaler/plugins/target/proto/v1"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // pluginServer is the gRPC server implementation of the Target interface.
@@ -21,7 +25,16 @@ func (p *pluginServer) Scale(_ context.Context, req *proto.ScaleRequest) (*proto
 	if err != nil {
 		return nil, err
 	}
-	return &proto.ScaleResponse{}, p.impl.Scale(action, req.GetConfig())
+
+	// Quota errors are sent with their own code, so the client can return
+	// the typed error to the caller.
+	if err := p.impl.Scale(action, req.GetConfig()); err != nil {
+		if errors.Is(err, sdk.ErrQuotaExhausted) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, err
+	}
+	return &proto.ScaleResponse{}, nil
 }
 
 // Status is the gRPC server implementation of the Target.Status interface
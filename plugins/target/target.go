@@ -1,6 +1,8 @@
 package target
 
 import (
+	"context"
+
 	"github.com/hashicorp/nomad-autoscaler/plugins/base"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
@@ -24,3 +26,39 @@ type Target interface {
 	// will be used when performing the strategy calculation.
 	Status(config map[string]string) (*sdk.TargetStatus, error)
 }
+
+// ContextScaler is an optional interface target plugins can implement to stop
+// scaling the target, such as while retrying failed requests, once the passed
+// context is cancelled. The agent cancels the context when shutting down.
+// Only internal plugins receive the context, as it can't be sent to external
+// plugins.
+type ContextScaler interface {
+
+	// ScaleWithContext behaves as Target.Scale, but returns once the passed
+	// context is cancelled.
+	ScaleWithContext(ctx context.Context, action sdk.ScalingAction, config map[string]string) error
+}
+
+// ConfigSchemaProvider is an optional interface target plugins can implement
+// to describe the policy target config they accept. Policies using the target
+// have their config validated against the schema when they are loaded.
+type ConfigSchemaProvider interface {
+
+	// ConfigSchema returns the schema of the target config accepted by
+	// Scale and Status.
+	ConfigSchema() *sdk.TargetConfigSchema
+}
+
+// ConfigValidator is an optional interface target plugins can implement to
+// validate that the policy target config refers to a target which can be
+// scaled, such as by querying the remote target, when policies are loaded.
+// Validation errors which are only known once the policy is evaluated are
+// harder for operators to notice.
+type ConfigValidator interface {
+
+	// ValidateTargetConfig returns an error describing why the target
+	// identified by the passed config can't be scaled. Errors querying the
+	// remote target should not be returned, as the target is queried again
+	// once the policy is evaluated.
+	ValidateTargetConfig(config map[string]string) error
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"fmt"
+	"sort"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// LintFile decodes and validates all the scaling policies within the file in
+// the same manner as the file source. Unlike the source, disabled policies
+// are also validated so mistakes are found before they are enabled.
+func LintFile(file string, processor *policy.Processor) error {
+	policies, err := decodeFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode file: %v", err)
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mErr *multierror.Error
+	for _, name := range names {
+		p := policies[name]
+
+		// The file source generates policy IDs when the file is loaded, so
+		// use the name to satisfy validation.
+		p.ID = name
+
+		if err := processor.ApplyPolicyTemplate(p); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("%q: %v", name, err))
+			continue
+		}
+		processor.ApplyPolicyDefaults(p)
+
+		if err := processor.ValidatePolicy(p); err != nil {
+			mErr = multierror.Append(mErr, multierror.Prefix(err, fmt.Sprintf("%q:", name)))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ReadFilePolicy decodes the named policy from the file, applying its
+// template and the defaults and validating it in the same manner as the file
+// source. If the name is empty, the file must contain a single policy.
+func ReadFilePolicy(file, name string, processor *policy.Processor) (*sdk.ScalingPolicy, error) {
+	policies, err := decodeFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file: %v", err)
+	}
+
+	if name == "" {
+		if len(policies) != 1 {
+			return nil, fmt.Errorf("file contains %d policies, a policy name is required", len(policies))
+		}
+		for n := range policies {
+			name = n
+		}
+	}
+
+	p, ok := policies[name]
+	if !ok {
+		return nil, fmt.Errorf("policy %q doesn't exist in file", name)
+	}
+
+	// Policies read outside of the file source do not have a generated ID,
+	// so use the name to satisfy validation.
+	p.ID = name
+
+	if err := processor.ApplyPolicyTemplate(p); err != nil {
+		return nil, fmt.Errorf("failed to apply template: %v", err)
+	}
+	processor.ApplyPolicyDefaults(p)
+
+	if err := processor.ValidatePolicy(p); err != nil {
+		return nil, fmt.Errorf("failed to validate policy: %v", err)
+	}
+
+	for _, c := range p.Checks {
+		processor.CanonicalizeCheck(c, p.Target)
+	}
+
+	if err := processor.ValidateCheckQueries(p); err != nil {
+		return nil, fmt.Errorf("failed to validate policy: %v", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,68 @@
+package file
+
+import (
+	"testing"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintFile(t *testing.T) {
+	testCases := []struct {
+		inputFile      string
+		expectedErrors int
+		name           string
+	}{
+		{
+			inputFile:      "./test-fixtures/full-cluster-policy.hcl",
+			expectedErrors: 0,
+			name:           "valid cluster policy",
+		},
+		{
+			inputFile:      "./test-fixtures/full-task-group-policy.hcl",
+			expectedErrors: 0,
+			name:           "valid task group policy",
+		},
+		{
+			inputFile:      "./test-fixtures/invalid/invalid-policies.hcl",
+			expectedErrors: 2,
+			name:           "invalid policies including disabled",
+		},
+		{
+			inputFile:      "./test-fixtures/invalid/invalid-queries.hcl",
+			expectedErrors: 2,
+			name:           "empty and whitespace-only queries",
+		},
+		{
+			inputFile:      "./test-fixtures/templated-policy.hcl",
+			expectedErrors: 0,
+			name:           "valid templated policy",
+		},
+		{
+			inputFile:      "./test-fixtures/does-not-exist.hcl",
+			expectedErrors: 1,
+			name:           "missing file",
+		},
+	}
+
+	templates, err := LoadTemplates("./test-fixtures/templates")
+	assert.NoError(t, err)
+
+	processor := policy.NewProcessor(&policy.ConfigDefaults{Templates: templates}, nil)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := LintFile(tc.inputFile, processor)
+			if tc.expectedErrors == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			if mErr, ok := err.(*multierror.Error); ok {
+				assert.Len(t, mErr.Errors, tc.expectedErrors)
+			}
+		})
+	}
+}
@@ -46,6 +46,54 @@ func decodePolicyDoc(decodePolicy *sdk.FileDecodeScalingPolicy) error {
 		decodePolicy.Doc.Cooldown = d
 	}
 
+	if decodePolicy.Doc.ZeroCooldownHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.ZeroCooldownHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.ZeroCooldown = d
+	}
+
+	if decodePolicy.Doc.CooldownPerUnitHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.CooldownPerUnitHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.CooldownPerUnit = d
+	}
+
+	if decodePolicy.Doc.MaxCooldownHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.MaxCooldownHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.MaxCooldown = d
+	}
+
+	if decodePolicy.Doc.ScaleInStabilizationHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.ScaleInStabilizationHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.ScaleInStabilization = d
+	}
+
+	if decodePolicy.Doc.StartupGracePeriodHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.StartupGracePeriodHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.StartupGracePeriod = d
+	}
+
+	if decodePolicy.Doc.DedupWindowHCL != "" {
+		d, err := time.ParseDuration(decodePolicy.Doc.DedupWindowHCL)
+		if err != nil {
+			return err
+		}
+		decodePolicy.Doc.DedupWindow = d
+	}
+
 	if decodePolicy.Doc.EvaluationIntervalHCL != "" {
 		d, err := time.ParseDuration(decodePolicy.Doc.EvaluationIntervalHCL)
 		if err != nil {
@@ -54,20 +102,41 @@ func decodePolicyDoc(decodePolicy *sdk.FileDecodeScalingPolicy) error {
 		decodePolicy.Doc.EvaluationInterval = d
 	}
 
-	// Parse query window for each check.
+	// Parse query window, timeout and query params window for each check.
 	for i := 0; i < len(decodePolicy.Doc.Checks); i++ {
 		check := decodePolicy.Doc.Checks[i]
 
-		// Skip parsing if query_window not set.
-		if check.QueryWindowHCL == "" {
-			continue
+		if check.QueryWindowHCL != "" {
+			w, err := time.ParseDuration(check.QueryWindowHCL)
+			if err != nil {
+				return err
+			}
+			decodePolicy.Doc.Checks[i].QueryWindow = w
 		}
 
-		w, err := time.ParseDuration(check.QueryWindowHCL)
-		if err != nil {
-			return err
+		if check.QueryTimeoutHCL != "" {
+			t, err := time.ParseDuration(check.QueryTimeoutHCL)
+			if err != nil {
+				return err
+			}
+			decodePolicy.Doc.Checks[i].QueryTimeout = t
+		}
+
+		if check.MaxMetricAgeHCL != "" {
+			a, err := time.ParseDuration(check.MaxMetricAgeHCL)
+			if err != nil {
+				return err
+			}
+			decodePolicy.Doc.Checks[i].MaxMetricAge = a
+		}
+
+		if check.QueryParams != nil && check.QueryParams.WindowHCL != "" {
+			w, err := time.ParseDuration(check.QueryParams.WindowHCL)
+			if err != nil {
+				return err
+			}
+			check.QueryParams.Window = w
 		}
-		decodePolicy.Doc.Checks[i].QueryWindow = w
 	}
 
 	return nil
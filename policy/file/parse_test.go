@@ -1,10 +1,14 @@
 package file
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad-autoscaler/policy"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,19 +23,73 @@ func Test_decodeFile(t *testing.T) {
 			inputFile: "./test-fixtures/full-cluster-policy.hcl",
 			expectedOutputPolicies: map[string]*sdk.ScalingPolicy{
 				"full-cluster-policy": &sdk.ScalingPolicy{
-					ID:                 "",
-					Type:               sdk.ScalingPolicyTypeCluster,
-					Enabled:            true,
-					Min:                10,
-					Max:                100,
-					Cooldown:           10 * time.Minute,
-					EvaluationInterval: 1 * time.Minute,
+					ID:                         "",
+					Type:                       sdk.ScalingPolicyTypeCluster,
+					Enabled:                    true,
+					Min:                        10,
+					Max:                        100,
+					Cooldown:                   10 * time.Minute,
+					ZeroCooldown:               time.Hour,
+					CooldownPerUnit:            time.Minute,
+					MaxCooldown:                45 * time.Minute,
+					CooldownOnCompletion:       true,
+					SupersedeInFlight:          true,
+					ScaleInStabilizationWindow: 15 * time.Minute,
+					DedupWindow:                30 * time.Minute,
+					Priority:                   80,
+					SoftMax:                    80,
+					OnMetricError:              "hold",
+					Quorum:                     2,
+					ScaleInBias:                2,
+					ScaleInBiasBand:            0.15,
+					HysteresisFactor:           2,
+					MinChangeCount:             1,
+					MinChangePercentage:        10,
+					RampIntervals:              4,
+					RespectManualOverride:      true,
+					DryRunSample:               0.25,
+					AllowSharedTarget:          true,
+					EvaluationInterval:         1 * time.Minute,
+					LimitsSchedule: &sdk.ScalingPolicyLimitsSchedule{
+						Timezone: "Europe/Berlin",
+						Windows: []*sdk.ScalingPolicyLimitsWindow{
+							{
+								Name:  "business_hours",
+								Days:  "mon-fri",
+								Start: "08:00",
+								End:   "18:00",
+								Min:   ptr.Int64ToPtr(20),
+							},
+							{
+								Name:  "overnight",
+								Start: "22:00",
+								End:   "06:00",
+								Min:   ptr.Int64ToPtr(2),
+								Max:   ptr.Int64ToPtr(50),
+							},
+						},
+					},
+					Tags: map[string]string{
+						"team":        "infra",
+						"cost_center": "1234",
+					},
 					Checks: []*sdk.ScalingPolicyCheck{
 						{
-							Name:        "cpu_nomad",
-							Source:      "nomad_apm",
-							Query:       "cpu_high-memory",
-							QueryWindow: time.Minute,
+							Name:              "cpu_nomad",
+							Source:            "nomad_apm",
+							Query:             "cpu_high-memory",
+							QueryWindow:       time.Minute,
+							MetricWindow:      5,
+							MetricAggregation: sdk.MetricAggregationMax,
+							SeriesQueries:     []string{"cpu_high-compute"},
+							SeriesAggregation: sdk.MetricAggregationSum,
+							Transforms:        []string{"log", "offset(-1)"},
+							Preconditions:     []string{"ready"},
+							Priority:          10,
+							QueryParams: &sdk.QueryParams{
+								Window: 30 * time.Second,
+								Rollup: "max",
+							},
 							Strategy: &sdk.ScalingPolicyStrategy{
 								Name: "target-value",
 								Config: map[string]string{
@@ -40,9 +98,20 @@ func Test_decodeFile(t *testing.T) {
 							},
 						},
 						{
-							Name:   "memory_prom",
-							Source: "prometheus",
-							Query:  "nomad_client_allocated_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)",
+							Name:                 "memory_prom",
+							Source:               "prometheus",
+							Query:                "nomad_client_allocated_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)",
+							Disabled:             true,
+							MetricSmoothing:      sdk.MetricSmoothingEWMA,
+							MetricSmoothingAlpha: 0.25,
+							FormulaQueries: map[string]string{
+								"reserved": "nomad_client_reserved_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)",
+							},
+							Formula: "query + (reserved / 2)",
+							SourceConfig: map[string]string{
+								"address":              "http://prometheus-infra:9090",
+								"header.Authorization": "Bearer token",
+							},
 							Strategy: &sdk.ScalingPolicyStrategy{
 								Name: "target-value",
 								Config: map[string]string{
@@ -74,12 +143,15 @@ func Test_decodeFile(t *testing.T) {
 					Min:                1,
 					Max:                10,
 					Cooldown:           1 * time.Minute,
+					StartupGracePeriod: 90 * time.Second,
 					EvaluationInterval: 30 * time.Second,
 					Checks: []*sdk.ScalingPolicyCheck{
 						{
-							Name:   "cpu_nomad",
-							Source: "nomad_apm",
-							Query:  "avg_cpu",
+							Name:         "cpu_nomad",
+							Source:       "nomad_apm",
+							Query:        "avg_cpu",
+							QueryTimeout: 20 * time.Second,
+							MaxMetricAge: 2 * time.Minute,
 							Strategy: &sdk.ScalingPolicyStrategy{
 								Name: "target-value",
 								Config: map[string]string{
@@ -126,3 +198,42 @@ func Test_decodeFile(t *testing.T) {
 		})
 	}
 }
+
+func Test_decodeFile_EncodeHCLRoundTrip(t *testing.T) {
+	testCases := []struct {
+		inputFile string
+		name      string
+	}{
+		{
+			inputFile: "./test-fixtures/full-cluster-policy.hcl",
+			name:      "full cluster policy",
+		},
+		{
+			inputFile: "./test-fixtures/full-task-group-policy.hcl",
+			name:      "full task group policy",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := decodeFile(tc.inputFile)
+			assert.NoError(t, err)
+
+			for name, p := range parsed {
+				// Encode the policy and decode it again, which should result
+				// in exactly the same policy.
+				fh, err := ioutil.TempFile("", "nomad-autoscaler*.hcl")
+				assert.NoError(t, err)
+				defer os.Remove(fh.Name())
+
+				_, err = fh.Write(policy.EncodeHCL(p))
+				assert.NoError(t, err)
+				assert.NoError(t, fh.Close())
+
+				got, err := decodeFile(fh.Name())
+				assert.NoError(t, err)
+				assert.Equal(t, p, got[p.ID], name)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"os"
 	"reflect"
 	"sync"
 
@@ -24,7 +25,8 @@ type pathMD5Sum [16]byte
 
 // Source is the File implementation of the policy.Source interface.
 type Source struct {
-	dir             string
+	name            policy.SourceName
+	path            string
 	log             hclog.Logger
 	policyProcessor *policy.Processor
 
@@ -54,10 +56,23 @@ type filePolicy struct {
 	policy *sdk.ScalingPolicy
 }
 
-func NewFileSource(log hclog.Logger, dir string, policyProcessor *policy.Processor) policy.Source {
+// NewFileSource returns the File implementation of the policy.Source
+// interface. The path can either be a directory, in which case all HCL and
+// JSON files within it are read, or a single file. Each file can contain
+// multiple scaling policies.
+func NewFileSource(log hclog.Logger, path string, policyProcessor *policy.Processor) policy.Source {
+	return NewNamedFileSource(log, policy.SourceNameFile, path, policyProcessor)
+}
+
+// NewNamedFileSource returns a File implementation of the policy.Source
+// interface which identifies itself using the passed name. This allows other
+// sources, which fetch policy files from elsewhere, to use it to read the
+// fetched files.
+func NewNamedFileSource(log hclog.Logger, name policy.SourceName, path string, policyProcessor *policy.Processor) policy.Source {
 	return &Source{
-		dir:              dir,
-		log:              log.ResetNamed("file_policy_source"),
+		name:             name,
+		path:             path,
+		log:              log.ResetNamed(string(name) + "_policy_source"),
 		idMap:            make(map[pathMD5Sum]policy.PolicyID),
 		policyMap:        make(map[policy.PolicyID]*filePolicy),
 		reloadCh:         make(chan struct{}),
@@ -68,7 +83,7 @@ func NewFileSource(log hclog.Logger, dir string, policyProcessor *policy.Process
 
 // Name satisfies the Name function of the policy.Source interface.
 func (s *Source) Name() policy.SourceName {
-	return policy.SourceNameFile
+	return s.name
 }
 
 // MonitorIDs satisfies the MonitorIDs function of the policy.Source interface.
@@ -177,6 +192,27 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 	}
 }
 
+// ReadPolicy satisfies the ReadPolicy function of the policy.Source
+// interface. The stored version of the policy is updated, so the monitor only
+// reports changes made after the read.
+func (s *Source) ReadPolicy(_ context.Context, ID policy.PolicyID) (*sdk.ScalingPolicy, error) {
+	s.policyMapLock.Lock()
+	defer s.policyMapLock.Unlock()
+
+	val, ok := s.policyMap[ID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get policy %s", ID)
+	}
+
+	p, err := s.readPolicy(ID, val.file, val.name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.policyMap[ID] = &filePolicy{file: val.file, name: val.name, policy: p}
+	return p, nil
+}
+
 // handleIndividualPolicyRead reads the policy from disk and compares it to the
 // stored version if there is one. If there is a difference the new policy will
 // be returned, otherwise we return nil to indicate no reload is required. This
@@ -184,6 +220,28 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 // lock on policyMapLock.
 func (s *Source) handleIndividualPolicyRead(ID policy.PolicyID, path, name string) (*sdk.ScalingPolicy, error) {
 
+	newPolicy, err := s.readPolicy(ID, path, name)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := s.policyMap[ID]
+	if !ok || val.policy == nil {
+		return newPolicy, nil
+	}
+
+	// Check the new policy against the stored. If they are the same, and
+	// therefore the policy has not changed indicate that to the caller.
+	if reflect.DeepEqual(newPolicy, val.policy) {
+		return nil, nil
+	}
+	return newPolicy, nil
+}
+
+// readPolicy decodes the named policy from the file at path, applying the
+// defaults and validating it.
+func (s *Source) readPolicy(ID policy.PolicyID, path, name string) (*sdk.ScalingPolicy, error) {
+
 	// Decode the file into a new policy to allow comparison to our stored
 	// policy. Make sure to add the ID string and defaults, we are responsible
 	// for managing this and if we don't add it, there will always be a
@@ -199,6 +257,10 @@ func (s *Source) handleIndividualPolicyRead(ID policy.PolicyID, path, name strin
 	}
 
 	newPolicy.ID = ID.String()
+
+	if err := s.policyProcessor.ApplyPolicyTemplate(newPolicy); err != nil {
+		return nil, fmt.Errorf("failed to apply template to file %s: %v", path, err)
+	}
 	s.policyProcessor.ApplyPolicyDefaults(newPolicy)
 
 	if err := s.policyProcessor.ValidatePolicy(newPolicy); err != nil {
@@ -209,20 +271,14 @@ func (s *Source) handleIndividualPolicyRead(ID policy.PolicyID, path, name strin
 		s.policyProcessor.CanonicalizeCheck(c, newPolicy.Target)
 	}
 
-	val, ok := s.policyMap[ID]
-	if !ok || val.policy == nil {
-		return newPolicy, nil
+	if err := s.policyProcessor.ValidateCheckQueries(newPolicy); err != nil {
+		return nil, fmt.Errorf("failed to validate file %s: %v", path, err)
 	}
 
-	// Check the new policy against the stored. If they are the same, and
-	// therefore the policy has not changed indicate that to the caller.
-	if reflect.DeepEqual(newPolicy, val.policy) {
-		return nil, nil
-	}
 	return newPolicy, nil
 }
 
-// identifyPolicyIDs iterates the configured directory, identifying the
+// identifyPolicyIDs iterates the configured path, identifying the
 // configured policyIDs. The IDs will be wrapped and sent to the resultCh so
 // the policy manager can do its work.
 func (s *Source) identifyPolicyIDs(resultCh chan<- policy.IDMessage, errCh chan<- error) {
@@ -237,16 +293,14 @@ func (s *Source) identifyPolicyIDs(resultCh chan<- policy.IDMessage, errCh chan<
 	resultCh <- policy.IDMessage{IDs: ids, Source: s.Name()}
 }
 
-// handleDir iterates through the configured directory, attempting to decode
-// and store all HCL and JSON files as scaling policies. If the policy is not
+// handleDir iterates through the configured path, attempting to decode and
+// store all HCL and JSON files as scaling policies. If the policy is not
 // enabled it will be ignored.
 func (s *Source) handleDir() ([]policy.PolicyID, error) {
 
-	// Obtain a list of all files in the directory which have the suffixes we
-	// can handle as scaling policies.
-	files, err := fileHelper.GetFileListFromDir(s.dir, ".hcl", ".json")
+	files, err := s.policyFiles()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files in directory: %v", err)
+		return nil, err
 	}
 
 	var policyIDs []policy.PolicyID
@@ -279,6 +333,10 @@ func (s *Source) handleDir() ([]policy.PolicyID, error) {
 				continue
 			}
 
+			if err := s.policyProcessor.ApplyPolicyTemplate(scalingPolicy); err != nil {
+				mErr = multierror.Append(fmt.Errorf("failed to apply template to file %s: %v", file, err), mErr)
+				continue
+			}
 			s.policyProcessor.ApplyPolicyDefaults(scalingPolicy)
 
 			if err := s.policyProcessor.ValidatePolicy(scalingPolicy); err != nil {
@@ -290,6 +348,11 @@ func (s *Source) handleDir() ([]policy.PolicyID, error) {
 				s.policyProcessor.CanonicalizeCheck(c, scalingPolicy.Target)
 			}
 
+			if err := s.policyProcessor.ValidateCheckQueries(scalingPolicy); err != nil {
+				mErr = multierror.Append(fmt.Errorf("failed to validate file %s: %v", file, err), mErr)
+				continue
+			}
+
 			// Store the file/name>id mapping if it doesn't exist. This makes the
 			// MonitorPolicy function simpler as we have an easy mapping of the
 			// policyID to the file it came from.
@@ -310,9 +373,46 @@ func (s *Source) handleDir() ([]policy.PolicyID, error) {
 		}
 	}
 
+	// Forget the policies which are not listed anymore, such as when their
+	// file has been deleted, so the stored policies don't accumulate over
+	// time.
+	listed := make(map[policy.PolicyID]bool, len(policyIDs))
+	for _, policyID := range policyIDs {
+		listed[policyID] = true
+	}
+
+	s.policyMapLock.Lock()
+	for policyID := range s.policyMap {
+		if !listed[policyID] {
+			delete(s.policyMap, policyID)
+		}
+	}
+	s.policyMapLock.Unlock()
+
 	return policyIDs, mErr.ErrorOrNil()
 }
 
+// policyFiles returns the files which contain the scaling policies. If the
+// configured path is a file, it is the only file returned regardless of its
+// suffix, otherwise all files in the directory which have the suffixes we can
+// handle as scaling policies are returned.
+func (s *Source) policyFiles() ([]string, error) {
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy path: %v", err)
+	}
+
+	if !fi.IsDir() {
+		return []string{s.path}, nil
+	}
+
+	files, err := fileHelper.GetFileListFromDir(s.path, ".hcl", ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in directory: %v", err)
+	}
+	return files, nil
+}
+
 // getFilePolicyID translates the file into its policyID. This is done by
 // firstly checking our internal state. If it isn't found, we generate and
 // store the ID in our state.
@@ -1,8 +1,10 @@
 package file
 
 import (
+	"sort"
 	"testing"
 
+	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad-autoscaler/policy"
 	"github.com/stretchr/testify/assert"
 )
@@ -48,3 +50,54 @@ func TestSource_getFilePolicyID(t *testing.T) {
 		})
 	}
 }
+
+func TestSource_handleDir(t *testing.T) {
+	testCases := []struct {
+		inputPath     string
+		expectedNames []string
+		expectError   bool
+		name          string
+	}{
+		{
+			inputPath:     "./test-fixtures/multi",
+			expectedNames: []string{"cache", "web"},
+			name:          "directory",
+		},
+		{
+			inputPath:     "./test-fixtures/multi/policies.hcl",
+			expectedNames: []string{"cache", "web"},
+			name:          "single file with multiple policies",
+		},
+		{
+			inputPath:   "./test-fixtures/does-not-exist.hcl",
+			expectError: true,
+			name:        "missing path",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			processor := policy.NewProcessor(&policy.ConfigDefaults{}, nil)
+			s := NewFileSource(hclog.NewNullLogger(), tc.inputPath, processor).(*Source)
+
+			ids, err := s.handleDir()
+			if tc.expectError {
+				assert.Error(t, err, tc.name)
+				return
+			}
+			assert.NoError(t, err, tc.name)
+
+			var names []string
+			for _, id := range ids {
+				names = append(names, s.policyMap[id].name)
+			}
+			sort.Strings(names)
+			assert.Equal(t, tc.expectedNames, names, tc.name)
+
+			// Reading the path again must keep the IDs of the policies stable.
+			newIDs, err := s.handleDir()
+			assert.NoError(t, err, tc.name)
+			assert.ElementsMatch(t, ids, newIDs, tc.name)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package file
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	fileHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/file"
+)
+
+// LoadTemplates decodes all the HCL and JSON files within the directory as
+// policy templates. Templates use the same format as file source scaling
+// policies, with the name of each scaling block being the template name.
+// Template names must be unique across the directory.
+func LoadTemplates(dir string) (map[string]*sdk.ScalingPolicy, error) {
+	files, err := fileHelper.GetFileListFromDir(dir, ".hcl", ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in directory: %v", err)
+	}
+
+	templates := make(map[string]*sdk.ScalingPolicy)
+	sources := make(map[string]string)
+	var mErr *multierror.Error
+
+	for _, file := range files {
+		policies, err := decodeFile(file)
+		if err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("failed to decode file %s: %v", file, err))
+			continue
+		}
+
+		for name, p := range policies {
+			if existing, ok := sources[name]; ok {
+				mErr = multierror.Append(mErr, fmt.Errorf("template %q in file %s is already defined in file %s", name, file, existing))
+				continue
+			}
+			if p.Template != "" {
+				mErr = multierror.Append(mErr, fmt.Errorf("template %q in file %s can't reference another template", name, file))
+				continue
+			}
+			sources[name] = file
+			templates[name] = p
+		}
+	}
+
+	return templates, mErr.ErrorOrNil()
+}
@@ -0,0 +1,48 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	templates, err := LoadTemplates("./test-fixtures/templates")
+	assert.NoError(t, err)
+
+	expected := map[string]*sdk.ScalingPolicy{
+		"web": {
+			Type:               sdk.ScalingPolicyTypeCluster,
+			Max:                20,
+			Cooldown:           2 * time.Minute,
+			EvaluationInterval: 30 * time.Second,
+			Checks: []*sdk.ScalingPolicyCheck{
+				{
+					Name:   "cpu",
+					Source: "prometheus",
+					Query:  "avg(cpu)",
+					Strategy: &sdk.ScalingPolicyStrategy{
+						Name:   "target-value",
+						Config: map[string]string{"target": "70"},
+					},
+				},
+			},
+			Target: &sdk.ScalingPolicyTarget{
+				Name:   "aws-asg",
+				Config: map[string]string{"node_drain_deadline": "5m"},
+			},
+		},
+	}
+	assert.Equal(t, expected, templates)
+
+	_, err = LoadTemplates("./test-fixtures/does-not-exist")
+	assert.Error(t, err)
+}
+
+func Test_decodeFile_template(t *testing.T) {
+	policies, err := decodeFile("./test-fixtures/templated-policy.hcl")
+	assert.NoError(t, err)
+	assert.Equal(t, "web", policies["templated-policy"].Template)
+}
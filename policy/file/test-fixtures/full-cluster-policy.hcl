@@ -6,13 +6,63 @@ scaling "full-cluster-policy" {
 
   policy {
 
-    cooldown            = "10m"
-    evaluation_interval = "1m"
+    cooldown                      = "10m"
+    zero_cooldown                 = "1h"
+    cooldown_per_unit             = "1m"
+    max_cooldown                  = "45m"
+    cooldown_on_completion        = true
+    supersede_in_flight           = true
+    scale_in_stabilization_window = "15m"
+    dedup_window                  = "30m"
+    evaluation_interval           = "1m"
+    priority                      = 80
+    soft_max                      = 80
+    on_metric_error               = "hold"
+    quorum                        = 2
+    scale_in_bias                 = 2
+    scale_in_bias_band            = 0.15
+    hysteresis_factor             = 2
+    min_change_count              = 1
+    min_change_percentage         = 10
+    ramp_intervals                = 4
+    respect_manual_override       = true
+    dry_run_sample                = 0.25
+    allow_shared_target           = true
+
+    limits_schedule {
+      timezone = "Europe/Berlin"
+
+      window "business_hours" {
+        days  = "mon-fri"
+        start = "08:00"
+        end   = "18:00"
+        min   = 20
+      }
+
+      window "overnight" {
+        start = "22:00"
+        end   = "06:00"
+        min   = 2
+        max   = 50
+      }
+    }
 
     check "cpu_nomad" {
-      source       = "nomad_apm"
-      query        = "cpu_high-memory"
-      query_window = "1m"
+      source             = "nomad_apm"
+      query              = "cpu_high-memory"
+      query_window       = "1m"
+      metric_window      = 5
+      metric_aggregation = "max"
+      series_queries     = ["cpu_high-compute"]
+      series_aggregation = "sum"
+      transforms         = ["log", "offset(-1)"]
+      preconditions      = ["ready"]
+      priority           = 10
+
+      query_params {
+        window = "30s"
+        rollup = "max"
+      }
 
       strategy "target-value" {
         target = "80"
@@ -20,8 +70,21 @@ scaling "full-cluster-policy" {
     }
 
     check "memory_prom" {
-      source = "prometheus"
-      query  = "nomad_client_allocated_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)"
+      enabled                = false
+      source                 = "prometheus"
+      query                  = "nomad_client_allocated_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)"
+      metric_smoothing       = "ewma"
+      metric_smoothing_alpha = 0.25
+      formula                = "query + (reserved / 2)"
+
+      formula_queries = {
+        reserved = "nomad_client_reserved_memory*100/(nomad_client_allocated_memory+nomad_client_unallocated_memory)"
+      }
+
+      source_config = {
+        address                = "http://prometheus-infra:9090"
+        "header.Authorization" = "Bearer token"
+      }
 
       strategy "target-value" {
         target = "80"
@@ -33,5 +96,10 @@ scaling "full-cluster-policy" {
       node_class          = "high-memory"
       node_drain_deadline = "15m"
     }
+
+    tags {
+      team        = "infra"
+      cost_center = "1234"
+    }
   }
 }
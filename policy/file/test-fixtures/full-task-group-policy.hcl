@@ -6,12 +6,15 @@ scaling "full-task-group-policy" {
 
   policy {
 
-    cooldown            = "1m"
-    evaluation_interval = "30s"
+    cooldown             = "1m"
+    evaluation_interval  = "30s"
+    startup_grace_period = "90s"
 
     check "cpu_nomad" {
-      source = "nomad_apm"
-      query  = "avg_cpu"
+      source         = "nomad_apm"
+      query          = "avg_cpu"
+      query_timeout  = "20s"
+      max_metric_age = "2m"
 
       strategy "target-value" {
         target = "80"
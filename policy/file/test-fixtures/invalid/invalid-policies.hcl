@@ -0,0 +1,40 @@
+scaling "min-greater-than-max" {
+  enabled = true
+  min     = 10
+  max     = 1
+
+  policy {
+    check "cpu" {
+      query = "avg_cpu"
+
+      strategy "target-value" {
+        target = "80"
+      }
+    }
+
+    target "aws-asg" {
+      aws_asg_name = "my-target-asg"
+    }
+  }
+}
+
+scaling "disabled-invalid-priority" {
+  enabled = false
+  max     = 10
+
+  policy {
+    priority = 200
+
+    check "cpu" {
+      query = "avg_cpu"
+
+      strategy "target-value" {
+        target = "80"
+      }
+    }
+
+    target "aws-asg" {
+      aws_asg_name = "my-target-asg"
+    }
+  }
+}
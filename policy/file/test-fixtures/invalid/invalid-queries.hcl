@@ -0,0 +1,37 @@
+scaling "empty-query" {
+  enabled = true
+  max     = 10
+
+  policy {
+    check "cpu" {
+      query = ""
+
+      strategy "target-value" {
+        target = "80"
+      }
+    }
+
+    target "aws-asg" {
+      aws_asg_name = "my-target-asg"
+    }
+  }
+}
+
+scaling "whitespace-query" {
+  enabled = true
+  max     = 10
+
+  policy {
+    check "cpu" {
+      query = "  \t "
+
+      strategy "target-value" {
+        target = "80"
+      }
+    }
+
+    target "aws-asg" {
+      aws_asg_name = "my-target-asg"
+    }
+  }
+}
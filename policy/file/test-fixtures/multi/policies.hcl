@@ -0,0 +1,58 @@
+scaling "cache" {
+  enabled = true
+  min     = 1
+  max     = 10
+  type    = "horizontal"
+
+  policy {
+    check "cpu_nomad" {
+      source = "nomad_apm"
+      query  = "avg_cpu"
+
+      strategy "target-value" {
+        target = "80"
+      }
+    }
+
+    target "nomad" {
+      Group = "cache"
+      Job   = "example"
+    }
+  }
+}
+
+scaling "web" {
+  enabled = true
+  min     = 1
+  max     = 10
+  type    = "horizontal"
+
+  policy {
+    check "cpu_nomad" {
+      source = "nomad_apm"
+      query  = "avg_cpu"
+
+      strategy "target-value" {
+        target = "70"
+      }
+    }
+
+    target "nomad" {
+      Group = "web"
+      Job   = "example"
+    }
+  }
+}
+
+scaling "disabled" {
+  enabled = false
+  max     = 10
+  type    = "horizontal"
+
+  policy {
+    target "nomad" {
+      Group = "disabled"
+      Job   = "example"
+    }
+  }
+}
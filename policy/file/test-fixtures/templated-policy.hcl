@@ -0,0 +1,14 @@
+scaling "templated-policy" {
+  enabled  = true
+  template = "web"
+  min      = 2
+  max      = 10
+
+  policy {
+    cooldown = "5m"
+
+    target "aws-asg" {
+      aws_asg_name = "web-asg"
+    }
+  }
+}
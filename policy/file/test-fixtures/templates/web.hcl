@@ -0,0 +1,21 @@
+scaling "web" {
+  max = 20
+
+  policy {
+    cooldown            = "2m"
+    evaluation_interval = "30s"
+
+    check "cpu" {
+      source = "prometheus"
+      query  = "avg(cpu)"
+
+      strategy "target-value" {
+        target = "70"
+      }
+    }
+
+    target "aws-asg" {
+      node_drain_deadline = "5m"
+    }
+  }
+}
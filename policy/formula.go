@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// formulaQueryNameRegexp matches the names of check FormulaQueries, which
+// must be valid variable names within the formula.
+var formulaQueryNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// MetricFormula is an arithmetic expression which combines the metrics of a
+// check into the single metric passed to its strategy, such as
+// "(requests / 100) + (cpu / 0.7)". It uses the HCL expression syntax, with
+// the metrics referenced by name and without functions.
+type MetricFormula struct {
+	raw  string
+	expr hclsyntax.Expression
+}
+
+// ParseMetricFormula parses the formula of a check, and returns an error if it
+// references a metric which is not in the passed names.
+func ParseMetricFormula(formula string, names []string) (*MetricFormula, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(formula), "formula", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse formula: %v", diags)
+	}
+
+	declared := make(map[string]bool, len(names))
+	for _, n := range names {
+		declared[n] = true
+	}
+
+	for _, t := range expr.Variables() {
+		name := t.RootName()
+		if !declared[name] {
+			return nil, fmt.Errorf("formula references undeclared metric %q", name)
+		}
+		if len(t) > 1 {
+			return nil, fmt.Errorf("formula must reference metric %q by name only", name)
+		}
+	}
+
+	// Functions are not available when evaluating the formula, so reject them
+	// when it is parsed instead.
+	var call *hclsyntax.FunctionCallExpr
+	hclsyntax.VisitAll(expr, func(n hclsyntax.Node) hcl.Diagnostics {
+		if c, ok := n.(*hclsyntax.FunctionCallExpr); ok && call == nil {
+			call = c
+		}
+		return nil
+	})
+	if call != nil {
+		return nil, fmt.Errorf("formula function %q is not supported", call.Name)
+	}
+
+	return &MetricFormula{raw: formula, expr: expr}, nil
+}
+
+// String returns the formula as it was parsed.
+func (f *MetricFormula) String() string {
+	return f.raw
+}
+
+// Evaluate returns the result of the formula for the passed metric values. An
+// error is returned if the result is not a finite number, such as due to a
+// division by zero, rather than passing it to the strategy.
+func (f *MetricFormula) Evaluate(values map[string]float64) (float64, error) {
+	vars := make(map[string]cty.Value, len(values))
+	for n, v := range values {
+		vars[n] = cty.NumberFloatVal(v)
+	}
+
+	v, diags := f.expr.Value(&hcl.EvalContext{Variables: vars})
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("failed to evaluate formula %q: %v", f.raw, diags)
+	}
+
+	v, err := convert.Convert(v, cty.Number)
+	if err != nil || v.IsNull() || !v.IsKnown() {
+		return 0, fmt.Errorf("formula %q must result in a number", f.raw)
+	}
+
+	result, _ := v.AsBigFloat().Float64()
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return 0, fmt.Errorf("formula %q result is not a finite number, check for a division by zero", f.raw)
+	}
+	return result, nil
+}
+
+// ParseCheckFormula parses the Formula of the check, which can reference the
+// result of the check Query as sdk.MetricFormulaQueryName and the results of
+// its FormulaQueries by name.
+func ParseCheckFormula(c *sdk.ScalingPolicyCheck) (*MetricFormula, error) {
+	return ParseMetricFormula(c.Formula, append(formulaQueryNames(c), sdk.MetricFormulaQueryName))
+}
+
+// formulaQueryNames returns the names of the FormulaQueries of the check,
+// sorted so they are queried and reported in a consistent order.
+func formulaQueryNames(c *sdk.ScalingPolicyCheck) []string {
+	names := make([]string, 0, len(c.FormulaQueries))
+	for n := range c.FormulaQueries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
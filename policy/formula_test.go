@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetricFormula(t *testing.T) {
+	testCases := []struct {
+		inputFormula  string
+		inputNames    []string
+		expectedError error
+		name          string
+	}{
+		{
+			inputFormula:  "(requests / 100) + (cpu / 0.7)",
+			inputNames:    []string{"cpu", "requests"},
+			expectedError: nil,
+			name:          "valid formula",
+		},
+		{
+			inputFormula:  "requests / 100 + mem",
+			inputNames:    []string{"cpu", "requests"},
+			expectedError: errors.New(`formula references undeclared metric "mem"`),
+			name:          "undeclared metric",
+		},
+		{
+			inputFormula:  "cpu.value * 2",
+			inputNames:    []string{"cpu"},
+			expectedError: errors.New(`formula must reference metric "cpu" by name only`),
+			name:          "metric attribute",
+		},
+		{
+			inputFormula:  "ceil(cpu)",
+			inputNames:    []string{"cpu"},
+			expectedError: errors.New(`formula function "ceil" is not supported`),
+			name:          "function call",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ParseMetricFormula(tc.inputFormula, tc.inputNames)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.inputFormula, f.String(), tc.name)
+			}
+		})
+	}
+
+	_, err := ParseMetricFormula("cpu +", []string{"cpu"})
+	assert.Error(t, err)
+}
+
+func TestMetricFormula_Evaluate(t *testing.T) {
+	testCases := []struct {
+		inputFormula   string
+		inputValues    map[string]float64
+		expectedOutput float64
+		expectedError  error
+		name           string
+	}{
+		{
+			inputFormula:   "(requests / 100) + (cpu / 0.7)",
+			inputValues:    map[string]float64{"requests": 250, "cpu": 1.4},
+			expectedOutput: 4.5,
+			name:           "arithmetic",
+		},
+		{
+			inputFormula:   "cpu > 0.8 ? cpu * 10 : requests",
+			inputValues:    map[string]float64{"requests": 3, "cpu": 0.5},
+			expectedOutput: 3,
+			name:           "conditional",
+		},
+		{
+			inputFormula:  "requests / cpu",
+			inputValues:   map[string]float64{"requests": 250, "cpu": 0},
+			expectedError: errors.New(`formula "requests / cpu" result is not a finite number, check for a division by zero`),
+			name:          "division by zero",
+		},
+		{
+			inputFormula:  "cpu > 0.8",
+			inputValues:   map[string]float64{"cpu": 0.5},
+			expectedError: errors.New(`formula "cpu > 0.8" must result in a number`),
+			name:          "boolean result",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ParseMetricFormula(tc.inputFormula, []string{"cpu", "requests"})
+			assert.NoError(t, err, tc.name)
+
+			actualOutput, err := f.Evaluate(tc.inputValues)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			assert.InDelta(t, tc.expectedOutput, actualOutput, 1e-9, tc.name)
+		})
+	}
+}
+
+func TestParseCheckFormula(t *testing.T) {
+	check := &sdk.ScalingPolicyCheck{
+		Query:          "requests",
+		FormulaQueries: map[string]string{"cpu": "cpu"},
+		Formula:        "query + cpu",
+	}
+
+	f, err := ParseCheckFormula(check)
+	assert.NoError(t, err)
+
+	actualOutput, err := f.Evaluate(map[string]float64{sdk.MetricFormulaQueryName: 2, "cpu": 3})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), actualOutput)
+}
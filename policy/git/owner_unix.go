@@ -0,0 +1,30 @@
+// +build !windows
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkOwner returns an error if the file is not owned by the user running
+// the agent.
+func checkOwner(path string, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is owned by uid %d rather than the agent user (uid %d)", path, st.Uid, os.Getuid())
+	}
+	return nil
+}
+
+// checkPrivate returns an error if the file is accessible by other users.
+func checkPrivate(path string, fi os.FileInfo) error {
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("%s is accessible by other users (mode %v)", path, perm)
+	}
+	return nil
+}
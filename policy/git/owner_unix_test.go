@@ -0,0 +1,49 @@
+// +build !windows
+
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_checkout_untrustedDir(t *testing.T) {
+	origin, cleanup := testOrigin(t)
+	defer cleanup()
+
+	tmpDir, err := ioutil.TempDir("", "git-clone")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// The default directory must not be accessible by other users, as they
+	// could create it before the agent does.
+	s := testSource(&Config{URL: origin})
+	s.dir = filepath.Join(tmpDir, "default")
+	require.NoError(t, os.Mkdir(s.dir, 0777))
+	require.NoError(t, os.Chmod(s.dir, 0777))
+
+	_, err = s.checkout(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "accessible by other users")
+	assert.NoFileExists(t, filepath.Join(s.dir, ".git"))
+
+	// A clone owned by another user is not reused.
+	if os.Getuid() != 0 {
+		t.Skip("changing the owner of the clone requires root")
+	}
+
+	s = testSource(&Config{URL: origin, CloneDir: filepath.Join(tmpDir, "clone")})
+	_, err = s.checkout(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, os.Chown(filepath.Join(s.dir, ".git"), os.Getuid()+1, -1))
+
+	_, err = s.checkout(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rather than the agent user")
+}
@@ -0,0 +1,399 @@
+package git
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	filePolicy "github.com/hashicorp/nomad-autoscaler/policy/file"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// Ensure Source satisfies the Source interface.
+var _ policy.Source = (*Source)(nil)
+
+const (
+	// DefaultInterval is the interval at which the repository is pulled if
+	// the operator has not configured one.
+	DefaultInterval = time.Minute
+
+	// defaultUsername is the username used to authenticate with a token if
+	// the operator has not configured one. Most Git hosting services accept
+	// any username when authenticating using an access token.
+	defaultUsername = "git"
+
+	// commandTimeout is the maximum time a single git command is allowed to
+	// take.
+	commandTimeout = 5 * time.Minute
+)
+
+// Config is the configuration of the Git policy source.
+type Config struct {
+
+	// URL is the URL of the repository, using any transport supported by
+	// git, such as HTTPS or SSH.
+	URL string
+
+	// Branch is the branch to check out. The default branch of the
+	// repository is checked out if empty.
+	Branch string
+
+	// Path is the directory within the repository which contains the policy
+	// files. The root of the repository is used if empty.
+	Path string
+
+	// Interval is the interval at which the repository is pulled.
+	Interval time.Duration
+
+	// Token is the access token used to authenticate over HTTPS, sent along
+	// with Username.
+	Token    string
+	Username string
+
+	// SSHKeyFile is the private key used to authenticate over SSH.
+	SSHKeyFile string
+
+	// Shallow limits the clone and pulls to the latest commit, which reduces
+	// the time and disk space required for repositories with a long history.
+	Shallow bool
+
+	// CloneDir is the directory the repository is cloned into. A private
+	// directory within the system temporary directory is used if empty. The
+	// directory must be owned by the user running the agent.
+	CloneDir string
+}
+
+// Source is the Git implementation of the policy.Source interface. It keeps a
+// clone of the repository up to date, and reads the policy files from the
+// clone using a file source. Policies are read again whenever a new commit is
+// checked out.
+type Source struct {
+	log    hclog.Logger
+	config *Config
+
+	// dir is the directory the repository is cloned into.
+	dir string
+
+	// private is whether dir is the default directory, which must not be
+	// accessible by other users as its path is predictable.
+	private bool
+
+	// files reads the policies from the policy files within the clone.
+	files policy.Source
+
+	// commit is the commit currently checked out. It is only accessed by the
+	// MonitorIDs routine.
+	commit string
+
+	// reloadChannels help coordinate reloading the of the MonitorIDs routine.
+	reloadCh         chan struct{}
+	reloadCompleteCh chan struct{}
+
+	// commitChs are notified when a new commit is checked out, so each policy
+	// monitor reads its policy again.
+	commitChs     map[chan struct{}]struct{}
+	commitChsLock sync.Mutex
+}
+
+// NewGitSource returns the Git implementation of the policy.Source interface.
+func NewGitSource(log hclog.Logger, config *Config, policyProcessor *policy.Processor) policy.Source {
+	dir, private := config.CloneDir, false
+	if dir == "" {
+		// Use a directory derived from the repository, so the clone is reused
+		// when the source is recreated by a reload or agent restart.
+		sum := md5.Sum([]byte(config.URL + "#" + config.Branch))
+		dir = filepath.Join(os.TempDir(), fmt.Sprintf("nomad-autoscaler-git-%x", sum[:8]))
+		private = true
+	}
+
+	return &Source{
+		log:              log.ResetNamed("git_policy_source").With("url", config.URL),
+		config:           config,
+		dir:              dir,
+		private:          private,
+		files:            filePolicy.NewNamedFileSource(log, policy.SourceNameGit, filepath.Join(dir, config.Path), policyProcessor),
+		reloadCh:         make(chan struct{}),
+		reloadCompleteCh: make(chan struct{}, 1),
+		commitChs:        make(map[chan struct{}]struct{}),
+	}
+}
+
+// Name satisfies the Name function of the policy.Source interface.
+func (s *Source) Name() policy.SourceName {
+	return policy.SourceNameGit
+}
+
+// MonitorIDs satisfies the MonitorIDs function of the policy.Source interface.
+func (s *Source) MonitorIDs(ctx context.Context, req policy.MonitorIDsReq) {
+	s.log.Debug("starting git policy source ID monitor")
+
+	// Pull the repository before the file source first reads the policies.
+	// If this fails the policies within an existing clone are still read.
+	s.pull(ctx, req.ErrCh)
+
+	// The file source is stopped once this routine returns, rather than when
+	// the context is canceled, so it is always running when it is reloaded
+	// below.
+	filesCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.files.MonitorIDs(filesCtx, req)
+
+	interval := s.config.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Trace("stopping git policy source ID monitor")
+			return
+
+		case <-ticker.C:
+			if s.pull(ctx, req.ErrCh) {
+				s.files.ReloadIDsMonitor()
+				s.notifyCommit()
+			}
+
+		case <-s.reloadCh:
+			s.log.Info("git policy source ID monitor received reload signal")
+			s.pull(ctx, req.ErrCh)
+			s.files.ReloadIDsMonitor()
+			s.reloadCompleteCh <- struct{}{}
+		}
+	}
+}
+
+// ReloadIDsMonitor satisfies the ReloadIDsMonitor function of the
+// policy.Source interface.
+func (s *Source) ReloadIDsMonitor() {
+	s.reloadCh <- struct{}{}
+	<-s.reloadCompleteCh
+}
+
+// MonitorPolicy satisfies the MonitorPolicy function of the policy.Source
+// interface. The policy is monitored by the file source, which is also asked
+// to read it again whenever a new commit is checked out.
+func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq) {
+	commitCh := make(chan struct{}, 1)
+	s.commitChsLock.Lock()
+	s.commitChs[commitCh] = struct{}{}
+	s.commitChsLock.Unlock()
+
+	defer func() {
+		s.commitChsLock.Lock()
+		delete(s.commitChs, commitCh)
+		s.commitChsLock.Unlock()
+	}()
+
+	reloadCh := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-req.ReloadCh:
+			case <-commitCh:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case reloadCh <- struct{}{}:
+			}
+		}
+	}()
+
+	s.files.MonitorPolicy(ctx, policy.MonitorPolicyReq{
+		ID:       req.ID,
+		ErrCh:    req.ErrCh,
+		ReloadCh: reloadCh,
+		ResultCh: req.ResultCh,
+	})
+}
+
+// ReadPolicy satisfies the ReadPolicy function of the policy.Source
+// interface. The policy is read from the current clone, and the repository is
+// not pulled.
+func (s *Source) ReadPolicy(ctx context.Context, ID policy.PolicyID) (*sdk.ScalingPolicy, error) {
+	return s.files.ReadPolicy(ctx, ID)
+}
+
+// notifyCommit notifies the policy monitors that a new commit has been
+// checked out. Notifications are dropped if a monitor has not handled the
+// previous one yet, as it will read the latest version of the policy anyway.
+func (s *Source) notifyCommit() {
+	s.commitChsLock.Lock()
+	defer s.commitChsLock.Unlock()
+
+	for ch := range s.commitChs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// pull updates the clone of the repository, and returns whether a new commit
+// has been checked out. Errors are sent to errCh, as the repository may be
+// pulled successfully on the next attempt.
+func (s *Source) pull(ctx context.Context, errCh chan<- error) bool {
+	commit, err := s.checkout(ctx)
+	if err != nil {
+		policy.HandleSourceError(s.Name(), fmt.Errorf("failed to pull git repository: %v", err), errCh)
+		return false
+	}
+
+	if commit == s.commit {
+		return false
+	}
+
+	s.log.Info("checked out new commit", "commit", commit, "previous_commit", s.commit)
+	s.commit = commit
+	return true
+}
+
+// checkout clones the repository, or fetches the latest commit of the branch
+// if it has already been cloned, and returns the commit checked out.
+func (s *Source) checkout(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var depth []string
+	if s.config.Shallow {
+		depth = []string{"--depth", "1"}
+	}
+
+	if err := s.prepareDir(); err != nil {
+		return "", err
+	}
+
+	fi, err := os.Lstat(filepath.Join(s.dir, ".git"))
+	switch {
+	case os.IsNotExist(err):
+		args := append([]string{"clone", "--single-branch"}, depth...)
+		if s.config.Branch != "" {
+			args = append(args, "--branch", s.config.Branch)
+		}
+		if _, err := s.git(ctx, "", append(args, "--", s.config.URL, s.dir)...); err != nil {
+			return "", err
+		}
+
+	case err != nil:
+		return "", err
+
+	default:
+		// Git runs the hooks and commands configured within the clone, so it
+		// must not be reused if it could have been modified by another user.
+		if err := checkOwner(filepath.Join(s.dir, ".git"), fi); err != nil {
+			return "", err
+		}
+
+		ref := s.config.Branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+
+		// Update the remote in case the clone directory was used for another
+		// repository.
+		if _, err := s.git(ctx, s.dir, "remote", "set-url", "origin", s.config.URL); err != nil {
+			return "", err
+		}
+		if _, err := s.git(ctx, s.dir, append(append([]string{"fetch"}, depth...), "origin", ref)...); err != nil {
+			return "", err
+		}
+		if _, err := s.git(ctx, s.dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+	}
+
+	return s.git(ctx, s.dir, "rev-parse", "HEAD")
+}
+
+// prepareDir creates the clone directory if it doesn't exist, and checks it
+// can't be modified by other users before the clone within it is used.
+func (s *Source) prepareDir() error {
+	perm := os.FileMode(0755)
+	if s.private {
+		perm = 0700
+	}
+	if err := os.MkdirAll(s.dir, perm); err != nil {
+		return err
+	}
+
+	// Lstat is used so a symlink planted at the path of the default
+	// directory is rejected rather than followed.
+	fi, err := os.Lstat(s.dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("clone directory %s is not a directory", s.dir)
+	}
+	if err := checkOwner(s.dir, fi); err != nil {
+		return err
+	}
+	if s.private {
+		return checkPrivate(s.dir, fi)
+	}
+	return nil
+}
+
+// git runs the git command with the passed arguments in dir, and returns its
+// trimmed output.
+func (s *Source) git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), s.env()...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// env returns the environment variables used to configure the authentication
+// of the git commands. Credentials are passed using the environment, rather
+// than arguments, so they are not exposed in the process list.
+func (s *Source) env() []string {
+
+	// Fail rather than prompting for credentials which can't be provided.
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+
+	if s.config.Token != "" {
+		username := s.config.Username
+		if username == "" {
+			username = defaultUsername
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + s.config.Token))
+
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+
+	if s.config.SSHKeyFile != "" {
+		env = append(env, "GIT_SSH_COMMAND=ssh -i "+shellQuote(s.config.SSHKeyFile)+" -o IdentitiesOnly=yes")
+	}
+	return env
+}
+
+// shellQuote quotes s so it is passed as a single argument by the shell which
+// runs GIT_SSH_COMMAND.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
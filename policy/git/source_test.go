@@ -0,0 +1,181 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_pull(t *testing.T) {
+	for _, shallow := range []bool{false, true} {
+		origin, cleanup := testOrigin(t)
+		defer cleanup()
+
+		cloneDir, err := ioutil.TempDir("", "git-clone")
+		require.NoError(t, err)
+		defer os.RemoveAll(cloneDir)
+
+		s := testSource(&Config{URL: origin, Path: "policies", Shallow: shallow, CloneDir: cloneDir})
+		errCh := make(chan error, 1)
+
+		// The first pull clones the repository.
+		assert.True(t, s.pull(context.Background(), errCh))
+		assert.FileExists(t, filepath.Join(cloneDir, "policies", "policies.hcl"))
+
+		// Pulling without new commits does not check out a new commit.
+		assert.False(t, s.pull(context.Background(), errCh))
+
+		testCommit(t, origin, "policies/more.hcl")
+		assert.True(t, s.pull(context.Background(), errCh))
+		assert.FileExists(t, filepath.Join(cloneDir, "policies", "more.hcl"))
+		assert.Empty(t, errCh)
+	}
+}
+
+func TestSource_pull_error(t *testing.T) {
+	cloneDir, err := ioutil.TempDir("", "git-clone")
+	require.NoError(t, err)
+	defer os.RemoveAll(cloneDir)
+
+	s := testSource(&Config{URL: filepath.Join(cloneDir, "does-not-exist"), CloneDir: filepath.Join(cloneDir, "clone")})
+	errCh := make(chan error, 1)
+
+	assert.False(t, s.pull(context.Background(), errCh))
+	assert.Contains(t, (<-errCh).Error(), "failed to pull git repository")
+}
+
+func TestSource_MonitorIDs(t *testing.T) {
+	origin, cleanup := testOrigin(t)
+	defer cleanup()
+
+	cloneDir, err := ioutil.TempDir("", "git-clone")
+	require.NoError(t, err)
+	defer os.RemoveAll(cloneDir)
+
+	s := testSource(&Config{URL: origin, Path: "policies", Interval: time.Hour, CloneDir: cloneDir})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultCh := make(chan policy.IDMessage, 1)
+	errCh := make(chan error, 1)
+	go s.MonitorIDs(ctx, policy.MonitorIDsReq{ErrCh: errCh, ResultCh: resultCh})
+
+	msg := <-resultCh
+	assert.Equal(t, policy.SourceNameGit, msg.Source)
+	assert.Len(t, msg.IDs, 2)
+
+	// Reloading pulls the new commit and lists its policies.
+	testCommit(t, origin, "policies/more.hcl")
+	go s.ReloadIDsMonitor()
+
+	select {
+	case msg = <-resultCh:
+		assert.Len(t, msg.IDs, 4)
+	case err := <-errCh:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for policy IDs")
+	}
+}
+
+func TestSource_env(t *testing.T) {
+	testCases := []struct {
+		inputConfig *Config
+		expectedEnv []string
+		name        string
+	}{
+		{
+			inputConfig: &Config{},
+			expectedEnv: []string{"GIT_TERMINAL_PROMPT=0"},
+			name:        "no authentication",
+		},
+		{
+			inputConfig: &Config{Token: "secret"},
+			expectedEnv: []string{
+				"GIT_TERMINAL_PROMPT=0",
+				"GIT_CONFIG_COUNT=1",
+				"GIT_CONFIG_KEY_0=http.extraHeader",
+				"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("git:secret")),
+			},
+			name: "token with default username",
+		},
+		{
+			inputConfig: &Config{Token: "secret", Username: "oauth2"},
+			expectedEnv: []string{
+				"GIT_TERMINAL_PROMPT=0",
+				"GIT_CONFIG_COUNT=1",
+				"GIT_CONFIG_KEY_0=http.extraHeader",
+				"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("oauth2:secret")),
+			},
+			name: "token with username",
+		},
+		{
+			inputConfig: &Config{SSHKeyFile: "/etc/nomad-autoscaler/id_ed25519"},
+			expectedEnv: []string{
+				"GIT_TERMINAL_PROMPT=0",
+				`GIT_SSH_COMMAND=ssh -i '/etc/nomad-autoscaler/id_ed25519' -o IdentitiesOnly=yes`,
+			},
+			name: "ssh key",
+		},
+		{
+			inputConfig: &Config{SSHKeyFile: "/etc/nomad autoscaler/it's $HOME"},
+			expectedEnv: []string{
+				"GIT_TERMINAL_PROMPT=0",
+				`GIT_SSH_COMMAND=ssh -i '/etc/nomad autoscaler/it'\''s $HOME' -o IdentitiesOnly=yes`,
+			},
+			name: "ssh key with shell characters",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedEnv, testSource(tc.inputConfig).env(), tc.name)
+		})
+	}
+}
+
+func testSource(config *Config) *Source {
+	processor := policy.NewProcessor(&policy.ConfigDefaults{}, nil)
+	return NewGitSource(hclog.NewNullLogger(), config, processor).(*Source)
+}
+
+// testOrigin creates a repository containing a commit of the multi policy
+// file fixture of the file source.
+func testOrigin(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "git-origin")
+	require.NoError(t, err)
+
+	testGit(t, dir, "init")
+	testCommit(t, dir, "policies/policies.hcl")
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// testCommit commits a copy of the multi policy file fixture at path.
+func testCommit(t *testing.T, dir, path string) {
+	data, err := ioutil.ReadFile("../file/test-fixtures/multi/policies.hcl")
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), data, 0600))
+
+	testGit(t, dir, "add", path)
+	testGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "add "+path)
+}
+
+func testGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
@@ -2,11 +2,14 @@ package policy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	"github.com/google/go-cmp/cmp"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
@@ -15,10 +18,44 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
 
+var (
+	// ErrPolicyInCooldown is returned when a manual evaluation is requested
+	// for a policy in cooldown without forcing it.
+	ErrPolicyInCooldown = errors.New("policy is in cooldown")
+
+	// ErrPolicyNotReady is returned when a manual evaluation is requested for
+	// a policy which can't currently be evaluated, such as when it is
+	// disabled or its target is not ready.
+	ErrPolicyNotReady = errors.New("policy is not ready to be evaluated")
+)
+
 const (
 	cooldownIgnoreTime = 1 * time.Second
+
+	// completionPollInterval is the interval at which the target status is
+	// polled while waiting for a scaling action to complete, if the policy
+	// does not set an evaluation interval.
+	completionPollInterval = 10 * time.Second
+
+	// completionTimeout is the maximum time to wait for a scaling action to
+	// complete before starting the cooldown regardless.
+	completionTimeout = 30 * time.Minute
 )
 
+// completionCooldown is a request to enter a cooldown period once the target
+// reports the count of a scaling action as reached.
+type completionCooldown struct {
+	cooldown time.Duration
+	action   sdk.InFlightAction
+}
+
+// inFlightAction is a scaling action awaiting completion by the target of a
+// policy which keeps being evaluated, so the action can be superseded.
+type inFlightAction struct {
+	completionCooldown
+	since time.Time
+}
+
 // Handler monitors a policy for changes and controls when them are sent for
 // evaluation.
 type Handler struct {
@@ -42,13 +79,60 @@ type Handler struct {
 	// period.
 	cooldownCh chan time.Duration
 
+	// completionCh is used to notify the handler that it should enter a
+	// cooldown period once the target has completed a scaling action.
+	completionCh chan completionCooldown
+
+	// cooldownUntil is the time at which the current cooldown ends. Policies
+	// which allow the cooldown to be bypassed use it to continue being
+	// evaluated during cooldown rather than blocking, and it is used to
+	// reject manual evaluations during cooldown.
+	cooldownUntil time.Time
+	cooldownLock  sync.RWMutex
+
+	// bypassableCooldown is the duration of the bypassable cooldown which
+	// ends at cooldownUntil. As these cooldowns do not block the handler,
+	// it is used to detect their expiry on the following ticks. It is
+	// protected by cooldownLock.
+	bypassableCooldown time.Duration
+
+	// onCooldownExpired, if set, is called when a cooldown of the policy
+	// expires.
+	onCooldownExpired func(cooldown time.Duration)
+
+	// claimTarget, if set, is called with each version of the policy
+	// received, and returns the IDs of the other policies which scale the
+	// same target without all of them allowing it.
+	claimTarget func(p *sdk.ScalingPolicy) []string
+
+	// targetConflicts, if set, returns the IDs of the other policies which
+	// scale the same target as the policy without all of them allowing it.
+	targetConflicts func() []string
+
+	// policy is the most recent version of the policy received from the
+	// policy source.
+	policy     *sdk.ScalingPolicy
+	policyLock sync.RWMutex
+
+	// startTime is when the handler started running, and is used to identify
+	// evaluations within the policy's startup grace period.
+	startTime time.Time
+
 	// running is used to help keep track if the handler is active or not.
+	// stopped is set once the handler has been stopped, which may happen
+	// before Run is called if the policy is removed right after being added.
 	running     bool
+	stopped     bool
 	runningLock sync.RWMutex
 
 	// ch is used to listen for policy updates.
 	ch chan sdk.ScalingPolicy
 
+	// updateCh is used to receive policies read outside of the policy
+	// source monitor, such as when a reload is requested through the API.
+	// Unlike ch it is owned by the handler and is never closed.
+	updateCh chan sdk.ScalingPolicy
+
 	// errCh is used to listen for errors from the policy source.
 	errCh chan error
 
@@ -68,9 +152,11 @@ func NewHandler(ID PolicyID, log hclog.Logger, pm *manager.PluginManager, ps Sou
 		pluginManager: pm,
 		policySource:  ps,
 		ch:            make(chan sdk.ScalingPolicy),
+		updateCh:      make(chan sdk.ScalingPolicy),
 		errCh:         make(chan error),
 		doneCh:        make(chan struct{}),
 		cooldownCh:    make(chan time.Duration),
+		completionCh:  make(chan completionCooldown),
 		reloadCh:      make(chan struct{}),
 	}
 }
@@ -84,18 +170,31 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation)
 
 	defer h.Stop()
 
-	// Mark the handler as running.
+	// Start with a long ticker until we receive the right interval.
+	// TODO(luiz): make this a config param
+	policyReadTimeout := 3 * time.Minute
+
+	// Mark the handler as running. The ticker is created while holding the
+	// lock since Stop may be called as soon as the handler is running. A
+	// handler which has already been stopped must not start, otherwise
+	// nothing would stop it until ctx is done.
 	h.runningLock.Lock()
+	if h.stopped {
+		h.runningLock.Unlock()
+		return
+	}
+	h.ticker = time.NewTicker(policyReadTimeout)
 	h.running = true
 	h.runningLock.Unlock()
 
+	h.startTime = time.Now()
+
 	// Store a local copy of the policy so we can compare it for changes.
 	var currentPolicy *sdk.ScalingPolicy
 
-	// Start with a long ticker until we receive the right interval.
-	// TODO(luiz): make this a config param
-	policyReadTimeout := 3 * time.Minute
-	h.ticker = time.NewTicker(policyReadTimeout)
+	// inFlight is the scaling action awaiting completion by the target, if
+	// the policy allows in-flight actions to be superseded.
+	var inFlight *inFlightAction
 
 	// Create separate context so we can stop the monitoring Go routine if
 	// doneCh is closed, but ctx is still valid.
@@ -137,10 +236,23 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation)
 			continue
 
 		case p := <-h.ch:
-			h.updateHandler(currentPolicy, &p)
-			currentPolicy = &p
+			currentPolicy = h.receivePolicy(currentPolicy, &p)
+
+		case p := <-h.updateCh:
+			currentPolicy = h.receivePolicy(currentPolicy, &p)
 
 		case <-h.ticker.C:
+			if inFlight != nil {
+				generate := func() (*sdk.ScalingEvaluation, error) { return h.generateEvaluation(currentPolicy) }
+
+				var ok bool
+				if inFlight, ok = h.handleInFlightTick(ctx, evalCh, currentPolicy, inFlight, generate); !ok {
+					// Context was canceled, return to stop the handler.
+					return
+				}
+				continue
+			}
+
 			eval, err := h.handleTick(ctx, currentPolicy)
 			if err != nil {
 				if err == context.Canceled {
@@ -151,16 +263,41 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation)
 				continue
 			}
 
-			if eval != nil {
-				evalCh <- eval
+			if eval != nil && !h.dispatchEval(ctx, evalCh, eval) {
+				// Context was canceled, return to stop the handler.
+				return
 			}
 
 		case ts := <-h.cooldownCh:
-			// Enforce the cooldown which will block until complete.
-			if !h.enforceCooldown(ctx, ts) {
+			if !h.startCooldown(ctx, currentPolicy, ts) {
 				// Context was canceled, return to stop the handler.
 				return
 			}
+
+		case req := <-h.completionCh:
+			// Policies which allow in-flight actions to be superseded keep
+			// being evaluated while the target completes the action. The
+			// action is replaced if a newer one supersedes it.
+			if currentPolicy != nil && currentPolicy.SupersedeInFlight {
+				if inFlight != nil {
+					h.log.Info("superseded in-flight scaling action",
+						"count", inFlight.action.Count, "new_count", req.action.Count)
+					metrics.IncrCounter([]string{"scale", "in_flight", "superseded_count"}, 1)
+				}
+				inFlight = &inFlightAction{completionCooldown: req, since: time.Now()}
+				continue
+			}
+
+			// Wait for the scaling action to complete so the cooldown
+			// doesn't expire while the target is still scaling.
+			status := func() (*sdk.TargetStatus, error) { return h.targetStatus(currentPolicy) }
+			if !h.awaitCompletion(ctx, status, h.completionPollInterval(currentPolicy), req.action.Count) {
+				return
+			}
+
+			if !h.startCooldown(ctx, currentPolicy, req.cooldown) {
+				return
+			}
 		}
 	}
 }
@@ -170,13 +307,94 @@ func (h *Handler) Stop() {
 	h.runningLock.Lock()
 	defer h.runningLock.Unlock()
 
+	if h.stopped {
+		return
+	}
+
+	h.log.Trace("stopping handler")
 	if h.running {
-		h.log.Trace("stopping handler")
 		h.ticker.Stop()
-		close(h.doneCh)
 	}
+	close(h.doneCh)
 
 	h.running = false
+	h.stopped = true
+}
+
+// Update sends a new version of the policy to the handler. It blocks until
+// the handler receives the policy, the handler is stopped or ctx is done.
+func (h *Handler) Update(ctx context.Context, p sdk.ScalingPolicy) error {
+	select {
+	case h.updateCh <- p:
+		return nil
+	case <-h.doneCh:
+		return fmt.Errorf("policy handler stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// receivePolicy updates the handler to use the new version of the policy and
+// returns it so it can be used as the current policy.
+func (h *Handler) receivePolicy(current, next *sdk.ScalingPolicy) *sdk.ScalingPolicy {
+	// Warn about plugins referenced by the policy which are not
+	// configured, so typos are found now rather than at evaluation.
+	if missing := h.missingPlugins(next); len(missing) > 0 {
+		h.log.Warn("policy references plugins which are not configured", "plugins", missing)
+	}
+
+	// Warn about other policies scaling the same target as soon as they are
+	// found, as the policies are not evaluated until the conflict is
+	// resolved.
+	if h.claimTarget != nil {
+		if conflicts := h.claimTarget(next); len(conflicts) > 0 {
+			h.log.Warn("policy scales the same target as other policies, set allow_shared_target on all of them if this is intended",
+				"policy_ids", conflicts)
+		}
+	}
+
+	h.updateHandler(current, next)
+
+	h.policyLock.Lock()
+	h.policy = next
+	h.policyLock.Unlock()
+
+	return next
+}
+
+// Policy returns the most recent version of the policy received by the
+// handler. It returns nil if the policy has not been read yet.
+func (h *Handler) Policy() *sdk.ScalingPolicy {
+	h.policyLock.RLock()
+	defer h.policyLock.RUnlock()
+	return h.policy
+}
+
+// dispatchEval sends the evaluation to the eval channel. If the channel is
+// full the dispatch stalls until a slot is freed; this is logged and measured
+// so operators know to increase the number of workers or the buffer size.
+// The returned bool indicates whether the evaluation was sent, it is false
+// if the context was canceled or the handler was stopped first.
+func (h *Handler) dispatchEval(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation, eval *sdk.ScalingEvaluation) bool {
+	select {
+	case evalCh <- eval:
+		return true
+	default:
+	}
+
+	metrics.IncrCounter([]string{"policy", "eval_dispatch", "stall_count"}, 1)
+	h.log.Warn("evaluation channel is full, waiting to dispatch evaluation; consider increasing eval_buffer_size or the number of workers")
+
+	start := time.Now()
+	select {
+	case evalCh <- eval:
+		metrics.MeasureSince([]string{"policy", "eval_dispatch", "stall_ms"}, start)
+		return true
+	case <-ctx.Done():
+		return false
+	case <-h.doneCh:
+		return false
+	}
 }
 
 func (h *Handler) handleTick(ctx context.Context, policy *sdk.ScalingPolicy) (*sdk.ScalingEvaluation, error) {
@@ -197,6 +415,25 @@ func (h *Handler) handleTick(ctx context.Context, policy *sdk.ScalingPolicy) (*s
 		return nil, nil
 	}
 
+	// Evaluations still run during the startup grace period so their results
+	// are logged, but any scaling action is suppressed.
+	if policy.StartupGracePeriod > 0 && time.Since(h.startTime) < policy.StartupGracePeriod {
+		eval.InStartupGracePeriod = true
+	}
+
+	// Bypassable cooldowns do not block the handler, so their expiry is
+	// detected on the first tick after they end.
+	if cooldown, ok := h.bypassableCooldownExpired(); ok {
+		h.cooldownExpired(cooldown)
+	}
+
+	// If the policy is within a bypassable cooldown, send the evaluation so a
+	// large deviation can still trigger a scale out.
+	if h.inCooldown() && policy.CooldownBypassFactor > 0 {
+		eval.InCooldown = true
+		return eval, nil
+	}
+
 	// If the target status includes a last event meta key, check for cooldown
 	// due to out-of-band events. This is also useful if the Autoscaler has
 	// been re-deployed.
@@ -217,11 +454,27 @@ func (h *Handler) handleTick(ctx context.Context, policy *sdk.ScalingPolicy) (*s
 	// Calculate the remaining time period left on the cooldown. If this is
 	// cooldownIgnoreTime or below, we do not need to enter cooldown. Reasoning
 	// on ignoring small variations can be seen within GH-138.
-	cdPeriod := h.calculateRemainingCooldown(policy.Cooldown, curTime, int64(lastTS))
+	//
+	// If the target is at zero the last event scaled it to zero, so the
+	// policy's zero cooldown applies.
+	cooldown := policy.Cooldown
+	if policy.ZeroCooldown > 0 && eval.TargetStatus.Count == 0 {
+		cooldown = policy.ZeroCooldown
+	}
+	cdPeriod := h.calculateRemainingCooldown(cooldown, curTime, int64(lastTS))
 	if cdPeriod <= cooldownIgnoreTime {
 		return eval, nil
 	}
 
+	// Policies which allow the cooldown to be bypassed are evaluated during
+	// cooldown rather than blocking.
+	if policy.CooldownBypassFactor > 0 {
+		h.log.Debug("scaling policy has been placed into bypassable cooldown", "cooldown", cdPeriod)
+		h.setBypassableCooldown(cdPeriod)
+		eval.InCooldown = true
+		return eval, nil
+	}
+
 	// Enforce the cooldown which will block until complete. A false response
 	// means we did not reach the end of cooldown due to a request to shutdown.
 	if !h.enforceCooldown(ctx, cdPeriod) {
@@ -251,6 +504,15 @@ func (h *Handler) generateEvaluation(policy *sdk.ScalingPolicy) (*sdk.ScalingEva
 		return nil, nil
 	}
 
+	// Policies which scale the same target would fight over its count, so
+	// don't evaluate them unless all of them allow it.
+	if h.targetConflicts != nil {
+		if conflicts := h.targetConflicts(); len(conflicts) > 0 {
+			return nil, fmt.Errorf("policy scales the same target as policies %s, set allow_shared_target on all of them to allow it",
+				strings.Join(conflicts, ", "))
+		}
+	}
+
 	// Dispense an instance of target plugin used by the policy.
 	targetPlugin, err := h.pluginManager.Dispense(policy.Target.Name, sdk.PluginTypeTarget)
 	if err != nil {
@@ -291,6 +553,35 @@ func (h *Handler) generateEvaluation(policy *sdk.ScalingPolicy) (*sdk.ScalingEva
 	return sdk.NewScalingEvaluation(policy, status), nil
 }
 
+// missingPlugins returns a description of each plugin referenced by the policy
+// that has not been configured in the plugin manager. Empty names are ignored
+// as they are handled by the policy validation.
+func (h *Handler) missingPlugins(p *sdk.ScalingPolicy) []string {
+	var missing []string
+
+	check := func(name, pluginType string) {
+		if name != "" && !h.pluginManager.HasPlugin(name, pluginType) {
+			missing = append(missing, fmt.Sprintf("%s %q", pluginType, name))
+		}
+	}
+
+	if p.Target != nil {
+		check(p.Target.Name, sdk.PluginTypeTarget)
+	}
+
+	for _, c := range p.Checks {
+		check(c.Source, sdk.PluginTypeAPM)
+		if c.Strategy != nil {
+			check(c.Strategy.Name, sdk.PluginTypeStrategy)
+		}
+		for _, s := range c.Chain {
+			check(s.Name, sdk.PluginTypeStrategy)
+		}
+	}
+
+	return missing
+}
+
 // updateHandler updates the handler's internal state based on the changes in
 // the policy being monitored.
 func (h *Handler) updateHandler(current, next *sdk.ScalingPolicy) {
@@ -302,11 +593,215 @@ func (h *Handler) updateHandler(current, next *sdk.ScalingPolicy) {
 	}
 
 	// Update ticker if it's the first time we receive the policy or if the
-	// policy's evaluation interval has changed.
+	// policy's evaluation interval has changed, so interval changes made to
+	// the policy are picked up without restarting the handler. The ticker is
+	// replaced while holding the lock since Stop may be called concurrently.
 	if current == nil || current.EvaluationInterval != next.EvaluationInterval {
+		if current != nil {
+			h.log.Debug("policy evaluation interval changed",
+				"from", current.EvaluationInterval, "to", next.EvaluationInterval)
+		}
+
+		h.runningLock.Lock()
 		h.ticker.Stop()
 		h.ticker = time.NewTicker(next.EvaluationInterval)
+		h.runningLock.Unlock()
+	}
+}
+
+// startCooldown places the policy into cooldown for the passed duration. The
+// boolean return details whether or not the cooldown was started without
+// the handler being instructed to exit.
+func (h *Handler) startCooldown(ctx context.Context, policy *sdk.ScalingPolicy, t time.Duration) bool {
+	// Policies which allow the cooldown to be bypassed keep being evaluated,
+	// so only record when the cooldown ends.
+	if policy != nil && policy.CooldownBypassFactor > 0 {
+		h.log.Debug("scaling policy has been placed into bypassable cooldown", "cooldown", t)
+		h.setBypassableCooldown(t)
+		return true
+	}
+
+	// Enforce the cooldown which will block until complete.
+	return h.enforceCooldown(ctx, t)
+}
+
+// awaitCompletion blocks until the target status reports the passed count,
+// completionTimeout is reached, or the handler has been instructed to exit.
+// The boolean return details whether or not the handler should continue.
+func (h *Handler) awaitCompletion(ctx context.Context, status func() (*sdk.TargetStatus, error),
+	interval time.Duration, count int64) bool {
+
+	h.log.Debug("waiting for scaling action to complete before cooldown", "count", count)
+
+	timeout := time.NewTimer(completionTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s, err := status()
+		switch {
+		case err != nil:
+			h.log.Warn("failed to get target status while waiting for scaling action to complete", "error", err)
+		case s == nil:
+			// The target doesn't exist anymore, so it will never complete.
+			return true
+		case s.Count == count:
+			h.log.Debug("scaling action completed", "count", count)
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			h.log.Warn("timeout waiting for scaling action to complete, starting cooldown",
+				"count", count, "timeout", completionTimeout)
+			return true
+		case <-ctx.Done():
+			return false
+		case <-h.doneCh:
+			return false
+		}
+	}
+}
+
+// handleInFlightTick evaluates a policy while its last scaling action is
+// in-flight, so an action in the opposite direction can supersede it. Once
+// the target completes the action, or completionTimeout is reached, the
+// policy enters cooldown as it does when not superseding actions. It returns
+// the action still in-flight, if any, and whether or not the handler should
+// continue.
+func (h *Handler) handleInFlightTick(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation,
+	policy *sdk.ScalingPolicy, inFlight *inFlightAction, generate func() (*sdk.ScalingEvaluation, error)) (*inFlightAction, bool) {
+
+	if time.Since(inFlight.since) >= completionTimeout {
+		h.log.Warn("timeout waiting for scaling action to complete, starting cooldown",
+			"count", inFlight.action.Count, "timeout", completionTimeout)
+		return nil, h.startCooldown(ctx, policy, inFlight.cooldown)
+	}
+
+	eval, err := generate()
+	if err != nil {
+		h.log.Error(err.Error())
+		return inFlight, true
+	}
+	if eval == nil {
+		return inFlight, true
 	}
+
+	if eval.TargetStatus.Count == inFlight.action.Count {
+		h.log.Debug("scaling action completed", "count", inFlight.action.Count)
+		return nil, h.startCooldown(ctx, policy, inFlight.cooldown)
+	}
+
+	action := inFlight.action
+	eval.InFlight = &action
+	return inFlight, h.dispatchEval(ctx, evalCh, eval)
+}
+
+// setCooldownUntil records the time at which the current cooldown ends.
+func (h *Handler) setCooldownUntil(t time.Time) {
+	h.cooldownLock.Lock()
+	defer h.cooldownLock.Unlock()
+	h.cooldownUntil = t
+}
+
+// setBypassableCooldown places the policy into a bypassable cooldown for the
+// passed duration.
+func (h *Handler) setBypassableCooldown(t time.Duration) {
+	h.cooldownLock.Lock()
+	defer h.cooldownLock.Unlock()
+	h.cooldownUntil = time.Now().Add(t)
+	h.bypassableCooldown = t
+}
+
+// bypassableCooldownExpired returns the duration of the bypassable cooldown
+// of the policy if it has expired since the last call.
+func (h *Handler) bypassableCooldownExpired() (time.Duration, bool) {
+	h.cooldownLock.Lock()
+	defer h.cooldownLock.Unlock()
+
+	if h.bypassableCooldown == 0 || time.Now().Before(h.cooldownUntil) {
+		return 0, false
+	}
+
+	t := h.bypassableCooldown
+	h.bypassableCooldown = 0
+	return t, true
+}
+
+// cooldownExpired reports the expiry of a cooldown of the passed duration.
+func (h *Handler) cooldownExpired(t time.Duration) {
+	h.log.Debug("scaling policy cooldown has expired", "cooldown", t)
+	if h.onCooldownExpired != nil {
+		h.onCooldownExpired(t)
+	}
+}
+
+// inCooldown returns whether the policy is currently in cooldown.
+func (h *Handler) inCooldown() bool {
+	h.cooldownLock.RLock()
+	defer h.cooldownLock.RUnlock()
+	return time.Now().Before(h.cooldownUntil)
+}
+
+// Evaluate returns a new evaluation of the handler's policy outside of the
+// policy's evaluation interval, such as when requested through the API. The
+// evaluation is rejected while the policy is in cooldown unless force is
+// set.
+func (h *Handler) Evaluate(triggeredBy string, force bool) (*sdk.ScalingEvaluation, error) {
+	policy := h.Policy()
+	if policy == nil {
+		return nil, ErrPolicyNotReady
+	}
+
+	if !force && h.inCooldown() {
+		return nil, ErrPolicyInCooldown
+	}
+
+	eval, err := h.generateEvaluation(policy)
+	if err != nil {
+		return nil, err
+	}
+	if eval == nil {
+		return nil, ErrPolicyNotReady
+	}
+
+	eval.Trigger = sdk.EvaluationTriggerManual
+	eval.TriggeredBy = triggeredBy
+
+	h.log.Info("manual evaluation requested", "triggered_by", triggeredBy, "force", force)
+	return eval, nil
+}
+
+// completionPollInterval returns the interval at which the target status is
+// polled while waiting for a scaling action to complete.
+func (h *Handler) completionPollInterval(policy *sdk.ScalingPolicy) time.Duration {
+	if policy != nil && policy.EvaluationInterval > 0 {
+		return policy.EvaluationInterval
+	}
+	return completionPollInterval
+}
+
+// targetStatus returns the current status of the target of the passed
+// policy.
+func (h *Handler) targetStatus(policy *sdk.ScalingPolicy) (*sdk.TargetStatus, error) {
+	if policy == nil || policy.Target == nil {
+		return nil, fmt.Errorf("policy has no target")
+	}
+
+	targetPlugin, err := h.pluginManager.Dispense(policy.Target.Name, sdk.PluginTypeTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	targetInst, ok := targetPlugin.Plugin().(targetpkg.Target)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s (%T) is not a target plugin", policy.Target.Name, targetPlugin.Plugin())
+	}
+
+	return targetInst.Status(policy.Target.Config)
 }
 
 // enforceCooldown blocks until the cooldown period has been reached, or the
@@ -318,6 +813,7 @@ func (h *Handler) enforceCooldown(ctx context.Context, t time.Duration) (complet
 	// blocks the ticker making this the only indication of cooldown to
 	// operators.
 	h.log.Debug("scaling policy has been placed into cooldown", "cooldown", t)
+	h.setCooldownUntil(time.Now().Add(t))
 
 	// Using a timer directly is mentioned to be more efficient than
 	// time.After() as long as we ensure to call Stop(). So setup a timer for
@@ -329,6 +825,7 @@ func (h *Handler) enforceCooldown(ctx context.Context, t time.Duration) (complet
 	// on all the channels desired here.
 	select {
 	case <-timer.C:
+		h.cooldownExpired(t)
 		complete = true
 		return
 	case <-ctx.Done():
@@ -1,10 +1,15 @@
 package policy
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,3 +49,291 @@ func TestHandler_calculateRemainingCooldown(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_missingPlugins(t *testing.T) {
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	testCases := []struct {
+		inputPolicy    *sdk.ScalingPolicy
+		expectedOutput []string
+		name           string
+	}{
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Strategy: &sdk.ScalingPolicyStrategy{Name: "target-value"}},
+				},
+			},
+			expectedOutput: nil,
+			name:           "all plugins configured",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Target: &sdk.ScalingPolicyTarget{Name: "aws-asg"},
+				Checks: []*sdk.ScalingPolicyCheck{
+					{
+						Source:   "prometheus",
+						Strategy: &sdk.ScalingPolicyStrategy{Name: "target-valeu"},
+					},
+				},
+			},
+			expectedOutput: []string{`target "aws-asg"`, `apm "prometheus"`, `strategy "target-valeu"`},
+			name:           "plugins not configured",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{
+						Strategy: &sdk.ScalingPolicyStrategy{Name: "target-value"},
+						Chain:    []*sdk.ScalingPolicyStrategy{{Name: "smooth"}},
+					},
+				},
+			},
+			expectedOutput: []string{`strategy "smooth"`},
+			name:           "chained strategy not configured",
+		},
+	}
+
+	h := NewHandler("", hclog.NewNullLogger(), pm, nil)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, h.missingPlugins(tc.inputPolicy), tc.name)
+		})
+	}
+}
+
+func TestHandler_awaitCompletion(t *testing.T) {
+	testCases := []struct {
+		inputCounts    []int64
+		inputErrs      []error
+		inputCount     int64
+		expectedCalls  int
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputCounts:    []int64{5},
+			inputCount:     5,
+			expectedCalls:  1,
+			expectedOutput: true,
+			name:           "count already reached",
+		},
+		{
+			inputCounts:    []int64{2, 3, 4, 5},
+			inputCount:     5,
+			expectedCalls:  4,
+			expectedOutput: true,
+			name:           "count reached after polling",
+		},
+		{
+			inputCounts:    []int64{2, 0, 5},
+			inputErrs:      []error{nil, errors.New("error"), nil},
+			inputCount:     5,
+			expectedCalls:  3,
+			expectedOutput: true,
+			name:           "status errors are retried",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+
+			var calls int
+			status := func() (*sdk.TargetStatus, error) {
+				i := calls
+				calls++
+				if i < len(tc.inputErrs) && tc.inputErrs[i] != nil {
+					return nil, tc.inputErrs[i]
+				}
+				return &sdk.TargetStatus{Ready: true, Count: tc.inputCounts[i]}, nil
+			}
+
+			actualOutput := h.awaitCompletion(context.Background(), status, time.Millisecond, tc.inputCount)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+			assert.Equal(t, tc.expectedCalls, calls, tc.name)
+		})
+	}
+
+	t.Run("handler stopped", func(t *testing.T) {
+		h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+		close(h.doneCh)
+
+		status := func() (*sdk.TargetStatus, error) {
+			return &sdk.TargetStatus{Ready: true, Count: 1}, nil
+		}
+		assert.False(t, h.awaitCompletion(context.Background(), status, time.Hour, 5))
+	})
+}
+
+func TestHandler_cooldownExpired(t *testing.T) {
+	var expired []time.Duration
+	h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+	h.onCooldownExpired = func(cooldown time.Duration) { expired = append(expired, cooldown) }
+
+	// Blocking cooldowns report their expiry once they end.
+	assert.True(t, h.enforceCooldown(context.Background(), 10*time.Millisecond))
+	assert.Equal(t, []time.Duration{10 * time.Millisecond}, expired)
+
+	// Cooldowns interrupted by the handler stopping do not expire.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, h.enforceCooldown(ctx, time.Hour))
+	assert.Len(t, expired, 1)
+
+	// Bypassable cooldowns expire once, after they end.
+	h.setBypassableCooldown(20 * time.Millisecond)
+	_, ok := h.bypassableCooldownExpired()
+	assert.False(t, ok)
+
+	time.Sleep(30 * time.Millisecond)
+	cooldown, ok := h.bypassableCooldownExpired()
+	assert.True(t, ok)
+	assert.Equal(t, 20*time.Millisecond, cooldown)
+
+	_, ok = h.bypassableCooldownExpired()
+	assert.False(t, ok)
+}
+
+func TestHandler_updateHandler(t *testing.T) {
+	h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+	h.ticker = time.NewTicker(time.Hour)
+	defer func() { h.ticker.Stop() }()
+
+	// The first policy received sets the evaluation interval.
+	current := &sdk.ScalingPolicy{EvaluationInterval: 10 * time.Millisecond}
+	h.updateHandler(nil, current)
+	select {
+	case <-h.ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("expected tick at the policy evaluation interval")
+	}
+
+	// Updates which don't change the interval keep the ticker.
+	ticker := h.ticker
+	next := &sdk.ScalingPolicy{EvaluationInterval: 10 * time.Millisecond, Cooldown: time.Minute}
+	h.updateHandler(current, next)
+	assert.Same(t, ticker, h.ticker)
+
+	// Changing the interval reschedules the ticker.
+	current, next = next, &sdk.ScalingPolicy{EvaluationInterval: time.Hour}
+	h.updateHandler(current, next)
+	assert.NotSame(t, ticker, h.ticker)
+	select {
+	case <-h.ticker.C:
+		t.Fatal("unexpected tick after the evaluation interval was increased")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandler_generateEvaluation_targetConflicts(t *testing.T) {
+	h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+	h.targetConflicts = func() []string { return []string{"a", "b"} }
+
+	// Policies which scale the same target as other policies are not
+	// evaluated.
+	eval, err := h.generateEvaluation(&sdk.ScalingPolicy{Enabled: true})
+	assert.Nil(t, eval)
+	assert.EqualError(t, err, "policy scales the same target as policies a, b, set allow_shared_target on all of them to allow it")
+
+	// Disabled policies are skipped before checking for conflicts.
+	eval, err = h.generateEvaluation(&sdk.ScalingPolicy{Enabled: false})
+	assert.Nil(t, eval)
+	assert.NoError(t, err)
+}
+
+func TestHandler_dispatchEval(t *testing.T) {
+	h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+	evalCh := make(chan *sdk.ScalingEvaluation, 1)
+	eval := &sdk.ScalingEvaluation{ID: "eval"}
+
+	// The first eval fits in the buffer.
+	assert.True(t, h.dispatchEval(context.Background(), evalCh, eval))
+
+	// The buffer is now full, so the dispatch stalls until it is drained.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-evalCh
+	}()
+	assert.True(t, h.dispatchEval(context.Background(), evalCh, eval))
+
+	// A stalled dispatch is abandoned once the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, h.dispatchEval(ctx, evalCh, eval))
+	assert.Len(t, evalCh, 1)
+}
+
+func TestHandler_handleInFlightTick(t *testing.T) {
+	policy := &sdk.ScalingPolicy{ID: "id", SupersedeInFlight: true, CooldownOnCompletion: true}
+	action := sdk.InFlightAction{Count: 10, Direction: sdk.ScaleDirectionUp}
+
+	testCases := []struct {
+		inputSince       time.Time
+		inputStatusCount int64
+		inputErr         error
+		expectedInFlight bool
+		expectedEval     bool
+		name             string
+	}{
+		{
+			inputSince:       time.Now(),
+			inputStatusCount: 7,
+			expectedInFlight: true,
+			expectedEval:     true,
+			name:             "action in-flight",
+		},
+		{
+			inputSince:       time.Now(),
+			inputStatusCount: 10,
+			expectedInFlight: false,
+			expectedEval:     false,
+			name:             "action completed",
+		},
+		{
+			inputSince:       time.Now(),
+			inputErr:         errors.New("error"),
+			expectedInFlight: true,
+			expectedEval:     false,
+			name:             "evaluation error",
+		},
+		{
+			inputSince:       time.Now().Add(-completionTimeout),
+			inputStatusCount: 7,
+			expectedInFlight: false,
+			expectedEval:     false,
+			name:             "completion timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler("", hclog.NewNullLogger(), nil, nil)
+			evalCh := make(chan *sdk.ScalingEvaluation, 1)
+			inFlight := &inFlightAction{completionCooldown: completionCooldown{action: action}, since: tc.inputSince}
+
+			generate := func() (*sdk.ScalingEvaluation, error) {
+				if tc.inputErr != nil {
+					return nil, tc.inputErr
+				}
+				return sdk.NewScalingEvaluation(policy, &sdk.TargetStatus{Ready: true, Count: tc.inputStatusCount}), nil
+			}
+
+			actualInFlight, ok := h.handleInFlightTick(context.Background(), evalCh, policy, inFlight, generate)
+			assert.True(t, ok, tc.name)
+			assert.Equal(t, tc.expectedInFlight, actualInFlight != nil, tc.name)
+
+			if !tc.expectedEval {
+				assert.Len(t, evalCh, 0, tc.name)
+				return
+			}
+			eval := <-evalCh
+			assert.Equal(t, &action, eval.InFlight, tc.name)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package policy
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// EncodeHCL serializes the policy into the canonical HCL format used by the
+// file policy source. All values, including those which were defaulted when
+// the policy was parsed, are written so the output describes exactly what the
+// agent understood. Checks and config keys are sorted to keep the output
+// stable.
+func EncodeHCL(p *sdk.ScalingPolicy) []byte {
+	f := hclwrite.NewEmptyFile()
+
+	scaling := f.Body().AppendNewBlock("scaling", []string{p.ID}).Body()
+	scaling.SetAttributeValue("enabled", cty.BoolVal(p.Enabled))
+	scaling.SetAttributeValue("type", cty.StringVal(p.Type))
+	scaling.SetAttributeValue("min", cty.NumberIntVal(p.Min))
+	scaling.SetAttributeValue("max", cty.NumberIntVal(p.Max))
+
+	scaling.AppendNewline()
+	doc := scaling.AppendNewBlock("policy", nil).Body()
+	doc.SetAttributeValue("priority", cty.NumberIntVal(int64(p.Priority)))
+	if p.SoftMax > 0 {
+		doc.SetAttributeValue("soft_max", cty.NumberIntVal(p.SoftMax))
+	}
+	doc.SetAttributeValue("cooldown", cty.StringVal(p.Cooldown.String()))
+	if p.CooldownBypassFactor > 0 {
+		doc.SetAttributeValue("cooldown_bypass_factor", cty.NumberFloatVal(p.CooldownBypassFactor))
+	}
+	if p.ZeroCooldown > 0 {
+		doc.SetAttributeValue("zero_cooldown", cty.StringVal(p.ZeroCooldown.String()))
+	}
+	if p.CooldownPerUnit > 0 {
+		doc.SetAttributeValue("cooldown_per_unit", cty.StringVal(p.CooldownPerUnit.String()))
+	}
+	if p.MaxCooldown > 0 {
+		doc.SetAttributeValue("max_cooldown", cty.StringVal(p.MaxCooldown.String()))
+	}
+	if p.CooldownOnCompletion {
+		doc.SetAttributeValue("cooldown_on_completion", cty.True)
+	}
+	if p.SupersedeInFlight {
+		doc.SetAttributeValue("supersede_in_flight", cty.True)
+	}
+	if p.ScaleInStabilizationWindow > 0 {
+		doc.SetAttributeValue("scale_in_stabilization_window", cty.StringVal(p.ScaleInStabilizationWindow.String()))
+	}
+	if p.StartupGracePeriod > 0 {
+		doc.SetAttributeValue("startup_grace_period", cty.StringVal(p.StartupGracePeriod.String()))
+	}
+	if p.DedupWindow > 0 {
+		doc.SetAttributeValue("dedup_window", cty.StringVal(p.DedupWindow.String()))
+	}
+	doc.SetAttributeValue("evaluation_interval", cty.StringVal(p.EvaluationInterval.String()))
+	if p.OnMetricError != "" {
+		doc.SetAttributeValue("on_metric_error", cty.StringVal(p.OnMetricError))
+	}
+	if p.SafeCount > 0 {
+		doc.SetAttributeValue("safe_count", cty.NumberIntVal(p.SafeCount))
+	}
+	if p.Quorum > 0 {
+		doc.SetAttributeValue("quorum", cty.NumberIntVal(int64(p.Quorum)))
+	}
+	if p.ScaleInBias > 0 {
+		doc.SetAttributeValue("scale_in_bias", cty.NumberIntVal(p.ScaleInBias))
+	}
+	if p.ScaleInBiasBand > 0 {
+		doc.SetAttributeValue("scale_in_bias_band", cty.NumberFloatVal(p.ScaleInBiasBand))
+	}
+	if p.HysteresisFactor > 0 {
+		doc.SetAttributeValue("hysteresis_factor", cty.NumberFloatVal(p.HysteresisFactor))
+	}
+	if p.MinChangeCount > 0 {
+		doc.SetAttributeValue("min_change_count", cty.NumberIntVal(p.MinChangeCount))
+	}
+	if p.MinChangePercentage > 0 {
+		doc.SetAttributeValue("min_change_percentage", cty.NumberFloatVal(p.MinChangePercentage))
+	}
+	if p.RampIntervals > 0 {
+		doc.SetAttributeValue("ramp_intervals", cty.NumberIntVal(int64(p.RampIntervals)))
+	}
+	if p.RespectManualOverride {
+		doc.SetAttributeValue("respect_manual_override", cty.True)
+	}
+	if p.DryRunSample > 0 {
+		doc.SetAttributeValue("dry_run_sample", cty.NumberFloatVal(p.DryRunSample))
+	}
+	if p.LimitsOnly {
+		doc.SetAttributeValue("limits_only", cty.True)
+	}
+	if p.AllowSharedTarget {
+		doc.SetAttributeValue("allow_shared_target", cty.True)
+	}
+
+	if p.LimitsSchedule != nil {
+		doc.AppendNewline()
+		appendLimitsScheduleBlock(doc, p.LimitsSchedule)
+	}
+
+	checks := make([]*sdk.ScalingPolicyCheck, len(p.Checks))
+	copy(checks, p.Checks)
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+
+	for _, c := range checks {
+		doc.AppendNewline()
+		check := doc.AppendNewBlock("check", []string{c.Name}).Body()
+		check.SetAttributeValue("enabled", cty.BoolVal(!c.Disabled))
+		if c.Priority > 0 {
+			check.SetAttributeValue("priority", cty.NumberIntVal(int64(c.Priority)))
+		}
+		check.SetAttributeValue("source", cty.StringVal(c.Source))
+		check.SetAttributeValue("query", cty.StringVal(c.Query))
+		if c.QueryWindow > 0 {
+			check.SetAttributeValue("query_window", cty.StringVal(c.QueryWindow.String()))
+		}
+		if c.QueryTimeout > 0 {
+			check.SetAttributeValue("query_timeout", cty.StringVal(c.QueryTimeout.String()))
+		}
+		if c.MaxMetricAge > 0 {
+			check.SetAttributeValue("max_metric_age", cty.StringVal(c.MaxMetricAge.String()))
+		}
+		if c.PerInstance {
+			check.SetAttributeValue("per_instance", cty.True)
+		}
+		if c.MetricWindow > 0 {
+			check.SetAttributeValue("metric_window", cty.NumberIntVal(int64(c.MetricWindow)))
+			check.SetAttributeValue("metric_aggregation", cty.StringVal(c.MetricAggregation))
+		}
+		if c.MetricSmoothing != "" {
+			check.SetAttributeValue("metric_smoothing", cty.StringVal(c.MetricSmoothing))
+			check.SetAttributeValue("metric_smoothing_alpha", cty.NumberFloatVal(c.MetricSmoothingAlpha))
+		}
+		if len(c.SeriesQueries) > 0 {
+			queries := make([]cty.Value, len(c.SeriesQueries))
+			for i, q := range c.SeriesQueries {
+				queries[i] = cty.StringVal(q)
+			}
+			check.SetAttributeValue("series_queries", cty.ListVal(queries))
+			check.SetAttributeValue("series_aggregation", cty.StringVal(c.SeriesAggregation))
+		}
+		if len(c.FormulaQueries) > 0 {
+			queries := make(map[string]cty.Value, len(c.FormulaQueries))
+			for k, v := range c.FormulaQueries {
+				queries[k] = cty.StringVal(v)
+			}
+			check.SetAttributeValue("formula_queries", cty.MapVal(queries))
+		}
+		if c.Formula != "" {
+			check.SetAttributeValue("formula", cty.StringVal(c.Formula))
+		}
+		if len(c.Transforms) > 0 {
+			transforms := make([]cty.Value, len(c.Transforms))
+			for i, t := range c.Transforms {
+				transforms[i] = cty.StringVal(t)
+			}
+			check.SetAttributeValue("transforms", cty.ListVal(transforms))
+		}
+		if len(c.Preconditions) > 0 {
+			preconditions := make([]cty.Value, len(c.Preconditions))
+			for i, p := range c.Preconditions {
+				preconditions[i] = cty.StringVal(p)
+			}
+			check.SetAttributeValue("preconditions", cty.ListVal(preconditions))
+		}
+		if c.QueryParams != nil {
+			appendQueryParamsBlock(check, c.QueryParams)
+		}
+		if len(c.SourceConfig) > 0 {
+			config := make(map[string]cty.Value, len(c.SourceConfig))
+			for k, v := range c.SourceConfig {
+				config[k] = cty.StringVal(v)
+			}
+			check.SetAttributeValue("source_config", cty.MapVal(config))
+		}
+
+		if c.Strategy != nil {
+			appendPluginBlock(check, "strategy", c.Strategy.Name, c.Strategy.Config)
+		}
+		for _, s := range c.Chain {
+			appendPluginBlock(check, "strategy", s.Name, s.Config)
+		}
+	}
+
+	if p.Target != nil {
+		doc.AppendNewline()
+		appendPluginBlock(doc, "target", p.Target.Name, p.Target.Config)
+	}
+
+	if len(p.Tags) > 0 {
+		doc.AppendNewline()
+		appendTagsBlock(doc, p.Tags)
+	}
+
+	return f.Bytes()
+}
+
+// appendQueryParamsBlock appends the query_params block of a check, omitting
+// parameters which are not set.
+func appendQueryParamsBlock(body *hclwrite.Body, params *sdk.QueryParams) {
+	block := body.AppendNewBlock("query_params", nil).Body()
+	if params.Window > 0 {
+		block.SetAttributeValue("window", cty.StringVal(params.Window.String()))
+	}
+	if params.Rollup != "" {
+		block.SetAttributeValue("rollup", cty.StringVal(params.Rollup))
+	}
+	if params.Aggregator != "" {
+		block.SetAttributeValue("aggregator", cty.StringVal(params.Aggregator))
+	}
+}
+
+// appendLimitsScheduleBlock appends the limits_schedule block of a policy,
+// with its windows sorted by name.
+func appendLimitsScheduleBlock(body *hclwrite.Body, s *sdk.ScalingPolicyLimitsSchedule) {
+	block := body.AppendNewBlock("limits_schedule", nil).Body()
+	if s.Timezone != "" {
+		block.SetAttributeValue("timezone", cty.StringVal(s.Timezone))
+	}
+
+	windows := make([]*sdk.ScalingPolicyLimitsWindow, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		if w != nil {
+			windows = append(windows, w)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Name < windows[j].Name })
+
+	for _, w := range windows {
+		window := block.AppendNewBlock("window", []string{w.Name}).Body()
+		if w.Days != "" {
+			window.SetAttributeValue("days", cty.StringVal(w.Days))
+		}
+		window.SetAttributeValue("start", cty.StringVal(w.Start))
+		window.SetAttributeValue("end", cty.StringVal(w.End))
+		if w.Min != nil {
+			window.SetAttributeValue("min", cty.NumberIntVal(*w.Min))
+		}
+		if w.Max != nil {
+			window.SetAttributeValue("max", cty.NumberIntVal(*w.Max))
+		}
+	}
+}
+
+// appendTagsBlock appends the tags block of a policy, sorted by tag name.
+func appendTagsBlock(body *hclwrite.Body, tags map[string]string) {
+	block := body.AppendNewBlock("tags", nil).Body()
+
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		block.SetAttributeValue(k, cty.StringVal(tags[k]))
+	}
+}
+
+// appendPluginBlock appends a labelled block, such as a strategy or target,
+// whose attributes are the plugin config sorted by key.
+func appendPluginBlock(body *hclwrite.Body, blockType, name string, config map[string]string) {
+	block := body.AppendNewBlock(blockType, []string{name}).Body()
+
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		block.SetAttributeValue(k, cty.StringVal(config[k]))
+	}
+}
@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+const (
+	// LimitSourceStatic indicates the limit is the value set within the
+	// policy.
+	LimitSourceStatic = "static"
+
+	// LimitSourceSchedule indicates the limit is the value set by the active
+	// window of the policy limits schedule.
+	LimitSourceSchedule = "schedule"
+
+	// LimitSourceQuota indicates the limit is the count the target was
+	// capped to by a quota when it was last scaled.
+	LimitSourceQuota = "quota"
+)
+
+// Limit is the effective value of a policy count limit along with the source
+// the value was resolved from.
+type Limit struct {
+	Value  int64
+	Source string
+}
+
+// Limits are the effective count limits of a policy.
+type Limits struct {
+	Min Limit
+	Max Limit
+}
+
+// EffectiveLimits returns the count limits which are applied to the scaling
+// actions of the policy. Policy evaluations and the HTTP API both use this, so
+// the reported limits always match those enforced.
+func EffectiveLimits(p *sdk.ScalingPolicy) Limits {
+	return EffectiveLimitsAt(p, time.Now())
+}
+
+// EffectiveLimitsAt returns the count limits which are applied to the scaling
+// actions of the policy at the time t. The limits of the active window of the
+// policy limits schedule, if any, override the static limits.
+func EffectiveLimitsAt(p *sdk.ScalingPolicy, t time.Time) Limits {
+	limits := Limits{
+		Min: Limit{Value: p.Min, Source: LimitSourceStatic},
+		Max: Limit{Value: p.Max, Source: LimitSourceStatic},
+	}
+
+	if p.LimitsSchedule == nil {
+		return limits
+	}
+
+	w := p.LimitsSchedule.ActiveWindow(t)
+	if w == nil {
+		return limits
+	}
+
+	// Windows whose limits conflict with the policy are rejected when the
+	// policy is validated, but are ignored here in case it wasn't.
+	min, max := windowLimits(p, w)
+	if min > max {
+		return limits
+	}
+
+	if w.Min != nil {
+		limits.Min = Limit{Value: min, Source: LimitSourceSchedule}
+	}
+	if w.Max != nil {
+		limits.Max = Limit{Value: max, Source: LimitSourceSchedule}
+	}
+	return limits
+}
+
+// WithQuotaCap returns the limits with the max lowered to the count the target
+// was capped to by a quota, if it is lower. The cap is applied by the target
+// rather than by policy evaluations, which would otherwise never learn that
+// the quota has grown.
+func (l Limits) WithQuotaCap(count int64) Limits {
+	if count < l.Max.Value {
+		l.Max = Limit{Value: count, Source: LimitSourceQuota}
+	}
+	return l
+}
+
+// windowLimits returns the min and max of the policy while the limits
+// schedule window is active.
+func windowLimits(p *sdk.ScalingPolicy, w *sdk.ScalingPolicyLimitsWindow) (int64, int64) {
+	min, max := p.Min, p.Max
+	if w.Min != nil {
+		min = *w.Min
+	}
+	if w.Max != nil {
+		max = *w.Max
+	}
+	return min, max
+}
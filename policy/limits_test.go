@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveLimits(t *testing.T) {
+	expected := Limits{
+		Min: Limit{Value: 1, Source: LimitSourceStatic},
+		Max: Limit{Value: 10, Source: LimitSourceStatic},
+	}
+	assert.Equal(t, expected, EffectiveLimits(&sdk.ScalingPolicy{Min: 1, Max: 10}))
+}
+
+func TestEffectiveLimitsAt(t *testing.T) {
+	p := &sdk.ScalingPolicy{
+		Min: 2,
+		Max: 10,
+		LimitsSchedule: &sdk.ScalingPolicyLimitsSchedule{
+			Timezone: "Europe/Berlin",
+			Windows: []*sdk.ScalingPolicyLimitsWindow{
+				{Name: "business_hours", Days: "mon-fri", Start: "08:00", End: "18:00", Min: ptr.Int64ToPtr(6)},
+				{Name: "peak", Days: "fri", Start: "17:00", End: "20:00", Min: ptr.Int64ToPtr(8), Max: ptr.Int64ToPtr(20)},
+				{Name: "weekend", Days: "sat,sun", Start: "00:00", End: "24:00", Max: ptr.Int64ToPtr(1)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		inputTime      time.Time
+		expectedLimits Limits
+		name           string
+	}{
+		{
+			// Wednesday 10:00 in Berlin.
+			inputTime: time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC),
+			expectedLimits: Limits{
+				Min: Limit{Value: 6, Source: LimitSourceSchedule},
+				Max: Limit{Value: 10, Source: LimitSourceStatic},
+			},
+			name: "window overrides min",
+		},
+		{
+			// Friday 17:30 in Berlin matches both windows, and the first by
+			// name is used.
+			inputTime: time.Date(2021, 3, 12, 16, 30, 0, 0, time.UTC),
+			expectedLimits: Limits{
+				Min: Limit{Value: 6, Source: LimitSourceSchedule},
+				Max: Limit{Value: 10, Source: LimitSourceStatic},
+			},
+			name: "first window by name",
+		},
+		{
+			// Friday 19:00 in Berlin.
+			inputTime: time.Date(2021, 3, 12, 18, 0, 0, 0, time.UTC),
+			expectedLimits: Limits{
+				Min: Limit{Value: 8, Source: LimitSourceSchedule},
+				Max: Limit{Value: 20, Source: LimitSourceSchedule},
+			},
+			name: "window overrides min and max",
+		},
+		{
+			// Wednesday 07:30 in Berlin, which is 08:30 during summer time
+			// and would be within the window if the timezone was ignored.
+			inputTime: time.Date(2021, 7, 14, 5, 30, 0, 0, time.UTC),
+			expectedLimits: Limits{
+				Min: Limit{Value: 2, Source: LimitSourceStatic},
+				Max: Limit{Value: 10, Source: LimitSourceStatic},
+			},
+			name: "no window during summer time",
+		},
+		{
+			// Saturday 12:00 in Berlin, the window max is below the policy
+			// min so it is ignored.
+			inputTime: time.Date(2021, 3, 13, 11, 0, 0, 0, time.UTC),
+			expectedLimits: Limits{
+				Min: Limit{Value: 2, Source: LimitSourceStatic},
+				Max: Limit{Value: 10, Source: LimitSourceStatic},
+			},
+			name: "conflicting window is ignored",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLimits, EffectiveLimitsAt(p, tc.inputTime))
+		})
+	}
+}
+
+func TestLimits_WithQuotaCap(t *testing.T) {
+	limits := EffectiveLimits(&sdk.ScalingPolicy{Min: 1, Max: 10})
+
+	assert.Equal(t, Limits{
+		Min: Limit{Value: 1, Source: LimitSourceStatic},
+		Max: Limit{Value: 6, Source: LimitSourceQuota},
+	}, limits.WithQuotaCap(6))
+
+	// A cap above the max does not restrict the count any further.
+	assert.Equal(t, limits, limits.WithQuotaCap(12))
+}
@@ -2,6 +2,9 @@ package policy
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -24,23 +27,237 @@ type Manager struct {
 	// handlers are used to track the Go routines monitoring policies.
 	handlers map[PolicyID]*Handler
 
-	// keep is used to mark active policies during reconciliation.
-	keep map[PolicyID]bool
+	// sourceIDs tracks the most recent listing of policy IDs received from
+	// each policy source.
+	sourceIDs map[SourceName][]PolicyID
+
+	// sourceLastListing tracks when each policy source last sent a listing
+	// of policy IDs, or was started if it has not sent one yet. It is used
+	// to detect sources whose watch has silently stopped working.
+	sourceLastListing map[SourceName]time.Time
+
+	// duplicates tracks the policy IDs which have been found in multiple
+	// sources, so the conflict is only logged when first detected.
+	duplicates map[PolicyID]bool
+
+	// sourcePrecedence is the order in which policy sources are used when
+	// multiple sources provide a policy with the same ID. The first source
+	// has the highest precedence.
+	sourcePrecedence []SourceName
 
 	// metricsInterval is the interval at which the agent is configured to emit
 	// metrics. This is used when creating the periodicMetricsReporter.
 	metricsInterval time.Duration
+
+	// sourcesUpdateCh is used to notify the Run loop that the policy sources
+	// have been changed using SetSources.
+	sourcesUpdateCh chan struct{}
+
+	// pendingScaleIns tracks the scale ins recommended by policies with a
+	// scale-in stabilization window, which are waiting for the window to
+	// pass. It is protected by scaleInLock rather than lock, so evaluations
+	// are not blocked by changes to the handlers.
+	pendingScaleIns map[PolicyID]*PendingScaleIn
+	scaleInLock     sync.Mutex
+
+	// softMaxExceeded tracks the policies whose target count is above the
+	// policy's soft max. It is protected by softMaxLock for the same reason
+	// pendingScaleIns has its own lock.
+	softMaxExceeded map[PolicyID]*SoftMaxExceeded
+	softMaxLock     sync.Mutex
+
+	// lastActions tracks the most recent scaling action executed for each
+	// policy. It is protected by lastActionLock for the same reason
+	// pendingScaleIns has its own lock.
+	lastActions    map[PolicyID]*LastAction
+	lastActionLock sync.Mutex
+
+	// metricErrors tracks the number of consecutive evaluations of each
+	// policy in which all checks failed to fetch metrics. It is protected by
+	// metricErrorLock for the same reason pendingScaleIns has its own lock.
+	metricErrors    map[PolicyID]int
+	metricErrorLock sync.Mutex
+
+	// ramps tracks the ramp of each policy with ramp intervals towards the
+	// count recommended by its checks. It is protected by rampLock for the
+	// same reason pendingScaleIns has its own lock.
+	ramps    map[PolicyID]*ramp
+	rampLock sync.Mutex
+
+	// observedCounts tracks the count of the target of each policy as of its
+	// most recent evaluation or scaling action, so changes made outside of
+	// the autoscaler can be detected. It is protected by observedCountLock
+	// for the same reason pendingScaleIns has its own lock.
+	observedCounts    map[PolicyID]int64
+	observedCountLock sync.Mutex
+
+	// quotaCaps tracks the count the target of each policy was capped to by
+	// a quota, as reported by its most recent status. It is protected by
+	// quotaCapLock for the same reason pendingScaleIns has its own lock.
+	quotaCaps    map[PolicyID]int64
+	quotaCapLock sync.Mutex
+
+	// targets tracks the target scaled by each enabled policy, so policies
+	// which scale the same target can be detected. It is protected by
+	// targetLock for the same reason pendingScaleIns has its own lock.
+	targets    map[PolicyID]targetClaim
+	targetLock sync.Mutex
+
+	// cooldownNotifier is notified when the cooldown of a policy expires. It
+	// is nil if no notifier has been set.
+	cooldownNotifier CooldownNotifier
+}
+
+// LastAction details the most recent scaling action executed for a policy,
+// to help understand why the target was scaled to its count.
+type LastAction struct {
+
+	// Time is when the action was submitted to the target.
+	Time time.Time
+
+	// Count and Direction are the count and direction of the action.
+	Count     int64
+	Direction string
+
+	// Reason is the final reason of the action, and ReasonHistory the
+	// previous reasons recorded as strategies and guards modified it, oldest
+	// first.
+	Reason        string
+	ReasonHistory []string
 }
 
-// NewManager returns a new Manager.
-func NewManager(log hclog.Logger, ps map[SourceName]Source, pm *manager.PluginManager, mInt time.Duration) *Manager {
+// SoftMaxExceeded details a policy whose target count is above the policy's
+// advisory soft max.
+type SoftMaxExceeded struct {
+
+	// Since is the time at which the target count was first seen above the
+	// soft max.
+	Since time.Time
+
+	// SoftMax is the policy's soft max.
+	SoftMax int64
+
+	// Count is the most recent target count.
+	Count int64
+}
+
+// SourceHealth details the liveness of a policy source.
+type SourceHealth struct {
+	Name SourceName
+
+	// LastListing is the time at which the source last sent a listing of
+	// policy IDs, or was started if it has not sent one yet.
+	LastListing time.Time
+
+	// Window is the time within which the source is expected to send a
+	// listing. It is zero for sources which only send listings on change,
+	// which are always considered live.
+	Window time.Duration
+
+	// Live is false if the source has not sent a listing within Window.
+	Live bool
+}
+
+// PendingScaleIn is a scale in recommended by a policy which is waiting for
+// the policy's scale-in stabilization window to pass.
+type PendingScaleIn struct {
+
+	// Since is the time at which the policy started recommending scaling in.
+	Since time.Time
+
+	// Window is the policy's scale-in stabilization window.
+	Window time.Duration
+
+	// Remaining is the time left until the window passes. It is only set on
+	// the values returned by PendingScaleIns.
+	Remaining time.Duration
+
+	// Count is the highest count recommended since Since, which is used as
+	// the new count once the window passes.
+	Count int64
+}
+
+// ramp is the progress of a policy towards a count spread over a number of
+// evaluations.
+type ramp struct {
+
+	// from is the target count when the ramp started, and to is the count
+	// the ramp ends at.
+	from int64
+	to   int64
+
+	// intervals is the number of steps of the ramp, and step the number of
+	// steps taken so far.
+	intervals int
+	step      int
+
+	// last is the count of the latest step, which the target is expected to
+	// have when the next step is taken.
+	last int64
+}
+
+// NewManager returns a new Manager. The precedence defines the order in which
+// policy sources are used when multiple sources provide a policy with the
+// same ID; sources not included are used after those listed, ordered by name.
+func NewManager(log hclog.Logger, ps map[SourceName]Source, pm *manager.PluginManager, mInt time.Duration, precedence []SourceName) *Manager {
 	return &Manager{
-		log:             log.ResetNamed("policy_manager"),
-		policySource:    ps,
-		pluginManager:   pm,
-		handlers:        make(map[PolicyID]*Handler),
-		keep:            make(map[PolicyID]bool),
-		metricsInterval: mInt,
+		log:               log.ResetNamed("policy_manager"),
+		policySource:      ps,
+		pluginManager:     pm,
+		handlers:          make(map[PolicyID]*Handler),
+		sourceIDs:         make(map[SourceName][]PolicyID),
+		sourceLastListing: make(map[SourceName]time.Time),
+		duplicates:        make(map[PolicyID]bool),
+		sourcePrecedence:  precedence,
+		metricsInterval:   mInt,
+		sourcesUpdateCh:   make(chan struct{}, 1),
+		pendingScaleIns:   make(map[PolicyID]*PendingScaleIn),
+		softMaxExceeded:   make(map[PolicyID]*SoftMaxExceeded),
+		lastActions:       make(map[PolicyID]*LastAction),
+		metricErrors:      make(map[PolicyID]int),
+		ramps:             make(map[PolicyID]*ramp),
+		observedCounts:    make(map[PolicyID]int64),
+		quotaCaps:         make(map[PolicyID]int64),
+		targets:           make(map[PolicyID]targetClaim),
+	}
+}
+
+// SetSources replaces the policy sources used by the manager. Sources which
+// are unchanged keep running, while removed or replaced sources are stopped
+// along with the handlers of the policies they provided.
+func (m *Manager) SetSources(ps map[SourceName]Source) {
+	m.lock.Lock()
+	m.policySource = ps
+	m.lock.Unlock()
+
+	// Notify the Run loop without blocking; a pending notification already
+	// covers this update.
+	select {
+	case m.sourcesUpdateCh <- struct{}{}:
+	default:
+	}
+}
+
+// SetCooldownNotifier sets the notifier which is notified when the cooldown
+// of a policy expires. It only applies to the handlers created afterwards, so
+// it should be called before Run.
+func (m *Manager) SetCooldownNotifier(n CooldownNotifier) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.cooldownNotifier = n
+}
+
+// cooldownExpiredFunc returns the function called by the handler of the
+// policy identified by id when its cooldown expires. The lock must be held
+// when calling it.
+func (m *Manager) cooldownExpiredFunc(id PolicyID) func(time.Duration) {
+	n := m.cooldownNotifier
+	if n == nil {
+		return nil
+	}
+
+	return func(cooldown time.Duration) {
+		n.CooldownExpired(CooldownExpiry{PolicyID: string(id), Cooldown: cooldown, ExpiredAt: time.Now()})
 	}
 }
 
@@ -60,12 +277,45 @@ func (m *Manager) Run(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation)
 	monitorCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Start the policy source and listen for changes in the list of policy IDs
-	for _, s := range m.policySource {
-		req := MonitorIDsReq{ErrCh: policyIDsErrCh, ResultCh: policyIDsCh}
-		go s.MonitorIDs(monitorCtx, req)
+	// Start the policy sources and listen for changes in the list of policy
+	// IDs. Each source is monitored using its own context so it can be
+	// stopped if it is removed by SetSources.
+	monitors := make(map[SourceName]sourceMonitor)
+	startMonitors := func() {
+		for name, s := range m.policySource {
+			if mon, ok := monitors[name]; ok {
+				if mon.source == s {
+					continue
+				}
+
+				// The source has been replaced so its previous listing can
+				// no longer be trusted.
+				mon.cancel()
+				delete(m.sourceIDs, name)
+			}
+
+			sourceCtx, sourceCancel := context.WithCancel(monitorCtx)
+			monitors[name] = sourceMonitor{source: s, cancel: sourceCancel}
+			m.sourceLastListing[name] = time.Now()
+
+			req := MonitorIDsReq{ErrCh: policyIDsErrCh, ResultCh: policyIDsCh}
+			go s.MonitorIDs(sourceCtx, req)
+		}
+
+		for name, mon := range monitors {
+			if _, ok := m.policySource[name]; !ok {
+				mon.cancel()
+				delete(monitors, name)
+				delete(m.sourceIDs, name)
+				delete(m.sourceLastListing, name)
+			}
+		}
 	}
 
+	m.lock.Lock()
+	startMonitors()
+	m.lock.Unlock()
+
 LOOP:
 	for {
 		select {
@@ -86,49 +336,22 @@ LOOP:
 
 			m.lock.Lock()
 
-			// Reset set of policies to keep. We will remove the policies that
-			// are not in policyIDs to reconcile our state.
-			m.keep = make(map[PolicyID]bool)
-
-			// Iterate over policy IDs and create new handlers if necessary
-			for _, policyID := range policyIDs.IDs {
-
-				// Mark policy as must-keep so it doesn't get removed.
-				m.keep[policyID] = true
-
-				// Check if we already have a handler for this policy.
-				if _, ok := m.handlers[policyID]; ok {
-					m.log.Trace("handler already exists",
-						"policy_id", policyID, "policy_source", policyIDs.Source)
-					continue
-				}
-
-				// Create and store a new handler and use its channels to monitor
-				// the policy for changes.
-				m.log.Trace("creating new handler",
-					"policy_id", policyID, "policy_source", policyIDs.Source)
-
-				h := NewHandler(policyID, m.log, m.pluginManager, m.policySource[policyIDs.Source])
-				m.handlers[policyID] = h
-
-				go func(ID PolicyID) {
-					h.Run(ctx, evalCh)
-
-					// Remove the handler when it stops running.
-					m.lock.Lock()
-					delete(m.handlers, ID)
-					m.lock.Unlock()
-				}(policyID)
+			// Store the latest listing for the source, ignoring listings
+			// from sources which have since been removed.
+			if _, ok := m.policySource[policyIDs.Source]; ok {
+				m.sourceIDs[policyIDs.Source] = policyIDs.IDs
+				m.sourceLastListing[policyIDs.Source] = time.Now()
+				m.reconcileHandlers(ctx, evalCh)
 			}
 
-			// Remove and stop handlers for policies that don't exist anymore
-			// for the source which manages them.
-			for k, h := range m.handlers {
-				if !m.keep[k] && h.policySource.Name() == policyIDs.Source {
-					m.stopHandler(h)
-				}
-			}
+			m.lock.Unlock()
+
+		case <-m.sourcesUpdateCh:
+			m.log.Debug("policy sources updated")
 
+			m.lock.Lock()
+			startMonitors()
+			m.reconcileHandlers(ctx, evalCh)
 			m.lock.Unlock()
 		}
 	}
@@ -141,7 +364,11 @@ LOOP:
 	// of relying on the deferred statements, otherwise the next iteration of
 	// m.Run would be executed before they are complete.
 	m.stopHandlers()
+	m.lock.Lock()
 	m.handlers = make(map[PolicyID]*Handler)
+	m.sourceIDs = make(map[SourceName][]PolicyID)
+	m.sourceLastListing = make(map[SourceName]time.Time)
+	m.lock.Unlock()
 	cancel()
 
 	// Delay the next iteration of m.Run to avoid re-runs to start too often.
@@ -149,6 +376,81 @@ LOOP:
 	go m.Run(ctx, evalCh)
 }
 
+// sourceMonitor tracks a running policy source ID monitor.
+type sourceMonitor struct {
+	source Source
+	cancel context.CancelFunc
+}
+
+// reconcileHandlers works out which source is responsible for each policy
+// using the latest listings, and starts, replaces and stops handlers so
+// every policy has a handler using its responsible source.
+//
+// This method is not thread-safe so a RW lock should be acquired before
+// calling it.
+func (m *Manager) reconcileHandlers(ctx context.Context, evalCh chan<- *sdk.ScalingEvaluation) {
+	owners := m.policyOwners()
+
+	// Iterate over policy IDs and create new handlers if necessary
+	for policyID, source := range owners {
+
+		// Check if we already have a handler for this policy. If the handler
+		// uses a different source, a source with higher precedence now
+		// provides the policy, or the source has been replaced, so it must
+		// be replaced.
+		if h, ok := m.handlers[policyID]; ok {
+			if h.policySource == m.policySource[source] {
+				m.log.Trace("handler already exists",
+					"policy_id", policyID, "policy_source", source)
+				continue
+			}
+
+			m.log.Info("replacing handler due to policy source change",
+				"policy_id", policyID, "old_policy_source", h.policySource.Name(),
+				"new_policy_source", source)
+			m.stopHandler(h)
+		}
+
+		// Create and store a new handler and use its channels to monitor
+		// the policy for changes.
+		m.log.Trace("creating new handler",
+			"policy_id", policyID, "policy_source", source)
+
+		h := NewHandler(policyID, m.log, m.pluginManager, m.policySource[source])
+		h.onCooldownExpired = m.cooldownExpiredFunc(policyID)
+		h.claimTarget, h.targetConflicts = m.targetFuncs(policyID)
+		m.handlers[policyID] = h
+
+		go func(ID PolicyID) {
+			h.Run(ctx, evalCh)
+
+			// Remove the handler and its state when it stops running,
+			// unless it has already been replaced.
+			m.lock.Lock()
+			if m.handlers[ID] == h {
+				delete(m.handlers, ID)
+				m.ResetScaleIn(string(ID))
+				m.ResetSoftMax(string(ID))
+				m.ResetLastAction(string(ID))
+				m.ResetMetricErrors(string(ID))
+				m.ResetRamp(string(ID))
+				m.ResetObservedCount(string(ID))
+				m.ResetQuotaCap(string(ID))
+				m.ResetTarget(string(ID))
+			}
+			m.lock.Unlock()
+		}(policyID)
+	}
+
+	// Remove and stop handlers for policies that don't exist anymore within
+	// any source.
+	for k, h := range m.handlers {
+		if _, ok := owners[k]; !ok {
+			m.stopHandler(h)
+		}
+	}
+}
+
 func (m *Manager) stopHandlers() {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -170,6 +472,72 @@ func (m *Manager) stopHandler(h *Handler) {
 
 	h.Stop()
 	delete(m.handlers, h.policyID)
+	m.ResetScaleIn(string(h.policyID))
+	m.ResetSoftMax(string(h.policyID))
+	m.ResetLastAction(string(h.policyID))
+	m.ResetMetricErrors(string(h.policyID))
+	m.ResetRamp(string(h.policyID))
+	m.ResetObservedCount(string(h.policyID))
+	m.ResetQuotaCap(string(h.policyID))
+	m.ResetTarget(string(h.policyID))
+}
+
+// policyOwners returns the source responsible for each policy ID listed by
+// the policy sources. When multiple sources list the same ID, the source with
+// the highest precedence wins and the others are ignored.
+//
+// This method is not thread-safe so a RW lock should be acquired before
+// calling it.
+func (m *Manager) policyOwners() map[PolicyID]SourceName {
+	owners := make(map[PolicyID]SourceName)
+	duplicates := make(map[PolicyID]bool)
+
+	for _, source := range m.sourceOrder() {
+		for _, policyID := range m.sourceIDs[source] {
+			owner, ok := owners[policyID]
+			if !ok {
+				owners[policyID] = source
+				continue
+			}
+
+			if owner == source {
+				continue
+			}
+
+			duplicates[policyID] = true
+			if !m.duplicates[policyID] {
+				m.log.Warn("policy ID found in multiple policy sources, using source with highest precedence",
+					"policy_id", policyID, "policy_source", owner, "ignored_policy_source", source)
+			}
+		}
+	}
+
+	m.duplicates = duplicates
+	return owners
+}
+
+// sourceOrder returns the names of the configured policy sources, ordered by
+// precedence.
+func (m *Manager) sourceOrder() []SourceName {
+	order := make([]SourceName, 0, len(m.policySource))
+	seen := make(map[SourceName]bool, len(m.policySource))
+
+	for _, name := range m.sourcePrecedence {
+		if _, ok := m.policySource[name]; ok && !seen[name] {
+			order = append(order, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []SourceName
+	for name := range m.policySource {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i] < rest[j] })
+
+	return append(order, rest...)
 }
 
 // EnforceCooldown attempts to enforce cooldown on the policy handler
@@ -193,6 +561,483 @@ func (m *Manager) EnforceCooldown(id string, t time.Duration) {
 	}
 }
 
+// EnforceCooldownOnCompletion attempts to enforce cooldown on the policy
+// handler representing the passed ID once its target completes the passed
+// scaling action, which is used by policies that anchor cooldown to the
+// completion of a scaling action.
+func (m *Manager) EnforceCooldownOnCompletion(id string, t time.Duration, action sdk.InFlightAction) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if handler, ok := m.handlers[PolicyID(id)]; ok && handler.completionCh != nil {
+		handler.completionCh <- completionCooldown{cooldown: t, action: action}
+	} else {
+		m.log.Debug("attempted to set cooldown on non-existent handler", "policy_id", id)
+	}
+}
+
+// StabilizeScaleIn records that the policy identified by the passed ID has
+// recommended scaling in to the passed count. Once scaling in has been
+// recommended consistently for the passed window, it returns true along with
+// the highest count recommended during the window, which is the most
+// conservative scale in. ResetScaleIn must be called when the policy
+// recommends anything other than scaling in, and after scaling in.
+func (m *Manager) StabilizeScaleIn(id string, window time.Duration, count int64) (int64, bool) {
+	m.scaleInLock.Lock()
+	defer m.scaleInLock.Unlock()
+
+	now := time.Now()
+
+	// Start a new window when the policy first recommends scaling in, or if
+	// the window of the policy has changed.
+	pending, ok := m.pendingScaleIns[PolicyID(id)]
+	if !ok || pending.Window != window {
+		pending = &PendingScaleIn{Since: now, Window: window, Count: count}
+		m.pendingScaleIns[PolicyID(id)] = pending
+	}
+
+	if count > pending.Count {
+		pending.Count = count
+	}
+	return pending.Count, now.Sub(pending.Since) >= window
+}
+
+// ResetScaleIn clears the pending scale in of the policy identified by the
+// passed ID, so the next scale in recommendation starts a new window.
+func (m *Manager) ResetScaleIn(id string) {
+	m.scaleInLock.Lock()
+	defer m.scaleInLock.Unlock()
+
+	delete(m.pendingScaleIns, PolicyID(id))
+}
+
+// PendingScaleIns returns the scale ins which are waiting for the scale-in
+// stabilization window of their policy to pass, keyed by policy ID.
+func (m *Manager) PendingScaleIns() map[string]PendingScaleIn {
+	m.scaleInLock.Lock()
+	defer m.scaleInLock.Unlock()
+
+	now := time.Now()
+	out := make(map[string]PendingScaleIn, len(m.pendingScaleIns))
+
+	for id, pending := range m.pendingScaleIns {
+		p := *pending
+		if p.Remaining = p.Window - now.Sub(p.Since); p.Remaining < 0 {
+			p.Remaining = 0
+		}
+		out[string(id)] = p
+	}
+	return out
+}
+
+// SetSoftMaxExceeded records that the target count of the policy identified
+// by the passed ID is above the passed soft max. It returns true if the count
+// was not already above the soft max, which indicates the soft max has just
+// been crossed.
+func (m *Manager) SetSoftMaxExceeded(id string, softMax, count int64) bool {
+	m.softMaxLock.Lock()
+	defer m.softMaxLock.Unlock()
+
+	exceeded, ok := m.softMaxExceeded[PolicyID(id)]
+	if !ok {
+		exceeded = &SoftMaxExceeded{Since: time.Now()}
+		m.softMaxExceeded[PolicyID(id)] = exceeded
+	}
+
+	exceeded.SoftMax = softMax
+	exceeded.Count = count
+	return !ok
+}
+
+// ResetSoftMax clears the record of the target count of the policy identified
+// by the passed ID being above its soft max.
+func (m *Manager) ResetSoftMax(id string) {
+	m.softMaxLock.Lock()
+	defer m.softMaxLock.Unlock()
+
+	delete(m.softMaxExceeded, PolicyID(id))
+}
+
+// SetLastAction records the passed action as the most recent scaling action
+// executed for the policy identified by the passed ID.
+func (m *Manager) SetLastAction(id string, action *sdk.ScalingAction) {
+	m.lastActionLock.Lock()
+	defer m.lastActionLock.Unlock()
+
+	m.lastActions[PolicyID(id)] = &LastAction{
+		Time:          time.Now(),
+		Count:         action.Count,
+		Direction:     action.Direction.String(),
+		Reason:        action.Reason,
+		ReasonHistory: action.ReasonHistory(),
+	}
+}
+
+// LastActionDirection returns the direction of the most recent scaling action
+// executed for the policy identified by the passed ID. It returns
+// sdk.ScaleDirectionNone if no action has been recorded.
+func (m *Manager) LastActionDirection(id string) sdk.ScaleDirection {
+	m.lastActionLock.Lock()
+	defer m.lastActionLock.Unlock()
+
+	action, ok := m.lastActions[PolicyID(id)]
+	if !ok {
+		return sdk.ScaleDirectionNone
+	}
+
+	for _, d := range []sdk.ScaleDirection{sdk.ScaleDirectionUp, sdk.ScaleDirectionDown} {
+		if action.Direction == d.String() {
+			return d
+		}
+	}
+	return sdk.ScaleDirectionNone
+}
+
+// IsDuplicateAction returns whether the passed action is identical to the most
+// recent scaling action executed for the policy identified by the passed ID
+// within the window. Actions are identical when they have the same count and
+// a reason which only differs by its numbers, such as the metric values
+// reported by the strategy.
+func (m *Manager) IsDuplicateAction(id string, window time.Duration, action *sdk.ScalingAction) bool {
+	if window <= 0 {
+		return false
+	}
+
+	m.lastActionLock.Lock()
+	defer m.lastActionLock.Unlock()
+
+	last, ok := m.lastActions[PolicyID(id)]
+	if !ok || time.Since(last.Time) >= window {
+		return false
+	}
+	return last.Count == action.Count && reasonTemplate(last.Reason) == reasonTemplate(action.Reason)
+}
+
+// reasonNumberRe matches the numbers within an action reason.
+var reasonNumberRe = regexp.MustCompile(`[-+]?[0-9]*\.?[0-9]+([eE][-+]?[0-9]+)?`)
+
+// reasonTemplate returns the reason with its numbers replaced by a
+// placeholder, so reasons which only differ by their numbers are equal.
+func reasonTemplate(reason string) string {
+	return reasonNumberRe.ReplaceAllString(reason, "#")
+}
+
+// ResetLastAction clears the most recent scaling action recorded for the
+// policy identified by the passed ID.
+func (m *Manager) ResetLastAction(id string) {
+	m.lastActionLock.Lock()
+	defer m.lastActionLock.Unlock()
+
+	delete(m.lastActions, PolicyID(id))
+}
+
+// RecordMetricError records that all checks of the policy identified by the
+// passed ID failed to fetch metrics, and returns the number of consecutive
+// evaluations in which this happened.
+func (m *Manager) RecordMetricError(id string) int {
+	m.metricErrorLock.Lock()
+	defer m.metricErrorLock.Unlock()
+
+	m.metricErrors[PolicyID(id)]++
+	return m.metricErrors[PolicyID(id)]
+}
+
+// ResetMetricErrors clears the consecutive evaluations in which the checks of
+// the policy identified by the passed ID failed to fetch metrics.
+func (m *Manager) ResetMetricErrors(id string) {
+	m.metricErrorLock.Lock()
+	defer m.metricErrorLock.Unlock()
+
+	delete(m.metricErrors, PolicyID(id))
+}
+
+// RampCount returns the count of the next step of the ramp of the policy
+// identified by the passed ID from the current count towards the count to,
+// spread over the passed number of intervals, along with the number of the
+// step. A new ramp is started from the current count if the policy has no
+// ramp, if the count it is ramping towards or its intervals have changed, or
+// if the current count is not the count of the previous step, such as when
+// the step was not executed or the count was modified outside of the ramp.
+func (m *Manager) RampCount(id string, intervals int, current, to int64) (int64, int) {
+	m.rampLock.Lock()
+	defer m.rampLock.Unlock()
+
+	r, ok := m.ramps[PolicyID(id)]
+	if !ok || r.to != to || r.intervals != intervals || r.last != current {
+		r = &ramp{from: current, to: to, intervals: intervals}
+		m.ramps[PolicyID(id)] = r
+	}
+	r.step++
+
+	// Move an equal share of the way on each step, but always by at least
+	// one so changes smaller than the number of intervals still progress.
+	count := r.from + (r.to-r.from)*int64(r.step)/int64(intervals)
+	switch {
+	case count == current && to > current:
+		count++
+	case count == current && to < current:
+		count--
+	}
+	r.last = count
+
+	if r.step >= intervals || count == to {
+		delete(m.ramps, PolicyID(id))
+	}
+	return count, r.step
+}
+
+// ResetRamp clears the ramp of the policy identified by the passed ID.
+func (m *Manager) ResetRamp(id string) {
+	m.rampLock.Lock()
+	defer m.rampLock.Unlock()
+
+	delete(m.ramps, PolicyID(id))
+}
+
+// ObserveCount records the passed count as the current count of the target of
+// the policy identified by the passed ID. It returns the previously observed
+// count and true if the passed count differs from it, which indicates the
+// count was changed outside of the autoscaler. The observed count must be
+// reset when the autoscaler scales the target, so the count the target
+// reaches is not detected as a change.
+func (m *Manager) ObserveCount(id string, count int64) (int64, bool) {
+	m.observedCountLock.Lock()
+	defer m.observedCountLock.Unlock()
+
+	previous, ok := m.observedCounts[PolicyID(id)]
+	m.observedCounts[PolicyID(id)] = count
+	return previous, ok && previous != count
+}
+
+// ResetObservedCount clears the observed count of the target of the policy
+// identified by the passed ID, so the next observed count is not compared
+// with it. This is used when the count is unknown, such as when scaling the
+// target failed.
+func (m *Manager) ResetObservedCount(id string) {
+	m.observedCountLock.Lock()
+	defer m.observedCountLock.Unlock()
+
+	delete(m.observedCounts, PolicyID(id))
+}
+
+// SetQuotaCap records the count the target of the policy identified by the
+// passed ID was capped to by a quota.
+func (m *Manager) SetQuotaCap(id string, count int64) {
+	m.quotaCapLock.Lock()
+	defer m.quotaCapLock.Unlock()
+
+	m.quotaCaps[PolicyID(id)] = count
+}
+
+// ResetQuotaCap clears the quota cap of the target of the policy identified by
+// the passed ID.
+func (m *Manager) ResetQuotaCap(id string) {
+	m.quotaCapLock.Lock()
+	defer m.quotaCapLock.Unlock()
+
+	delete(m.quotaCaps, PolicyID(id))
+}
+
+// QuotaCap returns the count the target of the policy identified by the passed
+// ID was capped to by a quota. The boolean return indicates whether the target
+// is capped.
+func (m *Manager) QuotaCap(id string) (int64, bool) {
+	m.quotaCapLock.Lock()
+	defer m.quotaCapLock.Unlock()
+
+	count, ok := m.quotaCaps[PolicyID(id)]
+	return count, ok
+}
+
+// targetClaim is the target scaled by an enabled policy.
+type targetClaim struct {
+	hash        uint64
+	allowShared bool
+}
+
+// targetFuncs returns the functions used by the handler of the policy
+// identified by id to claim its target and find conflicting policies.
+func (m *Manager) targetFuncs(id PolicyID) (func(*sdk.ScalingPolicy) []string, func() []string) {
+	claim := func(p *sdk.ScalingPolicy) []string { return m.ClaimTarget(string(id), p) }
+	conflicts := func() []string { return m.TargetConflicts(string(id)) }
+	return claim, conflicts
+}
+
+// ClaimTarget records the target of the passed policy as scaled by the policy
+// identified by the passed ID, replacing the target previously recorded for
+// it. Disabled policies don't scale their target, so they release it instead.
+// It returns the conflicting policies as TargetConflicts does.
+func (m *Manager) ClaimTarget(id string, p *sdk.ScalingPolicy) []string {
+	m.targetLock.Lock()
+	defer m.targetLock.Unlock()
+
+	if p.Enabled && p.Target != nil {
+		m.targets[PolicyID(id)] = targetClaim{hash: p.Target.Hash(), allowShared: p.AllowSharedTarget}
+	} else {
+		delete(m.targets, PolicyID(id))
+	}
+	return m.targetConflicts(PolicyID(id))
+}
+
+// TargetConflicts returns the IDs of the other policies which scale the same
+// target as the policy identified by the passed ID, sorted. It returns nil if
+// all of the policies allow sharing the target.
+func (m *Manager) TargetConflicts(id string) []string {
+	m.targetLock.Lock()
+	defer m.targetLock.Unlock()
+
+	return m.targetConflicts(PolicyID(id))
+}
+
+// targetConflicts implements TargetConflicts. The targetLock must be held
+// when calling it.
+func (m *Manager) targetConflicts(id PolicyID) []string {
+	claim, ok := m.targets[id]
+	if !ok {
+		return nil
+	}
+
+	var ids []string
+	allowed := claim.allowShared
+	for other, c := range m.targets {
+		if other == id || c.hash != claim.hash {
+			continue
+		}
+		ids = append(ids, string(other))
+		allowed = allowed && c.allowShared
+	}
+
+	if allowed {
+		return nil
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ResetTarget releases the target scaled by the policy identified by the
+// passed ID.
+func (m *Manager) ResetTarget(id string) {
+	m.targetLock.Lock()
+	defer m.targetLock.Unlock()
+
+	delete(m.targets, PolicyID(id))
+}
+
+// LastActions returns the most recent scaling action executed for each
+// policy, keyed by policy ID.
+func (m *Manager) LastActions() map[string]LastAction {
+	m.lastActionLock.Lock()
+	defer m.lastActionLock.Unlock()
+
+	out := make(map[string]LastAction, len(m.lastActions))
+	for id, action := range m.lastActions {
+		out[string(id)] = *action
+	}
+	return out
+}
+
+// SoftMaxExceeded returns the policies whose target count is above the
+// policy's soft max, keyed by policy ID.
+func (m *Manager) SoftMaxExceeded() map[string]SoftMaxExceeded {
+	m.softMaxLock.Lock()
+	defer m.softMaxLock.Unlock()
+
+	out := make(map[string]SoftMaxExceeded, len(m.softMaxExceeded))
+	for id, exceeded := range m.softMaxExceeded {
+		out[string(id)] = *exceeded
+	}
+	return out
+}
+
+// SourceHealth returns the liveness of the running policy sources, sorted by
+// name.
+func (m *Manager) SourceHealth() []SourceHealth {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := time.Now()
+	out := make([]SourceHealth, 0, len(m.sourceLastListing))
+
+	for name, last := range m.sourceLastListing {
+		h := SourceHealth{Name: name, LastListing: last, Live: true}
+		if ls, ok := m.policySource[name].(LivenessSource); ok {
+			h.Window = ls.LivenessWindow()
+			h.Live = now.Sub(last) <= h.Window
+		}
+		out = append(out, h)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// PolicyCount returns the number of policies monitored by the manager, and
+// whether all of the policy sources have sent a listing of their policies.
+func (m *Manager) PolicyCount() (int, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.handlers), len(m.sourceIDs) == len(m.policySource)
+}
+
+// GetPolicy returns the policy identified by the passed ID, as understood by
+// the agent after parsing. The boolean return indicates whether the policy
+// was found.
+func (m *Manager) GetPolicy(id string) (*sdk.ScalingPolicy, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	handler, ok := m.handlers[PolicyID(id)]
+	if !ok {
+		return nil, false
+	}
+
+	p := handler.Policy()
+	return p, p != nil
+}
+
+// ReloadPolicy reads the policy identified by the passed ID from the source
+// which owns it and sends the new version to its handler, so the change takes
+// effect without waiting for the source to detect it. The boolean return
+// indicates whether the policy was found.
+func (m *Manager) ReloadPolicy(ctx context.Context, id string) (*sdk.ScalingPolicy, bool, error) {
+	m.lock.RLock()
+	handler, ok := m.handlers[PolicyID(id)]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	p, err := handler.policySource.ReadPolicy(ctx, PolicyID(id))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read policy: %v", err)
+	}
+
+	if err := handler.Update(ctx, *p); err != nil {
+		return nil, true, fmt.Errorf("failed to update policy handler: %v", err)
+	}
+
+	m.log.Info("reloaded policy", "policy_id", id)
+	return p, true, nil
+}
+
+// EvaluatePolicy creates an evaluation of the policy identified by the passed
+// ID outside of its evaluation interval, for example to scale a target on
+// demand. The boolean return indicates whether the policy was found.
+func (m *Manager) EvaluatePolicy(id, triggeredBy string, force bool) (*sdk.ScalingEvaluation, bool, error) {
+	m.lock.RLock()
+	handler, ok := m.handlers[PolicyID(id)]
+	m.lock.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	eval, err := handler.Evaluate(triggeredBy, force)
+	return eval, true, err
+}
+
 // ReloadSources triggers a reload of all the policy sources.
 func (m *Manager) ReloadSources() {
 	m.lock.Lock()
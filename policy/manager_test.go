@@ -0,0 +1,575 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_policyOwners(t *testing.T) {
+	testCases := []struct {
+		inputPrecedence []SourceName
+		inputSourceIDs  map[SourceName][]PolicyID
+		expectedOutput  map[PolicyID]SourceName
+		name            string
+	}{
+		{
+			inputPrecedence: nil,
+			inputSourceIDs: map[SourceName][]PolicyID{
+				SourceNameFile:  {"a", "b"},
+				SourceNameNomad: {"c"},
+			},
+			expectedOutput: map[PolicyID]SourceName{
+				"a": SourceNameFile,
+				"b": SourceNameFile,
+				"c": SourceNameNomad,
+			},
+			name: "no duplicates",
+		},
+		{
+			inputPrecedence: nil,
+			inputSourceIDs: map[SourceName][]PolicyID{
+				SourceNameFile:  {"a", "b"},
+				SourceNameNomad: {"b", "c"},
+			},
+			expectedOutput: map[PolicyID]SourceName{
+				"a": SourceNameFile,
+				"b": SourceNameFile,
+				"c": SourceNameNomad,
+			},
+			name: "duplicate without precedence uses source name order",
+		},
+		{
+			inputPrecedence: []SourceName{SourceNameNomad, SourceNameFile},
+			inputSourceIDs: map[SourceName][]PolicyID{
+				SourceNameFile:  {"a", "b"},
+				SourceNameNomad: {"b", "c"},
+			},
+			expectedOutput: map[PolicyID]SourceName{
+				"a": SourceNameFile,
+				"b": SourceNameNomad,
+				"c": SourceNameNomad,
+			},
+			name: "duplicate with precedence",
+		},
+		{
+			inputPrecedence: []SourceName{SourceNameNomad, SourceNameFile},
+			inputSourceIDs: map[SourceName][]PolicyID{
+				SourceNameFile:  {"a", "b"},
+				SourceNameNomad: {"c"},
+			},
+			expectedOutput: map[PolicyID]SourceName{
+				"a": SourceNameFile,
+				"b": SourceNameFile,
+				"c": SourceNameNomad,
+			},
+			name: "winning source no longer lists duplicate",
+		},
+		{
+			inputPrecedence: []SourceName{"consul", SourceNameNomad},
+			inputSourceIDs: map[SourceName][]PolicyID{
+				SourceNameFile:  {"a"},
+				SourceNameNomad: {"a"},
+			},
+			expectedOutput: map[PolicyID]SourceName{
+				"a": SourceNameNomad,
+			},
+			name: "unconfigured source in precedence",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sources := map[SourceName]Source{SourceNameFile: nil, SourceNameNomad: nil}
+			m := NewManager(hclog.NewNullLogger(), sources, nil, 0, tc.inputPrecedence)
+			m.sourceIDs = tc.inputSourceIDs
+
+			assert.Equal(t, tc.expectedOutput, m.policyOwners(), tc.name)
+		})
+	}
+}
+
+// testSource is a policy source which lists a set of policy IDs. New
+// listings can be sent using updates.
+type testSource struct {
+	name     SourceName
+	ids      []PolicyID
+	updates  chan []PolicyID
+	policies map[PolicyID]*sdk.ScalingPolicy
+}
+
+func (s *testSource) MonitorIDs(ctx context.Context, req MonitorIDsReq) {
+	ids := s.ids
+	for {
+		select {
+		case req.ResultCh <- IDMessage{IDs: ids, Source: s.name}:
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case ids = <-s.updates:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *testSource) MonitorPolicy(ctx context.Context, _ MonitorPolicyReq) { <-ctx.Done() }
+func (s *testSource) Name() SourceName                                      { return s.name }
+func (s *testSource) ReloadIDsMonitor()                                     {}
+
+func (s *testSource) ReadPolicy(_ context.Context, ID PolicyID) (*sdk.ScalingPolicy, error) {
+	p, ok := s.policies[ID]
+	if !ok {
+		return nil, errors.New("policy not found")
+	}
+	return p, nil
+}
+
+func TestManager_SetSources(t *testing.T) {
+	nomadSource := &testSource{name: SourceNameNomad, ids: []PolicyID{"a"}}
+	m := NewManager(hclog.NewNullLogger(), map[SourceName]Source{SourceNameNomad: nomadSource}, nil, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx, make(chan *sdk.ScalingEvaluation))
+
+	// handlerSources returns the source used by the handler of each policy.
+	handlerSources := func() map[PolicyID]Source {
+		m.lock.RLock()
+		defer m.lock.RUnlock()
+
+		sources := make(map[PolicyID]Source)
+		for id, h := range m.handlers {
+			sources[id] = h.policySource
+		}
+		return sources
+	}
+
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(handlerSources(), map[PolicyID]Source{"a": nomadSource})
+	}, time.Second, 10*time.Millisecond)
+
+	// Adding a source starts handlers for its policies.
+	fileSource := &testSource{name: SourceNameFile, ids: []PolicyID{"b"}}
+	m.SetSources(map[SourceName]Source{SourceNameNomad: nomadSource, SourceNameFile: fileSource})
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(handlerSources(), map[PolicyID]Source{"a": nomadSource, "b": fileSource})
+	}, time.Second, 10*time.Millisecond)
+
+	// Replacing a source replaces the handlers of its policies.
+	newFileSource := &testSource{name: SourceNameFile, ids: []PolicyID{"b", "c"}}
+	m.SetSources(map[SourceName]Source{SourceNameNomad: nomadSource, SourceNameFile: newFileSource})
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(handlerSources(),
+			map[PolicyID]Source{"a": nomadSource, "b": newFileSource, "c": newFileSource})
+	}, time.Second, 10*time.Millisecond)
+
+	// Removing a source stops the handlers of its policies.
+	m.SetSources(map[SourceName]Source{SourceNameNomad: nomadSource})
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(handlerSources(), map[PolicyID]Source{"a": nomadSource})
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_ReloadPolicy(t *testing.T) {
+	p := &sdk.ScalingPolicy{ID: "a", EvaluationInterval: time.Hour}
+	source := &testSource{
+		name:     SourceNameNomad,
+		ids:      []PolicyID{"a", "b"},
+		policies: map[PolicyID]*sdk.ScalingPolicy{"a": p},
+	}
+	m := NewManager(hclog.NewNullLogger(), map[SourceName]Source{SourceNameNomad: source}, nil, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx, make(chan *sdk.ScalingEvaluation))
+
+	assert.Eventually(t, func() bool {
+		m.lock.RLock()
+		defer m.lock.RUnlock()
+		return len(m.handlers) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// The reloaded policy is returned and used by the handler.
+	out, found, err := m.ReloadPolicy(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, p, out)
+
+	assert.Eventually(t, func() bool {
+		handlerPolicy, found := m.GetPolicy("a")
+		return found && reflect.DeepEqual(handlerPolicy, p)
+	}, time.Second, 10*time.Millisecond)
+
+	// Errors reading the policy are returned.
+	_, found, err = m.ReloadPolicy(ctx, "b")
+	assert.Error(t, err)
+	assert.True(t, found)
+
+	// Policies without a handler are not found.
+	_, found, err = m.ReloadPolicy(ctx, "c")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestManager_StabilizeScaleIn(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	// The first recommendation starts the window.
+	count, ok := m.StabilizeScaleIn("policy", time.Hour, 3)
+	assert.False(t, ok)
+	assert.Equal(t, int64(3), count)
+
+	// The highest recommended count is used.
+	count, ok = m.StabilizeScaleIn("policy", time.Hour, 5)
+	assert.False(t, ok)
+	assert.Equal(t, int64(5), count)
+	count, _ = m.StabilizeScaleIn("policy", time.Hour, 4)
+	assert.Equal(t, int64(5), count)
+
+	pending := m.PendingScaleIns()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, int64(5), pending["policy"].Count)
+	assert.Equal(t, time.Hour, pending["policy"].Window)
+	assert.True(t, pending["policy"].Remaining > 0 && pending["policy"].Remaining <= time.Hour)
+
+	// Changing the window starts a new one, and the scale in is allowed once
+	// the window has passed.
+	count, _ = m.StabilizeScaleIn("policy", time.Nanosecond, 2)
+	assert.Equal(t, int64(2), count)
+	time.Sleep(time.Millisecond)
+	count, ok = m.StabilizeScaleIn("policy", time.Nanosecond, 1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), count)
+
+	// Resetting clears the pending scale in.
+	m.ResetScaleIn("policy")
+	assert.Empty(t, m.PendingScaleIns())
+}
+
+func TestManager_SoftMaxExceeded(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	// Only the first report indicates the soft max has been crossed.
+	assert.True(t, m.SetSoftMaxExceeded("policy", 5, 6))
+	since := m.SoftMaxExceeded()["policy"].Since
+	assert.False(t, m.SetSoftMaxExceeded("policy", 5, 7))
+
+	exceeded := m.SoftMaxExceeded()
+	assert.Len(t, exceeded, 1)
+	assert.Equal(t, SoftMaxExceeded{Since: since, SoftMax: 5, Count: 7}, exceeded["policy"])
+
+	// Resetting clears the record, so the next report is a new crossing.
+	m.ResetSoftMax("policy")
+	assert.Empty(t, m.SoftMaxExceeded())
+	assert.True(t, m.SetSoftMaxExceeded("policy", 5, 6))
+}
+
+// livenessTestSource is a testSource which is expected to send a listing of
+// policy IDs at least once per window.
+type livenessTestSource struct {
+	*testSource
+	window time.Duration
+}
+
+func (s *livenessTestSource) LivenessWindow() time.Duration { return s.window }
+
+func TestManager_SourceHealth(t *testing.T) {
+	nomadSource := &livenessTestSource{
+		testSource: &testSource{name: SourceNameNomad, updates: make(chan []PolicyID)},
+		window:     100 * time.Millisecond,
+	}
+	fileSource := &testSource{name: SourceNameFile, updates: make(chan []PolicyID)}
+
+	m := NewManager(hclog.NewNullLogger(), map[SourceName]Source{
+		SourceNameNomad: nomadSource,
+		SourceNameFile:  fileSource,
+	}, nil, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx, make(chan *sdk.ScalingEvaluation))
+
+	live := func() map[SourceName]bool {
+		out := make(map[SourceName]bool)
+		for _, h := range m.SourceHealth() {
+			out[h.Name] = h.Live
+		}
+		return out
+	}
+
+	assert.Eventually(t, func() bool { return len(live()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[SourceName]bool{SourceNameFile: true, SourceNameNomad: true}, live())
+
+	// Only sources with a liveness window can become stale.
+	time.Sleep(2 * nomadSource.window)
+	assert.Equal(t, map[SourceName]bool{SourceNameFile: true, SourceNameNomad: false}, live())
+
+	// A new listing, even if unchanged, makes the source live again.
+	nomadSource.updates <- nil
+	assert.Eventually(t, func() bool { return live()[SourceNameNomad] }, time.Second, 10*time.Millisecond)
+
+	health := m.SourceHealth()
+	assert.Equal(t, SourceNameFile, health[0].Name)
+	assert.Zero(t, health[0].Window)
+	assert.Equal(t, SourceNameNomad, health[1].Name)
+	assert.Equal(t, nomadSource.window, health[1].Window)
+}
+
+func TestManager_LastActions(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	action := &sdk.ScalingAction{Count: 8, Direction: sdk.ScaleDirectionUp, Reason: "scaling up"}
+	action.Canonicalize()
+	action.CapCount(1, 5)
+	m.SetLastAction("policy", action)
+
+	last := m.LastActions()["policy"]
+	assert.Equal(t, int64(5), last.Count)
+	assert.Equal(t, "up", last.Direction)
+	assert.Equal(t, "capped count from 8 to 5 to stay within limits", last.Reason)
+	assert.Equal(t, []string{"scaling up"}, last.ReasonHistory)
+
+	// Changes to the action after it was recorded are not reflected.
+	action.Meta["nomad_autoscaler.reason_history"] = []string{"modified"}
+	assert.Equal(t, []string{"scaling up"}, m.LastActions()["policy"].ReasonHistory)
+	assert.Equal(t, sdk.ScaleDirection(sdk.ScaleDirectionUp), m.LastActionDirection("policy"))
+
+	m.ResetLastAction("policy")
+	assert.Empty(t, m.LastActions())
+	assert.Equal(t, sdk.ScaleDirection(sdk.ScaleDirectionNone), m.LastActionDirection("policy"))
+}
+
+func TestManager_IsDuplicateAction(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	action := &sdk.ScalingAction{Count: 10, Direction: sdk.ScaleDirectionUp, Reason: "scaling up because factor is 1.500000"}
+	action.Canonicalize()
+
+	// Without a previous action or window there is nothing to duplicate.
+	assert.False(t, m.IsDuplicateAction("policy", time.Minute, action))
+	m.SetLastAction("policy", action)
+	assert.False(t, m.IsDuplicateAction("policy", 0, action))
+
+	testCases := []struct {
+		inputAction    *sdk.ScalingAction
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputAction:    &sdk.ScalingAction{Count: 10, Reason: "scaling up because factor is 1.500000"},
+			expectedOutput: true,
+			name:           "identical action",
+		},
+		{
+			inputAction:    &sdk.ScalingAction{Count: 10, Reason: "scaling up because factor is 1.250000"},
+			expectedOutput: true,
+			name:           "reason with different numbers",
+		},
+		{
+			inputAction:    &sdk.ScalingAction{Count: 11, Reason: "scaling up because factor is 1.500000"},
+			expectedOutput: false,
+			name:           "different count",
+		},
+		{
+			inputAction:    &sdk.ScalingAction{Count: 10, Reason: "capped count from 12 to 10 to stay within limits"},
+			expectedOutput: false,
+			name:           "different reason",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, m.IsDuplicateAction("policy", time.Minute, tc.inputAction), tc.name)
+		})
+	}
+
+	// Actions are only duplicates within the window.
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, m.IsDuplicateAction("policy", 5*time.Millisecond, action))
+	assert.False(t, m.IsDuplicateAction("other", time.Minute, action))
+}
+
+func TestManager_RampCount(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	// Each step moves an equal share of the way towards the count, and the
+	// final step reaches it.
+	count, step := m.RampCount("policy", 3, 4, 10)
+	assert.Equal(t, int64(6), count)
+	assert.Equal(t, 1, step)
+	count, step = m.RampCount("policy", 3, 6, 10)
+	assert.Equal(t, int64(8), count)
+	assert.Equal(t, 2, step)
+	count, step = m.RampCount("policy", 3, 8, 10)
+	assert.Equal(t, int64(10), count)
+	assert.Equal(t, 3, step)
+
+	// The completed ramp is removed, so the next ramp starts afresh.
+	count, step = m.RampCount("policy", 3, 10, 4)
+	assert.Equal(t, int64(8), count)
+	assert.Equal(t, 1, step)
+
+	// A change in the count being ramped towards restarts the ramp from the
+	// current count.
+	count, step = m.RampCount("policy", 3, 8, 2)
+	assert.Equal(t, int64(6), count)
+	assert.Equal(t, 1, step)
+
+	// As does a current count which isn't the count of the previous step.
+	count, step = m.RampCount("policy", 3, 5, 2)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, 1, step)
+
+	// Changes smaller than the number of intervals still progress.
+	m.ResetRamp("policy")
+	count, _ = m.RampCount("policy", 5, 1, 3)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestManager_ObserveCount(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	// The first observation has nothing to compare with.
+	_, changed := m.ObserveCount("policy", 3)
+	assert.False(t, changed)
+
+	// An unchanged count, or the count of an action executed by the
+	// autoscaler, is not a change.
+	_, changed = m.ObserveCount("policy", 3)
+	assert.False(t, changed)
+	m.ObserveCount("policy", 5)
+	_, changed = m.ObserveCount("policy", 5)
+	assert.False(t, changed)
+
+	// A count which differs from the observed count was changed outside of
+	// the autoscaler.
+	previous, changed := m.ObserveCount("policy", 8)
+	assert.True(t, changed)
+	assert.Equal(t, int64(5), previous)
+
+	// Other policies are not affected.
+	_, changed = m.ObserveCount("other", 1)
+	assert.False(t, changed)
+
+	// Resetting the observed count starts again.
+	m.ResetObservedCount("policy")
+	_, changed = m.ObserveCount("policy", 2)
+	assert.False(t, changed)
+}
+
+func TestManager_QuotaCap(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	_, ok := m.QuotaCap("policy")
+	assert.False(t, ok)
+
+	m.SetQuotaCap("policy", 5)
+	count, ok := m.QuotaCap("policy")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), count)
+
+	m.ResetQuotaCap("policy")
+	_, ok = m.QuotaCap("policy")
+	assert.False(t, ok)
+}
+
+func TestManager_TargetConflicts(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	target := func() *sdk.ScalingPolicyTarget {
+		return &sdk.ScalingPolicyTarget{Name: "target", Config: map[string]string{"Job": "example", "Group": "cache"}}
+	}
+
+	// A single policy doesn't conflict.
+	assert.Empty(t, m.ClaimTarget("a", &sdk.ScalingPolicy{Enabled: true, Target: target()}))
+
+	// Policies scaling the same target conflict with each other.
+	assert.Equal(t, []string{"a"}, m.ClaimTarget("b", &sdk.ScalingPolicy{Enabled: true, Target: target()}))
+	assert.Equal(t, []string{"b"}, m.TargetConflicts("a"))
+
+	// Disabled policies don't scale their target.
+	assert.Empty(t, m.ClaimTarget("c", &sdk.ScalingPolicy{Enabled: false, Target: target()}))
+	assert.Equal(t, []string{"b"}, m.TargetConflicts("a"))
+
+	// Sharing the target must be allowed by all of the policies.
+	m.ClaimTarget("a", &sdk.ScalingPolicy{Enabled: true, Target: target(), AllowSharedTarget: true})
+	assert.Equal(t, []string{"b"}, m.TargetConflicts("a"))
+	m.ClaimTarget("b", &sdk.ScalingPolicy{Enabled: true, Target: target(), AllowSharedTarget: true})
+	assert.Empty(t, m.TargetConflicts("a"))
+	assert.Empty(t, m.TargetConflicts("b"))
+
+	// Releasing the target resolves the conflict.
+	m.ClaimTarget("b", &sdk.ScalingPolicy{Enabled: true, Target: target()})
+	assert.Equal(t, []string{"b"}, m.TargetConflicts("a"))
+	m.ResetTarget("b")
+	assert.Empty(t, m.TargetConflicts("a"))
+}
+
+func TestManager_MetricErrors(t *testing.T) {
+	m := NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)
+
+	// Consecutive metric errors are counted per policy.
+	assert.Equal(t, 1, m.RecordMetricError("policy"))
+	assert.Equal(t, 2, m.RecordMetricError("policy"))
+	assert.Equal(t, 1, m.RecordMetricError("other"))
+
+	m.ResetMetricErrors("policy")
+	assert.Equal(t, 1, m.RecordMetricError("policy"))
+	assert.Equal(t, 2, m.RecordMetricError("other"))
+}
+
+func TestManager_removedPolicies(t *testing.T) {
+	source := &testSource{name: SourceNameNomad, updates: make(chan []PolicyID)}
+	m := NewManager(hclog.NewNullLogger(), map[SourceName]Source{SourceNameNomad: source}, nil, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx, make(chan *sdk.ScalingEvaluation))
+
+	numHandlers := func() int {
+		m.lock.RLock()
+		defer m.lock.RUnlock()
+		return len(m.handlers)
+	}
+
+	// Wait for the manager to be running before taking the baseline number
+	// of goroutines.
+	source.updates <- nil
+	baseline := runtime.NumGoroutine()
+
+	// Repeatedly add and remove policies, including removing them right
+	// after they have been added so handlers can be stopped before they
+	// start running.
+	for i := 0; i < 50; i++ {
+		ids := make([]PolicyID, 20)
+		for j := range ids {
+			ids[j] = PolicyID(fmt.Sprintf("policy-%d-%d", i, j))
+		}
+		source.updates <- ids
+		m.StabilizeScaleIn(string(ids[0]), time.Hour, 1)
+		source.updates <- nil
+	}
+
+	// The goroutines are counted in a plain loop since assert.Eventually
+	// runs the condition in a goroutine of its own. The pending scale ins
+	// are cleared once the last listing has been processed.
+	deadline := time.Now().Add(5 * time.Second)
+	for numHandlers() > 0 || len(m.PendingScaleIns()) > 0 || runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("leaked handlers: %d, pending scale ins: %d, goroutines: %d, expected at most %d",
+				numHandlers(), len(m.PendingScaleIns()), runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,91 @@
+package nomad
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+)
+
+// lintPolicyID is the ID given to scaling policies which have not yet been
+// registered with Nomad, and therefore do not have an ID, during linting.
+const lintPolicyID = "lint"
+
+// ValidateJob validates the scaling policies within a job using the same
+// validation as the Nomad policy source, without connecting to Nomad. This
+// allows policies to be checked before the job is registered.
+func ValidateJob(job *api.Job) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+
+	var mErr *multierror.Error
+
+	for _, tg := range job.TaskGroups {
+		if tg == nil {
+			continue
+		}
+		groupName := stringValue(tg.Name)
+
+		if tg.Scaling != nil {
+			target := map[string]string{
+				"Namespace": stringValue(job.Namespace),
+				"Job":       stringValue(job.ID),
+				"Group":     groupName,
+			}
+			if err := validateScalingPolicy(lintScalingPolicy(tg.Scaling, target)); err != nil {
+				mErr = multierror.Append(mErr, multierror.Prefix(err, fmt.Sprintf("group %q:", groupName)))
+			}
+		}
+
+		for _, task := range tg.Tasks {
+			if task == nil {
+				continue
+			}
+
+			for _, sp := range task.ScalingPolicies {
+				if sp == nil {
+					continue
+				}
+
+				target := map[string]string{
+					"Namespace": stringValue(job.Namespace),
+					"Job":       stringValue(job.ID),
+					"Group":     groupName,
+					"Task":      task.Name,
+				}
+				if err := validateScalingPolicy(lintScalingPolicy(sp, target)); err != nil {
+					mErr = multierror.Append(mErr, multierror.Prefix(err, fmt.Sprintf("task %q:", groupName+"/"+task.Name)))
+				}
+			}
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// lintScalingPolicy returns a copy of the policy with the fields Nomad sets
+// when the job is registered populated, so they don't cause validation to
+// fail.
+func lintScalingPolicy(p *api.ScalingPolicy, target map[string]string) *api.ScalingPolicy {
+	c := *p
+	if c.ID == "" {
+		c.ID = lintPolicyID
+	}
+	if c.Target == nil {
+		c.Target = target
+	}
+	if c.Type == "" {
+		c.Type = "horizontal"
+	}
+	return &c
+}
+
+// stringValue returns the value of the string pointer, or an empty string if
+// it is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJob(t *testing.T) {
+	testCases := []struct {
+		inputFile         string
+		inputUnregistered bool
+		expectError       bool
+		name              string
+	}{
+		{
+			inputFile:   "full-scaling",
+			expectError: false,
+			name:        "valid policy",
+		},
+		{
+			inputFile:         "full-scaling",
+			inputUnregistered: true,
+			expectError:       false,
+			name:              "valid policy not yet registered",
+		},
+		{
+			inputFile:   "missing-scaling",
+			expectError: false,
+			name:        "job without scaling policy",
+		},
+		{
+			inputFile:   "invalid-cooldown",
+			expectError: true,
+			name:        "invalid policy",
+		},
+		{
+			inputFile:         "invalid-missing-query",
+			inputUnregistered: true,
+			expectError:       true,
+			name:              "invalid policy not yet registered",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := TestParseJob(t, fmt.Sprintf("test-fixtures/%s.json.golden", tc.inputFile))
+
+			// Jobs which have not been registered don't have the fields which
+			// are set by Nomad.
+			if tc.inputUnregistered {
+				for _, tg := range job.TaskGroups {
+					if tg.Scaling != nil {
+						tg.Scaling.ID = ""
+						tg.Scaling.Target = nil
+						tg.Scaling.Type = ""
+					}
+				}
+			}
+
+			err := ValidateJob(job)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
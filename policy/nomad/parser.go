@@ -2,9 +2,14 @@ package nomad
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/hashicorp/nomad-autoscaler/plugins"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
 	"github.com/hashicorp/nomad/api"
 )
 
@@ -48,6 +53,152 @@ func parsePolicy(p *api.ScalingPolicy) sdk.ScalingPolicy {
 		to.Cooldown, _ = time.ParseDuration(cooldown)
 	}
 
+	// Parse cooldown_bypass_factor as float64.
+	// Ignore error since we assume policy has been validated.
+	if factor, ok := p.Policy[keyCooldownBypass]; ok {
+		to.CooldownBypassFactor, _ = parseFloat(factor)
+	}
+
+	// Parse zero_cooldown as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if zeroCooldown, ok := p.Policy[keyZeroCooldown].(string); ok {
+		to.ZeroCooldown, _ = time.ParseDuration(zeroCooldown)
+	}
+
+	// Parse cooldown_per_unit as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if perUnit, ok := p.Policy[keyCooldownPerUnit].(string); ok {
+		to.CooldownPerUnit, _ = time.ParseDuration(perUnit)
+	}
+
+	// Parse max_cooldown as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if maxCooldown, ok := p.Policy[keyMaxCooldown].(string); ok {
+		to.MaxCooldown, _ = time.ParseDuration(maxCooldown)
+	}
+
+	// Parse cooldown_on_completion as bool.
+	// Ignore error since we assume policy has been validated.
+	to.CooldownOnCompletion, _ = p.Policy[keyCooldownCompletion].(bool)
+
+	// Parse supersede_in_flight as bool.
+	// Ignore error since we assume policy has been validated.
+	to.SupersedeInFlight, _ = p.Policy[keySupersedeInFlight].(bool)
+
+	// Parse scale_in_stabilization_window as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if window, ok := p.Policy[keyScaleInWindow].(string); ok {
+		to.ScaleInStabilizationWindow, _ = time.ParseDuration(window)
+	}
+
+	// Parse startup_grace_period as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if grace, ok := p.Policy[keyStartupGrace].(string); ok {
+		to.StartupGracePeriod, _ = time.ParseDuration(grace)
+	}
+
+	// Parse dedup_window as time.Duration.
+	// Ignore error since we assume policy has been validated.
+	if window, ok := p.Policy[keyDedupWindow].(string); ok {
+		to.DedupWindow, _ = time.ParseDuration(window)
+	}
+
+	// Parse priority as int.
+	// Ignore error since we assume policy has been validated.
+	if priority, ok := p.Policy[keyPriority]; ok {
+		to.Priority, _ = parseInt(priority)
+	}
+
+	// Parse soft_max as int64.
+	// Ignore error since we assume policy has been validated.
+	if softMax, ok := p.Policy[keySoftMax]; ok {
+		v, _ := parseInt(softMax)
+		to.SoftMax = int64(v)
+	}
+
+	// Parse on_metric_error as string.
+	// Ignore error since we assume policy has been validated.
+	to.OnMetricError, _ = p.Policy[keyOnMetricError].(string)
+
+	// Parse safe_count as int64.
+	// Ignore error since we assume policy has been validated.
+	if safeCount, ok := p.Policy[keySafeCount]; ok {
+		v, _ := parseInt(safeCount)
+		to.SafeCount = int64(v)
+	}
+
+	// Parse quorum as int.
+	// Ignore error since we assume policy has been validated.
+	if quorum, ok := p.Policy[keyQuorum]; ok {
+		to.Quorum, _ = parseInt(quorum)
+	}
+
+	// Parse scale_in_bias as int64.
+	// Ignore error since we assume policy has been validated.
+	if bias, ok := p.Policy[keyScaleInBias]; ok {
+		v, _ := parseInt(bias)
+		to.ScaleInBias = int64(v)
+	}
+
+	// Parse scale_in_bias_band as float64.
+	// Ignore error since we assume policy has been validated.
+	if band, ok := p.Policy[keyScaleInBiasBand]; ok {
+		to.ScaleInBiasBand, _ = parseFloat(band)
+	}
+
+	// Parse hysteresis_factor as float64.
+	// Ignore error since we assume policy has been validated.
+	if factor, ok := p.Policy[keyHysteresisFactor]; ok {
+		to.HysteresisFactor, _ = parseFloat(factor)
+	}
+
+	// Parse min_change_count as int64.
+	// Ignore error since we assume policy has been validated.
+	if minChange, ok := p.Policy[keyMinChangeCount]; ok {
+		v, _ := parseInt(minChange)
+		to.MinChangeCount = int64(v)
+	}
+
+	// Parse min_change_percentage as float64.
+	// Ignore error since we assume policy has been validated.
+	if minChange, ok := p.Policy[keyMinChangePercent]; ok {
+		to.MinChangePercentage, _ = parseFloat(minChange)
+	}
+
+	// Parse ramp_intervals as int.
+	// Ignore error since we assume policy has been validated.
+	if ramp, ok := p.Policy[keyRampIntervals]; ok {
+		to.RampIntervals, _ = parseInt(ramp)
+	}
+
+	// Parse respect_manual_override as bool.
+	// Ignore error since we assume policy has been validated.
+	to.RespectManualOverride, _ = p.Policy[keyManualOverride].(bool)
+
+	// Parse dry_run_sample as float64.
+	// Ignore error since we assume policy has been validated.
+	if sample, ok := p.Policy[keyDryRunSample]; ok {
+		to.DryRunSample, _ = parseFloat(sample)
+	}
+
+	// Parse limits_only as bool.
+	// Ignore error since we assume policy has been validated.
+	to.LimitsOnly, _ = p.Policy[keyLimitsOnly].(bool)
+
+	// Parse allow_shared_target as bool.
+	// Ignore error since we assume policy has been validated.
+	to.AllowSharedTarget, _ = p.Policy[keySharedTarget].(bool)
+
+	// Parse template as string.
+	// Ignore error since we assume policy has been validated.
+	to.Template, _ = p.Policy[keyTemplate].(string)
+
+	// Parse tags block.
+	to.Tags = parseTags(p.Policy[keyTags])
+
+	// Parse limits_schedule block.
+	to.LimitsSchedule = parseLimitsSchedule(p.Policy[keyLimitsSchedule])
+
 	// Parse target block.
 	var target *sdk.ScalingPolicyTarget
 
@@ -65,6 +216,12 @@ func parsePolicy(p *api.ScalingPolicy) sdk.ScalingPolicy {
 			}
 		}
 	}
+
+	// Horizontal policies without a target block scale the task group
+	// identified by the Target field, which is done by the Nomad target.
+	if target != nil && target.Name == "" && (to.Type == "" || to.Type == sdk.ScalingPolicyTypeHorizontal) {
+		target.Name = plugins.InternalTargetNomad
+	}
 	to.Target = target
 
 	return to
@@ -105,10 +262,15 @@ func parseChecks(cs interface{}) []*sdk.ScalingPolicyCheck {
 //    policy {
 //    +--------------------------------+
 //    | check "name" {                 |
+//    |   enabled = true               |
+//    |   priority = 10                |
 //    |   source = "source"            |
 //    |   query = "query"              |
 //    |   query_window = "5m"          |
+//    |   query_params { ... }         |
+//    |   source_config = { ... }      |
 //    |   strategy "strategy" { ... }  |
+//    |   strategy "chained" { ... }   |
 //    | }                              |
 //    +--------------------------------+
 //    }
@@ -123,14 +285,14 @@ func parseCheck(c interface{}) *sdk.ScalingPolicyCheck {
 		return nil
 	}
 
-	// Parse a single strategy block.
-	// There shouldn't be more than one, but iterate just in case.
+	// The first strategy block is the check strategy, and any others form
+	// the chain of strategies run after it.
 	var strategy *sdk.ScalingPolicyStrategy
-	for k, v := range parseBlocks(checkMap[keyStrategy]) {
-		strategy = parseStrategy(v)
-		if strategy != nil {
-			strategy.Name = k
-			break
+	var chain []*sdk.ScalingPolicyStrategy
+	if strategies := parseStrategies(checkMap[keyStrategy]); len(strategies) > 0 {
+		strategy = strategies[0]
+		if len(strategies) > 1 {
+			chain = strategies[1:]
 		}
 	}
 
@@ -144,14 +306,326 @@ func parseCheck(c interface{}) *sdk.ScalingPolicyCheck {
 		queryWindow, _ = time.ParseDuration(queryWindowStr)
 	}
 
+	// Parse query_timeout ignoring errors since we assume policy has been
+	// validated.
+	var queryTimeout time.Duration
+	if queryTimeoutStr, ok := checkMap[keyQueryTimeout].(string); ok {
+		queryTimeout, _ = time.ParseDuration(queryTimeoutStr)
+	}
+
+	// Parse max_metric_age ignoring errors since we assume policy has been
+	// validated.
+	var maxMetricAge time.Duration
+	if maxMetricAgeStr, ok := checkMap[keyMaxMetricAge].(string); ok {
+		maxMetricAge, _ = time.ParseDuration(maxMetricAgeStr)
+	}
+
+	// Checks are enabled unless explicitly disabled.
+	enabled, ok := checkMap[keyEnabled].(bool)
+
+	// Parse metric_window ignoring errors since we assume policy has been
+	// validated.
+	var metricWindow int
+	if v, ok := checkMap[keyMetricWindow]; ok {
+		metricWindow, _ = parseInt(v)
+	}
+	metricAggregation, _ := checkMap[keyMetricAggregation].(string)
+	metricSmoothing, _ := checkMap[keyMetricSmoothing].(string)
+
+	// Parse metric_smoothing_alpha as float64 ignoring errors since we assume
+	// policy has been validated.
+	var smoothingAlpha float64
+	if v, ok := checkMap[keySmoothingAlpha]; ok {
+		smoothingAlpha, _ = parseFloat(v)
+	}
+	perInstance, _ := checkMap[keyPerInstance].(bool)
+
+	// Parse series_queries ignoring invalid values since we assume policy
+	// has been validated.
+	var seriesQueries []string
+	if queries, ok := checkMap[keySeriesQueries].([]interface{}); ok {
+		for _, q := range queries {
+			if s, ok := q.(string); ok {
+				seriesQueries = append(seriesQueries, s)
+			}
+		}
+	}
+	seriesAggregation, _ := checkMap[keySeriesAggregation].(string)
+
+	// Parse formula_queries, which is a map of strings like source_config,
+	// and formula ignoring invalid values since we assume policy has been
+	// validated.
+	formulaQueries := parseSourceConfig(checkMap[keyFormulaQueries])
+	formula, _ := checkMap[keyFormula].(string)
+
+	// Parse transforms ignoring invalid values since we assume policy has
+	// been validated.
+	var transforms []string
+	if list, ok := checkMap[keyTransforms].([]interface{}); ok {
+		for _, t := range list {
+			if s, ok := t.(string); ok {
+				transforms = append(transforms, s)
+			}
+		}
+	}
+
+	// Parse preconditions ignoring invalid values since we assume policy has
+	// been validated.
+	var preconditions []string
+	if list, ok := checkMap[keyPreconditions].([]interface{}); ok {
+		for _, p := range list {
+			if s, ok := p.(string); ok {
+				preconditions = append(preconditions, s)
+			}
+		}
+	}
+
+	// Parse priority ignoring errors since we assume policy has been
+	// validated.
+	var priority int
+	if v, ok := checkMap[keyPriority]; ok {
+		priority, _ = parseInt(v)
+	}
+
 	return &sdk.ScalingPolicyCheck{
-		Query:       query,
-		QueryWindow: queryWindow,
-		Source:      source,
-		Strategy:    strategy,
+		Query:                query,
+		QueryWindow:          queryWindow,
+		QueryTimeout:         queryTimeout,
+		MaxMetricAge:         maxMetricAge,
+		QueryParams:          parseQueryParams(checkMap[keyQueryParams]),
+		SourceConfig:         parseSourceConfig(checkMap[keySourceConfig]),
+		Source:               source,
+		Strategy:             strategy,
+		Chain:                chain,
+		Disabled:             ok && !enabled,
+		MetricWindow:         metricWindow,
+		MetricAggregation:    metricAggregation,
+		MetricSmoothing:      metricSmoothing,
+		MetricSmoothingAlpha: smoothingAlpha,
+		SeriesQueries:        seriesQueries,
+		SeriesAggregation:    seriesAggregation,
+		FormulaQueries:       formulaQueries,
+		Formula:              formula,
+		Transforms:           transforms,
+		Preconditions:        preconditions,
+		PerInstance:          perInstance,
+		Priority:             priority,
+	}
+}
+
+// parseQueryParams parses the content of the query_params block from a
+// policy check.
+//
+// It provides best-effort parsing and will return `nil` in case of errors.
+//
+//  scaling {
+//    policy {
+//      check "name" {
+//      +----------------------+
+//      | query_params {       |
+//      |   window = "1m"      |
+//      |   rollup = "avg"     |
+//      |   aggregator = "sum" |
+//      | }                    |
+//      +----------------------+
+//      }
+//    }
+//  }
+func parseQueryParams(qp interface{}) *sdk.QueryParams {
+	if qp == nil {
+		return nil
+	}
+
+	paramsMap := parseBlock(qp)
+	if paramsMap == nil {
+		return nil
+	}
+
+	// Parse window ignoring errors since we assume policy has been validated.
+	var window time.Duration
+	if windowStr, ok := paramsMap[keyWindow].(string); ok {
+		window, _ = time.ParseDuration(windowStr)
+	}
+
+	rollup, _ := paramsMap[keyRollup].(string)
+	aggregator, _ := paramsMap[keyAggregator].(string)
+
+	return &sdk.QueryParams{
+		Window:     window,
+		Rollup:     rollup,
+		Aggregator: aggregator,
 	}
 }
 
+// parseSourceConfig parses the source_config block of a policy check.
+//
+//  scaling {
+//    policy {
+//      check "name" {
+//      +------------------------------------------+
+//      | source_config = {                        |
+//      |   address = "http://prometheus:9090"     |
+//      |   "header.Authorization" = "Bearer ..."  |
+//      | }                                        |
+//      +------------------------------------------+
+//      }
+//    }
+//  }
+//
+// It provides best-effort parsing and will skip values with errors.
+func parseSourceConfig(sc interface{}) map[string]string {
+	if sc == nil {
+		return nil
+	}
+
+	// source_config is an attribute, so is usually a map, but may be a list
+	// of maps depending on how the job was parsed.
+	configMap, ok := sc.(map[string]interface{})
+	if !ok {
+		configMap = parseBlock(sc)
+	}
+	if configMap == nil {
+		return nil
+	}
+
+	config := make(map[string]string, len(configMap))
+	for k, v := range configMap {
+		if s, ok := v.(string); ok {
+			config[k] = s
+		}
+	}
+	return config
+}
+
+// parseTags parses the tags block of a policy.
+//
+//  scaling {
+//    policy {
+//    +------------------+
+//    | tags {           |
+//    |   team = "infra" |
+//    | }                |
+//    +------------------+
+//    }
+//  }
+//
+// It provides best-effort parsing and will skip tags with errors.
+func parseTags(t interface{}) map[string]string {
+	if t == nil {
+		return nil
+	}
+
+	tagsMap := parseBlock(t)
+	if tagsMap == nil {
+		return nil
+	}
+
+	tags := make(map[string]string, len(tagsMap))
+	for k, v := range tagsMap {
+		if s, ok := v.(string); ok {
+			tags[k] = s
+		}
+	}
+	return tags
+}
+
+// parseLimitsSchedule parses the limits_schedule block of a policy.
+//
+//  scaling {
+//    policy {
+//    +------------------------------+
+//    | limits_schedule {            |
+//    |   timezone = "Europe/Berlin" |
+//    |   window "name" {            |
+//    |     days  = "mon-fri"        |
+//    |     start = "08:00"          |
+//    |     end   = "18:00"          |
+//    |     min   = 10               |
+//    |     max   = 20               |
+//    |   }                          |
+//    | }                            |
+//    +------------------------------+
+//    }
+//  }
+//
+// It provides best-effort parsing and will skip windows with errors. Windows
+// are returned sorted by name.
+func parseLimitsSchedule(s interface{}) *sdk.ScalingPolicyLimitsSchedule {
+	if s == nil {
+		return nil
+	}
+
+	scheduleMap := parseBlock(s)
+	if scheduleMap == nil {
+		return nil
+	}
+
+	schedule := &sdk.ScalingPolicyLimitsSchedule{}
+	schedule.Timezone, _ = scheduleMap[keyTimezone].(string)
+
+	for name, w := range parseBlocks(scheduleMap[keyWindow]) {
+		windowMap := parseBlock(w)
+		if windowMap == nil {
+			continue
+		}
+
+		window := &sdk.ScalingPolicyLimitsWindow{Name: name}
+		window.Days, _ = windowMap[keyDays].(string)
+		window.Start, _ = windowMap[keyStart].(string)
+		window.End, _ = windowMap[keyEnd].(string)
+		if min, err := parseInt(windowMap[keyMin]); err == nil {
+			window.Min = ptr.Int64ToPtr(int64(min))
+		}
+		if max, err := parseInt(windowMap[keyMax]); err == nil {
+			window.Max = ptr.Int64ToPtr(int64(max))
+		}
+		schedule.Windows = append(schedule.Windows, window)
+	}
+
+	sort.Slice(schedule.Windows, func(i, j int) bool {
+		return schedule.Windows[i].Name < schedule.Windows[j].Name
+	})
+	return schedule
+}
+
+// parseStrategies parses the strategy blocks of a policy check, keeping the
+// order in which they are defined.
+//
+// It provides best-effort parsing and will skip blocks with errors.
+func parseStrategies(blocks interface{}) []*sdk.ScalingPolicyStrategy {
+	blocksList, ok := blocks.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var strategies []*sdk.ScalingPolicyStrategy
+	for _, blockInterface := range blocksList {
+		blockMap, ok := blockInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Each block is expected to be its own list entry, but sort the
+		// labels in case there are several so the order is stable.
+		names := make([]string, 0, len(blockMap))
+		for name := range blockMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			strategy := parseStrategy(blockMap[name])
+			if strategy == nil {
+				continue
+			}
+			strategy.Name = name
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	return strategies
+}
+
 // parseStrategy parses the content of the strategy block from a policy.
 //
 // It provides best-effort parsing and will return `nil` in case of errors.
@@ -177,7 +651,7 @@ func parseStrategy(s interface{}) *sdk.ScalingPolicyStrategy {
 
 	configMapString := make(map[string]string)
 	for k, v := range strategyMap {
-		configMapString[k] = fmt.Sprintf("%v", v)
+		configMapString[k] = formatConfigValue(v)
 	}
 
 	return &sdk.ScalingPolicyStrategy{
@@ -213,7 +687,7 @@ func parseTarget(targetBlock interface{}, targetAttr map[string]string) *sdk.Sca
 	}
 
 	for k, v := range targetMap {
-		configMapString[k] = fmt.Sprintf("%v", v)
+		configMapString[k] = formatConfigValue(v)
 	}
 
 	return &sdk.ScalingPolicyTarget{
@@ -221,20 +695,91 @@ func parseTarget(targetBlock interface{}, targetAttr map[string]string) *sdk.Sca
 	}
 }
 
+// formatConfigValue formats a strategy or target config value as a string.
+// Numbers decoded from the Nomad API JSON responses are float64, which %v
+// formats using scientific notation for large values, such as 1e+06, so
+// numbers are formatted in full and whole numbers without a decimal point so
+// they can be parsed as integers.
+func formatConfigValue(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+			return strconv.FormatInt(int64(n), 10)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(n)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// parseInt parses a numeric policy value into an int. Numbers decoded from
+// the Nomad API JSON responses are float64, so only whole values are valid.
+func parseInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if n != float64(int(n)) {
+			return 0, fmt.Errorf("%v is not a whole number", n)
+		}
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("must be a number, found %T", v)
+	}
+}
+
+// parseFloat parses a number, which may be decoded from JSON as any numeric
+// type, as a float64.
+func parseFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf("must be a number, found %T", v)
+	}
+}
+
 // parseBlock parses the specific structure of a block into a more usable
 // value of map[string]interface{}.
+//
+// A block should only be defined once, which is enforced by validation. If
+// the block is defined multiple times, the entries are merged in order so no
+// configuration is silently dropped, with later entries taking precedence.
 func parseBlock(block interface{}) map[string]interface{} {
 	blockInterfaceList, ok := block.([]interface{})
-	if !ok || len(blockInterfaceList) != 1 {
+	if !ok || len(blockInterfaceList) == 0 {
 		return nil
 	}
 
-	blockMap, ok := blockInterfaceList[0].(map[string]interface{})
-	if !ok {
-		return nil
+	if len(blockInterfaceList) == 1 {
+		blockMap, _ := blockInterfaceList[0].(map[string]interface{})
+		return blockMap
+	}
+
+	var merged map[string]interface{}
+	for _, entry := range blockInterfaceList {
+		blockMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if merged == nil {
+			merged = make(map[string]interface{})
+		}
+		for k, v := range blockMap {
+			merged[k] = v
+		}
 	}
 
-	return blockMap
+	return merged
 }
 
 // parseBlocks flattens a list of block into a map, with the labels as keys.
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
+	"github.com/hashicorp/nomad/api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,13 +22,49 @@ func Test_parsePolicy(t *testing.T) {
 			name:  "full scaling",
 			input: "full-scaling",
 			expected: sdk.ScalingPolicy{
-				ID:                 "id",
-				Min:                2,
-				Max:                10,
-				Enabled:            false,
-				EvaluationInterval: 5 * time.Second,
-				Cooldown:           5 * time.Minute,
-				Type:               "horizontal",
+				ID:                         "id",
+				Min:                        2,
+				Max:                        10,
+				Enabled:                    false,
+				EvaluationInterval:         5 * time.Second,
+				Cooldown:                   5 * time.Minute,
+				CooldownBypassFactor:       2.5,
+				ZeroCooldown:               30 * time.Minute,
+				CooldownPerUnit:            30 * time.Second,
+				MaxCooldown:                20 * time.Minute,
+				CooldownOnCompletion:       true,
+				SupersedeInFlight:          true,
+				ScaleInStabilizationWindow: 10 * time.Minute,
+				StartupGracePeriod:         2 * time.Minute,
+				DedupWindow:                15 * time.Minute,
+				Priority:                   80,
+				SoftMax:                    8,
+				OnMetricError:              "scale_to_safe",
+				SafeCount:                  6,
+				Quorum:                     1,
+				ScaleInBias:                1,
+				ScaleInBiasBand:            0.1,
+				HysteresisFactor:           1.5,
+				MinChangeCount:             2,
+				MinChangePercentage:        5,
+				RampIntervals:              3,
+				RespectManualOverride:      true,
+				DryRunSample:               0.5,
+				AllowSharedTarget:          true,
+				Type:                       "horizontal",
+				Tags:                       map[string]string{"team": "infra"},
+				LimitsSchedule: &sdk.ScalingPolicyLimitsSchedule{
+					Timezone: "America/New_York",
+					Windows: []*sdk.ScalingPolicyLimitsWindow{
+						{
+							Name:  "business-hours",
+							Days:  "mon-fri",
+							Start: "09:00",
+							End:   "17:00",
+							Min:   ptr.Int64ToPtr(4),
+						},
+					},
+				},
 				Target: &sdk.ScalingPolicyTarget{
 					Name: "target",
 					Config: map[string]string{
@@ -40,10 +78,28 @@ func Test_parsePolicy(t *testing.T) {
 				},
 				Checks: []*sdk.ScalingPolicyCheck{
 					{
-						Name:        "check-1",
-						Source:      "source-1",
-						Query:       "query-1",
-						QueryWindow: time.Minute,
+						Name:              "check-1",
+						Source:            "source-1",
+						Query:             "query-1",
+						QueryWindow:       time.Minute,
+						QueryTimeout:      30 * time.Second,
+						MaxMetricAge:      5 * time.Minute,
+						QueryParams: &sdk.QueryParams{
+							Window:     time.Minute,
+							Rollup:     "max",
+							Aggregator: "sum",
+						},
+						SourceConfig: map[string]string{
+							"address":              "http://prometheus-2:9090",
+							"header.Authorization": "Bearer token",
+						},
+						MetricWindow:      3,
+						MetricAggregation: sdk.MetricAggregationP95,
+						SeriesQueries:     []string{"query-1-a", "query-1-b"},
+						SeriesAggregation: sdk.MetricAggregationSum,
+						Transforms:        []string{"clamp(0,100)", "scale(1.5)"},
+						Preconditions:     []string{"healthy", "meta.deployment != running"},
+						Priority:          10,
 						Strategy: &sdk.ScalingPolicyStrategy{
 							Name: "strategy-1",
 							Config: map[string]string{
@@ -54,9 +110,11 @@ func Test_parsePolicy(t *testing.T) {
 						},
 					},
 					{
-						Name:   "check-2",
-						Source: "source-2",
-						Query:  "query-2",
+						Name:        "check-2",
+						Source:      "source-2",
+						Query:       "query-2",
+						Disabled:    true,
+						PerInstance: true,
 						Strategy: &sdk.ScalingPolicyStrategy{
 							Name: "strategy-2",
 							Config: map[string]string{
@@ -66,6 +124,25 @@ func Test_parsePolicy(t *testing.T) {
 							},
 						},
 					},
+					{
+						Name:                 "check-3",
+						Source:               "source-3",
+						Query:                "query-3",
+						MetricSmoothing:      sdk.MetricSmoothingEWMA,
+						MetricSmoothingAlpha: 0.3,
+						FormulaQueries: map[string]string{
+							"cpu": "cpu-query-3",
+						},
+						Formula: "(query / 100) + (cpu / 0.7)",
+						Strategy: &sdk.ScalingPolicyStrategy{
+							Name: "strategy-3",
+							Config: map[string]string{
+								"int_config":  "2",
+								"bool_config": "true",
+								"str_config":  "str",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -79,7 +156,7 @@ func Test_parsePolicy(t *testing.T) {
 				Enabled: true,
 				Type:    "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "minimum-valid-scaling",
@@ -115,7 +192,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "empty-policy",
@@ -132,7 +209,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "invalid-evaluation-interval",
@@ -149,7 +226,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "invalid-cooldown",
@@ -192,7 +269,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "empty-check",
@@ -212,7 +289,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "single-check",
@@ -236,6 +313,41 @@ func Test_parsePolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "strategy chain",
+			input: "strategy-chain",
+			expected: sdk.ScalingPolicy{
+				ID:      "id",
+				Min:     1,
+				Max:     10,
+				Enabled: true,
+				Type:    "horizontal",
+				Target: &sdk.ScalingPolicyTarget{
+					Name: "nomad-target",
+					Config: map[string]string{
+						"Namespace": "default",
+						"Job":       "strategy-chain",
+						"Group":     "test",
+					},
+				},
+				Checks: []*sdk.ScalingPolicyCheck{
+					{
+						Name:  "check",
+						Query: "query",
+						Strategy: &sdk.ScalingPolicyStrategy{
+							Name:   "strategy-1",
+							Config: map[string]string{},
+						},
+						Chain: []*sdk.ScalingPolicyStrategy{
+							{
+								Name:   "strategy-2",
+								Config: map[string]string{"max_change": "1"},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:  "invalid check",
 			input: "invalid-check",
@@ -244,7 +356,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "invalid-check",
@@ -261,7 +373,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "missing-strategy",
@@ -285,7 +397,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "empty-strategy",
@@ -311,7 +423,7 @@ func Test_parsePolicy(t *testing.T) {
 				Max:  10,
 				Type: "horizontal",
 				Target: &sdk.ScalingPolicyTarget{
-					Name: "",
+					Name: "nomad-target",
 					Config: map[string]string{
 						"Namespace": "default",
 						"Job":       "invalid-strategy",
@@ -351,6 +463,59 @@ func Test_parsePolicy(t *testing.T) {
 	}
 }
 
+func Test_parsePolicy_targetAttribute(t *testing.T) {
+	testCases := []struct {
+		name         string
+		inputType    string
+		expectedName string
+	}{
+		{
+			name:         "horizontal policy",
+			inputType:    sdk.ScalingPolicyTypeHorizontal,
+			expectedName: "nomad-target",
+		},
+		{
+			name:         "policy without type",
+			inputType:    "",
+			expectedName: "nomad-target",
+		},
+		{
+			name:         "cluster policy",
+			inputType:    sdk.ScalingPolicyTypeCluster,
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The policy uses the Target attribute, set by Nomad to the task
+			// group of the policy, without a target block.
+			actual := parsePolicy(&api.ScalingPolicy{
+				ID:   "id",
+				Type: tc.inputType,
+				Max:  ptr.Int64ToPtr(10),
+				Target: map[string]string{
+					"Namespace": "default",
+					"Job":       "example",
+					"Group":     "cache",
+				},
+				Policy: map[string]interface{}{
+					keyCooldown: "5m",
+				},
+			})
+
+			assert.Equal(t, &sdk.ScalingPolicyTarget{
+				Name: tc.expectedName,
+				Config: map[string]string{
+					"Namespace": "default",
+					"Job":       "example",
+					"Group":     "cache",
+				},
+			}, actual.Target)
+		})
+	}
+}
+
 func Test_parseBlock(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -383,6 +548,41 @@ func Test_parseBlock(t *testing.T) {
 				map[string]interface{}{},
 				map[string]interface{}{},
 			},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "more than one element are merged",
+			input: []interface{}{
+				map[string]interface{}{
+					"a": "1",
+					"b": "1",
+				},
+				map[string]interface{}{
+					"b": "2",
+					"c": "2",
+				},
+			},
+			expected: map[string]interface{}{
+				"a": "1",
+				"b": "2",
+				"c": "2",
+			},
+		},
+		{
+			name: "more than one element with invalid type",
+			input: []interface{}{
+				1,
+				map[string]interface{}{
+					"a": "1",
+				},
+			},
+			expected: map[string]interface{}{
+				"a": "1",
+			},
+		},
+		{
+			name:     "more than one element all invalid",
+			input:    []interface{}{1, 2},
 			expected: nil,
 		},
 		{
@@ -399,3 +599,53 @@ func Test_parseBlock(t *testing.T) {
 		})
 	}
 }
+
+func Test_formatConfigValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{
+			name:     "string",
+			input:    "avg",
+			expected: "avg",
+		},
+		{
+			name:     "whole number",
+			input:    float64(5),
+			expected: "5",
+		},
+		{
+			name:     "large whole number",
+			input:    float64(1000000),
+			expected: "1000000",
+		},
+		{
+			name:     "negative whole number",
+			input:    float64(-20),
+			expected: "-20",
+		},
+		{
+			name:     "fraction",
+			input:    0.25,
+			expected: "0.25",
+		},
+		{
+			name:     "large fraction",
+			input:    12345678.5,
+			expected: "12345678.5",
+		},
+		{
+			name:     "bool",
+			input:    true,
+			expected: "true",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatConfigValue(tc.input))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package nomad
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -11,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/policy"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/blocking"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
 	"github.com/hashicorp/nomad/api"
 )
 
@@ -20,15 +23,94 @@ const (
 	keySource             = "source"
 	keyQuery              = "query"
 	keyQueryWindow        = "query_window"
+	keyQueryTimeout       = "query_timeout"
+	keyMaxMetricAge       = "max_metric_age"
 	keyEvaluationInterval = "evaluation_interval"
 	keyTarget             = "target"
 	keyChecks             = "check"
 	keyStrategy           = "strategy"
 	keyCooldown           = "cooldown"
+	keyCooldownBypass     = "cooldown_bypass_factor"
+	keyZeroCooldown       = "zero_cooldown"
+	keyCooldownPerUnit    = "cooldown_per_unit"
+	keyMaxCooldown        = "max_cooldown"
+	keyCooldownCompletion = "cooldown_on_completion"
+	keySupersedeInFlight  = "supersede_in_flight"
+	keyScaleInWindow      = "scale_in_stabilization_window"
+	keyStartupGrace       = "startup_grace_period"
+	keyDedupWindow        = "dedup_window"
+	keyPriority           = "priority"
+	keySoftMax            = "soft_max"
+	keyOnMetricError      = "on_metric_error"
+	keySafeCount          = "safe_count"
+	keyQuorum             = "quorum"
+	keyScaleInBias        = "scale_in_bias"
+	keyScaleInBiasBand    = "scale_in_bias_band"
+	keyHysteresisFactor   = "hysteresis_factor"
+	keyMinChangeCount     = "min_change_count"
+	keyMinChangePercent   = "min_change_percentage"
+	keyRampIntervals      = "ramp_intervals"
+	keyManualOverride     = "respect_manual_override"
+	keyDryRunSample       = "dry_run_sample"
+	keyLimitsOnly         = "limits_only"
+	keySharedTarget       = "allow_shared_target"
+	keyEnabled            = "enabled"
+	keyTemplate           = "template"
+	keyMetricWindow       = "metric_window"
+	keyMetricAggregation  = "metric_aggregation"
+	keyMetricSmoothing    = "metric_smoothing"
+	keySmoothingAlpha     = "metric_smoothing_alpha"
+	keySeriesQueries      = "series_queries"
+	keySeriesAggregation  = "series_aggregation"
+	keyFormulaQueries     = "formula_queries"
+	keyFormula            = "formula"
+	keyPerInstance        = "per_instance"
+	keyTransforms         = "transforms"
+	keyPreconditions      = "preconditions"
+	keyQueryParams        = "query_params"
+	keyWindow             = "window"
+	keyRollup             = "rollup"
+	keyAggregator         = "aggregator"
+	keySourceConfig       = "source_config"
+	keyTags               = "tags"
+	keyLimitsSchedule     = "limits_schedule"
+	keyTimezone           = "timezone"
+	keyDays               = "days"
+	keyStart              = "start"
+	keyEnd                = "end"
+	keyMin                = "min"
+	keyMax                = "max"
 )
 
-// Ensure NomadSource satisfies the Source interface.
-var _ policy.Source = (*Source)(nil)
+const (
+	// backoffBase and backoffLimit control the wait period between failed
+	// calls to the Nomad API.
+	backoffBase  = 1 * time.Second
+	backoffLimit = 1 * time.Minute
+
+	// notFoundWait is the wait period after the Nomad API responds that the
+	// requested object was not found, which resolves once it is registered.
+	notFoundWait = 10 * time.Second
+
+	// unreachableThreshold is the number of consecutive failed calls to the
+	// Nomad API after which Nomad is considered unreachable.
+	unreachableThreshold = 5
+
+	// blockingQueryWaitTime is the maximum time the blocking queries to the
+	// Nomad API wait for changes before returning.
+	blockingQueryWaitTime = 5 * time.Minute
+
+	// livenessWindow is the time within which the source is expected to send
+	// a listing of policy IDs. It allows for the blocking query wait time,
+	// the jitter Nomad adds to it, and a failed call being retried.
+	livenessWindow = 3 * blockingQueryWaitTime
+)
+
+// Ensure NomadSource satisfies the Source and LivenessSource interfaces.
+var (
+	_ policy.Source         = (*Source)(nil)
+	_ policy.LivenessSource = (*Source)(nil)
+)
 
 // Source is an implementation of the Source interface that retrieves
 // policies from a Nomad cluster.
@@ -36,6 +118,14 @@ type Source struct {
 	log             hclog.Logger
 	nomad           *api.Client
 	policyProcessor *policy.Processor
+
+	// region is the additional Nomad region the source watches. It is empty
+	// for the source which watches the region of the agent.
+	region string
+
+	// apiFailures is the number of consecutive failed calls to the Nomad API
+	// and should be accessed atomically.
+	apiFailures int32
 }
 
 // NewNomadSource returns a new Nomad policy source.
@@ -47,8 +137,24 @@ func NewNomadSource(log hclog.Logger, nomad *api.Client, policyProcessor *policy
 	}
 }
 
+// NewNomadRegionSource returns a new Nomad policy source which watches the
+// policies of an additional Nomad region using a client for that region. The
+// region is added to the target config of the policies which scale Nomad task
+// groups, so they are scaled within the correct region.
+func NewNomadRegionSource(log hclog.Logger, nomad *api.Client, policyProcessor *policy.Processor, region string) *Source {
+	return &Source{
+		log:             log.ResetNamed("nomad_policy_source").With("region", region),
+		nomad:           nomad,
+		policyProcessor: policyProcessor,
+		region:          region,
+	}
+}
+
 // Name satisfies the Name function of the policy.Source interface.
 func (s *Source) Name() policy.SourceName {
+	if s.region != "" {
+		return policy.NomadRegionSourceName(s.region)
+	}
 	return policy.SourceNameNomad
 }
 
@@ -60,6 +166,53 @@ func (s *Source) Name() policy.SourceName {
 // level.
 func (s *Source) ReloadIDsMonitor() {}
 
+// LivenessWindow satisfies the LivenessWindow function of the
+// policy.LivenessSource interface.
+func (s *Source) LivenessWindow() time.Duration {
+	return livenessWindow
+}
+
+// Reachable returns false when the Nomad API has persistently failed to
+// respond to the source's requests.
+func (s *Source) Reachable() bool {
+	return atomic.LoadInt32(&s.apiFailures) < unreachableThreshold
+}
+
+// handleAPIError records a failed Nomad API call and returns the duration
+// the caller should wait before trying again. Permanent errors, such as
+// permission denied, will not resolve quickly so the maximum wait is used,
+// except for not found errors which resolve once the object is registered.
+// They do however mean Nomad responded, so are not counted as failures.
+func (s *Source) handleAPIError(err error, b *nomadHelper.Backoff) time.Duration {
+	if !nomadHelper.IsTransientError(err) {
+		atomic.StoreInt32(&s.apiFailures, 0)
+		if nomadHelper.ResponseCode(err) == http.StatusNotFound {
+			return notFoundWait
+		}
+		return b.Limit()
+	}
+
+	atomic.AddInt32(&s.apiFailures, 1)
+	return b.Next()
+}
+
+// handleAPISuccess records a successful Nomad API call.
+func (s *Source) handleAPISuccess(b *nomadHelper.Backoff) {
+	atomic.StoreInt32(&s.apiFailures, 0)
+	b.Reset()
+}
+
+// wait blocks for the duration d or until the context is closed.
+func wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
 // MonitorIDs retrieves a list of policy IDs from a Nomad cluster and sends it
 // in the resultCh channel when change is detected. Errors are sent through the
 // errCh channel.
@@ -68,7 +221,13 @@ func (s *Source) ReloadIDsMonitor() {}
 func (s *Source) MonitorIDs(ctx context.Context, req policy.MonitorIDsReq) {
 	s.log.Debug("starting policy blocking query watcher")
 
-	q := &api.QueryOptions{WaitTime: 5 * time.Minute, WaitIndex: 1}
+	q := (&api.QueryOptions{WaitTime: blockingQueryWaitTime, WaitIndex: 1}).WithContext(ctx)
+	backoff := nomadHelper.NewBackoff(backoffBase, backoffLimit)
+
+	// policyIDs is the most recent listing sent, and sent is whether a
+	// listing has been sent at all.
+	var policyIDs []policy.PolicyID
+	var sent bool
 
 	for {
 		select {
@@ -78,9 +237,7 @@ func (s *Source) MonitorIDs(ctx context.Context, req policy.MonitorIDsReq) {
 		default:
 			// Perform a blocking query on the Nomad API that returns a stub list
 			// of scaling policies. If we get an errors at this point, we should
-			// sleep and try again.
-			//
-			// TODO(jrasell) in the future maybe use a better method than sleep.
+			// backoff and try again.
 			policies, meta, err := s.nomad.Scaling().ListPolicies(q)
 
 			// Return immediately if context is closed.
@@ -91,17 +248,22 @@ func (s *Source) MonitorIDs(ctx context.Context, req policy.MonitorIDsReq) {
 
 			if err != nil {
 				policy.HandleSourceError(s.Name(), fmt.Errorf("failed to call the Nomad list policies API: %v", err), req.ErrCh)
-				time.Sleep(10 * time.Second)
+				wait(ctx, s.handleAPIError(err, backoff))
 				continue
 			}
+			s.handleAPISuccess(backoff)
 
 			// If the index has not changed, the query returned because the timeout
-			// was reached, therefore start the next query loop.
+			// was reached, therefore start the next query loop. The previous
+			// listing is sent again so the manager knows the watch is working.
 			if !blocking.IndexHasChanged(meta.LastIndex, q.WaitIndex) {
+				if sent {
+					req.ResultCh <- policy.IDMessage{IDs: policyIDs, Source: s.Name()}
+				}
 				continue
 			}
 
-			var policyIDs []policy.PolicyID
+			policyIDs = nil
 
 			// Iterate over all policies in the list and filter out policies
 			// that are not enabled.
@@ -120,6 +282,7 @@ func (s *Source) MonitorIDs(ctx context.Context, req policy.MonitorIDsReq) {
 
 			// Send new policy IDs in the channel.
 			req.ResultCh <- policy.IDMessage{IDs: policyIDs, Source: s.Name()}
+			sent = true
 		}
 	}
 }
@@ -137,7 +300,9 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 
 	log.Trace("starting policy blocking query watcher")
 
-	q := &api.QueryOptions{WaitTime: 5 * time.Minute, WaitIndex: 1}
+	q := (&api.QueryOptions{WaitTime: blockingQueryWaitTime, WaitIndex: 1}).WithContext(ctx)
+	backoff := nomadHelper.NewBackoff(backoffBase, backoffLimit)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -146,9 +311,7 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 		default:
 			// Perform a blocking query on the Nomad API that returns a stub list
 			// of scaling policies. If we get an errors at this point, we should
-			// sleep and try again.
-			//
-			// TODO(jrasell) in the future maybe use a better method than sleep.
+			// backoff and try again.
 			p, meta, err := s.nomad.Scaling().GetPolicy(string(req.ID), q)
 
 			// Return immediately if context is closed.
@@ -159,9 +322,10 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 
 			if err != nil {
 				policy.HandleSourceError(s.Name(), fmt.Errorf("failed to get policy: %v", err), req.ErrCh)
-				time.Sleep(10 * time.Second)
+				wait(ctx, s.handleAPIError(err, backoff))
 				continue
 			}
+			s.handleAPISuccess(backoff)
 
 			// If the index has not changed, the query returned because the timeout
 			// was reached, therefore start the next query loop.
@@ -188,13 +352,82 @@ func (s *Source) MonitorPolicy(ctx context.Context, req policy.MonitorPolicyReq)
 			}
 
 			autoPolicy := parsePolicy(p)
+			if err := s.applyPolicyTemplate(&autoPolicy); err != nil {
+				policy.HandleSourceError(s.Name(), err, req.ErrCh)
+				continue
+			}
 			s.canonicalizePolicy(&autoPolicy)
 
+			// The target config and check queries can only be validated once
+			// the policy is canonicalized, as this sets the target name and
+			// the check sources.
+			if err := s.validateCanonicalPolicy(&autoPolicy); err != nil {
+				policy.HandleSourceError(s.Name(), fmt.Errorf("policy validation failed: %v", err), req.ErrCh)
+				continue
+			}
+
 			req.ResultCh <- autoPolicy
 		}
 	}
 }
 
+// ReadPolicy satisfies the ReadPolicy function of the policy.Source
+// interface.
+func (s *Source) ReadPolicy(ctx context.Context, ID policy.PolicyID) (*sdk.ScalingPolicy, error) {
+	p, _, err := s.nomad.Scaling().GetPolicy(string(ID), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy: %v", err)
+	}
+
+	if err := validateScalingPolicy(p); err != nil {
+		return nil, fmt.Errorf("policy validation failed: %v", err)
+	}
+
+	autoPolicy := parsePolicy(p)
+	if err := s.applyPolicyTemplate(&autoPolicy); err != nil {
+		return nil, err
+	}
+	s.canonicalizePolicy(&autoPolicy)
+
+	if err := s.validateCanonicalPolicy(&autoPolicy); err != nil {
+		return nil, fmt.Errorf("policy validation failed: %v", err)
+	}
+
+	return &autoPolicy, nil
+}
+
+// applyPolicyTemplate merges the template referenced by the policy, if any,
+// into the policy and validates the result. The policy document is validated
+// before the template is applied, so only templated policies are validated
+// again.
+func (s *Source) applyPolicyTemplate(p *sdk.ScalingPolicy) error {
+	if p.Template == "" {
+		return nil
+	}
+
+	if err := s.policyProcessor.ApplyPolicyTemplate(p); err != nil {
+		return fmt.Errorf("failed to apply policy template: %v", err)
+	}
+	if err := s.policyProcessor.ValidatePolicy(p); err != nil {
+		return fmt.Errorf("policy validation failed: %v", err)
+	}
+	return nil
+}
+
+// validateCanonicalPolicy validates the parts of the policy which depend on
+// values set when the policy is canonicalized.
+func (s *Source) validateCanonicalPolicy(p *sdk.ScalingPolicy) error {
+	var mErr *multierror.Error
+
+	if err := s.policyProcessor.ValidateTargetConfig(p); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	if err := s.policyProcessor.ValidateCheckQueries(p); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
 // canonicalizePolicy sets standarized values for missing fields.
 func (s *Source) canonicalizePolicy(p *sdk.ScalingPolicy) {
 	if p == nil {
@@ -242,6 +475,12 @@ func (s *Source) canonicalizeHorizontalPolicy(p *sdk.ScalingPolicy) {
 	if p.Target.Name == "" {
 		p.Target.Name = plugins.InternalTargetNomad
 	}
+
+	// Route the scaling of policies from an additional region to the
+	// region, unless the operator has set one.
+	if s.region != "" && p.Target.Name == plugins.InternalTargetNomad && p.Target.Config["Region"] == "" {
+		p.Target.Config["Region"] = s.region
+	}
 }
 
 func (s *Source) canonicalizeCheck(c *sdk.ScalingPolicyCheck, t *sdk.ScalingPolicyTarget) {
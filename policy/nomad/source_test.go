@@ -1,12 +1,14 @@
 package nomad
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/nomad-autoscaler/plugins"
 	"github.com/hashicorp/nomad-autoscaler/policy"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
 	"github.com/hashicorp/nomad/api"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,6 +25,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			input: &sdk.ScalingPolicy{
 				ID:                 "string",
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           80,
 				Min:                1,
 				Max:                5,
 				Enabled:            true,
@@ -55,6 +58,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			expected: &sdk.ScalingPolicy{
 				ID:                 "string",
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           80,
 				Min:                1,
 				Max:                5,
 				Enabled:            true,
@@ -90,6 +94,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			input: &sdk.ScalingPolicy{},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name:   plugins.InternalTargetNomad,
@@ -119,6 +124,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name: plugins.InternalTargetNomad,
@@ -157,6 +163,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name: plugins.InternalTargetNomad,
@@ -194,6 +201,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name: plugins.InternalTargetNomad,
@@ -232,6 +240,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name: plugins.InternalTargetNomad,
@@ -269,6 +278,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name: plugins.InternalTargetNomad,
@@ -294,6 +304,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			input: &sdk.ScalingPolicy{},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 5 * time.Second,
 				Target: &sdk.ScalingPolicyTarget{
 					Name:   plugins.InternalTargetNomad,
@@ -309,6 +320,7 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 			input: &sdk.ScalingPolicy{},
 			expected: &sdk.ScalingPolicy{
 				Type:               sdk.ScalingPolicyTypeHorizontal,
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				EvaluationInterval: 10 * time.Second,
 				Cooldown:           1 * time.Hour,
 				Target: &sdk.ScalingPolicyTarget{
@@ -330,3 +342,77 @@ func TestSource_canonicalizePolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestSource_canonicalizePolicy_region(t *testing.T) {
+	s := TestNomadSource(t, nil)
+	s.region = "eu"
+	assert.Equal(t, policy.SourceName("nomad-eu"), s.Name())
+
+	p := &sdk.ScalingPolicy{Type: sdk.ScalingPolicyTypeHorizontal}
+	s.canonicalizePolicy(p)
+	assert.Equal(t, "eu", p.Target.Config["Region"])
+
+	// A region set by the operator is kept.
+	p = &sdk.ScalingPolicy{
+		Type:   sdk.ScalingPolicyTypeHorizontal,
+		Target: &sdk.ScalingPolicyTarget{Config: map[string]string{"Region": "us"}},
+	}
+	s.canonicalizePolicy(p)
+	assert.Equal(t, "us", p.Target.Config["Region"])
+
+	// Only policies using the Nomad target are routed.
+	p = &sdk.ScalingPolicy{
+		Type:   sdk.ScalingPolicyTypeHorizontal,
+		Target: &sdk.ScalingPolicyTarget{Name: "other-target"},
+	}
+	s.canonicalizePolicy(p)
+	assert.Empty(t, p.Target.Config["Region"])
+}
+
+func TestSource_applyPolicyTemplate(t *testing.T) {
+	s := TestNomadSource(t, func(_ *api.Config, sourceConfig *policy.ConfigDefaults) {
+		sourceConfig.Templates = map[string]*sdk.ScalingPolicy{
+			"web": {
+				Cooldown: 2 * time.Minute,
+				Checks:   []*sdk.ScalingPolicyCheck{{Name: "cpu", Query: "avg_cpu"}},
+			},
+		}
+	})
+
+	p := &sdk.ScalingPolicy{ID: "id", Max: 5, Template: "web"}
+	assert.NoError(t, s.applyPolicyTemplate(p))
+	assert.Equal(t, 2*time.Minute, p.Cooldown)
+	assert.Len(t, p.Checks, 1)
+
+	// The merged policy is validated.
+	p = &sdk.ScalingPolicy{ID: "id", Min: 10, Max: 5, Template: "web"}
+	assert.Error(t, s.applyPolicyTemplate(p))
+
+	p = &sdk.ScalingPolicy{ID: "id", Max: 5, Template: "missing"}
+	assert.Error(t, s.applyPolicyTemplate(p))
+}
+
+func TestSource_Reachable(t *testing.T) {
+	s := TestNomadSource(t, nil)
+	b := nomadHelper.NewBackoff(time.Millisecond, time.Second)
+
+	assert.True(t, s.Reachable())
+
+	// Transient errors are counted towards Nomad being unreachable.
+	for i := 0; i < unreachableThreshold; i++ {
+		s.handleAPIError(errors.New("connection refused"), b)
+	}
+	assert.False(t, s.Reachable())
+
+	// A permanent error means Nomad responded. Not found errors resolve once
+	// the object is registered, so are retried sooner.
+	assert.Equal(t, time.Second, s.handleAPIError(errors.New("Unexpected response code: 403 (Permission denied)"), b))
+	assert.True(t, s.Reachable())
+	assert.Equal(t, notFoundWait, s.handleAPIError(errors.New("Unexpected response code: 404 (not found)"), b))
+
+	for i := 0; i < unreachableThreshold; i++ {
+		s.handleAPIError(errors.New("EOF"), b)
+	}
+	s.handleAPISuccess(b)
+	assert.True(t, s.Reachable())
+}
@@ -9,8 +9,46 @@ job "full-scaling" {
       enabled = false
 
       policy {
-        evaluation_interval = "5s"
-        cooldown            = "5m"
+        evaluation_interval           = "5s"
+        cooldown                      = "5m"
+        cooldown_bypass_factor        = 2.5
+        zero_cooldown                 = "30m"
+        cooldown_per_unit             = "30s"
+        max_cooldown                  = "20m"
+        cooldown_on_completion        = true
+        supersede_in_flight           = true
+        scale_in_stabilization_window = "10m"
+        startup_grace_period          = "2m"
+        dedup_window                  = "15m"
+        priority                      = 80
+        soft_max                      = 8
+        on_metric_error               = "scale_to_safe"
+        safe_count                    = 6
+        quorum                        = 1
+        scale_in_bias                 = 1
+        scale_in_bias_band            = 0.1
+        hysteresis_factor             = 1.5
+        min_change_count              = 2
+        ramp_intervals                = 3
+        respect_manual_override       = true
+        dry_run_sample                = 0.5
+        allow_shared_target           = true
+        min_change_percentage         = 5
+
+        limits_schedule {
+          timezone = "America/New_York"
+
+          window "business-hours" {
+            days  = "mon-fri"
+            start = "09:00"
+            end   = "17:00"
+            min   = 4
+          }
+        }
+
+        tags {
+          team = "infra"
+        }
 
         target "target" {
           int_config  = 2
@@ -19,9 +57,29 @@ job "full-scaling" {
         }
 
         check "check-1" {
-          source       = "source-1"
-          query        = "query-1"
-          query_window = "1m"
+          source             = "source-1"
+          query              = "query-1"
+          query_window       = "1m"
+          query_timeout      = "30s"
+          max_metric_age     = "5m"
+          metric_window      = 3
+          metric_aggregation = "p95"
+          series_queries     = ["query-1-a", "query-1-b"]
+          series_aggregation = "sum"
+          transforms         = ["clamp(0,100)", "scale(1.5)"]
+          preconditions      = ["healthy", "meta.deployment != running"]
+          priority           = 10
+
+          query_params {
+            window     = "1m"
+            rollup     = "max"
+            aggregator = "sum"
+          }
+
+          source_config = {
+            address                = "http://prometheus-2:9090"
+            "header.Authorization" = "Bearer token"
+          }
 
           strategy "strategy-1" {
             int_config  = 2
@@ -31,8 +89,10 @@ job "full-scaling" {
         }
 
         check "check-2" {
-          source = "source-2"
-          query  = "query-2"
+          enabled      = false
+          source       = "source-2"
+          query        = "query-2"
+          per_instance = true
 
           strategy "strategy-2" {
             int_config  = 2
@@ -40,6 +100,24 @@ job "full-scaling" {
             str_config  = "str"
           }
         }
+
+        check "check-3" {
+          source                 = "source-3"
+          query                  = "query-3"
+          metric_smoothing       = "ewma"
+          metric_smoothing_alpha = 0.3
+          formula                = "(query / 100) + (cpu / 0.7)"
+
+          formula_queries = {
+            cpu = "cpu-query-3"
+          }
+
+          strategy "strategy-3" {
+            int_config  = 2
+            bool_config = true
+            str_config  = "str"
+          }
+        }
       }
     }
 
@@ -0,0 +1,24 @@
+job "invalid-allow-shared-target" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "test" {
+    scaling {
+      min     = 0
+      max     = 10
+      enabled = false
+
+      policy {
+        allow_shared_target = "yes"
+      }
+    }
+
+    task "echo" {
+      driver = "raw_exec"
+      config {
+        command = "echo"
+        args    = ["hi"]
+      }
+    }
+  }
+}
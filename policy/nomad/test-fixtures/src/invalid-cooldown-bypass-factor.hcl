@@ -0,0 +1,24 @@
+job "invalid-cooldown-bypass-factor" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "test" {
+    scaling {
+      min     = 0
+      max     = 10
+      enabled = false
+
+      policy {
+        cooldown_bypass_factor = 0.5
+      }
+    }
+
+    task "echo" {
+      driver = "raw_exec"
+      config {
+        command = "echo"
+        args    = ["hi"]
+      }
+    }
+  }
+}
@@ -0,0 +1,24 @@
+job "invalid-cooldown-on-completion" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "test" {
+    scaling {
+      min     = 0
+      max     = 10
+      enabled = false
+
+      policy {
+        cooldown_on_completion = "yes"
+      }
+    }
+
+    task "echo" {
+      driver = "raw_exec"
+      config {
+        command = "echo"
+        args    = ["hi"]
+      }
+    }
+  }
+}
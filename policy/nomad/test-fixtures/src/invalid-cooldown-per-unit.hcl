@@ -0,0 +1,24 @@
+job "invalid-cooldown-per-unit" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "test" {
+    scaling {
+      min     = 0
+      max     = 10
+      enabled = false
+
+      policy {
+        cooldown_per_unit = "invalid"
+      }
+    }
+
+    task "echo" {
+      driver = "raw_exec"
+      config {
+        command = "echo"
+        args    = ["hi"]
+      }
+    }
+  }
+}
@@ -1,4 +1,4 @@
-job "invalid-multiple-strategies" {
+job "strategy-chain" {
   datacenters = ["dc1"]
   type        = "batch"
 
@@ -11,7 +11,10 @@ job "invalid-multiple-strategies" {
           query = "query"
 
           strategy "strategy-1" {}
-          strategy "strategy-2" {}
+
+          strategy "strategy-2" {
+            max_change = 1
+          }
         }
       }
     }
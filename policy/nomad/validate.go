@@ -2,9 +2,12 @@ package nomad
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
 	"github.com/hashicorp/nomad/api"
 )
@@ -85,6 +88,235 @@ func validatePolicy(p map[string]interface{}) error {
 		}
 	}
 
+	// Validate ZeroCooldown, if present.
+	//   1. ZeroCooldown should be a valid duration.
+	if zeroCooldown, ok := p[keyZeroCooldown]; ok {
+		if err := validateDuration(zeroCooldown, path+"."+keyZeroCooldown); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate CooldownPerUnit, if present.
+	//   1. CooldownPerUnit should be a valid duration.
+	if perUnit, ok := p[keyCooldownPerUnit]; ok {
+		if err := validateDuration(perUnit, path+"."+keyCooldownPerUnit); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate MaxCooldown, if present.
+	//   1. MaxCooldown should be a valid duration.
+	if maxCooldown, ok := p[keyMaxCooldown]; ok {
+		if err := validateDuration(maxCooldown, path+"."+keyMaxCooldown); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate CooldownOnCompletion, if present.
+	//   1. CooldownOnCompletion must be a boolean.
+	if completion, ok := p[keyCooldownCompletion]; ok {
+		if _, ok := completion.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keyCooldownCompletion, completion))
+		}
+	}
+
+	// Validate SupersedeInFlight, if present.
+	//   1. SupersedeInFlight must be a boolean.
+	if supersede, ok := p[keySupersedeInFlight]; ok {
+		if _, ok := supersede.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keySupersedeInFlight, supersede))
+		}
+	}
+
+	// Validate ScaleInStabilizationWindow, if present.
+	//   1. ScaleInStabilizationWindow should be a valid duration.
+	if window, ok := p[keyScaleInWindow]; ok {
+		if err := validateDuration(window, path+"."+keyScaleInWindow); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate CooldownBypassFactor, if present.
+	//   1. CooldownBypassFactor should be a number.
+	//   2. CooldownBypassFactor should be greater than 1.
+	if factor, ok := p[keyCooldownBypass]; ok {
+		if err := validateFactor(factor, path+"."+keyCooldownBypass); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate StartupGracePeriod, if present.
+	//   1. StartupGracePeriod should be a valid duration.
+	if grace, ok := p[keyStartupGrace]; ok {
+		if err := validateDuration(grace, path+"."+keyStartupGrace); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate DedupWindow, if present.
+	//   1. DedupWindow should be a valid duration.
+	if window, ok := p[keyDedupWindow]; ok {
+		if err := validateDuration(window, path+"."+keyDedupWindow); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate Priority, if present.
+	//   1. Priority should be a whole number.
+	//   2. Priority should be within the allowed range.
+	if priority, ok := p[keyPriority]; ok {
+		if err := validatePriority(priority, path+"."+keyPriority); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate SoftMax, if present.
+	//   1. SoftMax must be a whole number.
+	//   2. SoftMax must not be negative.
+	if softMax, ok := p[keySoftMax]; ok {
+		if v, err := parseInt(softMax); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keySoftMax, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keySoftMax, v))
+		}
+	}
+
+	// Validate OnMetricError, if present.
+	//   1. OnMetricError must be a string.
+	//   2. OnMetricError must be one of the supported behaviours.
+	if onErr, ok := p[keyOnMetricError]; ok {
+		if s, ok := onErr.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyOnMetricError, onErr))
+		} else if s != sdk.MetricErrorHold && s != sdk.MetricErrorScaleToSafe {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be one of %q or %q, found %q",
+				path, keyOnMetricError, sdk.MetricErrorHold, sdk.MetricErrorScaleToSafe, s))
+		}
+	}
+
+	// Validate SafeCount, if present.
+	//   1. SafeCount must be a whole number.
+	//   2. SafeCount must not be negative.
+	if safeCount, ok := p[keySafeCount]; ok {
+		if v, err := parseInt(safeCount); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keySafeCount, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keySafeCount, v))
+		}
+	}
+
+	// Validate Quorum, if present.
+	//   1. Quorum must be a whole number.
+	//   2. Quorum must not be negative.
+	if quorum, ok := p[keyQuorum]; ok {
+		if v, err := parseInt(quorum); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyQuorum, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keyQuorum, v))
+		}
+	}
+
+	// Validate ScaleInBias, if present.
+	//   1. ScaleInBias must be a whole number.
+	//   2. ScaleInBias must not be negative.
+	if bias, ok := p[keyScaleInBias]; ok {
+		if v, err := parseInt(bias); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyScaleInBias, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keyScaleInBias, v))
+		}
+	}
+
+	// Validate ScaleInBiasBand, if present.
+	//   1. ScaleInBiasBand must be a number.
+	//   2. ScaleInBiasBand must be between 0 and 1.
+	if band, ok := p[keyScaleInBiasBand]; ok {
+		if v, err := parseFloat(band); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyScaleInBiasBand, err))
+		} else if v < 0 || v >= 1 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be between 0 and 1, found %g", path, keyScaleInBiasBand, v))
+		}
+	}
+
+	// Validate HysteresisFactor, if present.
+	//   1. HysteresisFactor should be a number.
+	//   2. HysteresisFactor should be greater than 1.
+	if factor, ok := p[keyHysteresisFactor]; ok {
+		if err := validateFactor(factor, path+"."+keyHysteresisFactor); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate MinChangeCount, if present.
+	//   1. MinChangeCount must be a whole number.
+	//   2. MinChangeCount must not be negative.
+	if minChange, ok := p[keyMinChangeCount]; ok {
+		if v, err := parseInt(minChange); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyMinChangeCount, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keyMinChangeCount, v))
+		}
+	}
+
+	// Validate MinChangePercentage, if present.
+	//   1. MinChangePercentage must be a number.
+	//   2. MinChangePercentage must be between 0 and 100.
+	if minChange, ok := p[keyMinChangePercent]; ok {
+		if v, err := parseFloat(minChange); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyMinChangePercent, err))
+		} else if v < 0 || v > 100 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be between 0 and 100, found %g", path, keyMinChangePercent, v))
+		}
+	}
+
+	// Validate RampIntervals, if present.
+	//   1. RampIntervals must be a whole number.
+	//   2. RampIntervals must not be negative.
+	if ramp, ok := p[keyRampIntervals]; ok {
+		if v, err := parseInt(ramp); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyRampIntervals, err))
+		} else if v < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keyRampIntervals, v))
+		}
+	}
+
+	// Validate RespectManualOverride, if present.
+	//   1. RespectManualOverride must be a boolean.
+	if override, ok := p[keyManualOverride]; ok {
+		if _, ok := override.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keyManualOverride, override))
+		}
+	}
+
+	// Validate DryRunSample, if present.
+	//   1. DryRunSample must be a number.
+	//   2. DryRunSample must be between 0 and 1.
+	if sample, ok := p[keyDryRunSample]; ok {
+		if v, err := parseFloat(sample); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyDryRunSample, err))
+		} else if v < 0 || v > 1 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be between 0 and 1, found %g", path, keyDryRunSample, v))
+		}
+	}
+
+	// Validate LimitsOnly, if present.
+	//   1. LimitsOnly must be a boolean.
+	//   2. LimitsOnly policies must not have Check blocks.
+	if limitsOnly, ok := p[keyLimitsOnly]; ok {
+		if v, ok := limitsOnly.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keyLimitsOnly, limitsOnly))
+		} else if v && p[keyChecks] != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be set on a policy with %s blocks", path, keyLimitsOnly, keyChecks))
+		}
+	}
+
+	// Validate AllowSharedTarget, if present.
+	//   1. AllowSharedTarget must be a boolean.
+	if shared, ok := p[keySharedTarget]; ok {
+		if _, ok := shared.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keySharedTarget, shared))
+		}
+	}
+
 	// Validate Target, if present.
 	if targetInterface, ok := p[keyTarget]; ok {
 		err := validateBlocks(targetInterface, path+"."+keyTarget, validateTarget)
@@ -93,15 +325,67 @@ func validatePolicy(p map[string]interface{}) error {
 		}
 	}
 
-	// Validate Check blocks.
-	err := validateBlocks(p[keyChecks], path+"."+keyChecks, validateChecks)
-	if err != nil {
-		result = multierror.Append(result, err)
+	// Validate Template, if present.
+	//   1. Template should be a string.
+	//   2. Template should not be empty.
+	if template, ok := p[keyTemplate]; ok {
+		if s, ok := template.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyTemplate, template))
+		} else if s == "" {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be empty", path, keyTemplate))
+		}
+	}
+
+	// Validate Tags, if present.
+	//   1. Tags must be a valid block.
+	//   2. Only 1 Tags block allowed.
+	if tags, ok := p[keyTags]; ok {
+		if err := validateBlock(tags, path+"."+keyTags, validateTags); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate LimitsSchedule, if present.
+	//   1. LimitsSchedule must be a valid block.
+	//   2. Only 1 LimitsSchedule block allowed.
+	if schedule, ok := p[keyLimitsSchedule]; ok {
+		if err := validateBlock(schedule, path+"."+keyLimitsSchedule, validateLimitsSchedule); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate Check blocks. Policies which reference a template can omit
+	// them and use the template checks instead, and limits-only policies
+	// don't have any.
+	switch {
+	case omitsTemplatedChecks(p), isLimitsOnly(p):
+	case p[keyChecks] == nil:
+		result = multierror.Append(result, fmt.Errorf("%s.%s is missing, set %s.%s to only enforce the scaling limits",
+			path, keyChecks, path, keyLimitsOnly))
+	default:
+		err := validateBlocks(p[keyChecks], path+"."+keyChecks, validateChecks)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
 	}
 
 	return result.ErrorOrNil()
 }
 
+// omitsTemplatedChecks returns true if the policy references a template and
+// does not define any check blocks.
+func omitsTemplatedChecks(p map[string]interface{}) bool {
+	_, hasTemplate := p[keyTemplate]
+	return hasTemplate && p[keyChecks] == nil
+}
+
+// isLimitsOnly returns true if the policy is a limits-only policy without any
+// check blocks.
+func isLimitsOnly(p map[string]interface{}) bool {
+	limitsOnly, _ := p[keyLimitsOnly].(bool)
+	return limitsOnly && p[keyChecks] == nil
+}
+
 // validateTarget validates target blocks within policy.
 //
 //  scaling {
@@ -178,19 +462,27 @@ func validateCheck(c map[string]interface{}, path string) error {
 
 	// Validate Query.
 	//   1. Query must have string value.
-	//   2. Query must not be empty.
+	//   2. Query must not be empty or only contain whitespace.
 	query, ok := c[keyQuery]
 	if ok {
 		queryStr, ok := query.(string)
 		if !ok {
 			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyQuery, query))
 		} else {
-			if queryStr == "" {
+			if strings.TrimSpace(queryStr) == "" {
 				result = multierror.Append(result, fmt.Errorf("%s.%s can't be empty", path, keyQuery))
 			}
 		}
 	}
 
+	// Validate Enabled, if present.
+	//   1. Enabled must be a boolean.
+	if enabled, ok := c[keyEnabled]; ok {
+		if _, ok := enabled.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keyEnabled, enabled))
+		}
+	}
+
 	// Validate QueryWindow, if present.
 	//   1. QueryWindow should be a valid time duration.
 	queryWindow, ok := c[keyQueryWindow]
@@ -200,10 +492,203 @@ func validateCheck(c map[string]interface{}, path string) error {
 		}
 	}
 
+	// Validate QueryTimeout, if present.
+	//   1. QueryTimeout should be a valid time duration.
+	if queryTimeout, ok := c[keyQueryTimeout]; ok {
+		if err := validateDuration(queryTimeout, path+"."+keyQueryTimeout); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate MaxMetricAge, if present.
+	//   1. MaxMetricAge should be a valid time duration.
+	if maxMetricAge, ok := c[keyMaxMetricAge]; ok {
+		if err := validateDuration(maxMetricAge, path+"."+keyMaxMetricAge); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate MetricWindow, if present.
+	//   1. MetricWindow must be a positive whole number.
+	if metricWindow, ok := c[keyMetricWindow]; ok {
+		if err := validateMetricWindow(metricWindow, path+"."+keyMetricWindow); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate MetricAggregation, if present.
+	//   1. MetricAggregation must be a string.
+	//   2. MetricAggregation must be a supported aggregation.
+	if aggregation, ok := c[keyMetricAggregation]; ok {
+		switch a := aggregation.(type) {
+		case string:
+			switch a {
+			case sdk.MetricAggregationAvg, sdk.MetricAggregationMax, sdk.MetricAggregationP95:
+			default:
+				result = multierror.Append(result, fmt.Errorf(`%s.%s must be one of "%s", "%s" or "%s", found "%s"`,
+					path, keyMetricAggregation, sdk.MetricAggregationAvg, sdk.MetricAggregationMax, sdk.MetricAggregationP95, a))
+			}
+		default:
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyMetricAggregation, aggregation))
+		}
+	}
+
+	// Validate MetricSmoothing, if present.
+	//   1. MetricSmoothing must be a string.
+	//   2. MetricSmoothing must be a supported smoothing.
+	if smoothing, ok := c[keyMetricSmoothing]; ok {
+		switch s := smoothing.(type) {
+		case string:
+			if s != sdk.MetricSmoothingEWMA {
+				result = multierror.Append(result, fmt.Errorf(`%s.%s must be "%s", found "%s"`,
+					path, keyMetricSmoothing, sdk.MetricSmoothingEWMA, s))
+			}
+		default:
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyMetricSmoothing, smoothing))
+		}
+	}
+
+	// Validate MetricSmoothingAlpha, if present.
+	//   1. MetricSmoothingAlpha must be a number between 0 and 1.
+	if alpha, ok := c[keySmoothingAlpha]; ok {
+		if err := validateSmoothingAlpha(alpha, path+"."+keySmoothingAlpha); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate SeriesQueries, if present.
+	//   1. SeriesQueries must be a list.
+	//   2. SeriesQueries must only contain non-empty strings.
+	if queries, ok := c[keySeriesQueries]; ok {
+		if list, ok := queries.([]interface{}); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be list, found %T", path, keySeriesQueries, queries))
+		} else {
+			for i, q := range list {
+				if s, ok := q.(string); !ok {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] must be string, found %T", path, keySeriesQueries, i, q))
+				} else if strings.TrimSpace(s) == "" {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] can't be empty", path, keySeriesQueries, i))
+				}
+			}
+		}
+	}
+
+	// Validate SeriesAggregation, if present.
+	//   1. SeriesAggregation must be a string.
+	//   2. SeriesAggregation must be a supported aggregation.
+	if aggregation, ok := c[keySeriesAggregation]; ok {
+		switch a := aggregation.(type) {
+		case string:
+			switch a {
+			case sdk.MetricAggregationSum, sdk.MetricAggregationAvg, sdk.MetricAggregationMax, sdk.MetricAggregationMin:
+			default:
+				result = multierror.Append(result, fmt.Errorf(`%s.%s must be one of "%s", "%s", "%s" or "%s", found "%s"`,
+					path, keySeriesAggregation, sdk.MetricAggregationSum, sdk.MetricAggregationAvg,
+					sdk.MetricAggregationMax, sdk.MetricAggregationMin, a))
+			}
+		default:
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keySeriesAggregation, aggregation))
+		}
+	}
+
+	// Validate FormulaQueries, if present.
+	//   1. FormulaQueries must be a map.
+	//   2. FormulaQueries values must be strings.
+	if queries, ok := c[keyFormulaQueries]; ok {
+		if err := validateBlock(queries, path+"."+keyFormulaQueries, validateSourceConfig); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate Formula, if present.
+	//   1. Formula must be a string.
+	//   2. Formula can't be empty.
+	if formula, ok := c[keyFormula]; ok {
+		if s, ok := formula.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyFormula, formula))
+		} else if strings.TrimSpace(s) == "" {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be empty", path, keyFormula))
+		}
+	}
+
+	// Validate Transforms, if present.
+	//   1. Transforms must be a list.
+	//   2. Transforms must only contain strings.
+	//   3. Transforms must only contain supported transforms.
+	if transforms, ok := c[keyTransforms]; ok {
+		if list, ok := transforms.([]interface{}); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be list, found %T", path, keyTransforms, transforms))
+		} else {
+			for i, t := range list {
+				if s, ok := t.(string); !ok {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] must be string, found %T", path, keyTransforms, i, t))
+				} else if _, err := sdk.ParseMetricTransform(s); err != nil {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] is invalid: %v", path, keyTransforms, i, err))
+				}
+			}
+		}
+	}
+
+	// Validate Preconditions, if present.
+	//   1. Preconditions must be a list.
+	//   2. Preconditions must only contain strings.
+	//   3. Preconditions must only contain supported preconditions.
+	if preconditions, ok := c[keyPreconditions]; ok {
+		if list, ok := preconditions.([]interface{}); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be list, found %T", path, keyPreconditions, preconditions))
+		} else {
+			for i, p := range list {
+				if s, ok := p.(string); !ok {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] must be string, found %T", path, keyPreconditions, i, p))
+				} else if _, err := sdk.ParseCheckPrecondition(s); err != nil {
+					result = multierror.Append(result, fmt.Errorf("%s.%s[%d] is invalid: %v", path, keyPreconditions, i, err))
+				}
+			}
+		}
+	}
+
+	// Validate PerInstance, if present.
+	//   1. PerInstance must be a boolean.
+	if perInstance, ok := c[keyPerInstance]; ok {
+		if _, ok := perInstance.(bool); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be bool, found %T", path, keyPerInstance, perInstance))
+		}
+	}
+
+	// Validate Priority, if present.
+	//   1. Priority must be a whole number.
+	//   2. Priority must not be negative.
+	if priority, ok := c[keyPriority]; ok {
+		if p, err := parseInt(priority); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, keyPriority, err))
+		} else if p < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, keyPriority, p))
+		}
+	}
+
+	// Validate QueryParams, if present.
+	//   1. QueryParams must be a valid block.
+	//   2. Only 1 QueryParams block allowed.
+	if queryParams, ok := c[keyQueryParams]; ok {
+		if err := validateBlock(queryParams, path+"."+keyQueryParams, validateQueryParams); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Validate SourceConfig, if present.
+	//   1. SourceConfig must be a map.
+	//   2. SourceConfig values must be strings.
+	if sourceConfig, ok := c[keySourceConfig]; ok {
+		if err := validateBlock(sourceConfig, path+"."+keySourceConfig, validateSourceConfig); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	// Validate Strategy.
 	//   1. Strategy key must exist.
 	//   2. Strategy must be a valid block.
-	//   3. Only 1 Strategy allowed.
+	//   3. Strategies after the first are run as a chain, so each strategy
+	//      may only be used once.
 	strategyErrs := validateBlocks(c[keyStrategy], path+"."+keyStrategy, validateStrategy)
 	if strategyErrs != nil {
 		result = multierror.Append(result, strategyErrs)
@@ -212,6 +697,229 @@ func validateCheck(c map[string]interface{}, path string) error {
 	return result.ErrorOrNil()
 }
 
+// validateQueryParams validates the query_params block within a policy check.
+//
+//  scaling {
+//    policy {
+//      check "check" {
+//      +---------------------+
+//      | query_params {      |
+//      |   window = "1m"     |
+//      | }                   |
+//      +---------------------+
+//      }
+//    }
+//  }
+//
+// Validation rules:
+//   1. Only window, rollup and aggregator keys are allowed.
+//   2. window must be a valid time duration.
+//   3. rollup and aggregator must be non-empty strings.
+func validateQueryParams(qp map[string]interface{}, path string) error {
+	var result *multierror.Error
+
+	// Sort the keys so errors are reported in a consistent order.
+	keys := make([]string, 0, len(qp))
+	for k := range qp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := qp[k]
+		switch k {
+		case keyWindow:
+			if err := validateDuration(v, path+"."+keyWindow); err != nil {
+				result = multierror.Append(result, err)
+			}
+		case keyRollup, keyAggregator:
+			s, ok := v.(string)
+			if !ok {
+				result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, k, v))
+			} else if s == "" {
+				result = multierror.Append(result, fmt.Errorf("%s.%s can't be empty", path, k))
+			}
+		default:
+			result = multierror.Append(result, fmt.Errorf("%s.%s is not a supported query parameter", path, k))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateSourceConfig validates the source_config block within a policy
+// check. Whether the keys can be overridden is validated by the APM plugin.
+//
+//  scaling {
+//    policy {
+//      check "check" {
+//      +------------------------------------------+
+//      | source_config = {                        |
+//      |   address = "http://prometheus:9090"     |
+//      |   "header.Authorization" = "Bearer ..."  |
+//      | }                                        |
+//      +------------------------------------------+
+//      }
+//    }
+//  }
+//
+// Validation rules:
+//   1. Values must be strings.
+func validateSourceConfig(sc map[string]interface{}, path string) error {
+	var result *multierror.Error
+
+	// Sort the keys so errors are reported in a consistent order.
+	keys := make([]string, 0, len(sc))
+	for k := range sc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := sc[k].(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, k, sc[k]))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateTags validates the tags block within a policy.
+//
+//  scaling {
+//    policy {
+//    +------------------+
+//    | tags {           |
+//    |   team = "infra" |
+//    | }                |
+//    +------------------+
+//    }
+//  }
+//
+// Validation rules:
+//   1. Tag values must be strings.
+func validateTags(t map[string]interface{}, path string) error {
+	var result *multierror.Error
+
+	// Sort the keys so errors are reported in a consistent order.
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := t[k].(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, k, t[k]))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateLimitsSchedule validates the limits_schedule block within a policy.
+//
+//  scaling {
+//    policy {
+//    +------------------------------+
+//    | limits_schedule {            |
+//    |   timezone = "Europe/Berlin" |
+//    |   window "name" { ... }      |
+//    | }                            |
+//    +------------------------------+
+//    }
+//  }
+//
+// Validation rules:
+//   1. Timezone must be a valid IANA timezone name, if present.
+//   2. At least one window block.
+//   3. All window blocks should have labels.
+//   4. All window blocks structure should be valid.
+func validateLimitsSchedule(s map[string]interface{}, path string) error {
+	var result *multierror.Error
+
+	if tz, ok := s[keyTimezone]; ok {
+		if v, ok := tz.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyTimezone, tz))
+		} else if _, err := time.LoadLocation(v); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s is invalid: %v", path, keyTimezone, err))
+		}
+	}
+
+	validateWindows := func(in map[string]interface{}, path string) error {
+		return validateLabeledBlocks(in, path, ptr.IntToPtr(1), nil, validateLimitsWindow)
+	}
+	if err := validateBlocks(s[keyWindow], path+"."+keyWindow, validateWindows); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateLimitsWindow validates the content of a window block within the
+// limits_schedule block of a policy.
+//
+//  scaling {
+//    policy {
+//      limits_schedule {
+//      +-----------------------+
+//      | window "name" {       |
+//      |   days  = "mon-fri"   |
+//      |   start = "08:00"     |
+//      |   end   = "18:00"     |
+//      |   min   = 10          |
+//      |   max   = 20          |
+//      | }                     |
+//      +-----------------------+
+//      }
+//    }
+//  }
+//
+// Validation rules:
+//   1. Days must be a string, if present.
+//   2. Start and end are required and must be strings.
+//   3. Min and max must be non-negative whole numbers, and at least one of
+//      them is required.
+func validateLimitsWindow(w map[string]interface{}, path string) error {
+	var result *multierror.Error
+
+	if days, ok := w[keyDays]; ok {
+		if _, ok := days.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, keyDays, days))
+		}
+	}
+
+	for _, key := range []string{keyStart, keyEnd} {
+		v, ok := w[key]
+		if !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s is missing", path, key))
+			continue
+		}
+		if _, ok := v.(string); !ok {
+			result = multierror.Append(result, fmt.Errorf("%s.%s must be string, found %T", path, key, v))
+		}
+	}
+
+	_, hasMin := w[keyMin]
+	_, hasMax := w[keyMax]
+	if !hasMin && !hasMax {
+		result = multierror.Append(result, fmt.Errorf("%s must set %s or %s", path, keyMin, keyMax))
+	}
+	for _, key := range []string{keyMin, keyMax} {
+		v, ok := w[key]
+		if !ok {
+			continue
+		}
+		if n, err := parseInt(v); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s.%s %v", path, key, err))
+		} else if n < 0 {
+			result = multierror.Append(result, fmt.Errorf("%s.%s can't be negative, found %d", path, key, n))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
 // validateStrategy validates strategy blocks within a policy check.
 //
 //  scaling {
@@ -227,11 +935,11 @@ func validateCheck(c map[string]interface{}, path string) error {
 //  }
 //
 // Validation rules:
-//   1. Only one strategy block.
+//   1. At least one strategy block.
 //   2. Block must have a label.
 //   3. Block structure should be valid.
 func validateStrategy(s map[string]interface{}, path string) error {
-	return validateLabeledBlocks(s, path, ptr.IntToPtr(1), ptr.IntToPtr(1), nil)
+	return validateLabeledBlocks(s, path, ptr.IntToPtr(1), nil, nil)
 }
 
 // validateDuration validates if the input has a valid time.Duration format.
@@ -252,6 +960,81 @@ func validateDuration(d interface{}, path string) error {
 	return nil
 }
 
+// validatePriority validates if the input is a valid policy priority.
+//
+// Validation rules:
+//   1. Input must be a whole number.
+//   2. Input must be within the allowed priority range.
+func validatePriority(p interface{}, path string) error {
+	priority, err := parseInt(p)
+	if err != nil {
+		return fmt.Errorf("%s %v", path, err)
+	}
+
+	if priority < sdk.ScalingPolicyPriorityMin || priority > sdk.ScalingPolicyPriorityMax {
+		return fmt.Errorf("%s must be between %d and %d, found %d",
+			path, sdk.ScalingPolicyPriorityMin, sdk.ScalingPolicyPriorityMax, priority)
+	}
+
+	return nil
+}
+
+// validateFactor validates if the input is a valid policy factor, such as
+// the cooldown bypass factor.
+//
+// Validation rules:
+//   1. Input must be a number.
+//   2. Input must be greater than 1.
+func validateFactor(f interface{}, path string) error {
+	factor, err := parseFloat(f)
+	if err != nil {
+		return fmt.Errorf("%s %v", path, err)
+	}
+
+	if factor <= 1 {
+		return fmt.Errorf("%s must be greater than 1, found %v", path, factor)
+	}
+
+	return nil
+}
+
+// validateSmoothingAlpha validates if the input is a valid check metric
+// smoothing alpha.
+//
+// Validation rules:
+//   1. Input must be a number.
+//   2. Input must be greater than 0 and no greater than 1.
+func validateSmoothingAlpha(a interface{}, path string) error {
+	alpha, err := parseFloat(a)
+	if err != nil {
+		return fmt.Errorf("%s %v", path, err)
+	}
+
+	if alpha <= 0 || alpha > 1 {
+		return fmt.Errorf("%s must be greater than 0 and no greater than 1, found %v", path, alpha)
+	}
+
+	return nil
+}
+
+// validateMetricWindow validates if the input is a valid check metric window.
+//
+// Validation rules:
+//   1. Input must be a whole number.
+//   2. Input must be greater than zero.
+func validateMetricWindow(w interface{}, path string) error {
+	window, err := parseInt(w)
+	if err != nil {
+		return fmt.Errorf("%s %v", path, err)
+	}
+
+	if window < 1 {
+		return fmt.Errorf("%s must be greater than 0, found %d", path, window)
+	}
+
+	return nil
+}
+
 // validateBlock validates the structure of a block parsed from HCL.
 // The content of the block can be further validated by passing a `validator`
 // function.
@@ -285,8 +1068,11 @@ func validateBlock(in interface{}, path string, validator validatorFunc) error {
 		return multierror.Append(result, fmt.Errorf("%s must be []interface{}, found %T", path, in))
 	}
 
-	if len(list) != 1 {
-		return multierror.Append(result, fmt.Errorf("%s must have length 1, found %d", path, len(list)))
+	switch {
+	case len(list) == 0:
+		return multierror.Append(result, fmt.Errorf("%s must have length 1, found 0", path))
+	case len(list) > 1:
+		return multierror.Append(result, fmt.Errorf("%s must only be defined once, found %d blocks", path, len(list)))
 	}
 
 	inMap, ok := list[0].(map[string]interface{})
@@ -345,6 +1131,12 @@ func validateBlocks(in interface{}, path string, validator validatorFunc) error
 		}
 
 		for k, v := range blockMap {
+			// Flattening blocks with the same label would silently drop all
+			// but the last, so report them instead.
+			if _, ok := blocksMap[k]; ok {
+				result = multierror.Append(result, fmt.Errorf("%s[%s] must only be defined once", path, k))
+				continue
+			}
 			blocksMap[k] = v
 		}
 	}
@@ -36,10 +36,12 @@ func validateHorizontalPolicy(policy *api.ScalingPolicy) error {
 		}
 	}
 
-	// Validate Check blocks.
-	err := validateBlocks(policy.Policy[keyChecks], "scaling.policy."+keyChecks, validateChecksHorizontal)
-	if err != nil {
-		result = multierror.Append(result, err)
+	// Validate Check blocks. Missing blocks are reported by validatePolicy.
+	if policy.Policy[keyChecks] != nil {
+		err := validateBlocks(policy.Policy[keyChecks], "scaling.policy."+keyChecks, validateChecksHorizontal)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
 	}
 
 	return result.ErrorOrNil()
@@ -39,6 +39,119 @@ func Test_validateScalingPolicy(t *testing.T) {
 			input:       &api.ScalingPolicy{},
 			expectError: true,
 		},
+		{
+			name: "templated policy without checks",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min: ptr.Int64ToPtr(1),
+				Max: ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{
+					keyTemplate: "web",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "policy without checks",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min:    ptr.Int64ToPtr(1),
+				Max:    ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{},
+			},
+			expectError: true,
+		},
+		{
+			name: "limits-only policy without checks",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min: ptr.Int64ToPtr(1),
+				Max: ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{
+					keyLimitsOnly: true,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "limits-only policy with checks",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min: ptr.Int64ToPtr(1),
+				Max: ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{
+					keyLimitsOnly: true,
+					keyChecks: []interface{}{
+						map[string]interface{}{
+							"check": []interface{}{
+								map[string]interface{}{
+									keySource: "source",
+									keyQuery:  "query",
+									keyStrategy: []interface{}{
+										map[string]interface{}{
+											"strategy": []interface{}{
+												map[string]interface{}{
+													"key": "value",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "limits_only is not a bool",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min: ptr.Int64ToPtr(1),
+				Max: ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{
+					keyLimitsOnly: "yes",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "template is not a string",
+			input: &api.ScalingPolicy{
+				ID:   "id",
+				Type: "horizontal",
+				Target: map[string]string{
+					"key": "value",
+				},
+				Min: ptr.Int64ToPtr(1),
+				Max: ptr.Int64ToPtr(5),
+				Policy: map[string]interface{}{
+					keyTemplate: 2,
+				},
+			},
+			expectError: true,
+		},
 		{
 			name: "id is missing",
 			input: &api.ScalingPolicy{
@@ -268,11 +381,91 @@ func Test_validateScalingPolicy(t *testing.T) {
 			inputFile:   "invalid-query-window2",
 			expectError: true,
 		},
+		{
+			name:        "policy.check.query_timeout is not a duration",
+			inputFile:   "invalid-query-timeout",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.max_metric_age is not a duration",
+			inputFile:   "invalid-max-metric-age",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.query_params is not valid",
+			inputFile:   "invalid-query-params",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.source_config value is not a string",
+			inputFile:   "invalid-source-config",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.enabled is not a bool",
+			inputFile:   "invalid-check-enabled",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.metric_window is not positive",
+			inputFile:   "invalid-metric-window",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.per_instance is not bool",
+			inputFile:   "invalid-per-instance",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.priority is negative",
+			inputFile:   "invalid-check-priority",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.metric_aggregation is not supported",
+			inputFile:   "invalid-metric-aggregation",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.metric_smoothing is not supported",
+			inputFile:   "invalid-metric-smoothing",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.metric_smoothing_alpha is greater than 1",
+			inputFile:   "invalid-metric-smoothing-alpha",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.series_aggregation is not supported",
+			inputFile:   "invalid-series-aggregation",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.formula is invalid",
+			inputFile:   "invalid-formula",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.transforms is not supported",
+			inputFile:   "invalid-transforms",
+			expectError: true,
+		},
+		{
+			name:        "policy.check.preconditions contains unsupported precondition",
+			inputFile:   "invalid-preconditions",
+			expectError: true,
+		},
 		{
 			name:        "policy.check.query is empty",
 			inputFile:   "invalid-empty-query",
 			expectError: true,
 		},
+		{
+			name:        "policy.check.query is only whitespace",
+			inputFile:   "invalid-whitespace-query",
+			expectError: true,
+		},
 		{
 			name:        "policy.check.strategy is missing",
 			inputFile:   "missing-strategy",
@@ -343,9 +536,9 @@ func Test_validateScalingPolicy(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name:        "policy.check.strategy multiple",
-			inputFile:   "invalid-multiple-strategies",
-			expectError: true,
+			name:        "policy.check.strategy chain",
+			inputFile:   "strategy-chain",
+			expectError: false,
 		},
 		{
 			name:        "policy.evaluation_interval has wrong type",
@@ -454,6 +647,116 @@ func Test_validateScalingPolicy(t *testing.T) {
 			inputFile:   "invalid-cooldown",
 			expectError: true,
 		},
+		{
+			name:        "policy.zero_cooldown has wrong format",
+			inputFile:   "invalid-zero-cooldown",
+			expectError: true,
+		},
+		{
+			name:        "policy.cooldown_per_unit has wrong format",
+			inputFile:   "invalid-cooldown-per-unit",
+			expectError: true,
+		},
+		{
+			name:        "policy.cooldown_on_completion has wrong type",
+			inputFile:   "invalid-cooldown-on-completion",
+			expectError: true,
+		},
+		{
+			name:        "policy.supersede_in_flight has wrong type",
+			inputFile:   "invalid-supersede-in-flight",
+			expectError: true,
+		},
+		{
+			name:        "policy.soft_max is negative",
+			inputFile:   "invalid-soft-max",
+			expectError: true,
+		},
+		{
+			name:        "policy.on_metric_error is not supported",
+			inputFile:   "invalid-on-metric-error",
+			expectError: true,
+		},
+		{
+			name:        "policy.quorum is negative",
+			inputFile:   "invalid-quorum",
+			expectError: true,
+		},
+		{
+			name:        "policy.scale_in_bias is negative",
+			inputFile:   "invalid-scale-in-bias",
+			expectError: true,
+		},
+		{
+			name:        "policy.tags has wrong type",
+			inputFile:   "invalid-tags",
+			expectError: true,
+		},
+		{
+			name:        "policy.limits_schedule has invalid window",
+			inputFile:   "invalid-limits-schedule",
+			expectError: true,
+		},
+		{
+			name:        "policy.scale_in_stabilization_window has wrong format",
+			inputFile:   "invalid-scale-in-stabilization-window",
+			expectError: true,
+		},
+		{
+			name:        "policy.startup_grace_period has wrong format",
+			inputFile:   "invalid-startup-grace-period",
+			expectError: true,
+		},
+		{
+			name:        "policy.dedup_window has wrong format",
+			inputFile:   "invalid-dedup-window",
+			expectError: true,
+		},
+		{
+			name:        "policy.priority has wrong type",
+			inputFile:   "invalid-priority-type",
+			expectError: true,
+		},
+		{
+			name:        "policy.priority out of range",
+			inputFile:   "invalid-priority",
+			expectError: true,
+		},
+		{
+			name:        "policy.cooldown_bypass_factor too small",
+			inputFile:   "invalid-cooldown-bypass-factor",
+			expectError: true,
+		},
+		{
+			name:        "policy.hysteresis_factor too small",
+			inputFile:   "invalid-hysteresis-factor",
+			expectError: true,
+		},
+		{
+			name:        "policy.min_change_percentage out of range",
+			inputFile:   "invalid-min-change",
+			expectError: true,
+		},
+		{
+			name:        "policy.dry_run_sample out of range",
+			inputFile:   "invalid-dry-run-sample",
+			expectError: true,
+		},
+		{
+			name:        "policy.ramp_intervals is negative",
+			inputFile:   "invalid-ramp-intervals",
+			expectError: true,
+		},
+		{
+			name:        "policy.respect_manual_override has wrong type",
+			inputFile:   "invalid-respect-manual-override",
+			expectError: true,
+		},
+		{
+			name:        "policy.allow_shared_target has wrong type",
+			inputFile:   "invalid-allow-shared-target",
+			expectError: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -533,6 +836,18 @@ func Test_validateBlock(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "block defined multiple times",
+			input: []interface{}{
+				map[string]interface{}{
+					"key": "value",
+				},
+				map[string]interface{}{
+					"key": "other",
+				},
+			},
+			expectError: true,
+		},
 		{
 			name:        "block root first element has wront type",
 			input:       []interface{}{1},
@@ -578,3 +893,57 @@ func Test_validateBlock(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateBlocks(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       interface{}
+		expectError bool
+	}{
+		{
+			name: "valid blocks",
+			input: []interface{}{
+				map[string]interface{}{
+					"label-1": []interface{}{map[string]interface{}{"key": "value"}},
+				},
+				map[string]interface{}{
+					"label-2": []interface{}{map[string]interface{}{"key": "value"}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "label defined multiple times",
+			input: []interface{}{
+				map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"key": "value"}},
+				},
+				map[string]interface{}{
+					"label": []interface{}{map[string]interface{}{"key": "other"}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name:        "blocks are nil",
+			input:       nil,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateBlocks(tc.input, "path.key", nil)
+			if err != nil && *showValidationError {
+				fmt.Println(err)
+			}
+
+			assertFunc := assert.NoError
+			if tc.expectError {
+				assertFunc = assert.Error
+			}
+
+			assertFunc(t, err)
+		})
+	}
+}
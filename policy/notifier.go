@@ -0,0 +1,102 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// webhookTimeout is the maximum time a webhook request is allowed to take.
+const webhookTimeout = 10 * time.Second
+
+// CooldownExpiry details the expiry of the cooldown of a policy, after which
+// the policy is able to scale its target again.
+type CooldownExpiry struct {
+	PolicyID string
+
+	// Cooldown is the duration of the cooldown which expired.
+	Cooldown time.Duration
+
+	// ExpiredAt is the time at which the cooldown expiry was observed.
+	ExpiredAt time.Time
+}
+
+// CooldownNotifier is notified of policy cooldown expiries.
+type CooldownNotifier interface {
+
+	// CooldownExpired is called when the cooldown of a policy expires. It
+	// must not block, as it is called by the policy handler.
+	CooldownExpired(CooldownExpiry)
+}
+
+// WebhookNotifier is a CooldownNotifier which sends each expiry as a JSON
+// document in a POST request to a URL. Requests are sent in the background
+// and failures are only logged, as the notifications are informational.
+type WebhookNotifier struct {
+	log    hclog.Logger
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a new WebhookNotifier which sends requests to
+// the passed URL.
+func NewWebhookNotifier(log hclog.Logger, url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		log:    log.Named("cooldown_webhook"),
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// CooldownExpired satisfies the CooldownExpired function on the
+// CooldownNotifier interface.
+func (n *WebhookNotifier) CooldownExpired(e CooldownExpiry) {
+	go func() {
+		if err := n.send(context.Background(), e); err != nil {
+			n.log.Warn("failed to send cooldown expiry", "policy_id", e.PolicyID, "error", err)
+		}
+	}()
+}
+
+// webhookPayload is the body of the webhook requests.
+type webhookPayload struct {
+	Event     string    `json:"event"`
+	PolicyID  string    `json:"policy_id"`
+	Cooldown  string    `json:"cooldown"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (n *WebhookNotifier) send(ctx context.Context, e CooldownExpiry) error {
+	body, err := json.Marshal(&webhookPayload{
+		Event:     "cooldown_expired",
+		PolicyID:  e.PolicyID,
+		Cooldown:  e.Cooldown.String(),
+		ExpiredAt: e.ExpiredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_send(t *testing.T) {
+	var payload webhookPayload
+	status := http.StatusOK
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	expiredAt := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	n := NewWebhookNotifier(hclog.NewNullLogger(), ts.URL)
+
+	err := n.send(context.Background(), CooldownExpiry{PolicyID: "policy", Cooldown: 5 * time.Minute, ExpiredAt: expiredAt})
+	require.NoError(t, err)
+	assert.Equal(t, webhookPayload{
+		Event:     "cooldown_expired",
+		PolicyID:  "policy",
+		Cooldown:  "5m0s",
+		ExpiredAt: expiredAt,
+	}, payload)
+
+	status = http.StatusInternalServerError
+	err = n.send(context.Background(), CooldownExpiry{PolicyID: "policy"})
+	assert.EqualError(t, err, `unexpected response status "500 Internal Server Error"`)
+}
@@ -2,7 +2,10 @@ package policy
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad-autoscaler/plugins"
@@ -10,9 +13,27 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
 
+// tagNameRegexp matches the policy tag names which are valid telemetry label
+// names.
+var tagNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedTagNames are the telemetry label names set by the autoscaler, which
+// policy tags can't override.
+var reservedTagNames = map[string]bool{
+	"error_type":  true,
+	"phase":       true,
+	"plugin_name": true,
+	"policy_id":   true,
+	"target_name": true,
+}
+
 // Processor helps process policies and perform common actions on them when
 // they are discovered from their source.
 type Processor struct {
+
+	// lock protects the fields below which can be updated when the agent
+	// configuration is reloaded.
+	lock      sync.RWMutex
 	defaults  *ConfigDefaults
 	nomadAPMs []string
 }
@@ -25,10 +46,26 @@ func NewProcessor(defaults *ConfigDefaults, apms []string) *Processor {
 	}
 }
 
+// Update replaces the config defaults and Nomad APM names used by the
+// processor. Policies processed after the update use the new values.
+func (pr *Processor) Update(defaults *ConfigDefaults, apms []string) {
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	pr.defaults = defaults
+	pr.nomadAPMs = apms
+}
+
 // ApplyPolicyDefaults applies the config defaults to the policy where the
 // operator does not supply the parameter. This can be used for both cluster
 // and task group policies.
 func (pr *Processor) ApplyPolicyDefaults(p *sdk.ScalingPolicy) {
+	pr.lock.RLock()
+	defer pr.lock.RUnlock()
+
+	if p.Priority == 0 {
+		p.Priority = sdk.ScalingPolicyPriorityDefault
+	}
 	if p.Cooldown == 0 {
 		p.Cooldown = pr.defaults.DefaultCooldown
 	}
@@ -41,6 +78,15 @@ func (pr *Processor) ApplyPolicyDefaults(p *sdk.ScalingPolicy) {
 		if c.QueryWindow == 0 {
 			c.QueryWindow = DefaultQueryWindow
 		}
+		if c.MetricWindow > 0 && c.MetricAggregation == "" {
+			c.MetricAggregation = sdk.MetricAggregationAvg
+		}
+		if c.MetricSmoothing != "" && c.MetricSmoothingAlpha == 0 {
+			c.MetricSmoothingAlpha = sdk.MetricSmoothingAlphaDefault
+		}
+		if len(c.SeriesQueries) > 0 && c.SeriesAggregation == "" {
+			c.SeriesAggregation = sdk.MetricAggregationAvg
+		}
 	}
 }
 
@@ -62,10 +108,330 @@ func (pr *Processor) ValidatePolicy(p *sdk.ScalingPolicy) error {
 	if p.Min > p.Max {
 		mErr = multierror.Append(mErr, fmt.Errorf("policy Min must not be greater Max"))
 	}
+	if s := p.LimitsSchedule; s != nil {
+		if err := s.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("policy LimitsSchedule is invalid: %v", err))
+		} else {
+			for _, w := range s.Windows {
+				if w == nil {
+					continue
+				}
+				if min, max := windowLimits(p, w); min > max {
+					mErr = multierror.Append(mErr, fmt.Errorf("policy LimitsSchedule window %s Min must not be greater Max", w.Name))
+				}
+			}
+		}
+	}
+	if p.SoftMax < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy SoftMax can't be negative"))
+	} else if p.SoftMax > 0 && (p.SoftMax < p.Min || p.SoftMax > p.Max) {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy SoftMax must be between Min and Max"))
+	}
+	if p.Priority != 0 && (p.Priority < sdk.ScalingPolicyPriorityMin || p.Priority > sdk.ScalingPolicyPriorityMax) {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy Priority must be between %d and %d",
+			sdk.ScalingPolicyPriorityMin, sdk.ScalingPolicyPriorityMax))
+	}
+	if p.CooldownBypassFactor != 0 && p.CooldownBypassFactor <= 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy CooldownBypassFactor must be greater than 1"))
+	}
+	if p.ZeroCooldown < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ZeroCooldown can't be negative"))
+	}
+	if p.CooldownPerUnit < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy CooldownPerUnit can't be negative"))
+	} else if p.CooldownPerUnit > 0 && p.MaxCooldown <= 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy MaxCooldown must be set when CooldownPerUnit is set"))
+	}
+	if p.MaxCooldown < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy MaxCooldown can't be negative"))
+	}
+	if p.ScaleInStabilizationWindow < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ScaleInStabilizationWindow can't be negative"))
+	}
+	if p.StartupGracePeriod < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy StartupGracePeriod can't be negative"))
+	}
+	if p.DedupWindow < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy DedupWindow can't be negative"))
+	}
+	switch p.OnMetricError {
+	case "", sdk.MetricErrorHold, sdk.MetricErrorScaleToSafe:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("policy OnMetricError %q is not supported", p.OnMetricError))
+	}
+	if p.SafeCount < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy SafeCount can't be negative"))
+	} else if p.SafeCount > 0 && (p.SafeCount < p.Min || p.SafeCount > p.Max) {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy SafeCount must be between Min and Max"))
+	}
+	if p.Quorum < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy Quorum can't be negative"))
+	} else if p.Quorum > len(p.Checks) {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy Quorum must not be greater than the number of checks"))
+	}
+	if p.ScaleInBias < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ScaleInBias can't be negative"))
+	} else if p.ScaleInBias > 0 && p.ScaleInStabilizationWindow <= 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ScaleInStabilizationWindow must be set when ScaleInBias is set"))
+	}
+	if p.ScaleInBiasBand < 0 || p.ScaleInBiasBand >= 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ScaleInBiasBand must be between 0 and 1"))
+	} else if p.ScaleInBias > 0 && p.ScaleInBiasBand == 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy ScaleInBiasBand must be set when ScaleInBias is set"))
+	}
+	if p.HysteresisFactor != 0 && p.HysteresisFactor <= 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy HysteresisFactor must be greater than 1"))
+	}
+	if p.MinChangeCount < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy MinChangeCount can't be negative"))
+	}
+	if p.MinChangePercentage < 0 || p.MinChangePercentage > 100 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy MinChangePercentage must be between 0 and 100"))
+	}
+	if p.RampIntervals < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy RampIntervals can't be negative"))
+	}
+	if p.DryRunSample < 0 || p.DryRunSample > 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy DryRunSample must be between 0 and 1"))
+	}
+	if len(p.Checks) == 0 && !p.LimitsOnly {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy must have at least one check, or set LimitsOnly to only enforce Min and Max"))
+	} else if len(p.Checks) > 0 && p.LimitsOnly {
+		mErr = multierror.Append(mErr, fmt.Errorf("policy LimitsOnly can't be set on a policy with checks"))
+	}
+
+	for _, c := range p.Checks {
+		if strings.TrimSpace(c.Query) == "" {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s Query can't be empty", c.Name))
+		}
+		if c.QueryTimeout < 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s QueryTimeout can't be negative", c.Name))
+		}
+		if c.MaxMetricAge < 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MaxMetricAge can't be negative", c.Name))
+		}
+		if c.QueryParams != nil && c.QueryParams.Window < 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s QueryParams Window can't be negative", c.Name))
+		}
+		if c.MetricWindow < 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricWindow can't be negative", c.Name))
+		}
+		if c.Priority < 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s Priority can't be negative", c.Name))
+		}
+		if c.PerInstance && c.MetricWindow > 1 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricWindow is not supported for per-instance checks", c.Name))
+		}
+		switch c.MetricAggregation {
+		case "", sdk.MetricAggregationAvg, sdk.MetricAggregationMax, sdk.MetricAggregationP95:
+		default:
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricAggregation %q is not supported", c.Name, c.MetricAggregation))
+		}
+		switch c.MetricSmoothing {
+		case "", sdk.MetricSmoothingEWMA:
+		default:
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricSmoothing %q is not supported", c.Name, c.MetricSmoothing))
+		}
+		if c.MetricSmoothingAlpha < 0 || c.MetricSmoothingAlpha > 1 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricSmoothingAlpha must be between 0 and 1", c.Name))
+		}
+		if c.MetricSmoothing != "" && c.MetricWindow > 1 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricSmoothing can't be used with a MetricWindow", c.Name))
+		}
+		if c.PerInstance && c.MetricSmoothing != "" {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s MetricSmoothing is not supported for per-instance checks", c.Name))
+		}
+		for _, q := range c.SeriesQueries {
+			if strings.TrimSpace(q) == "" {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s SeriesQueries can't contain empty queries", c.Name))
+				break
+			}
+		}
+		if c.PerInstance && len(c.SeriesQueries) > 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s SeriesQueries is not supported for per-instance checks", c.Name))
+		}
+		if len(c.FormulaQueries) > 0 && c.Formula == "" {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s FormulaQueries requires a Formula", c.Name))
+		}
+		for _, name := range formulaQueryNames(c) {
+			if !formulaQueryNameRegexp.MatchString(name) || name == sdk.MetricFormulaQueryName {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s FormulaQueries name %q must only contain letters, digits and underscores, not start with a digit and not be %q",
+					c.Name, name, sdk.MetricFormulaQueryName))
+			}
+			if strings.TrimSpace(c.FormulaQueries[name]) == "" {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s FormulaQueries query %s can't be empty", c.Name, name))
+			}
+		}
+		if c.Formula != "" {
+			if _, err := ParseCheckFormula(c); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s Formula is invalid: %v", c.Name, err))
+			}
+			if c.PerInstance {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s Formula is not supported for per-instance checks", c.Name))
+			}
+			if len(c.SeriesQueries) > 0 {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s Formula can't be used with SeriesQueries", c.Name))
+			}
+		}
+		if c.PerInstance && len(c.SourceConfig) > 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s SourceConfig is not supported for per-instance checks", c.Name))
+		}
+		switch c.SeriesAggregation {
+		case "", sdk.MetricAggregationSum, sdk.MetricAggregationAvg, sdk.MetricAggregationMax, sdk.MetricAggregationMin:
+		default:
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s SeriesAggregation %q is not supported", c.Name, c.SeriesAggregation))
+		}
+		if _, err := sdk.ParseMetricTransforms(c.Transforms); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s Transforms is invalid: %v", c.Name, err))
+		}
+		if _, err := sdk.ParseCheckPreconditions(c.Preconditions); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s Preconditions is invalid: %v", c.Name, err))
+		}
+	}
+
+	// Sort the tag names so errors are reported in a consistent order.
+	tagNames := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		tagNames = append(tagNames, k)
+	}
+	sort.Strings(tagNames)
+
+	for _, k := range tagNames {
+		switch {
+		case !tagNameRegexp.MatchString(k):
+			mErr = multierror.Append(mErr, fmt.Errorf("policy Tags name %q must only contain letters, digits and underscores and not start with a digit", k))
+		case reservedTagNames[k]:
+			mErr = multierror.Append(mErr, fmt.Errorf("policy Tags name %q is reserved", k))
+		}
+	}
+
+	if err := pr.ValidateTargetConfig(p); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ValidateTargetConfig validates the policy target config against the schema
+// provided by the target plugin, and then using the target plugin validator.
+// Policies whose target does not provide a schema or validator are not
+// validated.
+func (pr *Processor) ValidateTargetConfig(p *sdk.ScalingPolicy) error {
+	if p.Target == nil {
+		return nil
+	}
+
+	pr.lock.RLock()
+	if pr.defaults == nil {
+		pr.lock.RUnlock()
+		return nil
+	}
+	schema := pr.defaults.TargetSchemas[p.Target.Name]
+	validator := pr.defaults.TargetValidators[p.Target.Name]
+	pr.lock.RUnlock()
+
+	if schema != nil {
+		if err := schema.Validate(p.Target.Config); err != nil {
+			return fmt.Errorf("policy target %s config is invalid: %v", p.Target.Name, err)
+		}
+	}
+
+	// The validator may query the remote target, so it is called without
+	// holding the lock.
+	if validator != nil {
+		if err := validator.ValidateTargetConfig(p.Target.Config); err != nil {
+			return fmt.Errorf("policy target %s is invalid: %v", p.Target.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateCheckQueries validates the policy check queries using the APM
+// plugins which provide a query validator, along with the check source config
+// overrides and the strategy configs of strategy plugins which provide a
+// config validator. It must be called once the checks are canonicalized, as
+// this sets their source and expands short queries.
+func (pr *Processor) ValidateCheckQueries(p *sdk.ScalingPolicy) error {
+	pr.lock.RLock()
+	defer pr.lock.RUnlock()
+
+	if pr.defaults == nil {
+		return nil
+	}
+
+	var mErr *multierror.Error
+
+	for _, c := range p.Checks {
+		if err := pr.validateSourceConfig(c); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+		if err := pr.validateStrategyConfigs(c); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+
+		v, ok := pr.defaults.QueryValidators[c.Source]
+		if !ok || v == nil {
+			continue
+		}
+		if err := v.ValidateQuery(c.Query); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s Query is invalid: %v", c.Name, err))
+		}
+		for _, q := range c.SeriesQueries {
+			if err := v.ValidateQuery(q); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s SeriesQueries query %q is invalid: %v", c.Name, q, err))
+			}
+		}
+		for _, name := range formulaQueryNames(c) {
+			if err := v.ValidateQuery(c.FormulaQueries[name]); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("check %s FormulaQueries query %s is invalid: %v", c.Name, name, err))
+			}
+		}
+	}
 
 	return mErr.ErrorOrNil()
 }
 
+// validateSourceConfig validates the source config overrides of the check
+// using the APM plugin, which must allow its config to be overridden. The
+// caller must hold the lock.
+func (pr *Processor) validateSourceConfig(c *sdk.ScalingPolicyCheck) error {
+	if len(c.SourceConfig) == 0 || pr.defaults.ConfigOverriders == nil {
+		return nil
+	}
+
+	o, ok := pr.defaults.ConfigOverriders[c.Source]
+	if !ok || o == nil {
+		return fmt.Errorf("check %s SourceConfig is not supported by source %s", c.Name, c.Source)
+	}
+	if err := o.ValidateConfigOverride(c.SourceConfig); err != nil {
+		return fmt.Errorf("check %s SourceConfig is invalid: %v", c.Name, err)
+	}
+	return nil
+}
+
+// validateStrategyConfigs validates the config of the check strategy and its
+// chained strategies using the strategy plugins which provide a config
+// validator. The caller must hold the lock.
+func (pr *Processor) validateStrategyConfigs(c *sdk.ScalingPolicyCheck) error {
+	var mErr *multierror.Error
+
+	strategies := c.Chain
+	if c.Strategy != nil {
+		strategies = append([]*sdk.ScalingPolicyStrategy{c.Strategy}, c.Chain...)
+	}
+
+	for _, s := range strategies {
+		v, ok := pr.defaults.StrategyValidators[s.Name]
+		if !ok || v == nil {
+			continue
+		}
+		if err := v.ValidateStrategyConfig(s.Config); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("check %s strategy %s config is invalid: %v", c.Name, s.Name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 // CanonicalizeCheck sets standardised values on fields.
 func (pr *Processor) CanonicalizeCheck(c *sdk.ScalingPolicyCheck, t *sdk.ScalingPolicyTarget) {
 
@@ -122,6 +488,9 @@ func (pr *Processor) CanonicalizeAPMQuery(c *sdk.ScalingPolicyCheck, t *sdk.Scal
 // isNomadAPMQuery helps identify whether the policy query is aligned with a
 // configured Nomad APM source.
 func (pr *Processor) isNomadAPMQuery(source string) bool {
+	pr.lock.RLock()
+	defer pr.lock.RUnlock()
+
 	for _, name := range pr.nomadAPMs {
 		if source == name {
 			return true
@@ -2,11 +2,17 @@ package policy
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad-autoscaler/plugins/apm"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/plugins/target"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,18 +24,20 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 	}{
 		{
 			inputPolicy: &sdk.ScalingPolicy{
-				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
-				Min: 1,
-				Max: 10,
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				LimitsOnly: true,
 			},
 			expectedOutput: nil,
 			name:           "valid input policy",
 		},
 		{
 			inputPolicy: &sdk.ScalingPolicy{
-				ID:  "",
-				Min: 1,
-				Max: 10,
+				ID:         "",
+				Min:        1,
+				Max:        10,
+				LimitsOnly: true,
 			},
 			expectedOutput: &multierror.Error{
 				Errors: []error{
@@ -40,9 +48,10 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 		},
 		{
 			inputPolicy: &sdk.ScalingPolicy{
-				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
-				Min: -1,
-				Max: 10,
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        -1,
+				Max:        10,
+				LimitsOnly: true,
 			},
 			expectedOutput: &multierror.Error{
 				Errors: []error{
@@ -53,9 +62,10 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 		},
 		{
 			inputPolicy: &sdk.ScalingPolicy{
-				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
-				Min: 100,
-				Max: 10,
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        100,
+				Max:        10,
+				LimitsOnly: true,
 			},
 			expectedOutput: &multierror.Error{
 				Errors: []error{
@@ -66,9 +76,10 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 		},
 		{
 			inputPolicy: &sdk.ScalingPolicy{
-				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
-				Min: 1,
-				Max: -10,
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        -10,
+				LimitsOnly: true,
 			},
 			expectedOutput: &multierror.Error{
 				Errors: []error{
@@ -78,6 +89,570 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 			},
 			name: "negative maximum value which is lower than minimum",
 		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				Priority:   101,
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy Priority must be between 1 and 100"),
+				},
+			},
+			name: "priority out of range",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				SoftMax:    11,
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy SoftMax must be between Min and Max"),
+				},
+			},
+			name: "soft max above maximum",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
+				Min: 1,
+				Max: 10,
+				LimitsSchedule: &sdk.ScalingPolicyLimitsSchedule{
+					Timezone: "Mars/Olympus_Mons",
+					Windows: []*sdk.ScalingPolicyLimitsWindow{
+						{Name: "night", Start: "22:00", End: "06:00", Min: ptr.Int64ToPtr(0)},
+					},
+				},
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`policy LimitsSchedule is invalid: invalid timezone "Mars/Olympus_Mons": unknown time zone Mars/Olympus_Mons`),
+				},
+			},
+			name: "limits schedule with invalid timezone",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "ce888afe-3dd2-144c-7227-74644434f708",
+				Min: 1,
+				Max: 10,
+				LimitsSchedule: &sdk.ScalingPolicyLimitsSchedule{
+					Windows: []*sdk.ScalingPolicyLimitsWindow{
+						{Name: "day", Start: "08:00", End: "18:00", Min: ptr.Int64ToPtr(12)},
+					},
+				},
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy LimitsSchedule window day Min must not be greater Max"),
+				},
+			},
+			name: "limits schedule window min above policy max",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				SoftMax:    -1,
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy SoftMax can't be negative"),
+				},
+			},
+			name: "negative soft max",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:            "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:           1,
+				Max:           10,
+				OnMetricError: "panic",
+				LimitsOnly:    true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`policy OnMetricError "panic" is not supported`),
+				},
+			},
+			name: "unsupported on metric error",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:            "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:           1,
+				Max:           10,
+				OnMetricError: sdk.MetricErrorScaleToSafe,
+				SafeCount:     11,
+				LimitsOnly:    true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy SafeCount must be between Min and Max"),
+				},
+			},
+			name: "safe count above maximum",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				SafeCount:  -1,
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy SafeCount can't be negative"),
+				},
+			},
+			name: "negative safe count",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:     "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:    1,
+				Max:    10,
+				Quorum: 2,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Query: "avg_cpu"},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy Quorum must not be greater than the number of checks"),
+				},
+			},
+			name: "quorum above number of checks",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:        1,
+				Max:        10,
+				Quorum:     -1,
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy Quorum can't be negative"),
+				},
+			},
+			name: "negative quorum",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:          "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:         1,
+				Max:         10,
+				ScaleInBias: -1,
+				LimitsOnly:  true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy ScaleInBias can't be negative"),
+				},
+			},
+			name: "negative scale in bias",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:              "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:             1,
+				Max:             10,
+				ScaleInBias:     1,
+				ScaleInBiasBand: 0.1,
+				LimitsOnly:      true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy ScaleInStabilizationWindow must be set when ScaleInBias is set"),
+				},
+			},
+			name: "scale in bias without stabilization window",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:                         "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:                        1,
+				Max:                        10,
+				ScaleInBias:                1,
+				ScaleInStabilizationWindow: 5 * time.Minute,
+				LimitsOnly:                 true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy ScaleInBiasBand must be set when ScaleInBias is set"),
+				},
+			},
+			name: "scale in bias without band",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:              "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:             1,
+				Max:             10,
+				ScaleInBiasBand: 1.5,
+				LimitsOnly:      true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy ScaleInBiasBand must be between 0 and 1"),
+				},
+			},
+			name: "scale in bias band out of range",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:              "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:             1,
+				Max:             10,
+				CooldownPerUnit: time.Minute,
+				LimitsOnly:      true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy MaxCooldown must be set when CooldownPerUnit is set"),
+				},
+			},
+			name: "cooldown per unit without max cooldown",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:                   "ce888afe-3dd2-144c-7227-74644434f708",
+				Min:                  1,
+				Max:                  10,
+				CooldownBypassFactor: 0.5,
+				LimitsOnly:           true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy CooldownBypassFactor must be greater than 1"),
+				},
+			},
+			name: "cooldown bypass factor too small",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:               "3e9b7c1a-6f2d-4a8e-b5c0-d4f1a7e2c936",
+				Min:              1,
+				Max:              10,
+				HysteresisFactor: 1,
+				LimitsOnly:       true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy HysteresisFactor must be greater than 1"),
+				},
+			},
+			name: "hysteresis factor too small",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:                  "3e9b7c1a-6f2d-4a8e-b5c0-d4f1a7e2c936",
+				Min:                 1,
+				Max:                 10,
+				MinChangeCount:      -1,
+				MinChangePercentage: 101,
+				LimitsOnly:          true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy MinChangeCount can't be negative"),
+					errors.New("policy MinChangePercentage must be between 0 and 100"),
+				},
+			},
+			name: "invalid min change",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:           "c7a4e1d9-3b5f-4e2a-9d8c-6f1b0a3e5d27",
+				Min:          1,
+				Max:          10,
+				DryRunSample: 1.1,
+				LimitsOnly:   true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy DryRunSample must be between 0 and 1"),
+				},
+			},
+			name: "invalid dry-run sample",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:            "7b2e9c4f-1a6d-4f3e-8c5b-0d9a2e7f1c48",
+				Min:           1,
+				Max:           10,
+				RampIntervals: -1,
+				LimitsOnly:    true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy RampIntervals can't be negative"),
+				},
+			},
+			name: "negative ramp intervals",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "5d0f3b8a-6c2e-4a71-9e4d-2b7c1f8a9e06",
+				Min: 1,
+				Max: 10,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy must have at least one check, or set LimitsOnly to only enforce Min and Max"),
+				},
+			},
+			name: "policy without checks",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "5d0f3b8a-6c2e-4a71-9e4d-2b7c1f8a9e06",
+				Min:        1,
+				Max:        10,
+				LimitsOnly: true,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "check", Query: "query"},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy LimitsOnly can't be set on a policy with checks"),
+				},
+			},
+			name: "limits-only policy with checks",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:                 "c4d3f1e2-0d7d-4f4e-9d6c-7b6a2c1f0e9d",
+				Min:                1,
+				Max:                10,
+				StartupGracePeriod: -time.Minute,
+				LimitsOnly:         true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy StartupGracePeriod can't be negative"),
+				},
+			},
+			name: "negative startup grace period",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:          "c4d3f1e2-0d7d-4f4e-9d6c-7b6a2c1f0e9d",
+				Min:         1,
+				Max:         10,
+				DedupWindow: -time.Minute,
+				LimitsOnly:  true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("policy DedupWindow can't be negative"),
+				},
+			},
+			name: "negative dedup window",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "c4d3f1e2-0d7d-4f4e-9d6c-7b6a2c1f0e9d",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "check", Query: "avg_cpu", PerInstance: true, MetricWindow: 3},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check check MetricWindow is not supported for per-instance checks"),
+				},
+			},
+			name: "metric window on per-instance check",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "e1f4a7b2-6c3d-4e8f-a9b0-2d5c8e1f7a36",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "check", Query: "avg_cpu", PerInstance: true, SourceConfig: map[string]string{"address": "http://prometheus:9090"}},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check check SourceConfig is not supported for per-instance checks"),
+				},
+			},
+			name: "source config on per-instance check",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "9f3b6d2e-8a1c-4e7f-b5d4-3c2a1e0f9d87",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "check", Query: "avg_cpu", MaxMetricAge: -time.Minute},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check check MaxMetricAge can't be negative"),
+				},
+			},
+			name: "negative max metric age",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "7c2e9a4f-1b6d-4f3e-8a5c-0d9b2e6f4a13",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "unsupported", Query: "cpu", MetricSmoothing: "median"},
+					{Name: "alpha", Query: "cpu", MetricSmoothing: sdk.MetricSmoothingEWMA, MetricSmoothingAlpha: 1.5},
+					{Name: "window", Query: "cpu", MetricSmoothing: sdk.MetricSmoothingEWMA, MetricWindow: 3},
+					{Name: "per-instance", Query: "cpu", MetricSmoothing: sdk.MetricSmoothingEWMA, PerInstance: true},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`check unsupported MetricSmoothing "median" is not supported`),
+					errors.New("check alpha MetricSmoothingAlpha must be between 0 and 1"),
+					errors.New("check window MetricSmoothing can't be used with a MetricWindow"),
+					errors.New("check per-instance MetricSmoothing is not supported for per-instance checks"),
+				},
+			},
+			name: "invalid metric smoothing",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "5a8e2f1c-9d4b-4c7e-b3a6-1e0d7f2c8b94",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "empty", Query: "cpu", SeriesQueries: []string{"cpu_b", " "}, SeriesAggregation: sdk.MetricAggregationSum},
+					{Name: "per-instance", Query: "cpu", PerInstance: true, SeriesQueries: []string{"cpu_b"}},
+					{Name: "unsupported", Query: "cpu", SeriesQueries: []string{"cpu_b"}, SeriesAggregation: sdk.MetricAggregationP95},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check empty SeriesQueries can't contain empty queries"),
+					errors.New("check per-instance SeriesQueries is not supported for per-instance checks"),
+					errors.New(`check unsupported SeriesAggregation "p95" is not supported`),
+				},
+			},
+			name: "invalid series queries",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "e41c7b2a-6f0d-4a39-8c5e-2b9d1f7a3c60",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "valid", Query: "rps", FormulaQueries: map[string]string{"cpu": "cpu"}, Formula: "(query / 100) + (cpu / 0.7)"},
+					{Name: "missing", Query: "rps", FormulaQueries: map[string]string{"cpu": "cpu"}},
+					{Name: "names", Query: "rps", FormulaQueries: map[string]string{"1cpu": "cpu", "query": "rps", "mem": " "}, Formula: "query"},
+					{Name: "undeclared", Query: "rps", FormulaQueries: map[string]string{"cpu": "cpu"}, Formula: "query + mem"},
+					{Name: "function", Query: "rps", Formula: "max(query, 1)"},
+					{Name: "per-instance", Query: "rps", PerInstance: true, Formula: "query / 2"},
+					{Name: "series", Query: "rps", SeriesQueries: []string{"rps_b"}, Formula: "query / 2"},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check missing FormulaQueries requires a Formula"),
+					errors.New(`check names FormulaQueries name "1cpu" must only contain letters, digits and underscores, not start with a digit and not be "query"`),
+					errors.New("check names FormulaQueries query mem can't be empty"),
+					errors.New(`check names FormulaQueries name "query" must only contain letters, digits and underscores, not start with a digit and not be "query"`),
+					errors.New(`check undeclared Formula is invalid: formula references undeclared metric "mem"`),
+					errors.New(`check function Formula is invalid: formula function "max" is not supported`),
+					errors.New("check per-instance Formula is not supported for per-instance checks"),
+					errors.New("check series Formula can't be used with SeriesQueries"),
+				},
+			},
+			name: "invalid formula",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "8d2f6a4e-1b7c-4e3d-9a5f-c6b0e8d2f4a1",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "valid", Query: "cpu", Transforms: []string{"clamp(0, 100)", "log", "scale(1.5)"}},
+					{Name: "unknown", Query: "cpu", Transforms: []string{"sqrt"}},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`check unknown Transforms is invalid: transform "sqrt" is not supported`),
+				},
+			},
+			name: "invalid transforms",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "2f8d4b6a-9c1e-4a7d-b3f5-6e0c8a2d4f17",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "valid", Query: "cpu", Preconditions: []string{"ready", "healthy", "meta.deployment == successful"}},
+					{Name: "unknown", Query: "cpu", Preconditions: []string{"stable"}},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`check unknown Preconditions is invalid: precondition "stable" is not supported`),
+				},
+			},
+			name: "invalid preconditions",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:         "0b6c1e2d-5f3a-4d8e-a1c9-2e7f4b3d6a81",
+				Min:        1,
+				Max:        10,
+				Tags:       map[string]string{"team": "infra", "1st": "a", "cost-center": "b", "policy_id": "c"},
+				LimitsOnly: true,
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New(`policy Tags name "1st" must only contain letters, digits and underscores and not start with a digit`),
+					errors.New(`policy Tags name "cost-center" must only contain letters, digits and underscores and not start with a digit`),
+					errors.New(`policy Tags name "policy_id" is reserved`),
+				},
+			},
+			name: "invalid tag names",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:  "7e2d9c4a-3b1f-4a6e-8c5d-0f9b2a7e4c13",
+				Min: 1,
+				Max: 10,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "empty", Query: ""},
+					{Name: "whitespace", Query: " \t\n"},
+				},
+			},
+			expectedOutput: &multierror.Error{
+				Errors: []error{
+					errors.New("check empty Query can't be empty"),
+					errors.New("check whitespace Query can't be empty"),
+				},
+			},
+			name: "empty and whitespace-only queries",
+		},
 	}
 
 	pr := Processor{}
@@ -90,6 +665,243 @@ func TestProcessor_ValidatePolicy(t *testing.T) {
 	}
 }
 
+type testTargetValidator struct{}
+
+func (testTargetValidator) ValidateTargetConfig(config map[string]string) error {
+	if config["Job"] == "system" {
+		return fmt.Errorf("system jobs can't be scaled")
+	}
+	return nil
+}
+
+func TestProcessor_ValidateTargetConfig(t *testing.T) {
+	pr := NewProcessor(&ConfigDefaults{
+		TargetSchemas: map[string]*sdk.TargetConfigSchema{
+			"nomad-target": {
+				Keys: map[string]*sdk.TargetConfigKey{
+					"Job":   {Required: true},
+					"Group": {Required: true},
+				},
+			},
+		},
+		TargetValidators: map[string]target.ConfigValidator{"nomad-target": testTargetValidator{}},
+	}, nil)
+
+	testCases := []struct {
+		inputPolicy   *sdk.ScalingPolicy
+		expectedError bool
+		name          string
+	}{
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Target: &sdk.ScalingPolicyTarget{
+					Name:   "nomad-target",
+					Config: map[string]string{"Job": "example", "Group": "cache"},
+				},
+			},
+			expectedError: false,
+			name:          "valid target config",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Target: &sdk.ScalingPolicyTarget{
+					Name:   "nomad-target",
+					Config: map[string]string{"Job": "example", "Grop": "cache"},
+				},
+			},
+			expectedError: true,
+			name:          "invalid target config",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Target: &sdk.ScalingPolicyTarget{
+					Name:   "nomad-target",
+					Config: map[string]string{"Job": "system", "Group": "cache"},
+				},
+			},
+			expectedError: true,
+			name:          "target rejected by validator",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Target: &sdk.ScalingPolicyTarget{
+					Name:   "aws-asg",
+					Config: map[string]string{"anything": "goes"},
+				},
+			},
+			expectedError: false,
+			name:          "target without schema",
+		},
+		{
+			inputPolicy:   &sdk.ScalingPolicy{},
+			expectedError: false,
+			name:          "nil target",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pr.ValidateTargetConfig(tc.inputPolicy)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+		})
+	}
+}
+
+type testQueryValidator struct{}
+
+func (testQueryValidator) ValidateQuery(query string) error {
+	if !strings.HasPrefix(query, "valid") {
+		return fmt.Errorf("query must start with valid")
+	}
+	return nil
+}
+
+type testConfigOverrider struct{}
+
+func (testConfigOverrider) ValidateConfigOverride(config map[string]string) error {
+	for k := range config {
+		if k != "address" {
+			return fmt.Errorf("%q config value can't be overridden", k)
+		}
+	}
+	return nil
+}
+
+type testStrategyValidator struct{}
+
+func (testStrategyValidator) ValidateStrategyConfig(config map[string]string) error {
+	if config["steps"] == "" {
+		return fmt.Errorf("missing required field `steps`")
+	}
+	return nil
+}
+
+func TestProcessor_ValidateCheckQueries(t *testing.T) {
+	pr := NewProcessor(&ConfigDefaults{
+		QueryValidators:    map[string]apm.QueryValidator{"validating-apm": testQueryValidator{}},
+		ConfigOverriders:   map[string]apm.ConfigOverrider{"validating-apm": testConfigOverrider{}},
+		StrategyValidators: map[string]strategy.ConfigValidator{"validating-strategy": testStrategyValidator{}},
+	}, nil)
+
+	testCases := []struct {
+		inputPolicy   *sdk.ScalingPolicy
+		expectedError error
+		name          string
+	}{
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "valid_cpu"},
+				},
+			},
+			expectedError: nil,
+			name:          "valid query",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "cpu"},
+					{Name: "memory", Source: "other-apm", Query: "memory"},
+				},
+			},
+			expectedError: &multierror.Error{
+				Errors: []error{
+					errors.New("check cpu Query is invalid: query must start with valid"),
+				},
+			},
+			name: "invalid query",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "valid_cpu", SeriesQueries: []string{"valid_cpu_b", "cpu_c"}},
+				},
+			},
+			expectedError: &multierror.Error{
+				Errors: []error{
+					errors.New(`check cpu SeriesQueries query "cpu_c" is invalid: query must start with valid`),
+				},
+			},
+			name: "invalid series query",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "valid_rps", FormulaQueries: map[string]string{"cpu": "valid_cpu", "mem": "mem"}, Formula: "query + cpu + mem"},
+				},
+			},
+			expectedError: &multierror.Error{
+				Errors: []error{
+					errors.New("check cpu FormulaQueries query mem is invalid: query must start with valid"),
+				},
+			},
+			name: "invalid formula query",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "memory", Source: "other-apm", Query: "memory"},
+				},
+			},
+			expectedError: nil,
+			name:          "source without validator",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "valid_cpu", SourceConfig: map[string]string{"address": "http://prometheus:9090"}},
+				},
+			},
+			expectedError: nil,
+			name:          "valid source config",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "cpu", Source: "validating-apm", Query: "valid_cpu", SourceConfig: map[string]string{"timeout": "1m"}},
+					{Name: "memory", Source: "other-apm", Query: "memory", SourceConfig: map[string]string{"address": "http://prometheus:9090"}},
+				},
+			},
+			expectedError: &multierror.Error{
+				Errors: []error{
+					errors.New(`check cpu SourceConfig is invalid: "timeout" config value can't be overridden`),
+					errors.New("check memory SourceConfig is not supported by source other-apm"),
+				},
+			},
+			name: "invalid source config",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Checks: []*sdk.ScalingPolicyCheck{
+					{
+						Name:     "cpu",
+						Source:   "other-apm",
+						Query:    "cpu",
+						Strategy: &sdk.ScalingPolicyStrategy{Name: "validating-strategy", Config: map[string]string{"steps": "..0:-1, 0..:+1"}},
+						Chain: []*sdk.ScalingPolicyStrategy{
+							{Name: "other-strategy", Config: map[string]string{}},
+							{Name: "validating-strategy", Config: map[string]string{}},
+						},
+					},
+				},
+			},
+			expectedError: &multierror.Error{
+				Errors: []error{
+					errors.New("check cpu strategy validating-strategy config is invalid: missing required field `steps`"),
+				},
+			},
+			name: "invalid strategy config",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pr.ValidateCheckQueries(tc.inputPolicy)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+		})
+	}
+}
+
 func TestProcessor_CanonicalizeAPMQuery(t *testing.T) {
 	testCases := []struct {
 		inputCheck          *sdk.ScalingPolicyCheck
@@ -238,6 +1050,7 @@ func TestProcessor_ApplyPolicyDefaults(t *testing.T) {
 				DefaultCooldown:           10 * time.Second,
 			},
 			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				Cooldown:           20 * time.Second,
 				EvaluationInterval: 5 * time.Second,
 			},
@@ -252,6 +1065,7 @@ func TestProcessor_ApplyPolicyDefaults(t *testing.T) {
 				DefaultCooldown:           11 * time.Second,
 			},
 			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				Cooldown:           11 * time.Second,
 				EvaluationInterval: 15 * time.Second,
 			},
@@ -264,6 +1078,7 @@ func TestProcessor_ApplyPolicyDefaults(t *testing.T) {
 				DefaultCooldown:           10 * time.Second,
 			},
 			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				Cooldown:           10 * time.Second,
 				EvaluationInterval: 5 * time.Second,
 			},
@@ -279,11 +1094,77 @@ func TestProcessor_ApplyPolicyDefaults(t *testing.T) {
 				DefaultCooldown:           10 * time.Second,
 			},
 			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
 				Cooldown:           10 * time.Minute,
 				EvaluationInterval: 5 * time.Minute,
 			},
 			name: "neither set to default",
 		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Priority:           90,
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+			},
+			inputDefaults: &ConfigDefaults{
+				DefaultEvaluationInterval: 5 * time.Second,
+				DefaultCooldown:           10 * time.Second,
+			},
+			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           90,
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+			},
+			name: "priority not set to default",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "default", SeriesQueries: []string{"b"}},
+					{Name: "set", SeriesQueries: []string{"b"}, SeriesAggregation: sdk.MetricAggregationSum},
+				},
+			},
+			inputDefaults: &ConfigDefaults{
+				DefaultEvaluationInterval: 5 * time.Second,
+				DefaultCooldown:           10 * time.Second,
+			},
+			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "default", QueryWindow: time.Minute, SeriesQueries: []string{"b"}, SeriesAggregation: sdk.MetricAggregationAvg},
+					{Name: "set", QueryWindow: time.Minute, SeriesQueries: []string{"b"}, SeriesAggregation: sdk.MetricAggregationSum},
+				},
+			},
+			name: "series aggregation set to default",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "default", MetricSmoothing: sdk.MetricSmoothingEWMA},
+					{Name: "set", MetricSmoothing: sdk.MetricSmoothingEWMA, MetricSmoothingAlpha: 0.2},
+				},
+			},
+			inputDefaults: &ConfigDefaults{
+				DefaultEvaluationInterval: 5 * time.Second,
+				DefaultCooldown:           10 * time.Second,
+			},
+			expectedOutputPolicy: &sdk.ScalingPolicy{
+				Priority:           sdk.ScalingPolicyPriorityDefault,
+				Cooldown:           10 * time.Minute,
+				EvaluationInterval: 5 * time.Minute,
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "default", QueryWindow: time.Minute, MetricSmoothing: sdk.MetricSmoothingEWMA, MetricSmoothingAlpha: sdk.MetricSmoothingAlphaDefault},
+					{Name: "set", QueryWindow: time.Minute, MetricSmoothing: sdk.MetricSmoothingEWMA, MetricSmoothingAlpha: 0.2},
+				},
+			},
+			name: "metric smoothing alpha set to default",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2,9 +2,13 @@ package policy
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad-autoscaler/plugins/apm"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/plugins/target"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 )
 
@@ -16,6 +20,36 @@ const DefaultQueryWindow = time.Minute
 type ConfigDefaults struct {
 	DefaultEvaluationInterval time.Duration
 	DefaultCooldown           time.Duration
+
+	// Templates are the named policy templates which policies can reference
+	// to inherit values they do not set.
+	Templates map[string]*sdk.ScalingPolicy
+
+	// TargetSchemas are the config schemas provided by target plugins, keyed
+	// by the plugin name. Policies using a target without a schema do not
+	// have their target config validated.
+	TargetSchemas map[string]*sdk.TargetConfigSchema
+
+	// TargetValidators are the target plugins which validate policy target
+	// configs, keyed by the plugin name. They are used once the config is
+	// validated against the schema.
+	TargetValidators map[string]target.ConfigValidator
+
+	// QueryValidators are the APM plugins which validate check queries, keyed
+	// by the plugin name. Checks using other APMs only have their queries
+	// validated once they are run.
+	QueryValidators map[string]apm.QueryValidator
+
+	// ConfigOverriders are the APM plugins which allow checks to override
+	// their config, keyed by the plugin name. Checks which override the
+	// config of other APMs are rejected. If nil, overrides are not
+	// validated.
+	ConfigOverriders map[string]apm.ConfigOverrider
+
+	// StrategyValidators are the strategy plugins which validate check
+	// strategy configs, keyed by the plugin name. Checks using other
+	// strategies only have their config validated once they are run.
+	StrategyValidators map[string]strategy.ConfigValidator
 }
 
 type MonitorIDsReq struct {
@@ -36,6 +70,11 @@ type Source interface {
 	MonitorIDs(ctx context.Context, monitorIDsReq MonitorIDsReq)
 	MonitorPolicy(ctx context.Context, monitorPolicyReq MonitorPolicyReq)
 
+	// ReadPolicy reads and parses the current version of the policy from the
+	// source immediately, rather than waiting for the monitor to detect a
+	// change.
+	ReadPolicy(ctx context.Context, ID PolicyID) (*sdk.ScalingPolicy, error)
+
 	// Name returns the SourceName for the implementation. This helps handlers
 	// identify the source implementation which is responsible for policies.
 	Name() SourceName
@@ -46,6 +85,14 @@ type Source interface {
 	ReloadIDsMonitor()
 }
 
+// LivenessSource is implemented by policy sources which watch for changes to
+// the list of policy IDs. They send a listing at least once per LivenessWindow,
+// even if it has not changed, so a watch which has silently stopped working can
+// be detected.
+type LivenessSource interface {
+	LivenessWindow() time.Duration
+}
+
 type PolicyID string
 
 // String satisfies the Stringer interface.
@@ -65,8 +112,18 @@ const (
 
 	// SourceNameFile is the source for policies that are loaded from disk.
 	SourceNameFile SourceName = "file"
+
+	// SourceNameGit is the source for policies that are loaded from a Git
+	// repository.
+	SourceNameGit SourceName = "git"
 )
 
+// NomadRegionSourceName returns the SourceName of the Nomad source which
+// watches the scaling policies of an additional Nomad region.
+func NomadRegionSourceName(region string) SourceName {
+	return SourceName(fmt.Sprintf("%s-%s", SourceNameNomad, region))
+}
+
 // HandleSourceError provides common functionality when a policy source
 // encounters an ephemeral or non-critical error.
 func HandleSourceError(name SourceName, err error, errCha chan<- error) {
@@ -0,0 +1,74 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// redactedValue replaces the values of strategy config keys which are likely
+// to contain credentials.
+const redactedValue = "<redacted>"
+
+// redactedKeys is the denylist of substrings which identify strategy config
+// keys whose values are redacted when the config is reported.
+var redactedKeys = []string{
+	"token",
+	"secret",
+	"password",
+	"passwd",
+	"credential",
+	"api_key",
+	"apikey",
+	"private_key",
+	"auth",
+}
+
+// StrategyConfig is the config passed to the Run function of a strategy.
+type StrategyConfig struct {
+	Name   string
+	Config map[string]string
+}
+
+// EffectiveStrategyConfigs returns the strategy configs of each check of the
+// policy, keyed by check name. The configs of a check are those of its
+// Strategy followed by those of its chained strategies, in the order they are
+// run. Values of keys on the denylist are redacted.
+func EffectiveStrategyConfigs(p *sdk.ScalingPolicy) map[string][]StrategyConfig {
+	configs := make(map[string][]StrategyConfig, len(p.Checks))
+
+	for _, c := range p.Checks {
+		var strategies []*sdk.ScalingPolicyStrategy
+		if c.Strategy != nil {
+			strategies = append(strategies, c.Strategy)
+		}
+		strategies = append(strategies, c.Chain...)
+
+		checkConfigs := make([]StrategyConfig, 0, len(strategies))
+		for _, s := range strategies {
+			checkConfigs = append(checkConfigs, StrategyConfig{
+				Name:   s.Name,
+				Config: RedactStrategyConfig(s.Config),
+			})
+		}
+		configs[c.Name] = checkConfigs
+	}
+	return configs
+}
+
+// RedactStrategyConfig returns a copy of the strategy config with the values
+// of keys on the denylist redacted. Keys are matched case insensitively.
+func RedactStrategyConfig(config map[string]string) map[string]string {
+	out := make(map[string]string, len(config))
+	for k, v := range config {
+		out[k] = v
+		lower := strings.ToLower(k)
+		for _, denied := range redactedKeys {
+			if strings.Contains(lower, denied) {
+				out[k] = redactedValue
+				break
+			}
+		}
+	}
+	return out
+}
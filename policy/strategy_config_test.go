@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveStrategyConfigs(t *testing.T) {
+	p := &sdk.ScalingPolicy{
+		Checks: []*sdk.ScalingPolicyCheck{
+			{
+				Name: "cpu",
+				Strategy: &sdk.ScalingPolicyStrategy{
+					Name:   "target-value",
+					Config: map[string]string{"target": "70", "threshold": "0.05"},
+				},
+				Chain: []*sdk.ScalingPolicyStrategy{
+					{Name: "webhook", Config: map[string]string{"url": "https://example.com", "API_Token": "s3cr3t"}},
+				},
+			},
+			{
+				Name: "no-strategy",
+			},
+		},
+	}
+
+	expected := map[string][]StrategyConfig{
+		"cpu": {
+			{Name: "target-value", Config: map[string]string{"target": "70", "threshold": "0.05"}},
+			{Name: "webhook", Config: map[string]string{"url": "https://example.com", "API_Token": redactedValue}},
+		},
+		"no-strategy": {},
+	}
+	assert.Equal(t, expected, EffectiveStrategyConfigs(p))
+
+	// The policy config is not modified by the redaction.
+	assert.Equal(t, "s3cr3t", p.Checks[0].Chain[0].Config["API_Token"])
+}
+
+func TestRedactStrategyConfig(t *testing.T) {
+	testCases := []struct {
+		inputConfig    map[string]string
+		expectedConfig map[string]string
+		name           string
+	}{
+		{
+			inputConfig:    nil,
+			expectedConfig: map[string]string{},
+			name:           "nil config",
+		},
+		{
+			inputConfig:    map[string]string{"target": "10", "max_scale_up": "2"},
+			expectedConfig: map[string]string{"target": "10", "max_scale_up": "2"},
+			name:           "no denied keys",
+		},
+		{
+			inputConfig: map[string]string{
+				"target":         "10",
+				"token":          "a",
+				"client_secret":  "b",
+				"Password":       "c",
+				"aws_credential": "d",
+				"apikey":         "e",
+				"basic_auth":     "f",
+			},
+			expectedConfig: map[string]string{
+				"target":         "10",
+				"token":          redactedValue,
+				"client_secret":  redactedValue,
+				"Password":       redactedValue,
+				"aws_credential": redactedValue,
+				"apikey":         redactedValue,
+				"basic_auth":     redactedValue,
+			},
+			name: "denied keys",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedConfig, RedactStrategyConfig(tc.inputConfig), tc.name)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/mitchellh/copystructure"
+)
+
+// ApplyPolicyTemplate merges the template referenced by the policy into the
+// policy. Values set on the policy take precedence over those of the template,
+// with zero values being treated as unset. Checks are merged by name and the
+// target config is merged by key. This should be called before the defaults
+// are applied and the policy is validated, so the merged result is validated.
+func (pr *Processor) ApplyPolicyTemplate(p *sdk.ScalingPolicy) error {
+	if p.Template == "" {
+		return nil
+	}
+
+	pr.lock.RLock()
+	t, ok := pr.defaults.Templates[p.Template]
+	pr.lock.RUnlock()
+
+	if !ok || t == nil {
+		return fmt.Errorf("policy template %q not found", p.Template)
+	}
+
+	// Copy the template so policies using it do not share checks and targets,
+	// which are modified when the policy is canonicalized.
+	c, err := copystructure.Copy(t)
+	if err != nil {
+		return fmt.Errorf("failed to copy policy template %q: %v", p.Template, err)
+	}
+	mergePolicyTemplate(p, c.(*sdk.ScalingPolicy))
+
+	return nil
+}
+
+// mergePolicyTemplate sets the values of the template on the policy where the
+// policy does not set them.
+func mergePolicyTemplate(p, t *sdk.ScalingPolicy) {
+	if p.Priority == 0 {
+		p.Priority = t.Priority
+	}
+	if p.Min == 0 {
+		p.Min = t.Min
+	}
+	if p.Max == 0 {
+		p.Max = t.Max
+	}
+	if p.LimitsSchedule == nil {
+		p.LimitsSchedule = t.LimitsSchedule
+	}
+	if p.SoftMax == 0 {
+		p.SoftMax = t.SoftMax
+	}
+	if p.Cooldown == 0 {
+		p.Cooldown = t.Cooldown
+	}
+	if p.CooldownBypassFactor == 0 {
+		p.CooldownBypassFactor = t.CooldownBypassFactor
+	}
+	if p.ZeroCooldown == 0 {
+		p.ZeroCooldown = t.ZeroCooldown
+	}
+	if p.CooldownPerUnit == 0 {
+		p.CooldownPerUnit = t.CooldownPerUnit
+	}
+	if p.MaxCooldown == 0 {
+		p.MaxCooldown = t.MaxCooldown
+	}
+	if !p.CooldownOnCompletion {
+		p.CooldownOnCompletion = t.CooldownOnCompletion
+	}
+	if !p.SupersedeInFlight {
+		p.SupersedeInFlight = t.SupersedeInFlight
+	}
+	if p.ScaleInStabilizationWindow == 0 {
+		p.ScaleInStabilizationWindow = t.ScaleInStabilizationWindow
+	}
+	if p.StartupGracePeriod == 0 {
+		p.StartupGracePeriod = t.StartupGracePeriod
+	}
+	if p.DedupWindow == 0 {
+		p.DedupWindow = t.DedupWindow
+	}
+	if p.EvaluationInterval == 0 {
+		p.EvaluationInterval = t.EvaluationInterval
+	}
+	if p.OnMetricError == "" {
+		p.OnMetricError = t.OnMetricError
+	}
+	if p.SafeCount == 0 {
+		p.SafeCount = t.SafeCount
+	}
+	if p.Quorum == 0 {
+		p.Quorum = t.Quorum
+	}
+	if p.ScaleInBias == 0 {
+		p.ScaleInBias = t.ScaleInBias
+	}
+	if p.ScaleInBiasBand == 0 {
+		p.ScaleInBiasBand = t.ScaleInBiasBand
+	}
+	if p.HysteresisFactor == 0 {
+		p.HysteresisFactor = t.HysteresisFactor
+	}
+	if p.MinChangeCount == 0 {
+		p.MinChangeCount = t.MinChangeCount
+	}
+	if p.MinChangePercentage == 0 {
+		p.MinChangePercentage = t.MinChangePercentage
+	}
+	if p.RampIntervals == 0 {
+		p.RampIntervals = t.RampIntervals
+	}
+	if !p.RespectManualOverride {
+		p.RespectManualOverride = t.RespectManualOverride
+	}
+	if p.DryRunSample == 0 {
+		p.DryRunSample = t.DryRunSample
+	}
+	if !p.LimitsOnly {
+		p.LimitsOnly = t.LimitsOnly
+	}
+	if !p.AllowSharedTarget {
+		p.AllowSharedTarget = t.AllowSharedTarget
+	}
+	if p.Tags == nil && len(t.Tags) > 0 {
+		p.Tags = make(map[string]string, len(t.Tags))
+	}
+	for k, v := range t.Tags {
+		if _, ok := p.Tags[k]; !ok {
+			p.Tags[k] = v
+		}
+	}
+
+	// Checks defined on the policy replace the template check with the same
+	// name, the remaining template checks are added after the policy checks.
+	names := make(map[string]bool, len(p.Checks))
+	for _, c := range p.Checks {
+		names[c.Name] = true
+	}
+	for _, c := range t.Checks {
+		if !names[c.Name] {
+			p.Checks = append(p.Checks, c)
+		}
+	}
+
+	switch {
+	case t.Target == nil:
+	case p.Target == nil:
+		p.Target = t.Target
+	default:
+		if p.Target.Name == "" {
+			p.Target.Name = t.Target.Name
+		}
+		if p.Target.Config == nil && len(t.Target.Config) > 0 {
+			p.Target.Config = make(map[string]string, len(t.Target.Config))
+		}
+		for k, v := range t.Target.Config {
+			if _, ok := p.Target.Config[k]; !ok {
+				p.Target.Config[k] = v
+			}
+		}
+	}
+}
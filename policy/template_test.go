@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessor_ApplyPolicyTemplate(t *testing.T) {
+
+	newTemplate := func() *sdk.ScalingPolicy {
+		return &sdk.ScalingPolicy{
+			Min:                1,
+			Max:                10,
+			Cooldown:           5 * time.Minute,
+			EvaluationInterval: 10 * time.Second,
+			Tags:               map[string]string{"team": "web", "tier": "frontend"},
+			Checks: []*sdk.ScalingPolicyCheck{
+				{Name: "cpu", Query: "avg_cpu"},
+				{Name: "memory", Query: "avg_memory"},
+			},
+			Target: &sdk.ScalingPolicyTarget{
+				Name:   "nomad-target",
+				Config: map[string]string{"Job": "example", "Group": "cache"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		inputPolicy    *sdk.ScalingPolicy
+		expectedPolicy *sdk.ScalingPolicy
+		expectedError  error
+		name           string
+	}{
+		{
+			inputPolicy:    &sdk.ScalingPolicy{Max: 5},
+			expectedPolicy: &sdk.ScalingPolicy{Max: 5},
+			expectedError:  nil,
+			name:           "no template",
+		},
+		{
+			inputPolicy:    &sdk.ScalingPolicy{Max: 5, Template: "missing"},
+			expectedPolicy: &sdk.ScalingPolicy{Max: 5, Template: "missing"},
+			expectedError:  errors.New(`policy template "missing" not found`),
+			name:           "missing template",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{Template: "web"},
+			expectedPolicy: func() *sdk.ScalingPolicy {
+				p := newTemplate()
+				p.Template = "web"
+				return p
+			}(),
+			expectedError: nil,
+			name:          "all values from template",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				Max:      20,
+				Cooldown: time.Minute,
+				Template: "web",
+				Tags:     map[string]string{"team": "api"},
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "memory", Query: "max_memory"},
+				},
+				Target: &sdk.ScalingPolicyTarget{
+					Config: map[string]string{"Group": "api"},
+				},
+			},
+			expectedPolicy: &sdk.ScalingPolicy{
+				Min:                1,
+				Max:                20,
+				Cooldown:           time.Minute,
+				EvaluationInterval: 10 * time.Second,
+				Template:           "web",
+				Tags:               map[string]string{"team": "api", "tier": "frontend"},
+				Checks: []*sdk.ScalingPolicyCheck{
+					{Name: "memory", Query: "max_memory"},
+					{Name: "cpu", Query: "avg_cpu"},
+				},
+				Target: &sdk.ScalingPolicyTarget{
+					Name:   "nomad-target",
+					Config: map[string]string{"Job": "example", "Group": "api"},
+				},
+			},
+			expectedError: nil,
+			name:          "policy values take precedence",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := NewProcessor(&ConfigDefaults{
+				Templates: map[string]*sdk.ScalingPolicy{"web": newTemplate()},
+			}, nil)
+
+			err := pr.ApplyPolicyTemplate(tc.inputPolicy)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			assert.Equal(t, tc.expectedPolicy, tc.inputPolicy, tc.name)
+		})
+	}
+}
+
+func TestProcessor_ApplyPolicyTemplate_copy(t *testing.T) {
+	template := &sdk.ScalingPolicy{
+		Checks: []*sdk.ScalingPolicyCheck{{Name: "cpu"}},
+		Target: &sdk.ScalingPolicyTarget{Config: map[string]string{"Job": "example"}},
+	}
+	pr := NewProcessor(&ConfigDefaults{
+		Templates: map[string]*sdk.ScalingPolicy{"web": template},
+	}, nil)
+
+	p := &sdk.ScalingPolicy{Template: "web"}
+	assert.NoError(t, pr.ApplyPolicyTemplate(p))
+
+	// Modifying the merged policy must not modify the template.
+	p.Checks[0].QueryWindow = time.Minute
+	p.Target.Config["Group"] = "cache"
+	assert.Zero(t, template.Checks[0].QueryWindow)
+	assert.Len(t, template.Target.Config, 1)
+}
@@ -0,0 +1,143 @@
+package policyeval
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// ActionLimiter limits the number of scaling actions which can be in-flight
+// at once across all workers, so a metric spike across many policies does not
+// result in a massive scaling event. Actions waiting for a slot are granted
+// one in order of policy priority, and in order of arrival for policies with
+// the same priority. It must be shared by all workers.
+//
+// A nil ActionLimiter does not limit actions.
+type ActionLimiter struct {
+	lock     sync.Mutex
+	limit    int
+	inFlight int
+	waiting  actionWaiters
+
+	// seq orders waiters with the same priority by arrival.
+	seq uint64
+}
+
+// NewActionLimiter returns a new ActionLimiter which allows limit actions to
+// be in-flight at once. It returns nil if limit is zero, which disables the
+// limit.
+func NewActionLimiter(limit int) *ActionLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &ActionLimiter{limit: limit}
+}
+
+// Acquire blocks until a slot is available for an action of a policy with the
+// passed priority, or the context is canceled. The returned function must be
+// called to release the slot once the action is no longer in-flight.
+func (l *ActionLimiter) Acquire(ctx context.Context, priority int) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.lock.Lock()
+	if l.inFlight < l.limit && len(l.waiting) == 0 {
+		l.inFlight++
+		l.lock.Unlock()
+		return l.releaseFunc(), nil
+	}
+
+	w := &actionWaiter{priority: priority, seq: l.seq, ready: make(chan struct{})}
+	l.seq++
+	heap.Push(&l.waiting, w)
+	l.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// The slot may have been granted while the context was being canceled,
+	// in which case it must be passed on.
+	if w.index < 0 {
+		l.release()
+	} else {
+		heap.Remove(&l.waiting, w.index)
+	}
+	return nil, ctx.Err()
+}
+
+// releaseFunc returns a function which releases a slot once, no matter how
+// many times it is called.
+func (l *ActionLimiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			l.release()
+		})
+	}
+}
+
+// release hands the slot over to the next waiter, or frees it if there are
+// none. The lock must be held when calling it.
+func (l *ActionLimiter) release() {
+	if len(l.waiting) > 0 {
+		w := heap.Pop(&l.waiting).(*actionWaiter)
+		close(w.ready)
+		return
+	}
+	l.inFlight--
+}
+
+// actionWaiter is an action waiting for a slot.
+type actionWaiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+
+	// index is the position of the waiter in the heap, or -1 once it has
+	// been granted a slot.
+	index int
+}
+
+// actionWaiters implements heap.Interface, ordering waiters by descending
+// priority and then by arrival.
+type actionWaiters []*actionWaiter
+
+func (a actionWaiters) Len() int { return len(a) }
+
+func (a actionWaiters) Less(i, j int) bool {
+	if a[i].priority != a[j].priority {
+		return a[i].priority > a[j].priority
+	}
+	return a[i].seq < a[j].seq
+}
+
+func (a actionWaiters) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+	a[i].index = i
+	a[j].index = j
+}
+
+func (a *actionWaiters) Push(x interface{}) {
+	w := x.(*actionWaiter)
+	w.index = len(*a)
+	*a = append(*a, w)
+}
+
+func (a *actionWaiters) Pop() interface{} {
+	old := *a
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*a = old[:n-1]
+	return w
+}
@@ -0,0 +1,103 @@
+package policyeval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionLimiter_disabled(t *testing.T) {
+	l := NewActionLimiter(0)
+	assert.Nil(t, l)
+
+	// A nil limiter never blocks.
+	for i := 0; i < 10; i++ {
+		release, err := l.Acquire(context.Background(), 0)
+		require.NoError(t, err)
+		defer release()
+	}
+}
+
+func TestActionLimiter_Acquire(t *testing.T) {
+	l := NewActionLimiter(1)
+
+	release, err := l.Acquire(context.Background(), 0)
+	require.NoError(t, err)
+
+	// Queue waiters with different priorities while the slot is in use.
+	order := make(chan int, 3)
+	for i, priority := range []int{10, 50, 10} {
+		queued, priority := i+1, priority
+
+		go func() {
+			release, err := l.Acquire(context.Background(), priority)
+			if err != nil {
+				return
+			}
+			order <- priority
+			release()
+		}()
+
+		assert.Eventually(t, func() bool {
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			return len(l.waiting) == queued
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	// Releasing the slot, even multiple times, grants it to the waiters in
+	// order of priority.
+	release()
+	release()
+
+	for _, expected := range []int{50, 10, 10} {
+		select {
+		case p := <-order:
+			assert.Equal(t, expected, p)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for action slot")
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	assert.Equal(t, 0, l.inFlight)
+	assert.Empty(t, l.waiting)
+}
+
+func TestActionLimiter_Acquire_canceled(t *testing.T) {
+	l := NewActionLimiter(1)
+
+	release, err := l.Acquire(context.Background(), 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := l.Acquire(ctx, 0)
+		errCh <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		return len(l.waiting) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Canceled waiters leave the queue without taking a slot.
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+
+	release()
+	release, err = l.Acquire(context.Background(), 0)
+	require.NoError(t, err)
+	release()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	assert.Equal(t, 0, l.inFlight)
+	assert.Empty(t, l.waiting)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -16,11 +18,46 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/policy"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// errTargetNotReady is used by a check handler to indicate the policy target
-// is not ready.
-var errTargetNotReady = errors.New("target not ready")
+// The phases of a policy evaluation which are timed individually, so
+// operators can identify which part of an evaluation is slow.
+const (
+	evalPhaseTargetStatus = "target_status"
+	evalPhaseAPMQuery     = "apm_query"
+	evalPhaseStrategyRun  = "strategy_run"
+	evalPhaseTargetScale  = "target_scale"
+)
+
+// metricErrorSafeScaleThreshold is the number of consecutive evaluations in
+// which all checks of a policy must fail to fetch metrics before the target
+// is scaled to the policy's safe count, so a brief APM outage doesn't cause a
+// scaling action.
+const metricErrorSafeScaleThreshold = 3
+
+// The reasons reported for policy evaluations which don't result in a scaling
+// action, in addition to the sdk.NoActionReason values reported by checks.
+const (
+	noActionReasonAboveCeiling   = "above_ceiling"
+	noActionReasonManualOverride = "manual_override"
+	noActionReasonDesiredCount   = "desired_count_not_running"
+	noActionReasonWithinLimits   = "within_limits"
+	noActionReasonMetricLoss     = "metric_loss"
+	noActionReasonNoCheckResults = "no_check_results"
+	noActionReasonQuorum         = "quorum_not_met"
+	noActionReasonStartupGrace   = "startup_grace_period"
+	noActionReasonInFlight       = "in_flight"
+	noActionReasonHysteresis     = "hysteresis"
+	noActionReasonCooldown       = "cooldown"
+	noActionReasonStabilization  = "scale_in_stabilization"
+	noActionReasonMinChange      = "min_change"
+	noActionReasonDedup          = "dedup"
+	noActionReasonHealthyGuard   = "healthy_guard"
+	noActionReasonPaused         = "paused"
+	noActionReasonQuotaExhausted = "quota_exhausted"
+)
 
 // Worker is responsible for executing a policy evaluation request.
 type BaseWorker struct {
@@ -29,11 +66,48 @@ type BaseWorker struct {
 	pluginManager *manager.PluginManager
 	policyManager *policy.Manager
 	broker        *Broker
+	wal           *WAL
 	queue         string
+
+	// slowPhaseThreshold is the duration after which an evaluation phase is
+	// logged as slow. Zero disables the logging.
+	slowPhaseThreshold time.Duration
+
+	// metricWindows stores the recent query results of checks which smooth
+	// their metrics, and must be shared by all workers.
+	metricWindows *MetricWindows
+
+	// requireDesiredCount indicates policies are only evaluated once their
+	// target is running its desired count.
+	requireDesiredCount bool
+
+	// maxActionCount is the absolute ceiling for the count of a scaling
+	// action. Zero disables the check.
+	maxActionCount int64
+
+	// scaleInHealthyGuard indicates scale in actions are skipped while the
+	// healthy count of the target is at or below the new count.
+	scaleInHealthyGuard bool
+
+	// actionLimiter limits the number of scaling actions in-flight across
+	// all workers, and must be shared by all workers.
+	actionLimiter *ActionLimiter
+
+	// pause stops the execution of scaling actions while scaling is paused,
+	// and must be shared by all workers.
+	pause *Pause
+
+	// sample returns a random number in [0, 1) used to sample the scaling
+	// actions of policies which set a DryRunSample. It is replaced when
+	// testing.
+	sample func() float64
 }
 
-// NewBaseWorker returns a new BaseWorker instance.
-func NewBaseWorker(l hclog.Logger, pm *manager.PluginManager, m *policy.Manager, b *Broker, queue string) *BaseWorker {
+// NewBaseWorker returns a new BaseWorker instance. The WAL, action limiter and
+// pause are optional and can be nil.
+func NewBaseWorker(l hclog.Logger, pm *manager.PluginManager, m *policy.Manager, b *Broker, wal *WAL, queue string,
+	slowPhaseThreshold time.Duration, mw *MetricWindows, requireDesiredCount bool, maxActionCount int64,
+	scaleInHealthyGuard bool, al *ActionLimiter, pause *Pause) *BaseWorker {
 	id := uuid.Generate()
 
 	return &BaseWorker{
@@ -42,7 +116,17 @@ func NewBaseWorker(l hclog.Logger, pm *manager.PluginManager, m *policy.Manager,
 		pluginManager: pm,
 		policyManager: m,
 		broker:        b,
+		wal:           wal,
 		queue:         queue,
+
+		slowPhaseThreshold:  slowPhaseThreshold,
+		metricWindows:       mw,
+		requireDesiredCount: requireDesiredCount,
+		maxActionCount:      maxActionCount,
+		scaleInHealthyGuard: scaleInHealthyGuard,
+		actionLimiter:       al,
+		pause:               pause,
+		sample:              rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
 	}
 }
 
@@ -73,8 +157,27 @@ func (w *BaseWorker) Run(ctx context.Context) {
 			"eval_token", token,
 			"policy_id", eval.Policy.ID)
 
-		if err := w.handlePolicy(ctx, eval); err != nil {
-			logger.Error("failed to evaluate policy", "err", err)
+		evalCtx, span := tracer.Start(ctx, "policy_eval", trace.WithAttributes(
+			attribute.String("eval_id", eval.ID),
+			attribute.String("policy_id", eval.Policy.ID),
+			attribute.String("plugin_name", eval.Policy.Target.Name),
+		))
+		err = w.handlePolicy(evalCtx, eval)
+		endSpan(span, err)
+
+		if err != nil {
+			metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "error_count"}, 1, policyLabels(eval.Policy,
+				metrics.Label{Name: "policy_id", Value: eval.Policy.ID},
+				metrics.Label{Name: "error_type", Value: evalErrorType(err)},
+			))
+
+			// Targets are routinely not ready, such as during deployments,
+			// so this isn't reported as an error.
+			if errors.Is(err, ErrTargetNotReady) {
+				logger.Warn("target not ready, skipping policy evaluation")
+			} else {
+				logger.Error("failed to evaluate policy", "err", err)
+			}
 
 			// Notify broker that policy eval was not successful.
 			if err := w.broker.Nack(eval.ID, token); err != nil {
@@ -96,47 +199,155 @@ func (w *BaseWorker) handlePolicy(ctx context.Context, eval *sdk.ScalingEvaluati
 	// Record the start time of the eval portion of this function. The labels
 	// are also used across multiple metrics, so define them.
 	evalStartTime := time.Now()
-	labels := []metrics.Label{
-		{Name: "policy_id", Value: eval.Policy.ID},
-		{Name: "target_name", Value: eval.Policy.Target.Name},
-	}
-
-	logger := w.logger.With("policy_id", eval.Policy.ID, "target", eval.Policy.Target.Name)
+	labels := policyLabels(eval.Policy,
+		metrics.Label{Name: "policy_id", Value: eval.Policy.ID},
+		metrics.Label{Name: "target_name", Value: eval.Policy.Target.Name},
+	)
+
+	// Generate a correlation ID which is attached to all log lines, and the
+	// scaling action, for this evaluation. This allows operators to trace a
+	// single evaluation end-to-end.
+	correlationID := uuid.Generate()
+
+	logger := w.logger.With(
+		"policy_id", eval.Policy.ID,
+		"target", eval.Policy.Target.Name,
+		"correlation_id", correlationID,
+		"trigger", eval.Trigger)
 	logger.Debug("received policy for evaluation")
 
+	policyTagCardinality.observe(w.logger, eval.Policy.Tags)
+
 	// Dispense taget plugin.
 	targetPlugin, err := w.pluginManager.Dispense(eval.Policy.Target.Name, sdk.PluginTypeTarget)
 	if err != nil {
-		return fmt.Errorf(`target plugin "%s" not initialized: %v`, eval.Policy.Target.Name, err)
+		return newEvalError(ErrPluginDispense, `target plugin "%s" not initialized: %w`, eval.Policy.Target.Name, err)
 	}
 	targetInst, ok := targetPlugin.Plugin().(target.Target)
 	if !ok {
-		return fmt.Errorf(`"%s" is not a target plugin`, eval.Policy.Target.Name)
+		return newEvalError(ErrPluginDispense, `"%s" is not a target plugin`, eval.Policy.Target.Name)
 	}
 
 	// Fetch target status.
 	logger.Debug("fetching current count")
 
-	currentStatus, err := w.runTargetStatus(targetInst, eval.Policy)
+	currentStatus, err := w.runTargetStatus(ctx, logger, targetInst, eval.Policy)
 	if err != nil {
-		return fmt.Errorf("failed to fetch current count: %v", err)
+		return newEvalError(ErrTargetStatus, "failed to fetch current count: %w", err)
 	}
 	if !currentStatus.Ready {
-		return errTargetNotReady
+		return ErrTargetNotReady
+	}
+
+	// Detect changes to the target count made outside of the autoscaler, such
+	// as an operator setting the count manually. The count of a target
+	// completing an in-flight scaling action is still changing, so it is not
+	// compared.
+	manualOverride, overridden := int64(0), false
+	if eval.InFlight == nil {
+		count := observableCount(currentStatus)
+
+		if manualOverride, overridden = w.policyManager.ObserveCount(eval.Policy.ID, count); overridden {
+			logger.Info("detected target count changed outside of the autoscaler",
+				"from", manualOverride, "to", count)
+			metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "manual_override_count"}, 1, labels)
+
+			if currentStatus.Meta == nil {
+				currentStatus.Meta = make(map[string]string)
+			}
+			currentStatus.Meta[sdk.TargetStatusMetaKeyManualOverride] = strconv.FormatInt(manualOverride, 10)
+
+			// Policies which respect manual overrides don't scale the
+			// target for their cooldown, so the change is not reverted.
+			if eval.Policy.RespectManualOverride {
+				logger.Info("respecting manual override, enforcing cooldown",
+					"cooldown", eval.Policy.Cooldown)
+				w.policyManager.EnforceCooldown(eval.Policy.ID, eval.Policy.Cooldown)
+				reportNoAction(logger, labels, noActionReasonManualOverride)
+				return nil
+			}
+		}
+	}
+
+	// Track whether the target is above the policy's soft max, so operators
+	// can find the targets approaching their capacity limits.
+	if softMax := eval.Policy.SoftMax; softMax > 0 && currentStatus.Count > softMax {
+		if w.policyManager.SetSoftMaxExceeded(eval.Policy.ID, softMax, currentStatus.Count) {
+			logger.Warn("target count is above the policy soft max",
+				"count", currentStatus.Count, "soft_max", softMax)
+		}
+	} else {
+		w.policyManager.ResetSoftMax(eval.Policy.ID)
+	}
+
+	// Track whether the target was capped by a quota when it was last
+	// scaled, so the cap is reported along with the policy limits.
+	if capped, ok := currentStatus.QuotaCappedCount(); ok {
+		w.policyManager.SetQuotaCap(eval.Policy.ID, capped)
+	} else {
+		w.policyManager.ResetQuotaCap(eval.Policy.ID)
+	}
+
+	// Scaling based on the count while instances are failing can mask the
+	// problem, so report the gap between the desired and running counts.
+	if desired, running, ok := currentStatus.DesiredAndRunningCounts(); ok {
+		metrics.SetGaugeWithLabels([]string{"scale", "target", "count_gap"}, float32(desired-running), labels)
+
+		if running != desired {
+			logger.Debug("target is not running its desired count", "desired", desired, "running", running)
+
+			if w.requireDesiredCount {
+				logger.Info("skipping evaluation until target is running its desired count",
+					"desired", desired, "running", running)
+				reportNoAction(logger, labels, noActionReasonDesiredCount)
+				return nil
+			}
+		}
 	}
 
 	// Prepare handlers.
 	handlersCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// winningAction is the action to be executed after all checks' results are
-	// reconciled.
-	var winningAction *sdk.ScalingAction
-	var winningHandler *checkHandler
+	// results holds the action of each check which ran successfully, so they
+	// can be reconciled once all checks have finished.
+	var results []checkResult
+
+	// enabledChecks tracks the number of checks which were run, so we can
+	// detect policies where all checks are disabled.
+	var enabledChecks int
+
+	// metricErrorChecks tracks the number of checks which failed to fetch
+	// metrics, so we can detect policies where all metrics are unavailable.
+	var metricErrorChecks int
 
 	// Start check handlers.
 	for _, checkEval := range eval.CheckEvaluations {
-		checkHandler := newCheckHandler(logger, eval.Policy, checkEval, w.pluginManager)
+		if checkEval.Check.Disabled {
+			logger.Debug("skipping disabled check", "check", checkEval.Check.Name)
+			continue
+		}
+
+		// Checks whose preconditions are not met by the target status are
+		// skipped for this evaluation, as if they were disabled.
+		if len(checkEval.Check.Preconditions) > 0 {
+			unmet, err := unmetPrecondition(checkEval.Check, currentStatus)
+			if err != nil {
+				logger.Warn("failed to parse check preconditions, skipping check",
+					"check", checkEval.Check.Name, "error", err)
+				continue
+			}
+			if unmet != nil {
+				logger.Info("check precondition not met, skipping check",
+					"check", checkEval.Check.Name, "precondition", unmet.String())
+				metrics.IncrCounterWithLabels([]string{"scale", "check", "precondition_skipped_count"}, 1,
+					policyLabels(eval.Policy, metrics.Label{Name: "policy_id", Value: eval.Policy.ID}))
+				continue
+			}
+		}
+		enabledChecks++
+
+		checkHandler := newCheckHandler(logger, eval.Policy, checkEval, w.pluginManager, w.slowPhaseThreshold, w.metricWindows, w.maxActionCount)
 
 		// Wrap target status call in a goroutine so we can listen for ctx as well.
 		var action *sdk.ScalingAction
@@ -157,38 +368,304 @@ func (w *BaseWorker) handlePolicy(ctx context.Context, eval *sdk.ScalingEvaluati
 
 		if err != nil {
 			logger.Warn("failed to run check", "err", err)
+			if errors.Is(err, ErrAPMQuery) || errors.Is(err, ErrStaleMetrics) {
+				metricErrorChecks++
+			}
 			continue
 		}
 
-		winningAction = sdk.PreemptScalingAction(winningAction, action)
-		if winningAction == action {
-			winningHandler = checkHandler
-		}
+		results = append(results, checkResult{handler: checkHandler, action: action})
+	}
+
+	// winningAction is the action to be executed after all checks' results are
+	// reconciled.
+	winningHandler, winningAction := reconcileCheckResults(results)
+
+	// Scale in recommendations must be observed consistently for the policy's
+	// scale-in stabilization window, so any other recommendation restarts
+	// the window.
+	if winningAction == nil || winningAction.Direction != sdk.ScaleDirectionDown {
+		w.policyManager.ResetScaleIn(eval.Policy.ID)
 	}
 
 	// At this point the checks have finished. Therefore emit of metric data
 	// tracking how long it takes to run all the checks within a policy.
 	metrics.MeasureSinceWithLabels([]string{"scale", "evaluate_ms"}, evalStartTime, labels)
 
-	if winningHandler == nil || winningAction == nil || winningAction.Direction == sdk.ScaleDirectionNone {
-		logger.Debug("no checks need to be executed")
+	// Track the consecutive evaluations in which no check was able to fetch
+	// metrics, so policies can fall back to their safe count when the
+	// metrics are persistently unavailable.
+	metricLoss := enabledChecks > 0 && metricErrorChecks == enabledChecks
+	var metricLossEvals int
+	if metricLoss {
+		metricLossEvals = w.policyManager.RecordMetricError(eval.Policy.ID)
+		metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "metric_loss_count"}, 1, labels)
+	} else if len(results) > 0 {
+		w.policyManager.ResetMetricErrors(eval.Policy.ID)
+	}
+
+	// If the policy is limits-only, or all checks are disabled, there is no
+	// check result to reconcile, but the policy limits must still be
+	// enforced.
+	if enabledChecks == 0 {
+		if eval.Policy.LimitsOnly {
+			logger.Debug("policy is limits-only, enforcing policy limits")
+		} else {
+			logger.Debug("all checks are disabled, enforcing policy limits only")
+		}
+
+		limits := policy.EffectiveLimits(eval.Policy)
+		winningAction = minMaxAction(currentStatus.Count, limits.Min.Value, limits.Max.Value)
+		if winningAction == nil {
+			logger.Debug("nothing to do")
+			reportNoAction(logger, labels, noActionReasonWithinLimits)
+			return nil
+		}
+		winningAction.Canonicalize()
+	} else if metricLoss {
+		winningAction = safeCountAction(eval.Policy, currentStatus.Count, metricLossEvals)
+		if winningAction == nil {
+			logger.Debug("all checks failed to fetch metrics, holding current count",
+				"count", currentStatus.Count, "evaluations", metricLossEvals)
+			reportNoAction(logger, labels, noActionReasonMetricLoss)
+			return nil
+		}
+		logger.Warn("all checks failed to fetch metrics, scaling to safe count",
+			"count", winningAction.Count, "evaluations", metricLossEvals)
+	} else {
+		if winningHandler == nil || winningAction == nil || winningAction.Direction == sdk.ScaleDirectionNone {
+			logger.Debug("no checks need to be executed")
+			reason := noActionReasonNoCheckResults
+			if winningAction != nil {
+				reason = winningAction.NoActionReason()
+			}
+			reportNoAction(logger, labels, reason)
+			return nil
+		}
+
+		logger.Trace(fmt.Sprintf("check %s selected", winningHandler.checkEval.Check.Name),
+			"direction", winningAction.Direction, "count", winningAction.Count,
+			"priority", winningHandler.checkEval.Check.Priority)
+
+		// Record the metric the action is based on, so targets can report
+		// the context of the scaling decision.
+		if m := winningHandler.checkEval.Metrics; len(m) > 0 {
+			winningAction.SetMetricValue(m[len(m)-1].Value)
+		}
+
+		// Policies which require a quorum only scale once enough checks
+		// agree on the direction of the selected action.
+		if quorum := eval.Policy.Quorum; quorum > 0 {
+			votes, ballot := quorumVotes(results, winningAction.Direction)
+			if votes < quorum {
+				logger.Info("checks did not reach quorum, skipping scaling action",
+					"direction", winningAction.Direction, "votes", votes, "quorum", quorum,
+					"checks", ballot)
+				metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "quorum_not_met_count"}, 1, labels)
+				reportNoAction(logger, labels, noActionReasonQuorum)
+				return nil
+			}
+		}
+	}
+
+	// Scaling actions are suppressed during the policy's startup grace period
+	// to avoid acting on target status or metrics which are still warming.
+	if eval.InStartupGracePeriod {
+		logger.Info("policy is in startup grace period, suppressing scaling action",
+			"direction", winningAction.Direction, "count", winningAction.Count,
+			"reason", winningAction.Reason)
+		reportNoAction(logger, labels, noActionReasonStartupGrace)
+		return nil
+	}
+
+	// Policies which allow in-flight scaling actions to be superseded are
+	// evaluated while the target completes the action, but only an action in
+	// the opposite direction may run.
+	if eval.InFlight != nil {
+		if !winningAction.Supersedes(eval.InFlight) {
+			logger.Debug("scaling action is in-flight, skipping scaling action",
+				"in_flight_count", eval.InFlight.Count, "direction", winningAction.Direction,
+				"count", winningAction.Count)
+			reportNoAction(logger, labels, noActionReasonInFlight)
+			return nil
+		}
+
+		logger.Info("superseding in-flight scaling action",
+			"in_flight_count", eval.InFlight.Count, "count", winningAction.Count)
+		winningAction.SetSuperseded(eval.InFlight)
+	}
+
+	// Policies with a hysteresis factor require a larger deviation to reverse
+	// the direction of their last scaling action than to continue it. Actions
+	// which enforce the policy limits or the safe count are not held back.
+	if eval.Policy.HysteresisFactor > 0 && enabledChecks > 0 && !metricLoss {
+		last := w.policyManager.LastActionDirection(eval.Policy.ID)
+		if deviation, ok := hysteresisDeviation(eval.Policy, winningAction, last); !ok {
+			logger.Debug("deviation too small to reverse last scaling direction, skipping scaling action",
+				"direction", winningAction.Direction, "last_direction", last, "deviation", deviation,
+				"hysteresis_factor", eval.Policy.HysteresisFactor)
+			metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "hysteresis_count"}, 1, labels)
+			reportNoAction(logger, labels, noActionReasonHysteresis)
+			return nil
+		}
+	}
+
+	// Policies which allow the cooldown to be bypassed are evaluated during
+	// cooldown, but only a scale out with a large enough deviation may run.
+	if eval.InCooldown {
+		deviation, ok := cooldownBypassDeviation(eval.Policy, winningAction)
+		if !ok {
+			logger.Debug("policy is in cooldown, skipping scaling action",
+				"direction", winningAction.Direction, "count", winningAction.Count)
+			reportNoAction(logger, labels, noActionReasonCooldown)
+			return nil
+		}
+
+		logger.Warn("bypassing cooldown to scale out due to large metric deviation",
+			"deviation", deviation, "cooldown_bypass_factor", eval.Policy.CooldownBypassFactor,
+			"count", winningAction.Count)
+		winningAction.SetCooldownBypassed()
+	}
+
+	// Delay scaling in until the checks have recommended it for the whole
+	// scale-in stabilization window, using the most conservative count
+	// recommended during the window. Enforcing the policy limits when all
+	// checks are disabled is never delayed.
+	if window := eval.Policy.ScaleInStabilizationWindow; window > 0 && enabledChecks > 0 &&
+		winningAction.Direction == sdk.ScaleDirectionDown {
+
+		count, ok := w.policyManager.StabilizeScaleIn(eval.Policy.ID, window, winningAction.Count)
+		if !ok {
+			logger.Debug("waiting for scale-in stabilization window, skipping scaling action",
+				"count", winningAction.Count, "window", window)
+			reportNoAction(logger, labels, noActionReasonStabilization)
+			return nil
+		}
+		if count != winningAction.Count {
+			logger.Debug("using highest count recommended during scale-in stabilization window",
+				"recommended_count", winningAction.Count, "count", count)
+			winningAction.Count = count
+		}
+	}
+
+	// Suppress actions which change the count by less than the policy's
+	// minimum change, to avoid churn from trivial actions. Actions which
+	// enforce the policy limits or the safe count are not suppressed.
+	if enabledChecks > 0 && !metricLoss &&
+		winningAction.SuppressMinChange(currentStatus.Count, eval.Policy.MinChangeCount, eval.Policy.MinChangePercentage) {
+		logger.Info("change below minimum change threshold, suppressing scaling action",
+			"from", currentStatus.Count, "to", winningAction.Count, "reason", winningAction.Reason,
+			"min_change_count", eval.Policy.MinChangeCount,
+			"min_change_percentage", eval.Policy.MinChangePercentage)
+		metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "min_change_suppressed_count"}, 1, labels)
+		reportNoAction(logger, labels, noActionReasonMinChange)
 		return nil
 	}
 
-	logger.Trace(fmt.Sprintf("check %s selected", winningHandler.checkEval.Check.Name),
-		"direction", winningAction.Direction, "count", winningAction.Count)
+	// Suppress actions which repeat the last scaling action of the policy
+	// within its dedup window, even if the target count has since changed.
+	// Actions which enforce the policy limits or the safe count are not
+	// suppressed.
+	if enabledChecks > 0 && !metricLoss &&
+		w.policyManager.IsDuplicateAction(eval.Policy.ID, eval.Policy.DedupWindow, winningAction) {
+		logger.Info("identical scaling action executed within dedup window, suppressing scaling action",
+			"count", winningAction.Count, "reason", winningAction.Reason, "dedup_window", eval.Policy.DedupWindow)
+		metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "dedup_suppressed_count"}, 1, labels)
+		reportNoAction(logger, labels, noActionReasonDedup)
+		return nil
+	}
+
+	// Spread the action over the policy's ramp intervals, so each evaluation
+	// only moves the count part of the way towards the recommended count.
+	// Actions which enforce the policy limits or the safe count are not
+	// ramped.
+	if intervals := eval.Policy.RampIntervals; intervals > 1 && enabledChecks > 0 && !metricLoss &&
+		winningAction.Direction != sdk.ScaleDirectionNone {
+
+		count, step := w.policyManager.RampCount(eval.Policy.ID, intervals, currentStatus.Count, winningAction.Count)
+		if count != winningAction.Count {
+			logger.Info("ramping scaling action",
+				"from", currentStatus.Count, "to", count, "recommended_count", winningAction.Count,
+				"step", step, "ramp_intervals", intervals)
+			winningAction.Ramp(count, step, intervals)
+		}
+	}
+
+	// Scaling in while instances are unhealthy can worsen an ongoing
+	// incident, so don't reduce the count while the healthy count is already
+	// at or below it. Targets which don't report both counts are not guarded.
+	if w.scaleInHealthyGuard {
+		if _, healthy, ok := currentStatus.DesiredAndRunningCounts(); ok && winningAction.GuardScaleIn(healthy) {
+			logger.Info("healthy count is at or below scale in count, skipping scaling action",
+				"count", winningAction.Count, "healthy", healthy, "reason", winningAction.Reason, "meta", winningAction.Meta)
+			reportNoAction(logger, labels, noActionReasonHealthyGuard)
+			return nil
+		}
+	}
 
 	// Measure how long it takes to invoke the scaling actions. This helps
 	// understand the time taken to interact with the remote target and action
 	// the scaling action.
 	defer metrics.MeasureSinceWithLabels([]string{"scale", "invoke_ms"}, time.Now(), labels)
 
+	// Attach the correlation ID so target plugins and the events they
+	// create can be linked back to this evaluation, and the policy tags so
+	// they can be attributed like the policy telemetry.
+	winningAction.SetCorrelationID(correlationID)
+	winningAction.SetTags(eval.Policy.Tags)
+
+	// The soft max is advisory, so scaling out beyond it is allowed, but it
+	// is reported prominently as the target is approaching its capacity
+	// limits.
+	if winningAction.ExceedsSoftMax(eval.Policy.SoftMax) {
+		logger.Warn("scaling target above the policy soft max",
+			"from", currentStatus.Count, "to", winningAction.Count, "soft_max", eval.Policy.SoftMax)
+		metrics.IncrCounterWithLabels([]string{"scale", "soft_max", "exceeded_count"}, 1, labels)
+		winningAction.SetSoftMaxExceeded(eval.Policy.SoftMax)
+	}
+
+	// Record what triggered the evaluation so manual scaling actions can be
+	// audited.
+	winningAction.SetTrigger(eval.Trigger, eval.TriggeredBy)
+	winningAction.SetPolicyID(eval.Policy.ID)
+	if overridden {
+		winningAction.SetManualOverride(manualOverride)
+	}
+
+	// Calculate the cooldown to enforce after the scaling action before the
+	// count is modified for dry-run. Scaling to or from zero may use a
+	// different cooldown to avoid flapping around zero, and the cooldown may
+	// grow with the size of the change.
+	cooldown := eval.Policy.CooldownFor(currentStatus.Count, winningAction.Count)
+	if cooldown != eval.Policy.Cooldown {
+		logger.Debug("using computed cooldown for scaling action", "cooldown", cooldown)
+	}
+	winningAction.SetCooldown(cooldown)
+
+	// While scaling is paused policies are still evaluated, but the scaling
+	// action is only logged so operators can see what would have happened.
+	if w.pause.Paused() {
+		logger.Info("scaling is paused, skipping scaling action",
+			"from", currentStatus.Count, "to", winningAction.Count,
+			"reason", winningAction.Reason, "meta", winningAction.Meta)
+		metrics.IncrCounterWithLabels([]string{"scale", "paused", "skipped_count"}, 1, labels)
+		reportNoAction(logger, labels, noActionReasonPaused)
+		return nil
+	}
+
 	// If the policy is configured with dry-run:true then we set the
 	// action count to nil so its no-nop. This allows us to still
 	// submit the job, but not alter its state.
-	if val, ok := eval.Policy.Target.Config["dry-run"]; ok && val == "true" {
+	if val, ok := eval.Policy.Target.Config[sdk.TargetConfigKeyDryRun]; ok && val == "true" {
 		logger.Info("scaling dry-run is enabled, using no-op task group count")
 		winningAction.SetDryRun()
+	} else if sampleDryRun(winningAction, eval.Policy.DryRunSample, w.sample) {
+		logger.Info("scaling action sampled for dry-run, using no-op task group count",
+			"dry_run_sample", eval.Policy.DryRunSample)
+	}
+	if sample := winningAction.DryRunSample(); sample != "" {
+		metrics.IncrCounterWithLabels([]string{"scale", "dry_run_sample", "count"}, 1,
+			append(labels[:len(labels):len(labels)], metrics.Label{Name: "sample", Value: sample}))
 	}
 
 	if winningAction.Count == sdk.StrategyActionMetaValueDryRunCount {
@@ -210,151 +687,432 @@ func (w *BaseWorker) handlePolicy(ctx context.Context, eval *sdk.ScalingEvaluati
 	default:
 	}
 
+	// Wait for the number of in-flight scaling actions to be below the
+	// limit. Dry-run actions don't modify the target so are not limited.
+	releaseAction := func() {}
+	if winningAction.Count != sdk.StrategyActionMetaValueDryRunCount {
+		waitStart := time.Now()
+		release, err := w.actionLimiter.Acquire(ctx, eval.Policy.Priority)
+		if err != nil {
+			w.logger.Info("stopping worker")
+			return nil
+		}
+		releaseAction = release
+		defer release()
+		metrics.MeasureSinceWithLabels([]string{"scale", "concurrency", "wait_ms"}, waitStart,
+			policyLabels(eval.Policy, metrics.Label{Name: "policy_id", Value: eval.Policy.ID}))
+	}
+
+	// Record the action in the write-ahead log before scaling the target, so
+	// the agent can verify it if it stops while the action is in-flight.
+	// Dry-run actions don't modify the target so are not recorded.
+	var walID string
+	if winningAction.Count != sdk.StrategyActionMetaValueDryRunCount {
+		walID, err = w.wal.Begin(eval.Policy.ID, eval.Policy.Target, winningAction)
+		if err != nil {
+			return fmt.Errorf("failed to record scaling action: %v", err)
+		}
+	}
+
 	// Scale the target. If we receive an error add this onto the result so the
 	// handler understand what do to.
-	err = w.runTargetScale(targetInst, eval.Policy, *winningAction)
+	err = w.runTargetScale(ctx, logger, targetInst, eval.Policy, *winningAction)
+
+	// The target has responded, so the action is no longer in-flight whether
+	// or not it succeeded. The slot is released now rather than once the
+	// evaluation completes, since cooldowns don't involve the target.
+	releaseAction()
+	if walErr := w.wal.Complete(walID); walErr != nil {
+		logger.Warn("failed to complete scaling action in WAL", "error", walErr)
+	}
+
+	// A quota preventing the target from scaling is not a failure of the
+	// target, and the count is unchanged, so no cooldown is enforced and the
+	// policy is evaluated again once the quota allows.
+	if errors.Is(err, sdk.ErrQuotaExhausted) {
+		logger.Info("quota does not allow scaling target, skipping scaling action",
+			"from", currentStatus.Count, "to", winningAction.Count, "error", err)
+		reportNoAction(logger, labels, noActionReasonQuotaExhausted)
+		return nil
+	}
+
 	if err != nil {
+		// The target may have been partially scaled, so its count is
+		// unknown and must not be detected as a manual change.
+		w.policyManager.ResetObservedCount(eval.Policy.ID)
 		metrics.IncrCounter([]string{"scale", "invoke", "error_count"}, 1)
-		return fmt.Errorf("failed to scale target: %v", err)
+		return newEvalError(ErrTargetScale, "failed to scale target: %w", err)
 	} else {
 		logger.Info("successfully submitted scaling action to target",
 			"desired_count", winningAction.Count)
 		metrics.IncrCounter([]string{"scale", "invoke", "success_count"}, 1)
 	}
 
-	// Enforce the cooldown after a successful scaling event.
-	w.policyManager.EnforceCooldown(eval.Policy.ID, eval.Policy.Cooldown)
+	// Keep the action so operators can see why the target was scaled to its
+	// count. Dry-run actions don't change the count so are not kept.
+	if winningAction.Count != sdk.StrategyActionMetaValueDryRunCount {
+		w.recordScalingAction(eval.Policy.ID, winningAction)
+	}
+
+	// Enforce the cooldown after a successful scaling event. Policies can
+	// anchor the cooldown to the target reaching the new count rather than to
+	// the submission of the action. Dry-run actions never change the count,
+	// so their cooldown always starts immediately.
+	if eval.Policy.CooldownOnCompletion && winningAction.Count != sdk.StrategyActionMetaValueDryRunCount {
+		w.policyManager.EnforceCooldownOnCompletion(eval.Policy.ID, cooldown,
+			sdk.InFlightAction{Count: winningAction.Count, Direction: winningAction.Direction})
+	} else {
+		w.policyManager.EnforceCooldown(eval.Policy.ID, cooldown)
+	}
+	w.policyManager.ResetScaleIn(eval.Policy.ID)
 
 	logger.Info("policy evaluation complete")
 	return nil
 }
 
+// recordScalingAction keeps the scaling action executed on the target of the
+// policy identified by the passed ID.
+//
+// The target may not reach the count of the action, such as when it is capped
+// by a quota or clamped by the target, so the observed count is cleared rather
+// than set to the count of the action. The next evaluation then observes the
+// count reported by the target, which is not detected as a manual change.
+func (w *BaseWorker) recordScalingAction(policyID string, action *sdk.ScalingAction) {
+	w.policyManager.SetLastAction(policyID, action)
+	w.policyManager.ResetObservedCount(policyID)
+}
+
+// observableCount returns the count of the target status which is compared
+// between evaluations to detect changes made outside of the autoscaler. The
+// desired count is used when reported, as the running count changes while
+// instances are placed.
+func observableCount(status *sdk.TargetStatus) int64 {
+	if desired, _, ok := status.DesiredAndRunningCounts(); ok {
+		return desired
+	}
+	return status.Count
+}
+
 // runTargetStatus wraps the target.Status call to provide operational
 // functionality.
-func (w *BaseWorker) runTargetStatus(targetImpl target.Target, policy *sdk.ScalingPolicy) (*sdk.TargetStatus, error) {
+func (w *BaseWorker) runTargetStatus(ctx context.Context, logger hclog.Logger, targetImpl target.Target, policy *sdk.ScalingPolicy) (status *sdk.TargetStatus, err error) {
+	_, span := startPhaseSpan(ctx, evalPhaseTargetStatus, policy.Target.Name, policy.ID)
+	defer func() { endSpan(span, err) }()
+
 	// Trigger a metric measure to track latency of the call.
-	labels := []metrics.Label{{Name: "plugin_name", Value: policy.Target.Name}, {Name: "policy_id", Value: policy.ID}}
+	labels := policyLabels(policy, metrics.Label{Name: "plugin_name", Value: policy.Target.Name}, metrics.Label{Name: "policy_id", Value: policy.ID})
 	defer metrics.MeasureSinceWithLabels([]string{"plugin", "target", "status", "invoke_ms"}, time.Now(), labels)
+	defer measurePhase(logger, w.slowPhaseThreshold, evalPhaseTargetStatus, policy.Target.Name, policy, time.Now())
 
-	return targetImpl.Status(policy.Target.Config)
+	// The result is only read if the call returns in time, as the call keeps
+	// running after timing out.
+	var s *sdk.TargetStatus
+	err = callPlugin(w.pluginManager, policy.Target.Name, sdk.PluginTypeTarget, func() error {
+		var err error
+		s, err = targetImpl.Status(policy.Target.Config)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
 // runTargetScale wraps the target.Scale call to provide operational
 // functionality.
-func (w *BaseWorker) runTargetScale(targetImpl target.Target, policy *sdk.ScalingPolicy, action sdk.ScalingAction) error {
+func (w *BaseWorker) runTargetScale(ctx context.Context, logger hclog.Logger, targetImpl target.Target, policy *sdk.ScalingPolicy, action sdk.ScalingAction) (err error) {
+	_, span := startPhaseSpan(ctx, evalPhaseTargetScale, policy.Target.Name, policy.ID)
+	defer func() { endSpan(span, err) }()
+
 	// Trigger a metric measure to track latency of the call.
-	labels := []metrics.Label{{Name: "plugin_name", Value: policy.Target.Name}, {Name: "policy_id", Value: policy.ID}}
+	labels := policyLabels(policy, metrics.Label{Name: "plugin_name", Value: policy.Target.Name}, metrics.Label{Name: "policy_id", Value: policy.ID})
 	defer metrics.MeasureSinceWithLabels([]string{"plugin", "target", "scale", "invoke_ms"}, time.Now(), labels)
+	defer measurePhase(logger, w.slowPhaseThreshold, evalPhaseTargetScale, policy.Target.Name, policy, time.Now())
 
+	// Targets which support it stop scaling, such as while retrying failed
+	// requests, once the agent is shutting down.
+	if cs, ok := targetImpl.(target.ContextScaler); ok {
+		return cs.ScaleWithContext(ctx, action, policy.Target.Config)
+	}
 	return targetImpl.Scale(action, policy.Target.Config)
 }
 
+// callPlugin runs call, which calls the named plugin, and waits at most for
+// the plugin call timeout for it to return. The timeouts are reported to the
+// plugin manager, so plugins which consistently time out are restarted. It
+// must only be used for calls which can be abandoned, as the call keeps
+// running in the background after timing out.
+func callPlugin(pm *manager.PluginManager, name, pluginType string, call func() error) error {
+	timeout := pm.CallTimeout()
+	if timeout <= 0 {
+		return call()
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- call() }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errCh:
+		pm.ReportSuccess(name, pluginType)
+		return err
+	case <-timer.C:
+		metrics.IncrCounterWithLabels([]string{"plugin", "call", "timeout_count"}, 1, []metrics.Label{
+			{Name: "plugin_name", Value: name}, {Name: "plugin_type", Value: pluginType}})
+		pm.ReportTimeout(name, pluginType)
+		return fmt.Errorf("call to plugin %s timed out after %v", name, timeout)
+	}
+}
+
+// measurePhase emits the time taken by a phase of a policy evaluation, and
+// logs it if it exceeded the slow threshold.
+func measurePhase(logger hclog.Logger, slowThreshold time.Duration, phase, pluginName string, policy *sdk.ScalingPolicy, start time.Time) {
+	labels := policyLabels(policy,
+		metrics.Label{Name: "phase", Value: phase},
+		metrics.Label{Name: "plugin_name", Value: pluginName},
+		metrics.Label{Name: "policy_id", Value: policy.ID},
+	)
+	metrics.MeasureSinceWithLabels([]string{"scale", "evaluate", "phase_ms"}, start, labels)
+
+	if elapsed := time.Since(start); slowThreshold > 0 && elapsed > slowThreshold {
+		logger.Debug("slow policy evaluation phase",
+			"phase", phase, "plugin", pluginName, "duration", elapsed, "threshold", slowThreshold)
+	}
+}
+
 // checkHandler evaluates one of the checks of a policy.
 type checkHandler struct {
-	logger        hclog.Logger
-	policy        *sdk.ScalingPolicy
-	checkEval     *sdk.ScalingCheckEvaluation
-	pluginManager *manager.PluginManager
+	logger             hclog.Logger
+	policy             *sdk.ScalingPolicy
+	checkEval          *sdk.ScalingCheckEvaluation
+	pluginManager      *manager.PluginManager
+	slowPhaseThreshold time.Duration
+	metricWindows      *MetricWindows
+	maxActionCount     int64
+
+	// now returns the time the APM query range ends at. It is replaced when
+	// simulating evaluations over historical metrics.
+	now func() time.Time
 }
 
 // newCheckHandler returns a new checkHandler instance.
-func newCheckHandler(l hclog.Logger, p *sdk.ScalingPolicy, c *sdk.ScalingCheckEvaluation, pm *manager.PluginManager) *checkHandler {
+func newCheckHandler(l hclog.Logger, p *sdk.ScalingPolicy, c *sdk.ScalingCheckEvaluation, pm *manager.PluginManager,
+	slowPhaseThreshold time.Duration, mw *MetricWindows, maxActionCount int64) *checkHandler {
 	return &checkHandler{
 		logger: l.Named("check_handler").With(
 			"check", c.Check.Name,
 			"source", c.Check.Source,
 			"strategy", c.Check.Strategy.Name,
 		),
-		policy:        p,
-		checkEval:     c,
-		pluginManager: pm,
+		policy:             p,
+		checkEval:          c,
+		pluginManager:      pm,
+		slowPhaseThreshold: slowPhaseThreshold,
+		metricWindows:      mw,
+		maxActionCount:     maxActionCount,
+		now:                time.Now,
 	}
 }
 
 // start begins the execution of the check handler.
-func (h *checkHandler) start(ctx context.Context, currentStatus *sdk.TargetStatus) (*sdk.ScalingAction, error) {
+func (h *checkHandler) start(ctx context.Context, currentStatus *sdk.TargetStatus) (action *sdk.ScalingAction, err error) {
 	h.logger.Debug("received policy check for evaluation")
 
+	ctx, span := tracer.Start(ctx, "check", trace.WithAttributes(
+		attribute.String("check", h.checkEval.Check.Name),
+		attribute.String("policy_id", h.policy.ID),
+	))
+	defer func() { endSpan(span, err) }()
+
 	var apmInst apm.APM
-	var strategyInst strategy.Strategy
 
 	// Dispense plugins.
 	apmPlugin, err := h.pluginManager.Dispense(h.checkEval.Check.Source, sdk.PluginTypeAPM)
 	if err != nil {
-		return nil, fmt.Errorf(`apm plugin "%s" not initialized: %v`, h.checkEval.Check.Source, err)
+		return nil, newEvalError(ErrPluginDispense, `apm plugin "%s" not initialized: %w`, h.checkEval.Check.Source, err)
 	}
 	apmInst, ok := apmPlugin.Plugin().(apm.APM)
 	if !ok {
-		return nil, fmt.Errorf(`"%s" is not an APM plugin`, h.checkEval.Check.Source)
+		return nil, newEvalError(ErrPluginDispense, `"%s" is not an APM plugin`, h.checkEval.Check.Source)
+	}
+
+	// Strategy aliases run the strategy plugin they refer to using their
+	// preset config. The check is copied so the policy is not modified.
+	if s := h.pluginManager.ResolveStrategy(h.checkEval.Check.Strategy); s != h.checkEval.Check.Strategy {
+		h.logger.Trace("resolved strategy alias", "strategy_alias", h.checkEval.Check.Strategy.Name, "strategy", s.Name)
+		check := *h.checkEval.Check
+		check.Strategy = s
+		h.checkEval.Check = &check
 	}
 
-	strategyPlugin, err := h.pluginManager.Dispense(h.checkEval.Check.Strategy.Name, sdk.PluginTypeStrategy)
+	strategyInst, err := h.dispenseStrategy(h.checkEval.Check.Strategy.Name)
 	if err != nil {
-		return nil, fmt.Errorf(`strategy plugin "%s" not initialized: %v`, h.checkEval.Check.Strategy.Name, err)
+		return nil, err
 	}
-	strategyInst, ok = strategyPlugin.Plugin().(strategy.Strategy)
-	if !ok {
-		return nil, fmt.Errorf(`"%s" is not a strategy plugin`, h.checkEval.Check.Strategy.Name)
+
+	// Parse the check's formula, which combines the results of its queries,
+	// before running them.
+	var formula *policy.MetricFormula
+	if h.checkEval.Check.Formula != "" {
+		if formula, err = policy.ParseCheckFormula(h.checkEval.Check); err != nil {
+			return nil, newEvalError(ErrMetricFormula, "failed to parse formula: %w", err)
+		}
 	}
 
 	// Query check's APM.
-	// Wrap call in a goroutine so we can listen for ctx as well.
-	apmQueryDoneCh := make(chan interface{})
+	// Wrap call in a goroutine so we can listen for ctx as well. The result
+	// channel is buffered so the goroutine can exit if the query times out.
+	type apmQueryResult struct {
+		metrics sdk.TimestampedMetrics
+		labeled sdk.LabeledMetrics
+		series  map[string]sdk.TimestampedMetrics
+		err     error
+	}
+	apmQueryResultCh := make(chan apmQueryResult, 1)
 	go func() {
-		defer close(apmQueryDoneCh)
-		h.checkEval.Metrics, err = h.runAPMQuery(apmInst)
+		if h.checkEval.Check.PerInstance {
+			l, err := h.runLabeledAPMQuery(ctx, apmInst)
+			apmQueryResultCh <- apmQueryResult{labeled: l, err: err}
+			return
+		}
+		if len(h.checkEval.Check.SeriesQueries) > 0 {
+			m, err := h.runSeriesQueries(ctx, apmInst)
+			apmQueryResultCh <- apmQueryResult{metrics: m, err: err}
+			return
+		}
+		if formula != nil {
+			s, err := h.runFormulaQueries(ctx, apmInst)
+			apmQueryResultCh <- apmQueryResult{series: s, err: err}
+			return
+		}
+		m, err := h.runAPMQuery(ctx, apmInst, h.checkEval.Check.Query)
+		apmQueryResultCh <- apmQueryResult{metrics: m, err: err}
 	}()
 
+	// If the check has a query timeout, only this check is skipped when the
+	// query is too slow. Otherwise the plugin call timeout applies.
+	var queryTimeoutCh <-chan time.Time
+	queryTimeout := h.checkEval.Check.QueryTimeout
+	if queryTimeout <= 0 {
+		queryTimeout = h.pluginManager.CallTimeout()
+	}
+	if queryTimeout > 0 {
+		timer := time.NewTimer(queryTimeout)
+		defer timer.Stop()
+		queryTimeoutCh = timer.C
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, nil
-	case <-apmQueryDoneCh:
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to query source: %v", err)
+	case <-queryTimeoutCh:
+		metrics.IncrCounterWithLabels([]string{"plugin", "apm", "query", "timeout_count"}, 1,
+			policyLabels(h.policy, metrics.Label{Name: "plugin_name", Value: h.checkEval.Check.Source}, metrics.Label{Name: "policy_id", Value: h.policy.ID}))
+		h.pluginManager.ReportTimeout(h.checkEval.Check.Source, sdk.PluginTypeAPM)
+		return nil, newEvalError(ErrAPMQuery, "query to source timed out after %v", queryTimeout)
+	case res := <-apmQueryResultCh:
+		h.pluginManager.ReportSuccess(h.checkEval.Check.Source, sdk.PluginTypeAPM)
+		if res.err != nil {
+			return nil, newEvalError(ErrAPMQuery, "failed to query source: %w", res.err)
+		}
+		if res.series != nil {
+			if res.metrics, err = evaluateFormulaSeries(res.series, formula); err != nil {
+				return nil, newEvalError(ErrMetricFormula, "failed to evaluate formula: %w", err)
+			}
+		}
+		h.checkEval.Metrics = res.metrics
+		h.checkEval.LabeledMetrics = res.labeled
 	}
 
 	// Make sure metrics are sorted consistently.
 	sort.Sort(h.checkEval.Metrics)
+	for _, m := range h.checkEval.LabeledMetrics {
+		sort.Sort(m)
+	}
 
-	if len(h.checkEval.Metrics) == 0 {
+	if len(h.checkEval.Metrics) == 0 && len(h.checkEval.LabeledMetrics) == 0 {
 		h.logger.Warn("no metrics available")
-		return &sdk.ScalingAction{Direction: sdk.ScaleDirectionNone}, nil
+		return noAction(sdk.NoActionReasonNoMetrics), nil
+	}
+
+	// Skip the check if the APM returned stale metrics, such as the last
+	// known value during an outage.
+	if maxAge := h.checkEval.Check.MaxMetricAge; maxAge > 0 {
+		if err := h.checkMetricAge(maxAge); err != nil {
+			metrics.IncrCounterWithLabels([]string{"scale", "check", "stale_metrics_count"}, 1,
+				policyLabels(h.policy, metrics.Label{Name: "policy_id", Value: h.policy.ID}))
+			return nil, err
+		}
+	}
+
+	// Smooth the metric using the recent query results if the check has a
+	// metric window.
+	if h.checkEval.Check.MetricWindow > 1 && h.metricWindows != nil {
+		h.smoothMetrics()
+	}
+
+	// Smooth the metric using its moving average if the check has a metric
+	// smoothing.
+	if h.checkEval.Check.MetricSmoothing == sdk.MetricSmoothingEWMA && h.metricWindows != nil {
+		h.smoothMetricsEWMA()
+	}
+
+	// Transform the metrics before they are passed to the strategy.
+	if len(h.checkEval.Check.Transforms) > 0 {
+		if err := h.transformMetrics(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Calculate new count using check's Strategy.
 	h.logger.Debug("calculating new count", "count", currentStatus.Count)
-	runResp, err := h.runStrategyRun(strategyInst, currentStatus.Count)
+	runResp, err := h.runStrategyRun(ctx, strategyInst, h.checkEval, currentStatus.Count)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute strategy: %v", err)
+		return nil, newEvalError(ErrStrategyRun, "failed to execute strategy: %w", err)
 	}
 	h.checkEval = runResp
 
+	// Refine the new count using the strategies chained after the check's
+	// Strategy.
+	if len(h.checkEval.Check.Chain) > 0 {
+		if err := h.runStrategyChain(ctx, currentStatus.Count); err != nil {
+			return nil, fmt.Errorf("failed to execute strategy chain: %w", err)
+		}
+	}
+
+	// Reject absurd counts before they are capped to the policy max, since
+	// they are most likely caused by a bug rather than a scaling decision.
+	if h.maxActionCount > 0 && h.checkEval.Action.Direction != sdk.ScaleDirectionNone &&
+		h.checkEval.Action.Count > h.maxActionCount {
+		h.logger.Error("skipping scaling action with count above the absolute ceiling, this is likely a bug in the strategy or metric",
+			"count", h.checkEval.Action.Count, "ceiling", h.maxActionCount)
+		return noAction(noActionReasonAboveCeiling), nil
+	}
+
+	limits := policy.EffectiveLimits(h.policy)
+
+	// Resolve ambiguous results in favour of fewer instances if the policy
+	// has a scale in bias. Biased scale ins are still bounded by the policy
+	// min and delayed by its scale-in stabilization window.
+	if bias := h.policy.ScaleInBias; bias > 0 &&
+		h.checkEval.Action.BiasScaleIn(currentStatus.Count, bias, limits.Min.Value, h.policy.ScaleInBiasBand) {
+		h.logger.Debug("applied scale in bias",
+			"count", currentStatus.Count, "new_count", h.checkEval.Action.Count, "bias", bias,
+			"band", h.policy.ScaleInBiasBand)
+		metrics.IncrCounterWithLabels([]string{"scale", "check", "scale_in_bias_count"}, 1,
+			policyLabels(h.policy, metrics.Label{Name: "policy_id", Value: h.policy.ID}))
+	}
+
 	if h.checkEval.Action.Direction == sdk.ScaleDirectionNone {
 		// Make sure we are currently within [min, max] limits even if there's
 		// no action to execute
-		var minMaxAction *sdk.ScalingAction
-
-		if currentStatus.Count < h.policy.Min {
-			minMaxAction = &sdk.ScalingAction{
-				Count:     h.policy.Min,
-				Direction: sdk.ScaleDirectionUp,
-				Reason:    fmt.Sprintf("current count (%d) below limit (%d)", currentStatus.Count, h.policy.Min),
-			}
-		} else if currentStatus.Count > h.policy.Max {
-			minMaxAction = &sdk.ScalingAction{
-				Count:     h.policy.Max,
-				Direction: sdk.ScaleDirectionDown,
-				Reason:    fmt.Sprintf("current count (%d) above limit (%d)", currentStatus.Count, h.policy.Max),
-			}
-		}
-
-		if minMaxAction != nil {
-			h.checkEval.Action = minMaxAction
+		if action := minMaxAction(currentStatus.Count, limits.Min.Value, limits.Max.Value); action != nil {
+			h.checkEval.Action = action
 		} else {
 			h.logger.Debug("nothing to do")
-			return &sdk.ScalingAction{Direction: sdk.ScaleDirectionNone}, nil
+			return noAction(h.checkEval.Action.NoActionReason()), nil
 		}
 	}
 
@@ -362,43 +1120,552 @@ func (h *checkHandler) start(ctx context.Context, currentStatus *sdk.TargetStatu
 	h.checkEval.Action.Canonicalize()
 
 	// Make sure new count value is within [min, max] limits
-	h.checkEval.Action.CapCount(h.policy.Min, h.policy.Max)
+	proposedCount := h.checkEval.Action.Count
+	h.checkEval.Action.CapCount(limits.Min.Value, limits.Max.Value)
 
-	// Skip action if count doesn't change.
+	// Skip action if count doesn't change. If the proposed count was capped
+	// to the current count, the target is already at the policy min or max.
 	if currentStatus.Count == h.checkEval.Action.Count {
 		h.logger.Debug("nothing to do", "from", currentStatus.Count, "to", h.checkEval.Action.Count)
-		return &sdk.ScalingAction{Direction: sdk.ScaleDirectionNone}, nil
+		if proposedCount != h.checkEval.Action.Count {
+			return noAction(sdk.NoActionReasonAtLimit), nil
+		}
+		return noAction(sdk.NoActionReasonNoChange), nil
 	}
 
 	return h.checkEval.Action, nil
 }
 
+// dispenseStrategy returns the strategy plugin with the passed name.
+func (h *checkHandler) dispenseStrategy(name string) (strategy.Strategy, error) {
+	strategyPlugin, err := h.pluginManager.Dispense(name, sdk.PluginTypeStrategy)
+	if err != nil {
+		return nil, newEvalError(ErrPluginDispense, `strategy plugin "%s" not initialized: %w`, name, err)
+	}
+	strategyInst, ok := strategyPlugin.Plugin().(strategy.Strategy)
+	if !ok {
+		return nil, newEvalError(ErrPluginDispense, `"%s" is not a strategy plugin`, name)
+	}
+	return strategyInst, nil
+}
+
+// runStrategyChain runs the strategies chained after the check's Strategy in
+// order, with each strategy receiving the count proposed by the previous one.
+// The final action replaces the check action. Its direction is relative to
+// the current count, and its reason history includes the reasons of every
+// strategy in the chain.
+func (h *checkHandler) runStrategyChain(ctx context.Context, count int64) error {
+	action := h.checkEval.Action
+
+	for _, s := range h.checkEval.Check.Chain {
+		// A strategy which doesn't propose a change keeps the current count.
+		proposed := count
+		if action.Direction != sdk.ScaleDirectionNone {
+			proposed = action.Count
+		}
+
+		// Strategy aliases run the strategy plugin they refer to using their
+		// preset config.
+		resolved := h.pluginManager.ResolveStrategy(s)
+
+		strategyInst, err := h.dispenseStrategy(resolved.Name)
+		if err != nil {
+			return err
+		}
+
+		// Strategies read their config from the check, so each strategy in
+		// the chain receives a copy of the check with its own config.
+		check := *h.checkEval.Check
+		check.Strategy = resolved
+		eval := &sdk.ScalingCheckEvaluation{
+			Check:          &check,
+			PolicyID:       h.checkEval.PolicyID,
+			Metrics:        h.checkEval.Metrics,
+			LabeledMetrics: h.checkEval.LabeledMetrics,
+			Action:         &sdk.ScalingAction{},
+		}
+		eval.Action.Canonicalize()
+
+		h.logger.Debug("running chained strategy", "chained_strategy", s.Name, "count", proposed)
+		runResp, err := h.runStrategyRun(ctx, strategyInst, eval, proposed)
+		if err != nil {
+			return newEvalError(ErrStrategyRun, `strategy "%s": %w`, s.Name, err)
+		}
+
+		next := runResp.Action
+		if next.Direction == sdk.ScaleDirectionNone {
+			next.Count = proposed
+		}
+		next.MergeReasonHistory(action)
+
+		// Keep the meta set by previous strategies, such as the metric
+		// deviation, unless the strategy overrides it.
+		for k, v := range action.Meta {
+			if _, ok := next.Meta[k]; !ok {
+				next.Meta[k] = v
+			}
+		}
+		action = next
+	}
+
+	switch {
+	case action.Count > count:
+		action.Direction = sdk.ScaleDirectionUp
+	case action.Count < count:
+		action.Direction = sdk.ScaleDirectionDown
+	default:
+		action.Direction = sdk.ScaleDirectionNone
+	}
+
+	h.checkEval.Action = action
+	return nil
+}
+
+// checkMetricAge returns an error if the most recent metric is older than
+// maxAge. Instances of per-instance checks with stale metrics are removed,
+// and an error is only returned if all instances are stale. Metrics without a
+// timestamp are not checked.
+func (h *checkHandler) checkMetricAge(maxAge time.Duration) error {
+	now := h.now()
+	stale := func(m sdk.TimestampedMetrics) (time.Duration, bool) {
+		latest := m[len(m)-1].Timestamp
+		if latest.IsZero() {
+			return 0, false
+		}
+		age := now.Sub(latest)
+		return age, age > maxAge
+	}
+
+	if len(h.checkEval.Metrics) > 0 {
+		if age, ok := stale(h.checkEval.Metrics); ok {
+			return newEvalError(ErrStaleMetrics, "most recent metric is %v old, more than the maximum age of %v", age, maxAge)
+		}
+		return nil
+	}
+
+	for label, m := range h.checkEval.LabeledMetrics {
+		if len(m) == 0 {
+			continue
+		}
+		if age, ok := stale(m); ok {
+			h.logger.Debug("ignoring instance with stale metrics", "instance", label, "age", age, "max_age", maxAge)
+			delete(h.checkEval.LabeledMetrics, label)
+		}
+	}
+	if len(h.checkEval.LabeledMetrics) == 0 {
+		return newEvalError(ErrStaleMetrics, "metrics of all instances are more than the maximum age of %v old", maxAge)
+	}
+	return nil
+}
+
+// smoothMetrics records the latest metric value within the check's metric
+// window and replaces the metrics passed to the strategy with the aggregate
+// of the window.
+func (h *checkHandler) smoothMetrics() {
+	latest := h.checkEval.Metrics[len(h.checkEval.Metrics)-1]
+
+	value, samples := h.metricWindows.Add(h.policy.ID, h.checkEval.Check, latest.Value)
+	h.logger.Debug("smoothed metric using metric window", "value", latest.Value, "smoothed_value", value,
+		"samples", samples, "aggregation", h.checkEval.Check.MetricAggregation)
+
+	h.checkEval.Metrics = sdk.TimestampedMetrics{{Timestamp: latest.Timestamp, Value: value}}
+}
+
+// smoothMetricsEWMA records the latest metric value within the check's moving
+// average and replaces the metrics passed to the strategy with the average.
+func (h *checkHandler) smoothMetricsEWMA() {
+	latest := h.checkEval.Metrics[len(h.checkEval.Metrics)-1]
+
+	value := h.metricWindows.Smooth(h.policy.ID, h.checkEval.Check, latest.Value)
+	h.logger.Debug("smoothed metric using moving average", "value", latest.Value, "smoothed_value", value,
+		"alpha", h.checkEval.Check.MetricSmoothingAlpha)
+
+	h.checkEval.Metrics = sdk.TimestampedMetrics{{Timestamp: latest.Timestamp, Value: value}}
+}
+
+// transformMetrics applies the check transforms to its metrics in order.
+func (h *checkHandler) transformMetrics() error {
+	transforms, err := sdk.ParseMetricTransforms(h.checkEval.Check.Transforms)
+	if err != nil {
+		return newEvalError(ErrMetricTransform, "failed to parse check transforms: %w", err)
+	}
+
+	h.checkEval.Metrics = sdk.TransformMetrics(h.checkEval.Metrics, transforms)
+	for label, m := range h.checkEval.LabeledMetrics {
+		h.checkEval.LabeledMetrics[label] = sdk.TransformMetrics(m, transforms)
+	}
+	return nil
+}
+
+// unmetPrecondition returns the first precondition of the check which is not
+// met by the target status, or nil if all of them are met.
+func unmetPrecondition(c *sdk.ScalingPolicyCheck, status *sdk.TargetStatus) (*sdk.CheckPrecondition, error) {
+	preconditions, err := sdk.ParseCheckPreconditions(c.Preconditions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range preconditions {
+		if !p.Met(status) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkResult is the action proposed by a check handler.
+type checkResult struct {
+	handler *checkHandler
+	action  *sdk.ScalingAction
+}
+
+// reconcileCheckResults selects the action to execute from the actions
+// proposed by the policy checks. Only the checks with the highest priority of
+// the checks which propose scaling are considered, and their actions are
+// reconciled using sdk.PreemptScalingAction. When all checks have the same
+// priority, such as when none is configured, all the actions are reconciled.
+func reconcileCheckResults(results []checkResult) (*checkHandler, *sdk.ScalingAction) {
+	var priority int
+	var scaling bool
+	for _, r := range results {
+		if r.action == nil || r.action.Direction == sdk.ScaleDirectionNone {
+			continue
+		}
+		if p := r.handler.checkEval.Check.Priority; !scaling || p > priority {
+			priority, scaling = p, true
+		}
+	}
+
+	var winningHandler *checkHandler
+	var winningAction *sdk.ScalingAction
+	for _, r := range results {
+		if scaling && r.handler.checkEval.Check.Priority != priority {
+			continue
+		}
+
+		winningAction = sdk.PreemptScalingAction(winningAction, r.action)
+		if winningAction == r.action {
+			winningHandler = r.handler
+		}
+	}
+
+	return winningHandler, winningAction
+}
+
+// quorumVotes returns the number of checks whose action is in the passed
+// direction, along with the direction voted by each check so disagreements
+// can be logged. Checks which did not propose an action vote for no scaling.
+func quorumVotes(results []checkResult, direction sdk.ScaleDirection) (int, []string) {
+	var votes int
+	ballot := make([]string, 0, len(results))
+
+	for _, r := range results {
+		vote := sdk.ScaleDirection(sdk.ScaleDirectionNone)
+		if r.action != nil {
+			vote = r.action.Direction
+		}
+		if vote == direction {
+			votes++
+		}
+		ballot = append(ballot, fmt.Sprintf("%s=%s", r.handler.checkEval.Check.Name, vote))
+	}
+
+	return votes, ballot
+}
+
+// cooldownBypassDeviation returns the metric deviation of the action and
+// whether it allows the policy cooldown to be bypassed. Only scale out actions
+// whose deviation reaches the policy's bypass factor are allowed, as bypassing
+// the cooldown to scale in could cause flapping.
+func cooldownBypassDeviation(p *sdk.ScalingPolicy, action *sdk.ScalingAction) (float64, bool) {
+	if p.CooldownBypassFactor <= 0 || action.Direction != sdk.ScaleDirectionUp {
+		return 0, false
+	}
+
+	deviation, ok := action.Deviation()
+	if !ok {
+		return 0, false
+	}
+	return deviation, deviation >= p.CooldownBypassFactor
+}
+
+// hysteresisDeviation returns the metric deviation of the action and whether
+// it is large enough to run the action given the direction of the policy's
+// last scaling action. Actions which continue in the same direction are
+// always allowed, while reversing the direction requires the deviation to
+// reach the policy's hysteresis factor, or its reciprocal when scaling in.
+// Actions without a deviation are allowed, as there is nothing to compare.
+func hysteresisDeviation(p *sdk.ScalingPolicy, action *sdk.ScalingAction, last sdk.ScaleDirection) (float64, bool) {
+	if p.HysteresisFactor <= 0 || last == sdk.ScaleDirectionNone || action.Direction == last {
+		return 0, true
+	}
+
+	deviation, ok := action.Deviation()
+	if !ok {
+		return 0, true
+	}
+
+	switch action.Direction {
+	case sdk.ScaleDirectionUp:
+		return deviation, deviation >= p.HysteresisFactor
+	case sdk.ScaleDirectionDown:
+		return deviation, deviation <= 1/p.HysteresisFactor
+	default:
+		return deviation, true
+	}
+}
+
+// noAction returns an action which doesn't scale the target, recording the
+// passed reason so it can be reported by the worker.
+func noAction(reason string) *sdk.ScalingAction {
+	a := &sdk.ScalingAction{Direction: sdk.ScaleDirectionNone}
+	a.SetNoActionReason(reason)
+	return a
+}
+
+// reportNoAction reports why a policy evaluation didn't result in a scaling
+// action. Most evaluations don't scale the target, so the reason is only
+// logged at trace level, but it is always counted so operators can find why
+// a policy is not scaling.
+func reportNoAction(logger hclog.Logger, labels []metrics.Label, reason string) {
+	logger.Trace("no scaling action taken", "no_action_reason", reason)
+	metrics.IncrCounterWithLabels([]string{"scale", "evaluate", "no_action_count"}, 1,
+		append(labels[:len(labels):len(labels)], metrics.Label{Name: "reason", Value: reason}))
+}
+
+// sampleDryRun samples whether the action of a policy which sets a
+// DryRunSample is executed in dry-run mode, and records the outcome in the
+// action Meta so live and dry-run actions can be told apart. It returns
+// whether the action was marked as dry-run.
+func sampleDryRun(a *sdk.ScalingAction, dryRunSample float64, sample func() float64) bool {
+	if dryRunSample <= 0 {
+		return false
+	}
+
+	if sample() >= dryRunSample {
+		a.SetDryRunSample(sdk.DryRunSampleLive)
+		return false
+	}
+
+	a.SetDryRunSample(sdk.DryRunSampleDryRun)
+	a.SetDryRun()
+	return true
+}
+
+// minMaxAction returns the scaling action required to bring the current count
+// within the [min, max] limits. It returns nil if the count is already within
+// the limits.
+func minMaxAction(count, min, max int64) *sdk.ScalingAction {
+	if count < min {
+		return &sdk.ScalingAction{
+			Count:     min,
+			Direction: sdk.ScaleDirectionUp,
+			Reason:    fmt.Sprintf("current count (%d) below limit (%d)", count, min),
+		}
+	}
+	if count > max {
+		return &sdk.ScalingAction{
+			Count:     max,
+			Direction: sdk.ScaleDirectionDown,
+			Reason:    fmt.Sprintf("current count (%d) above limit (%d)", count, max),
+		}
+	}
+	return nil
+}
+
+// safeCountAction returns the action scaling the target to the safe count of
+// the policy, once all checks have failed to fetch metrics for the passed
+// number of consecutive evaluations. It returns nil if the policy holds its
+// count on metric errors, the threshold has not been reached, or the target
+// is already at the safe count.
+func safeCountAction(p *sdk.ScalingPolicy, count int64, evals int) *sdk.ScalingAction {
+	if p.OnMetricError != sdk.MetricErrorScaleToSafe || evals < metricErrorSafeScaleThreshold {
+		return nil
+	}
+
+	limits := policy.EffectiveLimits(p)
+	safe := p.SafeCount
+	if safe == 0 {
+		safe = limits.Max.Value
+	}
+
+	action := &sdk.ScalingAction{
+		Count:  safe,
+		Reason: fmt.Sprintf("metrics unavailable for %d evaluations, scaling to safe count (%d)", evals, safe),
+	}
+	action.Canonicalize()
+	action.CapCount(limits.Min.Value, limits.Max.Value)
+
+	switch {
+	case action.Count > count:
+		action.Direction = sdk.ScaleDirectionUp
+	case action.Count < count:
+		action.Direction = sdk.ScaleDirectionDown
+	default:
+		return nil
+	}
+	return action
+}
+
 // runAPMQuery wraps the apm.Query call to provide operational functionality.
-func (h *checkHandler) runAPMQuery(apmImpl apm.APM) (sdk.TimestampedMetrics, error) {
+// The query parameters of the check are only used for the check's Query,
+// while its source config overrides are used for every query.
+func (h *checkHandler) runAPMQuery(ctx context.Context, apmImpl apm.APM, query string) (m sdk.TimestampedMetrics, err error) {
+	_, span := startPhaseSpan(ctx, evalPhaseAPMQuery, h.checkEval.Check.Source, h.policy.ID)
+	defer func() { endSpan(span, err) }()
 
-	h.logger.Debug("querying source", "query", h.checkEval.Check.Query, "source", h.checkEval.Check.Source)
+	h.logger.Debug("querying source", "query", query, "source", h.checkEval.Check.Source)
 
 	// Trigger a metric measure to track latency of the call.
-	labels := []metrics.Label{{Name: "plugin_name", Value: h.checkEval.Check.Source}, {Name: "policy_id", Value: h.policy.ID}}
+	labels := policyLabels(h.policy, metrics.Label{Name: "plugin_name", Value: h.checkEval.Check.Source}, metrics.Label{Name: "policy_id", Value: h.policy.ID})
 	defer metrics.MeasureSinceWithLabels([]string{"plugin", "apm", "query", "invoke_ms"}, time.Now(), labels)
+	defer measurePhase(h.logger, h.slowPhaseThreshold, evalPhaseAPMQuery, h.checkEval.Check.Source, h.policy, time.Now())
 
 	// Calculate query range from the query window defined in the check.
-	to := time.Now()
+	to := h.now()
 	from := to.Add(-h.checkEval.Check.QueryWindow)
 	r := sdk.TimeRange{From: from, To: to}
 
-	return apmImpl.Query(h.checkEval.Check.Query, r)
+	var params *sdk.QueryParams
+	if query == h.checkEval.Check.Query {
+		params = h.checkEval.Check.QueryParams
+	}
+
+	// Querying the APM without the source config overrides would query the
+	// wrong server, so fail if they are not supported. Overrides are
+	// validated when the policy is loaded, so this only happens if the APM
+	// plugin has changed since.
+	config := h.checkEval.Check.SourceConfig
+	if len(config) > 0 {
+		_, canOverride := apmImpl.(apm.ConfigOverrider)
+		pq, ok := apmImpl.(apm.ParamsQuerier)
+		if !canOverride || !ok {
+			return nil, newEvalError(ErrAPMQuery, "source %s does not support source config overrides", h.checkEval.Check.Source)
+		}
+		return pq.QueryWithParams(sdk.QueryRequest{Query: query, TimeRange: r, Params: params, Config: config})
+	}
+
+	// Pass the structured query parameters to APMs which support them.
+	if params != nil {
+		if pq, ok := apmImpl.(apm.ParamsQuerier); ok {
+			return pq.QueryWithParams(sdk.QueryRequest{Query: query, TimeRange: r, Params: params})
+		}
+		h.logger.Debug("source does not support query parameters, ignoring them", "source", h.checkEval.Check.Source)
+	}
+
+	return apmImpl.Query(query, r)
+}
+
+// runSeriesQueries runs the check's Query and SeriesQueries and aggregates
+// their results into a single series. If any query returns no metrics, no
+// metrics are returned since the aggregate would be misleading.
+func (h *checkHandler) runSeriesQueries(ctx context.Context, apmImpl apm.APM) (sdk.TimestampedMetrics, error) {
+	queries := append([]string{h.checkEval.Check.Query}, h.checkEval.Check.SeriesQueries...)
+	series := make([]sdk.TimestampedMetrics, 0, len(queries))
+
+	for _, q := range queries {
+		m, err := h.runAPMQuery(ctx, apmImpl, q)
+		if err != nil {
+			return nil, err
+		}
+		if len(m) == 0 {
+			h.logger.Warn("no metrics available for series query", "query", q)
+			return nil, nil
+		}
+		series = append(series, m)
+	}
+
+	return aggregateSeries(series, h.checkEval.Check.SeriesAggregation), nil
+}
+
+// runFormulaQueries runs the check's Query and FormulaQueries, and returns
+// their results by the name they are referenced by within the check's
+// Formula. If any query returns no metrics, no metrics are returned since the
+// result of the formula would be misleading.
+func (h *checkHandler) runFormulaQueries(ctx context.Context, apmImpl apm.APM) (map[string]sdk.TimestampedMetrics, error) {
+	queries := map[string]string{sdk.MetricFormulaQueryName: h.checkEval.Check.Query}
+	for name, q := range h.checkEval.Check.FormulaQueries {
+		queries[name] = q
+	}
+
+	names := make([]string, 0, len(queries))
+	for name := range queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	series := make(map[string]sdk.TimestampedMetrics, len(queries))
+	for _, name := range names {
+		m, err := h.runAPMQuery(ctx, apmImpl, queries[name])
+		if err != nil {
+			return nil, err
+		}
+		if len(m) == 0 {
+			h.logger.Warn("no metrics available for formula query", "name", name, "query", queries[name])
+			return nil, nil
+		}
+		series[name] = m
+	}
+
+	return series, nil
+}
+
+// runLabeledAPMQuery wraps the apm.LabeledQuerier QueryLabeled call to
+// provide operational functionality. It is used by per-instance checks.
+func (h *checkHandler) runLabeledAPMQuery(ctx context.Context, apmImpl apm.APM) (m sdk.LabeledMetrics, err error) {
+	_, span := startPhaseSpan(ctx, evalPhaseAPMQuery, h.checkEval.Check.Source, h.policy.ID)
+	defer func() { endSpan(span, err) }()
+
+	lq, ok := apmImpl.(apm.LabeledQuerier)
+	if !ok {
+		return nil, fmt.Errorf("source %q does not support per-instance queries", h.checkEval.Check.Source)
+	}
+
+	h.logger.Debug("querying source per instance", "query", h.checkEval.Check.Query, "source", h.checkEval.Check.Source)
+
+	// Trigger a metric measure to track latency of the call.
+	labels := policyLabels(h.policy, metrics.Label{Name: "plugin_name", Value: h.checkEval.Check.Source}, metrics.Label{Name: "policy_id", Value: h.policy.ID})
+	defer metrics.MeasureSinceWithLabels([]string{"plugin", "apm", "query", "invoke_ms"}, time.Now(), labels)
+	defer measurePhase(h.logger, h.slowPhaseThreshold, evalPhaseAPMQuery, h.checkEval.Check.Source, h.policy, time.Now())
+
+	// Calculate query range from the query window defined in the check.
+	to := h.now()
+	from := to.Add(-h.checkEval.Check.QueryWindow)
+
+	return lq.QueryLabeled(h.checkEval.Check.Query, sdk.TimeRange{From: from, To: to})
 }
 
 // runStrategyRun wraps the strategy.Run call to provide operational functionality.
-func (h *checkHandler) runStrategyRun(strategyImpl strategy.Strategy, count int64) (*sdk.ScalingCheckEvaluation, error) {
+func (h *checkHandler) runStrategyRun(ctx context.Context, strategyImpl strategy.Strategy, eval *sdk.ScalingCheckEvaluation, count int64) (res *sdk.ScalingCheckEvaluation, err error) {
+	_, span := startPhaseSpan(ctx, evalPhaseStrategyRun, eval.Check.Strategy.Name, h.policy.ID)
+	defer func() { endSpan(span, err) }()
 
 	// Trigger a metric measure to track latency of the call.
-	labels := []metrics.Label{
-		{Name: "plugin_name", Value: h.checkEval.Check.Strategy.Name},
-		{Name: "policy_id", Value: h.policy.ID},
-	}
+	labels := policyLabels(h.policy,
+		metrics.Label{Name: "plugin_name", Value: eval.Check.Strategy.Name},
+		metrics.Label{Name: "policy_id", Value: h.policy.ID},
+	)
 	defer metrics.MeasureSinceWithLabels([]string{"plugin", "strategy", "run", "invoke_ms"}, time.Now(), labels)
+	defer measurePhase(h.logger, h.slowPhaseThreshold, evalPhaseStrategyRun, eval.Check.Strategy.Name, h.policy, time.Now())
+
+	// Strategies can't calculate a meaningful count from a negative count, so
+	// don't call them with one. A zero count is valid but is signalled
+	// explicitly, as strategies can't scale proportionally from it.
+	if count < 0 {
+		return nil, fmt.Errorf("invalid current count %d", count)
+	}
+	eval.ScaleFromZero = count == 0
 
-	return strategyImpl.Run(h.checkEval, count)
+	// The result is only read if the call returns in time, as the call keeps
+	// running after timing out.
+	var r *sdk.ScalingCheckEvaluation
+	err = callPlugin(h.pluginManager, eval.Check.Strategy.Name, sdk.PluginTypeStrategy, func() error {
+		var err error
+		r, err = strategyImpl.Run(eval, count)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
@@ -0,0 +1,807 @@
+package policyeval
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/plugins/strategy"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cooldownBypassDeviation(t *testing.T) {
+	testCases := []struct {
+		inputFactor       float64
+		inputDirection    sdk.ScaleDirection
+		inputDeviation    float64
+		inputSetDeviation bool
+		expectedDeviation float64
+		expectedOK        bool
+		name              string
+	}{
+		{
+			inputFactor:       0,
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputDeviation:    5,
+			inputSetDeviation: true,
+			expectedDeviation: 0,
+			expectedOK:        false,
+			name:              "bypass not configured",
+		},
+		{
+			inputFactor:       2,
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputDeviation:    3,
+			inputSetDeviation: true,
+			expectedDeviation: 3,
+			expectedOK:        true,
+			name:              "scale out above factor",
+		},
+		{
+			inputFactor:       2,
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputDeviation:    1.5,
+			inputSetDeviation: true,
+			expectedDeviation: 1.5,
+			expectedOK:        false,
+			name:              "scale out below factor",
+		},
+		{
+			inputFactor:       2,
+			inputDirection:    sdk.ScaleDirectionDown,
+			inputDeviation:    3,
+			inputSetDeviation: true,
+			expectedDeviation: 0,
+			expectedOK:        false,
+			name:              "scale in never bypasses",
+		},
+		{
+			inputFactor:       2,
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputSetDeviation: false,
+			expectedDeviation: 0,
+			expectedOK:        false,
+			name:              "strategy did not set deviation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &sdk.ScalingPolicy{CooldownBypassFactor: tc.inputFactor}
+			action := &sdk.ScalingAction{Direction: tc.inputDirection}
+			action.Canonicalize()
+			if tc.inputSetDeviation {
+				action.SetDeviation(tc.inputDeviation)
+			}
+
+			actualDeviation, actualOK := cooldownBypassDeviation(p, action)
+			assert.Equal(t, tc.expectedDeviation, actualDeviation, tc.name)
+			assert.Equal(t, tc.expectedOK, actualOK, tc.name)
+		})
+	}
+}
+
+func Test_hysteresisDeviation(t *testing.T) {
+	testCases := []struct {
+		inputDirection    sdk.ScaleDirection
+		inputLast         sdk.ScaleDirection
+		inputDeviation    float64
+		inputSetDeviation bool
+		expectedOK        bool
+		name              string
+	}{
+		{
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputLast:         sdk.ScaleDirectionNone,
+			inputDeviation:    1.1,
+			inputSetDeviation: true,
+			expectedOK:        true,
+			name:              "no last action",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputLast:         sdk.ScaleDirectionUp,
+			inputDeviation:    1.1,
+			inputSetDeviation: true,
+			expectedOK:        true,
+			name:              "continuing scale out",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputLast:         sdk.ScaleDirectionDown,
+			inputDeviation:    1.1,
+			inputSetDeviation: true,
+			expectedOK:        false,
+			name:              "reversing scale in with small deviation",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionUp,
+			inputLast:         sdk.ScaleDirectionDown,
+			inputDeviation:    1.5,
+			inputSetDeviation: true,
+			expectedOK:        true,
+			name:              "reversing scale in with large deviation",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionDown,
+			inputLast:         sdk.ScaleDirectionUp,
+			inputDeviation:    0.8,
+			inputSetDeviation: true,
+			expectedOK:        false,
+			name:              "reversing scale out with small deviation",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionDown,
+			inputLast:         sdk.ScaleDirectionUp,
+			inputDeviation:    0.5,
+			inputSetDeviation: true,
+			expectedOK:        true,
+			name:              "reversing scale out with large deviation",
+		},
+		{
+			inputDirection:    sdk.ScaleDirectionDown,
+			inputLast:         sdk.ScaleDirectionUp,
+			inputSetDeviation: false,
+			expectedOK:        true,
+			name:              "strategy did not set deviation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &sdk.ScalingPolicy{HysteresisFactor: 1.5}
+			action := &sdk.ScalingAction{Direction: tc.inputDirection}
+			action.Canonicalize()
+			if tc.inputSetDeviation {
+				action.SetDeviation(tc.inputDeviation)
+			}
+
+			_, actualOK := hysteresisDeviation(p, action, tc.inputLast)
+			assert.Equal(t, tc.expectedOK, actualOK, tc.name)
+		})
+	}
+}
+
+func Test_noAction(t *testing.T) {
+	a := noAction(sdk.NoActionReasonDeadzone)
+	assert.Equal(t, sdk.ScaleDirection(sdk.ScaleDirectionNone), a.Direction)
+	assert.Equal(t, sdk.NoActionReasonDeadzone, a.NoActionReason())
+
+	// The reason of the action selected from checks which all propose no
+	// action is reported for the evaluation.
+	_, winning := reconcileCheckResults([]checkResult{
+		{handler: &checkHandler{checkEval: &sdk.ScalingCheckEvaluation{Check: &sdk.ScalingPolicyCheck{}}}, action: a},
+	})
+	assert.Equal(t, sdk.NoActionReasonDeadzone, winning.NoActionReason())
+}
+
+func TestBaseWorker_recordScalingAction(t *testing.T) {
+	w := &BaseWorker{policyManager: policy.NewManager(hclog.NewNullLogger(), nil, nil, time.Minute, nil)}
+
+	status := func(desired int) *sdk.TargetStatus {
+		return &sdk.TargetStatus{Count: int64(desired), Meta: map[string]string{
+			sdk.TargetStatusMetaKeyDesiredCount: strconv.Itoa(desired),
+			sdk.TargetStatusMetaKeyRunningCount: strconv.Itoa(desired),
+		}}
+	}
+
+	_, changed := w.policyManager.ObserveCount("policy", observableCount(status(3)))
+	assert.False(t, changed)
+
+	// The target is asked to scale to 8, but only reaches 5, such as when
+	// capped by a quota. This is not a change made outside the autoscaler.
+	w.recordScalingAction("policy", &sdk.ScalingAction{Count: 8, Direction: sdk.ScaleDirectionUp})
+	assert.Equal(t, int64(8), w.policyManager.LastActions()["policy"].Count)
+
+	_, changed = w.policyManager.ObserveCount("policy", observableCount(status(5)))
+	assert.False(t, changed)
+
+	// Later changes to the count reached by the target are still detected.
+	previous, changed := w.policyManager.ObserveCount("policy", observableCount(status(7)))
+	assert.True(t, changed)
+	assert.Equal(t, int64(5), previous)
+}
+
+func Test_observableCount(t *testing.T) {
+	assert.Equal(t, int64(3), observableCount(&sdk.TargetStatus{Count: 3}))
+	assert.Equal(t, int64(5), observableCount(&sdk.TargetStatus{Count: 3, Meta: map[string]string{
+		sdk.TargetStatusMetaKeyDesiredCount: "5",
+		sdk.TargetStatusMetaKeyRunningCount: "3",
+	}}))
+}
+
+func Test_sampleDryRun(t *testing.T) {
+	testCases := []struct {
+		inputDryRunSample float64
+		inputSample       float64
+		expectedDryRun    bool
+		expectedCount     int64
+		expectedSample    string
+		name              string
+	}{
+		{
+			inputDryRunSample: 0,
+			inputSample:       0,
+			expectedDryRun:    false,
+			expectedCount:     5,
+			expectedSample:    "",
+			name:              "sampling disabled",
+		},
+		{
+			inputDryRunSample: 0.9,
+			inputSample:       0.3,
+			expectedDryRun:    true,
+			expectedCount:     sdk.StrategyActionMetaValueDryRunCount,
+			expectedSample:    sdk.DryRunSampleDryRun,
+			name:              "sampled dry-run",
+		},
+		{
+			inputDryRunSample: 0.9,
+			inputSample:       0.9,
+			expectedDryRun:    false,
+			expectedCount:     5,
+			expectedSample:    sdk.DryRunSampleLive,
+			name:              "sampled live",
+		},
+		{
+			inputDryRunSample: 1,
+			inputSample:       0.999,
+			expectedDryRun:    true,
+			expectedCount:     sdk.StrategyActionMetaValueDryRunCount,
+			expectedSample:    sdk.DryRunSampleDryRun,
+			name:              "always dry-run",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &sdk.ScalingAction{Count: 5, Direction: sdk.ScaleDirectionUp, Meta: map[string]interface{}{}}
+
+			dryRun := sampleDryRun(a, tc.inputDryRunSample, func() float64 { return tc.inputSample })
+			assert.Equal(t, tc.expectedDryRun, dryRun, tc.name)
+			assert.Equal(t, tc.expectedDryRun, a.IsDryRun(), tc.name)
+			assert.Equal(t, tc.expectedCount, a.Count, tc.name)
+			assert.Equal(t, tc.expectedSample, a.DryRunSample(), tc.name)
+		})
+	}
+}
+
+func Test_safeCountAction(t *testing.T) {
+	testCases := []struct {
+		inputOnError      string
+		inputSafeCount    int64
+		inputCount        int64
+		inputEvals        int
+		expectedCount     int64
+		expectedDirection sdk.ScaleDirection
+		expectedNil       bool
+		name              string
+	}{
+		{
+			inputOnError: sdk.MetricErrorHold,
+			inputCount:   3,
+			inputEvals:   10,
+			expectedNil:  true,
+			name:         "hold",
+		},
+		{
+			inputOnError: "",
+			inputCount:   3,
+			inputEvals:   10,
+			expectedNil:  true,
+			name:         "default holds",
+		},
+		{
+			inputOnError:   sdk.MetricErrorScaleToSafe,
+			inputSafeCount: 5,
+			inputCount:     3,
+			inputEvals:     metricErrorSafeScaleThreshold - 1,
+			expectedNil:    true,
+			name:           "below threshold",
+		},
+		{
+			inputOnError:      sdk.MetricErrorScaleToSafe,
+			inputSafeCount:    5,
+			inputCount:        3,
+			inputEvals:        metricErrorSafeScaleThreshold,
+			expectedCount:     5,
+			expectedDirection: sdk.ScaleDirectionUp,
+			name:              "scale out to safe count",
+		},
+		{
+			inputOnError:      sdk.MetricErrorScaleToSafe,
+			inputSafeCount:    5,
+			inputCount:        8,
+			inputEvals:        metricErrorSafeScaleThreshold,
+			expectedCount:     5,
+			expectedDirection: sdk.ScaleDirectionDown,
+			name:              "scale in to safe count",
+		},
+		{
+			inputOnError:      sdk.MetricErrorScaleToSafe,
+			inputCount:        3,
+			inputEvals:        metricErrorSafeScaleThreshold,
+			expectedCount:     10,
+			expectedDirection: sdk.ScaleDirectionUp,
+			name:              "safe count defaults to max",
+		},
+		{
+			inputOnError:   sdk.MetricErrorScaleToSafe,
+			inputSafeCount: 5,
+			inputCount:     5,
+			inputEvals:     metricErrorSafeScaleThreshold,
+			expectedNil:    true,
+			name:           "already at safe count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &sdk.ScalingPolicy{
+				Min:           1,
+				Max:           10,
+				OnMetricError: tc.inputOnError,
+				SafeCount:     tc.inputSafeCount,
+				Target:        &sdk.ScalingPolicyTarget{},
+			}
+
+			action := safeCountAction(p, tc.inputCount, tc.inputEvals)
+			if tc.expectedNil {
+				assert.Nil(t, action)
+				return
+			}
+			assert.NotNil(t, action)
+			assert.Equal(t, tc.expectedCount, action.Count)
+			assert.Equal(t, tc.expectedDirection, action.Direction)
+		})
+	}
+}
+
+type testScaleFromZeroStrategy struct {
+	strategy.Strategy
+}
+
+func (testScaleFromZeroStrategy) Run(eval *sdk.ScalingCheckEvaluation, count int64) (*sdk.ScalingCheckEvaluation, error) {
+	eval.Action.Direction = sdk.ScaleDirectionNone
+	if eval.ScaleFromZero {
+		eval.Action.Direction = sdk.ScaleDirectionUp
+	}
+	return eval, nil
+}
+
+func TestCheckHandler_runStrategyRun_count(t *testing.T) {
+	testCases := []struct {
+		inputCount       int64
+		expectedError    bool
+		expectedFromZero bool
+		name             string
+	}{
+		{inputCount: -1, expectedError: true, name: "negative count"},
+		{inputCount: 0, expectedFromZero: true, name: "zero count"},
+		{inputCount: 1, expectedFromZero: false, name: "one count"},
+		{inputCount: 1000000, expectedFromZero: false, name: "large count"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkEval := &sdk.ScalingCheckEvaluation{
+				Check:  &sdk.ScalingPolicyCheck{Name: "check", Strategy: &sdk.ScalingPolicyStrategy{Name: "test"}},
+				Action: &sdk.ScalingAction{},
+			}
+
+			h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, nil, 0, nil, 0)
+			res, err := h.runStrategyRun(context.Background(), testScaleFromZeroStrategy{}, checkEval, tc.inputCount)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+			if err == nil {
+				assert.Equal(t, tc.expectedFromZero, res.ScaleFromZero, tc.name)
+				assert.Equal(t, tc.expectedFromZero, res.Action.Direction == sdk.ScaleDirectionUp, tc.name)
+			}
+		})
+	}
+}
+
+func TestCheckHandler_runStrategyChain(t *testing.T) {
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	// Chained strategies can refer to strategy aliases.
+	assert.NoError(t, pm.SetStrategyAliases([]*config.StrategyAlias{
+		{Name: "target-five", Strategy: "target-value", Config: map[string]string{"target": "5"}},
+	}))
+
+	testCases := []struct {
+		inputMetric    float64
+		inputAction    *sdk.ScalingAction
+		expectedCount  int64
+		expectedDir    sdk.ScaleDirection
+		expectedReason string
+		name           string
+	}{
+		{
+			inputMetric:    20,
+			inputAction:    &sdk.ScalingAction{Count: 2, Direction: sdk.ScaleDirectionUp, Reason: "first"},
+			expectedCount:  8,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because factor is 4.000000",
+			name:           "chained strategy refines proposed count",
+		},
+		{
+			inputMetric:    5,
+			inputAction:    &sdk.ScalingAction{Count: 2, Direction: sdk.ScaleDirectionUp, Reason: "first"},
+			expectedCount:  2,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "",
+			name:           "chained strategy keeps proposed count",
+		},
+		{
+			inputMetric:    10,
+			inputAction:    &sdk.ScalingAction{Direction: sdk.ScaleDirectionNone, Reason: "first"},
+			expectedCount:  2,
+			expectedDir:    sdk.ScaleDirectionUp,
+			expectedReason: "scaling up because factor is 2.000000",
+			name:           "chained strategy changes current count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.inputAction.Canonicalize()
+			check := &sdk.ScalingPolicyCheck{
+				Name:     "check",
+				Strategy: &sdk.ScalingPolicyStrategy{Name: "target-value", Config: map[string]string{"target": "10"}},
+				Chain: []*sdk.ScalingPolicyStrategy{
+					{Name: "target-five"},
+				},
+			}
+			checkEval := &sdk.ScalingCheckEvaluation{
+				Check:   check,
+				Metrics: sdk.TimestampedMetrics{{Value: tc.inputMetric}},
+				Action:  tc.inputAction,
+			}
+
+			h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, pm, 0, nil, 0)
+			assert.NoError(t, h.runStrategyChain(context.Background(), 1))
+
+			action := h.checkEval.Action
+			assert.Equal(t, tc.expectedCount, action.Count, tc.name)
+			assert.Equal(t, tc.expectedDir, action.Direction, tc.name)
+			assert.Equal(t, tc.expectedReason, action.Reason, tc.name)
+			assert.Equal(t, []string{"first"}, action.Meta["nomad_autoscaler.reason_history"], tc.name)
+
+			// The check config used by the chained strategy isn't changed.
+			assert.Equal(t, "10", h.checkEval.Check.Strategy.Config["target"], tc.name)
+		})
+	}
+}
+
+func Test_reconcileCheckResults(t *testing.T) {
+	result := func(name string, priority int, dir sdk.ScaleDirection, count int64) checkResult {
+		return checkResult{
+			handler: &checkHandler{checkEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{Name: name, Priority: priority},
+			}},
+			action: &sdk.ScalingAction{Direction: dir, Count: count},
+		}
+	}
+
+	testCases := []struct {
+		inputResults  []checkResult
+		expectedCheck string
+		name          string
+	}{
+		{
+			inputResults:  nil,
+			expectedCheck: "",
+			name:          "no results",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 0, sdk.ScaleDirectionDown, 2),
+				result("queue", 0, sdk.ScaleDirectionNone, 0),
+			},
+			expectedCheck: "queue",
+			name:          "no priorities prefers safest action",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 0, sdk.ScaleDirectionUp, 8),
+				result("queue", 10, sdk.ScaleDirectionUp, 4),
+			},
+			expectedCheck: "queue",
+			name:          "highest priority check wins",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 0, sdk.ScaleDirectionUp, 8),
+				result("queue", 10, sdk.ScaleDirectionDown, 2),
+			},
+			expectedCheck: "queue",
+			name:          "highest priority check wins over safer action",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 5, sdk.ScaleDirectionUp, 8),
+				result("queue", 10, sdk.ScaleDirectionNone, 0),
+			},
+			expectedCheck: "cpu",
+			name:          "checks which do not propose scaling are skipped",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 10, sdk.ScaleDirectionUp, 8),
+				result("memory", 10, sdk.ScaleDirectionUp, 6),
+				result("queue", 0, sdk.ScaleDirectionUp, 10),
+			},
+			expectedCheck: "cpu",
+			name:          "checks with same priority are reconciled",
+		},
+		{
+			inputResults: []checkResult{
+				result("cpu", 10, sdk.ScaleDirectionDown, 2),
+				result("memory", 10, sdk.ScaleDirectionNone, 0),
+				result("queue", 0, sdk.ScaleDirectionUp, 10),
+			},
+			expectedCheck: "memory",
+			name:          "checks with same priority which do not propose scaling are reconciled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, action := reconcileCheckResults(tc.inputResults)
+			if tc.expectedCheck == "" {
+				assert.Nil(t, handler, tc.name)
+				assert.Nil(t, action, tc.name)
+				return
+			}
+			assert.Equal(t, tc.expectedCheck, handler.checkEval.Check.Name, tc.name)
+
+			for _, r := range tc.inputResults {
+				if r.handler == handler {
+					assert.Equal(t, r.action, action, tc.name)
+				}
+			}
+		})
+	}
+}
+
+func Test_quorumVotes(t *testing.T) {
+	result := func(name string, action *sdk.ScalingAction) checkResult {
+		return checkResult{
+			handler: &checkHandler{checkEval: &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{Name: name},
+			}},
+			action: action,
+		}
+	}
+
+	results := []checkResult{
+		result("cpu", &sdk.ScalingAction{Direction: sdk.ScaleDirectionUp, Count: 8}),
+		result("memory", &sdk.ScalingAction{Direction: sdk.ScaleDirectionUp, Count: 6}),
+		result("queue", &sdk.ScalingAction{Direction: sdk.ScaleDirectionDown, Count: 2}),
+		result("latency", nil),
+	}
+
+	testCases := []struct {
+		inputDirection sdk.ScaleDirection
+		expectedVotes  int
+		name           string
+	}{
+		{
+			inputDirection: sdk.ScaleDirectionUp,
+			expectedVotes:  2,
+			name:           "scale out",
+		},
+		{
+			inputDirection: sdk.ScaleDirectionDown,
+			expectedVotes:  1,
+			name:           "scale in",
+		},
+		{
+			inputDirection: sdk.ScaleDirectionNone,
+			expectedVotes:  1,
+			name:           "checks without action vote for no scaling",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			votes, ballot := quorumVotes(results, tc.inputDirection)
+			assert.Equal(t, tc.expectedVotes, votes, tc.name)
+			assert.Equal(t, []string{"cpu=up", "memory=up", "queue=down", "latency=none"}, ballot, tc.name)
+		})
+	}
+}
+
+func TestCheckHandler_start_errors(t *testing.T) {
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	checkEval := &sdk.ScalingCheckEvaluation{
+		Check: &sdk.ScalingPolicyCheck{
+			Name:     "check",
+			Source:   "missing",
+			Strategy: &sdk.ScalingPolicyStrategy{Name: "target-value"},
+		},
+		Action: &sdk.ScalingAction{},
+	}
+
+	h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, pm, 0, nil, 0)
+	_, err := h.start(context.Background(), &sdk.TargetStatus{Ready: true, Count: 1})
+	assert.True(t, errors.Is(err, ErrPluginDispense))
+	assert.Contains(t, err.Error(), `apm plugin "missing" not initialized`)
+}
+
+func TestCheckHandler_checkMetricAge(t *testing.T) {
+	now := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		inputMetrics        sdk.TimestampedMetrics
+		inputLabeledMetrics sdk.LabeledMetrics
+		expectedLabels      []string
+		expectedStale       bool
+		name                string
+	}{
+		{
+			inputMetrics: sdk.TimestampedMetrics{
+				{Timestamp: now.Add(-10 * time.Minute), Value: 1},
+				{Timestamp: now.Add(-time.Minute), Value: 2},
+			},
+			expectedStale: false,
+			name:          "recent metric",
+		},
+		{
+			inputMetrics: sdk.TimestampedMetrics{
+				{Timestamp: now.Add(-10 * time.Minute), Value: 1},
+				{Timestamp: now.Add(-6 * time.Minute), Value: 2},
+			},
+			expectedStale: true,
+			name:          "stale metric",
+		},
+		{
+			inputMetrics:  sdk.TimestampedMetrics{{Value: 1}},
+			expectedStale: false,
+			name:          "metric without timestamp",
+		},
+		{
+			inputLabeledMetrics: sdk.LabeledMetrics{
+				"alloc-1": {{Timestamp: now.Add(-time.Minute), Value: 1}},
+				"alloc-2": {{Timestamp: now.Add(-6 * time.Minute), Value: 2}},
+			},
+			expectedLabels: []string{"alloc-1"},
+			expectedStale:  false,
+			name:           "stale instance",
+		},
+		{
+			inputLabeledMetrics: sdk.LabeledMetrics{
+				"alloc-1": {{Timestamp: now.Add(-7 * time.Minute), Value: 1}},
+				"alloc-2": {{Timestamp: now.Add(-6 * time.Minute), Value: 2}},
+			},
+			expectedLabels: []string{},
+			expectedStale:  true,
+			name:           "all instances stale",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkEval := &sdk.ScalingCheckEvaluation{
+				Check: &sdk.ScalingPolicyCheck{
+					Name:         "check",
+					MaxMetricAge: 5 * time.Minute,
+					Strategy:     &sdk.ScalingPolicyStrategy{Name: "target-value"},
+				},
+				Metrics:        tc.inputMetrics,
+				LabeledMetrics: tc.inputLabeledMetrics,
+			}
+
+			h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, nil, 0, nil, 0)
+			h.now = func() time.Time { return now }
+
+			err := h.checkMetricAge(checkEval.Check.MaxMetricAge)
+			assert.Equal(t, tc.expectedStale, errors.Is(err, ErrStaleMetrics), tc.name)
+
+			if tc.expectedLabels != nil {
+				labels := []string{}
+				for l := range checkEval.LabeledMetrics {
+					labels = append(labels, l)
+				}
+				assert.ElementsMatch(t, tc.expectedLabels, labels, tc.name)
+			}
+		})
+	}
+}
+
+func TestCheckHandler_transformMetrics(t *testing.T) {
+	checkEval := &sdk.ScalingCheckEvaluation{
+		Check: &sdk.ScalingPolicyCheck{
+			Name:       "check",
+			Transforms: []string{"clamp(0,100)", "scale(0.5)"},
+			Strategy:   &sdk.ScalingPolicyStrategy{Name: "target-value"},
+		},
+		Metrics: sdk.TimestampedMetrics{{Value: 120}},
+		LabeledMetrics: sdk.LabeledMetrics{
+			"alloc-1": {{Value: -20}},
+			"alloc-2": {{Value: 60}},
+		},
+	}
+
+	h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, nil, 0, nil, 0)
+	assert.NoError(t, h.transformMetrics())
+	assert.Equal(t, sdk.TimestampedMetrics{{Value: 50}}, checkEval.Metrics)
+	assert.Equal(t, sdk.LabeledMetrics{
+		"alloc-1": {{Value: 0}},
+		"alloc-2": {{Value: 30}},
+	}, checkEval.LabeledMetrics)
+
+	checkEval.Check.Transforms = []string{"sqrt"}
+	assert.Error(t, h.transformMetrics())
+}
+
+func Test_unmetPrecondition(t *testing.T) {
+	status := &sdk.TargetStatus{
+		Ready: true,
+		Meta: map[string]string{
+			sdk.TargetStatusMetaKeyDesiredCount: "3",
+			sdk.TargetStatusMetaKeyRunningCount: "2",
+		},
+	}
+
+	unmet, err := unmetPrecondition(&sdk.ScalingPolicyCheck{Preconditions: []string{"ready"}}, status)
+	assert.NoError(t, err)
+	assert.Nil(t, unmet)
+
+	unmet, err = unmetPrecondition(&sdk.ScalingPolicyCheck{Preconditions: []string{"ready", "healthy"}}, status)
+	assert.NoError(t, err)
+	assert.Equal(t, "healthy", unmet.String())
+
+	_, err = unmetPrecondition(&sdk.ScalingPolicyCheck{Preconditions: []string{"stable"}}, status)
+	assert.Error(t, err)
+}
+
+func Test_callPlugin(t *testing.T) {
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	// Calls are not timed out by default.
+	err := callPlugin(pm, "target-value", sdk.PluginTypeStrategy, func() error {
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+
+	pm.SetHangDetection(10*time.Millisecond, 2)
+
+	assert.NoError(t, callPlugin(pm, "target-value", sdk.PluginTypeStrategy, func() error { return nil }))
+
+	// Consecutive timeouts restart the plugin.
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 2; i++ {
+		err = callPlugin(pm, "target-value", sdk.PluginTypeStrategy, func() error {
+			<-release
+			return nil
+		})
+		assert.EqualError(t, err, "call to plugin target-value timed out after 10ms")
+	}
+
+	restarts := pm.PluginRestarts()
+	assert.Len(t, restarts, 1)
+	assert.Equal(t, 1, restarts[0].Count)
+}
@@ -12,6 +12,11 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/uuid"
 )
 
+// starvationLimit is the maximum time a policy can wait in a queue before it
+// is picked regardless of its priority. This ensures low priority policies
+// are still evaluated when a queue is saturated by higher priority ones.
+const starvationLimit = 1 * time.Minute
+
 // Broker stores, dedups and control access to policy evaluation requests.
 //
 // A few notes on the inner workings of the broker:
@@ -42,6 +47,11 @@ import (
 //   - the value for the policy ID is the ID of the eval that was enqueued.
 //   - the value for the policy ID is updated if a newer eval for the policy is
 //     enqueued.
+//
+//   - policy IDs are stored as keys in `pendingSince` alongside
+//     `enqueuedPolicies`, with the time the policy was first enqueued as value.
+//   - the value is not updated when a newer eval for the policy is enqueued,
+//     so that a policy's wait time is not reset by its own updates.
 type Broker struct {
 	logger hclog.Logger
 
@@ -66,11 +76,19 @@ type Broker struct {
 	enqueuedEvals    map[string]int
 	enqueuedPolicies map[string]string
 
+	// pendingSince tracks when a policy was first enqueued, and is used to
+	// prevent starvation of low priority policies.
+	pendingSince map[string]time.Time
+
 	// unack tracks evaluations that have not been ack'd yet.
 	unack map[string]*unackEval
 
 	// waiting tracks Dequeue requests that are blocked waiting for work.
 	waiting map[string]chan struct{}
+
+	// acked is set once an evaluation has been ack'd, which indicates the
+	// agent has completed a policy evaluation successfully.
+	acked bool
 }
 
 // unackEval tracks an unacknowledged evaluation along with the Nack timer
@@ -89,6 +107,7 @@ func NewBroker(l hclog.Logger, timeout time.Duration, deliveryLimit int) *Broker
 		pendingEvals:     make(map[string]PendingEvaluations),
 		enqueuedEvals:    make(map[string]int),
 		enqueuedPolicies: make(map[string]string),
+		pendingSince:     make(map[string]time.Time),
 		unack:            make(map[string]*unackEval),
 		waiting:          make(map[string]chan struct{}),
 	}
@@ -134,6 +153,7 @@ func (b *Broker) enqueueLocked(eval *sdk.ScalingEvaluation, token string) {
 	pendingEvalID, ok := b.enqueuedPolicies[eval.Policy.ID]
 	if !ok {
 		b.enqueuedPolicies[eval.Policy.ID] = eval.ID
+		b.pendingSince[eval.Policy.ID] = time.Now()
 	} else if pendingEvalID != eval.ID {
 		logger.Debug("policy already enqueued")
 
@@ -224,13 +244,37 @@ func (b *Broker) findWork(queue string) *sdk.ScalingEvaluation {
 		return nil
 	}
 
-	// Pop heap and update reference.
-	raw := heap.Pop(&pending)
+	// Pick the eval which has been starved the longest if there is one,
+	// otherwise pop the heap which is ordered by priority. Update the
+	// reference once the heap has been modified.
+	var raw interface{}
+	if i := b.starvedEvalIndex(pending); i >= 0 {
+		raw = heap.Remove(&pending, i)
+	} else {
+		raw = heap.Pop(&pending)
+	}
 	b.pendingEvals[queue] = pending
 
 	return raw.(*sdk.ScalingEvaluation)
 }
 
+// starvedEvalIndex returns the index of the pending eval whose policy has been
+// waiting the longest beyond the starvationLimit, or -1 if there is none. The
+// caller must hold the broker lock.
+func (b *Broker) starvedEvalIndex(pending PendingEvaluations) int {
+	idx := -1
+	oldest := time.Now().Add(-starvationLimit)
+
+	for i, eval := range pending {
+		since, ok := b.pendingSince[eval.Policy.ID]
+		if ok && since.Before(oldest) {
+			idx, oldest = i, since
+		}
+	}
+
+	return idx
+}
+
 // waitForWork blocks until queue receives an item or the context is canceled.
 func (b *Broker) waitForWork(ctx context.Context, queue string) (proceed bool) {
 	b.logger.Debug("waiting for eval", "queue", queue)
@@ -278,11 +322,20 @@ func (b *Broker) Ack(evalID, token string) error {
 	delete(b.unack, evalID)
 	delete(b.enqueuedEvals, evalID)
 	delete(b.enqueuedPolicies, unack.Eval.Policy.ID)
+	delete(b.pendingSince, unack.Eval.Policy.ID)
+	b.acked = true
 
 	b.logger.Debug("eval ack'd", "policy_id", unack.Eval.Policy.ID)
 	return nil
 }
 
+// Acked returns whether any evaluation has been ack'd by the broker.
+func (b *Broker) Acked() bool {
+	b.l.RLock()
+	defer b.l.RUnlock()
+	return b.acked
+}
+
 // Nack is used to mark an eval as not completed.
 func (b *Broker) Nack(evalID, token string) error {
 	logger := b.logger.With("eval_id", evalID, "token", token)
@@ -315,6 +368,7 @@ func (b *Broker) Nack(evalID, token string) error {
 
 		delete(b.enqueuedEvals, evalID)
 		delete(b.enqueuedPolicies, unack.Eval.Policy.ID)
+		delete(b.pendingSince, unack.Eval.Policy.ID)
 		return nil
 	}
 
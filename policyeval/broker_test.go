@@ -95,10 +95,12 @@ func TestBroker(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(eval3, e)
 	assert.NotEmpty(token)
+	assert.False(b.Acked())
 
 	// Ack eval3.
 	err = b.Ack(e.ID, token)
 	assert.NoError(err)
+	assert.True(b.Acked())
 
 	// Check if eval2 is next since it's older.
 	e, token, err = b.Dequeue(ctx, "horizontal")
@@ -169,3 +171,56 @@ func TestBroker(t *testing.T) {
 	assert.Empty(token)
 	assert.Nil(err)
 }
+
+func TestBroker_starvation(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBroker(hclog.NewNullLogger(), time.Minute, 2)
+
+	low := &sdk.ScalingEvaluation{
+		ID: "low",
+		Policy: &sdk.ScalingPolicy{
+			ID:       "low",
+			Type:     "horizontal",
+			Priority: sdk.ScalingPolicyPriorityMin,
+		},
+		CreateTime: time.Now(),
+	}
+	high := &sdk.ScalingEvaluation{
+		ID: "high",
+		Policy: &sdk.ScalingPolicy{
+			ID:       "high",
+			Type:     "horizontal",
+			Priority: sdk.ScalingPolicyPriorityMax,
+		},
+		CreateTime: time.Now(),
+	}
+	b.Enqueue(low)
+	b.Enqueue(high)
+
+	// The high priority eval is picked first while the low priority eval has
+	// not been waiting for long.
+	e, token, err := b.Dequeue(context.Background(), "horizontal")
+	assert.NoError(err)
+	assert.Equal(high, e)
+	assert.NoError(b.Ack(e.ID, token))
+
+	// Simulate the low priority policy being starved and enqueue a new high
+	// priority eval. The low priority eval should be picked.
+	b.l.Lock()
+	b.pendingSince[low.Policy.ID] = time.Now().Add(-2 * starvationLimit)
+	b.l.Unlock()
+	b.Enqueue(high)
+
+	e, token, err = b.Dequeue(context.Background(), "horizontal")
+	assert.NoError(err)
+	assert.Equal(low, e)
+	assert.NoError(b.Ack(e.ID, token))
+
+	e, token, err = b.Dequeue(context.Background(), "horizontal")
+	assert.NoError(err)
+	assert.Equal(high, e)
+	assert.NoError(b.Ack(e.ID, token))
+
+	assert.Empty(b.pendingSince)
+}
@@ -0,0 +1,94 @@
+package policyeval
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The failure modes of a policy evaluation. Errors returned by the evaluation
+// pipeline can be matched against these using errors.Is.
+var (
+	// ErrPluginDispense indicates a plugin used by the policy could not be
+	// dispensed, or is not of the expected type.
+	ErrPluginDispense = errors.New("failed to dispense plugin")
+
+	// ErrTargetStatus indicates the status of the policy target could not be
+	// fetched.
+	ErrTargetStatus = errors.New("failed to fetch target status")
+
+	// ErrTargetNotReady indicates the policy target is not ready to be
+	// scaled, such as while a deployment is in progress.
+	ErrTargetNotReady = errors.New("target not ready")
+
+	// ErrAPMQuery indicates the query of a check failed or timed out.
+	ErrAPMQuery = errors.New("failed to query source")
+
+	// ErrStaleMetrics indicates the most recent metric returned by the query
+	// of a check is older than the check's maximum metric age.
+	ErrStaleMetrics = errors.New("metrics are stale")
+
+	// ErrMetricFormula indicates the formula of a check could not be
+	// evaluated using its query results, such as due to a division by zero.
+	ErrMetricFormula = errors.New("failed to evaluate metric formula")
+
+	// ErrMetricTransform indicates the transforms of a check could not be
+	// applied to its metrics.
+	ErrMetricTransform = errors.New("failed to transform metrics")
+
+	// ErrStrategyRun indicates the strategy of a check, or a strategy
+	// chained after it, failed to run.
+	ErrStrategyRun = errors.New("failed to execute strategy")
+
+	// ErrTargetScale indicates the policy target failed to scale.
+	ErrTargetScale = errors.New("failed to scale target")
+)
+
+// evalErrorTypes are the names of the failure modes, used to label telemetry.
+var evalErrorTypes = []struct {
+	err  error
+	name string
+}{
+	{ErrPluginDispense, "plugin_dispense"},
+	{ErrTargetStatus, "target_status"},
+	{ErrTargetNotReady, "target_not_ready"},
+	{ErrAPMQuery, "apm_query"},
+	{ErrStaleMetrics, "stale_metrics"},
+	{ErrMetricFormula, "metric_formula"},
+	{ErrMetricTransform, "metric_transform"},
+	{ErrStrategyRun, "strategy_run"},
+	{ErrTargetScale, "target_scale"},
+}
+
+// EvalError is an error which occurred during a policy evaluation. Its Kind
+// is one of the failure mode errors, so errors.Is reports the failure mode
+// while Error keeps the detailed message.
+type EvalError struct {
+	Kind error
+	Err  error
+}
+
+// newEvalError returns an EvalError of the passed kind with a message built
+// from the format and arguments, which can wrap another error using %w.
+func newEvalError(kind error, format string, a ...interface{}) error {
+	return &EvalError{Kind: kind, Err: fmt.Errorf(format, a...)}
+}
+
+// Error satisfies the error interface.
+func (e *EvalError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *EvalError) Unwrap() error { return e.Err }
+
+// Is returns true if target is the failure mode of the error.
+func (e *EvalError) Is(target error) bool { return target == e.Kind }
+
+// evalErrorType returns the name of the failure mode of err, or "unknown" if
+// it is not an EvalError.
+func evalErrorType(err error) string {
+	for _, t := range evalErrorTypes {
+		if errors.Is(err, t.err) {
+			return t.name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,74 @@
+package policyeval
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := newEvalError(ErrAPMQuery, "failed to query source: %w", cause)
+
+	// The message is kept while the failure mode and cause can be matched.
+	assert.Equal(t, "failed to query source: connection refused", err.Error())
+	assert.True(t, errors.Is(err, ErrAPMQuery))
+	assert.True(t, errors.Is(err, cause))
+	assert.False(t, errors.Is(err, ErrStrategyRun))
+
+	// The failure mode is kept when the error is wrapped.
+	wrapped := fmt.Errorf("failed to execute strategy chain: %w", newEvalError(ErrStrategyRun, "strategy failed"))
+	assert.True(t, errors.Is(wrapped, ErrStrategyRun))
+}
+
+func Test_evalErrorType(t *testing.T) {
+	testCases := []struct {
+		inputErr       error
+		expectedOutput string
+		name           string
+	}{
+		{
+			inputErr:       ErrTargetNotReady,
+			expectedOutput: "target_not_ready",
+			name:           "sentinel error",
+		},
+		{
+			inputErr:       newEvalError(ErrPluginDispense, `"%s" is not a target plugin`, "nomad"),
+			expectedOutput: "plugin_dispense",
+			name:           "eval error",
+		},
+		{
+			inputErr:       fmt.Errorf("wrapped: %w", newEvalError(ErrTargetScale, "failed")),
+			expectedOutput: "target_scale",
+			name:           "wrapped eval error",
+		},
+		{
+			inputErr:       newEvalError(ErrStaleMetrics, "stale"),
+			expectedOutput: "stale_metrics",
+			name:           "stale metrics",
+		},
+		{
+			inputErr:       newEvalError(ErrMetricFormula, "division by zero"),
+			expectedOutput: "metric_formula",
+			name:           "metric formula",
+		},
+		{
+			inputErr:       newEvalError(ErrMetricTransform, "unknown transform"),
+			expectedOutput: "metric_transform",
+			name:           "metric transform",
+		},
+		{
+			inputErr:       errors.New("failed to record scaling action"),
+			expectedOutput: "unknown",
+			name:           "other error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, evalErrorType(tc.inputErr), tc.name)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package policyeval
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// MetricWindows stores the most recent query results of policy checks which
+// configure a metric window, so the results can be aggregated before being
+// passed to the strategy. It also stores the moving average of checks which
+// configure a metric smoothing. It is shared by all workers since any worker
+// can evaluate a policy.
+type MetricWindows struct {
+	lock     sync.Mutex
+	windows  map[metricWindowKey]*metricWindow
+	averages map[metricWindowKey]float64
+}
+
+// metricWindowKey identifies the check a metric window belongs to.
+type metricWindowKey struct {
+	policyID string
+	check    string
+}
+
+// metricWindow is a ring buffer of query results.
+type metricWindow struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+// NewMetricWindows returns a new MetricWindows instance.
+func NewMetricWindows() *MetricWindows {
+	return &MetricWindows{
+		windows:  make(map[metricWindowKey]*metricWindow),
+		averages: make(map[metricWindowKey]float64),
+	}
+}
+
+// Smooth records the latest query result of the check within its
+// exponentially weighted moving average, weighted by the check's metric
+// smoothing alpha, and returns the average. The first result recorded for
+// the check seeds the average.
+func (m *MetricWindows) Smooth(policyID string, check *sdk.ScalingPolicyCheck, value float64) float64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := metricWindowKey{policyID: policyID, check: check.Name}
+
+	if avg, ok := m.averages[key]; ok {
+		value = check.MetricSmoothingAlpha*value + (1-check.MetricSmoothingAlpha)*avg
+	}
+	m.averages[key] = value
+
+	return value
+}
+
+// Add records the latest query result of the check and returns the aggregate
+// of the results within the check's metric window, along with the number of
+// results used. If the size of the window changes, previous results are
+// discarded.
+func (m *MetricWindows) Add(policyID string, check *sdk.ScalingPolicyCheck, value float64) (float64, int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := metricWindowKey{policyID: policyID, check: check.Name}
+
+	w, ok := m.windows[key]
+	if !ok || len(w.values) != check.MetricWindow {
+		w = &metricWindow{values: make([]float64, check.MetricWindow)}
+		m.windows[key] = w
+	}
+
+	w.values[w.next] = value
+	w.next = (w.next + 1) % len(w.values)
+	if w.next == 0 {
+		w.full = true
+	}
+
+	values := w.values[:w.next]
+	if w.full {
+		values = w.values
+	}
+
+	return aggregateMetrics(values, check.MetricAggregation), len(values)
+}
+
+// aggregateMetrics aggregates the values using the named aggregation. The
+// average is used if the aggregation is not recognised.
+func aggregateMetrics(values []float64, aggregation string) float64 {
+	switch aggregation {
+	case sdk.MetricAggregationMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			max = math.Max(max, v)
+		}
+		return max
+
+	case sdk.MetricAggregationMin:
+		min := values[0]
+		for _, v := range values[1:] {
+			min = math.Min(min, v)
+		}
+		return min
+
+	case sdk.MetricAggregationSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+
+	case sdk.MetricAggregationP95:
+		sorted := make([]float64, len(values))
+		copy(sorted, values)
+		sort.Float64s(sorted)
+
+		// Use the nearest-rank method so the result is always one of the
+		// recorded values.
+		rank := int(math.Ceil(0.95 * float64(len(sorted))))
+		return sorted[rank-1]
+
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	}
+}
@@ -0,0 +1,99 @@
+package policyeval
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricWindows_Add(t *testing.T) {
+	testCases := []struct {
+		inputAggregation string
+		inputValues      []float64
+		expectedValues   []float64
+		name             string
+	}{
+		{
+			inputAggregation: sdk.MetricAggregationAvg,
+			inputValues:      []float64{10, 20, 30, 40},
+			expectedValues:   []float64{10, 15, 20, 30},
+			name:             "average",
+		},
+		{
+			inputAggregation: sdk.MetricAggregationMax,
+			inputValues:      []float64{10, 40, 20, 30, 10},
+			expectedValues:   []float64{10, 40, 40, 40, 30},
+			name:             "max",
+		},
+		{
+			inputAggregation: sdk.MetricAggregationP95,
+			inputValues:      []float64{50, 10, 20, 30, 40},
+			expectedValues:   []float64{50, 50, 50, 30, 40},
+			name:             "p95",
+		},
+		{
+			inputAggregation: sdk.MetricAggregationSum,
+			inputValues:      []float64{10, 20, 30, 40},
+			expectedValues:   []float64{10, 30, 60, 90},
+			name:             "sum",
+		},
+		{
+			inputAggregation: sdk.MetricAggregationMin,
+			inputValues:      []float64{30, 10, 20, 40, 50},
+			expectedValues:   []float64{30, 10, 10, 10, 20},
+			name:             "min",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mw := NewMetricWindows()
+			check := &sdk.ScalingPolicyCheck{Name: "check", MetricWindow: 3, MetricAggregation: tc.inputAggregation}
+
+			for i, v := range tc.inputValues {
+				value, samples := mw.Add("policy", check, v)
+				assert.Equal(t, tc.expectedValues[i], value, "value %d", i)
+				assert.LessOrEqual(t, samples, check.MetricWindow)
+			}
+		})
+	}
+}
+
+func TestMetricWindows_Add_resize(t *testing.T) {
+	mw := NewMetricWindows()
+	check := &sdk.ScalingPolicyCheck{Name: "check", MetricWindow: 2, MetricAggregation: sdk.MetricAggregationAvg}
+
+	mw.Add("policy", check, 10)
+	value, samples := mw.Add("policy", check, 20)
+	assert.Equal(t, 15.0, value)
+	assert.Equal(t, 2, samples)
+
+	// Changing the window size discards the previous results.
+	check.MetricWindow = 3
+	value, samples = mw.Add("policy", check, 30)
+	assert.Equal(t, 30.0, value)
+	assert.Equal(t, 1, samples)
+
+	// Windows are tracked separately for each policy.
+	value, samples = mw.Add("other-policy", check, 5)
+	assert.Equal(t, 5.0, value)
+	assert.Equal(t, 1, samples)
+}
+
+func TestMetricWindows_Smooth(t *testing.T) {
+	mw := NewMetricWindows()
+	check := &sdk.ScalingPolicyCheck{Name: "check", MetricSmoothing: sdk.MetricSmoothingEWMA, MetricSmoothingAlpha: 0.5}
+
+	// The first result seeds the moving average.
+	assert.Equal(t, 10.0, mw.Smooth("policy", check, 10))
+	assert.Equal(t, 20.0, mw.Smooth("policy", check, 30))
+	assert.Equal(t, 15.0, mw.Smooth("policy", check, 10))
+
+	// Higher alphas give more weight to the latest result.
+	check.MetricSmoothingAlpha = 0.75
+	assert.Equal(t, 36.0, mw.Smooth("policy", check, 43))
+
+	// Moving averages are tracked separately for each policy.
+	assert.Equal(t, 5.0, mw.Smooth("other-policy", check, 5))
+}
@@ -0,0 +1,138 @@
+package policyeval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pauseFileName is the name of the file storing the pause state within the
+// configured directory.
+const pauseFileName = "pause.json"
+
+// PauseStatus details whether scaling is paused.
+type PauseStatus struct {
+
+	// Paused is true if scaling actions are not executed.
+	Paused bool
+
+	// Since is the time at which scaling was last paused or resumed.
+	Since time.Time
+}
+
+// Pause is a switch which pauses the scaling actions of all workers, so
+// operators can stop the autoscaler from modifying targets during maintenance
+// or incidents without stopping the agent. Policies are still evaluated while
+// paused, so the actions which would have been executed are logged. It must
+// be shared by all workers.
+//
+// A nil Pause is never paused.
+type Pause struct {
+	lock   sync.RWMutex
+	status PauseStatus
+
+	// path is the file the state is persisted to, so the agent stays paused
+	// when restarted. It is empty if the state is not persisted.
+	path string
+}
+
+// NewPause returns a new Pause. If dir is not empty, the state is persisted
+// within it and the state persisted by a previous agent is restored.
+func NewPause(dir string) (*Pause, error) {
+	p := &Pause{status: PauseStatus{Since: time.Now()}}
+	if dir == "" {
+		return p, nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create pause state directory: %v", err)
+	}
+	p.path = filepath.Join(dir, pauseFileName)
+
+	data, err := ioutil.ReadFile(p.path)
+	switch {
+	case os.IsNotExist(err):
+		return p, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read pause state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &p.status); err != nil {
+		return nil, fmt.Errorf("failed to decode pause state: %v", err)
+	}
+	return p, nil
+}
+
+// Paused returns whether scaling is paused.
+func (p *Pause) Paused() bool {
+	if p == nil {
+		return false
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.status.Paused
+}
+
+// Status returns the pause status.
+func (p *Pause) Status() PauseStatus {
+	if p == nil {
+		return PauseStatus{}
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.status
+}
+
+// Set pauses or resumes scaling and persists the new state, if configured.
+// Setting the current state again does not modify it. A nil Pause can't be
+// modified, so an error is returned.
+func (p *Pause) Set(paused bool) (PauseStatus, error) {
+	if p == nil {
+		return PauseStatus{}, errors.New("pause is not configured")
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.status.Paused == paused {
+		return p.status, nil
+	}
+
+	status := PauseStatus{Paused: paused, Since: time.Now()}
+	if err := p.persist(status); err != nil {
+		return p.status, err
+	}
+
+	p.status = status
+	return status, nil
+}
+
+// persist writes the status to the state file, replacing it atomically so a
+// crash can't leave a partially written file. The lock must be held when
+// calling it.
+func (p *Pause) persist(status PauseStatus) error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(&status)
+	if err != nil {
+		return err
+	}
+
+	tmp := p.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write pause state: %v", err)
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		return fmt.Errorf("failed to write pause state: %v", err)
+	}
+	return nil
+}
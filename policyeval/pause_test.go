@@ -0,0 +1,68 @@
+package policyeval
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause_nil(t *testing.T) {
+	// A nil pause is never paused.
+	var p *Pause
+	assert.False(t, p.Paused())
+	assert.Equal(t, PauseStatus{}, p.Status())
+
+	// A nil pause can't be modified.
+	status, err := p.Set(true)
+	assert.Error(t, err)
+	assert.Equal(t, PauseStatus{}, status)
+	assert.False(t, p.Paused())
+}
+
+func TestPause_Set(t *testing.T) {
+	p, err := NewPause("")
+	require.NoError(t, err)
+	assert.False(t, p.Paused())
+
+	status, err := p.Set(true)
+	require.NoError(t, err)
+	assert.True(t, status.Paused)
+	assert.True(t, p.Paused())
+
+	// Pausing again does not modify the time scaling was paused.
+	again, err := p.Set(true)
+	require.NoError(t, err)
+	assert.Equal(t, status, again)
+
+	status, err = p.Set(false)
+	require.NoError(t, err)
+	assert.False(t, status.Paused)
+	assert.False(t, p.Paused())
+}
+
+func TestPause_persisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pause")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p, err := NewPause(dir)
+	require.NoError(t, err)
+	status, err := p.Set(true)
+	require.NoError(t, err)
+
+	// The pause state is restored by a restarted agent.
+	restored, err := NewPause(dir)
+	require.NoError(t, err)
+	assert.True(t, restored.Paused())
+	assert.True(t, status.Since.Equal(restored.Status().Since))
+
+	_, err = restored.Set(false)
+	require.NoError(t, err)
+
+	restored, err = NewPause(dir)
+	require.NoError(t, err)
+	assert.False(t, restored.Paused())
+}
@@ -0,0 +1,93 @@
+package policyeval
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// aggregateSeries combines multiple metric series into one by aggregating the
+// values at each position using the named aggregation. Series are aligned on
+// their most recent data point, since queries run at the same time but may
+// return a different number of points, and the result is truncated to the
+// length of the shortest series. The timestamp of each aggregated point is the
+// latest of the aggregated points.
+func aggregateSeries(series []sdk.TimestampedMetrics, aggregation string) sdk.TimestampedMetrics {
+	if len(series) == 0 {
+		return nil
+	}
+
+	length := -1
+	for _, s := range series {
+		sort.Sort(s)
+		if length == -1 || len(s) < length {
+			length = len(s)
+		}
+	}
+	if length == 0 {
+		return nil
+	}
+
+	out := make(sdk.TimestampedMetrics, length)
+	values := make([]float64, len(series))
+
+	for i := 0; i < length; i++ {
+		var point sdk.TimestampedMetric
+		for j, s := range series {
+			m := s[len(s)-length+i]
+			values[j] = m.Value
+			if m.Timestamp.After(point.Timestamp) {
+				point.Timestamp = m.Timestamp
+			}
+		}
+		point.Value = aggregateMetrics(values, aggregation)
+		out[i] = point
+	}
+
+	return out
+}
+
+// evaluateFormulaSeries combines the named metric series into one by
+// evaluating the formula with the values at each position. Series are aligned
+// as they are by aggregateSeries. An error is returned if the formula can't be
+// evaluated at any position, such as due to a division by zero.
+func evaluateFormulaSeries(series map[string]sdk.TimestampedMetrics, formula *policy.MetricFormula) (sdk.TimestampedMetrics, error) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	length := -1
+	for _, s := range series {
+		sort.Sort(s)
+		if length == -1 || len(s) < length {
+			length = len(s)
+		}
+	}
+	if length == 0 {
+		return nil, nil
+	}
+
+	out := make(sdk.TimestampedMetrics, length)
+	values := make(map[string]float64, len(series))
+
+	for i := 0; i < length; i++ {
+		var point sdk.TimestampedMetric
+		for name, s := range series {
+			m := s[len(s)-length+i]
+			values[name] = m.Value
+			if m.Timestamp.After(point.Timestamp) {
+				point.Timestamp = m.Timestamp
+			}
+		}
+
+		v, err := formula.Evaluate(values)
+		if err != nil {
+			return nil, err
+		}
+		point.Value = v
+		out[i] = point
+	}
+
+	return out, nil
+}
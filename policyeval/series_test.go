@@ -0,0 +1,156 @@
+package policyeval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_aggregateSeries(t *testing.T) {
+	t1 := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	testCases := []struct {
+		inputSeries      []sdk.TimestampedMetrics
+		inputAggregation string
+		expectedMetrics  sdk.TimestampedMetrics
+		name             string
+	}{
+		{
+			inputSeries:      nil,
+			inputAggregation: sdk.MetricAggregationSum,
+			expectedMetrics:  nil,
+			name:             "no series",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 2}},
+				{},
+			},
+			inputAggregation: sdk.MetricAggregationSum,
+			expectedMetrics:  nil,
+			name:             "empty series",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 2}},
+				{{Timestamp: t1, Value: 10}, {Timestamp: t2, Value: 20}},
+				{{Timestamp: t1, Value: 100}, {Timestamp: t2, Value: 200}},
+			},
+			inputAggregation: sdk.MetricAggregationSum,
+			expectedMetrics:  sdk.TimestampedMetrics{{Timestamp: t1, Value: 111}, {Timestamp: t2, Value: 222}},
+			name:             "sum",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 30}},
+				{{Timestamp: t1, Value: 3}, {Timestamp: t2, Value: 10}},
+			},
+			inputAggregation: sdk.MetricAggregationAvg,
+			expectedMetrics:  sdk.TimestampedMetrics{{Timestamp: t1, Value: 2}, {Timestamp: t2, Value: 20}},
+			name:             "avg",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 30}},
+				{{Timestamp: t1, Value: 3}, {Timestamp: t2, Value: 10}},
+			},
+			inputAggregation: sdk.MetricAggregationMax,
+			expectedMetrics:  sdk.TimestampedMetrics{{Timestamp: t1, Value: 3}, {Timestamp: t2, Value: 30}},
+			name:             "max",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 30}},
+				{{Timestamp: t1, Value: 3}, {Timestamp: t2, Value: 10}},
+			},
+			inputAggregation: sdk.MetricAggregationMin,
+			expectedMetrics:  sdk.TimestampedMetrics{{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 10}},
+			name:             "min",
+		},
+		{
+			inputSeries: []sdk.TimestampedMetrics{
+				{{Timestamp: t3, Value: 3}, {Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 2}},
+				{{Timestamp: t2, Value: 20}, {Timestamp: t3.Add(-time.Second), Value: 30}},
+			},
+			inputAggregation: sdk.MetricAggregationSum,
+			expectedMetrics:  sdk.TimestampedMetrics{{Timestamp: t2, Value: 22}, {Timestamp: t3, Value: 33}},
+			name:             "unaligned series",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMetrics, aggregateSeries(tc.inputSeries, tc.inputAggregation), tc.name)
+		})
+	}
+}
+
+func Test_evaluateFormulaSeries(t *testing.T) {
+	t1 := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	formula, err := policy.ParseMetricFormula("(query / 100) + (cpu / 0.5)", []string{"query", "cpu"})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		inputSeries     map[string]sdk.TimestampedMetrics
+		expectedMetrics sdk.TimestampedMetrics
+		expectedError   error
+		name            string
+	}{
+		{
+			inputSeries:     nil,
+			expectedMetrics: nil,
+			name:            "no series",
+		},
+		{
+			inputSeries: map[string]sdk.TimestampedMetrics{
+				"query": {{Timestamp: t1, Value: 100}},
+				"cpu":   {},
+			},
+			expectedMetrics: nil,
+			name:            "empty series",
+		},
+		{
+			inputSeries: map[string]sdk.TimestampedMetrics{
+				"query": {{Timestamp: t1, Value: 100}, {Timestamp: t2, Value: 200}},
+				"cpu":   {{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 2}},
+			},
+			expectedMetrics: sdk.TimestampedMetrics{{Timestamp: t1, Value: 3}, {Timestamp: t2, Value: 6}},
+			name:            "aligned series",
+		},
+		{
+			inputSeries: map[string]sdk.TimestampedMetrics{
+				"query": {{Timestamp: t3, Value: 300}, {Timestamp: t1, Value: 100}, {Timestamp: t2, Value: 200}},
+				"cpu":   {{Timestamp: t2, Value: 1}, {Timestamp: t3.Add(-time.Second), Value: 2}},
+			},
+			expectedMetrics: sdk.TimestampedMetrics{{Timestamp: t2, Value: 4}, {Timestamp: t3, Value: 7}},
+			name:            "unaligned series",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualMetrics, err := evaluateFormulaSeries(tc.inputSeries, formula)
+			assert.Equal(t, tc.expectedError, err, tc.name)
+			assert.Equal(t, tc.expectedMetrics, actualMetrics, tc.name)
+		})
+	}
+
+	// A division by zero in any point fails the evaluation rather than
+	// passing an infinite metric to the strategy.
+	divide, err := policy.ParseMetricFormula("query / cpu", []string{"query", "cpu"})
+	assert.NoError(t, err)
+
+	_, err = evaluateFormulaSeries(map[string]sdk.TimestampedMetrics{
+		"query": {{Timestamp: t1, Value: 100}, {Timestamp: t2, Value: 200}},
+		"cpu":   {{Timestamp: t1, Value: 1}, {Timestamp: t2, Value: 0}},
+	}, divide)
+	assert.EqualError(t, err, `formula "query / cpu" result is not a finite number, check for a division by zero`)
+}
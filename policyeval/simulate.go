@@ -0,0 +1,156 @@
+package policyeval
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/policy"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// SimulationStep is the result of a single simulated policy evaluation.
+type SimulationStep struct {
+
+	// Time is the simulated time of the evaluation. The check queries use it
+	// as the end of their query window.
+	Time time.Time `json:"time"`
+
+	// Count is the target count after the evaluation.
+	Count int64 `json:"count"`
+
+	// Direction is the direction of the winning action, if any.
+	Direction string `json:"direction"`
+
+	// Check is the name of the check whose action won the evaluation.
+	Check string `json:"check,omitempty"`
+
+	// Reason is the reason of the winning action.
+	Reason string `json:"reason,omitempty"`
+
+	// InCooldown indicates the winning action was skipped because the
+	// policy was in cooldown.
+	InCooldown bool `json:"in_cooldown"`
+}
+
+// Simulator replays the evaluations of a policy over a historical time range,
+// allowing operators to tune strategy parameters against past metrics. The
+// checks are run using the same logic as the workers, with the check queries
+// ending at the simulated time, but the target is never scaled. Instead the
+// count of the target is tracked by the simulator.
+//
+// The APMs used by the policy checks must be able to return historical
+// metrics for the simulation to be meaningful.
+type Simulator struct {
+	logger        hclog.Logger
+	pluginManager *manager.PluginManager
+}
+
+// NewSimulator returns a new Simulator which uses the plugins of the passed
+// plugin manager.
+func NewSimulator(l hclog.Logger, pm *manager.PluginManager) *Simulator {
+	return &Simulator{
+		logger:        l.Named("simulator"),
+		pluginManager: pm,
+	}
+}
+
+// Run simulates the evaluation of the policy at each evaluation interval
+// between from and to, starting with the target at the passed count. It
+// returns the result of every evaluation. Checks which fail are logged and
+// skipped, as they are by the workers.
+//
+// The cooldown of the policy is enforced after each simulated scaling action,
+// while the scale-in stabilization window and startup grace period are not
+// simulated.
+func (s *Simulator) Run(ctx context.Context, p *sdk.ScalingPolicy, from, to time.Time, count int64) ([]*SimulationStep, error) {
+	if p.EvaluationInterval <= 0 {
+		return nil, fmt.Errorf("policy evaluation interval must be positive")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("simulation start must be before its end")
+	}
+
+	var (
+		steps         []*SimulationStep
+		cooldownUntil time.Time
+	)
+	metricWindows := NewMetricWindows()
+
+	for now := from; !now.After(to); now = now.Add(p.EvaluationInterval) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		step := &SimulationStep{Time: now, Count: count, Direction: sdk.ScaleDirection(sdk.ScaleDirectionNone).String()}
+		steps = append(steps, step)
+
+		handler, action := s.evaluate(ctx, p, now, count, metricWindows)
+		if action == nil || action.Direction == sdk.ScaleDirectionNone {
+			continue
+		}
+
+		step.Direction = action.Direction.String()
+		step.Reason = action.Reason
+		if handler != nil {
+			step.Check = handler.checkEval.Check.Name
+		}
+
+		if now.Before(cooldownUntil) {
+			step.InCooldown = true
+			continue
+		}
+
+		step.Count = action.Count
+		cooldownUntil = now.Add(p.CooldownFor(count, action.Count))
+		count = action.Count
+	}
+
+	return steps, nil
+}
+
+// evaluate runs the policy checks at the simulated time and reconciles their
+// results in the same manner as BaseWorker.handlePolicy.
+func (s *Simulator) evaluate(ctx context.Context, p *sdk.ScalingPolicy, now time.Time, count int64,
+	mw *MetricWindows) (*checkHandler, *sdk.ScalingAction) {
+
+	logger := s.logger.With("policy_id", p.ID, "time", now)
+	status := &sdk.TargetStatus{Ready: true, Count: count}
+	eval := sdk.NewScalingEvaluation(p, status)
+
+	var (
+		results       []checkResult
+		enabledChecks int
+	)
+
+	for _, checkEval := range eval.CheckEvaluations {
+		if checkEval.Check.Disabled {
+			continue
+		}
+		enabledChecks++
+
+		handler := newCheckHandler(logger, p, checkEval, s.pluginManager, 0, mw, 0)
+		handler.now = func() time.Time { return now }
+
+		action, err := handler.start(ctx, status)
+		if err != nil {
+			logger.Warn("failed to run check", "check", checkEval.Check.Name, "error", err)
+			continue
+		}
+		results = append(results, checkResult{handler: handler, action: action})
+	}
+
+	// If all checks are disabled the policy limits are still enforced.
+	if enabledChecks == 0 {
+		limits := policy.EffectiveLimits(p)
+		action := minMaxAction(count, limits.Min.Value, limits.Max.Value)
+		if action != nil {
+			action.Canonicalize()
+		}
+		return nil, action
+	}
+
+	return reconcileCheckResults(results)
+}
@@ -0,0 +1,72 @@
+package policyeval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulator_Run(t *testing.T) {
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"apm":      {{Name: "mock-apm", Driver: "mock-apm"}},
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	p := &sdk.ScalingPolicy{
+		ID:                 "test",
+		Min:                1,
+		Max:                10,
+		Cooldown:           2 * time.Minute,
+		EvaluationInterval: time.Minute,
+		Checks: []*sdk.ScalingPolicyCheck{
+			{
+				Name:   "check",
+				Source: "mock-apm",
+				Query:  "sequence:20,20,20,5,5,5",
+				Strategy: &sdk.ScalingPolicyStrategy{
+					Name:   "target-value",
+					Config: map[string]string{"target": "10"},
+				},
+			},
+		},
+	}
+
+	from := time.Date(2020, 6, 16, 12, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+
+	steps, err := NewSimulator(hclog.NewNullLogger(), pm).Run(context.Background(), p, from, to, 2)
+	assert.NoError(t, err)
+	assert.Len(t, steps, 6)
+
+	expected := []struct {
+		count      int64
+		direction  string
+		inCooldown bool
+	}{
+		{count: 4, direction: "up"},
+		{count: 4, direction: "up", inCooldown: true},
+		{count: 8, direction: "up"},
+		{count: 8, direction: "down", inCooldown: true},
+		{count: 4, direction: "down"},
+		{count: 4, direction: "down", inCooldown: true},
+	}
+	for i, e := range expected {
+		assert.Equal(t, from.Add(time.Duration(i)*time.Minute), steps[i].Time, "step %d", i)
+		assert.Equal(t, e.count, steps[i].Count, "step %d", i)
+		assert.Equal(t, e.direction, steps[i].Direction, "step %d", i)
+		assert.Equal(t, e.inCooldown, steps[i].InCooldown, "step %d", i)
+		assert.Equal(t, "check", steps[i].Check, "step %d", i)
+	}
+
+	// Invalid ranges are rejected.
+	_, err = NewSimulator(hclog.NewNullLogger(), pm).Run(context.Background(), p, to, from, 2)
+	assert.Error(t, err)
+}
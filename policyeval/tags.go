@@ -0,0 +1,86 @@
+package policyeval
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+)
+
+// maxPolicyTagValues is the number of distinct values a policy tag can have
+// across all policies before a warning is logged. Each distinct value creates
+// new telemetry series, so a high number usually indicates the tag contains
+// unique values, such as IDs, which are not suited to be labels.
+const maxPolicyTagValues = 100
+
+// policyTagCardinality tracks the distinct values of the policy tags seen by
+// all workers.
+var policyTagCardinality = newTagCardinalityGuard(maxPolicyTagValues)
+
+// policyLabels returns the passed telemetry labels with the policy tags
+// appended, sorted by tag name so the labels of a policy are stable.
+func policyLabels(p *sdk.ScalingPolicy, labels ...metrics.Label) []metrics.Label {
+	if len(p.Tags) == 0 {
+		return labels
+	}
+
+	names := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	out := make([]metrics.Label, 0, len(labels)+len(names))
+	out = append(out, labels...)
+	for _, k := range names {
+		out = append(out, metrics.Label{Name: k, Value: p.Tags[k]})
+	}
+	return out
+}
+
+// tagCardinalityGuard counts the distinct values of each tag and warns once
+// per tag when the count exceeds the limit. Values are only counted up to the
+// limit to bound the memory used by tags with unique values.
+type tagCardinalityGuard struct {
+	lock   sync.Mutex
+	limit  int
+	values map[string]map[string]struct{}
+	warned map[string]bool
+}
+
+func newTagCardinalityGuard(limit int) *tagCardinalityGuard {
+	return &tagCardinalityGuard{
+		limit:  limit,
+		values: make(map[string]map[string]struct{}),
+		warned: make(map[string]bool),
+	}
+}
+
+// observe records the values of tags and logs a warning for each tag which
+// has exceeded the limit of distinct values for the first time.
+func (g *tagCardinalityGuard) observe(logger hclog.Logger, tags map[string]string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for k, v := range tags {
+		if g.warned[k] {
+			continue
+		}
+
+		values, ok := g.values[k]
+		if !ok {
+			values = make(map[string]struct{})
+			g.values[k] = values
+		}
+		values[v] = struct{}{}
+
+		if len(values) > g.limit {
+			logger.Warn("policy tag has a high number of distinct values, which increases telemetry cardinality",
+				"tag", k, "limit", g.limit)
+			g.warned[k] = true
+			delete(g.values, k)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package policyeval
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_policyLabels(t *testing.T) {
+	testCases := []struct {
+		inputPolicy    *sdk.ScalingPolicy
+		inputLabels    []metrics.Label
+		expectedLabels []metrics.Label
+		name           string
+	}{
+		{
+			inputPolicy:    &sdk.ScalingPolicy{ID: "id"},
+			inputLabels:    []metrics.Label{{Name: "policy_id", Value: "id"}},
+			expectedLabels: []metrics.Label{{Name: "policy_id", Value: "id"}},
+			name:           "no tags",
+		},
+		{
+			inputPolicy: &sdk.ScalingPolicy{
+				ID:   "id",
+				Tags: map[string]string{"tier": "web", "team": "infra"},
+			},
+			inputLabels: []metrics.Label{{Name: "policy_id", Value: "id"}},
+			expectedLabels: []metrics.Label{
+				{Name: "policy_id", Value: "id"},
+				{Name: "team", Value: "infra"},
+				{Name: "tier", Value: "web"},
+			},
+			name: "tags sorted after labels",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLabels, policyLabels(tc.inputPolicy, tc.inputLabels...), tc.name)
+		})
+	}
+}
+
+func Test_tagCardinalityGuard(t *testing.T) {
+	var buf bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &buf})
+	g := newTagCardinalityGuard(3)
+
+	// Repeated values don't count towards the limit.
+	for i := 0; i < 10; i++ {
+		g.observe(logger, map[string]string{"team": "infra", "tier": fmt.Sprintf("tier-%d", i%3)})
+	}
+	assert.Empty(t, buf.String())
+
+	// Exceeding the limit warns once per tag.
+	for i := 0; i < 10; i++ {
+		g.observe(logger, map[string]string{"team": fmt.Sprintf("team-%d", i)})
+	}
+	assert.Equal(t, 1, strings.Count(buf.String(), "policy tag has a high number of distinct values"))
+	assert.Contains(t, buf.String(), "tag=team")
+	assert.NotContains(t, buf.String(), "tag=tier")
+}
@@ -0,0 +1,32 @@
+package policyeval
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of policy evaluations. It uses the global tracer
+// provider, which discards spans unless the agent has configured tracing.
+var tracer = otel.Tracer("github.com/hashicorp/nomad-autoscaler/policyeval")
+
+// startPhaseSpan starts a span for a phase of a policy evaluation as a child
+// of the span within ctx.
+func startPhaseSpan(ctx context.Context, phase, pluginName, policyID string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, phase, trace.WithAttributes(
+		attribute.String("plugin_name", pluginName),
+		attribute.String("policy_id", policyID),
+	))
+}
+
+// endSpan records err on span, if not nil, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
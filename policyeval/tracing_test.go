@@ -0,0 +1,67 @@
+package policyeval
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCheckHandler_tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	pm := manager.NewPluginManager(hclog.NewNullLogger(), "", map[string][]*config.Plugin{
+		"strategy": {{Name: "target-value", Driver: "target-value"}},
+	})
+	assert.NoError(t, pm.Load())
+	defer pm.KillPlugins()
+
+	action := &sdk.ScalingAction{Count: 2, Direction: sdk.ScaleDirectionUp}
+	action.Canonicalize()
+	checkEval := &sdk.ScalingCheckEvaluation{
+		Check: &sdk.ScalingPolicyCheck{
+			Name:     "check",
+			Strategy: &sdk.ScalingPolicyStrategy{Name: "target-value", Config: map[string]string{"target": "10"}},
+			Chain: []*sdk.ScalingPolicyStrategy{
+				{Name: "target-value", Config: map[string]string{"target": "5"}},
+			},
+		},
+		Metrics: sdk.TimestampedMetrics{{Value: 20}},
+		Action:  action,
+	}
+
+	ctx, parent := tracer.Start(context.Background(), "policy_eval")
+	h := newCheckHandler(hclog.NewNullLogger(), &sdk.ScalingPolicy{ID: "id"}, checkEval, pm, 0, nil, 0)
+	assert.NoError(t, h.runStrategyChain(ctx, 1))
+	endSpan(parent, errors.New("failed"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	// The strategy span is a child of the evaluation span.
+	assert.Equal(t, evalPhaseStrategyRun, spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("plugin_name", "target-value"),
+		attribute.String("policy_id", "id"),
+	}, spans[0].Attributes())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	// Errors are recorded on the span.
+	assert.Equal(t, "policy_eval", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "failed", spans[1].Status().Description)
+}
@@ -0,0 +1,271 @@
+package policyeval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/uuid"
+)
+
+// walFileName is the name of the write-ahead log file within the configured
+// directory.
+const walFileName = "actions.wal"
+
+// WALEntry is a scaling action recorded in the write-ahead log.
+type WALEntry struct {
+	ID         string
+	PolicyID   string
+	Target     *sdk.ScalingPolicyTarget `json:",omitempty"`
+	Count      int64
+	Direction  sdk.ScaleDirection
+	CreateTime time.Time
+	Complete   bool
+}
+
+// WAL is an on-disk write-ahead log of in-flight scaling actions. An entry is
+// written before the target is asked to scale and marked complete once the
+// target has responded. Any entries which are not complete when the agent
+// starts indicate the agent stopped while a scaling action was in-flight.
+//
+// A nil WAL is valid and performs no operations, which allows callers to use
+// it without checking whether it has been enabled.
+type WAL struct {
+	path       string
+	maxEntries int
+
+	// lock is used to synchronize access to the fields below.
+	lock    sync.Mutex
+	file    *os.File
+	pending map[string]*WALEntry
+
+	// numRecords is the number of records in the file, and is used to trigger
+	// a compaction once it exceeds maxEntries.
+	numRecords int
+}
+
+// OpenWAL opens, or creates, the write-ahead log within the passed directory.
+// The log is compacted once it holds more than maxEntries records, so only
+// entries which are still pending are kept.
+func OpenWAL(dir string, maxEntries int) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create WAL directory: %v", err)
+	}
+
+	w := &WAL{
+		path:       filepath.Join(dir, walFileName),
+		maxEntries: maxEntries,
+		pending:    make(map[string]*WALEntry),
+	}
+
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+
+	// Compact on open so the file only contains pending entries, and open it
+	// for appending new records.
+	if err := w.compactLocked(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// load reads all records from the log file and rebuilds the set of pending
+// entries.
+func (w *WAL) load() error {
+	f, err := os.Open(w.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open WAL: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry WALEntry
+
+		// A partially written record is possible if the agent crashed while
+		// appending it. In this case the action was never sent to the target
+		// so the record can be safely skipped.
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		if entry.Complete {
+			delete(w.pending, entry.ID)
+		} else {
+			w.pending[entry.ID] = &entry
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read WAL: %v", err)
+	}
+	return nil
+}
+
+// Begin records a scaling action as in-flight and returns the ID of the
+// entry, which must be passed to Complete once the action has finished.
+func (w *WAL) Begin(policyID string, target *sdk.ScalingPolicyTarget, action *sdk.ScalingAction) (string, error) {
+	if w == nil {
+		return "", nil
+	}
+
+	entry := &WALEntry{
+		ID:         uuid.Generate(),
+		PolicyID:   policyID,
+		Target:     target,
+		Count:      action.Count,
+		Direction:  action.Direction,
+		CreateTime: time.Now().UTC(),
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.appendLocked(entry); err != nil {
+		return "", err
+	}
+	w.pending[entry.ID] = entry
+
+	return entry.ID, nil
+}
+
+// Complete marks the entry with the passed ID as no longer in-flight.
+func (w *WAL) Complete(id string) error {
+	if w == nil || id == "" {
+		return nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.appendLocked(&WALEntry{ID: id, Complete: true}); err != nil {
+		return err
+	}
+	delete(w.pending, id)
+
+	if w.numRecords > w.maxEntries {
+		return w.compactLocked()
+	}
+	return nil
+}
+
+// Pending returns the entries which have not been completed, ordered by their
+// creation time.
+func (w *WAL) Pending() []*WALEntry {
+	if w == nil {
+		return nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	out := make([]*WALEntry, 0, len(w.pending))
+	for _, e := range w.pending {
+		out = append(out, e)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreateTime.Before(out[j].CreateTime) })
+	return out
+}
+
+// Close closes the underlying log file.
+func (w *WAL) Close() error {
+	if w == nil {
+		return nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// appendLocked writes a record to the log file and syncs it to disk. The
+// caller must hold the lock.
+func (w *WAL) appendLocked(entry *WALEntry) error {
+	if w.file == nil {
+		return fmt.Errorf("WAL is closed")
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode WAL entry: %v", err)
+	}
+
+	if _, err := w.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write WAL entry: %v", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync WAL: %v", err)
+	}
+
+	w.numRecords++
+	return nil
+}
+
+// compactLocked rewrites the log file so it only contains pending entries.
+// The new file is written alongside the current one and renamed into place,
+// so a crash during compaction does not lose any entries. The caller must
+// hold the lock.
+func (w *WAL) compactLocked() error {
+	tmpPath := w.path + ".tmp"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create WAL: %v", err)
+	}
+
+	for _, e := range w.pending {
+		b, err := json.Marshal(e)
+		if err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to encode WAL entry: %v", err)
+		}
+		if _, err := tmp.Write(append(b, '\n')); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to write WAL entry: %v", err)
+		}
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync WAL: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close WAL: %v", err)
+	}
+
+	if w.file != nil {
+		_ = w.file.Close()
+		w.file = nil
+	}
+
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		return fmt.Errorf("failed to replace WAL: %v", err)
+	}
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open WAL: %v", err)
+	}
+
+	w.file = f
+	w.numRecords = len(w.pending)
+	return nil
+}
@@ -0,0 +1,82 @@
+package policyeval
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nomad-autoscaler-wal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	wal, err := OpenWAL(dir, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, wal.Pending())
+
+	target := &sdk.ScalingPolicyTarget{Name: "nomad-target", Config: map[string]string{"Job": "example"}}
+	action := &sdk.ScalingAction{Count: 3, Direction: sdk.ScaleDirectionUp}
+
+	// Begin two actions and complete the first.
+	id1, err := wal.Begin("policy1", target, action)
+	assert.NoError(t, err)
+	id2, err := wal.Begin("policy2", target, action)
+	assert.NoError(t, err)
+	assert.NoError(t, wal.Complete(id1))
+
+	pending := wal.Pending()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, id2, pending[0].ID)
+
+	// Simulate an agent restart; the second action should still be pending.
+	assert.NoError(t, wal.Close())
+
+	wal, err = OpenWAL(dir, 3)
+	assert.NoError(t, err)
+
+	pending = wal.Pending()
+	assert.Len(t, pending, 1)
+	assert.Equal(t, id2, pending[0].ID)
+	assert.Equal(t, "policy2", pending[0].PolicyID)
+	assert.Equal(t, target, pending[0].Target)
+	assert.Equal(t, int64(3), pending[0].Count)
+	assert.Equal(t, sdk.ScaleDirection(sdk.ScaleDirectionUp), pending[0].Direction)
+
+	// Completing enough actions should compact the file so it only holds the
+	// pending entry.
+	for i := 0; i < 3; i++ {
+		id, err := wal.Begin("policy3", target, action)
+		assert.NoError(t, err)
+		assert.NoError(t, wal.Complete(id))
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, walFileName))
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, bytes.Count(b, []byte("\n")), 3)
+	assert.Len(t, wal.Pending(), 1)
+
+	assert.NoError(t, wal.Complete(id2))
+	assert.NoError(t, wal.Close())
+
+	wal, err = OpenWAL(dir, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, wal.Pending())
+	assert.NoError(t, wal.Close())
+}
+
+func TestWAL_nil(t *testing.T) {
+	var wal *WAL
+
+	id, err := wal.Begin("policy", nil, &sdk.ScalingAction{})
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+	assert.NoError(t, wal.Complete(id))
+	assert.Nil(t, wal.Pending())
+	assert.NoError(t, wal.Close())
+}
@@ -3,7 +3,8 @@ package sdk
 import "time"
 
 // TimestampedMetric contains a single metric Value along with its associated
-// Timestamp.
+// Timestamp. APMs which can't report when the value was recorded leave the
+// Timestamp as the zero time, which disables checks based on metric age.
 type TimestampedMetric struct {
 	Timestamp time.Time
 	Value     float64
@@ -22,8 +23,45 @@ func (t TimestampedMetrics) Less(i, j int) bool { return t[i].Timestamp.Before(t
 // Swap satisfies the Swap function of the sort.Interface interface.
 func (t TimestampedMetrics) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
 
+// LabeledMetrics are the results of a query which returns a metric series
+// per instance, such as one per allocation or node. Each series is keyed by
+// the label which identifies the instance it belongs to.
+type LabeledMetrics map[string]TimestampedMetrics
+
 // TimeRange defines a range of time.
 type TimeRange struct {
 	From time.Time
 	To   time.Time
 }
+
+// QueryParams are structured parameters of a check query. They allow
+// operators to control how the APM aggregates the queried metric without
+// embedding this within the query itself. APM plugins which do not support
+// structured parameters ignore them.
+type QueryParams struct {
+
+	// Window is the time interval over which the APM rolls up the metric
+	// data points.
+	Window time.Duration
+
+	// Rollup is the function the APM uses to combine the data points within
+	// each Window, such as "avg" or "max".
+	Rollup string
+
+	// Aggregator is the function the APM uses to combine the metric streams
+	// matched by the query, such as "avg" or "sum".
+	Aggregator string
+}
+
+// QueryRequest is the extended form of an APM query which includes the
+// structured query parameters of the check.
+type QueryRequest struct {
+	Query     string
+	TimeRange TimeRange
+	Params    *QueryParams
+
+	// Config is the SourceConfig of the check, which overrides the plugin
+	// config for this query. It is only set for APMs which implement the
+	// apm.ConfigOverrider interface.
+	Config map[string]string
+}
@@ -6,6 +6,16 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/sdk/helper/uuid"
 )
 
+const (
+	// EvaluationTriggerScheduled identifies evaluations created by the
+	// policy handler at the policy's evaluation interval.
+	EvaluationTriggerScheduled = "scheduled"
+
+	// EvaluationTriggerManual identifies evaluations requested through the
+	// HTTP API.
+	EvaluationTriggerManual = "manual"
+)
+
 // ScalingEvaluation forms an individual analysis undertaken by the autoscaler
 // in order to determine the desired state of a target.
 type ScalingEvaluation struct {
@@ -14,6 +24,38 @@ type ScalingEvaluation struct {
 	TargetStatus     *TargetStatus
 	CheckEvaluations []*ScalingCheckEvaluation
 	CreateTime       time.Time
+
+	// InCooldown indicates the policy was in cooldown when the evaluation was
+	// created. This only happens for policies which allow the cooldown to be
+	// bypassed, and such evaluations may only perform a scale out action.
+	InCooldown bool
+
+	// InStartupGracePeriod indicates the evaluation was created within the
+	// policy's startup grace period, so any scaling action must be
+	// suppressed.
+	InStartupGracePeriod bool
+
+	// Trigger describes what caused the evaluation to be created, such as
+	// EvaluationTriggerScheduled or EvaluationTriggerManual.
+	Trigger string
+
+	// TriggeredBy identifies who requested a manual evaluation. It is empty
+	// for scheduled evaluations, or if the requester is unknown.
+	TriggeredBy string
+
+	// InFlight is set when the evaluation was created while a previous
+	// scaling action of the policy was awaiting completion by the target.
+	// This only happens for policies which allow in-flight actions to be
+	// superseded, and such evaluations may only perform a scaling action in
+	// the opposite direction.
+	InFlight *InFlightAction
+}
+
+// InFlightAction describes a scaling action which has been submitted to the
+// target, but which the target has not completed yet.
+type InFlightAction struct {
+	Count     int64
+	Direction ScaleDirection
 }
 
 // NewScalingEvaluation creates a new ScalingEvaluation based off the passed
@@ -27,12 +69,14 @@ func NewScalingEvaluation(p *ScalingPolicy, status *TargetStatus) *ScalingEvalua
 		Policy:       p,
 		TargetStatus: status,
 		CreateTime:   time.Now().UTC(),
+		Trigger:      EvaluationTriggerScheduled,
 	}
 
 	// Iterate the policy checks and add then to the eval.
 	for _, check := range p.Checks {
 		checkEval := ScalingCheckEvaluation{
-			Check: check,
+			Check:    check,
+			PolicyID: p.ID,
 			Action: &ScalingAction{
 				Meta: map[string]interface{}{
 					"nomad_policy_id": p.ID,
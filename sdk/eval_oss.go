@@ -11,9 +11,24 @@ type ScalingCheckEvaluation struct {
 	// with.
 	Check *ScalingPolicyCheck
 
+	// PolicyID is the ID of the policy the check belongs to. Strategies which
+	// keep state between evaluations can use it to identify the check. It is
+	// not available to strategies over the external plugin gRPC interface.
+	PolicyID string
+
 	// Metrics is the metric resulting from querying the APM.
 	Metrics TimestampedMetrics
 
+	// LabeledMetrics are the per-instance metrics resulting from querying the
+	// APM. They are only populated for checks with PerInstance set.
+	LabeledMetrics LabeledMetrics
+
+	// ScaleFromZero is set when the current count passed to the strategy is
+	// zero. Strategies which calculate the new count proportionally to the
+	// current count can't do so when scaling from zero, and must calculate
+	// it from the metric alone or use a configured bootstrap count instead.
+	ScaleFromZero bool
+
 	// Action is the calculated desired state and is populated by strategy.Run.
 	Action *ScalingAction
 }
@@ -92,6 +92,7 @@ func TestNewScalingEvaluation(t *testing.T) {
 				},
 				CheckEvaluations: []*ScalingCheckEvaluation{
 					{
+						PolicyID: "test-test-test",
 						Check: &ScalingPolicyCheck{
 							Name:   "first-check",
 							Source: "apm-source",
@@ -109,6 +110,7 @@ func TestNewScalingEvaluation(t *testing.T) {
 						},
 					},
 					{
+						PolicyID: "test-test-test",
 						Check: &ScalingPolicyCheck{
 							Name:   "second-check",
 							Source: "apm-source",
@@ -126,6 +128,7 @@ func TestNewScalingEvaluation(t *testing.T) {
 						},
 					},
 				},
+				Trigger: EvaluationTriggerScheduled,
 			},
 			name: "basic struct population check",
 		},
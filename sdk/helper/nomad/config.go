@@ -140,6 +140,22 @@ func MergeMapWithAgentConfig(m map[string]string, cfg *api.Config) {
 	}
 }
 
+// ReplaceMapToken replaces the ACL token within the namespaced map config,
+// including the tokens of additional regions, where it matches the old token.
+// This allows a rotated token to be passed to plugins which inherited the
+// previous token from the agent.
+func ReplaceMapToken(m map[string]string, oldToken, newToken string) {
+	for k, v := range m {
+		if v != oldToken {
+			continue
+		}
+		if k == configKeyNomadToken ||
+			(strings.HasPrefix(k, configKeyNomadRegionsPrefix) && strings.HasSuffix(k, ".token")) {
+			m[k] = newToken
+		}
+	}
+}
+
 // MergeDefaultWithAgentConfig merges the agent Nomad configuration with the
 // default Nomad API configuration. The Nomad Autoscaler agent config takes
 // precedence over the default config as any user supplied variables should
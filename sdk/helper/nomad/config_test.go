@@ -185,3 +185,22 @@ func Test_MergeDefaultWithAgentConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_ReplaceMapToken(t *testing.T) {
+	m := map[string]string{
+		"nomad_address":           "old",
+		"nomad_token":             "old",
+		"nomad_regions.eu.token":  "old",
+		"nomad_regions.us.token":  "us-token",
+		"nomad_regions.eu.region": "old",
+	}
+	ReplaceMapToken(m, "old", "new")
+
+	assert.Equal(t, map[string]string{
+		"nomad_address":           "old",
+		"nomad_token":             "new",
+		"nomad_regions.eu.token":  "new",
+		"nomad_regions.us.token":  "us-token",
+		"nomad_regions.eu.region": "old",
+	}, m)
+}
@@ -0,0 +1,181 @@
+package nomad
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+var (
+	// sharedRateLimiter is the rate limiter applied to all Nomad API clients
+	// created using NewClient. It is shared so that all policies, targets and
+	// policy sources running within the agent process are limited together.
+	sharedRateLimiter     *RateLimiter
+	sharedRateLimiterLock sync.RWMutex
+)
+
+// SetSharedRateLimiter sets the rate limiter used by all Nomad API clients
+// created using NewClient from this point on. Passing nil disables rate
+// limiting.
+func SetSharedRateLimiter(r *RateLimiter) {
+	sharedRateLimiterLock.Lock()
+	defer sharedRateLimiterLock.Unlock()
+	sharedRateLimiter = r
+}
+
+// SharedRateLimiter returns the rate limiter shared by Nomad API clients. It
+// returns nil if rate limiting is not enabled.
+func SharedRateLimiter() *RateLimiter {
+	sharedRateLimiterLock.RLock()
+	defer sharedRateLimiterLock.RUnlock()
+	return sharedRateLimiter
+}
+
+// NewClient returns a Nomad API client using the passed config. If a shared
+// rate limiter has been set, all requests performed by the client wait for
+// the limiter before being sent.
+func NewClient(cfg *api.Config) (*api.Client, error) {
+	limiter := SharedRateLimiter()
+	if limiter == nil || cfg.HttpClient != nil {
+		return api.NewClient(cfg)
+	}
+
+	// Copy the config so the caller's object is not modified when we set the
+	// HTTP client.
+	c := *cfg
+
+	// Setting the HTTP client means the Nomad API will not configure TLS, so
+	// build the client in the same way the API does by default.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	httpClient := &http.Client{Transport: transport}
+	if c.TLSConfig != nil {
+		if err := api.ConfigureTLS(httpClient, c.TLSConfig); err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %v", err)
+		}
+	}
+
+	httpClient.Transport = &rateLimitedTransport{limiter: limiter, transport: httpClient.Transport}
+	c.HttpClient = httpClient
+
+	return api.NewClient(&c)
+}
+
+// rateLimitedTransport is an http.RoundTripper which waits for the rate
+// limiter before performing each request.
+type rateLimitedTransport struct {
+	limiter   *RateLimiter
+	transport http.RoundTripper
+}
+
+// RoundTrip satisfies the RoundTrip function on the http.RoundTripper
+// interface.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// RateLimiter is a token bucket rate limiter. Tokens are added to the bucket
+// at a fixed rate, up to the burst size, and each call to Wait consumes one
+// token. Callers queue until a token is available rather than failing.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	// lock is used to synchronize access to the fields below.
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter which allows rate requests per
+// second, with bursts of up to burst requests. A burst lower than one is
+// treated as one.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done. If the
+// context has a deadline which would be reached before a token becomes
+// available, Wait returns an error immediately. A nil RateLimiter never
+// blocks.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	// Reserve a token, which may put the bucket into debt. The size of the
+	// debt determines how long the caller must wait for its token.
+	delay := r.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.cancel()
+		return fmt.Errorf("rate limit wait of %v would exceed context deadline", delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve consumes a token and returns how long the caller must wait before
+// the token is available.
+func (r *RateLimiter) reserve(now time.Time) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Refill the bucket based on the time elapsed since the last reservation.
+	if elapsed := now.Sub(r.last); elapsed > 0 {
+		r.tokens += elapsed.Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+		r.last = now
+	}
+
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token to the bucket when the caller gives up
+// waiting for it.
+func (r *RateLimiter) cancel() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.tokens++
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+}
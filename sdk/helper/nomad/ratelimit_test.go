@@ -0,0 +1,53 @@
+package nomad
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	r := NewRateLimiter(10, 2)
+	now := r.last
+
+	// The burst allows two immediate requests, after which callers must wait
+	// for tokens to be refilled.
+	assert.Equal(t, time.Duration(0), r.reserve(now))
+	assert.Equal(t, time.Duration(0), r.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, r.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, r.reserve(now))
+
+	// Giving up on a reservation returns its token.
+	r.cancel()
+	assert.Equal(t, 200*time.Millisecond, r.reserve(now))
+
+	// After enough time has passed the bucket is full again, but never holds
+	// more than the burst.
+	later := now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), r.reserve(later))
+	assert.Equal(t, time.Duration(0), r.reserve(later))
+	assert.Equal(t, 100*time.Millisecond, r.reserve(later))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	r := NewRateLimiter(1, 1)
+
+	assert.NoError(t, r.Wait(context.Background()))
+
+	// The next token is available in a second, so a shorter deadline fails
+	// immediately rather than waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, r.Wait(ctx))
+
+	// A cancelled context stops the wait.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, r.Wait(ctx))
+
+	// A nil limiter never blocks.
+	var nilLimiter *RateLimiter
+	assert.NoError(t, nilLimiter.Wait(context.Background()))
+}
@@ -0,0 +1,161 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad/api"
+)
+
+// configKeyNomadRegionsPrefix is the prefix of the namespaced config keys
+// which hold the configuration of additional Nomad regions. Keys take the
+// form nomad_regions.<region>.<key>, where key is the namespaced config key
+// without the "nomad_" prefix, for example nomad_regions.eu.address.
+const configKeyNomadRegionsPrefix = "nomad_regions."
+
+// MergeRegionWithAgentConfig returns a copy of the agent Nomad API config,
+// with the configuration of the additional region taking precedence.
+func MergeRegionWithAgentConfig(region *config.NomadRegion, cfg *api.Config) *api.Config {
+	c := *cfg
+	c.Region = region.Name
+	c.TLSConfig = cfg.TLSConfig.Copy()
+	if c.TLSConfig == nil {
+		c.TLSConfig = &api.TLSConfig{}
+	}
+
+	if region.Address != "" {
+		c.Address = region.Address
+	}
+	if region.Token != "" {
+		c.SecretID = region.Token
+	}
+	if region.HTTPAuth != "" {
+		c.HttpAuth = HTTPAuthFromString(region.HTTPAuth)
+	}
+	if region.CACert != "" {
+		c.TLSConfig.CACert = region.CACert
+	}
+	if region.CAPath != "" {
+		c.TLSConfig.CAPath = region.CAPath
+	}
+	if region.ClientCert != "" {
+		c.TLSConfig.ClientCert = region.ClientCert
+	}
+	if region.ClientKey != "" {
+		c.TLSConfig.ClientKey = region.ClientKey
+	}
+	if region.TLSServerName != "" {
+		c.TLSConfig.TLSServerName = region.TLSServerName
+	}
+	if region.SkipVerify {
+		c.TLSConfig.Insecure = region.SkipVerify
+	}
+
+	return &c
+}
+
+// MergeMapWithRegionConfigs adds the configuration of the additional Nomad
+// regions to the namespaced map config, so plugins can connect to them.
+// Regions which are already configured within the map are not modified.
+func MergeMapWithRegionConfigs(m map[string]string, regions map[string]*api.Config) {
+	configured := RegionConfigsFromNamespacedMap(m)
+
+	for name, cfg := range regions {
+		if _, ok := configured[name]; ok {
+			continue
+		}
+
+		regionMap := make(map[string]string)
+		MergeMapWithAgentConfig(regionMap, cfg)
+		delete(regionMap, configKeyNomadRegion)
+
+		for k, v := range regionMap {
+			m[configKeyNomadRegionsPrefix+name+"."+strings.TrimPrefix(k, "nomad_")] = v
+		}
+	}
+}
+
+// RegionConfigsFromNamespacedMap returns the Nomad API config of each
+// additional region found within the namespaced map config, keyed by region.
+func RegionConfigsFromNamespacedMap(cfg map[string]string) map[string]*api.Config {
+	regionMaps := make(map[string]map[string]string)
+
+	for k, v := range cfg {
+		if !strings.HasPrefix(k, configKeyNomadRegionsPrefix) {
+			continue
+		}
+
+		// Region names can contain dots, but config keys can't, so split on
+		// the last one.
+		rest := strings.TrimPrefix(k, configKeyNomadRegionsPrefix)
+		i := strings.LastIndex(rest, ".")
+		if i <= 0 {
+			continue
+		}
+
+		name, key := rest[:i], rest[i+1:]
+		if regionMaps[name] == nil {
+			regionMaps[name] = make(map[string]string)
+		}
+		regionMaps[name]["nomad_"+key] = v
+	}
+
+	configs := make(map[string]*api.Config, len(regionMaps))
+	for name, m := range regionMaps {
+		c := ConfigFromNamespacedMap(m)
+		c.Region = name
+		configs[name] = c
+	}
+	return configs
+}
+
+// ClientPool maintains a Nomad API client per region. Regions which have an
+// explicit configuration use it, while other regions use a copy of the
+// default config, with Nomad forwarding the requests to the region.
+type ClientPool struct {
+	defaultCfg *api.Config
+	regionCfgs map[string]*api.Config
+
+	lock    sync.Mutex
+	clients map[string]*api.Client
+}
+
+// NewClientPool returns a new ClientPool which uses the passed default and
+// region specific configs to create clients.
+func NewClientPool(defaultCfg *api.Config, regionCfgs map[string]*api.Config) *ClientPool {
+	return &ClientPool{
+		defaultCfg: defaultCfg,
+		regionCfgs: regionCfgs,
+		clients:    make(map[string]*api.Client),
+	}
+}
+
+// Client returns the client for the region, creating it if required. An
+// empty region returns the client using the default config.
+func (p *ClientPool) Client(region string) (*api.Client, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if client, ok := p.clients[region]; ok {
+		return client, nil
+	}
+
+	cfg, ok := p.regionCfgs[region]
+	if !ok {
+		c := *p.defaultCfg
+		c.TLSConfig = p.defaultCfg.TLSConfig.Copy()
+		if region != "" {
+			c.Region = region
+		}
+		cfg = &c
+	}
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate Nomad client for region %q: %v", region, err)
+	}
+	p.clients[region] = client
+	return client, nil
+}
@@ -0,0 +1,113 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad-autoscaler/agent/config"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MergeRegionWithAgentConfig(t *testing.T) {
+	agentCfg := &api.Config{
+		Address:   "http://nomad.systems:4646",
+		Region:    "global",
+		Namespace: "default",
+		SecretID:  "global-token",
+		TLSConfig: &api.TLSConfig{CACert: "/etc/nomad.d/ca.crt"},
+	}
+
+	regionCfg := MergeRegionWithAgentConfig(&config.NomadRegion{
+		Name:       "eu",
+		Address:    "https://nomad-eu.systems:4646",
+		Token:      "eu-token",
+		ClientCert: "/etc/nomad.d/eu.crt",
+	}, agentCfg)
+
+	expected := &api.Config{
+		Address:   "https://nomad-eu.systems:4646",
+		Region:    "eu",
+		Namespace: "default",
+		SecretID:  "eu-token",
+		TLSConfig: &api.TLSConfig{
+			CACert:     "/etc/nomad.d/ca.crt",
+			ClientCert: "/etc/nomad.d/eu.crt",
+		},
+	}
+	assert.Equal(t, expected, regionCfg)
+
+	// The agent config must not be modified.
+	assert.Equal(t, "global", agentCfg.Region)
+	assert.Empty(t, agentCfg.TLSConfig.ClientCert)
+}
+
+func Test_RegionConfigsRoundTrip(t *testing.T) {
+	regions := map[string]*api.Config{
+		"eu": {
+			Address:   "https://nomad-eu.systems:4646",
+			Region:    "eu",
+			SecretID:  "eu-token",
+			TLSConfig: &api.TLSConfig{TLSServerName: "server.eu.nomad"},
+		},
+		"us.east": {
+			Address:   "http://nomad.systems:4646",
+			Region:    "us.east",
+			TLSConfig: &api.TLSConfig{},
+		},
+	}
+
+	m := map[string]string{
+		"nomad_address":                    "http://nomad.systems:4646",
+		"nomad_regions.eu.address":         "https://nomad-eu-override.systems:4646",
+		"nomad_regions.eu.token":           "override-token",
+		"nomad_regions.invalid":            "ignored",
+		"nomad_regions.eu.tls-server-name": "override.eu.nomad",
+	}
+	MergeMapWithRegionConfigs(m, regions)
+
+	// Regions within the map are not modified, while the others are added.
+	assert.Equal(t, "https://nomad-eu-override.systems:4646", m["nomad_regions.eu.address"])
+	assert.Equal(t, "http://nomad.systems:4646", m["nomad_regions.us.east.address"])
+
+	expected := map[string]*api.Config{
+		"eu": {
+			Address:   "https://nomad-eu-override.systems:4646",
+			Region:    "eu",
+			SecretID:  "override-token",
+			TLSConfig: &api.TLSConfig{TLSServerName: "override.eu.nomad"},
+		},
+		"us.east": {
+			Address:   "http://nomad.systems:4646",
+			Region:    "us.east",
+			TLSConfig: &api.TLSConfig{},
+		},
+	}
+	assert.Equal(t, expected, RegionConfigsFromNamespacedMap(m))
+}
+
+func TestClientPool_Client(t *testing.T) {
+	pool := NewClientPool(
+		&api.Config{Address: "http://nomad.systems:4646", TLSConfig: &api.TLSConfig{}},
+		map[string]*api.Config{
+			"eu": {Address: "http://nomad-eu.systems:4646", Region: "eu", TLSConfig: &api.TLSConfig{}},
+		},
+	)
+
+	defaultClient, err := pool.Client("")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nomad.systems:4646", defaultClient.Address())
+
+	euClient, err := pool.Client("eu")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nomad-eu.systems:4646", euClient.Address())
+
+	// Regions without an explicit config use the default address.
+	usClient, err := pool.Client("us")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nomad.systems:4646", usClient.Address())
+
+	// Clients are reused.
+	again, err := pool.Client("eu")
+	assert.NoError(t, err)
+	assert.Same(t, euClient, again)
+}
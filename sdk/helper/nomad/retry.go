@@ -0,0 +1,107 @@
+package nomad
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// responseCodeRegex is used to extract the HTTP response code from errors
+// returned by the Nomad API client. The client does not expose a typed error,
+// so the message is the only place where the code can be found.
+var responseCodeRegex = regexp.MustCompile(`Unexpected response code: (\d{3})`)
+
+// Backoff calculates exponentially increasing wait periods between attempts
+// to call the Nomad API. It is not safe for concurrent use.
+type Backoff struct {
+	base     time.Duration
+	limit    time.Duration
+	attempts int
+}
+
+// NewBackoff returns a new Backoff which starts at base and never exceeds the
+// passed limit.
+func NewBackoff(base, limit time.Duration) *Backoff {
+	return &Backoff{base: base, limit: limit}
+}
+
+// Next returns the duration to wait before the next attempt and records that
+// another attempt has failed.
+func (b *Backoff) Next() time.Duration {
+	d := b.base << uint(b.attempts)
+
+	// Guard against the shift overflowing as well as exceeding the limit.
+	if d <= 0 || d > b.limit {
+		d = b.limit
+	} else {
+		b.attempts++
+	}
+	return d
+}
+
+// Limit returns the maximum duration the Backoff will return.
+func (b *Backoff) Limit() time.Duration { return b.limit }
+
+// Reset sets the Backoff back to its initial state, and should be called
+// after a successful attempt.
+func (b *Backoff) Reset() { b.attempts = 0 }
+
+// IsTransientError identifies whether an error returned by the Nomad API
+// client is likely to succeed if retried. Server side errors and failures to
+// communicate with Nomad are considered transient, whereas client errors such
+// as a 403 or 404 are considered permanent.
+func IsTransientError(err error) bool {
+	if err == nil || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	if code := ResponseCode(err); code != 0 {
+		return code >= 500 || code == 429
+	}
+
+	// An error without a response code means we were unable to get a response
+	// from Nomad, such as a connection refused or an EOF.
+	return true
+}
+
+// ResponseCode returns the HTTP response code of an error returned by the
+// Nomad API client, or zero if the error does not include one.
+func ResponseCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if matches := responseCodeRegex.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		code, _ := strconv.Atoi(matches[1])
+		return code
+	}
+	return 0
+}
+
+// Retry calls f until it succeeds, returns a non-transient error, the number
+// of attempts is reached or the context is cancelled. The last error returned
+// by f is passed to the caller.
+func Retry(ctx context.Context, attempts int, b *Backoff, f func() error) error {
+	var err error
+
+	for i := 0; i < attempts; i++ {
+		if err = f(); err == nil || !IsTransientError(err) {
+			return err
+		}
+
+		// Do not wait after the final attempt.
+		if i == attempts-1 {
+			break
+		}
+
+		timer := time.NewTimer(b.Next())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,157 @@
+package nomad
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := NewBackoff(time.Second, 5*time.Second)
+
+	assert.Equal(t, time.Second, b.Next())
+	assert.Equal(t, 2*time.Second, b.Next())
+	assert.Equal(t, 4*time.Second, b.Next())
+	assert.Equal(t, 5*time.Second, b.Next())
+	assert.Equal(t, 5*time.Second, b.Next())
+
+	b.Reset()
+	assert.Equal(t, time.Second, b.Next())
+}
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		inputErr       error
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputErr:       nil,
+			expectedOutput: false,
+			name:           "nil error",
+		},
+		{
+			inputErr:       context.Canceled,
+			expectedOutput: false,
+			name:           "context cancelled",
+		},
+		{
+			inputErr:       errors.New(`Get "http://127.0.0.1:4646/v1/scaling/policies": dial tcp 127.0.0.1:4646: connect: connection refused`),
+			expectedOutput: true,
+			name:           "connection refused",
+		},
+		{
+			inputErr:       errors.New("Unexpected response code: 500 (rpc error: No cluster leader)"),
+			expectedOutput: true,
+			name:           "server error",
+		},
+		{
+			inputErr:       errors.New("Unexpected response code: 429 (too many requests)"),
+			expectedOutput: true,
+			name:           "rate limited",
+		},
+		{
+			inputErr:       errors.New("Unexpected response code: 403 (Permission denied)"),
+			expectedOutput: false,
+			name:           "permission denied",
+		},
+		{
+			inputErr:       errors.New("failed to scale group: Unexpected response code: 404 (job not found)"),
+			expectedOutput: false,
+			name:           "wrapped not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, IsTransientError(tc.inputErr), tc.name)
+		})
+	}
+}
+
+func TestResponseCode(t *testing.T) {
+	testCases := []struct {
+		inputErr       error
+		expectedOutput int
+		name           string
+	}{
+		{
+			inputErr:       nil,
+			expectedOutput: 0,
+			name:           "nil error",
+		},
+		{
+			inputErr:       errors.New("EOF"),
+			expectedOutput: 0,
+			name:           "error without response code",
+		},
+		{
+			inputErr:       errors.New("failed to read variable: Unexpected response code: 404 (variable not found)"),
+			expectedOutput: 404,
+			name:           "wrapped not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, ResponseCode(tc.inputErr), tc.name)
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	testCases := []struct {
+		inputAttempts    int
+		inputErrs        []error
+		expectedErr      error
+		expectedNumCalls int
+		name             string
+	}{
+		{
+			inputAttempts:    3,
+			inputErrs:        []error{nil},
+			expectedErr:      nil,
+			expectedNumCalls: 1,
+			name:             "success first time",
+		},
+		{
+			inputAttempts:    3,
+			inputErrs:        []error{errors.New("EOF"), nil},
+			expectedErr:      nil,
+			expectedNumCalls: 2,
+			name:             "success after transient error",
+		},
+		{
+			inputAttempts:    3,
+			inputErrs:        []error{errors.New("Unexpected response code: 400 (bad request)")},
+			expectedErr:      errors.New("Unexpected response code: 400 (bad request)"),
+			expectedNumCalls: 1,
+			name:             "permanent error is not retried",
+		},
+		{
+			inputAttempts:    2,
+			inputErrs:        []error{errors.New("EOF"), errors.New("EOF"), nil},
+			expectedErr:      errors.New("EOF"),
+			expectedNumCalls: 2,
+			name:             "attempts limit reached",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			f := func() error {
+				err := tc.inputErrs[calls]
+				calls++
+				return err
+			}
+
+			err := Retry(context.Background(), tc.inputAttempts, NewBackoff(time.Millisecond, time.Millisecond), f)
+			assert.Equal(t, tc.expectedErr, err, tc.name)
+			assert.Equal(t, tc.expectedNumCalls, calls, tc.name)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad-autoscaler/sdk"
+	nomadHelper "github.com/hashicorp/nomad-autoscaler/sdk/helper/nomad"
 	"github.com/hashicorp/nomad/api"
 )
 
@@ -31,7 +32,7 @@ type ScaleIn struct {
 // functions for performing scaling in operations.
 func NewScaleInUtils(cfg *api.Config, log hclog.Logger) (*ScaleIn, error) {
 
-	client, err := api.NewClient(cfg)
+	client, err := nomadHelper.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to instantiate Nomad client: %v", err)
 	}
@@ -1,6 +1,10 @@
 package scaleutils
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+)
 
 // Ready provides a method for understanding whether the node pool is in a
 // state that allows it to be safely scaled. This should be used by target
@@ -25,3 +29,37 @@ func (si *ScaleIn) Ready(id PoolIdentifier) (bool, error) {
 	}
 	return true, nil
 }
+
+// ReadyNodeCount returns the number of nodes within the pool which are ready
+// and eligible for scheduling. This can be used by target plugins to confirm
+// new nodes have registered with Nomad after scaling out. A non-nil error
+// indicates the pool is not stable, such as when it contains nodes which are
+// still initializing.
+func (si *ScaleIn) ReadyNodeCount(id PoolIdentifier) (int, error) {
+
+	if err := id.Validate(); err != nil {
+		return 0, fmt.Errorf("failed to validate pool identifier: %v", err)
+	}
+
+	nodes, _, err := si.nomad.Nodes().List(nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list Nomad nodes: %v", err)
+	}
+
+	poolNodes, err := id.IdentifyNodes(nodes)
+	if err != nil {
+		return 0, err
+	}
+	return countReadyNodes(poolNodes), nil
+}
+
+// countReadyNodes returns the number of passed nodes which are ready.
+func countReadyNodes(nodes []*api.NodeListStub) int {
+	var count int
+	for _, node := range nodes {
+		if node.Status == api.NodeStatusReady {
+			count++
+		}
+	}
+	return count
+}
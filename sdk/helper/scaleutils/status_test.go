@@ -0,0 +1,37 @@
+package scaleutils
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_countReadyNodes(t *testing.T) {
+	testCases := []struct {
+		inputNodes     []*api.NodeListStub
+		expectedOutput int
+		name           string
+	}{
+		{
+			inputNodes:     nil,
+			expectedOutput: 0,
+			name:           "no nodes",
+		},
+		{
+			inputNodes: []*api.NodeListStub{
+				{ID: "node1", Status: api.NodeStatusReady},
+				{ID: "node2", Status: api.NodeStatusDown},
+				{ID: "node3", Status: api.NodeStatusReady},
+			},
+			expectedOutput: 2,
+			name:           "down nodes are not counted",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, countReadyNodes(tc.inputNodes), tc.name)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinutesPerDay is the number of minutes within a day, and the value of the
+// 24:00 window end.
+const MinutesPerDay = 24 * 60
+
+// weekdays maps the day names accepted within a window to their weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a recurring period of time on some days of the week. It is
+// matched against the wall clock time of the time passed to Matches, so it
+// follows DST changes. Wall clock times which are skipped when DST starts
+// never match, and times which are repeated when DST ends match both times.
+type Window struct {
+
+	// Days are the weekdays the window starts on, indexed by time.Weekday.
+	Days [7]bool
+
+	// Start and End are the minutes since midnight the window starts and
+	// ends at. A window which ends before it starts crosses midnight.
+	Start, End int
+}
+
+// ParseWindow parses a window. Days is "*" or a comma separated list of day
+// names and ranges, such as "mon-fri" or "sat,sun", and an empty value is
+// every day. Start and end are wall clock times in the HH:MM format, with end
+// being exclusive and 24:00 allowed. A window which ends before it starts
+// crosses midnight and belongs to the day it starts on.
+func ParseWindow(days, start, end string) (*Window, error) {
+	w := &Window{}
+
+	if err := w.parseDays(days); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if w.Start, err = parseClock(start); err != nil {
+		return nil, err
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return nil, err
+	}
+	if w.Start == w.End || w.Start == MinutesPerDay {
+		return nil, fmt.Errorf("invalid time range %q", start+"-"+end)
+	}
+
+	return w, nil
+}
+
+// parseDays parses the days of a window.
+func (w *Window) parseDays(s string) error {
+	if s == "" || s == "*" {
+		for i := range w.Days {
+			w.Days[i] = true
+		}
+		return nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+
+		first, ok := weekdays[strings.ToLower(bounds[0])]
+		if !ok {
+			return fmt.Errorf("invalid day %q", bounds[0])
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdays[strings.ToLower(bounds[1])]; !ok {
+				return fmt.Errorf("invalid day %q", bounds[1])
+			}
+		}
+
+		// Ranges can wrap around the end of the week, such as fri-mon.
+		for d := first; ; d = (d + 1) % 7 {
+			w.Days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses a HH:MM wall clock time into the minutes since midnight.
+func parseClock(s string) (int, error) {
+	if s == "24:00" {
+		return MinutesPerDay, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Matches returns whether the window is active at the wall clock time of t.
+func (w *Window) Matches(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if w.Start < w.End {
+		return w.Days[day] && m >= w.Start && m < w.End
+	}
+
+	// The window crosses midnight, so times after midnight belong to the
+	// window which started on the previous day.
+	if m >= w.Start {
+		return w.Days[day]
+	}
+	if m < w.End {
+		return w.Days[(day+6)%7]
+	}
+	return false
+}
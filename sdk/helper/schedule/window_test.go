@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWindow(t *testing.T) {
+	testCases := []struct {
+		inputDays      string
+		inputStart     string
+		inputEnd       string
+		expectedWindow *Window
+		expectError    bool
+		name           string
+	}{
+		{
+			inputDays:      "*",
+			inputStart:     "00:00",
+			inputEnd:       "24:00",
+			expectedWindow: &Window{Days: [7]bool{true, true, true, true, true, true, true}, Start: 0, End: MinutesPerDay},
+			name:           "every day",
+		},
+		{
+			inputStart:     "09:00",
+			inputEnd:       "17:00",
+			expectedWindow: &Window{Days: [7]bool{true, true, true, true, true, true, true}, Start: 9 * 60, End: 17 * 60},
+			name:           "empty days",
+		},
+		{
+			inputDays:      "fri-mon",
+			inputStart:     "18:30",
+			inputEnd:       "06:15",
+			expectedWindow: &Window{Days: [7]bool{true, true, false, false, false, true, true}, Start: 18*60 + 30, End: 6*60 + 15},
+			name:           "range wrapping around the week",
+		},
+		{
+			inputDays:      "Tue, thu",
+			inputStart:     "09:00",
+			inputEnd:       "17:00",
+			expectedWindow: &Window{Days: [7]bool{false, false, true, false, true, false, false}, Start: 9 * 60, End: 17 * 60},
+			name:           "list of days",
+		},
+		{
+			inputDays:   "someday",
+			inputStart:  "09:00",
+			inputEnd:    "17:00",
+			expectError: true,
+			name:        "invalid day",
+		},
+		{
+			inputDays:   "mon",
+			inputStart:  "25:00",
+			inputEnd:    "17:00",
+			expectError: true,
+			name:        "invalid time",
+		},
+		{
+			inputDays:   "mon",
+			inputStart:  "09:00",
+			inputEnd:    "09:00",
+			expectError: true,
+			name:        "empty range",
+		},
+		{
+			inputDays:   "mon",
+			inputStart:  "24:00",
+			inputEnd:    "09:00",
+			expectError: true,
+			name:        "start at end of day",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := ParseWindow(tc.inputDays, tc.inputStart, tc.inputEnd)
+			assert.Equal(t, tc.expectError, err != nil, tc.name)
+			assert.Equal(t, tc.expectedWindow, w, tc.name)
+		})
+	}
+}
+
+func TestWindow_Matches(t *testing.T) {
+	w, err := ParseWindow("fri", "22:00", "06:00")
+	assert.NoError(t, err)
+
+	// Friday 23:00.
+	assert.True(t, w.Matches(time.Date(2021, 3, 12, 23, 0, 0, 0, time.UTC)))
+
+	// Saturday 05:00 belongs to the window which started on Friday.
+	assert.True(t, w.Matches(time.Date(2021, 3, 13, 5, 0, 0, 0, time.UTC)))
+
+	// Saturday 23:00 and Friday 05:00.
+	assert.False(t, w.Matches(time.Date(2021, 3, 13, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, w.Matches(time.Date(2021, 3, 12, 5, 0, 0, 0, time.UTC)))
+}
+
+func TestWindow_Matches_DST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	w, err := ParseWindow("*", "02:00", "03:00")
+	assert.NoError(t, err)
+
+	// Clocks jump from 02:00 to 03:00 when DST starts, so the window is
+	// skipped.
+	for m := 0; m < 120; m++ {
+		ts := time.Date(2021, time.March, 28, 0, m, 0, 0, time.UTC).In(loc)
+		assert.False(t, w.Matches(ts), ts.String())
+	}
+
+	// Clocks go back from 03:00 to 02:00 when DST ends, so the window is
+	// active twice.
+	var matches int
+	for m := 0; m < 180; m += 30 {
+		ts := time.Date(2021, time.October, 30, 23, m, 0, 0, time.UTC).In(loc)
+		if w.Matches(ts) {
+			matches++
+		}
+	}
+	assert.Equal(t, 4, matches)
+}
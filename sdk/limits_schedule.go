@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/schedule"
+)
+
+// ScalingPolicyLimitsSchedule overrides the Min and Max of a policy during
+// recurring windows of time, such as a higher Min during business hours.
+// Windows are matched against the wall clock time of the schedule timezone,
+// so they follow DST changes. Wall clock times which are skipped when DST
+// starts never match, and times which are repeated when DST ends match both
+// times.
+type ScalingPolicyLimitsSchedule struct {
+
+	// Timezone is the IANA name of the timezone the windows are defined in,
+	// such as "Europe/Berlin". An empty value is UTC.
+	Timezone string `hcl:"timezone,optional"`
+
+	// Windows are the windows during which the limits are overridden. If
+	// several windows are active, the first by name is used.
+	Windows []*ScalingPolicyLimitsWindow `hcl:"window,block"`
+}
+
+// ScalingPolicyLimitsWindow is a recurring window of time during which the
+// Min and/or Max of a policy are overridden.
+type ScalingPolicyLimitsWindow struct {
+	Name string `hcl:"name,label"`
+
+	// Days is "*" or a comma separated list of day names and ranges, such as
+	// "mon-fri" or "sat,sun". An empty value is every day.
+	Days string `hcl:"days,optional"`
+
+	// Start and End are wall clock times in the HH:MM format, with End being
+	// exclusive and 24:00 allowed. A window which ends before it starts
+	// crosses midnight and belongs to the day it starts on.
+	Start string `hcl:"start"`
+	End   string `hcl:"end"`
+
+	// Min and Max override the policy limits while the window is active. A
+	// nil value keeps the policy limit.
+	Min *int64 `hcl:"min,optional"`
+	Max *int64 `hcl:"max,optional"`
+}
+
+// Location returns the timezone the windows of the schedule are defined in.
+func (s *ScalingPolicyLimitsSchedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Validate returns an error if the timezone or any of the windows of the
+// schedule is invalid. The limits of each window are not compared to those of
+// the policy.
+func (s *ScalingPolicyLimitsSchedule) Validate() error {
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(s.Windows))
+	for _, w := range s.Windows {
+		if w == nil {
+			continue
+		}
+		if names[w.Name] {
+			return fmt.Errorf("window %q must only be defined once", w.Name)
+		}
+		names[w.Name] = true
+
+		if _, err := w.parse(); err != nil {
+			return fmt.Errorf("invalid window %q: %v", w.Name, err)
+		}
+	}
+	return nil
+}
+
+// ActiveWindow returns the window of the schedule which is active at t, or
+// nil if no window is active. Windows which are invalid never match.
+func (s *ScalingPolicyLimitsSchedule) ActiveWindow(t time.Time) *ScalingPolicyLimitsWindow {
+	loc, err := s.Location()
+	if err != nil {
+		return nil
+	}
+	t = t.In(loc)
+
+	windows := make([]*ScalingPolicyLimitsWindow, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		if w != nil {
+			windows = append(windows, w)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Name < windows[j].Name })
+
+	for _, w := range windows {
+		if parsed, err := w.parse(); err == nil && parsed.Matches(t) {
+			return w
+		}
+	}
+	return nil
+}
+
+// parse parses the days and times of the window, and checks its limits.
+func (w *ScalingPolicyLimitsWindow) parse() (*schedule.Window, error) {
+	sw, err := schedule.ParseWindow(w.Days, w.Start, w.End)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case w.Min == nil && w.Max == nil:
+		return nil, fmt.Errorf("min or max must be set")
+	case w.Min != nil && *w.Min < 0:
+		return nil, fmt.Errorf("min can't be negative")
+	case w.Max != nil && *w.Max < 0:
+		return nil, fmt.Errorf("max can't be negative")
+	case w.Min != nil && w.Max != nil && *w.Min > *w.Max:
+		return nil, fmt.Errorf("min must not be greater than max")
+	}
+
+	return sw, nil
+}
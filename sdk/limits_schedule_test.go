@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad-autoscaler/sdk/helper/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalingPolicyLimitsSchedule_Validate(t *testing.T) {
+	testCases := []struct {
+		inputSchedule *ScalingPolicyLimitsSchedule
+		expectedError error
+		name          string
+	}{
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Timezone: "America/New_York",
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "weekdays", Days: "mon-fri", Start: "09:00", End: "17:00", Min: ptr.Int64ToPtr(4)},
+					{Name: "overnight", Start: "22:00", End: "06:00", Max: ptr.Int64ToPtr(2)},
+				},
+			},
+			expectedError: nil,
+			name:          "valid schedule",
+		},
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "weekend", Days: "sat,sunday", Start: "00:00", End: "24:00", Min: ptr.Int64ToPtr(1)},
+				},
+			},
+			expectedError: errors.New(`invalid window "weekend": invalid day "sunday"`),
+			name:          "invalid day",
+		},
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "day", Start: "8am", End: "18:00", Min: ptr.Int64ToPtr(1)},
+				},
+			},
+			expectedError: errors.New(`invalid window "day": invalid time "8am"`),
+			name:          "invalid time",
+		},
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "day", Start: "08:00", End: "18:00"},
+				},
+			},
+			expectedError: errors.New(`invalid window "day": min or max must be set`),
+			name:          "missing limits",
+		},
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "day", Start: "08:00", End: "18:00", Min: ptr.Int64ToPtr(5), Max: ptr.Int64ToPtr(4)},
+				},
+			},
+			expectedError: errors.New(`invalid window "day": min must not be greater than max`),
+			name:          "min above max",
+		},
+		{
+			inputSchedule: &ScalingPolicyLimitsSchedule{
+				Windows: []*ScalingPolicyLimitsWindow{
+					{Name: "day", Start: "08:00", End: "18:00", Min: ptr.Int64ToPtr(1)},
+					{Name: "day", Start: "18:00", End: "20:00", Min: ptr.Int64ToPtr(2)},
+				},
+			},
+			expectedError: errors.New(`window "day" must only be defined once`),
+			name:          "duplicate window",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedError, tc.inputSchedule.Validate())
+		})
+	}
+}
+
+func TestScalingPolicyLimitsSchedule_ActiveWindow(t *testing.T) {
+	s := &ScalingPolicyLimitsSchedule{
+		Timezone: "Europe/Berlin",
+		Windows: []*ScalingPolicyLimitsWindow{
+			{Name: "overnight", Days: "fri", Start: "22:00", End: "06:00", Min: ptr.Int64ToPtr(1)},
+			{Name: "dst", Start: "02:00", End: "03:00", Min: ptr.Int64ToPtr(1)},
+		},
+	}
+
+	// Friday 23:00 in Berlin.
+	assert.Equal(t, "overnight", s.ActiveWindow(time.Date(2021, 3, 12, 22, 0, 0, 0, time.UTC)).Name)
+
+	// Saturday 05:00 in Berlin belongs to the window which started on Friday.
+	assert.Equal(t, "overnight", s.ActiveWindow(time.Date(2021, 3, 13, 4, 0, 0, 0, time.UTC)).Name)
+
+	// Saturday 23:00 in Berlin.
+	assert.Nil(t, s.ActiveWindow(time.Date(2021, 3, 13, 22, 0, 0, 0, time.UTC)))
+
+	// Clocks jump from 02:00 to 03:00 when DST starts, so the window is
+	// skipped.
+	for m := 0; m < 120; m++ {
+		ts := time.Date(2021, time.March, 28, 0, m, 0, 0, time.UTC)
+		assert.Nil(t, s.ActiveWindow(ts), ts.String())
+	}
+
+	// Clocks go back from 03:00 to 02:00 when DST ends, so the window is
+	// active twice.
+	var matches int
+	for m := 0; m < 180; m += 30 {
+		if s.ActiveWindow(time.Date(2021, time.October, 30, 23, m, 0, 0, time.UTC)) != nil {
+			matches++
+		}
+	}
+	assert.Equal(t, 4, matches)
+}
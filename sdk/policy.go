@@ -1,12 +1,69 @@
 package sdk
 
-import "time"
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+)
 
 const (
 	ScalingPolicyTypeCluster    = "cluster"
 	ScalingPolicyTypeHorizontal = "horizontal"
 )
 
+const (
+	// ScalingPolicyPriorityMin and ScalingPolicyPriorityMax define the
+	// inclusive range of valid policy priorities. Higher values are evaluated
+	// first when the evaluation queues are saturated.
+	ScalingPolicyPriorityMin = 1
+	ScalingPolicyPriorityMax = 100
+
+	// ScalingPolicyPriorityDefault is the priority assigned to policies which
+	// do not explicitly configure one.
+	ScalingPolicyPriorityDefault = 50
+)
+
+const (
+	// MetricAggregationAvg, MetricAggregationMax and MetricAggregationP95
+	// are the functions which can be used to aggregate the metric values
+	// within a check's metric window.
+	MetricAggregationAvg = "avg"
+	MetricAggregationMax = "max"
+	MetricAggregationP95 = "p95"
+
+	// MetricAggregationSum and MetricAggregationMin, along with
+	// MetricAggregationAvg and MetricAggregationMax, are the functions which
+	// can be used to aggregate the metric series of a check's queries.
+	MetricAggregationSum = "sum"
+	MetricAggregationMin = "min"
+)
+
+const (
+	// MetricSmoothingEWMA is the function which can be used to smooth the
+	// query results of a check using their exponentially weighted moving
+	// average.
+	MetricSmoothingEWMA = "ewma"
+
+	// MetricSmoothingAlphaDefault is the weight given to the latest query
+	// result by checks which don't configure a metric smoothing alpha.
+	MetricSmoothingAlphaDefault = 0.5
+)
+
+// MetricFormulaQueryName is the name the result of the check Query is
+// referenced by within the check Formula.
+const MetricFormulaQueryName = "query"
+
+const (
+	// MetricErrorHold and MetricErrorScaleToSafe are the behaviours a policy
+	// can use when its checks are unable to fetch metrics. Hold keeps the
+	// current count, and ScaleToSafe scales the target to the policy's safe
+	// count once the metrics have been unavailable for several evaluations.
+	MetricErrorHold        = "hold"
+	MetricErrorScaleToSafe = "scale_to_safe"
+)
+
 // ScalingPolicy is the internal representation of a scaling document and
 // encompasses all the required information for the autoscaler to perform
 // scaling evaluations on a target.
@@ -19,7 +76,10 @@ type ScalingPolicy struct {
 	// Type is the type of scaling this policy will perform.
 	Type string
 
-	// Priority controls the order in which a policy is picked for evaluation.
+	// Priority controls the order in which a policy is picked for evaluation
+	// when there are more pending evaluations than available workers. Higher
+	// values are picked first. A value of zero indicates the priority has not
+	// been set and the default will be applied.
 	Priority int
 
 	// Min forms a lower bound at which the target should never be asked to
@@ -32,6 +92,17 @@ type ScalingPolicy struct {
 	// this value is not violated.
 	Max int64
 
+	// LimitsSchedule, when set, overrides Min and Max during recurring
+	// windows of time, such as keeping more instances during business hours
+	// than overnight.
+	LimitsSchedule *ScalingPolicyLimitsSchedule
+
+	// SoftMax, when greater than zero, is an advisory upper bound which the
+	// target is expected to stay within. Unlike Max it does not limit the
+	// recommendations, but exceeding it is reported so operators can react
+	// before the hard limit is reached. Zero indicates it has not been set.
+	SoftMax int64
+
 	// Enabled indicates whether the autoscaler should actively evaluate the
 	// policy or not.
 	Enabled bool
@@ -40,11 +111,156 @@ type ScalingPolicy struct {
 	// which no policy evaluations will be started.
 	Cooldown time.Duration
 
+	// CooldownBypassFactor, when greater than zero, allows scale out actions
+	// to be performed during cooldown if the check metric deviates from the
+	// strategy target by at least this factor. Scale in actions never bypass
+	// the cooldown.
+	CooldownBypassFactor float64
+
+	// ZeroCooldown, when greater than zero, is the cooldown used instead of
+	// Cooldown after scaling the target to or from a count of zero. Scaling
+	// back up from zero can be expensive, such as due to cold starts, so this
+	// is typically longer than Cooldown to avoid flapping around zero.
+	ZeroCooldown time.Duration
+
+	// CooldownPerUnit, when greater than zero, is added to the cooldown for
+	// each unit the count changes by, so large scaling actions cool down for
+	// longer than small ones. The result is bounded by MaxCooldown, which
+	// must be set along with it.
+	CooldownPerUnit time.Duration
+	MaxCooldown     time.Duration
+
+	// CooldownOnCompletion indicates the cooldown starts once the target
+	// reports the count of a scaling action as reached, rather than once the
+	// action is submitted. This avoids the cooldown expiring while slow
+	// targets, such as clusters, are still scaling, at the cost of polling
+	// the target status.
+	CooldownOnCompletion bool
+
+	// SupersedeInFlight indicates the policy keeps being evaluated while the
+	// target completes a scaling action, so a scaling action in the opposite
+	// direction can supersede the one in-flight. For example, a slow scale
+	// out can be superseded by a scale in if the metrics drop. It has no
+	// effect unless CooldownOnCompletion is set, as actions are otherwise not
+	// tracked once submitted.
+	SupersedeInFlight bool
+
+	// ScaleInStabilizationWindow, when greater than zero, is the time period
+	// during which the policy must consistently recommend scaling in before
+	// the target is scaled in. The highest count recommended during the
+	// window is used, which avoids reacting to brief dips in the metrics.
+	// Scale out actions are not delayed.
+	ScaleInStabilizationWindow time.Duration
+
+	// StartupGracePeriod is the time period after the agent starts monitoring
+	// the policy during which evaluations run, but scaling actions are
+	// suppressed. This allows target status and metrics to stabilize before
+	// the first scaling action is performed.
+	StartupGracePeriod time.Duration
+
+	// DedupWindow, when greater than zero, is the time period after a
+	// scaling action during which an identical action is suppressed, even
+	// once the cooldown has expired. Actions are identical when they have
+	// the same count and a reason which only differs by its numbers. This
+	// avoids repeatedly applying the same count, such as when the target
+	// count is modified outside of the autoscaler.
+	DedupWindow time.Duration
+
 	// EvaluationInterval indicates the frequency at which the policy is
 	// evaluated. A lower value means more frequent evaluation and can result
 	// in a high rate of change in the target.
 	EvaluationInterval time.Duration
 
+	// OnMetricError is the behaviour of the policy when all of its checks
+	// fail to fetch metrics, either because the query fails or the metrics
+	// are stale. An empty value is the same as MetricErrorHold, which keeps
+	// the current count: this avoids acting on a metrics outage, but leaves
+	// the target unable to react to load until the metrics are restored.
+	// MetricErrorScaleToSafe instead scales the target to SafeCount, which
+	// favours availability, or cost, over reacting to the actual load. The
+	// cooldown and guards of the policy still apply to the safe scaling
+	// action.
+	OnMetricError string
+
+	// SafeCount is the count the target is scaled to when the policy uses
+	// MetricErrorScaleToSafe. A value of zero uses the policy's Max.
+	SafeCount int64
+
+	// Quorum, when greater than zero, is the number of checks which must
+	// agree on the direction of the reconciled action before it is taken.
+	// This reduces the scaling actions caused by a single noisy signal at
+	// the cost of reacting later. Checks which fail to run do not vote.
+	Quorum int
+
+	// ScaleInBias, when greater than zero, resolves ambiguous check results
+	// in favour of fewer instances to reduce cost. Results are ambiguous when
+	// the metric deviates from the strategy target by less than
+	// ScaleInBiasBand, such as 0.1 for deviations between 0.9 and 1.1, and is
+	// not within the deadzone of the strategy. An ambiguous check which
+	// recommends keeping the current count scales in by one instance instead,
+	// as long as the metric is expected to stay within the band, and an
+	// ambiguous check which recommends scaling out by at most ScaleInBias
+	// instances keeps the current count. It requires a ScaleInBiasBand and a
+	// ScaleInStabilizationWindow, so the biased scale ins can't cause
+	// flapping.
+	ScaleInBias     int64
+	ScaleInBiasBand float64
+
+	// HysteresisFactor, when greater than zero, requires a larger metric
+	// deviation to reverse the direction of the last scaling action of the
+	// policy than to continue it, so the target does not oscillate around
+	// the strategy target. A scale out which reverses a scale in requires
+	// the metric to deviate from the target by at least this factor, and a
+	// scale in which reverses a scale out by at most its reciprocal. Actions
+	// whose strategy does not report a deviation are not affected.
+	HysteresisFactor float64
+
+	// MinChangeCount and MinChangePercentage, when greater than zero,
+	// suppress scaling actions which change the count by fewer instances, or
+	// by a smaller percentage of the current count, respectively. This
+	// avoids churn from trivial actions, such as scaling from 100 to 101 on
+	// every evaluation. Unlike the deadzone of a strategy, which is based on
+	// the metric, the thresholds apply to the count once the strategy has run
+	// and the count has been capped to the policy limits.
+	MinChangeCount      int64
+	MinChangePercentage float64
+
+	// RampIntervals, when greater than one, spreads scaling actions over
+	// that many evaluations rather than jumping straight to the count
+	// recommended by the checks. Each evaluation moves the count an equal
+	// share of the way from the count at the start of the ramp towards the
+	// recommended count. The ramp restarts from the current count if the
+	// recommended count changes, or if the target count is modified outside
+	// of the ramp.
+	RampIntervals int
+
+	// RespectManualOverride indicates the policy stops scaling its target for
+	// its cooldown once the target count is detected to have been changed
+	// outside of the autoscaler, such as by an operator setting the count
+	// manually. This avoids immediately reverting the change. Changes are
+	// always detected and recorded, whether or not the policy respects them.
+	RespectManualOverride bool
+
+	// DryRunSample, when greater than zero, is the fraction of scaling
+	// actions executed in dry-run mode, such as 0.9 to only scale the target
+	// for one in ten actions. The actions are sampled at random, which allows
+	// the behaviour of the autoscaler to be observed at low risk while it is
+	// rolled out to a target. A value of 1 is the same as enabling dry-run
+	// on the target.
+	DryRunSample float64
+
+	// LimitsOnly indicates the policy intentionally has no checks, and only
+	// scales its target to keep the count within Min and Max. Policies
+	// without checks must set it, so a missing check is not mistaken for a
+	// policy which enforces its limits.
+	LimitsOnly bool
+
+	// AllowSharedTarget acknowledges the policy intentionally scales the same
+	// target as other policies. Policies which share a target would otherwise
+	// fight over its count, so they are not evaluated unless all of them
+	// allow it.
+	AllowSharedTarget bool
+
 	// Checks is an array of checks which will be triggered in parallel to
 	// determine the desired state of the ScalingPolicyTarget.
 	Checks []*ScalingPolicyCheck
@@ -52,6 +268,15 @@ type ScalingPolicy struct {
 	// Target identifies the scaling target which the autoscaler will interact
 	// with to ensure it meets the desired state as determined by the Checks.
 	Target *ScalingPolicyTarget
+
+	// Template is the name of the policy template this policy is based on.
+	// Values which are not set on the policy are taken from the template.
+	Template string
+
+	// Tags are arbitrary key/value pairs, such as the owning team, which are
+	// added as labels to the telemetry emitted for the policy and to the Meta
+	// of its scaling actions.
+	Tags map[string]string
 }
 
 // ScalingPolicyCheck is an individual check within a scaling policy.This check
@@ -73,9 +298,116 @@ type ScalingPolicyCheck struct {
 	// metrics.
 	QueryWindow time.Duration
 
+	// QueryTimeout is the maximum time the query is allowed to run against
+	// the Source. If the query does not complete in time, the check is
+	// skipped for the evaluation while the other checks are still used. A
+	// value of zero means the query is only limited by the evaluation itself.
+	QueryTimeout time.Duration
+
+	// MaxMetricAge is the maximum age of the most recent metric returned by
+	// the query. APMs may return the last known value during an outage, so
+	// the check is skipped if the metric is older, to avoid scaling on stale
+	// data. Metrics without a timestamp are not checked. A value of zero
+	// disables the check.
+	MaxMetricAge time.Duration
+
+	// QueryParams are the structured parameters passed to the Source along
+	// with the Query. They are optional and ignored by sources which do not
+	// support them.
+	QueryParams *QueryParams
+
+	// SourceConfig overrides the config of the Source plugin when running the
+	// check queries, such as the address of the APM or the credentials used
+	// to query it. This allows one APM plugin to query different servers for
+	// each check. Only APMs which implement apm.ConfigOverrider accept it.
+	//
+	// It is written as a map attribute, rather than a block, so keys which
+	// are not valid identifiers such as "header.Authorization" can be set.
+	SourceConfig map[string]string
+
 	// Strategy is the ScalingPolicyStrategy to use when performing the
 	// ScalingPolicyCheck evaluation.
 	Strategy *ScalingPolicyStrategy
+
+	// Chain is the ordered list of strategies run after Strategy. Each
+	// strategy receives the count proposed by the previous one, which allows
+	// the result of Strategy to be further refined, such as by smoothing or
+	// limiting the change.
+	Chain []*ScalingPolicyStrategy
+
+	// Disabled indicates the check should be skipped when evaluating the
+	// policy. It is set using the check `enabled` parameter, which allows
+	// operators to temporarily turn off a check while keeping it defined.
+	Disabled bool
+
+	// MetricWindow is the number of recent query results the agent keeps for
+	// the check. When greater than one, the strategy receives the aggregate
+	// of these results rather than the latest value, which smooths the
+	// signal and reduces flapping.
+	MetricWindow int
+
+	// MetricAggregation is the function used to aggregate the query results
+	// within the MetricWindow, such as MetricAggregationAvg.
+	MetricAggregation string
+
+	// MetricSmoothing is the function used to smooth the query results of
+	// the check, such as MetricSmoothingEWMA. Unlike the MetricWindow, only a
+	// single running value is kept for the check, and it reacts faster to
+	// changes in the metric.
+	MetricSmoothing string
+
+	// MetricSmoothingAlpha is the weight, between 0 and 1, given to the
+	// latest query result when smoothing the metric. Higher values react
+	// faster to changes, while lower values dampen more of the noise.
+	MetricSmoothingAlpha float64
+
+	// SeriesQueries are additional queries whose metric series are
+	// aggregated with the series of Query using SeriesAggregation, and the
+	// aggregate is passed to the Strategy. This allows a target to be scaled
+	// using the metrics of several others, such as the shards of a workload.
+	// Unlike Query they are not canonicalized, so must be written in full.
+	SeriesQueries []string
+
+	// SeriesAggregation is the function used to aggregate the metric series
+	// of Query and SeriesQueries, such as MetricAggregationSum.
+	SeriesAggregation string
+
+	// FormulaQueries are additional named queries whose results are combined
+	// with the result of Query using Formula, and the result of the formula
+	// is passed to the Strategy. This allows a target to be scaled using
+	// several metrics at once, such as request rate and CPU. Unlike Query
+	// they are not canonicalized, so must be written in full.
+	FormulaQueries map[string]string
+
+	// Formula is the arithmetic expression which combines the results of
+	// Query, referenced as MetricFormulaQueryName, and FormulaQueries,
+	// referenced by name, such as "(requests / 100) + (query / 0.7)".
+	Formula string
+
+	// Transforms is the ordered list of transforms applied to the metrics of
+	// the check before they are passed to the Strategy, such as
+	// "clamp(0,100)" or "scale(1.5)". They are parsed using
+	// ParseMetricTransforms.
+	Transforms []string
+
+	// Preconditions are the conditions the target status must meet for the
+	// check to be run, such as "healthy". Checks whose preconditions are not
+	// met are skipped for the evaluation, like disabled checks, which allows
+	// aggressive checks to be gated on the stability of the target. They are
+	// parsed using ParseCheckPreconditions.
+	Preconditions []string
+
+	// PerInstance indicates the Source should be queried for a metric series
+	// per instance, rather than a single series. The results are passed to
+	// the Strategy as LabeledMetrics and require a source and strategy which
+	// support them.
+	PerInstance bool
+
+	// Priority is used to choose between the actions of the policy checks.
+	// The action of the highest priority check which proposes scaling is
+	// used, and checks with the same priority are reconciled by preferring
+	// the safest action. Checks without a priority have priority zero.
+	Priority int
 }
 
 // ScalingPolicyStrategy contains the plugin and configuration details for
@@ -87,10 +419,62 @@ type ScalingPolicyStrategy struct {
 	Name string `hcl:"name,label"`
 
 	// Config is the mapping of config values used by the strategy plugin. Each
-	// plugin has a set of potentially uniquely supported keys.
+	// plugin has a set of potentially uniquely supported keys. Values are
+	// always strings, so ConfigInt, ConfigFloat and ConfigBool should be used
+	// to read typed values.
 	Config map[string]string `hcl:",remain"`
 }
 
+// ConfigInt returns the config value of key as an integer. Whole numbers
+// formatted as floats, including those using scientific notation such as
+// 1e+06, are accepted. The boolean return is false if the key is not set.
+func (s *ScalingPolicyStrategy) ConfigInt(key string) (int64, bool, error) {
+	v, ok := s.Config[key]
+	if !ok || v == "" {
+		return 0, false, nil
+	}
+
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return i, true, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f != float64(int64(f)) {
+		return 0, true, fmt.Errorf("invalid value for `%s`: %q is not an integer", key, v)
+	}
+	return int64(f), true, nil
+}
+
+// ConfigFloat returns the config value of key as a float. The boolean return
+// is false if the key is not set.
+func (s *ScalingPolicyStrategy) ConfigFloat(key string) (float64, bool, error) {
+	v, ok := s.Config[key]
+	if !ok || v == "" {
+		return 0, false, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid value for `%s`: %q is not a number", key, v)
+	}
+	return f, true, nil
+}
+
+// ConfigBool returns the config value of key as a boolean. The boolean
+// return is false if the key is not set.
+func (s *ScalingPolicyStrategy) ConfigBool(key string) (bool, bool, error) {
+	v, ok := s.Config[key]
+	if !ok || v == "" {
+		return false, false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, true, fmt.Errorf("invalid value for `%s`: %q is not a boolean", key, v)
+	}
+	return b, true, nil
+}
+
 // ScalingPolicyTarget identifies the target for which the ScalingPolicy as a
 // whole is configured for.
 type ScalingPolicyTarget struct {
@@ -119,6 +503,32 @@ func (t *ScalingPolicyTarget) IsNodePoolTarget() bool {
 	return ok
 }
 
+// Hash returns a hash of the target plugin name and config, which identifies
+// the resource scaled by the target, so policies scaling the same resource
+// can be detected. Config keys handled by the autoscaler rather than the
+// target plugin, such as dry-run, are ignored.
+func (t *ScalingPolicyTarget) Hash() uint64 {
+	keys := make([]string, 0, len(t.Config))
+	for k := range t.Config {
+		if k != TargetConfigKeyDryRun {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	// Separate each value with a zero byte so different configs can't
+	// produce the same input.
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(t.Name))
+	for _, k := range keys {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(t.Config[k]))
+	}
+	return h.Sum64()
+}
+
 type FileDecodeScalingPolicies struct {
 	ScalingPolicies []*FileDecodeScalingPolicy `hcl:"scaling,block"`
 }
@@ -128,30 +538,93 @@ type FileDecodeScalingPolicies struct {
 // flattened when compared to the literal HCL version. Therefore we cannot
 // translate into the internal struct but use this.
 type FileDecodeScalingPolicy struct {
-	Name    string               `hcl:"name,label"`
-	Enabled bool                 `hcl:"enabled,optional"`
-	Type    string               `hcl:"type,optional"`
-	Min     int64                `hcl:"min,optional"`
-	Max     int64                `hcl:"max"`
-	Doc     *FileDecodePolicyDoc `hcl:"policy,block"`
+	Name     string               `hcl:"name,label"`
+	Enabled  bool                 `hcl:"enabled,optional"`
+	Type     string               `hcl:"type,optional"`
+	Min      int64                `hcl:"min,optional"`
+	Max      int64                `hcl:"max"`
+	Template string               `hcl:"template,optional"`
+	Doc      *FileDecodePolicyDoc `hcl:"policy,block"`
 }
 
 type FileDecodePolicyDoc struct {
-	Cooldown              time.Duration
-	CooldownHCL           string `hcl:"cooldown,optional"`
-	EvaluationInterval    time.Duration
-	EvaluationIntervalHCL string                      `hcl:"evaluation_interval,optional"`
-	Checks                []*FileDecodePolicyCheckDoc `hcl:"check,block"`
-	Target                *ScalingPolicyTarget        `hcl:"target,block"`
+	Priority                int   `hcl:"priority,optional"`
+	SoftMax                 int64 `hcl:"soft_max,optional"`
+	Cooldown                time.Duration
+	CooldownHCL             string  `hcl:"cooldown,optional"`
+	CooldownBypassFactor    float64 `hcl:"cooldown_bypass_factor,optional"`
+	ZeroCooldown            time.Duration
+	ZeroCooldownHCL         string `hcl:"zero_cooldown,optional"`
+	CooldownPerUnit         time.Duration
+	CooldownPerUnitHCL      string `hcl:"cooldown_per_unit,optional"`
+	MaxCooldown             time.Duration
+	MaxCooldownHCL          string `hcl:"max_cooldown,optional"`
+	CooldownOnCompletion    bool   `hcl:"cooldown_on_completion,optional"`
+	SupersedeInFlight       bool   `hcl:"supersede_in_flight,optional"`
+	ScaleInStabilization    time.Duration
+	ScaleInStabilizationHCL string `hcl:"scale_in_stabilization_window,optional"`
+	StartupGracePeriod      time.Duration
+	StartupGracePeriodHCL   string `hcl:"startup_grace_period,optional"`
+	DedupWindow             time.Duration
+	DedupWindowHCL          string `hcl:"dedup_window,optional"`
+	EvaluationInterval      time.Duration
+	EvaluationIntervalHCL   string                       `hcl:"evaluation_interval,optional"`
+	OnMetricError           string                       `hcl:"on_metric_error,optional"`
+	SafeCount               int64                        `hcl:"safe_count,optional"`
+	Quorum                  int                          `hcl:"quorum,optional"`
+	ScaleInBias             int64                        `hcl:"scale_in_bias,optional"`
+	ScaleInBiasBand         float64                      `hcl:"scale_in_bias_band,optional"`
+	HysteresisFactor        float64                      `hcl:"hysteresis_factor,optional"`
+	MinChangeCount          int64                        `hcl:"min_change_count,optional"`
+	MinChangePercentage     float64                      `hcl:"min_change_percentage,optional"`
+	RampIntervals           int                          `hcl:"ramp_intervals,optional"`
+	RespectManualOverride   bool                         `hcl:"respect_manual_override,optional"`
+	DryRunSample            float64                      `hcl:"dry_run_sample,optional"`
+	LimitsOnly              bool                         `hcl:"limits_only,optional"`
+	AllowSharedTarget       bool                         `hcl:"allow_shared_target,optional"`
+	Checks                  []*FileDecodePolicyCheckDoc  `hcl:"check,block"`
+	LimitsSchedule          *ScalingPolicyLimitsSchedule `hcl:"limits_schedule,block"`
+	Target                  *ScalingPolicyTarget         `hcl:"target,block"`
+	Tags                    *FileDecodePolicyTags        `hcl:"tags,block"`
+}
+
+type FileDecodePolicyTags struct {
+	Tags map[string]string `hcl:",remain"`
 }
 
 type FileDecodePolicyCheckDoc struct {
-	Name           string `hcl:"name,label"`
-	Source         string `hcl:"source,optional"`
-	Query          string `hcl:"query"`
-	QueryWindow    time.Duration
-	QueryWindowHCL string                 `hcl:"query_window,optional"`
-	Strategy       *ScalingPolicyStrategy `hcl:"strategy,block"`
+	Name                 string `hcl:"name,label"`
+	Source               string `hcl:"source,optional"`
+	Query                string `hcl:"query"`
+	QueryWindow          time.Duration
+	QueryWindowHCL       string `hcl:"query_window,optional"`
+	QueryTimeout         time.Duration
+	QueryTimeoutHCL      string `hcl:"query_timeout,optional"`
+	MaxMetricAge         time.Duration
+	MaxMetricAgeHCL      string                   `hcl:"max_metric_age,optional"`
+	Enabled              *bool                    `hcl:"enabled,optional"`
+	MetricWindow         int                      `hcl:"metric_window,optional"`
+	MetricAggregation    string                   `hcl:"metric_aggregation,optional"`
+	MetricSmoothing      string                   `hcl:"metric_smoothing,optional"`
+	MetricSmoothingAlpha float64                  `hcl:"metric_smoothing_alpha,optional"`
+	SeriesQueries        []string                 `hcl:"series_queries,optional"`
+	SeriesAggregation    string                   `hcl:"series_aggregation,optional"`
+	FormulaQueries       map[string]string        `hcl:"formula_queries,optional"`
+	Formula              string                   `hcl:"formula,optional"`
+	Transforms           []string                 `hcl:"transforms,optional"`
+	Preconditions        []string                 `hcl:"preconditions,optional"`
+	PerInstance          bool                     `hcl:"per_instance,optional"`
+	QueryParams          *FileDecodeQueryParams   `hcl:"query_params,block"`
+	SourceConfig         map[string]string        `hcl:"source_config,optional"`
+	Priority             int                      `hcl:"priority,optional"`
+	Strategies           []*ScalingPolicyStrategy `hcl:"strategy,block"`
+}
+
+type FileDecodeQueryParams struct {
+	Window     time.Duration
+	WindowHCL  string `hcl:"window,optional"`
+	Rollup     string `hcl:"rollup,optional"`
+	Aggregator string `hcl:"aggregator,optional"`
 }
 
 // Translate all values from the decoded policy file into our internal policy
@@ -163,9 +636,38 @@ func (fpd *FileDecodeScalingPolicy) Translate() *ScalingPolicy {
 	p.Max = fpd.Max
 	p.Enabled = fpd.Enabled
 	p.Type = fpd.Type
+	p.Template = fpd.Template
+	p.Priority = fpd.Doc.Priority
+	p.SoftMax = fpd.Doc.SoftMax
 	p.Cooldown = fpd.Doc.Cooldown
+	p.CooldownBypassFactor = fpd.Doc.CooldownBypassFactor
+	p.ZeroCooldown = fpd.Doc.ZeroCooldown
+	p.CooldownPerUnit = fpd.Doc.CooldownPerUnit
+	p.MaxCooldown = fpd.Doc.MaxCooldown
+	p.CooldownOnCompletion = fpd.Doc.CooldownOnCompletion
+	p.SupersedeInFlight = fpd.Doc.SupersedeInFlight
+	p.ScaleInStabilizationWindow = fpd.Doc.ScaleInStabilization
+	p.StartupGracePeriod = fpd.Doc.StartupGracePeriod
+	p.DedupWindow = fpd.Doc.DedupWindow
 	p.EvaluationInterval = fpd.Doc.EvaluationInterval
+	p.OnMetricError = fpd.Doc.OnMetricError
+	p.SafeCount = fpd.Doc.SafeCount
+	p.Quorum = fpd.Doc.Quorum
+	p.ScaleInBias = fpd.Doc.ScaleInBias
+	p.ScaleInBiasBand = fpd.Doc.ScaleInBiasBand
+	p.HysteresisFactor = fpd.Doc.HysteresisFactor
+	p.MinChangeCount = fpd.Doc.MinChangeCount
+	p.MinChangePercentage = fpd.Doc.MinChangePercentage
+	p.RampIntervals = fpd.Doc.RampIntervals
+	p.RespectManualOverride = fpd.Doc.RespectManualOverride
+	p.DryRunSample = fpd.Doc.DryRunSample
+	p.LimitsOnly = fpd.Doc.LimitsOnly
+	p.AllowSharedTarget = fpd.Doc.AllowSharedTarget
+	p.LimitsSchedule = fpd.Doc.LimitsSchedule
 	p.Target = fpd.Doc.Target
+	if fpd.Doc.Tags != nil {
+		p.Tags = fpd.Doc.Tags.Tags
+	}
 
 	fpd.translateChecks(p)
 
@@ -190,5 +692,69 @@ func (fdc *FileDecodePolicyCheckDoc) Translate(c *ScalingPolicyCheck) {
 	c.Source = fdc.Source
 	c.Query = fdc.Query
 	c.QueryWindow = fdc.QueryWindow
-	c.Strategy = fdc.Strategy
+	c.QueryTimeout = fdc.QueryTimeout
+	c.MaxMetricAge = fdc.MaxMetricAge
+	// The first strategy block is the check strategy and any others are run
+	// in order after it.
+	if len(fdc.Strategies) > 0 {
+		c.Strategy = fdc.Strategies[0]
+	}
+	if len(fdc.Strategies) > 1 {
+		c.Chain = fdc.Strategies[1:]
+	}
+	c.Disabled = fdc.Enabled != nil && !*fdc.Enabled
+	c.MetricWindow = fdc.MetricWindow
+	c.MetricAggregation = fdc.MetricAggregation
+	c.MetricSmoothing = fdc.MetricSmoothing
+	c.MetricSmoothingAlpha = fdc.MetricSmoothingAlpha
+	c.SeriesQueries = fdc.SeriesQueries
+	c.SeriesAggregation = fdc.SeriesAggregation
+	c.FormulaQueries = fdc.FormulaQueries
+	c.Formula = fdc.Formula
+	c.Transforms = fdc.Transforms
+	c.Preconditions = fdc.Preconditions
+	c.PerInstance = fdc.PerInstance
+	c.Priority = fdc.Priority
+
+	if fdc.QueryParams != nil {
+		c.QueryParams = &QueryParams{
+			Window:     fdc.QueryParams.Window,
+			Rollup:     fdc.QueryParams.Rollup,
+			Aggregator: fdc.QueryParams.Aggregator,
+		}
+	}
+	c.SourceConfig = fdc.SourceConfig
+}
+
+// CooldownFor returns the cooldown to enforce after scaling the target from
+// the count from to the count to. Transitions to or from zero use the
+// ZeroCooldown if it is set. If CooldownPerUnit is set, it is added for each
+// unit of change, up to MaxCooldown.
+func (p *ScalingPolicy) CooldownFor(from, to int64) time.Duration {
+	cooldown := p.Cooldown
+	if p.ZeroCooldown > 0 && from != to && (from == 0 || to == 0) {
+		cooldown = p.ZeroCooldown
+	}
+
+	if p.CooldownPerUnit <= 0 {
+		return cooldown
+	}
+
+	delta := to - from
+	if delta < 0 {
+		delta = -delta
+	}
+
+	// Avoid overflowing the duration for very large changes.
+	if p.MaxCooldown > 0 && delta > int64((p.MaxCooldown-cooldown)/p.CooldownPerUnit) {
+		return maxDuration(cooldown, p.MaxCooldown)
+	}
+	return cooldown + time.Duration(delta)*p.CooldownPerUnit
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
 }
@@ -30,10 +30,12 @@ func TestFileDecodePolicy_Translate(t *testing.T) {
 							Query:          "how-fast-am-i-going",
 							QueryWindow:    time.Minute,
 							QueryWindowHCL: "1m",
-							Strategy: &ScalingPolicyStrategy{
-								Name: "approach-velocity",
-								Config: map[string]string{
-									"target": "0.01ms",
+							Strategies: []*ScalingPolicyStrategy{
+								{
+									Name: "approach-velocity",
+									Config: map[string]string{
+										"target": "0.01ms",
+									},
 								},
 							},
 						},
@@ -76,6 +78,41 @@ func TestFileDecodePolicy_Translate(t *testing.T) {
 			},
 			name: "fully hydrated decoded policy",
 		},
+		{
+			inputFileDecodePolicy: &FileDecodeScalingPolicy{
+				Enabled: true,
+				Min:     1,
+				Max:     3,
+				Doc: &FileDecodePolicyDoc{
+					Checks: []*FileDecodePolicyCheckDoc{
+						{
+							Name:  "approach-speed",
+							Query: "how-fast-am-i-going",
+							Strategies: []*ScalingPolicyStrategy{
+								{Name: "approach-velocity", Config: map[string]string{"target": "0.01ms"}},
+								{Name: "thruster-limit", Config: map[string]string{"max_change": "1"}},
+							},
+						},
+					},
+				},
+			},
+			expectedOutputPolicy: &ScalingPolicy{
+				Min:     1,
+				Max:     3,
+				Enabled: true,
+				Checks: []*ScalingPolicyCheck{
+					{
+						Name:     "approach-speed",
+						Query:    "how-fast-am-i-going",
+						Strategy: &ScalingPolicyStrategy{Name: "approach-velocity", Config: map[string]string{"target": "0.01ms"}},
+						Chain: []*ScalingPolicyStrategy{
+							{Name: "thruster-limit", Config: map[string]string{"max_change": "1"}},
+						},
+					},
+				},
+			},
+			name: "decoded policy with strategy chain",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -85,3 +122,214 @@ func TestFileDecodePolicy_Translate(t *testing.T) {
 		})
 	}
 }
+
+func TestScalingPolicy_CooldownFor(t *testing.T) {
+	testCases := []struct {
+		inputZeroCooldown    time.Duration
+		inputCooldownPerUnit time.Duration
+		inputMaxCooldown     time.Duration
+		inputFrom            int64
+		inputTo              int64
+		expectedOutput       time.Duration
+		name                 string
+	}{
+		{
+			inputZeroCooldown: time.Hour,
+			inputFrom:         0,
+			inputTo:           3,
+			expectedOutput:    time.Hour,
+			name:              "scale from zero",
+		},
+		{
+			inputZeroCooldown: time.Hour,
+			inputFrom:         3,
+			inputTo:           0,
+			expectedOutput:    time.Hour,
+			name:              "scale to zero",
+		},
+		{
+			inputZeroCooldown: time.Hour,
+			inputFrom:         3,
+			inputTo:           5,
+			expectedOutput:    5 * time.Minute,
+			name:              "scale without crossing zero",
+		},
+		{
+			inputZeroCooldown: time.Hour,
+			inputFrom:         0,
+			inputTo:           0,
+			expectedOutput:    5 * time.Minute,
+			name:              "count unchanged at zero",
+		},
+		{
+			inputZeroCooldown: 0,
+			inputFrom:         3,
+			inputTo:           0,
+			expectedOutput:    5 * time.Minute,
+			name:              "zero cooldown not set",
+		},
+		{
+			inputCooldownPerUnit: 30 * time.Second,
+			inputMaxCooldown:     time.Hour,
+			inputFrom:            3,
+			inputTo:              5,
+			expectedOutput:       6 * time.Minute,
+			name:                 "cooldown per unit scale out",
+		},
+		{
+			inputCooldownPerUnit: 30 * time.Second,
+			inputMaxCooldown:     time.Hour,
+			inputFrom:            10,
+			inputTo:              6,
+			expectedOutput:       7 * time.Minute,
+			name:                 "cooldown per unit scale in",
+		},
+		{
+			inputCooldownPerUnit: 30 * time.Second,
+			inputMaxCooldown:     20 * time.Minute,
+			inputFrom:            2,
+			inputTo:              52,
+			expectedOutput:       20 * time.Minute,
+			name:                 "cooldown per unit bounded by max cooldown",
+		},
+		{
+			inputCooldownPerUnit: time.Hour,
+			inputMaxCooldown:     2 * time.Hour,
+			inputFrom:            0,
+			inputTo:              1 << 40,
+			expectedOutput:       2 * time.Hour,
+			name:                 "cooldown per unit with very large change",
+		},
+		{
+			inputZeroCooldown:    time.Hour,
+			inputCooldownPerUnit: 30 * time.Second,
+			inputMaxCooldown:     30 * time.Minute,
+			inputFrom:            0,
+			inputTo:              4,
+			expectedOutput:       time.Hour,
+			name:                 "max cooldown below zero cooldown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &ScalingPolicy{
+				Cooldown:        5 * time.Minute,
+				ZeroCooldown:    tc.inputZeroCooldown,
+				CooldownPerUnit: tc.inputCooldownPerUnit,
+				MaxCooldown:     tc.inputMaxCooldown,
+			}
+			assert.Equal(t, tc.expectedOutput, p.CooldownFor(tc.inputFrom, tc.inputTo), tc.name)
+		})
+	}
+}
+
+func TestScalingPolicyTarget_Hash(t *testing.T) {
+	target := &ScalingPolicyTarget{
+		Name:   "nomad-target",
+		Config: map[string]string{"Job": "example", "Group": "cache"},
+	}
+
+	testCases := []struct {
+		inputTarget    *ScalingPolicyTarget
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputTarget: &ScalingPolicyTarget{
+				Name:   "nomad-target",
+				Config: map[string]string{"Group": "cache", "Job": "example"},
+			},
+			expectedOutput: true,
+			name:           "same target",
+		},
+		{
+			inputTarget: &ScalingPolicyTarget{
+				Name:   "nomad-target",
+				Config: map[string]string{"Job": "example", "Group": "cache", TargetConfigKeyDryRun: "true"},
+			},
+			expectedOutput: true,
+			name:           "dry-run ignored",
+		},
+		{
+			inputTarget: &ScalingPolicyTarget{
+				Name:   "nomad-target",
+				Config: map[string]string{"Job": "example", "Group": "web"},
+			},
+			expectedOutput: false,
+			name:           "different config",
+		},
+		{
+			inputTarget: &ScalingPolicyTarget{
+				Name:   "other-target",
+				Config: map[string]string{"Job": "example", "Group": "cache"},
+			},
+			expectedOutput: false,
+			name:           "different plugin",
+		},
+		{
+			inputTarget: &ScalingPolicyTarget{
+				Name:   "nomad-target",
+				Config: map[string]string{"Job": "example", "Groupcache": ""},
+			},
+			expectedOutput: false,
+			name:           "concatenated config",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, target.Hash() == tc.inputTarget.Hash(), tc.name)
+		})
+	}
+}
+
+func TestScalingPolicyStrategy_Config(t *testing.T) {
+	s := &ScalingPolicyStrategy{
+		Config: map[string]string{
+			"int":        "5",
+			"scientific": "1e+06",
+			"float":      "0.5",
+			"bool":       "true",
+			"string":     "avg",
+		},
+	}
+
+	i, ok, err := s.ConfigInt("int")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), i)
+
+	i, ok, err = s.ConfigInt("scientific")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1000000), i)
+
+	_, ok, err = s.ConfigInt("float")
+	assert.EqualError(t, err, "invalid value for `float`: \"0.5\" is not an integer")
+	assert.True(t, ok)
+
+	_, ok, err = s.ConfigInt("missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	f, ok, err := s.ConfigFloat("scientific")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, float64(1000000), f)
+
+	_, _, err = s.ConfigFloat("string")
+	assert.EqualError(t, err, "invalid value for `string`: \"avg\" is not a number")
+
+	b, ok, err := s.ConfigBool("bool")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	_, _, err = s.ConfigBool("string")
+	assert.EqualError(t, err, "invalid value for `string`: \"avg\" is not a boolean")
+
+	_, ok, err = s.ConfigBool("missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
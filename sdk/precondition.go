@@ -0,0 +1,115 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// CheckPreconditionReady and CheckPreconditionHealthy are the
+	// preconditions which can be required of the target status before a check
+	// is run, along with comparisons of the status meta such as
+	// "meta.nomad_autoscaler.count.running == 3".
+	//
+	//   ready   requires the target status to be ready.
+	//   healthy requires the target to be running its desired count, using
+	//           the TargetStatusMetaKeyDesiredCount and
+	//           TargetStatusMetaKeyRunningCount meta. Targets which don't
+	//           report both counts never meet it.
+	CheckPreconditionReady   = "ready"
+	CheckPreconditionHealthy = "healthy"
+
+	// checkPreconditionMetaPrefix is the prefix of preconditions comparing a
+	// value of the target status meta.
+	checkPreconditionMetaPrefix = "meta."
+)
+
+// CheckPrecondition is a condition the target status must meet for a check to
+// be run, parsed from its string form.
+type CheckPrecondition struct {
+	raw string
+
+	// name is CheckPreconditionReady, CheckPreconditionHealthy or the meta
+	// key compared by the precondition.
+	name string
+
+	// meta comparisons require the meta value to equal value, or not to if
+	// negate is set.
+	meta   bool
+	value  string
+	negate bool
+}
+
+// ParseCheckPrecondition parses a precondition such as "healthy" or
+// "meta.deployment == successful". Meta comparisons use == or != and compare
+// the value as a string, which can optionally be quoted.
+func ParseCheckPrecondition(s string) (*CheckPrecondition, error) {
+	s = strings.TrimSpace(s)
+
+	switch s {
+	case CheckPreconditionReady, CheckPreconditionHealthy:
+		return &CheckPrecondition{raw: s, name: s}, nil
+	}
+
+	if !strings.HasPrefix(s, checkPreconditionMetaPrefix) {
+		return nil, fmt.Errorf("precondition %q is not supported", s)
+	}
+
+	op, negate := "==", false
+	if strings.Contains(s, "!=") {
+		op, negate = "!=", true
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(s, checkPreconditionMetaPrefix), op, 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("precondition %q must compare the meta value using == or !=", s)
+	}
+
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if key == "" {
+		return nil, fmt.Errorf("precondition %q is missing the meta key", s)
+	}
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+
+	return &CheckPrecondition{raw: s, name: key, meta: true, value: value, negate: negate}, nil
+}
+
+// ParseCheckPreconditions parses each of the passed preconditions, and
+// returns an error for the first which is invalid.
+func ParseCheckPreconditions(preconditions []string) ([]*CheckPrecondition, error) {
+	parsed := make([]*CheckPrecondition, 0, len(preconditions))
+	for _, s := range preconditions {
+		p, err := ParseCheckPrecondition(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+// Met returns whether the target status meets the precondition.
+func (p *CheckPrecondition) Met(status *TargetStatus) bool {
+	if status == nil {
+		return false
+	}
+
+	switch {
+	case p.meta:
+		v, ok := status.Meta[p.name]
+		return (ok && v == p.value) != p.negate
+	case p.name == CheckPreconditionReady:
+		return status.Ready
+	case p.name == CheckPreconditionHealthy:
+		desired, running, ok := status.DesiredAndRunningCounts()
+		return ok && running == desired
+	}
+	return false
+}
+
+// String returns the precondition as it was configured.
+func (p *CheckPrecondition) String() string {
+	return p.raw
+}
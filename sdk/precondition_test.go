@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCheckPrecondition(t *testing.T) {
+	testCases := []struct {
+		input         string
+		expectedError bool
+		name          string
+	}{
+		{input: "ready", name: "ready"},
+		{input: " healthy ", name: "healthy with spaces"},
+		{input: "meta.deployment == successful", name: "meta equal"},
+		{input: `meta.deployment != "running"`, name: "meta not equal quoted"},
+		{input: "stable", expectedError: true, name: "unsupported"},
+		{input: "meta.deployment", expectedError: true, name: "meta without operator"},
+		{input: "meta. == x", expectedError: true, name: "meta without key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCheckPrecondition(tc.input)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+		})
+	}
+}
+
+func TestCheckPrecondition_Met(t *testing.T) {
+	healthy := &TargetStatus{
+		Ready: true,
+		Meta: map[string]string{
+			TargetStatusMetaKeyDesiredCount: "3",
+			TargetStatusMetaKeyRunningCount: "3",
+			"deployment":                    "successful",
+		},
+	}
+	unhealthy := &TargetStatus{
+		Ready: false,
+		Meta: map[string]string{
+			TargetStatusMetaKeyDesiredCount: "3",
+			TargetStatusMetaKeyRunningCount: "2",
+			"deployment":                    "running",
+		},
+	}
+	unknown := &TargetStatus{Ready: true}
+
+	testCases := []struct {
+		inputPrecondition string
+		inputStatus       *TargetStatus
+		expectedOutput    bool
+		name              string
+	}{
+		{"ready", healthy, true, "ready"},
+		{"ready", unhealthy, false, "not ready"},
+		{"healthy", healthy, true, "healthy"},
+		{"healthy", unhealthy, false, "unhealthy"},
+		{"healthy", unknown, false, "healthy without counts"},
+		{"meta.deployment == successful", healthy, true, "meta equal"},
+		{`meta.deployment == "successful"`, unhealthy, false, "meta not equal"},
+		{"meta.deployment != running", healthy, true, "meta negated"},
+		{"meta.deployment != running", unknown, true, "meta negated missing key"},
+		{"meta.deployment == successful", unknown, false, "meta missing key"},
+		{"ready", nil, false, "nil status"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParseCheckPrecondition(tc.inputPrecondition)
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedOutput, p.Met(tc.inputStatus), tc.name)
+		})
+	}
+}
@@ -1,16 +1,40 @@
 package sdk
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 const (
 	// strategyActionMetaKey are standardised keys used by the autoscaler to
 	// populate the ScalingAction Meta mapping with useful information for
 	// operators.
-	strategyActionMetaKeyDryRun        = "nomad_autoscaler.dry_run"
-	strategyActionMetaKeyDryRunCount   = "nomad_autoscaler.dry_run.count"
-	strategyActionMetaKeyCountCapped   = "nomad_autoscaler.count.capped"
-	strategyActionMetaKeyCountOriginal = "nomad_autoscaler.count.original"
-	strategyActionMetaKeyReasonHistory = "nomad_autoscaler.reason_history"
+	strategyActionMetaKeyDryRun           = "nomad_autoscaler.dry_run"
+	strategyActionMetaKeyDryRunCount      = "nomad_autoscaler.dry_run.count"
+	strategyActionMetaKeyDryRunSample     = "nomad_autoscaler.dry_run.sample"
+	strategyActionMetaKeyCountCapped      = "nomad_autoscaler.count.capped"
+	strategyActionMetaKeyCountOriginal    = "nomad_autoscaler.count.original"
+	strategyActionMetaKeyReasonHistory    = "nomad_autoscaler.reason_history"
+	strategyActionMetaKeyCorrelationID    = "nomad_autoscaler.correlation_id"
+	strategyActionMetaKeyDeviation        = "nomad_autoscaler.deviation"
+	strategyActionMetaKeyCooldownBypassed = "nomad_autoscaler.cooldown_bypassed"
+	strategyActionMetaKeyTrigger          = "nomad_autoscaler.trigger"
+	strategyActionMetaKeyTriggeredBy      = "nomad_autoscaler.triggered_by"
+	strategyActionMetaKeyScaleInGuarded   = "nomad_autoscaler.scale_in_guarded"
+	strategyActionMetaKeyCountHealthy     = "nomad_autoscaler.count.healthy"
+	strategyActionMetaKeyTagPrefix        = "nomad_autoscaler.tag."
+	strategyActionMetaKeySupersededCount  = "nomad_autoscaler.superseded.count"
+	strategyActionMetaKeySoftMaxExceeded  = "nomad_autoscaler.soft_max.exceeded"
+	strategyActionMetaKeyCooldown         = "nomad_autoscaler.cooldown"
+	strategyActionMetaKeyScaleInBias      = "nomad_autoscaler.scale_in_bias"
+	strategyActionMetaKeyMinChange        = "nomad_autoscaler.min_change_suppressed"
+	strategyActionMetaKeyRampCount        = "nomad_autoscaler.ramp.count"
+	strategyActionMetaKeyRampStep         = "nomad_autoscaler.ramp.step"
+	strategyActionMetaKeyManualOverride   = "nomad_autoscaler.manual_override"
+	strategyActionMetaKeyNoActionReason   = "nomad_autoscaler.no_action_reason"
+	strategyActionMetaKeyPolicyID         = "nomad_autoscaler.policy_id"
+	strategyActionMetaKeyMetricValue      = "nomad_autoscaler.metric.value"
 
 	// StrategyActionMetaValueDryRunCount is a special count value used when
 	// performing dry-run scaling activities. The Autoscaler will never set a
@@ -19,6 +43,36 @@ const (
 	StrategyActionMetaValueDryRunCount = -1
 )
 
+// The following constants are the reasons a check can report for resulting
+// in no scaling action, using SetNoActionReason.
+const (
+	// NoActionReasonNoChange indicates the strategy calculated the current
+	// count of the target.
+	NoActionReasonNoChange = "no_change"
+
+	// NoActionReasonDeadzone indicates the metric was within the deadzone of
+	// the strategy target.
+	NoActionReasonDeadzone = "deadzone"
+
+	// NoActionReasonNoMetrics indicates the check query returned no metrics.
+	NoActionReasonNoMetrics = "no_metrics"
+
+	// NoActionReasonAtLimit indicates the calculated count was capped to the
+	// current count by the policy min or max.
+	NoActionReasonAtLimit = "at_limit"
+)
+
+// The following constants are the outcomes of sampling the scaling actions of
+// a policy which sets DryRunSample, recorded using SetDryRunSample.
+const (
+	// DryRunSampleLive indicates the action was sampled to scale the target.
+	DryRunSampleLive = "live"
+
+	// DryRunSampleDryRun indicates the action was sampled to be executed in
+	// dry-run mode.
+	DryRunSampleDryRun = "dry_run"
+)
+
 // ScalingAction represents a strategy plugins intention to change the current
 // target state. It includes all the required information to enact the change,
 // along with useful meta information for operators and admins.
@@ -96,6 +150,220 @@ func (a *ScalingAction) SetDryRun() {
 	a.Count = StrategyActionMetaValueDryRunCount
 }
 
+// IsDryRun returns whether the Action has been marked to be executed in
+// dry-run mode using SetDryRun.
+func (a *ScalingAction) IsDryRun() bool {
+	dryRun, _ := a.Meta[strategyActionMetaKeyDryRun].(bool)
+	return dryRun
+}
+
+// SetDryRunSample records whether the Action was sampled to scale the target
+// or to be executed in dry-run mode, using DryRunSampleLive or
+// DryRunSampleDryRun. It does not mark the Action as dry-run itself.
+func (a *ScalingAction) SetDryRunSample(sample string) {
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyDryRunSample] = sample
+}
+
+// DryRunSample returns the outcome recorded by SetDryRunSample, or an empty
+// string if the Action was not sampled.
+func (a *ScalingAction) DryRunSample() string {
+	sample, _ := a.Meta[strategyActionMetaKeyDryRunSample].(string)
+	return sample
+}
+
+// DryRunCount returns the count the Action would have scaled the target to if
+// it was not executed in dry-run mode, and whether the count was recorded.
+// Meta which has been decoded from JSON stores the count as a float64, so
+// all numeric types are handled.
+func (a *ScalingAction) DryRunCount() (int64, bool) {
+	switch v := a.Meta[strategyActionMetaKeyDryRunCount].(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// SetCorrelationID stores the ID of the policy evaluation which generated the
+// Action in Meta. This allows operators to trace an Action, and any events it
+// creates, back to the agent logs for the evaluation.
+func (a *ScalingAction) SetCorrelationID(id string) {
+	a.Meta[strategyActionMetaKeyCorrelationID] = id
+}
+
+// CorrelationID returns the ID of the policy evaluation which generated the
+// Action, or an empty string if it has not been set.
+func (a *ScalingAction) CorrelationID() string {
+	id, _ := a.Meta[strategyActionMetaKeyCorrelationID].(string)
+	return id
+}
+
+// SetTags stores the tags of the policy which generated the Action in Meta,
+// prefixing each key, so the events created by the Action can be attributed
+// in the same way as the policy telemetry.
+func (a *ScalingAction) SetTags(tags map[string]string) {
+	for k, v := range tags {
+		a.Meta[strategyActionMetaKeyTagPrefix+k] = v
+	}
+}
+
+// SetSuperseded stores the count of the in-flight scaling action which the
+// Action supersedes in Meta.
+func (a *ScalingAction) SetSuperseded(inFlight *InFlightAction) {
+	a.Meta[strategyActionMetaKeySupersededCount] = inFlight.Count
+}
+
+// SetSoftMaxExceeded stores the soft max of the policy which the Action count
+// exceeds in Meta, so the events created by the Action highlight the target
+// is approaching its capacity limits.
+func (a *ScalingAction) SetSoftMaxExceeded(softMax int64) {
+	a.Meta[strategyActionMetaKeySoftMaxExceeded] = softMax
+}
+
+// SetManualOverride stores the count of the target before it was changed
+// outside of the autoscaler in Meta, so the events created by the Action show
+// it followed a manual change of the count.
+func (a *ScalingAction) SetManualOverride(from int64) {
+	a.Meta[strategyActionMetaKeyManualOverride] = from
+}
+
+// SetCooldown stores the cooldown enforced after the Action in Meta, so the
+// events created by the Action show when the policy can scale again.
+func (a *ScalingAction) SetCooldown(cooldown time.Duration) {
+	a.Meta[strategyActionMetaKeyCooldown] = cooldown.String()
+}
+
+// ExceedsSoftMax returns true if the Action scales the target out beyond the
+// passed soft max. A soft max of zero is not set and is never exceeded.
+func (a *ScalingAction) ExceedsSoftMax(softMax int64) bool {
+	return softMax > 0 && a.Direction == ScaleDirectionUp && a.Count > softMax
+}
+
+// Supersedes returns true if the Action scales in the opposite direction of
+// the passed in-flight action, and can therefore supersede it.
+func (a *ScalingAction) Supersedes(inFlight *InFlightAction) bool {
+	if inFlight == nil || a.Direction == ScaleDirectionNone {
+		return false
+	}
+	return a.Direction != inFlight.Direction
+}
+
+// SetTrigger stores what caused the evaluation which generated the Action in
+// Meta, along with who requested it if known. This allows manual scaling
+// actions to be distinguished from scheduled ones when auditing events.
+func (a *ScalingAction) SetTrigger(trigger, triggeredBy string) {
+	a.Meta[strategyActionMetaKeyTrigger] = trigger
+	if triggeredBy != "" {
+		a.Meta[strategyActionMetaKeyTriggeredBy] = triggeredBy
+	}
+}
+
+// SetPolicyID stores the ID of the policy which generated the Action in Meta,
+// so targets can relate the scaling action to its policy.
+func (a *ScalingAction) SetPolicyID(id string) {
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyPolicyID] = id
+}
+
+// PolicyID returns the ID of the policy which generated the Action, or an
+// empty string if it has not been set.
+func (a *ScalingAction) PolicyID() string {
+	id, _ := a.Meta[strategyActionMetaKeyPolicyID].(string)
+	return id
+}
+
+// SetMetricValue stores the latest metric of the check which generated the
+// Action in Meta, so the scaling action records the value it was based on.
+func (a *ScalingAction) SetMetricValue(v float64) {
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyMetricValue] = v
+}
+
+// MetricValue returns the latest metric of the check which generated the
+// Action, and whether it was set. Actions which were not generated by a
+// check, such as those enforcing the policy limits, don't have one.
+func (a *ScalingAction) MetricValue() (float64, bool) {
+	switch v := a.Meta[strategyActionMetaKeyMetricValue].(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// MetaWithoutDecisionContext returns a copy of Meta without the policy ID and
+// metric value of the scaling decision. Targets which send Meta to an
+// external system use it so the decision context is only shared when the
+// operator has opted in.
+func (a *ScalingAction) MetaWithoutDecisionContext() map[string]interface{} {
+	meta := make(map[string]interface{}, len(a.Meta))
+	for k, v := range a.Meta {
+		if k == strategyActionMetaKeyPolicyID || k == strategyActionMetaKeyMetricValue {
+			continue
+		}
+		meta[k] = v
+	}
+	return meta
+}
+
+// SetDeviation stores the factor by which the check metric deviates from the
+// strategy target. Strategies which have a target value should set this so
+// the autoscaler can identify large deviations, such as when deciding whether
+// to bypass cooldown.
+func (a *ScalingAction) SetDeviation(factor float64) {
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyDeviation] = factor
+}
+
+// Deviation returns the factor by which the check metric deviates from the
+// strategy target, and whether the strategy set it.
+func (a *ScalingAction) Deviation() (float64, bool) {
+	switch v := a.Meta[strategyActionMetaKeyDeviation].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// SetNoActionReason stores why the Action doesn't scale the target in Meta,
+// such as NoActionReasonDeadzone, so evaluations which don't result in a
+// scaling action can be reported by their reason.
+func (a *ScalingAction) SetNoActionReason(reason string) {
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyNoActionReason] = reason
+}
+
+// NoActionReason returns why the Action doesn't scale the target, or
+// NoActionReasonNoChange if a reason has not been set.
+func (a *ScalingAction) NoActionReason() string {
+	if reason, ok := a.Meta[strategyActionMetaKeyNoActionReason].(string); ok && reason != "" {
+		return reason
+	}
+	return NoActionReasonNoChange
+}
+
+// SetCooldownBypassed marks the Action as having been performed during the
+// policy cooldown due to a large metric deviation.
+func (a *ScalingAction) SetCooldownBypassed() {
+	a.Meta[strategyActionMetaKeyCooldownBypassed] = true
+}
+
 // CapCount caps the value of Count so it remains within the specified limits.
 // If Count is StrategyActionMetaValueDryRunCount this method has no effect.
 func (a *ScalingAction) CapCount(min, max int64) {
@@ -118,6 +386,150 @@ func (a *ScalingAction) CapCount(min, max int64) {
 	}
 }
 
+// GuardScaleIn prevents a scale in action from reducing the count while the
+// number of healthy instances of the target is already at or below the new
+// count, as removing instances could worsen an ongoing incident. If the guard
+// activates, the direction is set to ScaleDirectionNone and the activation is
+// recorded in Meta. The returned bool indicates whether the guard activated.
+func (a *ScalingAction) GuardScaleIn(healthy int64) bool {
+	if a.Direction != ScaleDirectionDown || a.Count == StrategyActionMetaValueDryRunCount || healthy > a.Count {
+		return false
+	}
+
+	a.Meta[strategyActionMetaKeyScaleInGuarded] = true
+	a.Meta[strategyActionMetaKeyCountHealthy] = healthy
+	a.pushReason(fmt.Sprintf("skipped scale in to %d as only %d instances are healthy", a.Count, healthy))
+	a.Direction = ScaleDirectionNone
+	return true
+}
+
+// BiasScaleIn resolves an ambiguous action in favour of fewer instances. An
+// action is ambiguous when the deviation reported by its strategy is within
+// band of the target, such as 0.1 for deviations between 0.9 and 1.1.
+// Actions without a deviation, or whose metric is within the deadzone of the
+// strategy, are never ambiguous.
+//
+// An ambiguous action which keeps the current count is replaced by a scale in
+// of one instance, unless that would take the count below min or the
+// deviation expected after removing the instance would leave the band. This
+// ensures a stable metric results in at most one biased scale in, rather than
+// removing an instance on every evaluation. An ambiguous scale out of at most
+// bias instances is replaced by keeping the current count. If the bias is
+// applied, it is recorded in Meta. The returned bool indicates whether the
+// bias was applied.
+func (a *ScalingAction) BiasScaleIn(current, bias, min int64, band float64) bool {
+	if bias <= 0 || band <= 0 || a.Count == StrategyActionMetaValueDryRunCount {
+		return false
+	}
+
+	if reason, _ := a.Meta[strategyActionMetaKeyNoActionReason].(string); reason == NoActionReasonDeadzone {
+		return false
+	}
+
+	deviation, ok := a.Deviation()
+	if !ok || math.Abs(deviation-1) > band {
+		return false
+	}
+
+	switch {
+	case a.Direction == ScaleDirectionNone && current > min &&
+		deviation*float64(current)/float64(current-1) <= 1+band:
+		a.Canonicalize()
+		a.pushReason(fmt.Sprintf("scaled in to %d due to scale in bias of %d", current-1, bias))
+		a.Count = current - 1
+		a.Direction = ScaleDirectionDown
+	case a.Direction == ScaleDirectionUp && a.Count-current <= bias:
+		a.Canonicalize()
+		a.pushReason(fmt.Sprintf("skipped scale out to %d due to scale in bias of %d", a.Count, bias))
+		a.Count = current
+		a.Direction = ScaleDirectionNone
+	default:
+		return false
+	}
+
+	a.Meta[strategyActionMetaKeyScaleInBias] = bias
+	return true
+}
+
+// SuppressMinChange prevents an action from changing the count by fewer than
+// minCount instances, or by less than minPercentage percent of the current
+// count, to avoid churn from trivial changes. A zero threshold is ignored, as
+// is the percentage when the current count is zero. If the action is
+// suppressed, the direction is set to ScaleDirectionNone and the suppression
+// is recorded in Meta. The returned bool indicates whether the action was
+// suppressed.
+func (a *ScalingAction) SuppressMinChange(current, minCount int64, minPercentage float64) bool {
+	if a.Direction == ScaleDirectionNone || a.Count == StrategyActionMetaValueDryRunCount {
+		return false
+	}
+
+	change := a.Count - current
+	if change < 0 {
+		change = -change
+	}
+
+	var reason string
+	switch {
+	case minCount > 0 && change < minCount:
+		reason = fmt.Sprintf("suppressed scaling from %d to %d as the change of %d is below the minimum change count of %d",
+			current, a.Count, change, minCount)
+	case minPercentage > 0 && current > 0 && float64(change)*100 < minPercentage*float64(current):
+		reason = fmt.Sprintf("suppressed scaling from %d to %d as the change of %d is below the minimum change percentage of %g",
+			current, a.Count, change, minPercentage)
+	default:
+		return false
+	}
+
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyMinChange] = true
+	a.pushReason(reason)
+	a.Direction = ScaleDirectionNone
+	return true
+}
+
+// Ramp limits the action to count, which is a step of a ramp towards the
+// action count spread over intervals evaluations. The count the action was
+// ramping towards and the step are recorded in Meta. If Count is
+// StrategyActionMetaValueDryRunCount, or already equals count, this method
+// has no effect.
+func (a *ScalingAction) Ramp(count int64, step, intervals int) {
+	if a.Count == StrategyActionMetaValueDryRunCount || a.Count == count {
+		return
+	}
+
+	a.Canonicalize()
+	a.Meta[strategyActionMetaKeyRampCount] = a.Count
+	a.Meta[strategyActionMetaKeyRampStep] = step
+	a.pushReason(fmt.Sprintf("ramping count towards %d over %d evaluations, step %d scales to %d",
+		a.Count, intervals, step, count))
+	a.Count = count
+}
+
+// MergeReasonHistory adds the reasons of the previous action, including its
+// reason history, to the start of the reason history of the action. It is
+// used when the action refines the count proposed by a previous action, such
+// as when strategies are chained, so the final action describes every step.
+func (a *ScalingAction) MergeReasonHistory(prev *ScalingAction) {
+	a.Canonicalize()
+
+	history := prev.ReasonHistory()
+	if prev.Reason != "" {
+		history = append(history, prev.Reason)
+	}
+	history = append(history, a.ReasonHistory()...)
+
+	if len(history) > 0 {
+		a.Meta[strategyActionMetaKeyReasonHistory] = history
+	}
+}
+
+// ReasonHistory returns a copy of the previous reasons of the action, such as
+// the decisions of the strategies and guards which modified it, oldest first.
+func (a *ScalingAction) ReasonHistory() []string {
+	history, _ := a.Meta[strategyActionMetaKeyReasonHistory].([]string)
+	return append([]string{}, history...)
+}
+
 // PushReason updates the Reason value and stores previous Reason into Meta.
 func (a *ScalingAction) pushReason(r string) {
 	history := []string{}
@@ -147,8 +559,8 @@ func (a *ScalingAction) pushReason(r string) {
 // option, where safest is defined as lowest impact in the underlying
 // infrastructure:
 //
-//   * ScaleDirectionUp: Action with highest count
-//   * ScaleDirectionDown: Action with highest count
+//   - ScaleDirectionUp: Action with highest count
+//   - ScaleDirectionDown: Action with highest count
 func PreemptScalingAction(a *ScalingAction, b *ScalingAction) *ScalingAction {
 	if a == nil {
 		return b
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -80,6 +81,278 @@ func TestAction_SetDryRun(t *testing.T) {
 	}
 }
 
+func TestAction_IsDryRun(t *testing.T) {
+	testCases := []struct {
+		inputAction    *ScalingAction
+		expectedDryRun bool
+		expectedCount  int64
+		expectedOK     bool
+		name           string
+	}{
+		{
+			inputAction: &ScalingAction{Count: 3, Meta: map[string]interface{}{}},
+			name:        "not dry-run",
+		},
+		{
+			inputAction: &ScalingAction{
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.dry_run":       true,
+					"nomad_autoscaler.dry_run.count": int64(3),
+				},
+			},
+			expectedDryRun: true,
+			expectedCount:  3,
+			expectedOK:     true,
+			name:           "dry-run",
+		},
+		{
+			inputAction: &ScalingAction{
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.dry_run":       true,
+					"nomad_autoscaler.dry_run.count": float64(5),
+				},
+			},
+			expectedDryRun: true,
+			expectedCount:  5,
+			expectedOK:     true,
+			name:           "dry-run decoded from JSON",
+		},
+		{
+			inputAction: &ScalingAction{
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.dry_run":       "true",
+					"nomad_autoscaler.dry_run.count": "3",
+				},
+			},
+			name: "invalid meta types",
+		},
+		{
+			inputAction: &ScalingAction{},
+			name:        "nil meta",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDryRun, tc.inputAction.IsDryRun(), tc.name)
+			count, ok := tc.inputAction.DryRunCount()
+			assert.Equal(t, tc.expectedCount, count, tc.name)
+			assert.Equal(t, tc.expectedOK, ok, tc.name)
+		})
+	}
+
+	// The accessors return the values set by SetDryRun.
+	a := &ScalingAction{Count: 7, Meta: map[string]interface{}{}}
+	a.SetDryRun()
+	assert.True(t, a.IsDryRun())
+	count, ok := a.DryRunCount()
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), count)
+}
+
+func TestAction_SetCorrelationID(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	assert.Equal(t, "", a.CorrelationID())
+
+	a.SetCorrelationID("b7b5d4b0-0e7a-4c53-9c2e-6a1d8b3f1e2a")
+	assert.Equal(t, "b7b5d4b0-0e7a-4c53-9c2e-6a1d8b3f1e2a", a.Meta["nomad_autoscaler.correlation_id"])
+	assert.Equal(t, "b7b5d4b0-0e7a-4c53-9c2e-6a1d8b3f1e2a", a.CorrelationID())
+}
+
+func TestAction_SetTags(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetTags(nil)
+	assert.Equal(t, map[string]interface{}{}, a.Meta)
+
+	a.SetTags(map[string]string{"team": "infra", "tier": "web"})
+	assert.Equal(t, map[string]interface{}{
+		"nomad_autoscaler.tag.team": "infra",
+		"nomad_autoscaler.tag.tier": "web",
+	}, a.Meta)
+}
+
+func TestAction_Supersedes(t *testing.T) {
+	inFlight := &InFlightAction{Count: 10, Direction: ScaleDirectionUp}
+
+	testCases := []struct {
+		inputAction    *ScalingAction
+		inputInFlight  *InFlightAction
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputAction:    &ScalingAction{Count: 5, Direction: ScaleDirectionDown},
+			inputInFlight:  inFlight,
+			expectedOutput: true,
+			name:           "opposite direction",
+		},
+		{
+			inputAction:    &ScalingAction{Count: 12, Direction: ScaleDirectionUp},
+			inputInFlight:  inFlight,
+			expectedOutput: false,
+			name:           "same direction",
+		},
+		{
+			inputAction:    &ScalingAction{Direction: ScaleDirectionNone},
+			inputInFlight:  inFlight,
+			expectedOutput: false,
+			name:           "no direction",
+		},
+		{
+			inputAction:    &ScalingAction{Count: 5, Direction: ScaleDirectionDown},
+			inputInFlight:  nil,
+			expectedOutput: false,
+			name:           "nothing in-flight",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, tc.inputAction.Supersedes(tc.inputInFlight), tc.name)
+		})
+	}
+
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetSuperseded(inFlight)
+	assert.Equal(t, map[string]interface{}{"nomad_autoscaler.superseded.count": int64(10)}, a.Meta)
+}
+
+func TestAction_ExceedsSoftMax(t *testing.T) {
+	testCases := []struct {
+		inputAction    *ScalingAction
+		inputSoftMax   int64
+		expectedOutput bool
+		name           string
+	}{
+		{
+			inputAction:    &ScalingAction{Count: 6, Direction: ScaleDirectionUp},
+			inputSoftMax:   5,
+			expectedOutput: true,
+			name:           "scale out above soft max",
+		},
+		{
+			inputAction:    &ScalingAction{Count: 5, Direction: ScaleDirectionUp},
+			inputSoftMax:   5,
+			expectedOutput: false,
+			name:           "scale out to soft max",
+		},
+		{
+			inputAction:    &ScalingAction{Count: 6, Direction: ScaleDirectionDown},
+			inputSoftMax:   5,
+			expectedOutput: false,
+			name:           "scale in above soft max",
+		},
+		{
+			inputAction:    &ScalingAction{Count: 6, Direction: ScaleDirectionUp},
+			inputSoftMax:   0,
+			expectedOutput: false,
+			name:           "soft max not set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, tc.inputAction.ExceedsSoftMax(tc.inputSoftMax), tc.name)
+		})
+	}
+
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetSoftMaxExceeded(5)
+	assert.Equal(t, map[string]interface{}{"nomad_autoscaler.soft_max.exceeded": int64(5)}, a.Meta)
+}
+
+func TestAction_SetManualOverride(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetManualOverride(3)
+	assert.Equal(t, map[string]interface{}{"nomad_autoscaler.manual_override": int64(3)}, a.Meta)
+}
+
+func TestAction_SetCooldown(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetCooldown(7*time.Minute + 30*time.Second)
+	assert.Equal(t, map[string]interface{}{"nomad_autoscaler.cooldown": "7m30s"}, a.Meta)
+}
+
+func TestAction_SetTrigger(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetTrigger(EvaluationTriggerScheduled, "")
+	assert.Equal(t, map[string]interface{}{"nomad_autoscaler.trigger": "scheduled"}, a.Meta)
+
+	a = &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetTrigger(EvaluationTriggerManual, "alice")
+	assert.Equal(t, map[string]interface{}{
+		"nomad_autoscaler.trigger":      "manual",
+		"nomad_autoscaler.triggered_by": "alice",
+	}, a.Meta)
+}
+
+func TestAction_SetPolicyID(t *testing.T) {
+	a := &ScalingAction{}
+	assert.Equal(t, "", a.PolicyID())
+
+	a.SetPolicyID("4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39")
+	assert.Equal(t, "4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39", a.Meta["nomad_autoscaler.policy_id"])
+	assert.Equal(t, "4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39", a.PolicyID())
+}
+
+func TestAction_SetMetricValue(t *testing.T) {
+	a := &ScalingAction{}
+	_, ok := a.MetricValue()
+	assert.False(t, ok)
+
+	a.SetMetricValue(72.5)
+	assert.Equal(t, 72.5, a.Meta["nomad_autoscaler.metric.value"])
+
+	value, ok := a.MetricValue()
+	assert.True(t, ok)
+	assert.Equal(t, 72.5, value)
+}
+
+func TestAction_MetaWithoutDecisionContext(t *testing.T) {
+	a := &ScalingAction{Meta: map[string]interface{}{}}
+	a.SetCorrelationID("9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64")
+	a.SetPolicyID("4f1c8e2a-7b3d-4a6e-9c5f-2d8b1e0a7c39")
+	a.SetMetricValue(72.5)
+
+	assert.Equal(t, map[string]interface{}{
+		"nomad_autoscaler.correlation_id": "9c2d7e4a-1f6b-4c8e-a3d5-7b0e2f9c1a64",
+	}, a.MetaWithoutDecisionContext())
+
+	// The meta of the action is not modified.
+	assert.Len(t, a.Meta, 3)
+}
+
+func TestAction_SetDeviation(t *testing.T) {
+	a := &ScalingAction{}
+	_, ok := a.Deviation()
+	assert.False(t, ok)
+
+	a.SetDeviation(2.5)
+	assert.Equal(t, 2.5, a.Meta["nomad_autoscaler.deviation"])
+
+	deviation, ok := a.Deviation()
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, deviation)
+}
+
+func TestAction_SetNoActionReason(t *testing.T) {
+	a := &ScalingAction{}
+	assert.Equal(t, NoActionReasonNoChange, a.NoActionReason())
+
+	a.SetNoActionReason(NoActionReasonDeadzone)
+	assert.Equal(t, "deadzone", a.Meta["nomad_autoscaler.no_action_reason"])
+	assert.Equal(t, NoActionReasonDeadzone, a.NoActionReason())
+}
+
+func TestAction_SetDryRunSample(t *testing.T) {
+	a := &ScalingAction{}
+	assert.Equal(t, "", a.DryRunSample())
+
+	a.SetDryRunSample(DryRunSampleDryRun)
+	assert.Equal(t, "dry_run", a.Meta["nomad_autoscaler.dry_run.sample"])
+	assert.Equal(t, DryRunSampleDryRun, a.DryRunSample())
+}
+
 func TestAction_CapCount(t *testing.T) {
 	testCases := []struct {
 		inputAction          *ScalingAction
@@ -173,6 +446,510 @@ func TestAction_CapCount(t *testing.T) {
 	}
 }
 
+func TestAction_GuardScaleIn(t *testing.T) {
+	testCases := []struct {
+		inputAction          *ScalingAction
+		inputHealthy         int64
+		expectedOutput       bool
+		expectedOutputAction *ScalingAction
+		name                 string
+	}{
+		{
+			inputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling down",
+			},
+			inputHealthy:   5,
+			expectedOutput: true,
+			expectedOutputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionNone,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.scale_in_guarded": true,
+					"nomad_autoscaler.count.healthy":    int64(5),
+					"nomad_autoscaler.reason_history":   []string{"scaling down"},
+				},
+				Reason: "skipped scale in to 6 as only 5 instances are healthy",
+			},
+			name: "healthy count below new count",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+			},
+			inputHealthy:   8,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+			},
+			name: "healthy count above new count",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			inputHealthy:   2,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			name: "scale out is not guarded",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, tc.inputAction.GuardScaleIn(tc.inputHealthy), tc.name)
+			assert.Equal(t, tc.expectedOutputAction, tc.inputAction, tc.name)
+		})
+	}
+}
+
+func TestAction_SuppressMinChange(t *testing.T) {
+	testCases := []struct {
+		inputAction          *ScalingAction
+		inputCurrent         int64
+		inputMinCount        int64
+		inputMinPercentage   float64
+		expectedOutput       bool
+		expectedOutputAction *ScalingAction
+		name                 string
+	}{
+		{
+			inputAction: &ScalingAction{
+				Count:     101,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling up",
+			},
+			inputCurrent:   100,
+			inputMinCount:  2,
+			expectedOutput: true,
+			expectedOutputAction: &ScalingAction{
+				Count:     101,
+				Direction: ScaleDirectionNone,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.min_change_suppressed": true,
+					"nomad_autoscaler.reason_history":        []string{"scaling up"},
+				},
+				Reason: "suppressed scaling from 100 to 101 as the change of 1 is below the minimum change count of 2",
+			},
+			name: "change below min count",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     96,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling down",
+			},
+			inputCurrent:       100,
+			inputMinPercentage: 5,
+			expectedOutput:     true,
+			expectedOutputAction: &ScalingAction{
+				Count:     96,
+				Direction: ScaleDirectionNone,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.min_change_suppressed": true,
+					"nomad_autoscaler.reason_history":        []string{"scaling down"},
+				},
+				Reason: "suppressed scaling from 100 to 96 as the change of 4 is below the minimum change percentage of 5",
+			},
+			name: "change below min percentage",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     95,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+			},
+			inputCurrent:       100,
+			inputMinCount:      5,
+			inputMinPercentage: 5,
+			expectedOutput:     false,
+			expectedOutputAction: &ScalingAction{
+				Count:     95,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{},
+			},
+			name: "change at thresholds",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     1,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			inputCurrent:       0,
+			inputMinPercentage: 50,
+			expectedOutput:     false,
+			expectedOutputAction: &ScalingAction{
+				Count:     1,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			name: "percentage ignored when scaling from zero",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     101,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			inputCurrent:   100,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     101,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			name: "no thresholds",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     StrategyActionMetaValueDryRunCount,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			inputCurrent:   100,
+			inputMinCount:  2,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     StrategyActionMetaValueDryRunCount,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			name: "dry-run action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.inputAction.SuppressMinChange(tc.inputCurrent, tc.inputMinCount, tc.inputMinPercentage)
+			assert.Equal(t, tc.expectedOutput, actual, tc.name)
+			assert.Equal(t, tc.expectedOutputAction, tc.inputAction, tc.name)
+		})
+	}
+}
+
+func TestAction_Ramp(t *testing.T) {
+	testCases := []struct {
+		inputAction          *ScalingAction
+		inputCount           int64
+		expectedOutputAction *ScalingAction
+		name                 string
+	}{
+		{
+			inputAction: &ScalingAction{
+				Count:     10,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling up",
+			},
+			inputCount: 6,
+			expectedOutputAction: &ScalingAction{
+				Count:     6,
+				Direction: ScaleDirectionUp,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.ramp.count":     int64(10),
+					"nomad_autoscaler.ramp.step":      1,
+					"nomad_autoscaler.reason_history": []string{"scaling up"},
+				},
+				Reason: "ramping count towards 10 over 3 evaluations, step 1 scales to 6",
+			},
+			name: "ramped action",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     10,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling up",
+			},
+			inputCount: 10,
+			expectedOutputAction: &ScalingAction{
+				Count:     10,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+				Reason:    "scaling up",
+			},
+			name: "final step",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     StrategyActionMetaValueDryRunCount,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			inputCount: 6,
+			expectedOutputAction: &ScalingAction{
+				Count:     StrategyActionMetaValueDryRunCount,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{},
+			},
+			name: "dry-run action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.inputAction.Ramp(tc.inputCount, 1, 3)
+			assert.Equal(t, tc.expectedOutputAction, tc.inputAction, tc.name)
+		})
+	}
+}
+
+func TestAction_BiasScaleIn(t *testing.T) {
+	testCases := []struct {
+		inputAction          *ScalingAction
+		inputCurrent         int64
+		inputBias            int64
+		inputMin             int64
+		inputBand            float64
+		expectedOutput       bool
+		expectedOutputAction *ScalingAction
+		name                 string
+	}{
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Reason:    "within tolerance",
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			inputCurrent:   10,
+			inputBias:      1,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: true,
+			expectedOutputAction: &ScalingAction{
+				Count:     9,
+				Direction: ScaleDirectionDown,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.deviation":      0.95,
+					"nomad_autoscaler.scale_in_bias":  int64(1),
+					"nomad_autoscaler.reason_history": []string{"within tolerance"},
+				},
+				Reason: "scaled in to 9 due to scale in bias of 1",
+			},
+			name: "no change within band scales in",
+		},
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0},
+			},
+			inputCurrent:   5,
+			inputBias:      1,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.0},
+			},
+			name: "no change which would leave band",
+		},
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.7},
+			},
+			inputCurrent:   10,
+			inputBias:      1,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.7},
+			},
+			name: "no change outside band",
+		},
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.no_action_reason": "deadzone"},
+			},
+			inputCurrent:   10,
+			inputBias:      1,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.no_action_reason": "deadzone"},
+			},
+			name: "deadzone",
+		},
+		{
+			inputAction:          &ScalingAction{Direction: ScaleDirectionNone},
+			inputCurrent:         10,
+			inputBias:            1,
+			inputMin:             1,
+			inputBand:            0.1,
+			expectedOutput:       false,
+			expectedOutputAction: &ScalingAction{Direction: ScaleDirectionNone},
+			name:                 "no deviation",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     7,
+				Direction: ScaleDirectionUp,
+				Reason:    "scaling up",
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.08},
+			},
+			inputCurrent:   5,
+			inputBias:      2,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: true,
+			expectedOutputAction: &ScalingAction{
+				Count:     5,
+				Direction: ScaleDirectionNone,
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.deviation":      1.08,
+					"nomad_autoscaler.scale_in_bias":  int64(2),
+					"nomad_autoscaler.reason_history": []string{"scaling up"},
+				},
+				Reason: "skipped scale out to 7 due to scale in bias of 2",
+			},
+			name: "scale out within bias and band keeps count",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     7,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.4},
+			},
+			inputCurrent:   5,
+			inputBias:      2,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     7,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.4},
+			},
+			name: "scale out with large deviation",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     8,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.05},
+			},
+			inputCurrent:   5,
+			inputBias:      2,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     8,
+				Direction: ScaleDirectionUp,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 1.05},
+			},
+			name: "scale out above bias",
+		},
+		{
+			inputAction: &ScalingAction{
+				Count:     3,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			inputCurrent:   5,
+			inputBias:      2,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Count:     3,
+				Direction: ScaleDirectionDown,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			name: "scale in",
+		},
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			inputCurrent:   1,
+			inputBias:      1,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			name: "no change at min",
+		},
+		{
+			inputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			inputCurrent:   10,
+			inputBias:      0,
+			inputMin:       1,
+			inputBand:      0.1,
+			expectedOutput: false,
+			expectedOutputAction: &ScalingAction{
+				Direction: ScaleDirectionNone,
+				Meta:      map[string]interface{}{"nomad_autoscaler.deviation": 0.95},
+			},
+			name: "no bias",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput,
+				tc.inputAction.BiasScaleIn(tc.inputCurrent, tc.inputBias, tc.inputMin, tc.inputBand), tc.name)
+			assert.Equal(t, tc.expectedOutputAction, tc.inputAction, tc.name)
+		})
+	}
+}
+
+func TestAction_BiasScaleIn_stableMetric(t *testing.T) {
+	// The load is steady and slightly below the target of 10 instances, but
+	// outside of any deadzone, so every evaluation is ambiguous. The bias
+	// scales in once, after which the metric moves towards the edge of the
+	// band and the count is kept.
+	const load = 9.5
+	current := int64(10)
+
+	for i := 0; i < 20; i++ {
+		a := &ScalingAction{Direction: ScaleDirectionNone}
+		a.SetDeviation(load / float64(current))
+
+		if a.BiasScaleIn(current, 1, 1, 0.1) {
+			current = a.Count
+		}
+	}
+	assert.Equal(t, int64(9), current)
+
+	// A metric exactly on target keeps the count on every evaluation.
+	current = 10
+	for i := 0; i < 20; i++ {
+		a := &ScalingAction{Direction: ScaleDirectionNone}
+		a.SetDeviation(1)
+
+		assert.False(t, a.BiasScaleIn(current, 1, 1, 0.1))
+	}
+}
+
 func TestAction_pushReason(t *testing.T) {
 	testCases := []struct {
 		inputAction          *ScalingAction
@@ -218,6 +995,57 @@ func TestAction_pushReason(t *testing.T) {
 	}
 }
 
+func TestAction_MergeReasonHistory(t *testing.T) {
+	testCases := []struct {
+		inputAction          *ScalingAction
+		inputPrev            *ScalingAction
+		expectedOutputAction *ScalingAction
+		name                 string
+	}{
+		{
+			inputAction: &ScalingAction{Reason: "limited change to 1"},
+			inputPrev:   &ScalingAction{},
+			expectedOutputAction: &ScalingAction{
+				Reason: "limited change to 1",
+				Meta:   map[string]interface{}{},
+			},
+			name: "no previous reasons",
+		},
+		{
+			inputAction: &ScalingAction{
+				Reason: "limited change to 1",
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.reason_history": []string{"smoothed count"},
+				},
+			},
+			inputPrev: &ScalingAction{
+				Reason: "scaling up because factor is 2.0",
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.reason_history": []string{"capped count from 0 to 1 to stay within limits"},
+				},
+			},
+			expectedOutputAction: &ScalingAction{
+				Reason: "limited change to 1",
+				Meta: map[string]interface{}{
+					"nomad_autoscaler.reason_history": []string{
+						"capped count from 0 to 1 to stay within limits",
+						"scaling up because factor is 2.0",
+						"smoothed count",
+					},
+				},
+			},
+			name: "previous reasons come first",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.inputAction.MergeReasonHistory(tc.inputPrev)
+			assert.Equal(t, tc.expectedOutputAction, tc.inputAction)
+		})
+	}
+}
+
 func TestPreemptAction(t *testing.T) {
 	testCases := []struct {
 		name     string
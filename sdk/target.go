@@ -1,5 +1,19 @@
 package sdk
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// ErrQuotaExhausted is returned, optionally wrapped, by target plugins when a
+// quota prevents the target from being scaled at all. The scaling action is
+// then treated as not having been taken, rather than as a failure.
+var ErrQuotaExhausted = errors.New("quota exhausted")
+
 // TargetStatus is the response object when performing the Status call of the
 // target plugin interface. The response details key information about the
 // current state of the target.
@@ -27,6 +41,31 @@ const (
 	// cooldown where out-of-band scaling activities have been triggered.
 	TargetStatusMetaKeyLastEvent = "nomad_autoscaler.last_event"
 
+	// TargetStatusMetaKeyDesiredCount is an optional meta key that can be
+	// added to the status return. The value is the number of instances the
+	// target has been asked to run, which can differ from the number running
+	// while instances are being placed or are failing.
+	TargetStatusMetaKeyDesiredCount = "nomad_autoscaler.count.desired"
+
+	// TargetStatusMetaKeyRunningCount is an optional meta key that can be
+	// added to the status return alongside TargetStatusMetaKeyDesiredCount.
+	// The value is the number of instances of the target currently running.
+	TargetStatusMetaKeyRunningCount = "nomad_autoscaler.count.running"
+
+	// TargetStatusMetaKeyManualOverride is added to the status by the
+	// autoscaler when it detects the target count was changed outside of the
+	// autoscaler since the previous evaluation, such as by an operator
+	// setting the count manually. The value is the count before the change.
+	// It can be used by check preconditions to skip checks after a manual
+	// change.
+	TargetStatusMetaKeyManualOverride = "nomad_autoscaler.manual_override"
+
+	// TargetStatusMetaKeyQuotaCappedCount is an optional meta key that can be
+	// added to the status return. The value is the count the most recent
+	// scaling action was capped to by a quota, as the target could not be
+	// scaled past it.
+	TargetStatusMetaKeyQuotaCappedCount = "nomad_autoscaler.quota.capped_count"
+
 	// TargetConfigKeyJob is the config key used within horizontal app scaling
 	// to identify the Nomad job targeted for autoscaling.
 	TargetConfigKeyJob = "Job"
@@ -53,4 +92,134 @@ const (
 	// Nomad clients are purged from Nomad once they have been terminated
 	// within their provider.
 	TargetConfigKeyNodePurge = "node_purge"
+
+	// TargetConfigKeyDryRun is the config key which enables dry-run for the
+	// policy. It is handled by the autoscaler rather than the target plugin,
+	// so it is accepted by all target config schemas.
+	TargetConfigKeyDryRun = "dry-run"
 )
+
+const (
+	// TargetConfigTypeString, TargetConfigTypeInt, TargetConfigTypeFloat and
+	// TargetConfigTypeBool are the value types which can be declared within a
+	// TargetConfigSchema.
+	TargetConfigTypeString = "string"
+	TargetConfigTypeInt    = "int"
+	TargetConfigTypeFloat  = "float"
+	TargetConfigTypeBool   = "bool"
+)
+
+// TargetConfigSchema describes the config keys accepted by a target plugin,
+// so the config of policies using the target can be validated when they are
+// loaded rather than failing when the target is scaled.
+type TargetConfigSchema struct {
+
+	// Keys maps each accepted config key to its description.
+	Keys map[string]*TargetConfigKey
+
+	// AllowUnknown indicates keys which are not described in Keys are
+	// accepted. This allows schemas to describe only some keys of targets
+	// which support dynamic config.
+	AllowUnknown bool
+}
+
+// TargetConfigKey describes an individual config key of a target.
+type TargetConfigKey struct {
+
+	// Type is the type the value must be parseable as. An empty value is
+	// handled as TargetConfigTypeString.
+	Type string
+
+	// Required indicates the key must be set.
+	Required bool
+}
+
+// Validate checks the passed target config against the schema, returning an
+// error for each missing, unknown or wrongly typed key.
+func (s *TargetConfigSchema) Validate(config map[string]string) error {
+	var mErr *multierror.Error
+
+	// Sort the keys so errors are reported in a stable order.
+	keys := make([]string, 0, len(s.Keys))
+	for k := range s.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, ok := config[k]
+		if !ok {
+			if s.Keys[k].Required {
+				mErr = multierror.Append(mErr, fmt.Errorf("missing required key %q", k))
+			}
+			continue
+		}
+		if err := validateTargetConfigType(s.Keys[k].Type, v); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("key %q %v", k, err))
+		}
+	}
+
+	if !s.AllowUnknown {
+		var unknown []string
+		for k := range config {
+			if _, ok := s.Keys[k]; !ok && k != TargetConfigKeyDryRun {
+				unknown = append(unknown, k)
+			}
+		}
+		sort.Strings(unknown)
+
+		for _, k := range unknown {
+			mErr = multierror.Append(mErr, fmt.Errorf("unknown key %q", k))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// validateTargetConfigType returns an error if the value can't be parsed as
+// the passed type.
+func validateTargetConfigType(t, v string) error {
+	var err error
+
+	switch t {
+	case "", TargetConfigTypeString:
+	case TargetConfigTypeInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case TargetConfigTypeFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case TargetConfigTypeBool:
+		_, err = strconv.ParseBool(v)
+	default:
+		return fmt.Errorf("has unsupported type %q", t)
+	}
+
+	if err != nil {
+		return fmt.Errorf("must be %s, found %q", t, v)
+	}
+	return nil
+}
+
+// QuotaCappedCount returns the count the most recent scaling action of the
+// target was capped to by a quota. The boolean return indicates whether the
+// target reported one.
+func (t *TargetStatus) QuotaCappedCount() (int64, bool) {
+	count, err := strconv.ParseInt(t.Meta[TargetStatusMetaKeyQuotaCappedCount], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return count, true
+}
+
+// DesiredAndRunningCounts returns the desired and running counts of the
+// target. The boolean return indicates whether the target reported both.
+func (t *TargetStatus) DesiredAndRunningCounts() (desired, running int64, ok bool) {
+	desired, err := strconv.ParseInt(t.Meta[TargetStatusMetaKeyDesiredCount], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	running, err = strconv.ParseInt(t.Meta[TargetStatusMetaKeyRunningCount], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return desired, running, true
+}
@@ -0,0 +1,162 @@
+package sdk
+
+import (
+	"testing"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetStatus_DesiredAndRunningCounts(t *testing.T) {
+	testCases := []struct {
+		inputMeta       map[string]string
+		expectedDesired int64
+		expectedRunning int64
+		expectedOK      bool
+		name            string
+	}{
+		{
+			inputMeta: map[string]string{
+				TargetStatusMetaKeyDesiredCount: "5",
+				TargetStatusMetaKeyRunningCount: "3",
+			},
+			expectedDesired: 5,
+			expectedRunning: 3,
+			expectedOK:      true,
+			name:            "both counts reported",
+		},
+		{
+			inputMeta:  map[string]string{TargetStatusMetaKeyDesiredCount: "5"},
+			expectedOK: false,
+			name:       "running count not reported",
+		},
+		{
+			inputMeta: map[string]string{
+				TargetStatusMetaKeyDesiredCount: "five",
+				TargetStatusMetaKeyRunningCount: "3",
+			},
+			expectedOK: false,
+			name:       "invalid desired count",
+		},
+		{
+			inputMeta:  nil,
+			expectedOK: false,
+			name:       "nil meta",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &TargetStatus{Meta: tc.inputMeta}
+			desired, running, ok := status.DesiredAndRunningCounts()
+			assert.Equal(t, tc.expectedDesired, desired, tc.name)
+			assert.Equal(t, tc.expectedRunning, running, tc.name)
+			assert.Equal(t, tc.expectedOK, ok, tc.name)
+		})
+	}
+}
+
+func TestTargetStatus_QuotaCappedCount(t *testing.T) {
+	count, ok := (&TargetStatus{Meta: map[string]string{TargetStatusMetaKeyQuotaCappedCount: "5"}}).QuotaCappedCount()
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), count)
+
+	_, ok = (&TargetStatus{Meta: map[string]string{TargetStatusMetaKeyQuotaCappedCount: "five"}}).QuotaCappedCount()
+	assert.False(t, ok)
+
+	_, ok = (&TargetStatus{}).QuotaCappedCount()
+	assert.False(t, ok)
+}
+
+func TestTargetConfigSchema_Validate(t *testing.T) {
+	schema := &TargetConfigSchema{
+		Keys: map[string]*TargetConfigKey{
+			"job":     {Type: TargetConfigTypeString, Required: true},
+			"count":   {Type: TargetConfigTypeInt},
+			"factor":  {Type: TargetConfigTypeFloat},
+			"enabled": {Type: TargetConfigTypeBool},
+		},
+	}
+
+	testCases := []struct {
+		inputSchema    *TargetConfigSchema
+		inputConfig    map[string]string
+		expectedErrors []string
+		name           string
+	}{
+		{
+			inputSchema: schema,
+			inputConfig: map[string]string{
+				"job":     "example",
+				"count":   "3",
+				"factor":  "1.5",
+				"enabled": "true",
+			},
+			name: "valid config",
+		},
+		{
+			inputSchema: schema,
+			inputConfig: map[string]string{"job": "example", TargetConfigKeyDryRun: "true"},
+			name:        "dry-run key always accepted",
+		},
+		{
+			inputSchema:    schema,
+			inputConfig:    map[string]string{"count": "3"},
+			expectedErrors: []string{`missing required key "job"`},
+			name:           "missing required key",
+		},
+		{
+			inputSchema:    schema,
+			inputConfig:    map[string]string{"job": "example", "jobb": "example", "a": "b"},
+			expectedErrors: []string{`unknown key "a"`, `unknown key "jobb"`},
+			name:           "unknown keys",
+		},
+		{
+			inputSchema: &TargetConfigSchema{Keys: schema.Keys, AllowUnknown: true},
+			inputConfig: map[string]string{"job": "example", "jobb": "example"},
+			name:        "unknown keys allowed",
+		},
+		{
+			inputSchema: schema,
+			inputConfig: map[string]string{
+				"job":     "example",
+				"count":   "three",
+				"factor":  "high",
+				"enabled": "yes",
+			},
+			expectedErrors: []string{
+				`key "count" must be int, found "three"`,
+				`key "enabled" must be bool, found "yes"`,
+				`key "factor" must be float, found "high"`,
+			},
+			name: "invalid types",
+		},
+		{
+			inputSchema: &TargetConfigSchema{
+				Keys: map[string]*TargetConfigKey{"job": {Type: "list"}},
+			},
+			inputConfig:    map[string]string{"job": "example"},
+			expectedErrors: []string{`key "job" has unsupported type "list"`},
+			name:           "unsupported type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputSchema.Validate(tc.inputConfig)
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err, tc.name)
+				return
+			}
+
+			mErr, ok := err.(*multierror.Error)
+			assert.True(t, ok, tc.name)
+
+			var actualErrors []string
+			for _, e := range mErr.Errors {
+				actualErrors = append(actualErrors, e.Error())
+			}
+			assert.Equal(t, tc.expectedErrors, actualErrors, tc.name)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package sdk
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MetricTransformClamp, MetricTransformScale, MetricTransformOffset and
+	// MetricTransformLog are the transforms which can be applied to the
+	// metrics of a check before they are passed to the strategy.
+	//
+	//   clamp(min, max) limits values to the range [min, max].
+	//   scale(factor)   multiplies values by factor.
+	//   offset(value)   adds value to values.
+	//   log             is the natural logarithm of one plus the value, so
+	//                   zero maps to zero. Negative values map to zero.
+	MetricTransformClamp  = "clamp"
+	MetricTransformScale  = "scale"
+	MetricTransformOffset = "offset"
+	MetricTransformLog    = "log"
+)
+
+// metricTransformArgs is the number of arguments accepted by each transform.
+var metricTransformArgs = map[string]int{
+	MetricTransformClamp:  2,
+	MetricTransformScale:  1,
+	MetricTransformOffset: 1,
+	MetricTransformLog:    0,
+}
+
+// MetricTransform is a function applied to each metric value of a check,
+// parsed from its `name(arg, ...)` form.
+type MetricTransform struct {
+	Name string
+	Args []float64
+}
+
+// ParseMetricTransform parses a transform such as "clamp(0,100)". The
+// parentheses can be omitted for transforms without arguments.
+func ParseMetricTransform(s string) (*MetricTransform, error) {
+	s = strings.TrimSpace(s)
+	name, argsStr := s, ""
+
+	if i := strings.Index(s, "("); i >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return nil, fmt.Errorf("transform %q is missing closing parenthesis", s)
+		}
+		name, argsStr = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:len(s)-1])
+	}
+
+	numArgs, ok := metricTransformArgs[name]
+	if !ok {
+		return nil, fmt.Errorf("transform %q is not supported", name)
+	}
+
+	t := &MetricTransform{Name: name}
+	if argsStr != "" {
+		for _, a := range strings.Split(argsStr, ",") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+			if err != nil {
+				return nil, fmt.Errorf("transform %q argument %q is not a number", name, strings.TrimSpace(a))
+			}
+			t.Args = append(t.Args, f)
+		}
+	}
+
+	if len(t.Args) != numArgs {
+		return nil, fmt.Errorf("transform %q requires %d arguments, found %d", name, numArgs, len(t.Args))
+	}
+	if name == MetricTransformClamp && t.Args[0] > t.Args[1] {
+		return nil, fmt.Errorf("transform %q min can't be greater than max", name)
+	}
+	return t, nil
+}
+
+// ParseMetricTransforms parses each of the passed transforms, and returns an
+// error for the first which is invalid.
+func ParseMetricTransforms(transforms []string) ([]*MetricTransform, error) {
+	parsed := make([]*MetricTransform, 0, len(transforms))
+	for _, s := range transforms {
+		t, err := ParseMetricTransform(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, t)
+	}
+	return parsed, nil
+}
+
+// Apply returns the transformed value.
+func (t *MetricTransform) Apply(v float64) float64 {
+	switch t.Name {
+	case MetricTransformClamp:
+		return math.Min(math.Max(v, t.Args[0]), t.Args[1])
+	case MetricTransformScale:
+		return v * t.Args[0]
+	case MetricTransformOffset:
+		return v + t.Args[0]
+	case MetricTransformLog:
+		return math.Log1p(math.Max(v, 0))
+	}
+	return v
+}
+
+// TransformMetrics returns a copy of the metrics with each value transformed
+// by the transforms in order.
+func TransformMetrics(m TimestampedMetrics, transforms []*MetricTransform) TimestampedMetrics {
+	out := make(TimestampedMetrics, len(m))
+	for i, metric := range m {
+		for _, t := range transforms {
+			metric.Value = t.Apply(metric.Value)
+		}
+		out[i] = metric
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetricTransform(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedOutput *MetricTransform
+		expectedError  bool
+		name           string
+	}{
+		{
+			input:          "clamp(0,100)",
+			expectedOutput: &MetricTransform{Name: MetricTransformClamp, Args: []float64{0, 100}},
+			name:           "clamp",
+		},
+		{
+			input:          " scale( 1.5 ) ",
+			expectedOutput: &MetricTransform{Name: MetricTransformScale, Args: []float64{1.5}},
+			name:           "spaces are ignored",
+		},
+		{
+			input:          "log",
+			expectedOutput: &MetricTransform{Name: MetricTransformLog},
+			name:           "without parentheses",
+		},
+		{
+			input:          "log()",
+			expectedOutput: &MetricTransform{Name: MetricTransformLog},
+			name:           "with empty parentheses",
+		},
+		{
+			input:         "sqrt",
+			expectedError: true,
+			name:          "unknown transform",
+		},
+		{
+			input:         "offset(1",
+			expectedError: true,
+			name:          "missing closing parenthesis",
+		},
+		{
+			input:         "offset(one)",
+			expectedError: true,
+			name:          "argument not a number",
+		},
+		{
+			input:         "clamp(0)",
+			expectedError: true,
+			name:          "missing argument",
+		},
+		{
+			input:         "clamp(100,0)",
+			expectedError: true,
+			name:          "clamp min greater than max",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := ParseMetricTransform(tc.input)
+			assert.Equal(t, tc.expectedError, err != nil, tc.name)
+			assert.Equal(t, tc.expectedOutput, actualOutput, tc.name)
+		})
+	}
+}
+
+func TestTransformMetrics(t *testing.T) {
+	ts := time.Date(2020, time.November, 18, 11, 0, 0, 0, time.UTC)
+
+	transforms, err := ParseMetricTransforms([]string{"clamp(0,100)", "scale(2)", "offset(-10)"})
+	assert.NoError(t, err)
+
+	input := TimestampedMetrics{{Timestamp: ts, Value: -5}, {Timestamp: ts, Value: 50}, {Timestamp: ts, Value: 150}}
+	expected := TimestampedMetrics{{Timestamp: ts, Value: -10}, {Timestamp: ts, Value: 90}, {Timestamp: ts, Value: 190}}
+	assert.Equal(t, expected, TransformMetrics(input, transforms))
+
+	// The input metrics are not modified.
+	assert.Equal(t, float64(-5), input[0].Value)
+
+	log := &MetricTransform{Name: MetricTransformLog}
+	assert.Equal(t, float64(0), log.Apply(0))
+	assert.Equal(t, float64(0), log.Apply(-1))
+	assert.Equal(t, math.Log(2), log.Apply(1))
+}